@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+
+	"AreYouOK/internal/model"
+	"AreYouOK/internal/service"
+	"AreYouOK/pkg/logger"
+	"AreYouOK/storage"
+)
+
+// 后台生成兑换码批次，明文兑换码只输出到 stdout 一次
+// 用法：go run ./cmd/redeem -name "养老院合作" -amount 100 -count 50 -expires-in 90
+func main() {
+	name := flag.String("name", "", "批次名称")
+	note := flag.String("note", "", "备注")
+	createdBy := flag.String("created-by", "", "操作人")
+	channel := flag.String("channel", string(model.QuotaChannelSMS), "额度渠道")
+	amount := flag.Int("amount", 0, "每次兑换发放的额度（cents）")
+	count := flag.Int("count", 1, "生成数量")
+	maxUses := flag.Int("max-uses", 1, "每个码可兑换次数，1 表示一次性")
+	expiresIn := flag.Int("expires-in", 30, "有效期（天）")
+	flag.Parse()
+
+	if *name == "" || *amount <= 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	logger.Init()
+	defer logger.Sync()
+
+	if err := storage.Init(); err != nil {
+		logger.Logger.Fatal("Failed to initialize storage for redeem", zap.Error(err))
+	}
+	defer storage.Close()
+
+	batch, codes, err := service.Redeem().GenerateBatch(context.Background(), service.RedeemBatchSpec{
+		Name:           *name,
+		Note:           *note,
+		CreatedBy:      *createdBy,
+		Channel:        model.QuotaChannel(*channel),
+		Amount:         *amount,
+		Count:          *count,
+		MaxRedemptions: *maxUses,
+		ExpiresAt:      time.Now().AddDate(0, 0, *expiresIn),
+	})
+	if err != nil {
+		logger.Logger.Fatal("Failed to generate redeem codes", zap.Error(err))
+	}
+
+	fmt.Fprintf(os.Stderr, "batch %d: %d codes, amount %d, max uses %d, expires at %s\n",
+		batch.ID, len(codes), batch.Amount, batch.MaxRedemptions, batch.ExpiresAt.Format(time.RFC3339))
+	for _, code := range codes {
+		fmt.Println(code)
+	}
+}
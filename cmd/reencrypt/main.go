@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+
+	"AreYouOK/internal/service"
+	"AreYouOK/pkg/logger"
+	"AreYouOK/storage"
+)
+
+// 密钥轮换后将手机号密文重加密为当前密钥（ENCRYPTION_KEY_ID），可随时中断，再次执行从上次进度继续
+// 轮换步骤：旧密钥移入 ENCRYPTION_OLD_KEYS，配置新的 ENCRYPTION_KEY 与 ENCRYPTION_KEY_ID 并发布，
+// 然后执行本命令；输出 conflicts=0 且 failed=0 后即可从 ENCRYPTION_OLD_KEYS 中移除旧密钥
+// 用法：go run ./cmd/reencrypt -batch 200 -pause 200ms
+func main() {
+	batch := flag.Int("batch", 200, "每批处理的用户数")
+	pause := flag.Duration("pause", 200*time.Millisecond, "批次之间的间隔")
+	restart := flag.Bool("restart", false, "忽略已保存的进度，从头开始")
+	dryRun := flag.Bool("dry-run", false, "只统计需要重加密的数量，不写入")
+	flag.Parse()
+
+	logger.Init()
+	defer logger.Sync()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	if err := storage.Init(); err != nil {
+		logger.Logger.Fatal("Failed to initialize storage for reencrypt", zap.Error(err))
+	}
+	defer storage.Close()
+
+	result, err := service.Reencrypt().Run(ctx, service.ReencryptOptions{
+		BatchSize: *batch,
+		Pause:     *pause,
+		Restart:   *restart,
+		DryRun:    *dryRun,
+	})
+	if result != nil {
+		fmt.Fprintf(os.Stderr, "scanned=%d updated=%d ciphers=%d conflicts=%d failed=%d last_user_id=%d\n",
+			result.Scanned, result.Updated, result.Ciphers, result.Conflicts, result.Failed, result.LastID)
+	}
+	if err != nil {
+		logger.Logger.Error("Reencrypt job stopped", zap.Error(err))
+		os.Exit(1)
+	}
+	if result.Conflicts > 0 {
+		fmt.Fprintln(os.Stderr, "some users were modified during the run, re-run with -restart to cover them")
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+
+	"AreYouOK/internal/service"
+	"AreYouOK/pkg/logger"
+	"AreYouOK/storage"
+)
+
+// 手机号哈希版本轮换后按当前版本（PHONEHASH_VERSION）重算哈希，可随时中断，再次执行从上次进度继续
+// 轮换步骤：在 PHONEHASH_SECRETS 中加入新版本密钥，PHONEHASH_VERSION 改为新版本，
+// PHONEHASH_PREVIOUS_VERSION 设为旧版本并发布（迁移窗口内登录与查重同时匹配两个版本），
+// 然后执行本命令；输出 conflicts=0 且 failed=0 后即可将 PHONEHASH_PREVIOUS_VERSION 设回 -1 并移除旧密钥
+// 用法：go run ./cmd/rehash -batch 200 -pause 200ms
+func main() {
+	batch := flag.Int("batch", 200, "每批处理的用户数")
+	pause := flag.Duration("pause", 200*time.Millisecond, "批次之间的间隔")
+	restart := flag.Bool("restart", false, "忽略已保存的进度，从头开始")
+	dryRun := flag.Bool("dry-run", false, "只统计需要重算的数量，不写入")
+	flag.Parse()
+
+	logger.Init()
+	defer logger.Sync()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	if err := storage.Init(); err != nil {
+		logger.Logger.Fatal("Failed to initialize storage for rehash", zap.Error(err))
+	}
+	defer storage.Close()
+
+	result, err := service.Rehash().Run(ctx, service.RehashOptions{
+		BatchSize: *batch,
+		Pause:     *pause,
+		Restart:   *restart,
+		DryRun:    *dryRun,
+	})
+	if result != nil {
+		fmt.Fprintf(os.Stderr, "scanned=%d updated=%d hashes=%d conflicts=%d failed=%d last_user_id=%d\n",
+			result.Scanned, result.Updated, result.Hashes, result.Conflicts, result.Failed, result.LastID)
+	}
+	if err != nil {
+		logger.Logger.Error("Rehash job stopped", zap.Error(err))
+		os.Exit(1)
+	}
+	if result.Conflicts > 0 {
+		fmt.Fprintln(os.Stderr, "some users were modified during the run, re-run with -restart to cover them")
+	}
+}
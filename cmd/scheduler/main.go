@@ -56,6 +56,11 @@ func main() {
 	go runDailyCheckinLoop(ctx)
 	go runJourneyTimeoutLoop(ctx)
 	go runOverdueJourneyLoop(ctx)
+	go runSubscriptionGrantLoop(ctx)
+	go runAccountPurgeLoop(ctx)
+	go runContactConsentExpiryLoop(ctx)
+	go runJourneyLocationPurgeLoop(ctx)
+	go runJourneyRecurrenceLoop(ctx)
 
 
 	<-ctx.Done()
@@ -141,6 +146,9 @@ func runJourneyTimeoutLoop(ctx context.Context) {
 			if err := js.CheckJourneyTimeouts(runCtx, 10*time.Minute); err != nil {
 				logger.Logger.Error("Journey timeout check run failed", zap.Error(err))
 			}
+			if err := js.CheckCheckpointDeadlines(runCtx, 10*time.Minute); err != nil {
+				logger.Logger.Error("Journey checkpoint check run failed", zap.Error(err))
+			}
 			cancel()
 		}
 	}
@@ -173,3 +181,143 @@ func runOverdueJourneyLoop(ctx context.Context) {
 		}
 	}
 }
+
+// runSubscriptionGrantLoop 周期性为到达计费周年日的订阅发放额度
+// 当前实现：每 1 小时扫描一次
+func runSubscriptionGrantLoop(ctx context.Context) {
+	ss := schedule.GetSubscriptionScheduler()
+
+	interval := 1 * time.Hour
+	if config.Cfg.Environment == "development" {
+		interval = 1 * time.Minute
+		logger.Logger.Info("Subscription grant loop running in development mode with 1m interval")
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			runCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+			if err := ss.GrantDueSubscriptionQuotas(runCtx); err != nil {
+				logger.Logger.Error("Subscription grant run failed", zap.Error(err))
+			}
+			cancel()
+		}
+	}
+}
+
+// runAccountPurgeLoop 周期性清除恢复窗口已结束的注销账号
+// 当前实现：每 1 小时扫描一次
+func runAccountPurgeLoop(ctx context.Context) {
+	es := schedule.GetErasureScheduler()
+
+	interval := 1 * time.Hour
+	if config.Cfg.Environment == "development" {
+		interval = 1 * time.Minute
+		logger.Logger.Info("Account purge loop running in development mode with 1m interval")
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			runCtx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+			if err := es.PurgeDueAccounts(runCtx); err != nil {
+				logger.Logger.Error("Account purge run failed", zap.Error(err))
+			}
+			cancel()
+		}
+	}
+}
+
+// runContactConsentExpiryLoop 周期性标记确认链接已过期的紧急联系人
+// 当前实现：每 1 小时扫描一次
+func runContactConsentExpiryLoop(ctx context.Context) {
+	cs := schedule.GetContactScheduler()
+
+	interval := 1 * time.Hour
+	if config.Cfg.Environment == "development" {
+		interval = 1 * time.Minute
+		logger.Logger.Info("Contact consent expiry loop running in development mode with 1m interval")
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			runCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+			if err := cs.ExpireContactConsents(runCtx); err != nil {
+				logger.Logger.Error("Contact consent expiry run failed", zap.Error(err))
+			}
+			cancel()
+		}
+	}
+}
+
+// runJourneyLocationPurgeLoop 周期性删除结束超过保留天数的行程位置点
+// 当前实现：每 1 小时扫描一次
+func runJourneyLocationPurgeLoop(ctx context.Context) {
+	js := schedule.GetJourneyScheduler()
+
+	interval := 1 * time.Hour
+	if config.Cfg.Environment == "development" {
+		interval = 1 * time.Minute
+		logger.Logger.Info("Journey location purge loop running in development mode with 1m interval")
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			runCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+			if err := js.PurgeJourneyLocations(runCtx); err != nil {
+				logger.Logger.Error("Journey location purge run failed", zap.Error(err))
+			}
+			cancel()
+		}
+	}
+}
+
+// runJourneyRecurrenceLoop 周期性处理重复行程：生成安排、出发前提醒、到点开始或跳过
+// 当前实现：每 5 分钟扫描一次
+func runJourneyRecurrenceLoop(ctx context.Context) {
+	js := schedule.GetJourneyScheduler()
+
+	interval := 5 * time.Minute
+	if config.Cfg.Environment == "development" {
+		interval = 1 * time.Minute
+		logger.Logger.Info("Journey recurrence loop running in development mode with 1m interval")
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			runCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+			if err := js.RunJourneyRecurrence(runCtx); err != nil {
+				logger.Logger.Error("Journey recurrence run failed", zap.Error(err))
+			}
+			cancel()
+		}
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"AreYouOK/pkg/sms"
 	"AreYouOK/pkg/snowflake"
 	"AreYouOK/pkg/token"
+	"AreYouOK/pkg/wechat"
 	"AreYouOK/storage"
 )
 
@@ -92,6 +93,11 @@ func main() {
 		logger.Logger.Info("SMS service will be disabled, SMS features may not work")
 	}
 
+	if err := wechat.Init(); err != nil {
+		logger.Logger.Warn("Failed to initialize WeChat client", zap.Error(err))
+		logger.Logger.Info("WeChat mini-program login will be disabled")
+	}
+
 	if err := slider.Init(); err != nil {
 		logger.Logger.Warn("Failed to initialize slider service", zap.Error(err))
 		logger.Logger.Info("Slider service will be disabled, slider verification may not work")
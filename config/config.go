@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"log"
+	"strconv"
+	"strings"
 
 	"github.com/caarlos0/env/v6"
 	"github.com/joho/godotenv"
@@ -44,20 +46,29 @@ type Config struct {
 	RabbitMQPassword string `env:"RABBITMQ_PASSWORD" envDefault:"guest"`
 	RabbitMQVhost    string `env:"RABBITMQ_VHOST" envDefault:"/"`
 
-	JWTSecret    string `env:"JWT_SECRET"`
-	LoggerFormat string `env:"LOGGER_FORMAT" envDefault:"text"`
-	LoggerLevel  string `env:"LOGGER_LEVEL" envDefault:"INFO"`
+	JWTSecret     string `env:"JWT_SECRET"`
+	AdminAPIToken string `env:"ADMIN_API_TOKEN"` // 运维查询接口的 Bearer token，为空时关闭 /v1/admin
+	LoggerFormat  string `env:"LOGGER_FORMAT" envDefault:"text"`
+	LoggerLevel   string `env:"LOGGER_LEVEL" envDefault:"INFO"`
 	//AlipayAppID             string `env:"ALIPAY_APP_ID"`
 	ServerHost              string `env:"SERVER_HOST" envDefault:"0.0.0.0"`
 	PhoneHashSalt           string `env:"PHONEHASH_SALT"`
+	TokenHashSecret         string `env:"TOKEN_HASH_SECRET"` // 兑换码等不透明 token 的 HMAC 密钥，未配置时使用 JWT_SECRET
 	ServerPort              string `env:"SERVER_PORT" envDefault:"8888"`
 	AlipayGateway           string `env:"ALIPAY_GATEWAY" envDefault:"https://openapi.alipay.com/gateway.do"`
 	AliCloudAccessKeyID     string `env:"ALIBABA_CLOUD_ACCESS_KEY_ID"`
 	AliCloudAccessKeySecret string `env:"ALIBABA_CLOUD_ACCESS_KEY_SECRET"`
 
+	ExportSigningKey string `env:"EXPORT_SIGNING_KEY"` // 数据导出下载链接签名密钥，未配置时使用 JWT_SECRET
+
 	AliPayAESKey    string `env:"ALIPAY_AES_KEY"`
 	AlipayAppSecret string `env:"ALIPAY_APP_SECRET"`
 
+	// 微信小程序登录配置，WECHAT_PROVIDER 为 mock 时不请求微信接口，仅用于本地开发
+	WechatProvider  string `env:"WECHAT_PROVIDER" envDefault:"wechat"`
+	WechatAppID     string `env:"WECHAT_APP_ID"`
+	WechatAppSecret string `env:"WECHAT_APP_SECRET"`
+
 	SMSProvider string `env:"SMS_PROVIDER" envDefault:"aliyun"`
 	// 短信验证码配置
 	SMSSignName     string `env:"SMS_SIGN_NAME"`
@@ -74,13 +85,45 @@ type Config struct {
 	// 行程超时提醒配置
 	SMSJourneyTimeoutSignName string `env:"SMS_JOURNEY_TIMEOUT_SIGN_NAME"`
 	SMSJourneyTimeoutTemplate string `env:"SMS_JOURNEY_TIMEOUT_TEMPLATE"`
+	// 重复行程出发前确认提醒配置，模板变量 trip（行程标题）与 time（出发时间）
+	SMSJourneyPrestartSignName string `env:"SMS_JOURNEY_PRESTART_SIGN_NAME"`
+	SMSJourneyPrestartTemplate string `env:"SMS_JOURNEY_PRESTART_TEMPLATE"`
+	// 行程告警无法送达联系人时通知用户本人，模板变量 trip（行程标题）
+	SMSJourneyAlertUndeliveredSignName string `env:"SMS_JOURNEY_ALERT_UNDELIVERED_SIGN_NAME"`
+	SMSJourneyAlertUndeliveredTemplate string `env:"SMS_JOURNEY_ALERT_UNDELIVERED_TEMPLATE"`
 	// 打卡超时提醒配置
 	SMSCheckInTimeoutSignName string `env:"SMS_CHECKIN_TIMEOUT_SIGN_NAME"`
 	SMSCheckInTimeoutTemplate string `env:"SMS_CHECKIN_TIMEOUT_TEMPLATE"`
 	// 额度耗尽提醒配置
 	SMSQuotaDepletedSignName string `env:"SMS_QUOTA_DEPLETED_SIGN_NAME"`
 	SMSQuotaDepletedTemplate string `env:"SMS_QUOTA_DEPLETED_TEMPLATE"`
-	EncryptionKey            string `env:"ENCRYPTION_KEY"`
+	// 额度不足预警配置
+	SMSQuotaLowSignName string `env:"SMS_QUOTA_LOW_SIGN_NAME"`
+	SMSQuotaLowTemplate string `env:"SMS_QUOTA_LOW_TEMPLATE"`
+	// 更换手机号后通知旧号码配置
+	SMSPhoneChangedSignName string `env:"SMS_PHONE_CHANGED_SIGN_NAME"`
+	SMSPhoneChangedTemplate string `env:"SMS_PHONE_CHANGED_TEMPLATE"`
+	// 邀请紧急联系人确认的短信配置，模板变量 name（用户昵称）与 link（确认链接）
+	SMSContactConsentSignName string `env:"SMS_CONTACT_CONSENT_SIGN_NAME"`
+	SMSContactConsentTemplate string `env:"SMS_CONTACT_CONSENT_TEMPLATE"`
+	ContactConsentURL         string `env:"CONTACT_CONSENT_URL"` // 联系人确认页地址，短信链接为 {CONTACT_CONSENT_URL}?token=xxx
+	// 联系人退订后提醒用户更换联系人的短信配置，模板变量 name（联系人称呼）
+	SMSContactOptedOutSignName string `env:"SMS_CONTACT_OPTED_OUT_SIGN_NAME"`
+	SMSContactOptedOutTemplate string `env:"SMS_CONTACT_OPTED_OUT_TEMPLATE"`
+	ContactOptOutURL           string `env:"CONTACT_OPT_OUT_URL"` // 退订页地址，告警短信中的链接为 {CONTACT_OPT_OUT_URL}?c=联系人ID&sig=签名
+	SMSInboundToken            string `env:"SMS_INBOUND_TOKEN"`   // 短信上行回调地址中的 token，为空时关闭 /v1/webhooks/sms/inbound
+	EncryptionKey              string `env:"ENCRYPTION_KEY"`
+	// 密钥轮换：ENCRYPTION_KEY 为当前加密使用的密钥，ENCRYPTION_KEY_ID 写入密文头；
+	// 旧密钥以 "id:key,id:key" 形式放在 ENCRYPTION_OLD_KEYS 中，仅用于解密
+	EncryptionKeyID   string `env:"ENCRYPTION_KEY_ID" envDefault:"k1"`
+	EncryptionOldKeys string `env:"ENCRYPTION_OLD_KEYS"`
+
+	// 手机号哈希版本：0 为旧的 sha256(PHONEHASH_SALT:phone)，其余版本为 HMAC-SHA256，
+	// 密钥以 "version:secret,version:secret" 形式放在 PHONEHASH_SECRETS 中；
+	// 迁移窗口内查询同时匹配 PHONEHASH_PREVIOUS_VERSION，-1 表示不启用
+	PhoneHashVersion  int    `env:"PHONEHASH_VERSION" envDefault:"0"`
+	PhoneHashSecrets  string `env:"PHONEHASH_SECRETS"`
+	PhoneHashPrevious int    `env:"PHONEHASH_PREVIOUS_VERSION" envDefault:"-1"`
 
 	CaptchaExpireSeconds   int   `env:"CAPTCHA_EXPIRE_SECONDS" envDefault:"120"`
 	CaptchaSliderThreshold int   `env:"CAPTCHA_SLIDER_THRESHOLD" envDefault:"2"`
@@ -94,8 +137,31 @@ type Config struct {
 	CaptchaMaxDaily        int   `env:"CAPTCHA_MAX_DAILY" envDefault:"10"`
 	RateLimitRPS           int   `env:"RATE_LIMIT_RPS" envDefault:"100"`
 	PostgreSQLMaxIdle      int   `env:"POSTGRESQL_MAX_IDLE" envDefault:"30"`
-	DefaultSMSQuota        int   `env:"DEFAULT_SMS_QUOTA" envDefault:"100"` // 默认 SMS 额度（cents），100 cents = 20 次短信（每次 5 cents）
+	DefaultSMSQuota        int   `env:"DEFAULT_SMS_QUOTA" envDefault:"100"`       // 默认 SMS 额度（cents），100 cents = 20 次短信（每次 5 cents）
+	QuotaLowBalanceFanouts int   `env:"QUOTA_LOW_BALANCE_FANOUTS" envDefault:"2"` // 额度预警阈值：余额不足以支撑 N 次完整的紧急联系人通知时预警
 	RateLimitEnabled       bool  `env:"RATE_LIMIT_ENABLED" envDefault:"true"`
+	AccountRestoreDays     int   `env:"ACCOUNT_RESTORE_DAYS" envDefault:"14"`             // 注销后可恢复的天数，过期后清除个人数据
+	DataExportRetainHours  int   `env:"DATA_EXPORT_RETAIN_HOURS" envDefault:"24"`         // 导出归档保留时长，超时未下载自动删除
+	DataExportURLMinutes   int   `env:"DATA_EXPORT_URL_MINUTES" envDefault:"10"`          // 下载链接有效期
+	PhoneChangeCooldown    int   `env:"PHONE_CHANGE_COOLDOWN_DAYS" envDefault:"30"`       // 两次更换手机号的最小间隔（天）
+	StepUpMinutes          int   `env:"STEP_UP_MINUTES" envDefault:"10"`                  // 二次验证（验证本人手机号）后查看敏感信息的有效期
+	ContactConsentHours    int   `env:"CONTACT_CONSENT_HOURS" envDefault:"72"`            // 联系人确认链接有效期
+	ContactConsentMaxSends int   `env:"CONTACT_CONSENT_MAX_SENDS" envDefault:"3"`         // 同一联系人号码最多发送确认短信的次数
+	ContactConsentCooldown int   `env:"CONTACT_CONSENT_COOLDOWN_MINUTES" envDefault:"10"` // 两次发送确认短信的最小间隔（分钟）
+	ContactSessionMinutes  int   `env:"CONTACT_SESSION_MINUTES" envDefault:"30"`          // 紧急联系人会话 token 有效期（分钟），过期后重新验证手机号
+	JourneyMaxExtensions   int   `env:"JOURNEY_MAX_EXTENSIONS" envDefault:"3"`            // 单个行程最多延长次数
+	JourneyMaxExtendMins   int   `env:"JOURNEY_MAX_EXTEND_MINUTES" envDefault:"240"`      // 单个行程累计最多延长的分钟数
+	JourneyLocationMax     int   `env:"JOURNEY_LOCATION_MAX_POINTS" envDefault:"500"`     // 单个行程保留的位置点上限，超出时删除最早的点
+	JourneyLocationDays    int   `env:"JOURNEY_LOCATION_RETAIN_DAYS" envDefault:"7"`      // 行程结束后位置点保留天数，过期自动清除
+	JourneyShareMaxHours   int   `env:"JOURNEY_SHARE_MAX_HOURS" envDefault:"72"`          // 行程分享链接最长有效期（小时）
+	JourneyRecurLeadHours  int   `env:"JOURNEY_RECURRENCE_LEAD_HOURS" envDefault:"24"`    // 重复行程提前生成安排的时长（小时）
+	JourneyPrestartMinutes int   `env:"JOURNEY_PRESTART_REMIND_MINUTES" envDefault:"30"`  // 出发前多久提醒用户确认
+	JourneyConfirmGrace    int   `env:"JOURNEY_CONFIRM_GRACE_MINUTES" envDefault:"30"`    // 出发后多久内仍可确认，超过后记为未确认跳过
+
+	// 行程最后位置的地图链接，{lat} / {lon} 替换为坐标（小程序上报 gcj02 坐标）
+	JourneyMapLinkURL string `env:"JOURNEY_MAP_LINK_URL" envDefault:"https://uri.amap.com/marker?position={lon},{lat}"`
+	// 行程分享页地址，分享链接为 {JOURNEY_SHARE_URL}?token=xxx，未配置时只返回 token
+	JourneyShareURL string `env:"JOURNEY_SHARE_URL"`
 
 	OTELEXPORTERENDPOINT string `env:"OTEL_EXPORTER_OTLP_ENDPOINT" envDefault:"localhost:4317"`
 }
@@ -126,6 +192,18 @@ func validateConfig() {
 		log.Fatal("ENCRYPTION_KEY must be exactly 32 bytes for AES-256")
 	}
 
+	if _, err := Cfg.EncryptionKeys(); err != nil {
+		log.Fatalf("Invalid encryption keyring: %v", err)
+	}
+
+	if _, err := Cfg.PhoneHashKeys(); err != nil {
+		log.Fatalf("Invalid phone hash keys: %v", err)
+	}
+
+	if Cfg.TokenHashSecret == "" {
+		log.Printf("WARN: TOKEN_HASH_SECRET is not set, falling back to JWT_SECRET for token hashes")
+	}
+
 	// if Cfg.AlipayAppID == "" {
 	// 	log.Printf("WARN: ALIPAY_APP_ID is not set, Alipay integration will not work")
 	// }
@@ -230,6 +308,14 @@ func (c *Config) GetSMSTemplateConfig(messageType string) (signName, templateCod
 		signName = c.SMSJourneyTimeoutSignName
 		templateCode = c.SMSJourneyTimeoutTemplate
 
+	case "journey_prestart":
+		signName = c.SMSJourneyPrestartSignName
+		templateCode = c.SMSJourneyPrestartTemplate
+
+	case "journey_alert_undelivered":
+		signName = c.SMSJourneyAlertUndeliveredSignName
+		templateCode = c.SMSJourneyAlertUndeliveredTemplate
+
 	case "checkin_timeout":
 		signName = c.SMSCheckInTimeoutSignName
 		templateCode = c.SMSCheckInTimeoutTemplate
@@ -237,6 +323,22 @@ func (c *Config) GetSMSTemplateConfig(messageType string) (signName, templateCod
 	case "quota_depleted":
 		signName = c.SMSQuotaDepletedSignName
 		templateCode = c.SMSQuotaDepletedTemplate
+
+	case "quota_low":
+		signName = c.SMSQuotaLowSignName
+		templateCode = c.SMSQuotaLowTemplate
+
+	case "phone_changed":
+		signName = c.SMSPhoneChangedSignName
+		templateCode = c.SMSPhoneChangedTemplate
+
+	case "contact_consent":
+		signName = c.SMSContactConsentSignName
+		templateCode = c.SMSContactConsentTemplate
+
+	case "contact_opted_out":
+		signName = c.SMSContactOptedOutSignName
+		templateCode = c.SMSContactOptedOutTemplate
 	default:
 
 		signName = c.SMSSignName
@@ -253,3 +355,73 @@ func (c *Config) GetSMSTemplateConfig(messageType string) (signName, templateCod
 
 	return signName, templateCode, nil
 }
+
+// EncryptionKeys 返回密钥环（key ID -> 密钥），包含当前密钥与 ENCRYPTION_OLD_KEYS 中的旧密钥
+func (c *Config) EncryptionKeys() (map[string]string, error) {
+	if c.EncryptionKeyID == "" || len(c.EncryptionKeyID) > 32 || strings.ContainsAny(c.EncryptionKeyID, ":,") {
+		return nil, fmt.Errorf("ENCRYPTION_KEY_ID must be 1-32 characters without ':' or ','")
+	}
+
+	keys := map[string]string{c.EncryptionKeyID: c.EncryptionKey}
+
+	for _, entry := range strings.Split(c.EncryptionOldKeys, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		id, key, ok := strings.Cut(entry, ":")
+		if !ok || id == "" || len(id) > 32 {
+			return nil, fmt.Errorf("invalid ENCRYPTION_OLD_KEYS entry, expected id:key")
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("old encryption key %s must be exactly 32 bytes", id)
+		}
+		if _, exists := keys[id]; exists {
+			return nil, fmt.Errorf("duplicate encryption key id %s", id)
+		}
+		keys[id] = key
+	}
+
+	return keys, nil
+}
+
+// PhoneHashKeys 返回手机号哈希密钥（版本 -> 密钥），版本 0 对应 PHONEHASH_SALT
+// 当前版本与上一版本都必须有对应密钥
+func (c *Config) PhoneHashKeys() (map[int]string, error) {
+	keys := map[int]string{0: c.PhoneHashSalt}
+
+	for _, entry := range strings.Split(c.PhoneHashSecrets, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		versionStr, secret, ok := strings.Cut(entry, ":")
+		version, err := strconv.Atoi(versionStr)
+		if !ok || err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid PHONEHASH_SECRETS entry, expected version:secret with version > 0")
+		}
+		if len(secret) < 16 {
+			return nil, fmt.Errorf("phone hash secret %d must be at least 16 bytes", version)
+		}
+		if _, exists := keys[version]; exists {
+			return nil, fmt.Errorf("duplicate phone hash version %d", version)
+		}
+		keys[version] = secret
+	}
+
+	if _, ok := keys[c.PhoneHashVersion]; !ok {
+		return nil, fmt.Errorf("PHONEHASH_VERSION %d has no secret in PHONEHASH_SECRETS", c.PhoneHashVersion)
+	}
+	if c.PhoneHashPrevious >= 0 {
+		if c.PhoneHashPrevious == c.PhoneHashVersion {
+			return nil, fmt.Errorf("PHONEHASH_PREVIOUS_VERSION must differ from PHONEHASH_VERSION")
+		}
+		if _, ok := keys[c.PhoneHashPrevious]; !ok {
+			return nil, fmt.Errorf("PHONEHASH_PREVIOUS_VERSION %d has no secret", c.PhoneHashPrevious)
+		}
+	}
+
+	return keys, nil
+}
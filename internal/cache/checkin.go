@@ -20,9 +20,6 @@ const (
 
 	scheduledTTL = 24 * time.Hour
 	processedTTL = 48 * time.Hour
-
-	// MonthlyReminderLimit 每月每用户提醒消息上限
-	MonthlyReminderLimit = 5
 )
 
 
@@ -166,11 +163,12 @@ func IncrementMonthlyReminderCount(ctx context.Context, userID int64, monthKey s
 }
 
 // CheckMonthlyReminderLimit 检查用户是否超过月度提醒限制
-func CheckMonthlyReminderLimit(ctx context.Context, userID int64) (bool, int, error) {
+// limit 由用户套餐决定
+func CheckMonthlyReminderLimit(ctx context.Context, userID int64, limit int) (bool, int, error) {
 	monthKey := time.Now().Format("2006-01")
 	count, err := GetMonthlyReminderCount(ctx, userID, monthKey)
 	if err != nil {
 		return true, 0, err // 出错时降级，允许发送
 	}
-	return count < MonthlyReminderLimit, count, nil
+	return count < limit, count, nil
 }
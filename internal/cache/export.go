@@ -0,0 +1,33 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"AreYouOK/storage/redis"
+)
+
+const (
+	exportPrefix = "export"
+)
+
+// SetExportArchive 保存个人数据导出归档，到期自动删除
+// Key: ayok:export:archive:{export_code}
+func SetExportArchive(ctx context.Context, exportCode int64, archive []byte, ttl time.Duration) error {
+	key := redis.Key(exportPrefix, "archive", fmt.Sprintf("%d", exportCode))
+	return redis.Client().Set(ctx, key, archive, ttl).Err()
+}
+
+// TakeExportArchive 读取并删除导出归档，保证只能下载一次
+// 归档不存在（已下载或已过期）时返回 redis.Nil
+func TakeExportArchive(ctx context.Context, exportCode int64) ([]byte, error) {
+	key := redis.Key(exportPrefix, "archive", fmt.Sprintf("%d", exportCode))
+	return redis.Client().GetDel(ctx, key).Bytes()
+}
+
+// DeleteExportArchive 删除导出归档
+func DeleteExportArchive(ctx context.Context, exportCode int64) error {
+	key := redis.Key(exportPrefix, "archive", fmt.Sprintf("%d", exportCode))
+	return redis.Client().Del(ctx, key).Err()
+}
@@ -61,3 +61,41 @@ func UnmarkJourneyScheduled(ctx context.Context, journeyID int64) error {
 	}
 	return nil
 }
+
+const (
+	// 检查点防止重发，与行程的标记分开，key 使用检查点 ID
+	journeyCheckpointReminderScheduledPrefix = "journey:checkpoint:reminder:scheduled"
+	journeyCheckpointTimeoutScheduledPrefix  = "journey:checkpoint:timeout:scheduled"
+)
+
+// IsJourneyCheckpointReminderScheduled 检查检查点提醒消息是否已投放
+func IsJourneyCheckpointReminderScheduled(ctx context.Context, checkpointID int64) (bool, error) {
+	key := redis.Key(journeyCheckpointReminderScheduledPrefix, fmt.Sprintf("%d", checkpointID))
+	result, err := redis.Client().Exists(ctx, key).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check checkpoint reminder scheduled status: %w", err)
+	}
+	return result > 0, nil
+}
+
+// MarkJourneyCheckpointReminderScheduled 标记检查点提醒消息已投放
+func MarkJourneyCheckpointReminderScheduled(ctx context.Context, checkpointID int64) error {
+	key := redis.Key(journeyCheckpointReminderScheduledPrefix, fmt.Sprintf("%d", checkpointID))
+	return redis.Client().Set(ctx, key, "1", journeyScheduledTTL).Err()
+}
+
+// IsJourneyCheckpointTimeoutScheduled 检查检查点超时消息是否已投放
+func IsJourneyCheckpointTimeoutScheduled(ctx context.Context, checkpointID int64) (bool, error) {
+	key := redis.Key(journeyCheckpointTimeoutScheduledPrefix, fmt.Sprintf("%d", checkpointID))
+	result, err := redis.Client().Exists(ctx, key).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check checkpoint timeout scheduled status: %w", err)
+	}
+	return result > 0, nil
+}
+
+// MarkJourneyCheckpointTimeoutScheduled 标记检查点超时消息已投放
+func MarkJourneyCheckpointTimeoutScheduled(ctx context.Context, checkpointID int64) error {
+	key := redis.Key(journeyCheckpointTimeoutScheduledPrefix, fmt.Sprintf("%d", checkpointID))
+	return redis.Client().Set(ctx, key, "1", journeyScheduledTTL).Err()
+}
@@ -0,0 +1,37 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	ri "github.com/redis/go-redis/v9"
+
+	"AreYouOK/storage/redis"
+)
+
+// GetReencryptCursor 获取重加密任务进度（已处理到的最大 users.id）
+// Key: ayok:reencrypt:cursor:{key_id}，按目标密钥区分，新一轮轮换自动从头开始
+func GetReencryptCursor(ctx context.Context, keyID string) (int64, error) {
+	key := redis.Key("reencrypt", "cursor", keyID)
+	value, err := redis.Client().Get(ctx, key).Result()
+	if err != nil {
+		if errors.Is(err, ri.Nil) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// SetReencryptCursor 保存重加密任务进度，不设置过期时间
+func SetReencryptCursor(ctx context.Context, keyID string, lastUserID int64) error {
+	key := redis.Key("reencrypt", "cursor", keyID)
+	return redis.Client().Set(ctx, key, lastUserID, 0).Err()
+}
+
+// DeleteReencryptCursor 清除重加密任务进度
+func DeleteReencryptCursor(ctx context.Context, keyID string) error {
+	key := redis.Key("reencrypt", "cursor", keyID)
+	return redis.Client().Del(ctx, key).Err()
+}
@@ -0,0 +1,37 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	ri "github.com/redis/go-redis/v9"
+
+	"AreYouOK/storage/redis"
+)
+
+// GetRehashCursor 获取手机号重新哈希任务进度（已处理到的最大 users.id）
+// Key: ayok:rehash:cursor:{version}，按目标哈希版本区分，新一轮轮换自动从头开始
+func GetRehashCursor(ctx context.Context, version int) (int64, error) {
+	key := redis.Key("rehash", "cursor", strconv.Itoa(version))
+	value, err := redis.Client().Get(ctx, key).Result()
+	if err != nil {
+		if errors.Is(err, ri.Nil) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// SetRehashCursor 保存手机号重新哈希任务进度，不设置过期时间
+func SetRehashCursor(ctx context.Context, version int, lastUserID int64) error {
+	key := redis.Key("rehash", "cursor", strconv.Itoa(version))
+	return redis.Client().Set(ctx, key, lastUserID, 0).Err()
+}
+
+// DeleteRehashCursor 清除手机号重新哈希任务进度
+func DeleteRehashCursor(ctx context.Context, version int) error {
+	key := redis.Key("rehash", "cursor", strconv.Itoa(version))
+	return redis.Client().Del(ctx, key).Err()
+}
@@ -0,0 +1,29 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"AreYouOK/storage/redis"
+)
+
+const (
+	stepUpPrefix = "stepup"
+)
+
+// SetStepUp 标记会话已完成二次验证，到期后需要重新验证
+// Key: ayok:stepup:{session_id}
+func SetStepUp(ctx context.Context, sessionID string, ttl time.Duration) error {
+	key := redis.Key(stepUpPrefix, sessionID)
+	return redis.Client().Set(ctx, key, "1", ttl).Err()
+}
+
+// HasStepUp 会话是否在有效期内完成了二次验证
+func HasStepUp(ctx context.Context, sessionID string) (bool, error) {
+	key := redis.Key(stepUpPrefix, sessionID)
+	n, err := redis.Client().Exists(ctx, key).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
@@ -12,7 +12,7 @@ const (
 	tokenPrefix = "token"
 )
 
-// SetRefreshToken 存储 refresh token 到 Redis
+// SetRefreshToken 存储 refresh token 到 Redis（旧版单设备登录，现仅用于迁移未携带 sid 的 refresh token）
 // Key: ayok:token:refresh:{user_id}
 // TTL: 7天
 func SetRefreshToken(ctx context.Context, userID, refreshToken string) error {
@@ -42,3 +42,43 @@ func ValidateRefreshTokenExists(ctx context.Context, userID, refreshToken string
 	}
 	return storedToken == refreshToken
 }
+
+// RevokeAccessToken 将 access token 的 jti 加入黑名单，直到 token 自然过期
+// Key: ayok:token:blacklist:{jti}
+func RevokeAccessToken(ctx context.Context, jti string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	key := redis.Key(tokenPrefix, "blacklist", jti)
+	return redis.Client().Set(ctx, key, "1", ttl).Err()
+}
+
+// RevokeSession 标记会话已失效，使该会话已签发但未过期的 access token 一并失效
+// Key: ayok:token:session_revoked:{session_id}
+// TTL: access token 有效期
+func RevokeSession(ctx context.Context, sessionID string) error {
+	key := redis.Key(tokenPrefix, "session_revoked", sessionID)
+	ttl := time.Duration(config.Cfg.JWTExpireMinutes) * time.Minute
+
+	return redis.Client().Set(ctx, key, "1", ttl).Err()
+}
+
+// IsAccessTokenRevoked 检查 access token 的 jti 或所属会话是否已被拉黑
+func IsAccessTokenRevoked(ctx context.Context, jti, sessionID string) (bool, error) {
+	keys := make([]string, 0, 2)
+	if jti != "" {
+		keys = append(keys, redis.Key(tokenPrefix, "blacklist", jti))
+	}
+	if sessionID != "" {
+		keys = append(keys, redis.Key(tokenPrefix, "session_revoked", sessionID))
+	}
+	if len(keys) == 0 {
+		return false, nil
+	}
+
+	count, err := redis.Client().Exists(ctx, keys...).Result()
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
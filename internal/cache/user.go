@@ -46,6 +46,11 @@ func SetUserSettings(ctx context.Context, userID int64, settings *UserSettingsCa
 	return cache.Set(ctx, key, settings)
 }
 
+// DeleteUserSettings 删除用户设置缓存（账号清除时调用）
+func DeleteUserSettings(ctx context.Context, userID int64) error {
+	return UserSettingsProtectedCache.Delete(ctx, strconv.FormatInt(userID, 10))
+}
+
 // 获取用户缓存设置, 去除 userSettingCache（带空值保护）
 
 func GetUserSettings(ctx context.Context, userID int64) (*UserSettingsCache, error) {
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/cloudwego/hertz/pkg/app"
+
+	"AreYouOK/internal/middleware"
+	"AreYouOK/internal/model/dto"
+	"AreYouOK/internal/service"
+	"AreYouOK/pkg/errors"
+	"AreYouOK/pkg/response"
+)
+
+// ListActivity 查看本人账号的活动记录（登录、设备、联系人与设置变更等）
+// GET /v1/users/me/activity
+func ListActivity(ctx context.Context, c *app.RequestContext) {
+	userID, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	var query dto.ActivityQuery
+	if err := c.BindAndValidate(&query); err != nil {
+		response.BindError(ctx, c, err)
+		return
+	}
+
+	cursorID, ok := parseAuditPage(ctx, c, query.Cursor, &query.Limit)
+	if !ok {
+		return
+	}
+
+	result, nextCursor, err := service.Audit().ListMine(ctx, userID, cursorID, query.Limit)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	response.SuccessWithMeta(ctx, c, result, auditPageMeta(nextCursor))
+}
+
+// QueryAuditEvents 运维按用户、事件类型、trace ID 与时间范围查询审计事件
+// GET /v1/admin/audit-events
+func QueryAuditEvents(ctx context.Context, c *app.RequestContext) {
+	var query dto.AdminAuditQuery
+	if err := c.BindAndValidate(&query); err != nil {
+		response.BindError(ctx, c, err)
+		return
+	}
+
+	cursorID, ok := parseAuditPage(ctx, c, query.Cursor, &query.Limit)
+	if !ok {
+		return
+	}
+
+	result, nextCursor, err := service.Audit().Query(ctx, query, cursorID)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	response.SuccessWithMeta(ctx, c, result, auditPageMeta(nextCursor))
+}
+
+// parseAuditPage 解析游标并把 limit 限制在 1 到 100，默认 20
+func parseAuditPage(ctx context.Context, c *app.RequestContext, cursor string, limit *int) (int64, bool) {
+	if *limit <= 0 {
+		*limit = 20
+	}
+	if *limit > 100 {
+		*limit = 100
+	}
+
+	if cursor == "" {
+		return 0, true
+	}
+	cursorID, err := strconv.ParseInt(cursor, 10, 64)
+	if err != nil {
+		response.Error(ctx, c, errors.Definition{
+			Code:    "INVALID_CURSOR",
+			Message: "Invalid cursor format",
+		})
+		return 0, false
+	}
+	return cursorID, true
+}
+
+func auditPageMeta(nextCursor int64) map[string]interface{} {
+	meta := make(map[string]interface{})
+	if nextCursor > 0 {
+		meta["next_cursor"] = strconv.FormatInt(nextCursor, 10)
+	}
+	return meta
+}
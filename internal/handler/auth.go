@@ -94,7 +94,7 @@ func ExchangeAlipayAuth(ctx context.Context, c *app.RequestContext) {
 
 	authService := service.Auth()
 	//到注册这里是一定有 openid 的
-	result, err := authService.ExchangeAlipayAuthCode(ctx, encryptedData, req.Device, openid)
+	result, err := authService.ExchangeAlipayAuthCode(ctx, encryptedData, req.Device, openid, c.ClientIP())
 
 	if err != nil {
 		response.Error(ctx, c, err)
@@ -104,6 +104,25 @@ func ExchangeAlipayAuth(ctx context.Context, c *app.RequestContext) {
 	response.Success(ctx, c, result)
 } // 先去完善存储层
 
+// ExchangeWechatAuth 微信小程序登录，手机号匹配与内测名额逻辑与支付宝一致
+// POST /v1/auth/miniapp/wechat/exchange
+func ExchangeWechatAuth(ctx context.Context, c *app.RequestContext) {
+	var req dto.WechatExchangeRequest
+
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BindError(ctx, c, err)
+		return
+	}
+
+	result, err := service.Auth().ExchangeWechatAuthCode(ctx, req, c.ClientIP())
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	response.Success(ctx, c, result)
+}
+
 func defaultString(value, fallback string) string {
 	if value != "" {
 		return value
@@ -206,10 +225,15 @@ func VerifyCaptcha(ctx context.Context, c *app.RequestContext) {
 
 	authService := service.Auth()
 
+	var device dto.DeviceInfo
+	if req.Device != nil {
+		device = *req.Device
+	}
+
 	var result *dto.VerifyCaptchaResponse
 	var err error
 
-	result, err = authService.VerifyPhoneCaptchaAndLogin(ctx, req.Phone, req.VerifyCode, openid)
+	result, err = authService.VerifyPhoneCaptchaAndLogin(ctx, req.Phone, req.VerifyCode, openid, device, c.ClientIP())
 
 	if err != nil {
 		response.Error(ctx, c, err)
@@ -230,7 +254,7 @@ func RefreshToken(ctx context.Context, c *app.RequestContext) {
 	}
 
 	authService := service.Auth()
-	result, err := authService.RefreshToken(ctx, req.RefreshToken)
+	result, err := authService.RefreshToken(ctx, req.RefreshToken, c.ClientIP())
 
 	if err != nil {
 		response.Error(ctx, c, err)
@@ -53,11 +53,11 @@ func CreateContact(ctx context.Context, c *app.RequestContext) {
 		return
 	}
 
-	// 验证优先级范围
-	if req.Priority < 1 || req.Priority > 3 {
+	// 验证优先级范围，上限由用户套餐决定（在 service 中校验）
+	if req.Priority < 1 {
 		response.Error(ctx, c, errors.Definition{
 			Code:    "INVALID_PRIORITY",
-			Message: "Priority must be between 1 and 3",
+			Message: "Priority must be a positive integer",
 		})
 		return
 	}
@@ -114,6 +114,11 @@ func DeleteContact(ctx context.Context, c *app.RequestContext) {
 func UpdateContact(ctx context.Context, c *app.RequestContext) {
 	var req dto.UpdateContactRequest
 
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BindError(ctx, c, err)
+		return
+	}
+
 	if req.Phone != "" {
 		if !utils.ValidatePhone(req.Phone) {
 			response.Error(ctx, c, errors.Definition{
@@ -124,11 +129,11 @@ func UpdateContact(ctx context.Context, c *app.RequestContext) {
 		}
 	}
 
-	// 验证优先级范围
-	if req.Priority < 1 || req.Priority > 3 {
+	// 验证优先级范围，上限由用户套餐决定（在 service 中校验）
+	if req.Priority < 1 {
 		response.Error(ctx, c, errors.Definition{
 			Code:    "INVALID_PRIORITY",
-			Message: "Priority must be between 1 and 3",
+			Message: "Priority must be a positive integer",
 		})
 		return
 	}
@@ -179,3 +184,241 @@ func ReplaceContacts(ctx context.Context, c *app.RequestContext) {
 
 	response.Success(ctx, c, result)
 }
+
+// ResendContactConsent 重新发送联系人确认短信
+// POST /v1/contacts/:priority/consent
+func ResendContactConsent(ctx context.Context, c *app.RequestContext) {
+	priority, err := strconv.Atoi(c.Param("priority"))
+	if err != nil {
+		response.Error(ctx, c, errors.Definition{
+			Code:    "INVALID_PRIORITY",
+			Message: "Invalid priority format",
+		})
+		return
+	}
+
+	userID, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	result, err := service.Contact().ResendConsent(ctx, userID, priority)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	response.Success(ctx, c, result)
+}
+
+// ImportContacts 从 vCard 导入紧急联系人，apply 为 false 时只返回建议的优先级映射
+// POST /v1/contacts/import
+func ImportContacts(ctx context.Context, c *app.RequestContext) {
+	var req dto.ContactImportRequest
+
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BindError(ctx, c, err)
+		return
+	}
+
+	userID, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	result, err := service.Contact().ImportContacts(ctx, userID, req)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	response.Success(ctx, c, result)
+}
+
+// ExportContacts 导出紧急联系人 vCard，当前会话完成二次验证前号码脱敏
+// GET /v1/contacts/export.vcf
+func ExportContacts(ctx context.Context, c *app.RequestContext) {
+	userID, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	sessionID, _, _, _ := middleware.GetTokenClaims(ctx, c)
+
+	card, unmasked, err := service.Contact().ExportContacts(ctx, userID, sessionID)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="contacts.vcf"`)
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Phone-Masked", strconv.FormatBool(!unmasked))
+	c.Data(200, "text/vcard; charset=utf-8", card)
+}
+
+// GetContactConsent 联系人打开确认链接，查看邀请信息
+// GET /v1/contact-consents/:token
+func GetContactConsent(ctx context.Context, c *app.RequestContext) {
+	result, err := service.Contact().GetConsent(ctx, c.Param("token"))
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	response.Success(ctx, c, result)
+}
+
+// ConfirmContactConsent 联系人同意成为紧急联系人
+// POST /v1/contact-consents/:token/confirm
+func ConfirmContactConsent(ctx context.Context, c *app.RequestContext) {
+	result, err := service.Contact().RespondConsent(ctx, c.Param("token"), true)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	response.Success(ctx, c, result)
+}
+
+// DeclineContactConsent 联系人拒绝成为紧急联系人
+// POST /v1/contact-consents/:token/decline
+func DeclineContactConsent(ctx context.Context, c *app.RequestContext) {
+	result, err := service.Contact().RespondConsent(ctx, c.Param("token"), false)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	response.Success(ctx, c, result)
+}
+
+// OptOutContact 联系人通过告警短信中的退订链接退订，之后该号码不再收到任何短信
+// POST /v1/contact-opt-outs
+func OptOutContact(ctx context.Context, c *app.RequestContext) {
+	var req dto.ContactOptOutRequest
+
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BindError(ctx, c, err)
+		return
+	}
+
+	result, err := service.OptOut().OptOutByLink(ctx, req.ContactID, req.Signature)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	response.Success(ctx, c, result)
+}
+
+// LoginContactPortal 联系人用手机验证码登录联系人门户，验证码通过 /v1/contact-portal/phone/send-captcha 获取
+// POST /v1/contact-portal/phone/verify
+func LoginContactPortal(ctx context.Context, c *app.RequestContext) {
+	var req dto.ContactPortalVerifyRequest
+
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BindError(ctx, c, err)
+		return
+	}
+
+	if !utils.ValidatePhone(req.Phone) {
+		response.Error(ctx, c, errors.Definition{
+			Code:    "INVALID_PHONE",
+			Message: "Invalid phone number format",
+		})
+		return
+	}
+
+	result, err := service.ContactPortal().Login(ctx, req.Phone, req.VerifyCode)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	response.Success(ctx, c, result)
+}
+
+// ListContactRequesters 查看哪些用户把当前号码设为紧急联系人
+// GET /v1/contact-portal/me/requesters
+func ListContactRequesters(ctx context.Context, c *app.RequestContext) {
+	phoneCipher, ok := middleware.GetContactPhoneCipher(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("contact session not found in context"))
+		return
+	}
+
+	result, err := service.ContactPortal().ListRequesters(ctx, phoneCipher)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	response.Success(ctx, c, result)
+}
+
+// ListContactAlerts 查看当前号码收到过的告警短信
+// GET /v1/contact-portal/me/alerts
+func ListContactAlerts(ctx context.Context, c *app.RequestContext) {
+	phoneCipher, ok := middleware.GetContactPhoneCipher(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("contact session not found in context"))
+		return
+	}
+
+	result, err := service.ContactPortal().ListAlerts(ctx, phoneCipher)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	response.Success(ctx, c, result)
+}
+
+// AcceptContactRequest 联系人在门户中同意成为紧急联系人
+// POST /v1/contact-portal/me/requesters/:contact_id/accept
+func AcceptContactRequest(ctx context.Context, c *app.RequestContext) {
+	respondContactRequest(ctx, c, true)
+}
+
+// DeclineContactRequest 联系人在门户中拒绝成为紧急联系人
+// POST /v1/contact-portal/me/requesters/:contact_id/decline
+func DeclineContactRequest(ctx context.Context, c *app.RequestContext) {
+	respondContactRequest(ctx, c, false)
+}
+
+func respondContactRequest(ctx context.Context, c *app.RequestContext, accept bool) {
+	phoneCipher, ok := middleware.GetContactPhoneCipher(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("contact session not found in context"))
+		return
+	}
+
+	result, err := service.ContactPortal().Respond(ctx, phoneCipher, c.Param("contact_id"), accept)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	response.Success(ctx, c, result)
+}
+
+// LeaveContactList 联系人退出某位用户的紧急联系人列表
+// DELETE /v1/contact-portal/me/requesters/:contact_id
+func LeaveContactList(ctx context.Context, c *app.RequestContext) {
+	phoneCipher, ok := middleware.GetContactPhoneCipher(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("contact session not found in context"))
+		return
+	}
+
+	if err := service.ContactPortal().Leave(ctx, phoneCipher, c.Param("contact_id")); err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	response.NoContent(ctx, c)
+}
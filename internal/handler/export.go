@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"go.uber.org/zap"
+
+	"AreYouOK/internal/middleware"
+	"AreYouOK/internal/model/dto"
+	"AreYouOK/internal/queue"
+	"AreYouOK/internal/service"
+	"AreYouOK/pkg/response"
+)
+
+// CreateDataExport 发起个人数据导出（异步生成）
+// POST /v1/users/me/export
+func CreateDataExport(ctx context.Context, c *app.RequestContext) {
+	userID, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	var req dto.CreateDataExportRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BindError(ctx, c, err)
+		return
+	}
+
+	sessionID, _, _, _ := middleware.GetTokenClaims(ctx, c)
+
+	result, exportMsg, err := service.Export().Request(ctx, userID, sessionID, req)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	// 在 Handler 层负责发布到队列，投递失败时释放导出名额
+	if err := queue.PublishUserExport(*exportMsg); err != nil {
+		zap.L().Error("Failed to publish user export message",
+			zap.Int64("export_code", exportMsg.ExportCode),
+			zap.String("user_id", userID),
+			zap.Error(err),
+		)
+		if markErr := service.Export().MarkFailed(ctx, exportMsg.ExportCode, err.Error()); markErr != nil {
+			zap.L().Error("Failed to mark user export failed",
+				zap.Int64("export_code", exportMsg.ExportCode),
+				zap.Error(markErr),
+			)
+		}
+		response.Error(ctx, c, err)
+		return
+	}
+
+	response.Success(ctx, c, result)
+}
+
+// GetDataExport 查询个人数据导出状态，就绪时返回一次性下载链接
+// GET /v1/users/me/exports/:id
+func GetDataExport(ctx context.Context, c *app.RequestContext) {
+	userID, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	result, err := service.Export().GetStatus(ctx, userID, c.Param("id"))
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+	response.Success(ctx, c, result)
+}
+
+// DownloadDataExport 通过签名链接下载导出归档（无需登录，链接只能使用一次）
+// GET /v1/exports/:id/download
+func DownloadDataExport(ctx context.Context, c *app.RequestContext) {
+	archive, filename, err := service.Export().Download(
+		ctx,
+		c.Param("id"),
+		c.Query("expires"),
+		c.Query("signature"),
+	)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Header("Cache-Control", "no-store")
+	c.Data(200, "application/zip", archive)
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/hertz/pkg/app"
+
+	"AreYouOK/internal/middleware"
+	"AreYouOK/internal/model/dto"
+	"AreYouOK/internal/service"
+	"AreYouOK/pkg/response"
+)
+
+// ListIdentities 列出当前账号绑定的登录身份
+// GET /v1/users/me/identities
+func ListIdentities(ctx context.Context, c *app.RequestContext) {
+	userID, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	result, err := service.Identity().List(ctx, userID)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+	response.Success(ctx, c, result)
+}
+
+// LinkIdentity 绑定另一个平台的登录身份
+// POST /v1/users/me/identities
+func LinkIdentity(ctx context.Context, c *app.RequestContext) {
+	userID, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	var req dto.LinkIdentityRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BindError(ctx, c, err)
+		return
+	}
+
+	result, err := service.Identity().Link(ctx, userID, req)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+	response.Success(ctx, c, result)
+}
+
+// UnlinkIdentity 解绑某个平台的登录身份
+// DELETE /v1/users/me/identities/:provider
+func UnlinkIdentity(ctx context.Context, c *app.RequestContext) {
+	userID, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	if err := service.Identity().Unlink(ctx, userID, c.Param("provider")); err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	c.Status(204)
+}
+
+// MergeAccount 把另一个账号合并到当前账号
+// POST /v1/users/me/merge
+func MergeAccount(ctx context.Context, c *app.RequestContext) {
+	userID, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	var req dto.MergeAccountRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BindError(ctx, c, err)
+		return
+	}
+
+	sessionID, _, _, _ := middleware.GetTokenClaims(ctx, c)
+
+	result, err := service.AccountMerge().Merge(ctx, userID, sessionID, c.ClientIP(), req)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+	response.Success(ctx, c, result)
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"AreYouOK/internal/middleware"
+	"AreYouOK/internal/model"
 	"AreYouOK/internal/model/dto"
 	"AreYouOK/internal/queue"
 	"AreYouOK/internal/service"
@@ -287,7 +288,7 @@ func CompleteJourney(ctx context.Context, c *app.RequestContext) {
 	}
 
 	journeyService := service.Journey()
-	result, err := journeyService.CompleteJourney(ctx, userID, journeyID)
+	result, err := journeyService.CompleteJourney(ctx, userID, journeyID, model.JourneyCompletedByManual)
 	if err != nil {
 		response.Error(ctx, c, err)
 		return
@@ -408,3 +409,266 @@ func CancelJourney(ctx context.Context, c *app.RequestContext) {
 
 // 	response.NoContent(ctx, c)
 // }
+
+// ExtendJourney 延长行程（晚点了），在当前预计返回时间上顺延
+// POST /v1/journeys/:journey_id/extend
+func ExtendJourney(ctx context.Context, c *app.RequestContext) {
+	journeyID, err := strconv.ParseInt(c.Param("journey_id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, c, errors.Definition{
+			Code:    "INVALID_JOURNEY_ID",
+			Message: "Invalid journey ID format",
+		})
+		return
+	}
+
+	var req dto.ExtendJourneyRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BindError(ctx, c, err)
+		return
+	}
+
+	userIDStr, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	userID, err := strconv.ParseInt(userIDStr, 10, 64)
+	if err != nil {
+		response.Error(ctx, c, errors.Definition{
+			Code:    "INVALID_USER_ID",
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	result, timeoutMsg, err := service.Journey().ExtendJourney(ctx, userID, journeyID, req.Minutes)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	// 新的超时消息由 Handler 层发布，旧消息在 consumer 中按 message_id 判定为过期
+	if timeoutMsg != nil {
+		if err := queue.PublishJourneyTimeout(*timeoutMsg); err != nil {
+			// 发布失败时由定时任务补投
+			zap.L().Error("Failed to publish extended journey timeout message",
+				zap.Int64("journey_id", journeyID),
+				zap.Int64("user_id", userID),
+				zap.Error(err),
+			)
+		}
+	}
+
+	response.Success(ctx, c, result)
+}
+
+// GetJourneyTimeline 行程时间线（创建、改期、延长、完成、超时、取消）
+// GET /v1/journeys/:journey_id/timeline
+func GetJourneyTimeline(ctx context.Context, c *app.RequestContext) {
+	journeyID, err := strconv.ParseInt(c.Param("journey_id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, c, errors.Definition{
+			Code:    "INVALID_JOURNEY_ID",
+			Message: "Invalid journey ID format",
+		})
+		return
+	}
+
+	userIDStr, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	userID, err := strconv.ParseInt(userIDStr, 10, 64)
+	if err != nil {
+		response.Error(ctx, c, errors.Definition{
+			Code:    "INVALID_USER_ID",
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	events, err := service.Journey().GetJourneyTimeline(ctx, userID, journeyID)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	response.Success(ctx, c, events)
+}
+
+// CheckInJourneyCheckpoint 检查点打卡
+// POST /v1/journeys/:journey_id/checkpoints/:seq/check-in
+func CheckInJourneyCheckpoint(ctx context.Context, c *app.RequestContext) {
+	journeyID, err := strconv.ParseInt(c.Param("journey_id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, c, errors.Definition{
+			Code:    "INVALID_JOURNEY_ID",
+			Message: "Invalid journey ID format",
+		})
+		return
+	}
+
+	seq, err := strconv.Atoi(c.Param("seq"))
+	if err != nil || seq <= 0 {
+		response.Error(ctx, c, errors.JourneyCheckpointNotFound)
+		return
+	}
+
+	userIDStr, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	userID, err := strconv.ParseInt(userIDStr, 10, 64)
+	if err != nil {
+		response.Error(ctx, c, errors.Definition{
+			Code:    "INVALID_USER_ID",
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	checkpoint, err := service.Journey().CheckInCheckpoint(ctx, userID, journeyID, seq)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	response.Success(ctx, c, checkpoint)
+}
+
+// ReportJourneyLocations 上报行程定位点（小程序定期上报，离线期间的点可批量上报）
+// POST /v1/journeys/:journey_id/locations
+func ReportJourneyLocations(ctx context.Context, c *app.RequestContext) {
+	journeyID, err := strconv.ParseInt(c.Param("journey_id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, c, errors.Definition{
+			Code:    "INVALID_JOURNEY_ID",
+			Message: "Invalid journey ID format",
+		})
+		return
+	}
+
+	var req dto.ReportJourneyLocationsRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BindError(ctx, c, err)
+		return
+	}
+
+	userIDStr, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	userID, err := strconv.ParseInt(userIDStr, 10, 64)
+	if err != nil {
+		response.Error(ctx, c, errors.Definition{
+			Code:    "INVALID_USER_ID",
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	result, err := service.Journey().ReportLocations(ctx, userID, journeyID, req)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	response.Success(ctx, c, result)
+}
+
+// ShareJourney 生成行程公开分享链接，重新生成后旧链接失效
+// POST /v1/journeys/:journey_id/share
+func ShareJourney(ctx context.Context, c *app.RequestContext) {
+	journeyID, err := strconv.ParseInt(c.Param("journey_id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, c, errors.Definition{
+			Code:    "INVALID_JOURNEY_ID",
+			Message: "Invalid journey ID format",
+		})
+		return
+	}
+
+	var req dto.CreateJourneyShareRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BindError(ctx, c, err)
+		return
+	}
+
+	userIDStr, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	userID, err := strconv.ParseInt(userIDStr, 10, 64)
+	if err != nil {
+		response.Error(ctx, c, errors.Definition{
+			Code:    "INVALID_USER_ID",
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	result, err := service.Journey().ShareJourney(ctx, userID, journeyID, req)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	response.Success(ctx, c, result)
+}
+
+// RevokeJourneyShare 撤销行程分享链接
+// DELETE /v1/journeys/:journey_id/share
+func RevokeJourneyShare(ctx context.Context, c *app.RequestContext) {
+	journeyID, err := strconv.ParseInt(c.Param("journey_id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, c, errors.Definition{
+			Code:    "INVALID_JOURNEY_ID",
+			Message: "Invalid journey ID format",
+		})
+		return
+	}
+
+	userIDStr, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	userID, err := strconv.ParseInt(userIDStr, 10, 64)
+	if err != nil {
+		response.Error(ctx, c, errors.Definition{
+			Code:    "INVALID_USER_ID",
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	if err := service.Journey().RevokeJourneyShare(ctx, userID, journeyID); err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	response.NoContent(ctx, c)
+}
+
+// GetSharedJourney 分享页查看行程（分享链接 token 鉴权，无需登录）
+// GET /v1/shared/journeys/:token
+func GetSharedJourney(ctx context.Context, c *app.RequestContext) {
+	result, err := service.Journey().GetSharedJourney(ctx, c.Param("token"))
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	response.Success(ctx, c, result)
+}
@@ -0,0 +1,258 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"go.uber.org/zap"
+
+	"AreYouOK/internal/middleware"
+	"AreYouOK/internal/model"
+	"AreYouOK/internal/model/dto"
+	"AreYouOK/internal/queue"
+	"AreYouOK/internal/service"
+	"AreYouOK/pkg/errors"
+	"AreYouOK/pkg/response"
+)
+
+// journeyTemplateUserID 当前登录用户的 public_id，解析失败时已写入错误响应
+func journeyTemplateUserID(ctx context.Context, c *app.RequestContext) (int64, bool) {
+	userIDStr, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return 0, false
+	}
+
+	userID, err := strconv.ParseInt(userIDStr, 10, 64)
+	if err != nil {
+		response.Error(ctx, c, errors.InvalidUserID)
+		return 0, false
+	}
+	return userID, true
+}
+
+// respondJourneyStarted 发布新行程的超时消息并返回行程，与创建行程的响应一致
+func respondJourneyStarted(
+	ctx context.Context,
+	c *app.RequestContext,
+	userID int64,
+	journey *model.Journey,
+	timeoutMsg *model.JourneyTimeoutMessage,
+) {
+	if timeoutMsg != nil {
+		if err := queue.PublishJourneyTimeout(*timeoutMsg); err != nil {
+			// 记录错误但不影响主流程，由定时任务补投
+			zap.L().Error("Failed to publish journey timeout message",
+				zap.Int64("journey_id", journey.ID),
+				zap.Int64("user_id", userID),
+				zap.Error(err),
+			)
+		} else {
+			zap.L().Info("Journey timeout message published",
+				zap.Int64("journey_id", journey.ID),
+				zap.Duration("delay", time.Duration(timeoutMsg.DelaySeconds)*time.Second),
+			)
+		}
+	}
+
+	c.JSON(201, response.SuccessResponse{
+		Data: &dto.JourneyItem{
+			ID:                 strconv.FormatInt(journey.ID, 10),
+			Title:              journey.Title,
+			Note:               journey.Note,
+			Status:             string(journey.Status),
+			ExpectedReturnTime: journey.ExpectedReturnTime,
+			ActualReturnTime:   journey.ActualReturnTime,
+			CreatedAt:          journey.CreatedAt,
+		},
+	})
+}
+
+// ListJourneyTemplates 行程模板列表
+// GET /v1/journey-templates
+func ListJourneyTemplates(ctx context.Context, c *app.RequestContext) {
+	userID, ok := journeyTemplateUserID(ctx, c)
+	if !ok {
+		return
+	}
+
+	result, err := service.Journey().ListJourneyTemplates(ctx, userID)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	response.Success(ctx, c, result)
+}
+
+// CreateJourneyTemplate 创建行程模板
+// POST /v1/journey-templates
+func CreateJourneyTemplate(ctx context.Context, c *app.RequestContext) {
+	var req dto.JourneyTemplateRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BindError(ctx, c, err)
+		return
+	}
+
+	userID, ok := journeyTemplateUserID(ctx, c)
+	if !ok {
+		return
+	}
+
+	result, err := service.Journey().CreateJourneyTemplate(ctx, userID, req)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	c.JSON(201, response.SuccessResponse{
+		Data: result,
+	})
+}
+
+// UpdateJourneyTemplate 修改行程模板（全量替换），尚未开始的安排按新模板重新生成
+// PUT /v1/journey-templates/:template_id
+func UpdateJourneyTemplate(ctx context.Context, c *app.RequestContext) {
+	templateID, err := strconv.ParseInt(c.Param("template_id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, c, errors.JourneyTemplateNotFound)
+		return
+	}
+
+	var req dto.JourneyTemplateRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BindError(ctx, c, err)
+		return
+	}
+
+	userID, ok := journeyTemplateUserID(ctx, c)
+	if !ok {
+		return
+	}
+
+	result, err := service.Journey().UpdateJourneyTemplate(ctx, userID, templateID, req)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	response.Success(ctx, c, result)
+}
+
+// DeleteJourneyTemplate 删除行程模板
+// DELETE /v1/journey-templates/:template_id
+func DeleteJourneyTemplate(ctx context.Context, c *app.RequestContext) {
+	templateID, err := strconv.ParseInt(c.Param("template_id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, c, errors.JourneyTemplateNotFound)
+		return
+	}
+
+	userID, ok := journeyTemplateUserID(ctx, c)
+	if !ok {
+		return
+	}
+
+	if err := service.Journey().DeleteJourneyTemplate(ctx, userID, templateID); err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	response.NoContent(ctx, c)
+}
+
+// StartJourneyFromTemplate 按模板立即开始行程
+// POST /v1/journey-templates/:template_id/start
+func StartJourneyFromTemplate(ctx context.Context, c *app.RequestContext) {
+	templateID, err := strconv.ParseInt(c.Param("template_id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, c, errors.JourneyTemplateNotFound)
+		return
+	}
+
+	userID, ok := journeyTemplateUserID(ctx, c)
+	if !ok {
+		return
+	}
+
+	journey, timeoutMsg, err := service.Journey().StartJourneyFromTemplate(ctx, userID, templateID)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	respondJourneyStarted(ctx, c, userID, journey, timeoutMsg)
+}
+
+// ListJourneyOccurrences 重复行程的安排，可按状态过滤
+// GET /v1/journey-occurrences?status=scheduled&limit=20
+func ListJourneyOccurrences(ctx context.Context, c *app.RequestContext) {
+	userID, ok := journeyTemplateUserID(ctx, c)
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	result, err := service.Journey().ListJourneyOccurrences(ctx, userID, c.Query("status"), limit)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	response.Success(ctx, c, result)
+}
+
+// ConfirmJourneyOccurrence 确认出发，按安排开始行程
+// POST /v1/journey-occurrences/:occurrence_id/confirm
+func ConfirmJourneyOccurrence(ctx context.Context, c *app.RequestContext) {
+	occurrenceID, err := strconv.ParseInt(c.Param("occurrence_id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, c, errors.JourneyOccurrenceNotFound)
+		return
+	}
+
+	userID, ok := journeyTemplateUserID(ctx, c)
+	if !ok {
+		return
+	}
+
+	journey, timeoutMsg, err := service.Journey().ConfirmJourneyOccurrence(ctx, userID, occurrenceID)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	respondJourneyStarted(ctx, c, userID, journey, timeoutMsg)
+}
+
+// SkipJourneyOccurrence 跳过本次安排
+// POST /v1/journey-occurrences/:occurrence_id/skip
+func SkipJourneyOccurrence(ctx context.Context, c *app.RequestContext) {
+	occurrenceID, err := strconv.ParseInt(c.Param("occurrence_id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, c, errors.JourneyOccurrenceNotFound)
+		return
+	}
+
+	userID, ok := journeyTemplateUserID(ctx, c)
+	if !ok {
+		return
+	}
+
+	if err := service.Journey().SkipJourneyOccurrence(ctx, userID, occurrenceID); err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	response.NoContent(ctx, c)
+}
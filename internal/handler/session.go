@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/hertz/pkg/app"
+
+	"AreYouOK/internal/middleware"
+	"AreYouOK/internal/service"
+	"AreYouOK/pkg/response"
+)
+
+// ListSessions 列出当前用户的登录设备
+// GET /v1/users/me/sessions
+func ListSessions(ctx context.Context, c *app.RequestContext) {
+	userID, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	sessionID, _, _, _ := middleware.GetTokenClaims(ctx, c)
+
+	result, err := service.Session().ListSessions(ctx, userID, sessionID)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+	response.Success(ctx, c, result)
+}
+
+// RevokeSession 移除某台设备的登录
+// DELETE /v1/users/me/sessions/:id
+func RevokeSession(ctx context.Context, c *app.RequestContext) {
+	userID, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	if err := service.Session().RevokeSession(ctx, userID, c.Param("id")); err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	c.Status(204)
+}
+
+// Logout 登出当前设备，当前 access token 立即失效
+// POST /v1/auth/logout
+func Logout(ctx context.Context, c *app.RequestContext) {
+	userID, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	sessionID, jti, expiresAt, _ := middleware.GetTokenClaims(ctx, c)
+
+	if err := service.Session().Logout(ctx, userID, sessionID, jti, expiresAt); err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	c.Status(204)
+}
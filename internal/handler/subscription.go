@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+
+	"AreYouOK/internal/model"
+	"AreYouOK/internal/model/dto"
+	"AreYouOK/internal/service"
+	"AreYouOK/pkg/response"
+)
+
+// AdminSubscribe 运维为用户开通或变更套餐（支付对接前的人工开通入口）
+// PUT /v1/admin/users/:user_id/subscription
+func AdminSubscribe(ctx context.Context, c *app.RequestContext) {
+	var req dto.AdminSubscribeRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BindError(ctx, c, err)
+		return
+	}
+
+	result, err := service.Subscription().AdminSubscribe(ctx, c.Param("user_id"), model.PlanCode(req.Plan))
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	response.Success(ctx, c, result)
+}
+
+// AdminCancelSubscription 运维取消用户订阅，之后按免费版处理
+// DELETE /v1/admin/users/:user_id/subscription
+func AdminCancelSubscription(ctx context.Context, c *app.RequestContext) {
+	result, err := service.Subscription().AdminCancelSubscription(ctx, c.Param("user_id"))
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	response.Success(ctx, c, result)
+}
@@ -11,7 +11,9 @@ import (
 	"AreYouOK/internal/model/dto"
 	"AreYouOK/internal/queue"
 	"AreYouOK/internal/service"
+	"AreYouOK/pkg/errors"
 	"AreYouOK/pkg/response"
+	"AreYouOK/utils"
 )
 
 // GetUserStatus 获取用户状态和引导进度
@@ -117,6 +119,46 @@ func GetUserQuotas(ctx context.Context, c *app.RequestContext) {
 	response.Success(ctx, c, result)
 }
 
+// GetUserSubscription 获取用户订阅套餐
+// GET /v1/users/me/subscription
+func GetUserSubscription(ctx context.Context, c *app.RequestContext) {
+	userID, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	result, err := service.Subscription().GetUserSubscription(ctx, userID)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+	response.Success(ctx, c, result)
+}
+
+// RedeemQuotaCode 使用兑换码兑换额度
+// POST /v1/users/me/quotas/redeem
+func RedeemQuotaCode(ctx context.Context, c *app.RequestContext) {
+	var req dto.RedeemQuotaRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BindError(ctx, c, err)
+		return
+	}
+
+	userID, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	result, err := service.Redeem().RedeemCode(ctx, userID, req.Code)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+	response.Success(ctx, c, result)
+}
+
 // DeleteUser 软删除 User 部分
 // DELETE /v1/users/me
 
@@ -137,6 +179,75 @@ func DeleteUserProfile(ctx context.Context, c *app.RequestContext) {
 	c.Status(204) // 这里需要前端清楚 session 和 token
 }
 
+// RestoreUserProfile 恢复窗口内恢复已注销的账号（需重新登录后调用）
+// POST /v1/users/me/restore
+func RestoreUserProfile(ctx context.Context, c *app.RequestContext) {
+	userID, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	result, err := service.Erasure().Restore(ctx, userID)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	response.Success(ctx, c, result)
+}
+
+// SendStepUpCaptcha 二次验证：向本人绑定的手机号发送验证码
+// POST /v1/users/me/step-up/send-captcha
+func SendStepUpCaptcha(ctx context.Context, c *app.RequestContext) {
+	userID, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	var req dto.StepUpCaptchaRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BindError(ctx, c, err)
+		return
+	}
+
+	if err := service.StepUp().SendCaptcha(ctx, userID, req); err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	response.Success(ctx, c, map[string]interface{}{
+		"message": "Captcha sent successfully",
+	})
+}
+
+// VerifyStepUp 二次验证：校验验证码，有效期内当前会话可以查看完整号码
+// POST /v1/users/me/step-up
+func VerifyStepUp(ctx context.Context, c *app.RequestContext) {
+	userID, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	var req dto.StepUpRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BindError(ctx, c, err)
+		return
+	}
+
+	sessionID, _, _, _ := middleware.GetTokenClaims(ctx, c)
+
+	result, err := service.StepUp().Verify(ctx, userID, sessionID, req.VerifyCode)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	response.Success(ctx, c, result)
+}
+
 // GetWaitListInfo 获取排队/引导信息（通过 auth_code -> open_id）
 // POST /v1/users/waitlist
 func GetWaitListInfo(ctx context.Context, c *app.RequestContext) {
@@ -156,3 +267,68 @@ func GetWaitListInfo(ctx context.Context, c *app.RequestContext) {
 
 	response.Success(ctx, c, result)
 }
+
+// SendChangePhoneCaptcha 向新手机号发送验证码（更换手机号第一步）
+// 已绑定手机号时需先通过 POST /v1/users/me/step-up 验证旧号码
+// 超过发送阈值时需先通过 POST /v1/auth/phone/verify-slider 获取滑块 token
+// POST /v1/users/me/phone/send-captcha
+func SendChangePhoneCaptcha(ctx context.Context, c *app.RequestContext) {
+	userID, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	var req dto.SendCaptchaRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BindError(ctx, c, err)
+		return
+	}
+
+	if !utils.ValidatePhone(req.Phone) {
+		response.Error(ctx, c, errors.InvalidPhone)
+		return
+	}
+
+	sessionID, _, _, _ := middleware.GetTokenClaims(ctx, c)
+
+	if err := service.PhoneChange().SendCaptcha(ctx, userID, sessionID, req); err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	response.Success(ctx, c, map[string]interface{}{
+		"message": "Captcha sent successfully",
+	})
+}
+
+// ChangePhone 校验新手机号验证码并更换绑定手机号，其他设备的登录随之失效
+// PUT /v1/users/me/phone
+func ChangePhone(ctx context.Context, c *app.RequestContext) {
+	userID, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	var req dto.ChangePhoneRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BindError(ctx, c, err)
+		return
+	}
+
+	if !utils.ValidatePhone(req.Phone) {
+		response.Error(ctx, c, errors.InvalidPhone)
+		return
+	}
+
+	sessionID, _, _, _ := middleware.GetTokenClaims(ctx, c)
+
+	result, err := service.PhoneChange().ChangePhone(ctx, userID, sessionID, c.ClientIP(), req)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	response.Success(ctx, c, result)
+}
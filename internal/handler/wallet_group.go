@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/hertz/pkg/app"
+
+	"AreYouOK/internal/middleware"
+	"AreYouOK/internal/model/dto"
+	"AreYouOK/internal/service"
+	"AreYouOK/pkg/errors"
+	"AreYouOK/pkg/response"
+	"AreYouOK/utils"
+)
+
+// CreateWalletGroup 创建共享钱包
+// POST /v1/wallet-groups
+func CreateWalletGroup(ctx context.Context, c *app.RequestContext) {
+	var req dto.CreateWalletGroupRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BindError(ctx, c, err)
+		return
+	}
+
+	userID, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	result, err := service.WalletGroup().CreateGroup(ctx, userID, req)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+	response.Success(ctx, c, result)
+}
+
+// GetMyWalletGroup 获取当前用户所在的共享钱包
+// GET /v1/wallet-groups/me
+func GetMyWalletGroup(ctx context.Context, c *app.RequestContext) {
+	userID, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	result, err := service.WalletGroup().GetMyGroup(ctx, userID)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+	response.Success(ctx, c, result)
+}
+
+// InviteWalletGroupMember 邀请共享钱包成员（仅创建者），对方接受后加入
+// POST /v1/wallet-groups/me/members
+func InviteWalletGroupMember(ctx context.Context, c *app.RequestContext) {
+	var req dto.AddWalletGroupMemberRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BindError(ctx, c, err)
+		return
+	}
+
+	if !utils.ValidatePhone(req.Phone) {
+		response.Error(ctx, c, errors.InvalidPhone)
+		return
+	}
+
+	userID, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	result, err := service.WalletGroup().InviteMember(ctx, userID, req)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+	response.Success(ctx, c, result)
+}
+
+// CancelWalletGroupInvitation 撤销尚未接受的邀请（仅创建者）
+// DELETE /v1/wallet-groups/me/invitations/:invitation_id
+func CancelWalletGroupInvitation(ctx context.Context, c *app.RequestContext) {
+	userID, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	if err := service.WalletGroup().CancelInvitation(ctx, userID, c.Param("invitation_id")); err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	c.Status(204)
+}
+
+// ListWalletGroupInvitations 当前用户收到的共享钱包邀请
+// GET /v1/wallet-groups/invitations
+func ListWalletGroupInvitations(ctx context.Context, c *app.RequestContext) {
+	userID, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	result, err := service.WalletGroup().ListInvitations(ctx, userID)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+	response.Success(ctx, c, result)
+}
+
+// AcceptWalletGroupInvitation 接受邀请加入共享钱包
+// POST /v1/wallet-groups/invitations/:invitation_id/accept
+func AcceptWalletGroupInvitation(ctx context.Context, c *app.RequestContext) {
+	userID, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	result, err := service.WalletGroup().AcceptInvitation(ctx, userID, c.Param("invitation_id"))
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+	response.Success(ctx, c, result)
+}
+
+// DeclineWalletGroupInvitation 拒绝共享钱包邀请
+// POST /v1/wallet-groups/invitations/:invitation_id/decline
+func DeclineWalletGroupInvitation(ctx context.Context, c *app.RequestContext) {
+	userID, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	if err := service.WalletGroup().DeclineInvitation(ctx, userID, c.Param("invitation_id")); err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	c.Status(204)
+}
+
+// UpdateWalletGroupMember 更新成员月度上限或扣费顺序
+// PATCH /v1/wallet-groups/me/members/:user_id
+func UpdateWalletGroupMember(ctx context.Context, c *app.RequestContext) {
+	var req dto.UpdateWalletGroupMemberRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BindError(ctx, c, err)
+		return
+	}
+
+	userID, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	result, err := service.WalletGroup().UpdateMember(ctx, userID, c.Param("user_id"), req)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+	response.Success(ctx, c, result)
+}
+
+// RemoveWalletGroupMember 移除成员或自行退出
+// DELETE /v1/wallet-groups/me/members/:user_id
+func RemoveWalletGroupMember(ctx context.Context, c *app.RequestContext) {
+	userID, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	if err := service.WalletGroup().RemoveMember(ctx, userID, c.Param("user_id")); err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+
+	c.Status(204)
+}
+
+// TransferToWalletGroup 从个人钱包转入共享钱包
+// POST /v1/wallet-groups/me/transfer
+func TransferToWalletGroup(ctx context.Context, c *app.RequestContext) {
+	var req dto.TransferToWalletGroupRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BindError(ctx, c, err)
+		return
+	}
+
+	userID, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	result, err := service.WalletGroup().Transfer(ctx, userID, req)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+	response.Success(ctx, c, result)
+}
+
+// GetWalletGroupUsage 查询成员月度用量（仅创建者）
+// GET /v1/wallet-groups/me/usage
+func GetWalletGroupUsage(ctx context.Context, c *app.RequestContext) {
+	var req dto.WalletGroupUsageQuery
+	if err := c.BindAndValidate(&req); err != nil {
+		response.BindError(ctx, c, err)
+		return
+	}
+
+	userID, ok := middleware.GetUserID(ctx, c)
+	if !ok {
+		response.Error(ctx, c, fmt.Errorf("user ID not found in context"))
+		return
+	}
+
+	result, err := service.WalletGroup().GetUsage(ctx, userID, req)
+	if err != nil {
+		response.Error(ctx, c, err)
+		return
+	}
+	response.Success(ctx, c, result)
+}
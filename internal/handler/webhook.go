@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"go.uber.org/zap"
+
+	"AreYouOK/internal/service"
+	"AreYouOK/pkg/logger"
+	"AreYouOK/pkg/sms"
+)
+
+// ReceiveInboundSMS 短信上行回调，联系人回复 TD / 退订 等关键词后加入免打扰名单
+// POST /v1/webhooks/sms/inbound?token=xxx
+// 响应格式按阿里云要求：code 为 0 表示接收成功，否则平台会重试推送
+func ReceiveInboundSMS(ctx context.Context, c *app.RequestContext) {
+	messages, err := sms.ParseInbound(c.Request.Body())
+	if err != nil {
+		logger.Logger.Warn("Failed to parse inbound SMS", zap.Error(err))
+		// 格式错误重试也无法成功，直接确认接收
+		c.JSON(http.StatusOK, map[string]interface{}{"code": 0, "msg": "成功"})
+		return
+	}
+
+	registered, err := service.OptOut().HandleInbound(ctx, messages)
+	if err != nil {
+		logger.Logger.Error("Failed to handle inbound SMS", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, map[string]interface{}{"code": 1, "msg": "处理失败"})
+		return
+	}
+
+	logger.Logger.Info("Inbound SMS processed",
+		zap.Int("messages", len(messages)),
+		zap.Int("opted_out", registered),
+	)
+	c.JSON(http.StatusOK, map[string]interface{}{"code": 0, "msg": "成功"})
+}
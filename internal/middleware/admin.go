@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/cloudwego/hertz/pkg/app"
+
+	"AreYouOK/config"
+	"AreYouOK/pkg/errors"
+)
+
+// AdminAuthMiddleware 校验运维接口的 Bearer token（ADMIN_API_TOKEN）
+// 未配置 token 时运维接口整体关闭，返回 404
+func AdminAuthMiddleware() app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		expected := config.Cfg.AdminAPIToken
+		if expected == "" {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+
+		provided := strings.TrimPrefix(string(c.GetHeader("Authorization")), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(expected)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, map[string]interface{}{
+				"error": map[string]interface{}{
+					"code":    errors.Unauthorized.Code,
+					"message": errors.Unauthorized.Message,
+				},
+			})
+			return
+		}
+
+		c.Next(ctx)
+	}
+}
@@ -3,16 +3,24 @@ package middleware
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/hertz-contrib/jwt"
+	"go.uber.org/zap"
 
+	"AreYouOK/internal/cache"
 	"AreYouOK/pkg/errors"
+	"AreYouOK/pkg/logger"
 	"AreYouOK/pkg/token"
 )
 
 const (
 	IdentityKey = token.IdentityKey
+
+	// tokenRevokedKey 标记当前 access token 已登出或所属会话已被撤销
+	tokenRevokedKey = "auth_token_revoked"
 )
 
 var (
@@ -52,11 +60,49 @@ func initAuthMiddleware() error {
 			}
 		},
 
+		// 校验 access token 是否已登出或所属会话已被撤销，并拒绝 refresh token 与紧急联系人会话 token
+		Authorizator: func(data interface{}, ctx context.Context, c *app.RequestContext) bool {
+			claims := jwt.ExtractClaims(ctx, c)
+			// 紧急联系人会话 token 使用同一密钥签名，不能访问用户接口
+			if token.IsContactToken(claims) {
+				return false
+			}
+			// refresh token 有效期远长于会话撤销标记，不能当作 access token 使用
+			if token.IsRefreshToken(claims) {
+				return false
+			}
+
+			jti, _ := claims["jti"].(string)
+			sid, _ := claims["sid"].(string)
+			if jti == "" && sid == "" {
+				return true
+			}
+
+			revoked, err := cache.IsAccessTokenRevoked(ctx, jti, sid)
+			if err != nil {
+				// Redis 不可用时放行，避免所有请求失败
+				logger.Logger.Error("Failed to check access token revocation",
+					zap.String("session_id", sid),
+					zap.Error(err),
+				)
+				return true
+			}
+			if revoked {
+				c.Set(tokenRevokedKey, true)
+				return false
+			}
+			return true
+		},
+
 		HTTPStatusMessageFunc: func(err error, ctx context.Context, c *app.RequestContext) string {
 			return errors.Unauthorized.Message
 		},
 
 		Unauthorized: func(ctx context.Context, c *app.RequestContext, code int, message string) {
+			if _, revoked := c.Get(tokenRevokedKey); revoked {
+				code = http.StatusUnauthorized
+				message = errors.SessionRevoked.Message
+			}
 			c.JSON(code, map[string]interface{}{
 				"error": map[string]interface{}{
 					"code":    errors.Unauthorized.Code,
@@ -79,11 +125,11 @@ func initAuthMiddleware() error {
 func AuthMiddleware() app.HandlerFunc {
 	if authMiddleware == nil {
 		panic("AuthMiddleware not initialized, call Init() first")
-	}	
+	}
 
 	//c.Set("JWT_PAYLOAD", claims), MiddlewareFunc() 中解析得到的，其实可以直接获取 userID
 	//identity := mw.IdentityHandler(ctx, c)
-	
+
 	return authMiddleware.MiddlewareFunc()
 }
 
@@ -101,3 +147,19 @@ func GetUserID(ctx context.Context, c *app.RequestContext) (string, bool) {
 
 	return id, true
 }
+
+// GetTokenClaims 从请求上下文中获取当前 access token 的会话 ID、jti 与过期时间
+func GetTokenClaims(ctx context.Context, c *app.RequestContext) (sessionID string, jti string, expiresAt time.Time, ok bool) {
+	if _, exists := c.Get("JWT_PAYLOAD"); !exists {
+		return "", "", time.Time{}, false
+	}
+
+	claims := jwt.ExtractClaims(ctx, c)
+	sessionID, _ = claims["sid"].(string)
+	jti, _ = claims["jti"].(string)
+	if exp, ok := claims["exp"].(float64); ok {
+		expiresAt = time.Unix(int64(exp), 0)
+	}
+
+	return sessionID, jti, expiresAt, true
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/cloudwego/hertz/pkg/app"
+
+	"AreYouOK/pkg/errors"
+	"AreYouOK/pkg/token"
+)
+
+// contactClaimsKey 紧急联系人会话声明在请求上下文中的 key
+const contactClaimsKey = "contact_claims"
+
+// ContactAuthMiddleware 校验紧急联系人会话 token（aud 为 contact），用户 token 不能访问联系人接口
+func ContactAuthMiddleware() app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		provided := strings.TrimPrefix(string(c.GetHeader("Authorization")), "Bearer ")
+		if provided == "" {
+			abortContactUnauthorized(c)
+			return
+		}
+
+		claims, err := token.ValidateContactToken(provided)
+		if err != nil {
+			abortContactUnauthorized(c)
+			return
+		}
+
+		c.Set(contactClaimsKey, claims)
+		c.Next(ctx)
+	}
+}
+
+// GetContactPhoneCipher 从请求上下文中获取当前联系人会话的手机号密文
+func GetContactPhoneCipher(ctx context.Context, c *app.RequestContext) (string, bool) {
+	value, exists := c.Get(contactClaimsKey)
+	if !exists {
+		return "", false
+	}
+
+	claims, ok := value.(*token.ContactClaims)
+	if !ok {
+		return "", false
+	}
+
+	return claims.PhoneCipher, true
+}
+
+func abortContactUnauthorized(c *app.RequestContext) {
+	c.AbortWithStatusJSON(http.StatusUnauthorized, map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    errors.Unauthorized.Code,
+			"message": errors.Unauthorized.Message,
+		},
+	})
+}
@@ -67,6 +67,17 @@ var JourneySettingRateLimitConfig = RateLimitConfig{
 	ErrorMessage:  "设置修改过于频繁，请稍后再试",
 }
 
+// RedeemRateLimitConfig 兑换码兑换限流配置，防止暴力枚举兑换码
+var RedeemRateLimitConfig = RateLimitConfig{
+	Window:        600, // 10 min
+	MaxRequests:   5,   // 5次尝试
+	KeyPrefix:     "quota:redeem:rate",
+	ByUserID:      true,
+	ByIP:          true,
+	BlockDuration: 3600, // 阻塞1小时
+	ErrorMessage:  "兑换尝试过于频繁，请稍后再试",
+}
+
 // RateLimiter 限流器
 type RateLimiter struct {
 	config RateLimitConfig
@@ -204,6 +215,11 @@ func JourneySettingsRateLimitMiddleware() app.HandlerFunc {
 	return RateLimitMiddleware(JourneySettingRateLimitConfig)
 }
 
+// RedeemRateLimitMiddleware 兑换码兑换限流中间件
+func RedeemRateLimitMiddleware() app.HandlerFunc {
+	return RateLimitMiddleware(RedeemRateLimitConfig)
+}
+
 // CaptchaRateLimitMiddleware 验证码限流中间件
 func CaptchaRateLimitMiddleware() app.HandlerFunc {
 	config := RateLimitConfig{
@@ -232,3 +248,45 @@ func AuthRateLimitMiddleware() app.HandlerFunc {
 	return RateLimitMiddleware(config)
 }
 
+
+// ContactConsentRateLimitMiddleware 联系人确认链接限流（无需登录，按 IP 限流防止枚举 token）
+func ContactConsentRateLimitMiddleware() app.HandlerFunc {
+	config := RateLimitConfig{
+		Window:        60,    // 60秒
+		MaxRequests:   20,    // 20次请求
+		KeyPrefix:     "consent:rate",
+		ByUserID:      false, // 按 IP 限流
+		ByIP:          true,
+		BlockDuration: 900,   // 阻塞15分钟
+		ErrorMessage:  "请求过于频繁，请稍后再试",
+	}
+	return RateLimitMiddleware(config)
+}
+
+// ContactOptOutRateLimitMiddleware 联系人退订链接限流（无需登录，按 IP 限流防止枚举签名）
+func ContactOptOutRateLimitMiddleware() app.HandlerFunc {
+	config := RateLimitConfig{
+		Window:        60,    // 60秒
+		MaxRequests:   10,    // 10次请求
+		KeyPrefix:     "optout:rate",
+		ByUserID:      false, // 按 IP 限流
+		ByIP:          true,
+		BlockDuration: 900,   // 阻塞15分钟
+		ErrorMessage:  "请求过于频繁，请稍后再试",
+	}
+	return RateLimitMiddleware(config)
+}
+
+// JourneyShareRateLimitMiddleware 行程分享页限流（无需登录，按 IP 限流防止枚举 token）
+func JourneyShareRateLimitMiddleware() app.HandlerFunc {
+	config := RateLimitConfig{
+		Window:        60,    // 60秒
+		MaxRequests:   30,    // 30次请求，分享页会定时刷新位置
+		KeyPrefix:     "journey:share:rate",
+		ByUserID:      false, // 按 IP 限流
+		ByIP:          true,
+		BlockDuration: 900,   // 阻塞15分钟
+		ErrorMessage:  "请求过于频繁，请稍后再试",
+	}
+	return RateLimitMiddleware(config)
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+
+	"AreYouOK/pkg/reqmeta"
+)
+
+// RequestMetaMiddleware 把请求来源写入 context，服务层写审计日志时读取
+// 需注册在 OpenTelemetryMiddleware 之后，保证 context 中带有当前 span
+func RequestMetaMiddleware() app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		ctx = reqmeta.With(ctx, reqmeta.Meta{
+			IP:        c.ClientIP(),
+			UserAgent: toValidUTF8(string(c.UserAgent())),
+			RequestID: toValidUTF8(string(c.GetHeader("X-Request-Id"))),
+		})
+		c.Next(ctx)
+	}
+}
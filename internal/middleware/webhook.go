@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/cloudwego/hertz/pkg/app"
+
+	"AreYouOK/config"
+	"AreYouOK/pkg/errors"
+)
+
+// SMSInboundAuthMiddleware 校验短信上行回调地址中的 token（SMS_INBOUND_TOKEN）
+// 短信平台的 HTTP 推送不带签名，回调地址配置为 /v1/webhooks/sms/inbound?token=xxx；未配置 token 时回调关闭，返回 404
+func SMSInboundAuthMiddleware() app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		expected := config.Cfg.SMSInboundToken
+		if expected == "" {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+
+		provided := c.Query("token")
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(expected)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, map[string]interface{}{
+				"error": map[string]interface{}{
+					"code":    errors.Unauthorized.Code,
+					"message": errors.Unauthorized.Message,
+				},
+			})
+			return
+		}
+
+		c.Next(ctx)
+	}
+}
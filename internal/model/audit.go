@@ -0,0 +1,65 @@
+package model
+
+import "time"
+
+// AuditActorType 审计事件的操作者类型
+type AuditActorType string
+
+const (
+	AuditActorUser    AuditActorType = "user"    // 用户本人
+	AuditActorSystem  AuditActorType = "system"  // 定时任务等后台流程
+	AuditActorContact AuditActorType = "contact" // 紧急联系人通过确认链接操作
+)
+
+// AuditAction 审计事件类型
+const (
+	AuditActionLogin           = "auth.login"             // 登录（新建会话）
+	AuditActionTokenRefresh    = "auth.token_refresh"     // 轮换 refresh token
+	AuditActionLogout          = "auth.logout"            // 登出当前设备
+	AuditActionSessionRevoke   = "session.revoke"         // 会话被撤销（移除设备、更换手机号、注销等）
+	AuditActionContactCreate   = "contact.create"         // 添加紧急联系人
+	AuditActionContactUpdate   = "contact.update"         // 修改紧急联系人
+	AuditActionContactDelete   = "contact.delete"         // 删除紧急联系人
+	AuditActionContactReplace  = "contact.replace"        // 全量替换紧急联系人
+	AuditActionContactExport   = "contact.export"         // 导出紧急联系人 vCard
+	AuditActionContactResend   = "contact.consent_resend" // 重新发送联系人确认短信
+	AuditActionContactConsent  = "contact.consent"        // 联系人确认或拒绝
+	AuditActionContactOptOut   = "contact.opt_out"        // 联系人号码退订，停止向该号码发送短信
+	AuditActionContactLeave    = "contact.leave"          // 联系人在联系人门户中退出用户的联系人列表
+	AuditActionSettingsUpdate  = "settings.update"        // 修改用户设置
+	AuditActionPhoneChange     = "account.phone_change"   // 更换绑定手机号
+	AuditActionStepUp          = "account.step_up"        // 验证本人手机号（二次验证）
+	AuditActionEraseRequest    = "account.erase_request"  // 申请注销
+	AuditActionRestore         = "account.restore"        // 恢复窗口内恢复账号
+	AuditActionPurge           = "account.purge"          // 恢复窗口结束后清除个人数据
+	AuditActionDataExport      = "account.data_export"    // 申请导出个人数据
+	AuditActionIdentityLink    = "identity.link"          // 绑定登录身份
+	AuditActionIdentityUnlink  = "identity.unlink"        // 解绑登录身份
+	AuditActionAccountMerge    = "account.merge"          // 其他账号合并到本账号
+	AuditActionAccountMergedTo = "account.merged_into"    // 本账号被合并到其他账号
+	AuditActionJourneyShare    = "journey.share"          // 生成行程公开分享链接
+	AuditActionJourneyUnshare  = "journey.unshare"        // 撤销行程公开分享链接
+)
+
+// AuditEvent 账号敏感操作的审计事件，只追加不修改（数据库触发器拒绝 UPDATE / DELETE）
+// 与所描述的变更在同一个事务中写入；diff 经过脱敏，不包含手机号、联系人姓名等个人信息
+type AuditEvent struct {
+	OccurredAt time.Time      `gorm:"type:timestamptz;not null;default:now()" json:"occurred_at"`
+	Action     string         `gorm:"type:varchar(48);not null;index:idx_audit_events_action" json:"action"`
+	ActorType  AuditActorType `gorm:"type:varchar(16);not null;default:'user'" json:"actor_type"`
+	TargetType string         `gorm:"type:varchar(32);not null;default:''" json:"target_type"` // 被操作对象类型，例如 session、contact、identity
+	TargetID   string         `gorm:"type:varchar(64);not null;default:''" json:"target_id"`
+	IP         string         `gorm:"type:varchar(64);not null;default:''" json:"ip"`
+	Device     string         `gorm:"type:varchar(255);not null;default:''" json:"device"` // 登录时为客户端上报的设备信息，其余为 User-Agent
+	TraceID    string         `gorm:"type:varchar(32);not null;default:''" json:"trace_id"`
+	Diff       JSONB          `gorm:"type:jsonb;not null;default:'{}'" json:"diff"`
+	ID         int64          `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID     int64          `gorm:"not null;index:idx_audit_events_user" json:"user_id"` // 事件所属账号
+	ActorID    int64          `gorm:"not null;default:0" json:"actor_id"`                  // 用户本人操作时等于 user_id，系统操作为 0
+	SessionID  int64          `gorm:"not null;default:0" json:"session_id"`
+}
+
+// TableName 指定表名
+func (AuditEvent) TableName() string {
+	return "audit_events"
+}
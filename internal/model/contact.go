@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+// ContactStatus 联系人同意状态：联系人确认后才会收到告警通知
+type ContactStatus string
+
+const (
+	ContactStatusPending   ContactStatus = "pending"   // 已发送确认短信，等待联系人回应
+	ContactStatusConfirmed ContactStatus = "confirmed" // 联系人同意成为紧急联系人
+	ContactStatusDeclined  ContactStatus = "declined"  // 联系人拒绝
+	ContactStatusExpired   ContactStatus = "expired"   // 确认链接过期未回应
+	ContactStatusOptedOut  ContactStatus = "opted_out" // 号码已退订（见 ContactOptOut），不再发送任何短信
+)
+
+// Contact 紧急联系人（emergency_contacts 表），取代 users.emergency_contacts JSONB 数组
+// 删除与全量替换均为软删除，已删除的记录保留作为历史；同一用户未删除的联系人 priority 唯一
+// 修改时以 version 做乐观锁，并发修改只有一个请求成功
+// 新增或更换号码后需联系人通过短信链接确认，只有 confirmed 的联系人会收到告警
+type Contact struct {
+	ConsentSentAt    *time.Time    `gorm:"type:timestamptz" json:"consent_sent_at,omitempty"`
+	ConsentExpiresAt *time.Time    `gorm:"type:timestamptz;index:idx_emergency_contacts_consent_expires" json:"consent_expires_at,omitempty"`
+	RespondedAt      *time.Time    `gorm:"type:timestamptz" json:"responded_at,omitempty"`
+	ConsentTokenHash *string       `gorm:"type:char(64);uniqueIndex:emergency_contacts_consent_token_key,where:deleted_at IS NULL" json:"-"` // 确认链接 token 的哈希
+	Status           ContactStatus `gorm:"type:varchar(16);not null;default:'pending'" json:"status"`
+	DisplayName      string        `gorm:"type:varchar(64);not null" json:"display_name"`
+	Relationship     string        `gorm:"type:varchar(32);not null;default:''" json:"relationship"`
+	PhoneHash        string        `gorm:"type:char(64);not null;index:idx_emergency_contacts_phone_hash" json:"-"`
+	PhoneCipher      []byte        `gorm:"type:bytea;not null" json:"-"`
+	BaseModel
+	ContactID        int64 `gorm:"uniqueIndex:emergency_contacts_contact_id_key;not null" json:"contact_id"` // snowflake，对外暴露的联系人 ID
+	UserID           int64 `gorm:"not null;uniqueIndex:idx_emergency_contacts_user_priority,priority:1,where:deleted_at IS NULL" json:"user_id"`
+	Priority         int   `gorm:"not null;uniqueIndex:idx_emergency_contacts_user_priority,priority:2,where:deleted_at IS NULL" json:"priority"`
+	PhoneHashVersion int   `gorm:"type:smallint;not null;default:0" json:"-"` // phone_hash 的哈希版本，轮换密钥后由 rehash 任务更新
+	Version          int   `gorm:"not null;default:1" json:"version"`
+	ConsentSends     int   `gorm:"not null;default:0" json:"consent_sends"` // 当前号码已发送确认短信的次数
+}
+
+// TableName 指定表名
+func (Contact) TableName() string {
+	return "emergency_contacts"
+}
@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// ContactOptOutSource 退订来源
+type ContactOptOutSource string
+
+const (
+	ContactOptOutSourceSMSReply ContactOptOutSource = "sms_reply" // 回复 TD / 退订 等关键词
+	ContactOptOutSourceLink     ContactOptOutSource = "link"      // 点击告警短信中的退订链接
+)
+
+// ContactOptOut 全局免打扰名单（contact_opt_outs 表），按手机号哈希记录退订的号码
+// 名单中的号码不会再收到告警或确认短信，也不能再被任何用户添加为紧急联系人
+// 保存手机号密文，哈希版本轮换或密钥轮换时由 rehash / reencrypt 任务重算
+type ContactOptOut struct {
+	OptedOutAt  time.Time           `gorm:"type:timestamptz;not null" json:"opted_out_at"`
+	PhoneHash   string              `gorm:"type:char(64);not null;uniqueIndex:contact_opt_outs_phone_hash_key,where:deleted_at IS NULL" json:"-"`
+	PhoneCipher []byte              `gorm:"type:bytea;not null" json:"-"`
+	Source      ContactOptOutSource `gorm:"type:varchar(16);not null" json:"source"`
+	Keyword     string              `gorm:"type:varchar(32);not null;default:''" json:"keyword"` // 短信退订时回复的内容
+	BaseModel
+	PhoneHashVersion int `gorm:"type:smallint;not null;default:0" json:"-"`
+}
+
+// TableName 指定表名
+func (ContactOptOut) TableName() string {
+	return "contact_opt_outs"
+}
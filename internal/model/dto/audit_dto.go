@@ -0,0 +1,41 @@
+package dto
+
+import "time"
+
+// ActivityQuery 账号活动记录查询参数
+type ActivityQuery struct {
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit"`
+}
+
+// ActivityItem 账号活动记录
+type ActivityItem struct {
+	OccurredAt time.Time              `json:"occurred_at"`
+	Diff       map[string]interface{} `json:"diff,omitempty"`
+	ID         string                 `json:"id"`
+	Action     string                 `json:"action"`
+	TargetType string                 `json:"target_type,omitempty"`
+	TargetID   string                 `json:"target_id,omitempty"`
+	IP         string                 `json:"ip"`
+	Device     string                 `json:"device"`
+}
+
+// AdminAuditQuery 运维查询审计事件的参数，since / until 为 RFC3339 时间
+type AdminAuditQuery struct {
+	UserID  string `form:"user_id"` // 用户 public_id
+	Action  string `form:"action"`
+	TraceID string `form:"trace_id"`
+	Since   string `form:"since"`
+	Until   string `form:"until"`
+	Cursor  string `form:"cursor"`
+	Limit   int    `form:"limit"`
+}
+
+// AdminAuditEvent 运维查询返回的审计事件
+type AdminAuditEvent struct {
+	ActivityItem
+	UserID    string `json:"user_id"` // 用户 public_id
+	ActorType string `json:"actor_type"`
+	SessionID string `json:"session_id,omitempty"`
+	TraceID   string `json:"trace_id,omitempty"`
+}
@@ -17,6 +17,15 @@ type AuthExchangeRequest struct {
 	Device DeviceInfo `json:"device" binding:"required"`
 }
 
+// WechatExchangeRequest 微信小程序登录请求
+// code 来自 wx.login，encrypted_data / iv 来自 getPhoneNumber 按钮回调
+type WechatExchangeRequest struct {
+	Code          string     `json:"code" binding:"required"`
+	EncryptedData string     `json:"encrypted_data" binding:"required"`
+	IV            string     `json:"iv" binding:"required"`
+	Device        DeviceInfo `json:"device" binding:"required"`
+}
+
 // DeviceInfo 设备信息
 type DeviceInfo struct {
 	Platform   string `json:"platform" binding:"required"`
@@ -41,6 +50,8 @@ type AuthUserSnapshot struct {
 	NextStep      string `json:"next_step"`
 	IsNewUser     bool   `json:"is_new_user"`
 	AlipayOpenID  string `json:"alipay_open_id,omitempty"` // 便于前端后续调用携带
+	// 账号已注销且仍在恢复窗口内时返回，next_step 为 restore
+	PendingErasureUntil *time.Time `json:"pending_erasure_until,omitempty"`
 	// Waitlist      WaitlistInfo `json:"waitlist"` //不再需要，直接返回
 }
 
@@ -73,10 +84,11 @@ type VerifySliderResponse struct {
 
 // VerifyCaptchaRequest 验证码验证请求
 type VerifyCaptchaRequest struct {
-	AlipayOpenID       string `json:"alipay_open_id,omitempty"`
-	Phone      string `json:"phone" binding:"required"`
-	VerifyCode string `json:"verify_code" binding:"required"`
-	AuthCode   string `json:"auth_code,omitempty"` // 未登录场景必填，用于换取 open_id
+	AlipayOpenID string      `json:"alipay_open_id,omitempty"`
+	Phone        string      `json:"phone" binding:"required"`
+	VerifyCode   string      `json:"verify_code" binding:"required"`
+	AuthCode     string      `json:"auth_code,omitempty"` // 未登录场景必填，用于换取 open_id
+	Device       *DeviceInfo `json:"device,omitempty"`    // 可选，用于登录会话展示
 }
 
 // VerifyCaptchaResponse 验证码验证响应
@@ -100,3 +112,48 @@ type WaitlistStatusResponse struct {
 	Priority    int        `json:"priority"`
 	Position    int        `json:"position,omitempty"`
 }
+
+// SessionItem 登录会话
+type SessionItem struct {
+	LastSeenAt time.Time `json:"last_seen_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	ID         string    `json:"id"`
+	Platform   string    `json:"platform"`
+	Model      string    `json:"model"`
+	AppVersion string    `json:"app_version"`
+	IP         string    `json:"ip"`
+	Current    bool      `json:"current"` // 是否为发起请求的会话
+}
+
+// IdentityItem 账号绑定的登录身份
+type IdentityItem struct {
+	LinkedAt time.Time `json:"linked_at"`
+	Provider string    `json:"provider"`
+}
+
+// LinkIdentityRequest 绑定登录身份请求
+// code 为对应平台的授权码（支付宝 auth_code / 微信 wx.login code），由服务端换取身份，不接受客户端直接传入 open_id
+type LinkIdentityRequest struct {
+	Provider string `json:"provider" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// MergeAccountRequest 合并账号请求，当前登录账号为保留账号
+// provider / code 证明被合并账号的归属；phone 为任一账号绑定的手机号，verify_code 为该号码的短信验证码
+type MergeAccountRequest struct {
+	Provider   string `json:"provider" binding:"required"`
+	Code       string `json:"code" binding:"required"`
+	Phone      string `json:"phone" binding:"required"`
+	VerifyCode string `json:"verify_code" binding:"required"`
+}
+
+// AccountMergeData 合并结果
+type AccountMergeData struct {
+	MergedUserID string   `json:"merged_user_id"` // 被合并账号的 public_id
+	Identities   []string `json:"identities"`     // 合并后当前账号绑定的平台
+	Contacts     int      `json:"contacts"`       // 转入的紧急联系人数量
+	Journeys     int      `json:"journeys"`
+	CheckIns     int      `json:"check_ins"`
+	Quota        int      `json:"quota"` // 转入的个人钱包余额（cents）
+}
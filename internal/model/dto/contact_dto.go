@@ -3,15 +3,20 @@ package dto
 import "time"
 
 // ========== Contact 相关 DTO ==========
-// 存储在 user 中的部分，得先确定是哪个用户，根据索引
+// 存储在 emergency_contacts 表中，接口仍按 priority 定位联系人
 
 // ContactItem 紧急联系人项
 type ContactItem struct {
-	CreatedAt    time.Time `json:"created_at"`
-	DisplayName  string    `json:"display_name"`
-	Relationship string    `json:"relationship"`
-	PhoneMasked  string    `json:"phone_masked"`
-	Priority     int       `json:"priority"`
+	CreatedAt        time.Time  `json:"created_at"`
+	ConsentExpiresAt *time.Time `json:"consent_expires_at,omitempty"` // 等待确认时，确认链接的过期时间
+	ID               string     `json:"id"`
+	DisplayName      string     `json:"display_name"`
+	Relationship     string     `json:"relationship"`
+	PhoneMasked      string     `json:"phone_masked"`
+	Status           string     `json:"status"` // pending / confirmed / declined / expired / opted_out，只有 confirmed 的联系人会收到告警
+	Priority         int        `json:"priority"`
+	Version          int        `json:"version"`
+	ResendsLeft      int        `json:"resends_left"` // 还可以重新发送确认短信的次数
 }
 
 // CreateContactRequest 创建联系人请求
@@ -27,22 +32,29 @@ type UpdateContactRequest struct {
 	Relationship string `json:"relationship,omitempty"`
 	Phone        string `json:"phone,omitempty"`
 	Priority     int    `json:"priority,omitempty"`
+	Version      int    `json:"version,omitempty"` // 可选，客户端读取到的版本号，不一致时返回 CONTACT_VERSION_CONFLICT
 }
 
-// CreateContactResponse 创建联系人响应
+// CreateContactResponse 创建联系人响应，新联系人需确认后才会收到告警
 type CreateContactResponse struct {
+	ID           string `json:"id"`
 	DisplayName  string `json:"display_name"`
 	Relationship string `json:"relationship"`
 	PhoneMasked  string `json:"phone_masked"`
+	Status       string `json:"status"`
 	Priority     int    `json:"priority"`
+	Version      int    `json:"version"`
 }
 
 
 type UpdateContactResponse struct {
+	ID           string `json:"id"`
 	DisplayName  string `json:"display_name"`
 	Relationship string `json:"relationship"`
 	PhoneMasked  string `json:"phone_masked"`
+	Status       string `json:"status"` // 更换号码后重新进入 pending
 	Priority     int    `json:"priority"`
+	Version      int    `json:"version"`
 }
 
 // ReplaceContactItem 批量替换联系人时的单个联系人项
@@ -51,10 +63,91 @@ type ReplaceContactItem struct {
 	DisplayName  string `json:"display_name" binding:"required"`
 	Relationship string `json:"relationship" binding:"required"`
 	Phone        string `json:"phone" binding:"required"`
-	Priority     int    `json:"priority" binding:"required,min=1,max=3"`
+	Priority     int    `json:"priority" binding:"required,min=1"` // 上限由用户套餐决定
 }
 
 // ReplaceContactsRequest 批量替换联系人请求
 type ReplaceContactsRequest struct {
-	Contacts []ReplaceContactItem `json:"contacts" binding:"required,min=1,max=3,dive"`
-}
\ No newline at end of file
+	Contacts []ReplaceContactItem `json:"contacts" binding:"required,min=1,dive"` // 数量上限由用户套餐决定
+}
+
+// ContactConsentData 联系人打开确认链接时看到的邀请信息
+type ContactConsentData struct {
+	ExpiresAt    time.Time `json:"expires_at"`
+	Requester    string    `json:"requester"` // 发起邀请的用户昵称
+	DisplayName  string    `json:"display_name"`
+	Relationship string    `json:"relationship"`
+	Status       string    `json:"status"`
+}
+
+// ContactOptOutRequest 联系人通过告警短信中的退订链接退订，参数取自链接
+type ContactOptOutRequest struct {
+	ContactID string `json:"contact_id" binding:"required"` // 链接中的 c 参数
+	Signature string `json:"sig" binding:"required"`
+}
+
+// ContactOptOutResponse 联系人通过退订链接退订后的响应
+type ContactOptOutResponse struct {
+	PhoneMasked string `json:"phone_masked"` // 已退订的号码（脱敏）
+}
+
+// ========== 联系人门户 DTO ==========
+// 被设为紧急联系人的人通过手机验证码登录，查看并管理把自己设为联系人的用户
+
+// ContactPortalVerifyRequest 联系人验证手机号
+type ContactPortalVerifyRequest struct {
+	Phone      string `json:"phone" binding:"required"`
+	VerifyCode string `json:"verify_code" binding:"required"`
+}
+
+// ContactPortalSession 联系人会话，token 只能访问联系人门户接口
+type ContactPortalSession struct {
+	AccessToken string `json:"access_token"`
+	PhoneMasked string `json:"phone_masked"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// ContactRequesterItem 把当前号码设为紧急联系人的用户
+type ContactRequesterItem struct {
+	CreatedAt        time.Time  `json:"created_at"`
+	ConsentExpiresAt *time.Time `json:"consent_expires_at,omitempty"`
+	RespondedAt      *time.Time `json:"responded_at,omitempty"`
+	ContactID        string     `json:"contact_id"`
+	Requester        string     `json:"requester"`    // 用户昵称
+	DisplayName      string     `json:"display_name"` // 用户对联系人的称呼
+	Relationship     string     `json:"relationship"`
+	Status           string     `json:"status"`
+	Priority         int        `json:"priority"`
+}
+
+// ContactAlertItem 联系人收到的告警短信
+type ContactAlertItem struct {
+	SentAt    time.Time `json:"sent_at"`
+	ID        string    `json:"id"`
+	Requester string    `json:"requester"`
+	Category  string    `json:"category"` // check_in_timeout / journey_timeout
+}
+
+// ========== 联系人 vCard 导入 ==========
+
+// ContactImportRequest 导入 vCard（3.0 / 4.0），apply 为 false 时只返回建议的优先级映射
+type ContactImportRequest struct {
+	VCard string `json:"vcard" binding:"required"`
+	Apply bool   `json:"apply"` // true 时按建议映射全量替换现有联系人
+}
+
+// ContactImportSkipped 未导入的名片
+type ContactImportSkipped struct {
+	Name        string `json:"name"`
+	PhoneMasked string `json:"phone_masked,omitempty"`
+	Reason      string `json:"reason"` // no_mobile / duplicate / self / opted_out / limit
+}
+
+// ContactImportResponse 导入结果
+// proposed 可以调整后直接作为 PUT /v1/contacts 的 contacts 提交
+type ContactImportResponse struct {
+	Proposed []ReplaceContactItem   `json:"proposed"`
+	Skipped  []ContactImportSkipped `json:"skipped"`
+	Contacts []ContactItem          `json:"contacts,omitempty"` // apply 为 true 时返回替换后的联系人
+	Applied  bool                   `json:"applied"`
+}
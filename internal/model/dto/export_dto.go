@@ -0,0 +1,24 @@
+package dto
+
+import "time"
+
+// ========== 个人数据导出相关 DTO ==========
+
+// CreateDataExportRequest 发起个人数据导出请求
+// 默认导出脱敏后的联系人手机号，需要完整手机号时当前会话必须已完成二次验证
+type CreateDataExportRequest struct {
+	FullContacts bool `json:"full_contacts"`
+}
+
+// DataExportResponse 个人数据导出任务
+type DataExportResponse struct {
+	ID           string     `json:"id"`
+	Status       string     `json:"status"`
+	FullContacts bool       `json:"full_contacts"`
+	RequestedAt  time.Time  `json:"requested_at"`
+	ReadyAt      *time.Time `json:"ready_at,omitempty"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"` // 归档过期时间，过期未下载自动删除
+	SizeBytes    int        `json:"size_bytes,omitempty"`
+	DownloadURL  string     `json:"download_url,omitempty"` // 仅 ready 状态返回，短时有效且只能使用一次
+	URLExpiresAt *time.Time `json:"url_expires_at,omitempty"`
+}
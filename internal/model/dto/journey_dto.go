@@ -17,9 +17,48 @@ type JourneyItem struct {
 
 // CreateJourneyRequest 创建行程请求
 type CreateJourneyRequest struct {
-	Title              string    `json:"title" binding:"required"`
-	ExpectedReturnTime time.Time `json:"expected_return_time" binding:"required"`
-	Note               string    `json:"note"`
+	Title              string                   `json:"title" binding:"required"`
+	ExpectedReturnTime time.Time                `json:"expected_return_time" binding:"required"`
+	Note               string                   `json:"note"`
+	Checkpoints        []JourneyCheckpointInput `json:"checkpoints,omitempty"`        // 按预计到达时间排列的检查点
+	Destination        *JourneyDestinationInput `json:"destination,omitempty"`        // 目的地围栏，进入后自动结束行程
+	ContactPriorities  []int                    `json:"contact_priorities,omitempty"` // 超时通知的联系人优先级，为空表示全部
+	AlertMessage       string                   `json:"alert_message"`                // 发给联系人的留言，代替备注
+}
+
+// GeofenceDTO 地理围栏：中心坐标与半径（米）
+type GeofenceDTO struct {
+	Latitude  *float64 `json:"lat"`
+	Longitude *float64 `json:"lon"`
+	Radius    int      `json:"radius"`
+}
+
+// JourneyDestinationInput 行程目的地围栏，use_home 为 true 时使用用户设置中的家
+type JourneyDestinationInput struct {
+	GeofenceDTO
+	UseHome bool `json:"use_home"`
+}
+
+// JourneyCheckpointInput 创建行程时的检查点
+type JourneyCheckpointInput struct {
+	ExpectedAt time.Time `json:"expected_at" binding:"required"`
+	Latitude   *float64  `json:"latitude,omitempty"`
+	Longitude  *float64  `json:"longitude,omitempty"`
+	Label      string    `json:"label" binding:"required"`
+	Location   string    `json:"location"`
+}
+
+// JourneyCheckpointItem 行程检查点
+type JourneyCheckpointItem struct {
+	ExpectedAt       time.Time  `json:"expected_at"`
+	CheckedInAt      *time.Time `json:"checked_in_at,omitempty"`
+	AlertTriggeredAt *time.Time `json:"alert_triggered_at,omitempty"`
+	Latitude         *float64   `json:"latitude,omitempty"`
+	Longitude        *float64   `json:"longitude,omitempty"`
+	Label            string     `json:"label"`
+	Location         string     `json:"location"`
+	Status           string     `json:"status"`
+	Seq              int        `json:"seq"`
 }
 
 // UpdateJourneyRequest 更新行程请求
@@ -27,6 +66,11 @@ type UpdateJourneyRequest struct {
 	Title              *string    `json:"title" binding:"required"`
 	ExpectedReturnTime *time.Time `json:"expected_return_time" binding:"required"`
 	Note               *string    `json:"note"`
+
+	Destination *JourneyDestinationInput `json:"destination,omitempty"` // 修改目的地围栏，需要重新离开围栏后才会自动结束
+
+	ContactPriorities *[]int  `json:"contact_priorities,omitempty"` // 传空数组恢复为通知全部联系人
+	AlertMessage      *string `json:"alert_message,omitempty"`      // 传空字符串清除留言
 }
 
 // JourneyDetail 行程详情
@@ -37,6 +81,67 @@ type JourneyDetail struct {
 	AlertLastAttemptAt *time.Time `json:"alert_last_attempt_at,omitempty"`
 	AlertStatus        string     `json:"alert_status"`
 	AlertAttempts      int        `json:"alert_attempts"`
+	ExtensionCount     int        `json:"extension_count"`
+	ExtendedMinutes    int        `json:"extended_minutes"`
+
+	Checkpoints  []JourneyCheckpointItem `json:"checkpoints"`
+	LastLocation *JourneyLastLocation    `json:"last_location,omitempty"`
+	Destination  *GeofenceDTO            `json:"destination,omitempty"`
+	CompletedBy  string                  `json:"completed_by,omitempty"` // manual / geofence
+
+	ContactPriorities []int  `json:"contact_priorities"` // 为空表示全部联系人
+	AlertMessage      string `json:"alert_message"`
+}
+
+// JourneyLocationPoint 小程序上报的定位点
+type JourneyLocationPoint struct {
+	Timestamp time.Time `json:"timestamp" binding:"required"` // 设备定位时间
+	Latitude  *float64  `json:"lat" binding:"required"`
+	Longitude *float64  `json:"lon" binding:"required"`
+	Accuracy  float64   `json:"accuracy"` // 定位精度（米）
+}
+
+// ReportJourneyLocationsRequest 批量上报定位点，离线期间缓存的点可以一次上报
+type ReportJourneyLocationsRequest struct {
+	Points []JourneyLocationPoint `json:"points" binding:"required"`
+}
+
+// ReportJourneyLocationsResponse 上报结果，坐标或时间不合法的点被丢弃
+type ReportJourneyLocationsResponse struct {
+	LastLocation *JourneyLastLocation `json:"last_location,omitempty"`
+	Accepted     int                  `json:"accepted"`
+	Rejected     int                  `json:"rejected"`
+	Completed    bool                 `json:"completed"` // 进入目的地围栏，行程已自动结束
+}
+
+// JourneyLastLocation 行程最后已知位置
+type JourneyLastLocation struct {
+	RecordedAt time.Time `json:"recorded_at"`
+	MapLink    string    `json:"map_link"`
+	Latitude   float64   `json:"lat"`
+	Longitude  float64   `json:"lon"`
+	Accuracy   float64   `json:"accuracy"`
+}
+
+// ExtendJourneyRequest 延长行程（晚点了），在当前预计返回时间上顺延
+type ExtendJourneyRequest struct {
+	Minutes int `json:"minutes" binding:"required"`
+}
+
+// ExtendJourneyResponse 延长后的行程与剩余可延长额度
+type ExtendJourneyResponse struct {
+	JourneyItem
+	ExtensionCount  int `json:"extension_count"`
+	ExtendedMinutes int `json:"extended_minutes"`
+	ExtensionsLeft  int `json:"extensions_left"`
+	MinutesLeft     int `json:"minutes_left"`
+}
+
+// JourneyEventItem 行程时间线事件
+type JourneyEventItem struct {
+	OccurredAt time.Time              `json:"occurred_at"`
+	Data       map[string]interface{} `json:"data"`
+	Type       string                 `json:"type"`
 }
 
 // JourneyAlertData 行程提醒数据
@@ -52,3 +157,36 @@ type JourneyListQuery struct {
 	Cursor string `form:"cursor"`
 	Limit  int    `form:"limit"`
 }
+
+// CreateJourneyShareRequest 生成行程公开分享链接，重新生成后旧链接失效
+type CreateJourneyShareRequest struct {
+	Hours           int  `json:"hours"`            // 有效期（小时），不传时为 24 小时
+	IncludeLocation bool `json:"include_location"` // 分享页是否展示最后位置
+}
+
+// JourneyShareData 行程分享链接，token 只在生成时返回
+type JourneyShareData struct {
+	ExpiresAt       time.Time `json:"expires_at"`
+	Token           string    `json:"token"`
+	URL             string    `json:"url"`
+	IncludeLocation bool      `json:"include_location"`
+}
+
+// SharedJourneyData 分享页看到的行程，不包含备注、联系人与检查点坐标
+type SharedJourneyData struct {
+	ExpectedReturnTime time.Time                 `json:"expected_return_time"`
+	ActualReturnTime   *time.Time                `json:"actual_return_time,omitempty"`
+	LastLocation       *JourneyLastLocation      `json:"last_location,omitempty"`
+	Title              string                    `json:"title"`
+	Status             string                    `json:"status"`
+	Checkpoints        []SharedJourneyCheckpoint `json:"checkpoints"`
+}
+
+// SharedJourneyCheckpoint 分享页的检查点进度
+type SharedJourneyCheckpoint struct {
+	ExpectedAt  time.Time  `json:"expected_at"`
+	CheckedInAt *time.Time `json:"checked_in_at,omitempty"`
+	Label       string     `json:"label"`
+	Status      string     `json:"status"`
+	Seq         int        `json:"seq"`
+}
@@ -0,0 +1,51 @@
+package dto
+
+import "time"
+
+// JourneyTemplateRequest 创建或修改行程模板（修改为全量替换）
+type JourneyTemplateRequest struct {
+	Title             string                      `json:"title" binding:"required"`
+	Note              string                      `json:"note"`
+	RRule             string                      `json:"rrule"` // 重复规则，例如 FREQ=WEEKLY;BYDAY=MO,WE;BYHOUR=22;BYMINUTE=0，为空表示不重复
+	Checkpoints       []JourneyTemplateCheckpoint `json:"checkpoints,omitempty"`
+	ContactPriorities []int                       `json:"contact_priorities,omitempty"` // 超时通知的联系人，为空表示全部
+	AlertMessage      string                      `json:"alert_message"`                // 发给联系人的留言，代替备注
+	DurationMinutes   int                         `json:"duration_minutes" binding:"required"`
+	AutoStart         bool                        `json:"auto_start"` // 到点自动开始，不需要确认
+}
+
+// JourneyTemplateCheckpoint 模板检查点，时间为相对出发时间的分钟数
+type JourneyTemplateCheckpoint struct {
+	Latitude      *float64 `json:"latitude,omitempty"`
+	Longitude     *float64 `json:"longitude,omitempty"`
+	Label         string   `json:"label" binding:"required"`
+	Location      string   `json:"location"`
+	OffsetMinutes int      `json:"offset_minutes" binding:"required"`
+}
+
+// JourneyTemplateItem 行程模板
+type JourneyTemplateItem struct {
+	NextOccurrenceAt  *time.Time                  `json:"next_occurrence_at,omitempty"`
+	CreatedAt         time.Time                   `json:"created_at"`
+	ID                string                      `json:"id"`
+	Title             string                      `json:"title"`
+	Note              string                      `json:"note"`
+	RRule             string                      `json:"rrule"`
+	Checkpoints       []JourneyTemplateCheckpoint `json:"checkpoints"`
+	ContactPriorities []int                       `json:"contact_priorities"`
+	AlertMessage      string                      `json:"alert_message"`
+	DurationMinutes   int                         `json:"duration_minutes"`
+	AutoStart         bool                        `json:"auto_start"`
+}
+
+// JourneyOccurrenceItem 重复行程的单次安排
+type JourneyOccurrenceItem struct {
+	StartAt        time.Time  `json:"start_at"`
+	ReminderSentAt *time.Time `json:"reminder_sent_at,omitempty"`
+	ID             string     `json:"id"`
+	TemplateID     string     `json:"template_id"`
+	Title          string     `json:"title"`
+	Status         string     `json:"status"`
+	SkipReason     string     `json:"skip_reason,omitempty"`
+	JourneyID      string     `json:"journey_id,omitempty"`
+}
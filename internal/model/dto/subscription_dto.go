@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+// ========== Subscription 相关 DTO ==========
+
+// SubscriptionData 用户订阅信息
+type SubscriptionData struct {
+	NextGrantAt          *time.Time `json:"next_grant_at,omitempty"` // 下一次发放额度的时间（仅生效中的付费套餐）
+	Plan                 string     `json:"plan"`
+	PlanName             string     `json:"plan_name"`
+	Status               string     `json:"status"`
+	Channels             []string   `json:"channels"`
+	MonthlyGrant         int        `json:"monthly_grant"`
+	MaxContacts          int        `json:"max_contacts"`
+	MonthlyReminderLimit int        `json:"monthly_reminder_limit"`
+}
+
+// AdminSubscribeRequest 运维开通或变更套餐请求
+type AdminSubscribeRequest struct {
+	Plan string `json:"plan" binding:"required"` // free / premium / family
+}
@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 // ========== User 相关 DTO ==========
 
 // UserProfileData 用户资料数据
@@ -29,6 +31,8 @@ type UserSettingsDTO struct {
 	Timezone               string `json:"timezone"`
 	DailyCheckInEnabled    bool   `json:"daily_check_in_enabled"`
 	JourneyAutoNotify      bool   `json:"journey_auto_notify"`
+
+	Home *GeofenceDTO `json:"home,omitempty"` // 家的位置，行程可以以家为目的地
 }
 
 // UpdateUserSettingsRequest 更新用户设置请求
@@ -40,6 +44,9 @@ type UpdateUserSettingsRequest struct {
 	DailyCheckInGraceUntil *string `json:"daily_check_in_grace_until"`
 	JourneyAutoNotify      *bool   `json:"journey_auto_notify"`
 	Timezone               *string `json:"timezone"`
+
+	Home      *GeofenceDTO `json:"home"`       // 设置家的位置
+	ClearHome bool         `json:"clear_home"` // 清除家的位置
 }
 
 // UserStatusData 用户状态数据
@@ -47,6 +54,7 @@ type UserStatusData struct {
 	Status        string `json:"status"`
 	PhoneVerified bool   `json:"phone_verified"`
 	HasContacts   bool   `json:"has_contacts"`
+	LowBalance    bool   `json:"low_balance"` // 额度低于预警阈值
 	// Waitlist      WaitlistInfo `json:"waitlist"`
 }
 
@@ -54,7 +62,9 @@ type UserStatusData struct {
 type QuotaBalance struct {
 	SMSBalance int `json:"sms_balance"`
 	//VoiceBalance   int     `json:"voice_balance"`
-	SMSUnitPrice float32 `json:"sms_unit_price,omitempty"`
+	SMSUnitPrice     float32 `json:"sms_unit_price,omitempty"`
+	SpendableBalance int     `json:"spendable_balance"` // 个人钱包 + 共享钱包本月上限内可用的额度
+	LowBalance       bool    `json:"low_balance"`       // 余额不足以支撑预警阈值内的完整通知轮数
 	//VoiceUnitPrice float32 `json:"voice_unit_price,omitempty"`
 }
 
@@ -63,6 +73,42 @@ type WaitlistStatusData struct {
 }
 
 type WaitlistRequest struct {
-	AuthCode     string `json:"auth_code" binding:"required"`               // 支付宝 authCode
+	AuthCode     string `json:"auth_code" binding:"required"`                 // 支付宝 authCode
 	AlipayOpenID string `json:"alipay_open_id,omitempty" binding:"omitempty"` // 可选：前端已解析的 open_id（无则后端用 authCode 交换）
 }
+
+// RedeemQuotaRequest 兑换码兑换请求
+type RedeemQuotaRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// RedeemQuotaResponse 兑换结果
+type RedeemQuotaResponse struct {
+	Channel    string `json:"channel"`
+	Amount     int    `json:"amount"`      // 本次发放的额度（cents）
+	SMSBalance int    `json:"sms_balance"` // 兑换后的短信余额
+}
+
+// StepUpCaptchaRequest 二次验证：向本人绑定的手机号发送验证码
+// 超过发送阈值时需先用本人手机号通过 POST /v1/auth/phone/verify-slider 获取滑块 token
+type StepUpCaptchaRequest struct {
+	SceneId     string `json:"scene_id" binding:"required"`
+	VerifyToken string `json:"verify_token,omitempty"`
+}
+
+// StepUpRequest 二次验证：提交本人手机号收到的验证码
+type StepUpRequest struct {
+	VerifyCode string `json:"verify_code" binding:"required"`
+}
+
+// StepUpResponse 二次验证结果，有效期内当前会话可以查看敏感信息
+type StepUpResponse struct {
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ChangePhoneRequest 更换手机号请求
+// 新号码的验证码通过 POST /v1/users/me/phone/send-captcha 获取
+type ChangePhoneRequest struct {
+	Phone      string `json:"phone" binding:"required"`
+	VerifyCode string `json:"verify_code" binding:"required"`
+}
@@ -0,0 +1,78 @@
+package dto
+
+import "time"
+
+// ========== WalletGroup 相关 DTO ==========
+
+// CreateWalletGroupRequest 创建共享钱包请求
+type CreateWalletGroupRequest struct {
+	Name string `json:"name" binding:"required,max=64"`
+}
+
+// AddWalletGroupMemberRequest 邀请共享钱包成员请求（按手机号查找已注册用户，对方接受后加入）
+type AddWalletGroupMemberRequest struct {
+	Phone      string `json:"phone" binding:"required"`
+	MonthlyCap int    `json:"monthly_cap" binding:"min=0"` // 0 表示不限
+}
+
+// UpdateWalletGroupMemberRequest 更新成员设置
+// monthly_cap 只能由创建者修改，funding_order 只能由成员本人修改
+type UpdateWalletGroupMemberRequest struct {
+	MonthlyCap   *int    `json:"monthly_cap" binding:"omitempty,min=0"`
+	FundingOrder *string `json:"funding_order" binding:"omitempty,oneof=personal_first shared_first"`
+}
+
+// TransferToWalletGroupRequest 从个人钱包转入共享钱包
+type TransferToWalletGroupRequest struct {
+	Amount int `json:"amount" binding:"required,min=1"`
+}
+
+// WalletGroupUsageQuery 成员用量查询参数
+type WalletGroupUsageQuery struct {
+	Month string `form:"month"` // YYYY-MM，默认当月
+}
+
+// WalletGroupMemberItem 共享钱包成员
+type WalletGroupMemberItem struct {
+	UserID       string `json:"user_id"`
+	Nickname     string `json:"nickname"`
+	Role         string `json:"role"`
+	FundingOrder string `json:"funding_order"`
+	MonthlyCap   int    `json:"monthly_cap"`
+}
+
+// WalletGroupInvitationItem 共享钱包邀请
+// 被邀请人查看时返回组名与邀请人昵称，创建者查看时返回被邀请人昵称
+type WalletGroupInvitationItem struct {
+	ExpiresAt       time.Time `json:"expires_at"`
+	ID              string    `json:"id"`
+	GroupName       string    `json:"group_name,omitempty"`
+	InviterNickname string    `json:"inviter_nickname,omitempty"`
+	InviteeNickname string    `json:"invitee_nickname,omitempty"`
+	MonthlyCap      int       `json:"monthly_cap"`
+}
+
+// WalletGroupData 共享钱包信息
+type WalletGroupData struct {
+	ID          string                      `json:"id"`
+	Name        string                      `json:"name"`
+	Role        string                      `json:"role"` // 当前用户在组内的角色
+	Members     []WalletGroupMemberItem     `json:"members"`
+	Invitations []WalletGroupInvitationItem `json:"invitations,omitempty"` // 待接受的邀请（仅创建者可见）
+	SMSBalance  int                         `json:"sms_balance"`
+}
+
+// WalletGroupMemberUsage 成员月度用量
+type WalletGroupMemberUsage struct {
+	UserID     string `json:"user_id"`
+	Nickname   string `json:"nickname"`
+	Spent      int    `json:"spent"`       // 当月从共享钱包消费的额度（cents），不含已退款部分
+	MonthlyCap int    `json:"monthly_cap"` // 0 表示不限
+}
+
+// WalletGroupUsageData 共享钱包月度用量（仅创建者可见）
+type WalletGroupUsageData struct {
+	Month      string                   `json:"month"`
+	Members    []WalletGroupMemberUsage `json:"members"`
+	TotalSpent int                      `json:"total_spent"`
+}
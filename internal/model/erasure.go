@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// AccountErasureStatus 账号注销状态枚举
+type AccountErasureStatus string
+
+const (
+	AccountErasureStatusPending  AccountErasureStatus = "pending"  // 恢复窗口内，可恢复
+	AccountErasureStatusRestored AccountErasureStatus = "restored" // 用户已恢复账号
+	AccountErasureStatusPurged   AccountErasureStatus = "purged"   // 个人数据已清除
+)
+
+// AccountErasure 账号注销记录
+// 用户注销时创建，恢复或清除后保留作为审计记录，不含任何个人信息
+type AccountErasure struct {
+	RequestedAt time.Time            `gorm:"type:timestamptz;not null" json:"requested_at"`
+	PurgeAfter  time.Time            `gorm:"type:timestamptz;not null;index:idx_account_erasures_status_purge" json:"purge_after"` // 恢复窗口截止时间
+	RestoredAt  *time.Time           `gorm:"type:timestamptz" json:"restored_at,omitempty"`
+	PurgedAt    *time.Time           `gorm:"type:timestamptz" json:"purged_at,omitempty"`
+	Summary     JSONB                `gorm:"type:jsonb;not null;default:'{}'" json:"summary"` // 清除时各表处理的行数
+	Status      AccountErasureStatus `gorm:"type:varchar(16);not null;default:'pending';index:idx_account_erasures_status_purge" json:"status"`
+	BaseModel
+	UserID   int64 `gorm:"not null;index:idx_account_erasures_user" json:"user_id"`
+	PublicID int64 `gorm:"not null" json:"public_id"`
+}
+
+// TableName 指定表名
+func (AccountErasure) TableName() string {
+	return "account_erasures"
+}
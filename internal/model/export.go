@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// DataExportStatus 个人数据导出任务状态枚举
+type DataExportStatus string
+
+const (
+	DataExportStatusPending    DataExportStatus = "pending"    // 已提交，等待 worker 处理
+	DataExportStatusProcessing DataExportStatus = "processing" // 生成中
+	DataExportStatusReady      DataExportStatus = "ready"      // 已生成，可下载
+	DataExportStatusDownloaded DataExportStatus = "downloaded" // 已下载，归档已删除
+	DataExportStatusExpired    DataExportStatus = "expired"    // 超时未下载，归档已删除
+	DataExportStatusFailed     DataExportStatus = "failed"     // 生成失败
+)
+
+// DataExport 个人数据导出任务
+// 归档只保存在 Redis 中（带 TTL），下载一次后立即删除，数据库只记录任务状态
+type DataExport struct {
+	RequestedAt  time.Time        `gorm:"type:timestamptz;not null" json:"requested_at"`
+	ReadyAt      *time.Time       `gorm:"type:timestamptz" json:"ready_at,omitempty"`
+	ExpiresAt    *time.Time       `gorm:"type:timestamptz" json:"expires_at,omitempty"` // 归档过期时间
+	DownloadedAt *time.Time       `gorm:"type:timestamptz" json:"downloaded_at,omitempty"`
+	Status       DataExportStatus `gorm:"type:varchar(16);not null;default:'pending';index:idx_data_exports_user_status,priority:2" json:"status"`
+	ErrorMessage string           `gorm:"type:varchar(255);not null;default:''" json:"error_message,omitempty"`
+	BaseModel
+	UserID       int64 `gorm:"not null;index:idx_data_exports_user_status,priority:1" json:"user_id"`
+	ExportCode   int64 `gorm:"uniqueIndex:data_exports_export_code_key;not null" json:"export_code"` // 对外暴露的导出 ID
+	SizeBytes    int   `gorm:"not null;default:0" json:"size_bytes"`
+	FullContacts bool  `gorm:"not null;default:false" json:"full_contacts"` // 是否包含完整联系人手机号（需二次验证）
+}
+
+// TableName 指定表名
+func (DataExport) TableName() string {
+	return "data_exports"
+}
@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+// IdentityProvider 登录身份提供方
+type IdentityProvider string
+
+const (
+	IdentityProviderAlipay IdentityProvider = "alipay" // 支付宝小程序，subject 为支付宝 user_id / open_id
+	IdentityProviderWechat IdentityProvider = "wechat" // 微信小程序，subject 为 openid
+)
+
+// UserIdentity 用户登录身份，(provider, subject) 全局唯一，一个账号可以绑定多个平台的身份
+// users.alipay_open_id 作为支付宝身份的冗余字段保留，与 provider = alipay 的记录保持一致
+type UserIdentity struct {
+	UnionID  *string          `gorm:"type:varchar(64);index:idx_user_identities_union" json:"union_id,omitempty"` // 微信开放平台 unionid，未绑定开放平台时为空
+	Provider IdentityProvider `gorm:"type:varchar(16);not null;uniqueIndex:user_identities_provider_subject_key,priority:1" json:"provider"`
+	Subject  string           `gorm:"type:varchar(64);not null;uniqueIndex:user_identities_provider_subject_key,priority:2" json:"subject"`
+	BaseModel
+	UserID int64 `gorm:"not null;index:idx_user_identities_user" json:"user_id"`
+}
+
+// TableName 指定表名
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
+
+// IdentityClaim 登录时由平台换取到的身份
+type IdentityClaim struct {
+	Provider IdentityProvider
+	Subject  string
+	UnionID  string
+}
+
+// AccountMerge 账号合并记录，合并后保留作为审计记录
+// 被合并账号（source）的联系人、行程、打卡、额度与登录身份转移到保留账号（target），source 随后软删除
+type AccountMerge struct {
+	MergedAt time.Time        `gorm:"type:timestamptz;not null" json:"merged_at"`
+	Provider IdentityProvider `gorm:"type:varchar(16);not null" json:"provider"` // 证明 source 归属所用的登录身份
+	IP       string           `gorm:"type:varchar(64);not null;default:''" json:"ip"`
+	Summary  JSONB            `gorm:"type:jsonb;not null;default:'{}'" json:"summary"` // 各项数据转移的数量
+	BaseModel
+	SourceUserID   int64 `gorm:"not null;index:idx_account_merges_source" json:"source_user_id"`
+	SourcePublicID int64 `gorm:"not null" json:"source_public_id"`
+	TargetUserID   int64 `gorm:"not null;index:idx_account_merges_target" json:"target_user_id"`
+	TargetPublicID int64 `gorm:"not null" json:"target_public_id"`
+	SessionID      int64 `gorm:"not null;default:0" json:"session_id"` // 发起合并的会话
+}
+
+// TableName 指定表名
+func (AccountMerge) TableName() string {
+	return "account_merges"
+}
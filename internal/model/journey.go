@@ -1,6 +1,10 @@
 package model
 
-import "time"
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
 
 // JourneyStatus 行程状态枚举
 type JourneyStatus string
@@ -23,6 +27,14 @@ const (
 	AlertStatusFailed     AlertStatus = "failed"     // 失败
 )
 
+// JourneyCompletedBy 行程结束方式
+type JourneyCompletedBy string
+
+const (
+	JourneyCompletedByManual   JourneyCompletedBy = "manual"   // 用户点击归来打卡
+	JourneyCompletedByGeofence JourneyCompletedBy = "geofence" // 上报位置进入目的地围栏后自动结束
+)
+
 // Journey 行程报备模型
 type Journey struct {
 	ExpectedReturnTime time.Time     `gorm:"type:timestamptz;not null;index:idx_journeys_expected" json:"expected_return_time"`
@@ -35,15 +47,62 @@ type Journey struct {
 	Status             JourneyStatus `gorm:"type:varchar(16);not null;default:'ongoing';index:idx_journeys_user_status" json:"status"`
 	AlertStatus        AlertStatus   `gorm:"type:varchar(16);not null;default:'pending'" json:"alert_status"`
 
+	// 目的地围栏：离开围栏后再次进入时自动结束行程
+	DestLatitude   *float64           `gorm:"type:double precision" json:"dest_latitude,omitempty"`
+	DestLongitude  *float64           `gorm:"type:double precision" json:"dest_longitude,omitempty"`
+	GeofenceLeftAt *time.Time         `gorm:"type:timestamptz" json:"geofence_left_at,omitempty"` // 行程开始后第一次位于围栏外的定位时间
+	CompletedBy    JourneyCompletedBy `gorm:"type:varchar(16);not null;default:''" json:"completed_by"`
+	DestRadius     int                `gorm:"not null;default:0" json:"dest_radius"` // 围栏半径（米），0 表示没有目的地围栏
+
+	// 公开分享链接：只保存 token 哈希，重新生成或撤销后旧链接失效
+	ShareTokenHash *string    `gorm:"type:char(64);uniqueIndex:idx_journeys_share_token,where:share_token_hash IS NOT NULL" json:"-"`
+	ShareExpiresAt *time.Time `gorm:"type:timestamptz" json:"share_expires_at,omitempty"`
+	ShareLocation  bool       `gorm:"not null;default:false" json:"share_location"` // 分享页是否展示最后位置
+
+	// 超时通知：联系人优先级为空表示全部已确认的联系人；告警留言发给联系人，代替备注
+	ContactPriorities ContactPriorities `gorm:"type:jsonb;not null;default:'[]'" json:"contact_priorities"`
+	AlertMessage      string            `gorm:"type:varchar(128);not null;default:''" json:"alert_message"`
+
 	// P0.7: 延迟消息追踪（用于取消未触发的超时检查）
 	TimeoutMessageID *string `gorm:"type:varchar(128);index:idx_journeys_timeout_message_id" json:"timeout_message_id,omitempty"` // 延迟消息的 message_id，用于在 consumer 中检查行程状态
 
 	BaseModel
-	UserID        int64 `gorm:"not null;index:idx_journeys_user_status" json:"user_id"`
-	AlertAttempts int   `gorm:"not null;default:0" json:"alert_attempts"`
+	UserID          int64 `gorm:"not null;index:idx_journeys_user_status" json:"user_id"`
+	AlertAttempts   int   `gorm:"not null;default:0" json:"alert_attempts"`
+	ExtensionCount  int   `gorm:"not null;default:0" json:"extension_count"`  // 延长次数
+	ExtendedMinutes int   `gorm:"not null;default:0" json:"extended_minutes"` // 累计延长的分钟数
 }
 
 // TableName 指定表名
 func (Journey) TableName() string {
 	return "journeys"
 }
+
+// ContactPriorities 选定的紧急联系人优先级（jsonb 数组），为空表示全部
+type ContactPriorities []int
+
+// Scan 实现 sql.Scanner 接口
+func (p *ContactPriorities) Scan(value interface{}) error {
+	return scanJSONArray(value, p)
+}
+
+// Value 实现 driver.Valuer 接口
+func (p ContactPriorities) Value() (driver.Value, error) {
+	if p == nil {
+		return "[]", nil
+	}
+	return json.Marshal(p)
+}
+
+// Contains 是否选中了该优先级的联系人，为空时视为全部选中
+func (p ContactPriorities) Contains(priority int) bool {
+	if len(p) == 0 {
+		return true
+	}
+	for _, v := range p {
+		if v == priority {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+// JourneyCheckpointStatus 检查点状态
+type JourneyCheckpointStatus string
+
+const (
+	JourneyCheckpointPending   JourneyCheckpointStatus = "pending"    // 待打卡
+	JourneyCheckpointCheckedIn JourneyCheckpointStatus = "checked_in" // 已打卡
+	JourneyCheckpointMissed    JourneyCheckpointStatus = "missed"     // 超时未打卡，已通知紧急联系人
+)
+
+// JourneyCheckpoint 行程检查点（journey_checkpoints 表）
+// 行程中途的节点，每个检查点有独立的提醒与超时：到达预计时间提醒用户打卡，超过宽限期未打卡通知紧急联系人
+type JourneyCheckpoint struct {
+	ExpectedAt       time.Time  `gorm:"type:timestamptz;not null;index:idx_journey_checkpoints_expected" json:"expected_at"`
+	CheckedInAt      *time.Time `gorm:"type:timestamptz" json:"checked_in_at,omitempty"`
+	ReminderSentAt   *time.Time `gorm:"type:timestamptz" json:"reminder_sent_at,omitempty"`
+	AlertTriggeredAt *time.Time `gorm:"type:timestamptz" json:"alert_triggered_at,omitempty"`
+	Latitude         *float64   `gorm:"type:double precision" json:"latitude,omitempty"`
+	Longitude        *float64   `gorm:"type:double precision" json:"longitude,omitempty"`
+
+	// 超时延迟消息的 message_id，consumer 只处理与当前值一致的消息
+	TimeoutMessageID *string `gorm:"type:varchar(128)" json:"timeout_message_id,omitempty"`
+
+	Label    string                  `gorm:"type:varchar(64);not null" json:"label"`
+	Location string                  `gorm:"type:varchar(128);not null;default:''" json:"location"` // 地点描述，可为空
+	Status   JourneyCheckpointStatus `gorm:"type:varchar(16);not null;default:'pending'" json:"status"`
+	BaseModel
+	JourneyID int64 `gorm:"not null;uniqueIndex:idx_journey_checkpoints_seq" json:"journey_id"`
+	Seq       int   `gorm:"not null;uniqueIndex:idx_journey_checkpoints_seq" json:"seq"` // 行程内的顺序，从 1 开始
+}
+
+// TableName 指定表名
+func (JourneyCheckpoint) TableName() string {
+	return "journey_checkpoints"
+}
@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// JourneyEventType 行程时间线事件类型
+type JourneyEventType string
+
+const (
+	JourneyEventCreated     JourneyEventType = "created"     // 创建行程
+	JourneyEventRescheduled JourneyEventType = "rescheduled" // 修改预计返回时间
+	JourneyEventExtended    JourneyEventType = "extended"    // 延长行程（晚点了）
+	JourneyEventCompleted   JourneyEventType = "completed"   // 归来打卡
+	JourneyEventTimeout     JourneyEventType = "timeout"     // 超时并通知紧急联系人
+	JourneyEventCancelled   JourneyEventType = "cancelled"   // 用户取消
+
+	JourneyEventCheckpointCheckedIn JourneyEventType = "checkpoint_checked_in" // 检查点打卡
+	JourneyEventCheckpointMissed    JourneyEventType = "checkpoint_missed"     // 检查点超时并通知紧急联系人
+)
+
+// JourneyEvent 行程时间线（journey_events 表），只追加不修改
+// data 记录事件相关的取值，如延长前后的预计返回时间
+type JourneyEvent struct {
+	OccurredAt time.Time        `gorm:"type:timestamptz;not null" json:"occurred_at"`
+	Type       JourneyEventType `gorm:"type:varchar(32);not null" json:"type"`
+	Data       JSONB            `gorm:"type:jsonb;not null;default:'{}'" json:"data"`
+	BaseModel
+	JourneyID int64 `gorm:"not null;index:idx_journey_events_journey" json:"journey_id"`
+}
+
+// TableName 指定表名
+func (JourneyEvent) TableName() string {
+	return "journey_events"
+}
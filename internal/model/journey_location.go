@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// JourneyLocation 行程位置点（journey_locations 表）
+// 小程序在行程进行中定期上报，每个行程保留的点数有上限；行程结束 JOURNEY_LOCATION_RETAIN_DAYS 天后删除
+type JourneyLocation struct {
+	RecordedAt time.Time `gorm:"type:timestamptz;not null;index:idx_journey_locations_journey,priority:2" json:"recorded_at"` // 设备定位时间
+	Latitude   float64   `gorm:"type:double precision;not null" json:"latitude"`
+	Longitude  float64   `gorm:"type:double precision;not null" json:"longitude"`
+	Accuracy   float64   `gorm:"type:double precision;not null;default:0" json:"accuracy"` // 定位精度（米）
+	BaseModel
+	JourneyID int64 `gorm:"not null;index:idx_journey_locations_journey,priority:1" json:"journey_id"`
+}
+
+// TableName 指定表名
+func (JourneyLocation) TableName() string {
+	return "journey_locations"
+}
@@ -0,0 +1,113 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// JourneyTemplate 行程模板（journey_templates 表）
+// 保存经常重复的行程（夜班通勤、每周徒步），可以设置每周重复规则，由定时任务提前生成每一次的行程安排
+type JourneyTemplate struct {
+	NextOccurrenceAt *time.Time `gorm:"type:timestamptz;index:idx_journey_templates_next" json:"next_occurrence_at,omitempty"` // 下一次尚未生成安排的出发时间，没有重复规则时为空
+
+	Title string `gorm:"type:varchar(64);not null" json:"title"`
+	Note  string `gorm:"type:text;not null;default:''" json:"note"`
+	// 重复规则（RRULE 子集），例如 FREQ=WEEKLY;BYDAY=MO,WE,FR;BYHOUR=22;BYMINUTE=0，按用户时区解释；为空表示不重复
+	RRule             string                     `gorm:"type:varchar(128);not null;default:''" json:"rrule"`
+	Checkpoints       JourneyTemplateCheckpoints `gorm:"type:jsonb;not null;default:'[]'" json:"checkpoints"`
+	ContactPriorities ContactPriorities          `gorm:"type:jsonb;not null;default:'[]'" json:"contact_priorities"` // 超时通知的联系人，为空表示全部
+	AlertMessage      string                     `gorm:"type:varchar(128);not null;default:''" json:"alert_message"` // 发给联系人的留言
+	BaseModel
+	UserID          int64 `gorm:"not null;index:idx_journey_templates_user" json:"user_id"`
+	DurationMinutes int   `gorm:"not null" json:"duration_minutes"`         // 出发到预计返回的时长
+	AutoStart       bool  `gorm:"not null;default:false" json:"auto_start"` // 到点自动开始，否则需要用户确认出发
+}
+
+// TableName 指定表名
+func (JourneyTemplate) TableName() string {
+	return "journey_templates"
+}
+
+// JourneyTemplateCheckpoint 模板中的检查点，时间为相对出发时间的分钟数
+type JourneyTemplateCheckpoint struct {
+	Latitude      *float64 `json:"latitude,omitempty"`
+	Longitude     *float64 `json:"longitude,omitempty"`
+	Label         string   `json:"label"`
+	Location      string   `json:"location"`
+	OffsetMinutes int      `json:"offset_minutes"`
+}
+
+// JourneyTemplateCheckpoints 模板检查点数组（jsonb）
+type JourneyTemplateCheckpoints []JourneyTemplateCheckpoint
+
+// Scan 实现 sql.Scanner 接口
+func (c *JourneyTemplateCheckpoints) Scan(value interface{}) error {
+	return scanJSONArray(value, c)
+}
+
+// Value 实现 driver.Valuer 接口
+func (c JourneyTemplateCheckpoints) Value() (driver.Value, error) {
+	if c == nil {
+		return "[]", nil
+	}
+	return json.Marshal(c)
+}
+
+// JourneyOccurrenceStatus 重复行程的单次安排状态
+type JourneyOccurrenceStatus string
+
+const (
+	JourneyOccurrenceScheduled JourneyOccurrenceStatus = "scheduled" // 已生成，等待出发
+	JourneyOccurrenceStarted   JourneyOccurrenceStatus = "started"   // 已开始，生成了行程
+	JourneyOccurrenceSkipped   JourneyOccurrenceStatus = "skipped"   // 跳过
+)
+
+// JourneyOccurrenceSkipReason 跳过原因
+type JourneyOccurrenceSkipReason string
+
+const (
+	JourneyOccurrenceSkipUser        JourneyOccurrenceSkipReason = "user"        // 用户主动跳过
+	JourneyOccurrenceSkipUnconfirmed JourneyOccurrenceSkipReason = "unconfirmed" // 出发后宽限期内未确认
+	JourneyOccurrenceSkipFailed      JourneyOccurrenceSkipReason = "failed"      // 自动开始失败（例如模板已不满足创建行程的条件）
+)
+
+// JourneyOccurrence 重复行程的单次安排（journey_occurrences 表）
+// 定时任务按模板的重复规则提前生成，出发前提醒用户确认；开始后关联生成的行程，跳过的安排保留记录
+type JourneyOccurrence struct {
+	StartAt        time.Time                   `gorm:"type:timestamptz;not null;uniqueIndex:idx_journey_occurrences_start,priority:2;index:idx_journey_occurrences_status_start,priority:2" json:"start_at"`
+	ReminderSentAt *time.Time                  `gorm:"type:timestamptz" json:"reminder_sent_at,omitempty"` // 出发前确认提醒的发送时间
+	JourneyID      *int64                      `json:"journey_id,omitempty"`                               // 开始后生成的行程
+	Status         JourneyOccurrenceStatus     `gorm:"type:varchar(16);not null;default:'scheduled';index:idx_journey_occurrences_status_start,priority:1" json:"status"`
+	SkipReason     JourneyOccurrenceSkipReason `gorm:"type:varchar(16);not null;default:''" json:"skip_reason"`
+	BaseModel
+	TemplateID int64 `gorm:"not null;uniqueIndex:idx_journey_occurrences_start,priority:1" json:"template_id"`
+	UserID     int64 `gorm:"not null;index:idx_journey_occurrences_user" json:"user_id"`
+}
+
+// TableName 指定表名
+func (JourneyOccurrence) TableName() string {
+	return "journey_occurrences"
+}
+
+// scanJSONArray 从 jsonb 读取数组，NULL 或空值按空数组处理
+func scanJSONArray(value interface{}, dst interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("cannot scan non-string value into JSON array")
+	}
+	if len(bytes) == 0 {
+		return nil
+	}
+	return json.Unmarshal(bytes, dst)
+}
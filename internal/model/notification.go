@@ -18,6 +18,7 @@ const (
 	NotificationCategoryJourneyReminder NotificationCategory = "journey_reminder"  // 行程提醒（预留）
 	NotificationCategoryJourneyTimeout  NotificationCategory = "journey_timeout"   // 行程超时通知
 	NotificationCategoryQuotaDepleted   NotificationCategory = "quota_depleted"    // 额度耗尽提醒
+	NotificationCategoryQuotaLow        NotificationCategory = "quota_low"         // 额度不足预警
 )
 
 // NotificationChannel 通知渠道枚举
@@ -36,6 +37,7 @@ const (
 	NotificationTaskStatusProcessing NotificationTaskStatus = "processing" // 处理中
 	NotificationTaskStatusSuccess    NotificationTaskStatus = "success"    // 成功
 	NotificationTaskStatusFailed     NotificationTaskStatus = "failed"     // 失败
+	NotificationTaskStatusCancelled  NotificationTaskStatus = "cancelled"  // 已取消（用户注销）
 )
 
 // NotificationTask 通知任务模型
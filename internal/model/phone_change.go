@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// PhoneChangeLog 更换手机号记录，同时用于冷却期判断
+// 只保存手机号哈希，不保存明文
+type PhoneChangeLog struct {
+	ChangedAt    time.Time `gorm:"type:timestamptz;not null;index:idx_phone_change_logs_user_changed,priority:2" json:"changed_at"`
+	OldPhoneHash string    `gorm:"type:char(64);not null;default:''" json:"-"`
+	NewPhoneHash string    `gorm:"type:char(64);not null" json:"-"`
+	IP           string    `gorm:"type:varchar(64);not null;default:''" json:"ip"`
+	BaseModel
+	UserID      int64 `gorm:"not null;index:idx_phone_change_logs_user_changed,priority:1" json:"user_id"`
+	SessionID   int64 `gorm:"not null;default:0" json:"session_id"`       // 发起更换的会话，该会话保持登录
+	OldNotified bool  `gorm:"not null;default:false" json:"old_notified"` // 是否已短信通知旧号码
+}
+
+// TableName 指定表名
+func (PhoneChangeLog) TableName() string {
+	return "phone_change_logs"
+}
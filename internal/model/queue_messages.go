@@ -44,6 +44,7 @@ type JourneyReminderMessage struct {
 	ScheduledAt  string `json:"scheduled_at"`
 	JourneyID    int64  `json:"journey_id"`
 	UserID       int64  `json:"user_id"`
+	CheckpointID int64  `json:"checkpoint_id,omitempty"` // 非 0 时为检查点提醒
 	DelaySeconds int    `json:"delay_seconds"`
 }
 
@@ -53,6 +54,7 @@ type JourneyTimeoutMessage struct {
 	ScheduledAt  string `json:"scheduled_at"`
 	JourneyID    int64  `json:"journey_id"`
 	UserID       int64  `json:"user_id"`
+	CheckpointID int64  `json:"checkpoint_id,omitempty"` // 非 0 时为检查点超时
 	DelaySeconds int    `json:"delay_seconds"`
 }
 
@@ -77,3 +79,10 @@ type EventMessage struct {
 	EventType  string                 `json:"event_type"`
 	OccurredAt string                 `json:"occurred_at"`
 }
+
+// UserExportMessage 个人数据导出任务消息
+type UserExportMessage struct {
+	MessageID  string `json:"message_id"` // 消息唯一ID，用于幂等性检查
+	ExportCode int64  `json:"export_code"`
+	UserID     int64  `json:"user_id"` // public_id
+}
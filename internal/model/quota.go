@@ -15,7 +15,8 @@ const (
 // QuotaWallet 额度钱包模型
 type QuotaWallet struct {
 	ID             int64         `gorm:"primaryKey" json:"id"`
-	UserID         int64         `gorm:"not null;index:idx_quota_user_channel_frozen" json:"user_id"`
+	UserID         int64         `gorm:"not null;index:idx_quota_user_channel_frozen" json:"user_id"` // 个人钱包为所属用户，共享钱包为组创建者
+	GroupID        *int64        `gorm:"index:idx_quota_wallets_group" json:"group_id,omitempty"`       // 非空表示共享钱包
 	Channel        QuotaChannel  `gorm:"type:varchar(16);not null;index:idx_quota_user_channel_frozen" json:"channel"`
 	AvailableAmount int          `gorm:"not null;default:0" json:"available_amount"`
 	FrozenAmount   int          `gorm:"not null;default:0" json:"frozen_amount"`
@@ -43,23 +44,39 @@ const (
 //   - "grant_default": 默认赠送
 //   - "grant_recharge": 用户充值
 //   - "grant_refund": 退款（预扣减失败后的退款）
+//   - "grant_plan": 订阅套餐按月发放
+//   - "grant_redeem": 兑换码兑换
+//   - "grant_transfer": 从个人钱包转入共享钱包
+//   - "grant_merge": 账号合并时从被合并账号转入
+//   - "grant_group_settle": 共享钱包解散时按转入比例退回
 //
 // 扣减类型（transaction_type='deduct'）:
 //   - "sms_notification": 短信通知扣减（已废弃，改为预扣减机制）
 // 
 //   - "pre_deduct": 预扣减（冻结额度）
 //   - "confirm_deduct": 确认扣减（解冻并正式扣除）
+//   - "transfer_out": 转出到共享钱包
+//   - "merge_out": 账号合并时转出到保留账号
+//   - "group_settle_out": 共享钱包解散时结清余额
 const (
 	// 充值原因
-	QuotaReasonGrantDefault  = "grant_default"  // 默认赠送
-	QuotaReasonGrantRecharge = "grant_recharge" // 用户充值
-	QuotaReasonGrantRefund   = "grant_refund"   // 退款
+	QuotaReasonGrantDefault     = "grant_default"      // 默认赠送
+	QuotaReasonGrantRecharge    = "grant_recharge"     // 用户充值
+	QuotaReasonGrantRefund      = "grant_refund"       // 退款
+	QuotaReasonGrantPlan        = "grant_plan"         // 订阅套餐按月发放
+	QuotaReasonGrantRedeem      = "grant_redeem"       // 兑换码兑换
+	QuotaReasonGrantTransfer    = "grant_transfer"     // 从个人钱包转入共享钱包
+	QuotaReasonGrantMerge       = "grant_merge"        // 账号合并时从被合并账号转入
+	QuotaReasonGrantGroupSettle = "grant_group_settle" // 共享钱包解散时按转入比例退回
 
 	// 扣减原因
 	QuotaReasonSMSNotification   = "sms_notification"   // 短信通知扣减（已废弃）
 	QuotaReasonVoiceNotification = "voice_notification" // 语音通知扣减
 	QuotaReasonPreDeduct         = "pre_deduct"         // 预扣减（冻结额度）
 	QuotaReasonConfirmDeduct     = "confirm_deduct"     // 确认扣减（解冻并正式扣除）
+	QuotaReasonTransferOut       = "transfer_out"       // 转出到共享钱包
+	QuotaReasonMergeOut          = "merge_out"          // 账号合并时转出到保留账号
+	QuotaReasonGroupSettleOut    = "group_settle_out"   // 共享钱包解散时结清余额
 )
 
 // QuotaTransaction 额度流水模型
@@ -70,7 +87,8 @@ type QuotaTransaction struct {
 	TransactionType TransactionType `gorm:"type:varchar(16);not null" json:"transaction_type"`
 	Reason          string          `gorm:"type:varchar(32);not null" json:"reason"` // 扩展为 32 字符，支持更详细的 reason
 	BaseModel
-	UserID       int64 `gorm:"not null;index:idx_quota_transactions_user;index:idx_quota_transactions_user_channel_created" json:"user_id"`
+	UserID       int64 `gorm:"not null;index:idx_quota_transactions_user;index:idx_quota_transactions_user_channel_created" json:"user_id"` // 消费（或受益）的用户
+	WalletID     int64 `gorm:"not null;default:0;index:idx_quota_transactions_wallet" json:"wallet_id"`                                     // 实际付款的钱包
 	Amount       int   `gorm:"not null" json:"amount"`
 	BalanceAfter int   `gorm:"not null" json:"balance_after"`
 }
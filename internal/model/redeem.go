@@ -0,0 +1,64 @@
+package model
+
+import "time"
+
+// RedeemCodeStatus 兑换码状态枚举
+type RedeemCodeStatus string
+
+const (
+	RedeemCodeStatusActive   RedeemCodeStatus = "active"   // 可兑换
+	RedeemCodeStatusDisabled RedeemCodeStatus = "disabled" // 已作废
+)
+
+// RedeemCodeBatch 兑换码批次（由后台生成，例如发给合作的养老机构）
+type RedeemCodeBatch struct {
+	ExpiresAt time.Time    `gorm:"type:timestamptz;not null" json:"expires_at"`
+	Name      string       `gorm:"type:varchar(64);not null" json:"name"`
+	Note      string       `gorm:"type:text;not null;default:''" json:"note"`
+	CreatedBy string       `gorm:"type:varchar(64);not null;default:''" json:"created_by"`
+	Channel   QuotaChannel `gorm:"type:varchar(16);not null" json:"channel"`
+	BaseModel
+	Amount         int `gorm:"not null" json:"amount"`                    // 每次兑换发放的额度（cents）
+	MaxRedemptions int `gorm:"not null;default:1" json:"max_redemptions"` // 每个码可兑换次数，1 表示一次性
+	CodeCount      int `gorm:"not null" json:"code_count"`
+}
+
+// TableName 指定表名
+func (RedeemCodeBatch) TableName() string {
+	return "redeem_code_batches"
+}
+
+// RedeemCode 兑换码
+// 只保存兑换码的哈希，明文只在生成时输出一次
+type RedeemCode struct {
+	ExpiresAt time.Time        `gorm:"type:timestamptz;not null" json:"expires_at"`
+	CodeHash  string           `gorm:"type:char(64);not null;uniqueIndex:redeem_codes_code_hash_key" json:"-"`
+	Channel   QuotaChannel     `gorm:"type:varchar(16);not null" json:"channel"`
+	Status    RedeemCodeStatus `gorm:"type:varchar(16);not null;default:'active'" json:"status"`
+	BaseModel
+	BatchID        int64 `gorm:"not null;index:idx_redeem_codes_batch" json:"batch_id"`
+	Amount         int   `gorm:"not null" json:"amount"`
+	MaxRedemptions int   `gorm:"not null;default:1" json:"max_redemptions"`
+	RedeemedCount  int   `gorm:"not null;default:0" json:"redeemed_count"`
+}
+
+// TableName 指定表名
+func (RedeemCode) TableName() string {
+	return "redeem_codes"
+}
+
+// RedeemRecord 兑换记录，同一用户对同一兑换码只能兑换一次
+type RedeemRecord struct {
+	RedeemedAt time.Time    `gorm:"type:timestamptz;not null;default:now()" json:"redeemed_at"`
+	Channel    QuotaChannel `gorm:"type:varchar(16);not null" json:"channel"`
+	ID         int64        `gorm:"primaryKey;autoIncrement" json:"id"`
+	CodeID     int64        `gorm:"not null;uniqueIndex:redeem_records_code_user_key" json:"code_id"`
+	UserID     int64        `gorm:"not null;uniqueIndex:redeem_records_code_user_key;index:idx_redeem_records_user" json:"user_id"`
+	BatchID    int64        `gorm:"not null" json:"batch_id"`
+	Amount     int          `gorm:"not null" json:"amount"`
+}
+
+// TableName 指定表名
+func (RedeemRecord) TableName() string {
+	return "redeem_records"
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// SessionRevokeReason 会话失效原因
+const (
+	SessionRevokeReasonLogout       = "logout"        // 用户在该设备登出
+	SessionRevokeReasonUserRevoked  = "user_revoked"  // 用户在其他设备上移除该会话
+	SessionRevokeReasonRefreshReuse = "refresh_reuse" // 检测到已轮换的 refresh token 被重复使用
+	SessionRevokeReasonAccountErase = "account_erase" // 用户注销账号
+	SessionRevokeReasonPhoneChanged = "phone_changed" // 用户更换了绑定手机号
+	SessionRevokeReasonMerged       = "merged"        // 账号被合并到另一个账号
+)
+
+// UserSession 登录会话，每台设备一条
+// refresh token 每次使用都会轮换，会话中只记录当前有效的 refresh token jti，
+// 旧 jti 再次出现说明 token 已泄露，整个会话随之失效
+type UserSession struct {
+	LastSeenAt        time.Time  `gorm:"type:timestamptz;not null;default:now()" json:"last_seen_at"`
+	ExpiresAt         time.Time  `gorm:"type:timestamptz;not null" json:"expires_at"` // 随每次刷新顺延
+	RevokedAt         *time.Time `gorm:"type:timestamptz" json:"revoked_at,omitempty"`
+	Platform          string     `gorm:"type:varchar(32);not null;default:''" json:"platform"`
+	DeviceModel       string     `gorm:"type:varchar(64);not null;default:''" json:"device_model"`
+	AppVersion        string     `gorm:"type:varchar(32);not null;default:''" json:"app_version"`
+	IP                string     `gorm:"type:varchar(64);not null;default:''" json:"ip"`
+	CurrentRefreshJTI string     `gorm:"type:varchar(64);not null" json:"-"`
+	RevokedReason     string     `gorm:"type:varchar(32);not null;default:''" json:"revoked_reason,omitempty"`
+	BaseModel
+	UserID       int64 `gorm:"not null;index:idx_user_sessions_user" json:"user_id"`
+	RefreshCount int   `gorm:"not null;default:0" json:"refresh_count"` // 已轮换次数
+}
+
+// TableName 指定表名
+func (UserSession) TableName() string {
+	return "user_sessions"
+}
@@ -3,6 +3,7 @@ package model
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -60,15 +61,17 @@ func (m *smsMessage) SetTemplateCode(templateCode string) {
 }
 
 // CheckInReminderContactMessage 打卡通知紧急联系人
-// 模板内容：您的联系人${name}，今日的平安打卡任务还未完成，请及时联系 ta 确认情况。
+// 模板内容：您的联系人${name}，今日的平安打卡任务还未完成，请及时联系 ta 确认情况。退订：${optout}
 type CheckInReminderContactMessage struct {
 	smsMessage
-	Name string `json:"name"`
+	Name   string `json:"name"`
+	OptOut string `json:"optout"` // 退订链接
 }
 
 func (m *CheckInReminderContactMessage) GetTemplateParams() (string, error) {
 	params := map[string]string{
-		"name": m.Name,
+		"name":   m.Name,
+		"optout": m.OptOut,
 	}
 	data, err := json.Marshal(params)
 	return string(data), err
@@ -79,21 +82,36 @@ func (m *CheckInReminderContactMessage) GetMessageType() string {
 }
 
 // JourneyReminderContactMessage 旅行联系紧急联系人
-// 模板内容：您的联系人${name}没有进行归来打卡，请联系 ta 确认情况。行程信息：${trip}，预计归来时间：${time}。备注: ${note}。
+// 模板内容：您的联系人${name}没有进行归来打卡，请联系 ta 确认情况。行程信息：${trip}，预计归来时间：${time}。备注: ${note}。退订：${optout}
+// 检查点超时复用同一模板：trip 附带检查点名称，time 为检查点的预计到达时间
+// 有最后位置时并入 note：定位时间与地图链接
 type JourneyReminderContactMessage struct {
 	smsMessage
-	Name string `json:"name"` // 用户昵称
-	Trip string `json:"trip"` // 行程标题
-	Time string `json:"time"` // 预计返回时间（字符串格式）
-	Note string `json:"note"` // 备注
+	Name       string `json:"name"`                 // 用户昵称
+	Trip       string `json:"trip"`                 // 行程标题
+	Time       string `json:"time"`                 // 预计返回时间（字符串格式）
+	Note       string `json:"note"`                 // 备注
+	OptOut     string `json:"optout"`               // 退订链接
+	Checkpoint string `json:"checkpoint,omitempty"` // 错过的检查点名称，行程整体超时时为空
+	LocatedAt  string `json:"located_at,omitempty"` // 最后位置的定位时间
+	Map        string `json:"map,omitempty"`        // 最后位置的地图链接
 }
 
 func (m *JourneyReminderContactMessage) GetTemplateParams() (string, error) {
+	trip := m.Trip
+	if m.Checkpoint != "" {
+		trip = fmt.Sprintf("%s（未在检查点「%s」打卡）", m.Trip, m.Checkpoint)
+	}
+	note := m.Note
+	if m.Map != "" {
+		note = strings.TrimSpace(fmt.Sprintf("%s 最后位置（%s）：%s", m.Note, m.LocatedAt, m.Map))
+	}
 	params := map[string]string{
-		"name": m.Name, //这个部分应该取联系人的称呼
-		"trip": m.Trip,
-		"time": m.Time,
-		"note": m.Note,
+		"name":   m.Name, //这个部分应该取联系人的称呼
+		"trip":   trip,
+		"time":   m.Time,
+		"note":   note,
+		"optout": m.OptOut,
 	}
 	data, err := json.Marshal(params)
 	return string(data), err
@@ -138,6 +156,46 @@ func (m *JourneyTimeOut) GetMessageType() string {
 	return "journey_timeout"
 }
 
+// JourneyPrestart 重复行程出发前确认提醒（发送给用户本人）
+// 模板内容：安否提醒您，行程「${trip}」将于 ${time} 出发，请在小程序中确认出发或跳过本次行程。
+type JourneyPrestart struct {
+	smsMessage
+	Trip string `json:"trip"` // 行程标题
+	Time string `json:"time"` // 计划出发时间
+}
+
+func (m *JourneyPrestart) GetTemplateParams() (string, error) {
+	params := map[string]string{
+		"trip": m.Trip,
+		"time": m.Time,
+	}
+	data, err := json.Marshal(params)
+	return string(data), err
+}
+
+func (m *JourneyPrestart) GetMessageType() string {
+	return "journey_prestart"
+}
+
+// JourneyAlertUndelivered 行程告警无法送达指定联系人（发送给用户本人）
+// 模板内容：安否提醒您，行程「${trip}」已超时，但指定的紧急联系人均不可用，告警未能发出，请尽快确认平安。
+type JourneyAlertUndelivered struct {
+	smsMessage
+	Trip string `json:"trip"` // 行程标题
+}
+
+func (m *JourneyAlertUndelivered) GetTemplateParams() (string, error) {
+	params := map[string]string{
+		"trip": m.Trip,
+	}
+	data, err := json.Marshal(params)
+	return string(data), err
+}
+
+func (m *JourneyAlertUndelivered) GetMessageType() string {
+	return "journey_alert_undelivered"
+}
+
 // CheckInTimeOut 打卡超时提醒
 type CheckInTimeOut struct {
 	smsMessage
@@ -172,6 +230,27 @@ func (m *QuotaDepleted) GetMessageType() string {
 	return "quota_depleted"
 }
 
+// QuotaLow 额度不足预警
+// 模板内容：安否温馨提示，您的紧急联系额度仅剩 ${balance}，约可支持 ${fanouts} 次完整通知，请及时充值。
+type QuotaLow struct {
+	smsMessage
+	Balance int `json:"balance"`
+	Fanouts int `json:"fanouts"`
+}
+
+func (m *QuotaLow) GetTemplateParams() (string, error) {
+	params := map[string]string{
+		"balance": fmt.Sprintf("%d", m.Balance),
+		"fanouts": fmt.Sprintf("%d", m.Fanouts),
+	}
+	data, err := json.Marshal(params)
+	return string(data), err
+}
+
+func (m *QuotaLow) GetMessageType() string {
+	return "quota_low"
+}
+
 // ParseSMSMessage 从 map[string]interface{} 解析为具体的 SMSMessage
 
 func ParseSMSMessage(payload map[string]interface{}) (SMSMessage, error) {
@@ -210,6 +289,18 @@ func ParseSMSMessage(payload map[string]interface{}) (SMSMessage, error) {
 			return nil, fmt.Errorf("failed to parse JourneyTimeOut: %w", err)
 		}
 		return &msg, nil
+	case "journey_prestart":
+		var msg JourneyPrestart
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return nil, fmt.Errorf("failed to parse JourneyPrestart: %w", err)
+		}
+		return &msg, nil
+	case "journey_alert_undelivered":
+		var msg JourneyAlertUndelivered
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return nil, fmt.Errorf("failed to parse JourneyAlertUndelivered: %w", err)
+		}
+		return &msg, nil
 	case "checkin_timeout":
 		var msg CheckInTimeOut
 		if err := json.Unmarshal(data, &msg); err != nil {
@@ -222,6 +313,12 @@ func ParseSMSMessage(payload map[string]interface{}) (SMSMessage, error) {
 			return nil, fmt.Errorf("failed to parse QuotaDepleted: %w", err)
 		}
 		return &msg, nil
+	case "quota_low":
+		var msg QuotaLow
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return nil, fmt.Errorf("failed to parse QuotaLow: %w", err)
+		}
+		return &msg, nil
 	default:
 		return nil, fmt.Errorf("unknown message type: %s", messageType)
 	}
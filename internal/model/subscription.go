@@ -0,0 +1,108 @@
+package model
+
+import "time"
+
+// PlanCode 订阅套餐枚举
+type PlanCode string
+
+const (
+	PlanFree    PlanCode = "free"    // 免费版
+	PlanPremium PlanCode = "premium" // 高级版
+	PlanFamily  PlanCode = "family"  // 家庭版
+)
+
+// Plan 套餐定义：每月赠送额度、联系人上限、提醒上限与可用渠道
+type Plan struct {
+	Code                 PlanCode       `json:"code"`
+	Name                 string         `json:"name"`
+	Channels             []QuotaChannel `json:"channels"`
+	MonthlyGrant         int            `json:"monthly_grant"`          // 每个计费周期赠送的额度（cents）
+	MaxContacts          int            `json:"max_contacts"`           // 紧急联系人上限
+	MonthlyReminderLimit int            `json:"monthly_reminder_limit"` // 每月打卡提醒上限
+}
+
+// HasChannel 套餐是否包含指定渠道
+func (p Plan) HasChannel(channel QuotaChannel) bool {
+	for _, c := range p.Channels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// Plans 套餐列表，免费版保持原有限制（注册赠送一次，3 位联系人，每月 5 次提醒）
+var Plans = map[PlanCode]Plan{
+	PlanFree: {
+		Code:                 PlanFree,
+		Name:                 "免费版",
+		Channels:             []QuotaChannel{QuotaChannelSMS},
+		MonthlyGrant:         0,
+		MaxContacts:          3,
+		MonthlyReminderLimit: 5,
+	},
+	PlanPremium: {
+		Code:                 PlanPremium,
+		Name:                 "高级版",
+		Channels:             []QuotaChannel{QuotaChannelSMS},
+		MonthlyGrant:         300, // 60 次短信
+		MaxContacts:          5,
+		MonthlyReminderLimit: 31,
+	},
+	PlanFamily: {
+		Code:                 PlanFamily,
+		Name:                 "家庭版",
+		Channels:             []QuotaChannel{QuotaChannelSMS},
+		MonthlyGrant:         1000, // 200 次短信
+		MaxContacts:          5,
+		MonthlyReminderLimit: 31,
+	},
+}
+
+// GetPlan 获取套餐定义，未知套餐按免费版处理
+func GetPlan(code PlanCode) Plan {
+	if plan, ok := Plans[code]; ok {
+		return plan
+	}
+	return Plans[PlanFree]
+}
+
+// SubscriptionStatus 订阅状态枚举
+type SubscriptionStatus string
+
+const (
+	SubscriptionStatusActive    SubscriptionStatus = "active"    // 生效中
+	SubscriptionStatusCancelled SubscriptionStatus = "cancelled" // 已取消（不再续期发放额度）
+)
+
+// Subscription 用户订阅模型
+// 没有订阅记录或订阅已取消的用户按免费版处理
+type Subscription struct {
+	BillingAnchor time.Time          `gorm:"type:timestamptz;not null" json:"billing_anchor"`                                              // 计费锚点，每月的周年日发放额度
+	NextGrantAt   time.Time          `gorm:"type:timestamptz;not null;index:idx_subscriptions_next_grant,priority:2" json:"next_grant_at"` // 下一次发放额度的时间
+	Plan          PlanCode           `gorm:"type:varchar(16);not null;default:'free'" json:"plan"`
+	Status        SubscriptionStatus `gorm:"type:varchar(16);not null;default:'active';index:idx_subscriptions_next_grant,priority:1" json:"status"`
+
+	BaseModel
+	UserID        int64 `gorm:"not null;uniqueIndex:subscriptions_user_id_key" json:"user_id"`
+	GrantedCycles int   `gorm:"not null;default:0" json:"granted_cycles"` // 已发放的周期数
+}
+
+// TableName 指定表名
+func (Subscription) TableName() string {
+	return "subscriptions"
+}
+
+// NextBillingAnniversary 计算 anchor 之后第 cycles 个月的周年日
+// 锚点日期大于当月天数时取当月最后一天（例如 1 月 31 日 -> 2 月 28 日）
+func NextBillingAnniversary(anchor time.Time, cycles int) time.Time {
+	firstOfMonth := time.Date(anchor.Year(), anchor.Month()+time.Month(cycles), 1,
+		anchor.Hour(), anchor.Minute(), anchor.Second(), 0, anchor.Location())
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+
+	day := anchor.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return firstOfMonth.AddDate(0, 0, day-1)
+}
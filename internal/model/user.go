@@ -30,8 +30,9 @@ type User struct {
 	DailyCheckInDeadline   string  `gorm:"type:time without time zone;not null;default:'20:00:00'" json:"daily_check_in_deadline"`
 
 	PhoneHash              *string `gorm:"type:char(64);uniqueIndex:users_phone_hash_key" json:"-"` // phone_hash 唯一约束，匹配数据库中的约束名称
+	PhoneHashVersion       int     `gorm:"type:smallint;not null;default:0" json:"-"` // phone_hash 的哈希版本，轮换密钥后由 rehash 任务更新
 	PublicID            int64             `gorm:"uniqueIndex:users_public_id_key;not null" json:"public_id"` // 匹配数据库中的约束名称
-	AlipayOpenID        string            `gorm:"uniqueIndex:users_alipay_open_id_key;type:varchar(64);not null" json:"alipay_open_id"` // 匹配数据库中的约束名称
+	AlipayOpenID        string            `gorm:"uniqueIndex:users_alipay_open_id_key,where:alipay_open_id <> '';type:varchar(64);not null;default:''" json:"alipay_open_id"` // 支付宝身份的冗余字段，仅通过微信登录的用户为空
 
 	BaseModel
 	Status              UserStatus        `gorm:"type:varchar(16);not null;default:'waitlisted';index:idx_users_status" json:"status"`
@@ -39,11 +40,16 @@ type User struct {
 	Nickname            string            `gorm:"type:varchar(64);not null;default:''" json:"nickname"`
 	
 	PhoneCipher         []byte            `gorm:"type:bytea" json:"-"`
-	EmergencyContacts   EmergencyContacts `gorm:"type:jsonb;default:'[]'" json:"emergency_contacts"`
+	LegacyContacts      EmergencyContacts `gorm:"column:emergency_contacts;type:jsonb;default:'[]'" json:"-"` // 已迁移到 emergency_contacts 表，迁移回填后清空
 	
 	DailyCheckInEnabled bool              `gorm:"not null;default:false" json:"daily_check_in_enabled"`
 	JourneyAutoNotify   bool              `gorm:"not null;default:true" json:"journey_auto_notify"`
 
+	// 家的位置（地理围栏），行程可以选择以家为目的地，到达后自动结束行程
+	HomeLatitude        *float64          `gorm:"type:double precision" json:"home_latitude,omitempty"`
+	HomeLongitude       *float64          `gorm:"type:double precision" json:"home_longitude,omitempty"`
+	HomeRadius          int               `gorm:"not null;default:0" json:"home_radius"` // 围栏半径（米），0 表示未设置
+
 	//DailyCheckInTimeRange JSONB `gorm:"type:jsonb;default:'null'"` // {"start": "08:00:00", "end": "20:00:00"}
 }
 
@@ -51,7 +57,7 @@ func (User) TableName() string {
 	return "users"
 }
 
-// EmergencyContacts 紧急联系人数组（JSONB）
+// EmergencyContacts 旧版紧急联系人数组（users.emergency_contacts JSONB），仅用于迁移回填
 type EmergencyContacts []EmergencyContact
 
 // Scan 实现 sql.Scanner 接口，用于从数据库读取 JSONB 数据
@@ -87,12 +93,13 @@ func (ec EmergencyContacts) Value() (driver.Value, error) {
 	return json.Marshal(ec)
 }
 
-// EmergencyContact 紧急联系人结构（存储在 users.emergency_contacts JSONB 中）
+// EmergencyContact 旧版紧急联系人结构（存储在 users.emergency_contacts JSONB 中）
 type EmergencyContact struct {
 	DisplayName       string `json:"display_name"`
 	Relationship      string `json:"relationship"`
 	PhoneCipherBase64 string `json:"phone_cipher_base64"`
 	PhoneHash         string `json:"phone_hash"`
+	PhoneHashVersion  int    `json:"phone_hash_version"`
 	CreatedAt         string `json:"created_at"`
 	Priority          int    `json:"priority"`
 }
@@ -0,0 +1,63 @@
+package model
+
+import "time"
+
+// WalletGroupRole 共享钱包成员角色
+type WalletGroupRole string
+
+const (
+	WalletGroupRoleOwner  WalletGroupRole = "owner"  // 创建者，负责充值与管理成员
+	WalletGroupRoleMember WalletGroupRole = "member" // 普通成员，只能消费
+)
+
+// FundingOrder 扣费时钱包的使用顺序
+type FundingOrder string
+
+const (
+	FundingOrderPersonalFirst FundingOrder = "personal_first" // 先扣个人钱包，不足时扣共享钱包（默认）
+	FundingOrderSharedFirst   FundingOrder = "shared_first"   // 先扣共享钱包，不足或超出月度上限时扣个人钱包
+)
+
+// WalletGroup 共享钱包组（例如子女为父母共同支付提醒费用）
+// 组内每个渠道对应一个 quota_wallets 记录（group_id 指向本组）
+type WalletGroup struct {
+	Name string `gorm:"type:varchar(64);not null" json:"name"`
+	BaseModel
+	OwnerID int64 `gorm:"not null;index:idx_wallet_groups_owner" json:"owner_id"`
+}
+
+// TableName 指定表名
+func (WalletGroup) TableName() string {
+	return "wallet_groups"
+}
+
+// WalletGroupMember 共享钱包成员，一个用户同一时间只能加入一个组
+type WalletGroupMember struct {
+	Role         WalletGroupRole `gorm:"type:varchar(16);not null;default:'member'" json:"role"`
+	FundingOrder FundingOrder    `gorm:"type:varchar(16);not null;default:'personal_first'" json:"funding_order"`
+	BaseModel
+	GroupID    int64 `gorm:"not null;index:idx_wallet_group_members_group" json:"group_id"`
+	UserID     int64 `gorm:"not null;uniqueIndex:wallet_group_members_user_id_key" json:"user_id"`
+	MonthlyCap int   `gorm:"not null;default:0" json:"monthly_cap"` // 每月最多从共享钱包消费的额度（cents），0 表示不限
+}
+
+// TableName 指定表名
+func (WalletGroupMember) TableName() string {
+	return "wallet_group_members"
+}
+
+// WalletGroupInvitation 共享钱包邀请，被邀请人接受后才成为成员
+// 接受、拒绝或撤销后物理删除，同一组对同一用户只保留一条待处理的邀请
+type WalletGroupInvitation struct {
+	ExpiresAt time.Time `gorm:"type:timestamptz;not null" json:"expires_at"`
+	BaseModel
+	GroupID    int64 `gorm:"not null;uniqueIndex:wallet_group_invitations_group_user_key,priority:1" json:"group_id"`
+	UserID     int64 `gorm:"not null;uniqueIndex:wallet_group_invitations_group_user_key,priority:2;index:idx_wallet_group_invitations_user" json:"user_id"` // 被邀请人
+	InviterID  int64 `gorm:"not null" json:"inviter_id"`
+	MonthlyCap int   `gorm:"not null;default:0" json:"monthly_cap"` // 接受后生效的月度上限（cents），0 表示不限
+}
+
+// TableName 指定表名
+func (WalletGroupInvitation) TableName() string {
+	return "wallet_group_invitations"
+}
@@ -482,7 +482,12 @@ func StartJourneyReminderConsumer(ctx context.Context) error {
 		)
 		// 调用 service 层处理行程提醒逻辑（创建 NotificationTask 记录）
 		journeyService := service.Journey()
-		task, err := journeyService.ProcessReminder(ctx, msg.JourneyID, msg.UserID)
+		var task *model.NotificationTask
+		if msg.CheckpointID != 0 {
+			task, err = journeyService.ProcessCheckpointReminder(ctx, msg.JourneyID, msg.CheckpointID, msg.UserID)
+		} else {
+			task, err = journeyService.ProcessReminder(ctx, msg.JourneyID, msg.UserID)
+		}
 		if err != nil {
 			if errors.IsSkipMessageError(err) {
 				logger.Logger.Info("Skipping journey reminder processing",
@@ -618,7 +623,13 @@ func StartJourneyTimeoutConsumer(ctx context.Context) error {
 
 		// 调用 service 层处理行程超时逻辑
 		journeyService := service.Journey()
-		tasks, err := journeyService.ProcessTimeout(ctx, msg.JourneyID, msg.UserID)
+		var tasks []*model.NotificationTask
+		if msg.CheckpointID != 0 {
+			// 检查点超时：通知紧急联系人时附带检查点信息，行程保持进行中
+			tasks, err = journeyService.ProcessCheckpointTimeout(ctx, msg.JourneyID, msg.CheckpointID, msg.UserID, msg.MessageID)
+		} else {
+			tasks, err = journeyService.ProcessTimeout(ctx, msg.JourneyID, msg.UserID, msg.MessageID)
+		}
 		if err != nil {
 			// 1. 可跳过的错误：标记为已处理，不重试
 			if errors.IsSkipMessageError(err) {
@@ -880,6 +891,12 @@ func StartSMSNotificationConsumer(ctx context.Context) error {
 			}
 		}
 
+		// 扣费成功后检查额度是否跌破预警阈值（额度提醒本身不再触发检查）
+		if msg.Category != string(model.NotificationCategoryQuotaLow) &&
+			msg.Category != string(model.NotificationCategoryQuotaDepleted) {
+			publishQuotaLowNotification(ctx, msg.UserID)
+		}
+
 		// 处理成功，不需要清理标记
 		shouldCleanup = false
 		return nil
@@ -894,6 +911,141 @@ func StartSMSNotificationConsumer(ctx context.Context) error {
 	})
 }
 
+// StartUserExportConsumer 启动个人数据导出消费者
+// 生成归档属于耗时任务，放到 worker 中异步执行
+func StartUserExportConsumer(ctx context.Context) error {
+	handler := func(body []byte) error {
+		var msg model.UserExportMessage
+		if err := json.Unmarshal(body, &msg); err != nil {
+			return fmt.Errorf("failed to unmarshal user export message: %w", err)
+		}
+
+		messageID := msg.MessageID
+		shouldCleanup := false
+		defer func() {
+			if shouldCleanup && messageID != "" {
+				if err := cache.UnmarkMessageProcessing(ctx, messageID); err != nil {
+					logger.Logger.Warn("Failed to cleanup message processing mark",
+						zap.String("message_id", messageID),
+						zap.Error(err),
+					)
+				}
+			}
+		}()
+
+		processed, err := cache.TryMarkMessageProcessing(ctx, msg.MessageID, 24*time.Hour)
+		if err != nil {
+			logger.Logger.Warn("Failed to check message processed status",
+				zap.String("message_id", msg.MessageID),
+				zap.Int64("export_code", msg.ExportCode),
+				zap.Error(err),
+			)
+			shouldCleanup = true
+		} else if !processed {
+			logger.Logger.Debug("Message already processed or being processed, skipping",
+				zap.String("message_id", msg.MessageID),
+				zap.Int64("export_code", msg.ExportCode),
+			)
+			return &errors.SkipMessageError{Reason: fmt.Sprintf("Message %s already processed", msg.MessageID)}
+		} else {
+			shouldCleanup = true
+		}
+
+		if err := service.Export().Build(ctx, msg.ExportCode); err != nil {
+			if errors.IsSkipMessageError(err) {
+				logger.Logger.Info("Skipping user export",
+					zap.String("message_id", msg.MessageID),
+					zap.String("reason", err.(*errors.SkipMessageError).Reason),
+				)
+				if markErr := cache.MarkMessageProcessed(ctx, msg.MessageID, 48*time.Hour); markErr != nil {
+					logger.Logger.Warn("Failed to mark skipped message as processed",
+						zap.String("message_id", msg.MessageID),
+						zap.Error(markErr),
+					)
+				}
+				shouldCleanup = false
+				return nil
+			}
+
+			logger.Logger.Error("Failed to build user export",
+				zap.String("message_id", msg.MessageID),
+				zap.Int64("export_code", msg.ExportCode),
+				zap.Int64("user_id", msg.UserID),
+				zap.Error(err),
+			)
+			return fmt.Errorf("failed to build user export: %w", err)
+		}
+
+		if err := cache.MarkMessageProcessed(ctx, msg.MessageID, 48*time.Hour); err != nil {
+			logger.Logger.Warn("Failed to mark message as processed",
+				zap.String("message_id", msg.MessageID),
+				zap.Error(err),
+			)
+		}
+
+		shouldCleanup = false
+		return nil
+	}
+
+	return mq.Consume(mq.ConsumeOptions{
+		Queue:         "jobs.user_export",
+		ConsumerTag:   "user_export_consumer",
+		PrefetchCount: 2, // 打包占用内存较多，限制并发
+		Handler:       handler,
+		Context:       ctx,
+	})
+}
+
+// publishQuotaLowNotification 检查用户额度是否跌破预警阈值，需要时投递额度不足预警
+// 失败只记录日志，不影响当前消息的处理结果
+func publishQuotaLowNotification(ctx context.Context, publicUserID int64) {
+	db := database.DB().WithContext(ctx)
+	q := query.Use(db)
+
+	user, err := q.User.GetByPublicID(publicUserID)
+	if err != nil {
+		logger.Logger.Warn("Failed to query user for quota low check",
+			zap.Int64("user_id", publicUserID),
+			zap.Error(err),
+		)
+		return
+	}
+
+	task, err := service.Quota().CheckLowBalance(ctx, user)
+	if err != nil {
+		logger.Logger.Warn("Failed to check low balance",
+			zap.Int64("user_id", publicUserID),
+			zap.Error(err),
+		)
+		return
+	}
+	if task == nil {
+		return
+	}
+
+	notificationMsg := model.NotificationMessage{
+		MessageID: fmt.Sprintf("notification_%d", task.TaskCode),
+		TaskCode:  task.TaskCode,
+		UserID:    user.PublicID,
+		Category:  string(task.Category),
+		Channel:   string(task.Channel),
+		Payload:   task.Payload,
+	}
+
+	if err := PublishSMSNotification(notificationMsg); err != nil {
+		logger.Logger.Error("Failed to publish quota low notification",
+			zap.Int64("task_code", task.TaskCode),
+			zap.Error(err),
+		)
+		return
+	}
+
+	logger.Logger.Info("Published quota low notification",
+		zap.Int64("task_code", task.TaskCode),
+		zap.Int64("user_id", user.PublicID),
+	)
+}
+
 // updateReminderSentAt 更新打卡记录的 reminder_sent_at 字段
 func updateReminderSentAt(ctx context.Context, publicUserID int64, checkInDateStr string) error {
 	db := database.DB().WithContext(ctx)
@@ -954,6 +1106,7 @@ func StartAllConsumers(ctx context.Context) {
 		{"journey_reminder", StartJourneyReminderConsumer},
 		{"journey_timeout", StartJourneyTimeoutConsumer},
 		{"sms_notification", StartSMSNotificationConsumer},
+		{"user_export", StartUserExportConsumer},
 		//{"voice_notification", StartVoiceNotificationConsumer}, 现有全部切换为短信通知
 	}
 
@@ -241,3 +241,32 @@ func PublishSMSNotification(msg model.NotificationMessage) error {
 	return nil
 }
 
+// PublishUserExport 发布个人数据导出任务
+func PublishUserExport(msg model.UserExportMessage) error {
+	if msg.MessageID == "" {
+		msg.MessageID = fmt.Sprintf("user_export_%d", msg.ExportCode)
+	}
+
+	err := mq.PublishMessage(
+		"jobs.direct",
+		"jobs.user_export",
+		msg,
+	)
+
+	if err != nil {
+		logger.Logger.Error("Failed to publish user export message",
+			zap.Int64("export_code", msg.ExportCode),
+			zap.Int64("user_id", msg.UserID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	logger.Logger.Info("Published user export message",
+		zap.String("message_id", msg.MessageID),
+		zap.Int64("export_code", msg.ExportCode),
+		zap.Int64("user_id", msg.UserID),
+	)
+
+	return nil
+}
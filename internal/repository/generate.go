@@ -20,10 +20,10 @@ type UserQuerier interface {
 	// SELECT * FROM @@table WHERE alipay_open_id = @openID LIMIT 1
 	GetByAlipayOpenID(openID string) (*gen.T, error)
 
-	// GetByPhoneHash 根据手机号哈希查询用户
+	// GetByPhoneHashes 根据手机号哈希查询用户，传入当前与上一版本的候选哈希（见 utils.PhoneHashCandidates）
 	//
-	// SELECT * FROM @@table WHERE phone_hash = @phoneHash LIMIT 1
-	GetByPhoneHash(phoneHash string) (*gen.T, error)
+	// SELECT * FROM @@table WHERE phone_hash IN @phoneHashes LIMIT 1
+	GetByPhoneHashes(phoneHashes []string) (*gen.T, error)
 
 	// GetByPublicID 根据 PublicID 查询用户（最常用，API 中 userID 是 public_id）
 	//
@@ -67,8 +67,8 @@ type UserQuerier interface {
 	//   COALESCE(qw_sms.available_amount, 0) as sms_balance,
 	//   COALESCE(qw_voice.available_amount, 0) as voice_balance
 	// FROM @@table u
-	// LEFT JOIN quota_wallets qw_sms ON qw_sms.user_id = u.id AND qw_sms.channel = 'sms'
-	// LEFT JOIN quota_wallets qw_voice ON qw_voice.user_id = u.id AND qw_voice.channel = 'voice'
+	// LEFT JOIN quota_wallets qw_sms ON qw_sms.user_id = u.id AND qw_sms.channel = 'sms' AND qw_sms.group_id IS NULL
+	// LEFT JOIN quota_wallets qw_voice ON qw_voice.user_id = u.id AND qw_voice.channel = 'voice' AND qw_voice.group_id IS NULL
 	// WHERE u.id = @userID
 	// LIMIT 1
 	GetWithQuotas(userID int64) (gen.M, error)
@@ -79,8 +79,8 @@ type UserQuerier interface {
 	//   COALESCE(qw_sms.available_amount, 0) as sms_balance,
 	//   COALESCE(qw_voice.available_amount, 0) as voice_balance
 	// FROM @@table u
-	// LEFT JOIN quota_wallets qw_sms ON qw_sms.user_id = u.id AND qw_sms.channel = 'sms'
-	// LEFT JOIN quota_wallets qw_voice ON qw_voice.user_id = u.id AND qw_voice.channel = 'voice'
+	// LEFT JOIN quota_wallets qw_sms ON qw_sms.user_id = u.id AND qw_sms.channel = 'sms' AND qw_sms.group_id IS NULL
+	// LEFT JOIN quota_wallets qw_voice ON qw_voice.user_id = u.id AND qw_voice.channel = 'voice' AND qw_voice.group_id IS NULL
 	// WHERE u.public_id = @publicID
 	// LIMIT 1
 	GetByPublicIDWithQuotas(publicID int64) (gen.M, error)
@@ -522,6 +522,27 @@ func Generate() error {
 		&model.QuotaWallet{},    // 添加 QuotaWallet model
 		&model.QuotaTransaction{},
 		&model.ContactAttempt{}, // 添加 ContactAttempt model
+		&model.Subscription{},
+		&model.RedeemCodeBatch{},
+		&model.RedeemCode{},
+		&model.RedeemRecord{},
+		&model.WalletGroup{},
+		&model.WalletGroupMember{},
+		&model.WalletGroupInvitation{},
+		&model.UserSession{},
+		&model.AccountErasure{},
+		&model.DataExport{},
+		&model.PhoneChangeLog{},
+		&model.UserIdentity{},
+		&model.AccountMerge{},
+		&model.AuditEvent{},
+		&model.Contact{},
+		&model.ContactOptOut{},
+		&model.JourneyEvent{},
+		&model.JourneyCheckpoint{},
+		&model.JourneyLocation{},
+		&model.JourneyTemplate{},
+		&model.JourneyOccurrence{},
 	)
 
 	// 直接应用接口，GORM Gen 会根据接口中的类型自动匹配已注册的 model
@@ -0,0 +1,426 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"AreYouOK/internal/model"
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+func newAccountErasure(db *gorm.DB, opts ...gen.DOOption) accountErasure {
+	_accountErasure := accountErasure{}
+
+	_accountErasure.accountErasureDo.UseDB(db, opts...)
+	_accountErasure.accountErasureDo.UseModel(&model.AccountErasure{})
+
+	tableName := _accountErasure.accountErasureDo.TableName()
+	_accountErasure.ALL = field.NewAsterisk(tableName)
+	_accountErasure.RequestedAt = field.NewTime(tableName, "requested_at")
+	_accountErasure.PurgeAfter = field.NewTime(tableName, "purge_after")
+	_accountErasure.RestoredAt = field.NewTime(tableName, "restored_at")
+	_accountErasure.PurgedAt = field.NewTime(tableName, "purged_at")
+	_accountErasure.Summary = field.NewField(tableName, "summary")
+	_accountErasure.Status = field.NewString(tableName, "status")
+	_accountErasure.CreatedAt = field.NewTime(tableName, "created_at")
+	_accountErasure.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_accountErasure.DeletedAt = field.NewField(tableName, "deleted_at")
+	_accountErasure.ID = field.NewInt64(tableName, "id")
+	_accountErasure.UserID = field.NewInt64(tableName, "user_id")
+	_accountErasure.PublicID = field.NewInt64(tableName, "public_id")
+
+	_accountErasure.fillFieldMap()
+
+	return _accountErasure
+}
+
+type accountErasure struct {
+	accountErasureDo
+
+	ALL         field.Asterisk
+	RequestedAt field.Time
+	PurgeAfter  field.Time
+	RestoredAt  field.Time
+	PurgedAt    field.Time
+	Summary     field.Field
+	Status      field.String
+	CreatedAt   field.Time
+	UpdatedAt   field.Time
+	DeletedAt   field.Field
+	ID          field.Int64
+	UserID      field.Int64
+	PublicID    field.Int64
+
+	fieldMap map[string]field.Expr
+}
+
+func (a accountErasure) Table(newTableName string) *accountErasure {
+	a.accountErasureDo.UseTable(newTableName)
+	return a.updateTableName(newTableName)
+}
+
+func (a accountErasure) As(alias string) *accountErasure {
+	a.accountErasureDo.DO = *(a.accountErasureDo.As(alias).(*gen.DO))
+	return a.updateTableName(alias)
+}
+
+func (a *accountErasure) updateTableName(table string) *accountErasure {
+	a.ALL = field.NewAsterisk(table)
+	a.RequestedAt = field.NewTime(table, "requested_at")
+	a.PurgeAfter = field.NewTime(table, "purge_after")
+	a.RestoredAt = field.NewTime(table, "restored_at")
+	a.PurgedAt = field.NewTime(table, "purged_at")
+	a.Summary = field.NewField(table, "summary")
+	a.Status = field.NewString(table, "status")
+	a.CreatedAt = field.NewTime(table, "created_at")
+	a.UpdatedAt = field.NewTime(table, "updated_at")
+	a.DeletedAt = field.NewField(table, "deleted_at")
+	a.ID = field.NewInt64(table, "id")
+	a.UserID = field.NewInt64(table, "user_id")
+	a.PublicID = field.NewInt64(table, "public_id")
+
+	a.fillFieldMap()
+
+	return a
+}
+
+func (a *accountErasure) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := a.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (a *accountErasure) fillFieldMap() {
+	a.fieldMap = make(map[string]field.Expr, 12)
+	a.fieldMap["requested_at"] = a.RequestedAt
+	a.fieldMap["purge_after"] = a.PurgeAfter
+	a.fieldMap["restored_at"] = a.RestoredAt
+	a.fieldMap["purged_at"] = a.PurgedAt
+	a.fieldMap["summary"] = a.Summary
+	a.fieldMap["status"] = a.Status
+	a.fieldMap["created_at"] = a.CreatedAt
+	a.fieldMap["updated_at"] = a.UpdatedAt
+	a.fieldMap["deleted_at"] = a.DeletedAt
+	a.fieldMap["id"] = a.ID
+	a.fieldMap["user_id"] = a.UserID
+	a.fieldMap["public_id"] = a.PublicID
+}
+
+func (a accountErasure) clone(db *gorm.DB) accountErasure {
+	a.accountErasureDo.ReplaceConnPool(db.Statement.ConnPool)
+	return a
+}
+
+func (a accountErasure) replaceDB(db *gorm.DB) accountErasure {
+	a.accountErasureDo.ReplaceDB(db)
+	return a
+}
+
+type accountErasureDo struct{ gen.DO }
+
+type IAccountErasureDo interface {
+	gen.SubQuery
+	Debug() IAccountErasureDo
+	WithContext(ctx context.Context) IAccountErasureDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IAccountErasureDo
+	WriteDB() IAccountErasureDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IAccountErasureDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IAccountErasureDo
+	Not(conds ...gen.Condition) IAccountErasureDo
+	Or(conds ...gen.Condition) IAccountErasureDo
+	Select(conds ...field.Expr) IAccountErasureDo
+	Where(conds ...gen.Condition) IAccountErasureDo
+	Order(conds ...field.Expr) IAccountErasureDo
+	Distinct(cols ...field.Expr) IAccountErasureDo
+	Omit(cols ...field.Expr) IAccountErasureDo
+	Join(table schema.Tabler, on ...field.Expr) IAccountErasureDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IAccountErasureDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IAccountErasureDo
+	Group(cols ...field.Expr) IAccountErasureDo
+	Having(conds ...gen.Condition) IAccountErasureDo
+	Limit(limit int) IAccountErasureDo
+	Offset(offset int) IAccountErasureDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IAccountErasureDo
+	Unscoped() IAccountErasureDo
+	Create(values ...*model.AccountErasure) error
+	CreateInBatches(values []*model.AccountErasure, batchSize int) error
+	Save(values ...*model.AccountErasure) error
+	First() (*model.AccountErasure, error)
+	Take() (*model.AccountErasure, error)
+	Last() (*model.AccountErasure, error)
+	Find() ([]*model.AccountErasure, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.AccountErasure, err error)
+	FindInBatches(result *[]*model.AccountErasure, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.AccountErasure) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IAccountErasureDo
+	Assign(attrs ...field.AssignExpr) IAccountErasureDo
+	Joins(fields ...field.RelationField) IAccountErasureDo
+	Preload(fields ...field.RelationField) IAccountErasureDo
+	FirstOrInit() (*model.AccountErasure, error)
+	FirstOrCreate() (*model.AccountErasure, error)
+	FindByPage(offset int, limit int) (result []*model.AccountErasure, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IAccountErasureDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (a accountErasureDo) Debug() IAccountErasureDo {
+	return a.withDO(a.DO.Debug())
+}
+
+func (a accountErasureDo) WithContext(ctx context.Context) IAccountErasureDo {
+	return a.withDO(a.DO.WithContext(ctx))
+}
+
+func (a accountErasureDo) ReadDB() IAccountErasureDo {
+	return a.Clauses(dbresolver.Read)
+}
+
+func (a accountErasureDo) WriteDB() IAccountErasureDo {
+	return a.Clauses(dbresolver.Write)
+}
+
+func (a accountErasureDo) Session(config *gorm.Session) IAccountErasureDo {
+	return a.withDO(a.DO.Session(config))
+}
+
+func (a accountErasureDo) Clauses(conds ...clause.Expression) IAccountErasureDo {
+	return a.withDO(a.DO.Clauses(conds...))
+}
+
+func (a accountErasureDo) Returning(value interface{}, columns ...string) IAccountErasureDo {
+	return a.withDO(a.DO.Returning(value, columns...))
+}
+
+func (a accountErasureDo) Not(conds ...gen.Condition) IAccountErasureDo {
+	return a.withDO(a.DO.Not(conds...))
+}
+
+func (a accountErasureDo) Or(conds ...gen.Condition) IAccountErasureDo {
+	return a.withDO(a.DO.Or(conds...))
+}
+
+func (a accountErasureDo) Select(conds ...field.Expr) IAccountErasureDo {
+	return a.withDO(a.DO.Select(conds...))
+}
+
+func (a accountErasureDo) Where(conds ...gen.Condition) IAccountErasureDo {
+	return a.withDO(a.DO.Where(conds...))
+}
+
+func (a accountErasureDo) Order(conds ...field.Expr) IAccountErasureDo {
+	return a.withDO(a.DO.Order(conds...))
+}
+
+func (a accountErasureDo) Distinct(cols ...field.Expr) IAccountErasureDo {
+	return a.withDO(a.DO.Distinct(cols...))
+}
+
+func (a accountErasureDo) Omit(cols ...field.Expr) IAccountErasureDo {
+	return a.withDO(a.DO.Omit(cols...))
+}
+
+func (a accountErasureDo) Join(table schema.Tabler, on ...field.Expr) IAccountErasureDo {
+	return a.withDO(a.DO.Join(table, on...))
+}
+
+func (a accountErasureDo) LeftJoin(table schema.Tabler, on ...field.Expr) IAccountErasureDo {
+	return a.withDO(a.DO.LeftJoin(table, on...))
+}
+
+func (a accountErasureDo) RightJoin(table schema.Tabler, on ...field.Expr) IAccountErasureDo {
+	return a.withDO(a.DO.RightJoin(table, on...))
+}
+
+func (a accountErasureDo) Group(cols ...field.Expr) IAccountErasureDo {
+	return a.withDO(a.DO.Group(cols...))
+}
+
+func (a accountErasureDo) Having(conds ...gen.Condition) IAccountErasureDo {
+	return a.withDO(a.DO.Having(conds...))
+}
+
+func (a accountErasureDo) Limit(limit int) IAccountErasureDo {
+	return a.withDO(a.DO.Limit(limit))
+}
+
+func (a accountErasureDo) Offset(offset int) IAccountErasureDo {
+	return a.withDO(a.DO.Offset(offset))
+}
+
+func (a accountErasureDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IAccountErasureDo {
+	return a.withDO(a.DO.Scopes(funcs...))
+}
+
+func (a accountErasureDo) Unscoped() IAccountErasureDo {
+	return a.withDO(a.DO.Unscoped())
+}
+
+func (a accountErasureDo) Create(values ...*model.AccountErasure) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return a.DO.Create(values)
+}
+
+func (a accountErasureDo) CreateInBatches(values []*model.AccountErasure, batchSize int) error {
+	return a.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (a accountErasureDo) Save(values ...*model.AccountErasure) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return a.DO.Save(values)
+}
+
+func (a accountErasureDo) First() (*model.AccountErasure, error) {
+	if result, err := a.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AccountErasure), nil
+	}
+}
+
+func (a accountErasureDo) Take() (*model.AccountErasure, error) {
+	if result, err := a.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AccountErasure), nil
+	}
+}
+
+func (a accountErasureDo) Last() (*model.AccountErasure, error) {
+	if result, err := a.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AccountErasure), nil
+	}
+}
+
+func (a accountErasureDo) Find() ([]*model.AccountErasure, error) {
+	result, err := a.DO.Find()
+	return result.([]*model.AccountErasure), err
+}
+
+func (a accountErasureDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.AccountErasure, err error) {
+	buf := make([]*model.AccountErasure, 0, batchSize)
+	err = a.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (a accountErasureDo) FindInBatches(result *[]*model.AccountErasure, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return a.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (a accountErasureDo) Attrs(attrs ...field.AssignExpr) IAccountErasureDo {
+	return a.withDO(a.DO.Attrs(attrs...))
+}
+
+func (a accountErasureDo) Assign(attrs ...field.AssignExpr) IAccountErasureDo {
+	return a.withDO(a.DO.Assign(attrs...))
+}
+
+func (a accountErasureDo) Joins(fields ...field.RelationField) IAccountErasureDo {
+	for _, _f := range fields {
+		a = *a.withDO(a.DO.Joins(_f))
+	}
+	return &a
+}
+
+func (a accountErasureDo) Preload(fields ...field.RelationField) IAccountErasureDo {
+	for _, _f := range fields {
+		a = *a.withDO(a.DO.Preload(_f))
+	}
+	return &a
+}
+
+func (a accountErasureDo) FirstOrInit() (*model.AccountErasure, error) {
+	if result, err := a.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AccountErasure), nil
+	}
+}
+
+func (a accountErasureDo) FirstOrCreate() (*model.AccountErasure, error) {
+	if result, err := a.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AccountErasure), nil
+	}
+}
+
+func (a accountErasureDo) FindByPage(offset int, limit int) (result []*model.AccountErasure, count int64, err error) {
+	result, err = a.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = a.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (a accountErasureDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = a.Count()
+	if err != nil {
+		return
+	}
+
+	err = a.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (a accountErasureDo) Scan(result interface{}) (err error) {
+	return a.DO.Scan(result)
+}
+
+func (a accountErasureDo) Delete(models ...*model.AccountErasure) (result gen.ResultInfo, err error) {
+	return a.DO.Delete(models)
+}
+
+func (a *accountErasureDo) withDO(do gen.Dao) *accountErasureDo {
+	a.DO = *do.(*gen.DO)
+	return a
+}
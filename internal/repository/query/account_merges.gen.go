@@ -0,0 +1,430 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"AreYouOK/internal/model"
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+func newAccountMerge(db *gorm.DB, opts ...gen.DOOption) accountMerge {
+	_accountMerge := accountMerge{}
+
+	_accountMerge.accountMergeDo.UseDB(db, opts...)
+	_accountMerge.accountMergeDo.UseModel(&model.AccountMerge{})
+
+	tableName := _accountMerge.accountMergeDo.TableName()
+	_accountMerge.ALL = field.NewAsterisk(tableName)
+	_accountMerge.MergedAt = field.NewTime(tableName, "merged_at")
+	_accountMerge.Provider = field.NewString(tableName, "provider")
+	_accountMerge.IP = field.NewString(tableName, "ip")
+	_accountMerge.Summary = field.NewField(tableName, "summary")
+	_accountMerge.CreatedAt = field.NewTime(tableName, "created_at")
+	_accountMerge.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_accountMerge.DeletedAt = field.NewField(tableName, "deleted_at")
+	_accountMerge.ID = field.NewInt64(tableName, "id")
+	_accountMerge.SourceUserID = field.NewInt64(tableName, "source_user_id")
+	_accountMerge.SourcePublicID = field.NewInt64(tableName, "source_public_id")
+	_accountMerge.TargetUserID = field.NewInt64(tableName, "target_user_id")
+	_accountMerge.TargetPublicID = field.NewInt64(tableName, "target_public_id")
+	_accountMerge.SessionID = field.NewInt64(tableName, "session_id")
+
+	_accountMerge.fillFieldMap()
+
+	return _accountMerge
+}
+
+type accountMerge struct {
+	accountMergeDo
+
+	ALL            field.Asterisk
+	MergedAt       field.Time
+	Provider       field.String
+	IP             field.String
+	Summary        field.Field
+	CreatedAt      field.Time
+	UpdatedAt      field.Time
+	DeletedAt      field.Field
+	ID             field.Int64
+	SourceUserID   field.Int64
+	SourcePublicID field.Int64
+	TargetUserID   field.Int64
+	TargetPublicID field.Int64
+	SessionID      field.Int64
+
+	fieldMap map[string]field.Expr
+}
+
+func (a accountMerge) Table(newTableName string) *accountMerge {
+	a.accountMergeDo.UseTable(newTableName)
+	return a.updateTableName(newTableName)
+}
+
+func (a accountMerge) As(alias string) *accountMerge {
+	a.accountMergeDo.DO = *(a.accountMergeDo.As(alias).(*gen.DO))
+	return a.updateTableName(alias)
+}
+
+func (a *accountMerge) updateTableName(table string) *accountMerge {
+	a.ALL = field.NewAsterisk(table)
+	a.MergedAt = field.NewTime(table, "merged_at")
+	a.Provider = field.NewString(table, "provider")
+	a.IP = field.NewString(table, "ip")
+	a.Summary = field.NewField(table, "summary")
+	a.CreatedAt = field.NewTime(table, "created_at")
+	a.UpdatedAt = field.NewTime(table, "updated_at")
+	a.DeletedAt = field.NewField(table, "deleted_at")
+	a.ID = field.NewInt64(table, "id")
+	a.SourceUserID = field.NewInt64(table, "source_user_id")
+	a.SourcePublicID = field.NewInt64(table, "source_public_id")
+	a.TargetUserID = field.NewInt64(table, "target_user_id")
+	a.TargetPublicID = field.NewInt64(table, "target_public_id")
+	a.SessionID = field.NewInt64(table, "session_id")
+
+	a.fillFieldMap()
+
+	return a
+}
+
+func (a *accountMerge) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := a.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (a *accountMerge) fillFieldMap() {
+	a.fieldMap = make(map[string]field.Expr, 13)
+	a.fieldMap["merged_at"] = a.MergedAt
+	a.fieldMap["provider"] = a.Provider
+	a.fieldMap["ip"] = a.IP
+	a.fieldMap["summary"] = a.Summary
+	a.fieldMap["created_at"] = a.CreatedAt
+	a.fieldMap["updated_at"] = a.UpdatedAt
+	a.fieldMap["deleted_at"] = a.DeletedAt
+	a.fieldMap["id"] = a.ID
+	a.fieldMap["source_user_id"] = a.SourceUserID
+	a.fieldMap["source_public_id"] = a.SourcePublicID
+	a.fieldMap["target_user_id"] = a.TargetUserID
+	a.fieldMap["target_public_id"] = a.TargetPublicID
+	a.fieldMap["session_id"] = a.SessionID
+}
+
+func (a accountMerge) clone(db *gorm.DB) accountMerge {
+	a.accountMergeDo.ReplaceConnPool(db.Statement.ConnPool)
+	return a
+}
+
+func (a accountMerge) replaceDB(db *gorm.DB) accountMerge {
+	a.accountMergeDo.ReplaceDB(db)
+	return a
+}
+
+type accountMergeDo struct{ gen.DO }
+
+type IAccountMergeDo interface {
+	gen.SubQuery
+	Debug() IAccountMergeDo
+	WithContext(ctx context.Context) IAccountMergeDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IAccountMergeDo
+	WriteDB() IAccountMergeDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IAccountMergeDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IAccountMergeDo
+	Not(conds ...gen.Condition) IAccountMergeDo
+	Or(conds ...gen.Condition) IAccountMergeDo
+	Select(conds ...field.Expr) IAccountMergeDo
+	Where(conds ...gen.Condition) IAccountMergeDo
+	Order(conds ...field.Expr) IAccountMergeDo
+	Distinct(cols ...field.Expr) IAccountMergeDo
+	Omit(cols ...field.Expr) IAccountMergeDo
+	Join(table schema.Tabler, on ...field.Expr) IAccountMergeDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IAccountMergeDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IAccountMergeDo
+	Group(cols ...field.Expr) IAccountMergeDo
+	Having(conds ...gen.Condition) IAccountMergeDo
+	Limit(limit int) IAccountMergeDo
+	Offset(offset int) IAccountMergeDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IAccountMergeDo
+	Unscoped() IAccountMergeDo
+	Create(values ...*model.AccountMerge) error
+	CreateInBatches(values []*model.AccountMerge, batchSize int) error
+	Save(values ...*model.AccountMerge) error
+	First() (*model.AccountMerge, error)
+	Take() (*model.AccountMerge, error)
+	Last() (*model.AccountMerge, error)
+	Find() ([]*model.AccountMerge, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.AccountMerge, err error)
+	FindInBatches(result *[]*model.AccountMerge, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.AccountMerge) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IAccountMergeDo
+	Assign(attrs ...field.AssignExpr) IAccountMergeDo
+	Joins(fields ...field.RelationField) IAccountMergeDo
+	Preload(fields ...field.RelationField) IAccountMergeDo
+	FirstOrInit() (*model.AccountMerge, error)
+	FirstOrCreate() (*model.AccountMerge, error)
+	FindByPage(offset int, limit int) (result []*model.AccountMerge, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IAccountMergeDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (a accountMergeDo) Debug() IAccountMergeDo {
+	return a.withDO(a.DO.Debug())
+}
+
+func (a accountMergeDo) WithContext(ctx context.Context) IAccountMergeDo {
+	return a.withDO(a.DO.WithContext(ctx))
+}
+
+func (a accountMergeDo) ReadDB() IAccountMergeDo {
+	return a.Clauses(dbresolver.Read)
+}
+
+func (a accountMergeDo) WriteDB() IAccountMergeDo {
+	return a.Clauses(dbresolver.Write)
+}
+
+func (a accountMergeDo) Session(config *gorm.Session) IAccountMergeDo {
+	return a.withDO(a.DO.Session(config))
+}
+
+func (a accountMergeDo) Clauses(conds ...clause.Expression) IAccountMergeDo {
+	return a.withDO(a.DO.Clauses(conds...))
+}
+
+func (a accountMergeDo) Returning(value interface{}, columns ...string) IAccountMergeDo {
+	return a.withDO(a.DO.Returning(value, columns...))
+}
+
+func (a accountMergeDo) Not(conds ...gen.Condition) IAccountMergeDo {
+	return a.withDO(a.DO.Not(conds...))
+}
+
+func (a accountMergeDo) Or(conds ...gen.Condition) IAccountMergeDo {
+	return a.withDO(a.DO.Or(conds...))
+}
+
+func (a accountMergeDo) Select(conds ...field.Expr) IAccountMergeDo {
+	return a.withDO(a.DO.Select(conds...))
+}
+
+func (a accountMergeDo) Where(conds ...gen.Condition) IAccountMergeDo {
+	return a.withDO(a.DO.Where(conds...))
+}
+
+func (a accountMergeDo) Order(conds ...field.Expr) IAccountMergeDo {
+	return a.withDO(a.DO.Order(conds...))
+}
+
+func (a accountMergeDo) Distinct(cols ...field.Expr) IAccountMergeDo {
+	return a.withDO(a.DO.Distinct(cols...))
+}
+
+func (a accountMergeDo) Omit(cols ...field.Expr) IAccountMergeDo {
+	return a.withDO(a.DO.Omit(cols...))
+}
+
+func (a accountMergeDo) Join(table schema.Tabler, on ...field.Expr) IAccountMergeDo {
+	return a.withDO(a.DO.Join(table, on...))
+}
+
+func (a accountMergeDo) LeftJoin(table schema.Tabler, on ...field.Expr) IAccountMergeDo {
+	return a.withDO(a.DO.LeftJoin(table, on...))
+}
+
+func (a accountMergeDo) RightJoin(table schema.Tabler, on ...field.Expr) IAccountMergeDo {
+	return a.withDO(a.DO.RightJoin(table, on...))
+}
+
+func (a accountMergeDo) Group(cols ...field.Expr) IAccountMergeDo {
+	return a.withDO(a.DO.Group(cols...))
+}
+
+func (a accountMergeDo) Having(conds ...gen.Condition) IAccountMergeDo {
+	return a.withDO(a.DO.Having(conds...))
+}
+
+func (a accountMergeDo) Limit(limit int) IAccountMergeDo {
+	return a.withDO(a.DO.Limit(limit))
+}
+
+func (a accountMergeDo) Offset(offset int) IAccountMergeDo {
+	return a.withDO(a.DO.Offset(offset))
+}
+
+func (a accountMergeDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IAccountMergeDo {
+	return a.withDO(a.DO.Scopes(funcs...))
+}
+
+func (a accountMergeDo) Unscoped() IAccountMergeDo {
+	return a.withDO(a.DO.Unscoped())
+}
+
+func (a accountMergeDo) Create(values ...*model.AccountMerge) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return a.DO.Create(values)
+}
+
+func (a accountMergeDo) CreateInBatches(values []*model.AccountMerge, batchSize int) error {
+	return a.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (a accountMergeDo) Save(values ...*model.AccountMerge) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return a.DO.Save(values)
+}
+
+func (a accountMergeDo) First() (*model.AccountMerge, error) {
+	if result, err := a.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AccountMerge), nil
+	}
+}
+
+func (a accountMergeDo) Take() (*model.AccountMerge, error) {
+	if result, err := a.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AccountMerge), nil
+	}
+}
+
+func (a accountMergeDo) Last() (*model.AccountMerge, error) {
+	if result, err := a.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AccountMerge), nil
+	}
+}
+
+func (a accountMergeDo) Find() ([]*model.AccountMerge, error) {
+	result, err := a.DO.Find()
+	return result.([]*model.AccountMerge), err
+}
+
+func (a accountMergeDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.AccountMerge, err error) {
+	buf := make([]*model.AccountMerge, 0, batchSize)
+	err = a.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (a accountMergeDo) FindInBatches(result *[]*model.AccountMerge, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return a.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (a accountMergeDo) Attrs(attrs ...field.AssignExpr) IAccountMergeDo {
+	return a.withDO(a.DO.Attrs(attrs...))
+}
+
+func (a accountMergeDo) Assign(attrs ...field.AssignExpr) IAccountMergeDo {
+	return a.withDO(a.DO.Assign(attrs...))
+}
+
+func (a accountMergeDo) Joins(fields ...field.RelationField) IAccountMergeDo {
+	for _, _f := range fields {
+		a = *a.withDO(a.DO.Joins(_f))
+	}
+	return &a
+}
+
+func (a accountMergeDo) Preload(fields ...field.RelationField) IAccountMergeDo {
+	for _, _f := range fields {
+		a = *a.withDO(a.DO.Preload(_f))
+	}
+	return &a
+}
+
+func (a accountMergeDo) FirstOrInit() (*model.AccountMerge, error) {
+	if result, err := a.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AccountMerge), nil
+	}
+}
+
+func (a accountMergeDo) FirstOrCreate() (*model.AccountMerge, error) {
+	if result, err := a.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AccountMerge), nil
+	}
+}
+
+func (a accountMergeDo) FindByPage(offset int, limit int) (result []*model.AccountMerge, count int64, err error) {
+	result, err = a.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = a.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (a accountMergeDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = a.Count()
+	if err != nil {
+		return
+	}
+
+	err = a.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (a accountMergeDo) Scan(result interface{}) (err error) {
+	return a.DO.Scan(result)
+}
+
+func (a accountMergeDo) Delete(models ...*model.AccountMerge) (result gen.ResultInfo, err error) {
+	return a.DO.Delete(models)
+}
+
+func (a *accountMergeDo) withDO(do gen.Dao) *accountMergeDo {
+	a.DO = *do.(*gen.DO)
+	return a
+}
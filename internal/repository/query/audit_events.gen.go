@@ -0,0 +1,430 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"AreYouOK/internal/model"
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+func newAuditEvent(db *gorm.DB, opts ...gen.DOOption) auditEvent {
+	_auditEvent := auditEvent{}
+
+	_auditEvent.auditEventDo.UseDB(db, opts...)
+	_auditEvent.auditEventDo.UseModel(&model.AuditEvent{})
+
+	tableName := _auditEvent.auditEventDo.TableName()
+	_auditEvent.ALL = field.NewAsterisk(tableName)
+	_auditEvent.OccurredAt = field.NewTime(tableName, "occurred_at")
+	_auditEvent.Action = field.NewString(tableName, "action")
+	_auditEvent.ActorType = field.NewString(tableName, "actor_type")
+	_auditEvent.TargetType = field.NewString(tableName, "target_type")
+	_auditEvent.TargetID = field.NewString(tableName, "target_id")
+	_auditEvent.IP = field.NewString(tableName, "ip")
+	_auditEvent.Device = field.NewString(tableName, "device")
+	_auditEvent.TraceID = field.NewString(tableName, "trace_id")
+	_auditEvent.Diff = field.NewField(tableName, "diff")
+	_auditEvent.ID = field.NewInt64(tableName, "id")
+	_auditEvent.UserID = field.NewInt64(tableName, "user_id")
+	_auditEvent.ActorID = field.NewInt64(tableName, "actor_id")
+	_auditEvent.SessionID = field.NewInt64(tableName, "session_id")
+
+	_auditEvent.fillFieldMap()
+
+	return _auditEvent
+}
+
+type auditEvent struct {
+	auditEventDo
+
+	ALL        field.Asterisk
+	OccurredAt field.Time
+	Action     field.String
+	ActorType  field.String
+	TargetType field.String
+	TargetID   field.String
+	IP         field.String
+	Device     field.String
+	TraceID    field.String
+	Diff       field.Field
+	ID         field.Int64
+	UserID     field.Int64
+	ActorID    field.Int64
+	SessionID  field.Int64
+
+	fieldMap map[string]field.Expr
+}
+
+func (a auditEvent) Table(newTableName string) *auditEvent {
+	a.auditEventDo.UseTable(newTableName)
+	return a.updateTableName(newTableName)
+}
+
+func (a auditEvent) As(alias string) *auditEvent {
+	a.auditEventDo.DO = *(a.auditEventDo.As(alias).(*gen.DO))
+	return a.updateTableName(alias)
+}
+
+func (a *auditEvent) updateTableName(table string) *auditEvent {
+	a.ALL = field.NewAsterisk(table)
+	a.OccurredAt = field.NewTime(table, "occurred_at")
+	a.Action = field.NewString(table, "action")
+	a.ActorType = field.NewString(table, "actor_type")
+	a.TargetType = field.NewString(table, "target_type")
+	a.TargetID = field.NewString(table, "target_id")
+	a.IP = field.NewString(table, "ip")
+	a.Device = field.NewString(table, "device")
+	a.TraceID = field.NewString(table, "trace_id")
+	a.Diff = field.NewField(table, "diff")
+	a.ID = field.NewInt64(table, "id")
+	a.UserID = field.NewInt64(table, "user_id")
+	a.ActorID = field.NewInt64(table, "actor_id")
+	a.SessionID = field.NewInt64(table, "session_id")
+
+	a.fillFieldMap()
+
+	return a
+}
+
+func (a *auditEvent) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := a.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (a *auditEvent) fillFieldMap() {
+	a.fieldMap = make(map[string]field.Expr, 13)
+	a.fieldMap["occurred_at"] = a.OccurredAt
+	a.fieldMap["action"] = a.Action
+	a.fieldMap["actor_type"] = a.ActorType
+	a.fieldMap["target_type"] = a.TargetType
+	a.fieldMap["target_id"] = a.TargetID
+	a.fieldMap["ip"] = a.IP
+	a.fieldMap["device"] = a.Device
+	a.fieldMap["trace_id"] = a.TraceID
+	a.fieldMap["diff"] = a.Diff
+	a.fieldMap["id"] = a.ID
+	a.fieldMap["user_id"] = a.UserID
+	a.fieldMap["actor_id"] = a.ActorID
+	a.fieldMap["session_id"] = a.SessionID
+}
+
+func (a auditEvent) clone(db *gorm.DB) auditEvent {
+	a.auditEventDo.ReplaceConnPool(db.Statement.ConnPool)
+	return a
+}
+
+func (a auditEvent) replaceDB(db *gorm.DB) auditEvent {
+	a.auditEventDo.ReplaceDB(db)
+	return a
+}
+
+type auditEventDo struct{ gen.DO }
+
+type IAuditEventDo interface {
+	gen.SubQuery
+	Debug() IAuditEventDo
+	WithContext(ctx context.Context) IAuditEventDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IAuditEventDo
+	WriteDB() IAuditEventDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IAuditEventDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IAuditEventDo
+	Not(conds ...gen.Condition) IAuditEventDo
+	Or(conds ...gen.Condition) IAuditEventDo
+	Select(conds ...field.Expr) IAuditEventDo
+	Where(conds ...gen.Condition) IAuditEventDo
+	Order(conds ...field.Expr) IAuditEventDo
+	Distinct(cols ...field.Expr) IAuditEventDo
+	Omit(cols ...field.Expr) IAuditEventDo
+	Join(table schema.Tabler, on ...field.Expr) IAuditEventDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IAuditEventDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IAuditEventDo
+	Group(cols ...field.Expr) IAuditEventDo
+	Having(conds ...gen.Condition) IAuditEventDo
+	Limit(limit int) IAuditEventDo
+	Offset(offset int) IAuditEventDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IAuditEventDo
+	Unscoped() IAuditEventDo
+	Create(values ...*model.AuditEvent) error
+	CreateInBatches(values []*model.AuditEvent, batchSize int) error
+	Save(values ...*model.AuditEvent) error
+	First() (*model.AuditEvent, error)
+	Take() (*model.AuditEvent, error)
+	Last() (*model.AuditEvent, error)
+	Find() ([]*model.AuditEvent, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.AuditEvent, err error)
+	FindInBatches(result *[]*model.AuditEvent, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.AuditEvent) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IAuditEventDo
+	Assign(attrs ...field.AssignExpr) IAuditEventDo
+	Joins(fields ...field.RelationField) IAuditEventDo
+	Preload(fields ...field.RelationField) IAuditEventDo
+	FirstOrInit() (*model.AuditEvent, error)
+	FirstOrCreate() (*model.AuditEvent, error)
+	FindByPage(offset int, limit int) (result []*model.AuditEvent, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IAuditEventDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (a auditEventDo) Debug() IAuditEventDo {
+	return a.withDO(a.DO.Debug())
+}
+
+func (a auditEventDo) WithContext(ctx context.Context) IAuditEventDo {
+	return a.withDO(a.DO.WithContext(ctx))
+}
+
+func (a auditEventDo) ReadDB() IAuditEventDo {
+	return a.Clauses(dbresolver.Read)
+}
+
+func (a auditEventDo) WriteDB() IAuditEventDo {
+	return a.Clauses(dbresolver.Write)
+}
+
+func (a auditEventDo) Session(config *gorm.Session) IAuditEventDo {
+	return a.withDO(a.DO.Session(config))
+}
+
+func (a auditEventDo) Clauses(conds ...clause.Expression) IAuditEventDo {
+	return a.withDO(a.DO.Clauses(conds...))
+}
+
+func (a auditEventDo) Returning(value interface{}, columns ...string) IAuditEventDo {
+	return a.withDO(a.DO.Returning(value, columns...))
+}
+
+func (a auditEventDo) Not(conds ...gen.Condition) IAuditEventDo {
+	return a.withDO(a.DO.Not(conds...))
+}
+
+func (a auditEventDo) Or(conds ...gen.Condition) IAuditEventDo {
+	return a.withDO(a.DO.Or(conds...))
+}
+
+func (a auditEventDo) Select(conds ...field.Expr) IAuditEventDo {
+	return a.withDO(a.DO.Select(conds...))
+}
+
+func (a auditEventDo) Where(conds ...gen.Condition) IAuditEventDo {
+	return a.withDO(a.DO.Where(conds...))
+}
+
+func (a auditEventDo) Order(conds ...field.Expr) IAuditEventDo {
+	return a.withDO(a.DO.Order(conds...))
+}
+
+func (a auditEventDo) Distinct(cols ...field.Expr) IAuditEventDo {
+	return a.withDO(a.DO.Distinct(cols...))
+}
+
+func (a auditEventDo) Omit(cols ...field.Expr) IAuditEventDo {
+	return a.withDO(a.DO.Omit(cols...))
+}
+
+func (a auditEventDo) Join(table schema.Tabler, on ...field.Expr) IAuditEventDo {
+	return a.withDO(a.DO.Join(table, on...))
+}
+
+func (a auditEventDo) LeftJoin(table schema.Tabler, on ...field.Expr) IAuditEventDo {
+	return a.withDO(a.DO.LeftJoin(table, on...))
+}
+
+func (a auditEventDo) RightJoin(table schema.Tabler, on ...field.Expr) IAuditEventDo {
+	return a.withDO(a.DO.RightJoin(table, on...))
+}
+
+func (a auditEventDo) Group(cols ...field.Expr) IAuditEventDo {
+	return a.withDO(a.DO.Group(cols...))
+}
+
+func (a auditEventDo) Having(conds ...gen.Condition) IAuditEventDo {
+	return a.withDO(a.DO.Having(conds...))
+}
+
+func (a auditEventDo) Limit(limit int) IAuditEventDo {
+	return a.withDO(a.DO.Limit(limit))
+}
+
+func (a auditEventDo) Offset(offset int) IAuditEventDo {
+	return a.withDO(a.DO.Offset(offset))
+}
+
+func (a auditEventDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IAuditEventDo {
+	return a.withDO(a.DO.Scopes(funcs...))
+}
+
+func (a auditEventDo) Unscoped() IAuditEventDo {
+	return a.withDO(a.DO.Unscoped())
+}
+
+func (a auditEventDo) Create(values ...*model.AuditEvent) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return a.DO.Create(values)
+}
+
+func (a auditEventDo) CreateInBatches(values []*model.AuditEvent, batchSize int) error {
+	return a.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (a auditEventDo) Save(values ...*model.AuditEvent) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return a.DO.Save(values)
+}
+
+func (a auditEventDo) First() (*model.AuditEvent, error) {
+	if result, err := a.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AuditEvent), nil
+	}
+}
+
+func (a auditEventDo) Take() (*model.AuditEvent, error) {
+	if result, err := a.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AuditEvent), nil
+	}
+}
+
+func (a auditEventDo) Last() (*model.AuditEvent, error) {
+	if result, err := a.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AuditEvent), nil
+	}
+}
+
+func (a auditEventDo) Find() ([]*model.AuditEvent, error) {
+	result, err := a.DO.Find()
+	return result.([]*model.AuditEvent), err
+}
+
+func (a auditEventDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.AuditEvent, err error) {
+	buf := make([]*model.AuditEvent, 0, batchSize)
+	err = a.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (a auditEventDo) FindInBatches(result *[]*model.AuditEvent, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return a.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (a auditEventDo) Attrs(attrs ...field.AssignExpr) IAuditEventDo {
+	return a.withDO(a.DO.Attrs(attrs...))
+}
+
+func (a auditEventDo) Assign(attrs ...field.AssignExpr) IAuditEventDo {
+	return a.withDO(a.DO.Assign(attrs...))
+}
+
+func (a auditEventDo) Joins(fields ...field.RelationField) IAuditEventDo {
+	for _, _f := range fields {
+		a = *a.withDO(a.DO.Joins(_f))
+	}
+	return &a
+}
+
+func (a auditEventDo) Preload(fields ...field.RelationField) IAuditEventDo {
+	for _, _f := range fields {
+		a = *a.withDO(a.DO.Preload(_f))
+	}
+	return &a
+}
+
+func (a auditEventDo) FirstOrInit() (*model.AuditEvent, error) {
+	if result, err := a.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AuditEvent), nil
+	}
+}
+
+func (a auditEventDo) FirstOrCreate() (*model.AuditEvent, error) {
+	if result, err := a.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AuditEvent), nil
+	}
+}
+
+func (a auditEventDo) FindByPage(offset int, limit int) (result []*model.AuditEvent, count int64, err error) {
+	result, err = a.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = a.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (a auditEventDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = a.Count()
+	if err != nil {
+		return
+	}
+
+	err = a.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (a auditEventDo) Scan(result interface{}) (err error) {
+	return a.DO.Scan(result)
+}
+
+func (a auditEventDo) Delete(models ...*model.AuditEvent) (result gen.ResultInfo, err error) {
+	return a.DO.Delete(models)
+}
+
+func (a *auditEventDo) withDO(do gen.Dao) *auditEventDo {
+	a.DO = *do.(*gen.DO)
+	return a
+}
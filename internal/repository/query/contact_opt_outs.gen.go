@@ -0,0 +1,418 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"AreYouOK/internal/model"
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+func newContactOptOut(db *gorm.DB, opts ...gen.DOOption) contactOptOut {
+	_contactOptOut := contactOptOut{}
+
+	_contactOptOut.contactOptOutDo.UseDB(db, opts...)
+	_contactOptOut.contactOptOutDo.UseModel(&model.ContactOptOut{})
+
+	tableName := _contactOptOut.contactOptOutDo.TableName()
+	_contactOptOut.ALL = field.NewAsterisk(tableName)
+	_contactOptOut.OptedOutAt = field.NewTime(tableName, "opted_out_at")
+	_contactOptOut.PhoneHash = field.NewString(tableName, "phone_hash")
+	_contactOptOut.PhoneCipher = field.NewBytes(tableName, "phone_cipher")
+	_contactOptOut.Source = field.NewString(tableName, "source")
+	_contactOptOut.Keyword = field.NewString(tableName, "keyword")
+	_contactOptOut.CreatedAt = field.NewTime(tableName, "created_at")
+	_contactOptOut.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_contactOptOut.DeletedAt = field.NewField(tableName, "deleted_at")
+	_contactOptOut.ID = field.NewInt64(tableName, "id")
+	_contactOptOut.PhoneHashVersion = field.NewInt(tableName, "phone_hash_version")
+
+	_contactOptOut.fillFieldMap()
+
+	return _contactOptOut
+}
+
+type contactOptOut struct {
+	contactOptOutDo
+
+	ALL              field.Asterisk
+	OptedOutAt       field.Time
+	PhoneHash        field.String
+	PhoneCipher      field.Bytes
+	Source           field.String
+	Keyword          field.String
+	CreatedAt        field.Time
+	UpdatedAt        field.Time
+	DeletedAt        field.Field
+	ID               field.Int64
+	PhoneHashVersion field.Int
+
+	fieldMap map[string]field.Expr
+}
+
+func (c contactOptOut) Table(newTableName string) *contactOptOut {
+	c.contactOptOutDo.UseTable(newTableName)
+	return c.updateTableName(newTableName)
+}
+
+func (c contactOptOut) As(alias string) *contactOptOut {
+	c.contactOptOutDo.DO = *(c.contactOptOutDo.As(alias).(*gen.DO))
+	return c.updateTableName(alias)
+}
+
+func (c *contactOptOut) updateTableName(table string) *contactOptOut {
+	c.ALL = field.NewAsterisk(table)
+	c.OptedOutAt = field.NewTime(table, "opted_out_at")
+	c.PhoneHash = field.NewString(table, "phone_hash")
+	c.PhoneCipher = field.NewBytes(table, "phone_cipher")
+	c.Source = field.NewString(table, "source")
+	c.Keyword = field.NewString(table, "keyword")
+	c.CreatedAt = field.NewTime(table, "created_at")
+	c.UpdatedAt = field.NewTime(table, "updated_at")
+	c.DeletedAt = field.NewField(table, "deleted_at")
+	c.ID = field.NewInt64(table, "id")
+	c.PhoneHashVersion = field.NewInt(table, "phone_hash_version")
+
+	c.fillFieldMap()
+
+	return c
+}
+
+func (c *contactOptOut) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := c.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (c *contactOptOut) fillFieldMap() {
+	c.fieldMap = make(map[string]field.Expr, 10)
+	c.fieldMap["opted_out_at"] = c.OptedOutAt
+	c.fieldMap["phone_hash"] = c.PhoneHash
+	c.fieldMap["phone_cipher"] = c.PhoneCipher
+	c.fieldMap["source"] = c.Source
+	c.fieldMap["keyword"] = c.Keyword
+	c.fieldMap["created_at"] = c.CreatedAt
+	c.fieldMap["updated_at"] = c.UpdatedAt
+	c.fieldMap["deleted_at"] = c.DeletedAt
+	c.fieldMap["id"] = c.ID
+	c.fieldMap["phone_hash_version"] = c.PhoneHashVersion
+}
+
+func (c contactOptOut) clone(db *gorm.DB) contactOptOut {
+	c.contactOptOutDo.ReplaceConnPool(db.Statement.ConnPool)
+	return c
+}
+
+func (c contactOptOut) replaceDB(db *gorm.DB) contactOptOut {
+	c.contactOptOutDo.ReplaceDB(db)
+	return c
+}
+
+type contactOptOutDo struct{ gen.DO }
+
+type IContactOptOutDo interface {
+	gen.SubQuery
+	Debug() IContactOptOutDo
+	WithContext(ctx context.Context) IContactOptOutDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IContactOptOutDo
+	WriteDB() IContactOptOutDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IContactOptOutDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IContactOptOutDo
+	Not(conds ...gen.Condition) IContactOptOutDo
+	Or(conds ...gen.Condition) IContactOptOutDo
+	Select(conds ...field.Expr) IContactOptOutDo
+	Where(conds ...gen.Condition) IContactOptOutDo
+	Order(conds ...field.Expr) IContactOptOutDo
+	Distinct(cols ...field.Expr) IContactOptOutDo
+	Omit(cols ...field.Expr) IContactOptOutDo
+	Join(table schema.Tabler, on ...field.Expr) IContactOptOutDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IContactOptOutDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IContactOptOutDo
+	Group(cols ...field.Expr) IContactOptOutDo
+	Having(conds ...gen.Condition) IContactOptOutDo
+	Limit(limit int) IContactOptOutDo
+	Offset(offset int) IContactOptOutDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IContactOptOutDo
+	Unscoped() IContactOptOutDo
+	Create(values ...*model.ContactOptOut) error
+	CreateInBatches(values []*model.ContactOptOut, batchSize int) error
+	Save(values ...*model.ContactOptOut) error
+	First() (*model.ContactOptOut, error)
+	Take() (*model.ContactOptOut, error)
+	Last() (*model.ContactOptOut, error)
+	Find() ([]*model.ContactOptOut, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.ContactOptOut, err error)
+	FindInBatches(result *[]*model.ContactOptOut, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.ContactOptOut) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IContactOptOutDo
+	Assign(attrs ...field.AssignExpr) IContactOptOutDo
+	Joins(fields ...field.RelationField) IContactOptOutDo
+	Preload(fields ...field.RelationField) IContactOptOutDo
+	FirstOrInit() (*model.ContactOptOut, error)
+	FirstOrCreate() (*model.ContactOptOut, error)
+	FindByPage(offset int, limit int) (result []*model.ContactOptOut, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IContactOptOutDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (c contactOptOutDo) Debug() IContactOptOutDo {
+	return c.withDO(c.DO.Debug())
+}
+
+func (c contactOptOutDo) WithContext(ctx context.Context) IContactOptOutDo {
+	return c.withDO(c.DO.WithContext(ctx))
+}
+
+func (c contactOptOutDo) ReadDB() IContactOptOutDo {
+	return c.Clauses(dbresolver.Read)
+}
+
+func (c contactOptOutDo) WriteDB() IContactOptOutDo {
+	return c.Clauses(dbresolver.Write)
+}
+
+func (c contactOptOutDo) Session(config *gorm.Session) IContactOptOutDo {
+	return c.withDO(c.DO.Session(config))
+}
+
+func (c contactOptOutDo) Clauses(conds ...clause.Expression) IContactOptOutDo {
+	return c.withDO(c.DO.Clauses(conds...))
+}
+
+func (c contactOptOutDo) Returning(value interface{}, columns ...string) IContactOptOutDo {
+	return c.withDO(c.DO.Returning(value, columns...))
+}
+
+func (c contactOptOutDo) Not(conds ...gen.Condition) IContactOptOutDo {
+	return c.withDO(c.DO.Not(conds...))
+}
+
+func (c contactOptOutDo) Or(conds ...gen.Condition) IContactOptOutDo {
+	return c.withDO(c.DO.Or(conds...))
+}
+
+func (c contactOptOutDo) Select(conds ...field.Expr) IContactOptOutDo {
+	return c.withDO(c.DO.Select(conds...))
+}
+
+func (c contactOptOutDo) Where(conds ...gen.Condition) IContactOptOutDo {
+	return c.withDO(c.DO.Where(conds...))
+}
+
+func (c contactOptOutDo) Order(conds ...field.Expr) IContactOptOutDo {
+	return c.withDO(c.DO.Order(conds...))
+}
+
+func (c contactOptOutDo) Distinct(cols ...field.Expr) IContactOptOutDo {
+	return c.withDO(c.DO.Distinct(cols...))
+}
+
+func (c contactOptOutDo) Omit(cols ...field.Expr) IContactOptOutDo {
+	return c.withDO(c.DO.Omit(cols...))
+}
+
+func (c contactOptOutDo) Join(table schema.Tabler, on ...field.Expr) IContactOptOutDo {
+	return c.withDO(c.DO.Join(table, on...))
+}
+
+func (c contactOptOutDo) LeftJoin(table schema.Tabler, on ...field.Expr) IContactOptOutDo {
+	return c.withDO(c.DO.LeftJoin(table, on...))
+}
+
+func (c contactOptOutDo) RightJoin(table schema.Tabler, on ...field.Expr) IContactOptOutDo {
+	return c.withDO(c.DO.RightJoin(table, on...))
+}
+
+func (c contactOptOutDo) Group(cols ...field.Expr) IContactOptOutDo {
+	return c.withDO(c.DO.Group(cols...))
+}
+
+func (c contactOptOutDo) Having(conds ...gen.Condition) IContactOptOutDo {
+	return c.withDO(c.DO.Having(conds...))
+}
+
+func (c contactOptOutDo) Limit(limit int) IContactOptOutDo {
+	return c.withDO(c.DO.Limit(limit))
+}
+
+func (c contactOptOutDo) Offset(offset int) IContactOptOutDo {
+	return c.withDO(c.DO.Offset(offset))
+}
+
+func (c contactOptOutDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IContactOptOutDo {
+	return c.withDO(c.DO.Scopes(funcs...))
+}
+
+func (c contactOptOutDo) Unscoped() IContactOptOutDo {
+	return c.withDO(c.DO.Unscoped())
+}
+
+func (c contactOptOutDo) Create(values ...*model.ContactOptOut) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return c.DO.Create(values)
+}
+
+func (c contactOptOutDo) CreateInBatches(values []*model.ContactOptOut, batchSize int) error {
+	return c.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (c contactOptOutDo) Save(values ...*model.ContactOptOut) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return c.DO.Save(values)
+}
+
+func (c contactOptOutDo) First() (*model.ContactOptOut, error) {
+	if result, err := c.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.ContactOptOut), nil
+	}
+}
+
+func (c contactOptOutDo) Take() (*model.ContactOptOut, error) {
+	if result, err := c.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.ContactOptOut), nil
+	}
+}
+
+func (c contactOptOutDo) Last() (*model.ContactOptOut, error) {
+	if result, err := c.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.ContactOptOut), nil
+	}
+}
+
+func (c contactOptOutDo) Find() ([]*model.ContactOptOut, error) {
+	result, err := c.DO.Find()
+	return result.([]*model.ContactOptOut), err
+}
+
+func (c contactOptOutDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.ContactOptOut, err error) {
+	buf := make([]*model.ContactOptOut, 0, batchSize)
+	err = c.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (c contactOptOutDo) FindInBatches(result *[]*model.ContactOptOut, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return c.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (c contactOptOutDo) Attrs(attrs ...field.AssignExpr) IContactOptOutDo {
+	return c.withDO(c.DO.Attrs(attrs...))
+}
+
+func (c contactOptOutDo) Assign(attrs ...field.AssignExpr) IContactOptOutDo {
+	return c.withDO(c.DO.Assign(attrs...))
+}
+
+func (c contactOptOutDo) Joins(fields ...field.RelationField) IContactOptOutDo {
+	for _, _f := range fields {
+		c = *c.withDO(c.DO.Joins(_f))
+	}
+	return &c
+}
+
+func (c contactOptOutDo) Preload(fields ...field.RelationField) IContactOptOutDo {
+	for _, _f := range fields {
+		c = *c.withDO(c.DO.Preload(_f))
+	}
+	return &c
+}
+
+func (c contactOptOutDo) FirstOrInit() (*model.ContactOptOut, error) {
+	if result, err := c.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.ContactOptOut), nil
+	}
+}
+
+func (c contactOptOutDo) FirstOrCreate() (*model.ContactOptOut, error) {
+	if result, err := c.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.ContactOptOut), nil
+	}
+}
+
+func (c contactOptOutDo) FindByPage(offset int, limit int) (result []*model.ContactOptOut, count int64, err error) {
+	result, err = c.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = c.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (c contactOptOutDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = c.Count()
+	if err != nil {
+		return
+	}
+
+	err = c.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (c contactOptOutDo) Scan(result interface{}) (err error) {
+	return c.DO.Scan(result)
+}
+
+func (c contactOptOutDo) Delete(models ...*model.ContactOptOut) (result gen.ResultInfo, err error) {
+	return c.DO.Delete(models)
+}
+
+func (c *contactOptOutDo) withDO(do gen.Dao) *contactOptOutDo {
+	c.DO = *do.(*gen.DO)
+	return c
+}
@@ -0,0 +1,434 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"AreYouOK/internal/model"
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+func newDataExport(db *gorm.DB, opts ...gen.DOOption) dataExport {
+	_dataExport := dataExport{}
+
+	_dataExport.dataExportDo.UseDB(db, opts...)
+	_dataExport.dataExportDo.UseModel(&model.DataExport{})
+
+	tableName := _dataExport.dataExportDo.TableName()
+	_dataExport.ALL = field.NewAsterisk(tableName)
+	_dataExport.RequestedAt = field.NewTime(tableName, "requested_at")
+	_dataExport.ReadyAt = field.NewTime(tableName, "ready_at")
+	_dataExport.ExpiresAt = field.NewTime(tableName, "expires_at")
+	_dataExport.DownloadedAt = field.NewTime(tableName, "downloaded_at")
+	_dataExport.Status = field.NewString(tableName, "status")
+	_dataExport.ErrorMessage = field.NewString(tableName, "error_message")
+	_dataExport.CreatedAt = field.NewTime(tableName, "created_at")
+	_dataExport.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_dataExport.DeletedAt = field.NewField(tableName, "deleted_at")
+	_dataExport.ID = field.NewInt64(tableName, "id")
+	_dataExport.UserID = field.NewInt64(tableName, "user_id")
+	_dataExport.ExportCode = field.NewInt64(tableName, "export_code")
+	_dataExport.SizeBytes = field.NewInt(tableName, "size_bytes")
+	_dataExport.FullContacts = field.NewBool(tableName, "full_contacts")
+
+	_dataExport.fillFieldMap()
+
+	return _dataExport
+}
+
+type dataExport struct {
+	dataExportDo
+
+	ALL          field.Asterisk
+	RequestedAt  field.Time
+	ReadyAt      field.Time
+	ExpiresAt    field.Time
+	DownloadedAt field.Time
+	Status       field.String
+	ErrorMessage field.String
+	CreatedAt    field.Time
+	UpdatedAt    field.Time
+	DeletedAt    field.Field
+	ID           field.Int64
+	UserID       field.Int64
+	ExportCode   field.Int64
+	SizeBytes    field.Int
+	FullContacts field.Bool
+
+	fieldMap map[string]field.Expr
+}
+
+func (d dataExport) Table(newTableName string) *dataExport {
+	d.dataExportDo.UseTable(newTableName)
+	return d.updateTableName(newTableName)
+}
+
+func (d dataExport) As(alias string) *dataExport {
+	d.dataExportDo.DO = *(d.dataExportDo.As(alias).(*gen.DO))
+	return d.updateTableName(alias)
+}
+
+func (d *dataExport) updateTableName(table string) *dataExport {
+	d.ALL = field.NewAsterisk(table)
+	d.RequestedAt = field.NewTime(table, "requested_at")
+	d.ReadyAt = field.NewTime(table, "ready_at")
+	d.ExpiresAt = field.NewTime(table, "expires_at")
+	d.DownloadedAt = field.NewTime(table, "downloaded_at")
+	d.Status = field.NewString(table, "status")
+	d.ErrorMessage = field.NewString(table, "error_message")
+	d.CreatedAt = field.NewTime(table, "created_at")
+	d.UpdatedAt = field.NewTime(table, "updated_at")
+	d.DeletedAt = field.NewField(table, "deleted_at")
+	d.ID = field.NewInt64(table, "id")
+	d.UserID = field.NewInt64(table, "user_id")
+	d.ExportCode = field.NewInt64(table, "export_code")
+	d.SizeBytes = field.NewInt(table, "size_bytes")
+	d.FullContacts = field.NewBool(table, "full_contacts")
+
+	d.fillFieldMap()
+
+	return d
+}
+
+func (d *dataExport) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := d.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (d *dataExport) fillFieldMap() {
+	d.fieldMap = make(map[string]field.Expr, 14)
+	d.fieldMap["requested_at"] = d.RequestedAt
+	d.fieldMap["ready_at"] = d.ReadyAt
+	d.fieldMap["expires_at"] = d.ExpiresAt
+	d.fieldMap["downloaded_at"] = d.DownloadedAt
+	d.fieldMap["status"] = d.Status
+	d.fieldMap["error_message"] = d.ErrorMessage
+	d.fieldMap["created_at"] = d.CreatedAt
+	d.fieldMap["updated_at"] = d.UpdatedAt
+	d.fieldMap["deleted_at"] = d.DeletedAt
+	d.fieldMap["id"] = d.ID
+	d.fieldMap["user_id"] = d.UserID
+	d.fieldMap["export_code"] = d.ExportCode
+	d.fieldMap["size_bytes"] = d.SizeBytes
+	d.fieldMap["full_contacts"] = d.FullContacts
+}
+
+func (d dataExport) clone(db *gorm.DB) dataExport {
+	d.dataExportDo.ReplaceConnPool(db.Statement.ConnPool)
+	return d
+}
+
+func (d dataExport) replaceDB(db *gorm.DB) dataExport {
+	d.dataExportDo.ReplaceDB(db)
+	return d
+}
+
+type dataExportDo struct{ gen.DO }
+
+type IDataExportDo interface {
+	gen.SubQuery
+	Debug() IDataExportDo
+	WithContext(ctx context.Context) IDataExportDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IDataExportDo
+	WriteDB() IDataExportDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IDataExportDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IDataExportDo
+	Not(conds ...gen.Condition) IDataExportDo
+	Or(conds ...gen.Condition) IDataExportDo
+	Select(conds ...field.Expr) IDataExportDo
+	Where(conds ...gen.Condition) IDataExportDo
+	Order(conds ...field.Expr) IDataExportDo
+	Distinct(cols ...field.Expr) IDataExportDo
+	Omit(cols ...field.Expr) IDataExportDo
+	Join(table schema.Tabler, on ...field.Expr) IDataExportDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IDataExportDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IDataExportDo
+	Group(cols ...field.Expr) IDataExportDo
+	Having(conds ...gen.Condition) IDataExportDo
+	Limit(limit int) IDataExportDo
+	Offset(offset int) IDataExportDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IDataExportDo
+	Unscoped() IDataExportDo
+	Create(values ...*model.DataExport) error
+	CreateInBatches(values []*model.DataExport, batchSize int) error
+	Save(values ...*model.DataExport) error
+	First() (*model.DataExport, error)
+	Take() (*model.DataExport, error)
+	Last() (*model.DataExport, error)
+	Find() ([]*model.DataExport, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.DataExport, err error)
+	FindInBatches(result *[]*model.DataExport, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.DataExport) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IDataExportDo
+	Assign(attrs ...field.AssignExpr) IDataExportDo
+	Joins(fields ...field.RelationField) IDataExportDo
+	Preload(fields ...field.RelationField) IDataExportDo
+	FirstOrInit() (*model.DataExport, error)
+	FirstOrCreate() (*model.DataExport, error)
+	FindByPage(offset int, limit int) (result []*model.DataExport, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IDataExportDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (d dataExportDo) Debug() IDataExportDo {
+	return d.withDO(d.DO.Debug())
+}
+
+func (d dataExportDo) WithContext(ctx context.Context) IDataExportDo {
+	return d.withDO(d.DO.WithContext(ctx))
+}
+
+func (d dataExportDo) ReadDB() IDataExportDo {
+	return d.Clauses(dbresolver.Read)
+}
+
+func (d dataExportDo) WriteDB() IDataExportDo {
+	return d.Clauses(dbresolver.Write)
+}
+
+func (d dataExportDo) Session(config *gorm.Session) IDataExportDo {
+	return d.withDO(d.DO.Session(config))
+}
+
+func (d dataExportDo) Clauses(conds ...clause.Expression) IDataExportDo {
+	return d.withDO(d.DO.Clauses(conds...))
+}
+
+func (d dataExportDo) Returning(value interface{}, columns ...string) IDataExportDo {
+	return d.withDO(d.DO.Returning(value, columns...))
+}
+
+func (d dataExportDo) Not(conds ...gen.Condition) IDataExportDo {
+	return d.withDO(d.DO.Not(conds...))
+}
+
+func (d dataExportDo) Or(conds ...gen.Condition) IDataExportDo {
+	return d.withDO(d.DO.Or(conds...))
+}
+
+func (d dataExportDo) Select(conds ...field.Expr) IDataExportDo {
+	return d.withDO(d.DO.Select(conds...))
+}
+
+func (d dataExportDo) Where(conds ...gen.Condition) IDataExportDo {
+	return d.withDO(d.DO.Where(conds...))
+}
+
+func (d dataExportDo) Order(conds ...field.Expr) IDataExportDo {
+	return d.withDO(d.DO.Order(conds...))
+}
+
+func (d dataExportDo) Distinct(cols ...field.Expr) IDataExportDo {
+	return d.withDO(d.DO.Distinct(cols...))
+}
+
+func (d dataExportDo) Omit(cols ...field.Expr) IDataExportDo {
+	return d.withDO(d.DO.Omit(cols...))
+}
+
+func (d dataExportDo) Join(table schema.Tabler, on ...field.Expr) IDataExportDo {
+	return d.withDO(d.DO.Join(table, on...))
+}
+
+func (d dataExportDo) LeftJoin(table schema.Tabler, on ...field.Expr) IDataExportDo {
+	return d.withDO(d.DO.LeftJoin(table, on...))
+}
+
+func (d dataExportDo) RightJoin(table schema.Tabler, on ...field.Expr) IDataExportDo {
+	return d.withDO(d.DO.RightJoin(table, on...))
+}
+
+func (d dataExportDo) Group(cols ...field.Expr) IDataExportDo {
+	return d.withDO(d.DO.Group(cols...))
+}
+
+func (d dataExportDo) Having(conds ...gen.Condition) IDataExportDo {
+	return d.withDO(d.DO.Having(conds...))
+}
+
+func (d dataExportDo) Limit(limit int) IDataExportDo {
+	return d.withDO(d.DO.Limit(limit))
+}
+
+func (d dataExportDo) Offset(offset int) IDataExportDo {
+	return d.withDO(d.DO.Offset(offset))
+}
+
+func (d dataExportDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IDataExportDo {
+	return d.withDO(d.DO.Scopes(funcs...))
+}
+
+func (d dataExportDo) Unscoped() IDataExportDo {
+	return d.withDO(d.DO.Unscoped())
+}
+
+func (d dataExportDo) Create(values ...*model.DataExport) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return d.DO.Create(values)
+}
+
+func (d dataExportDo) CreateInBatches(values []*model.DataExport, batchSize int) error {
+	return d.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (d dataExportDo) Save(values ...*model.DataExport) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return d.DO.Save(values)
+}
+
+func (d dataExportDo) First() (*model.DataExport, error) {
+	if result, err := d.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.DataExport), nil
+	}
+}
+
+func (d dataExportDo) Take() (*model.DataExport, error) {
+	if result, err := d.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.DataExport), nil
+	}
+}
+
+func (d dataExportDo) Last() (*model.DataExport, error) {
+	if result, err := d.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.DataExport), nil
+	}
+}
+
+func (d dataExportDo) Find() ([]*model.DataExport, error) {
+	result, err := d.DO.Find()
+	return result.([]*model.DataExport), err
+}
+
+func (d dataExportDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.DataExport, err error) {
+	buf := make([]*model.DataExport, 0, batchSize)
+	err = d.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (d dataExportDo) FindInBatches(result *[]*model.DataExport, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return d.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (d dataExportDo) Attrs(attrs ...field.AssignExpr) IDataExportDo {
+	return d.withDO(d.DO.Attrs(attrs...))
+}
+
+func (d dataExportDo) Assign(attrs ...field.AssignExpr) IDataExportDo {
+	return d.withDO(d.DO.Assign(attrs...))
+}
+
+func (d dataExportDo) Joins(fields ...field.RelationField) IDataExportDo {
+	for _, _f := range fields {
+		d = *d.withDO(d.DO.Joins(_f))
+	}
+	return &d
+}
+
+func (d dataExportDo) Preload(fields ...field.RelationField) IDataExportDo {
+	for _, _f := range fields {
+		d = *d.withDO(d.DO.Preload(_f))
+	}
+	return &d
+}
+
+func (d dataExportDo) FirstOrInit() (*model.DataExport, error) {
+	if result, err := d.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.DataExport), nil
+	}
+}
+
+func (d dataExportDo) FirstOrCreate() (*model.DataExport, error) {
+	if result, err := d.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.DataExport), nil
+	}
+}
+
+func (d dataExportDo) FindByPage(offset int, limit int) (result []*model.DataExport, count int64, err error) {
+	result, err = d.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = d.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (d dataExportDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = d.Count()
+	if err != nil {
+		return
+	}
+
+	err = d.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (d dataExportDo) Scan(result interface{}) (err error) {
+	return d.DO.Scan(result)
+}
+
+func (d dataExportDo) Delete(models ...*model.DataExport) (result gen.ResultInfo, err error) {
+	return d.DO.Delete(models)
+}
+
+func (d *dataExportDo) withDO(do gen.Dao) *dataExportDo {
+	d.DO = *do.(*gen.DO)
+	return d
+}
@@ -16,64 +16,169 @@ import (
 )
 
 var (
-	Q                = new(Query)
-	ContactAttempt   *contactAttempt
-	DailyCheckIn     *dailyCheckIn
-	Journey          *journey
-	NotificationTask *notificationTask
-	QuotaTransaction *quotaTransaction
-	QuotaWallet      *quotaWallet
-	User             *user
+	Q                     = new(Query)
+	AccountErasure        *accountErasure
+	AccountMerge          *accountMerge
+	AuditEvent            *auditEvent
+	Contact               *contact
+	ContactAttempt        *contactAttempt
+	ContactOptOut         *contactOptOut
+	DailyCheckIn          *dailyCheckIn
+	DataExport            *dataExport
+	Journey               *journey
+	JourneyCheckpoint     *journeyCheckpoint
+	JourneyEvent          *journeyEvent
+	JourneyLocation       *journeyLocation
+	JourneyOccurrence     *journeyOccurrence
+	JourneyTemplate       *journeyTemplate
+	NotificationTask      *notificationTask
+	PhoneChangeLog        *phoneChangeLog
+	QuotaTransaction      *quotaTransaction
+	QuotaWallet           *quotaWallet
+	RedeemCode            *redeemCode
+	RedeemCodeBatch       *redeemCodeBatch
+	RedeemRecord          *redeemRecord
+	Subscription          *subscription
+	User                  *user
+	UserIdentity          *userIdentity
+	UserSession           *userSession
+	WalletGroup           *walletGroup
+	WalletGroupInvitation *walletGroupInvitation
+	WalletGroupMember     *walletGroupMember
 )
 
 func SetDefault(db *gorm.DB, opts ...gen.DOOption) {
 	*Q = *Use(db, opts...)
+	AccountErasure = &Q.AccountErasure
+	AccountMerge = &Q.AccountMerge
+	AuditEvent = &Q.AuditEvent
+	Contact = &Q.Contact
 	ContactAttempt = &Q.ContactAttempt
+	ContactOptOut = &Q.ContactOptOut
 	DailyCheckIn = &Q.DailyCheckIn
+	DataExport = &Q.DataExport
 	Journey = &Q.Journey
+	JourneyCheckpoint = &Q.JourneyCheckpoint
+	JourneyEvent = &Q.JourneyEvent
+	JourneyLocation = &Q.JourneyLocation
+	JourneyOccurrence = &Q.JourneyOccurrence
+	JourneyTemplate = &Q.JourneyTemplate
 	NotificationTask = &Q.NotificationTask
+	PhoneChangeLog = &Q.PhoneChangeLog
 	QuotaTransaction = &Q.QuotaTransaction
 	QuotaWallet = &Q.QuotaWallet
+	RedeemCode = &Q.RedeemCode
+	RedeemCodeBatch = &Q.RedeemCodeBatch
+	RedeemRecord = &Q.RedeemRecord
+	Subscription = &Q.Subscription
 	User = &Q.User
+	UserIdentity = &Q.UserIdentity
+	UserSession = &Q.UserSession
+	WalletGroup = &Q.WalletGroup
+	WalletGroupInvitation = &Q.WalletGroupInvitation
+	WalletGroupMember = &Q.WalletGroupMember
 }
 
 func Use(db *gorm.DB, opts ...gen.DOOption) *Query {
 	return &Query{
-		db:               db,
-		ContactAttempt:   newContactAttempt(db, opts...),
-		DailyCheckIn:     newDailyCheckIn(db, opts...),
-		Journey:          newJourney(db, opts...),
-		NotificationTask: newNotificationTask(db, opts...),
-		QuotaTransaction: newQuotaTransaction(db, opts...),
-		QuotaWallet:      newQuotaWallet(db, opts...),
-		User:             newUser(db, opts...),
+		db:                    db,
+		AccountErasure:        newAccountErasure(db, opts...),
+		AccountMerge:          newAccountMerge(db, opts...),
+		AuditEvent:            newAuditEvent(db, opts...),
+		Contact:               newContact(db, opts...),
+		ContactAttempt:        newContactAttempt(db, opts...),
+		ContactOptOut:         newContactOptOut(db, opts...),
+		DailyCheckIn:          newDailyCheckIn(db, opts...),
+		DataExport:            newDataExport(db, opts...),
+		Journey:               newJourney(db, opts...),
+		JourneyCheckpoint:     newJourneyCheckpoint(db, opts...),
+		JourneyEvent:          newJourneyEvent(db, opts...),
+		JourneyLocation:       newJourneyLocation(db, opts...),
+		JourneyOccurrence:     newJourneyOccurrence(db, opts...),
+		JourneyTemplate:       newJourneyTemplate(db, opts...),
+		NotificationTask:      newNotificationTask(db, opts...),
+		PhoneChangeLog:        newPhoneChangeLog(db, opts...),
+		QuotaTransaction:      newQuotaTransaction(db, opts...),
+		QuotaWallet:           newQuotaWallet(db, opts...),
+		RedeemCode:            newRedeemCode(db, opts...),
+		RedeemCodeBatch:       newRedeemCodeBatch(db, opts...),
+		RedeemRecord:          newRedeemRecord(db, opts...),
+		Subscription:          newSubscription(db, opts...),
+		User:                  newUser(db, opts...),
+		UserIdentity:          newUserIdentity(db, opts...),
+		UserSession:           newUserSession(db, opts...),
+		WalletGroup:           newWalletGroup(db, opts...),
+		WalletGroupInvitation: newWalletGroupInvitation(db, opts...),
+		WalletGroupMember:     newWalletGroupMember(db, opts...),
 	}
 }
 
 type Query struct {
 	db *gorm.DB
 
-	ContactAttempt   contactAttempt
-	DailyCheckIn     dailyCheckIn
-	Journey          journey
-	NotificationTask notificationTask
-	QuotaTransaction quotaTransaction
-	QuotaWallet      quotaWallet
-	User             user
+	AccountErasure        accountErasure
+	AccountMerge          accountMerge
+	AuditEvent            auditEvent
+	Contact               contact
+	ContactAttempt        contactAttempt
+	ContactOptOut         contactOptOut
+	DailyCheckIn          dailyCheckIn
+	DataExport            dataExport
+	Journey               journey
+	JourneyCheckpoint     journeyCheckpoint
+	JourneyEvent          journeyEvent
+	JourneyLocation       journeyLocation
+	JourneyOccurrence     journeyOccurrence
+	JourneyTemplate       journeyTemplate
+	NotificationTask      notificationTask
+	PhoneChangeLog        phoneChangeLog
+	QuotaTransaction      quotaTransaction
+	QuotaWallet           quotaWallet
+	RedeemCode            redeemCode
+	RedeemCodeBatch       redeemCodeBatch
+	RedeemRecord          redeemRecord
+	Subscription          subscription
+	User                  user
+	UserIdentity          userIdentity
+	UserSession           userSession
+	WalletGroup           walletGroup
+	WalletGroupInvitation walletGroupInvitation
+	WalletGroupMember     walletGroupMember
 }
 
 func (q *Query) Available() bool { return q.db != nil }
 
 func (q *Query) clone(db *gorm.DB) *Query {
 	return &Query{
-		db:               db,
-		ContactAttempt:   q.ContactAttempt.clone(db),
-		DailyCheckIn:     q.DailyCheckIn.clone(db),
-		Journey:          q.Journey.clone(db),
-		NotificationTask: q.NotificationTask.clone(db),
-		QuotaTransaction: q.QuotaTransaction.clone(db),
-		QuotaWallet:      q.QuotaWallet.clone(db),
-		User:             q.User.clone(db),
+		db:                    db,
+		AccountErasure:        q.AccountErasure.clone(db),
+		AccountMerge:          q.AccountMerge.clone(db),
+		AuditEvent:            q.AuditEvent.clone(db),
+		Contact:               q.Contact.clone(db),
+		ContactAttempt:        q.ContactAttempt.clone(db),
+		ContactOptOut:         q.ContactOptOut.clone(db),
+		DailyCheckIn:          q.DailyCheckIn.clone(db),
+		DataExport:            q.DataExport.clone(db),
+		Journey:               q.Journey.clone(db),
+		JourneyCheckpoint:     q.JourneyCheckpoint.clone(db),
+		JourneyEvent:          q.JourneyEvent.clone(db),
+		JourneyLocation:       q.JourneyLocation.clone(db),
+		JourneyOccurrence:     q.JourneyOccurrence.clone(db),
+		JourneyTemplate:       q.JourneyTemplate.clone(db),
+		NotificationTask:      q.NotificationTask.clone(db),
+		PhoneChangeLog:        q.PhoneChangeLog.clone(db),
+		QuotaTransaction:      q.QuotaTransaction.clone(db),
+		QuotaWallet:           q.QuotaWallet.clone(db),
+		RedeemCode:            q.RedeemCode.clone(db),
+		RedeemCodeBatch:       q.RedeemCodeBatch.clone(db),
+		RedeemRecord:          q.RedeemRecord.clone(db),
+		Subscription:          q.Subscription.clone(db),
+		User:                  q.User.clone(db),
+		UserIdentity:          q.UserIdentity.clone(db),
+		UserSession:           q.UserSession.clone(db),
+		WalletGroup:           q.WalletGroup.clone(db),
+		WalletGroupInvitation: q.WalletGroupInvitation.clone(db),
+		WalletGroupMember:     q.WalletGroupMember.clone(db),
 	}
 }
 
@@ -87,36 +192,99 @@ func (q *Query) WriteDB() *Query {
 
 func (q *Query) ReplaceDB(db *gorm.DB) *Query {
 	return &Query{
-		db:               db,
-		ContactAttempt:   q.ContactAttempt.replaceDB(db),
-		DailyCheckIn:     q.DailyCheckIn.replaceDB(db),
-		Journey:          q.Journey.replaceDB(db),
-		NotificationTask: q.NotificationTask.replaceDB(db),
-		QuotaTransaction: q.QuotaTransaction.replaceDB(db),
-		QuotaWallet:      q.QuotaWallet.replaceDB(db),
-		User:             q.User.replaceDB(db),
+		db:                    db,
+		AccountErasure:        q.AccountErasure.replaceDB(db),
+		AccountMerge:          q.AccountMerge.replaceDB(db),
+		AuditEvent:            q.AuditEvent.replaceDB(db),
+		Contact:               q.Contact.replaceDB(db),
+		ContactAttempt:        q.ContactAttempt.replaceDB(db),
+		ContactOptOut:         q.ContactOptOut.replaceDB(db),
+		DailyCheckIn:          q.DailyCheckIn.replaceDB(db),
+		DataExport:            q.DataExport.replaceDB(db),
+		Journey:               q.Journey.replaceDB(db),
+		JourneyCheckpoint:     q.JourneyCheckpoint.replaceDB(db),
+		JourneyEvent:          q.JourneyEvent.replaceDB(db),
+		JourneyLocation:       q.JourneyLocation.replaceDB(db),
+		JourneyOccurrence:     q.JourneyOccurrence.replaceDB(db),
+		JourneyTemplate:       q.JourneyTemplate.replaceDB(db),
+		NotificationTask:      q.NotificationTask.replaceDB(db),
+		PhoneChangeLog:        q.PhoneChangeLog.replaceDB(db),
+		QuotaTransaction:      q.QuotaTransaction.replaceDB(db),
+		QuotaWallet:           q.QuotaWallet.replaceDB(db),
+		RedeemCode:            q.RedeemCode.replaceDB(db),
+		RedeemCodeBatch:       q.RedeemCodeBatch.replaceDB(db),
+		RedeemRecord:          q.RedeemRecord.replaceDB(db),
+		Subscription:          q.Subscription.replaceDB(db),
+		User:                  q.User.replaceDB(db),
+		UserIdentity:          q.UserIdentity.replaceDB(db),
+		UserSession:           q.UserSession.replaceDB(db),
+		WalletGroup:           q.WalletGroup.replaceDB(db),
+		WalletGroupInvitation: q.WalletGroupInvitation.replaceDB(db),
+		WalletGroupMember:     q.WalletGroupMember.replaceDB(db),
 	}
 }
 
 type queryCtx struct {
-	ContactAttempt   IContactAttemptDo
-	DailyCheckIn     IDailyCheckInDo
-	Journey          IJourneyDo
-	NotificationTask INotificationTaskDo
-	QuotaTransaction IQuotaTransactionDo
-	QuotaWallet      IQuotaWalletDo
-	User             IUserDo
+	AccountErasure        IAccountErasureDo
+	AccountMerge          IAccountMergeDo
+	AuditEvent            IAuditEventDo
+	Contact               IContactDo
+	ContactAttempt        IContactAttemptDo
+	ContactOptOut         IContactOptOutDo
+	DailyCheckIn          IDailyCheckInDo
+	DataExport            IDataExportDo
+	Journey               IJourneyDo
+	JourneyCheckpoint     IJourneyCheckpointDo
+	JourneyEvent          IJourneyEventDo
+	JourneyLocation       IJourneyLocationDo
+	JourneyOccurrence     IJourneyOccurrenceDo
+	JourneyTemplate       IJourneyTemplateDo
+	NotificationTask      INotificationTaskDo
+	PhoneChangeLog        IPhoneChangeLogDo
+	QuotaTransaction      IQuotaTransactionDo
+	QuotaWallet           IQuotaWalletDo
+	RedeemCode            IRedeemCodeDo
+	RedeemCodeBatch       IRedeemCodeBatchDo
+	RedeemRecord          IRedeemRecordDo
+	Subscription          ISubscriptionDo
+	User                  IUserDo
+	UserIdentity          IUserIdentityDo
+	UserSession           IUserSessionDo
+	WalletGroup           IWalletGroupDo
+	WalletGroupInvitation IWalletGroupInvitationDo
+	WalletGroupMember     IWalletGroupMemberDo
 }
 
 func (q *Query) WithContext(ctx context.Context) *queryCtx {
 	return &queryCtx{
-		ContactAttempt:   q.ContactAttempt.WithContext(ctx),
-		DailyCheckIn:     q.DailyCheckIn.WithContext(ctx),
-		Journey:          q.Journey.WithContext(ctx),
-		NotificationTask: q.NotificationTask.WithContext(ctx),
-		QuotaTransaction: q.QuotaTransaction.WithContext(ctx),
-		QuotaWallet:      q.QuotaWallet.WithContext(ctx),
-		User:             q.User.WithContext(ctx),
+		AccountErasure:        q.AccountErasure.WithContext(ctx),
+		AccountMerge:          q.AccountMerge.WithContext(ctx),
+		AuditEvent:            q.AuditEvent.WithContext(ctx),
+		Contact:               q.Contact.WithContext(ctx),
+		ContactAttempt:        q.ContactAttempt.WithContext(ctx),
+		ContactOptOut:         q.ContactOptOut.WithContext(ctx),
+		DailyCheckIn:          q.DailyCheckIn.WithContext(ctx),
+		DataExport:            q.DataExport.WithContext(ctx),
+		Journey:               q.Journey.WithContext(ctx),
+		JourneyCheckpoint:     q.JourneyCheckpoint.WithContext(ctx),
+		JourneyEvent:          q.JourneyEvent.WithContext(ctx),
+		JourneyLocation:       q.JourneyLocation.WithContext(ctx),
+		JourneyOccurrence:     q.JourneyOccurrence.WithContext(ctx),
+		JourneyTemplate:       q.JourneyTemplate.WithContext(ctx),
+		NotificationTask:      q.NotificationTask.WithContext(ctx),
+		PhoneChangeLog:        q.PhoneChangeLog.WithContext(ctx),
+		QuotaTransaction:      q.QuotaTransaction.WithContext(ctx),
+		QuotaWallet:           q.QuotaWallet.WithContext(ctx),
+		RedeemCode:            q.RedeemCode.WithContext(ctx),
+		RedeemCodeBatch:       q.RedeemCodeBatch.WithContext(ctx),
+		RedeemRecord:          q.RedeemRecord.WithContext(ctx),
+		Subscription:          q.Subscription.WithContext(ctx),
+		User:                  q.User.WithContext(ctx),
+		UserIdentity:          q.UserIdentity.WithContext(ctx),
+		UserSession:           q.UserSession.WithContext(ctx),
+		WalletGroup:           q.WalletGroup.WithContext(ctx),
+		WalletGroupInvitation: q.WalletGroupInvitation.WithContext(ctx),
+		WalletGroupMember:     q.WalletGroupMember.WithContext(ctx),
 	}
 }
 
@@ -0,0 +1,442 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"AreYouOK/internal/model"
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+func newJourneyCheckpoint(db *gorm.DB, opts ...gen.DOOption) journeyCheckpoint {
+	_journeyCheckpoint := journeyCheckpoint{}
+
+	_journeyCheckpoint.journeyCheckpointDo.UseDB(db, opts...)
+	_journeyCheckpoint.journeyCheckpointDo.UseModel(&model.JourneyCheckpoint{})
+
+	tableName := _journeyCheckpoint.journeyCheckpointDo.TableName()
+	_journeyCheckpoint.ALL = field.NewAsterisk(tableName)
+	_journeyCheckpoint.ExpectedAt = field.NewTime(tableName, "expected_at")
+	_journeyCheckpoint.CheckedInAt = field.NewTime(tableName, "checked_in_at")
+	_journeyCheckpoint.ReminderSentAt = field.NewTime(tableName, "reminder_sent_at")
+	_journeyCheckpoint.AlertTriggeredAt = field.NewTime(tableName, "alert_triggered_at")
+	_journeyCheckpoint.Latitude = field.NewFloat64(tableName, "latitude")
+	_journeyCheckpoint.Longitude = field.NewFloat64(tableName, "longitude")
+	_journeyCheckpoint.TimeoutMessageID = field.NewString(tableName, "timeout_message_id")
+	_journeyCheckpoint.Label = field.NewString(tableName, "label")
+	_journeyCheckpoint.Location = field.NewString(tableName, "location")
+	_journeyCheckpoint.Status = field.NewString(tableName, "status")
+	_journeyCheckpoint.CreatedAt = field.NewTime(tableName, "created_at")
+	_journeyCheckpoint.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_journeyCheckpoint.DeletedAt = field.NewField(tableName, "deleted_at")
+	_journeyCheckpoint.ID = field.NewInt64(tableName, "id")
+	_journeyCheckpoint.JourneyID = field.NewInt64(tableName, "journey_id")
+	_journeyCheckpoint.Seq = field.NewInt(tableName, "seq")
+
+	_journeyCheckpoint.fillFieldMap()
+
+	return _journeyCheckpoint
+}
+
+type journeyCheckpoint struct {
+	journeyCheckpointDo
+
+	ALL              field.Asterisk
+	ExpectedAt       field.Time
+	CheckedInAt      field.Time
+	ReminderSentAt   field.Time
+	AlertTriggeredAt field.Time
+	Latitude         field.Float64
+	Longitude        field.Float64
+	TimeoutMessageID field.String
+	Label            field.String
+	Location         field.String
+	Status           field.String
+	CreatedAt        field.Time
+	UpdatedAt        field.Time
+	DeletedAt        field.Field
+	ID               field.Int64
+	JourneyID        field.Int64
+	Seq              field.Int
+
+	fieldMap map[string]field.Expr
+}
+
+func (j journeyCheckpoint) Table(newTableName string) *journeyCheckpoint {
+	j.journeyCheckpointDo.UseTable(newTableName)
+	return j.updateTableName(newTableName)
+}
+
+func (j journeyCheckpoint) As(alias string) *journeyCheckpoint {
+	j.journeyCheckpointDo.DO = *(j.journeyCheckpointDo.As(alias).(*gen.DO))
+	return j.updateTableName(alias)
+}
+
+func (j *journeyCheckpoint) updateTableName(table string) *journeyCheckpoint {
+	j.ALL = field.NewAsterisk(table)
+	j.ExpectedAt = field.NewTime(table, "expected_at")
+	j.CheckedInAt = field.NewTime(table, "checked_in_at")
+	j.ReminderSentAt = field.NewTime(table, "reminder_sent_at")
+	j.AlertTriggeredAt = field.NewTime(table, "alert_triggered_at")
+	j.Latitude = field.NewFloat64(table, "latitude")
+	j.Longitude = field.NewFloat64(table, "longitude")
+	j.TimeoutMessageID = field.NewString(table, "timeout_message_id")
+	j.Label = field.NewString(table, "label")
+	j.Location = field.NewString(table, "location")
+	j.Status = field.NewString(table, "status")
+	j.CreatedAt = field.NewTime(table, "created_at")
+	j.UpdatedAt = field.NewTime(table, "updated_at")
+	j.DeletedAt = field.NewField(table, "deleted_at")
+	j.ID = field.NewInt64(table, "id")
+	j.JourneyID = field.NewInt64(table, "journey_id")
+	j.Seq = field.NewInt(table, "seq")
+
+	j.fillFieldMap()
+
+	return j
+}
+
+func (j *journeyCheckpoint) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := j.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (j *journeyCheckpoint) fillFieldMap() {
+	j.fieldMap = make(map[string]field.Expr, 16)
+	j.fieldMap["expected_at"] = j.ExpectedAt
+	j.fieldMap["checked_in_at"] = j.CheckedInAt
+	j.fieldMap["reminder_sent_at"] = j.ReminderSentAt
+	j.fieldMap["alert_triggered_at"] = j.AlertTriggeredAt
+	j.fieldMap["latitude"] = j.Latitude
+	j.fieldMap["longitude"] = j.Longitude
+	j.fieldMap["timeout_message_id"] = j.TimeoutMessageID
+	j.fieldMap["label"] = j.Label
+	j.fieldMap["location"] = j.Location
+	j.fieldMap["status"] = j.Status
+	j.fieldMap["created_at"] = j.CreatedAt
+	j.fieldMap["updated_at"] = j.UpdatedAt
+	j.fieldMap["deleted_at"] = j.DeletedAt
+	j.fieldMap["id"] = j.ID
+	j.fieldMap["journey_id"] = j.JourneyID
+	j.fieldMap["seq"] = j.Seq
+}
+
+func (j journeyCheckpoint) clone(db *gorm.DB) journeyCheckpoint {
+	j.journeyCheckpointDo.ReplaceConnPool(db.Statement.ConnPool)
+	return j
+}
+
+func (j journeyCheckpoint) replaceDB(db *gorm.DB) journeyCheckpoint {
+	j.journeyCheckpointDo.ReplaceDB(db)
+	return j
+}
+
+type journeyCheckpointDo struct{ gen.DO }
+
+type IJourneyCheckpointDo interface {
+	gen.SubQuery
+	Debug() IJourneyCheckpointDo
+	WithContext(ctx context.Context) IJourneyCheckpointDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IJourneyCheckpointDo
+	WriteDB() IJourneyCheckpointDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IJourneyCheckpointDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IJourneyCheckpointDo
+	Not(conds ...gen.Condition) IJourneyCheckpointDo
+	Or(conds ...gen.Condition) IJourneyCheckpointDo
+	Select(conds ...field.Expr) IJourneyCheckpointDo
+	Where(conds ...gen.Condition) IJourneyCheckpointDo
+	Order(conds ...field.Expr) IJourneyCheckpointDo
+	Distinct(cols ...field.Expr) IJourneyCheckpointDo
+	Omit(cols ...field.Expr) IJourneyCheckpointDo
+	Join(table schema.Tabler, on ...field.Expr) IJourneyCheckpointDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IJourneyCheckpointDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IJourneyCheckpointDo
+	Group(cols ...field.Expr) IJourneyCheckpointDo
+	Having(conds ...gen.Condition) IJourneyCheckpointDo
+	Limit(limit int) IJourneyCheckpointDo
+	Offset(offset int) IJourneyCheckpointDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IJourneyCheckpointDo
+	Unscoped() IJourneyCheckpointDo
+	Create(values ...*model.JourneyCheckpoint) error
+	CreateInBatches(values []*model.JourneyCheckpoint, batchSize int) error
+	Save(values ...*model.JourneyCheckpoint) error
+	First() (*model.JourneyCheckpoint, error)
+	Take() (*model.JourneyCheckpoint, error)
+	Last() (*model.JourneyCheckpoint, error)
+	Find() ([]*model.JourneyCheckpoint, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.JourneyCheckpoint, err error)
+	FindInBatches(result *[]*model.JourneyCheckpoint, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.JourneyCheckpoint) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IJourneyCheckpointDo
+	Assign(attrs ...field.AssignExpr) IJourneyCheckpointDo
+	Joins(fields ...field.RelationField) IJourneyCheckpointDo
+	Preload(fields ...field.RelationField) IJourneyCheckpointDo
+	FirstOrInit() (*model.JourneyCheckpoint, error)
+	FirstOrCreate() (*model.JourneyCheckpoint, error)
+	FindByPage(offset int, limit int) (result []*model.JourneyCheckpoint, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IJourneyCheckpointDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (j journeyCheckpointDo) Debug() IJourneyCheckpointDo {
+	return j.withDO(j.DO.Debug())
+}
+
+func (j journeyCheckpointDo) WithContext(ctx context.Context) IJourneyCheckpointDo {
+	return j.withDO(j.DO.WithContext(ctx))
+}
+
+func (j journeyCheckpointDo) ReadDB() IJourneyCheckpointDo {
+	return j.Clauses(dbresolver.Read)
+}
+
+func (j journeyCheckpointDo) WriteDB() IJourneyCheckpointDo {
+	return j.Clauses(dbresolver.Write)
+}
+
+func (j journeyCheckpointDo) Session(config *gorm.Session) IJourneyCheckpointDo {
+	return j.withDO(j.DO.Session(config))
+}
+
+func (j journeyCheckpointDo) Clauses(conds ...clause.Expression) IJourneyCheckpointDo {
+	return j.withDO(j.DO.Clauses(conds...))
+}
+
+func (j journeyCheckpointDo) Returning(value interface{}, columns ...string) IJourneyCheckpointDo {
+	return j.withDO(j.DO.Returning(value, columns...))
+}
+
+func (j journeyCheckpointDo) Not(conds ...gen.Condition) IJourneyCheckpointDo {
+	return j.withDO(j.DO.Not(conds...))
+}
+
+func (j journeyCheckpointDo) Or(conds ...gen.Condition) IJourneyCheckpointDo {
+	return j.withDO(j.DO.Or(conds...))
+}
+
+func (j journeyCheckpointDo) Select(conds ...field.Expr) IJourneyCheckpointDo {
+	return j.withDO(j.DO.Select(conds...))
+}
+
+func (j journeyCheckpointDo) Where(conds ...gen.Condition) IJourneyCheckpointDo {
+	return j.withDO(j.DO.Where(conds...))
+}
+
+func (j journeyCheckpointDo) Order(conds ...field.Expr) IJourneyCheckpointDo {
+	return j.withDO(j.DO.Order(conds...))
+}
+
+func (j journeyCheckpointDo) Distinct(cols ...field.Expr) IJourneyCheckpointDo {
+	return j.withDO(j.DO.Distinct(cols...))
+}
+
+func (j journeyCheckpointDo) Omit(cols ...field.Expr) IJourneyCheckpointDo {
+	return j.withDO(j.DO.Omit(cols...))
+}
+
+func (j journeyCheckpointDo) Join(table schema.Tabler, on ...field.Expr) IJourneyCheckpointDo {
+	return j.withDO(j.DO.Join(table, on...))
+}
+
+func (j journeyCheckpointDo) LeftJoin(table schema.Tabler, on ...field.Expr) IJourneyCheckpointDo {
+	return j.withDO(j.DO.LeftJoin(table, on...))
+}
+
+func (j journeyCheckpointDo) RightJoin(table schema.Tabler, on ...field.Expr) IJourneyCheckpointDo {
+	return j.withDO(j.DO.RightJoin(table, on...))
+}
+
+func (j journeyCheckpointDo) Group(cols ...field.Expr) IJourneyCheckpointDo {
+	return j.withDO(j.DO.Group(cols...))
+}
+
+func (j journeyCheckpointDo) Having(conds ...gen.Condition) IJourneyCheckpointDo {
+	return j.withDO(j.DO.Having(conds...))
+}
+
+func (j journeyCheckpointDo) Limit(limit int) IJourneyCheckpointDo {
+	return j.withDO(j.DO.Limit(limit))
+}
+
+func (j journeyCheckpointDo) Offset(offset int) IJourneyCheckpointDo {
+	return j.withDO(j.DO.Offset(offset))
+}
+
+func (j journeyCheckpointDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IJourneyCheckpointDo {
+	return j.withDO(j.DO.Scopes(funcs...))
+}
+
+func (j journeyCheckpointDo) Unscoped() IJourneyCheckpointDo {
+	return j.withDO(j.DO.Unscoped())
+}
+
+func (j journeyCheckpointDo) Create(values ...*model.JourneyCheckpoint) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return j.DO.Create(values)
+}
+
+func (j journeyCheckpointDo) CreateInBatches(values []*model.JourneyCheckpoint, batchSize int) error {
+	return j.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (j journeyCheckpointDo) Save(values ...*model.JourneyCheckpoint) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return j.DO.Save(values)
+}
+
+func (j journeyCheckpointDo) First() (*model.JourneyCheckpoint, error) {
+	if result, err := j.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JourneyCheckpoint), nil
+	}
+}
+
+func (j journeyCheckpointDo) Take() (*model.JourneyCheckpoint, error) {
+	if result, err := j.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JourneyCheckpoint), nil
+	}
+}
+
+func (j journeyCheckpointDo) Last() (*model.JourneyCheckpoint, error) {
+	if result, err := j.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JourneyCheckpoint), nil
+	}
+}
+
+func (j journeyCheckpointDo) Find() ([]*model.JourneyCheckpoint, error) {
+	result, err := j.DO.Find()
+	return result.([]*model.JourneyCheckpoint), err
+}
+
+func (j journeyCheckpointDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.JourneyCheckpoint, err error) {
+	buf := make([]*model.JourneyCheckpoint, 0, batchSize)
+	err = j.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (j journeyCheckpointDo) FindInBatches(result *[]*model.JourneyCheckpoint, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return j.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (j journeyCheckpointDo) Attrs(attrs ...field.AssignExpr) IJourneyCheckpointDo {
+	return j.withDO(j.DO.Attrs(attrs...))
+}
+
+func (j journeyCheckpointDo) Assign(attrs ...field.AssignExpr) IJourneyCheckpointDo {
+	return j.withDO(j.DO.Assign(attrs...))
+}
+
+func (j journeyCheckpointDo) Joins(fields ...field.RelationField) IJourneyCheckpointDo {
+	for _, _f := range fields {
+		j = *j.withDO(j.DO.Joins(_f))
+	}
+	return &j
+}
+
+func (j journeyCheckpointDo) Preload(fields ...field.RelationField) IJourneyCheckpointDo {
+	for _, _f := range fields {
+		j = *j.withDO(j.DO.Preload(_f))
+	}
+	return &j
+}
+
+func (j journeyCheckpointDo) FirstOrInit() (*model.JourneyCheckpoint, error) {
+	if result, err := j.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JourneyCheckpoint), nil
+	}
+}
+
+func (j journeyCheckpointDo) FirstOrCreate() (*model.JourneyCheckpoint, error) {
+	if result, err := j.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JourneyCheckpoint), nil
+	}
+}
+
+func (j journeyCheckpointDo) FindByPage(offset int, limit int) (result []*model.JourneyCheckpoint, count int64, err error) {
+	result, err = j.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = j.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (j journeyCheckpointDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = j.Count()
+	if err != nil {
+		return
+	}
+
+	err = j.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (j journeyCheckpointDo) Scan(result interface{}) (err error) {
+	return j.DO.Scan(result)
+}
+
+func (j journeyCheckpointDo) Delete(models ...*model.JourneyCheckpoint) (result gen.ResultInfo, err error) {
+	return j.DO.Delete(models)
+}
+
+func (j *journeyCheckpointDo) withDO(do gen.Dao) *journeyCheckpointDo {
+	j.DO = *do.(*gen.DO)
+	return j
+}
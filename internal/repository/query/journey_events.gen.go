@@ -0,0 +1,410 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"AreYouOK/internal/model"
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+func newJourneyEvent(db *gorm.DB, opts ...gen.DOOption) journeyEvent {
+	_journeyEvent := journeyEvent{}
+
+	_journeyEvent.journeyEventDo.UseDB(db, opts...)
+	_journeyEvent.journeyEventDo.UseModel(&model.JourneyEvent{})
+
+	tableName := _journeyEvent.journeyEventDo.TableName()
+	_journeyEvent.ALL = field.NewAsterisk(tableName)
+	_journeyEvent.OccurredAt = field.NewTime(tableName, "occurred_at")
+	_journeyEvent.Type = field.NewString(tableName, "type")
+	_journeyEvent.Data = field.NewField(tableName, "data")
+	_journeyEvent.CreatedAt = field.NewTime(tableName, "created_at")
+	_journeyEvent.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_journeyEvent.DeletedAt = field.NewField(tableName, "deleted_at")
+	_journeyEvent.ID = field.NewInt64(tableName, "id")
+	_journeyEvent.JourneyID = field.NewInt64(tableName, "journey_id")
+
+	_journeyEvent.fillFieldMap()
+
+	return _journeyEvent
+}
+
+type journeyEvent struct {
+	journeyEventDo
+
+	ALL        field.Asterisk
+	OccurredAt field.Time
+	Type       field.String
+	Data       field.Field
+	CreatedAt  field.Time
+	UpdatedAt  field.Time
+	DeletedAt  field.Field
+	ID         field.Int64
+	JourneyID  field.Int64
+
+	fieldMap map[string]field.Expr
+}
+
+func (j journeyEvent) Table(newTableName string) *journeyEvent {
+	j.journeyEventDo.UseTable(newTableName)
+	return j.updateTableName(newTableName)
+}
+
+func (j journeyEvent) As(alias string) *journeyEvent {
+	j.journeyEventDo.DO = *(j.journeyEventDo.As(alias).(*gen.DO))
+	return j.updateTableName(alias)
+}
+
+func (j *journeyEvent) updateTableName(table string) *journeyEvent {
+	j.ALL = field.NewAsterisk(table)
+	j.OccurredAt = field.NewTime(table, "occurred_at")
+	j.Type = field.NewString(table, "type")
+	j.Data = field.NewField(table, "data")
+	j.CreatedAt = field.NewTime(table, "created_at")
+	j.UpdatedAt = field.NewTime(table, "updated_at")
+	j.DeletedAt = field.NewField(table, "deleted_at")
+	j.ID = field.NewInt64(table, "id")
+	j.JourneyID = field.NewInt64(table, "journey_id")
+
+	j.fillFieldMap()
+
+	return j
+}
+
+func (j *journeyEvent) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := j.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (j *journeyEvent) fillFieldMap() {
+	j.fieldMap = make(map[string]field.Expr, 8)
+	j.fieldMap["occurred_at"] = j.OccurredAt
+	j.fieldMap["type"] = j.Type
+	j.fieldMap["data"] = j.Data
+	j.fieldMap["created_at"] = j.CreatedAt
+	j.fieldMap["updated_at"] = j.UpdatedAt
+	j.fieldMap["deleted_at"] = j.DeletedAt
+	j.fieldMap["id"] = j.ID
+	j.fieldMap["journey_id"] = j.JourneyID
+}
+
+func (j journeyEvent) clone(db *gorm.DB) journeyEvent {
+	j.journeyEventDo.ReplaceConnPool(db.Statement.ConnPool)
+	return j
+}
+
+func (j journeyEvent) replaceDB(db *gorm.DB) journeyEvent {
+	j.journeyEventDo.ReplaceDB(db)
+	return j
+}
+
+type journeyEventDo struct{ gen.DO }
+
+type IJourneyEventDo interface {
+	gen.SubQuery
+	Debug() IJourneyEventDo
+	WithContext(ctx context.Context) IJourneyEventDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IJourneyEventDo
+	WriteDB() IJourneyEventDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IJourneyEventDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IJourneyEventDo
+	Not(conds ...gen.Condition) IJourneyEventDo
+	Or(conds ...gen.Condition) IJourneyEventDo
+	Select(conds ...field.Expr) IJourneyEventDo
+	Where(conds ...gen.Condition) IJourneyEventDo
+	Order(conds ...field.Expr) IJourneyEventDo
+	Distinct(cols ...field.Expr) IJourneyEventDo
+	Omit(cols ...field.Expr) IJourneyEventDo
+	Join(table schema.Tabler, on ...field.Expr) IJourneyEventDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IJourneyEventDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IJourneyEventDo
+	Group(cols ...field.Expr) IJourneyEventDo
+	Having(conds ...gen.Condition) IJourneyEventDo
+	Limit(limit int) IJourneyEventDo
+	Offset(offset int) IJourneyEventDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IJourneyEventDo
+	Unscoped() IJourneyEventDo
+	Create(values ...*model.JourneyEvent) error
+	CreateInBatches(values []*model.JourneyEvent, batchSize int) error
+	Save(values ...*model.JourneyEvent) error
+	First() (*model.JourneyEvent, error)
+	Take() (*model.JourneyEvent, error)
+	Last() (*model.JourneyEvent, error)
+	Find() ([]*model.JourneyEvent, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.JourneyEvent, err error)
+	FindInBatches(result *[]*model.JourneyEvent, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.JourneyEvent) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IJourneyEventDo
+	Assign(attrs ...field.AssignExpr) IJourneyEventDo
+	Joins(fields ...field.RelationField) IJourneyEventDo
+	Preload(fields ...field.RelationField) IJourneyEventDo
+	FirstOrInit() (*model.JourneyEvent, error)
+	FirstOrCreate() (*model.JourneyEvent, error)
+	FindByPage(offset int, limit int) (result []*model.JourneyEvent, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IJourneyEventDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (j journeyEventDo) Debug() IJourneyEventDo {
+	return j.withDO(j.DO.Debug())
+}
+
+func (j journeyEventDo) WithContext(ctx context.Context) IJourneyEventDo {
+	return j.withDO(j.DO.WithContext(ctx))
+}
+
+func (j journeyEventDo) ReadDB() IJourneyEventDo {
+	return j.Clauses(dbresolver.Read)
+}
+
+func (j journeyEventDo) WriteDB() IJourneyEventDo {
+	return j.Clauses(dbresolver.Write)
+}
+
+func (j journeyEventDo) Session(config *gorm.Session) IJourneyEventDo {
+	return j.withDO(j.DO.Session(config))
+}
+
+func (j journeyEventDo) Clauses(conds ...clause.Expression) IJourneyEventDo {
+	return j.withDO(j.DO.Clauses(conds...))
+}
+
+func (j journeyEventDo) Returning(value interface{}, columns ...string) IJourneyEventDo {
+	return j.withDO(j.DO.Returning(value, columns...))
+}
+
+func (j journeyEventDo) Not(conds ...gen.Condition) IJourneyEventDo {
+	return j.withDO(j.DO.Not(conds...))
+}
+
+func (j journeyEventDo) Or(conds ...gen.Condition) IJourneyEventDo {
+	return j.withDO(j.DO.Or(conds...))
+}
+
+func (j journeyEventDo) Select(conds ...field.Expr) IJourneyEventDo {
+	return j.withDO(j.DO.Select(conds...))
+}
+
+func (j journeyEventDo) Where(conds ...gen.Condition) IJourneyEventDo {
+	return j.withDO(j.DO.Where(conds...))
+}
+
+func (j journeyEventDo) Order(conds ...field.Expr) IJourneyEventDo {
+	return j.withDO(j.DO.Order(conds...))
+}
+
+func (j journeyEventDo) Distinct(cols ...field.Expr) IJourneyEventDo {
+	return j.withDO(j.DO.Distinct(cols...))
+}
+
+func (j journeyEventDo) Omit(cols ...field.Expr) IJourneyEventDo {
+	return j.withDO(j.DO.Omit(cols...))
+}
+
+func (j journeyEventDo) Join(table schema.Tabler, on ...field.Expr) IJourneyEventDo {
+	return j.withDO(j.DO.Join(table, on...))
+}
+
+func (j journeyEventDo) LeftJoin(table schema.Tabler, on ...field.Expr) IJourneyEventDo {
+	return j.withDO(j.DO.LeftJoin(table, on...))
+}
+
+func (j journeyEventDo) RightJoin(table schema.Tabler, on ...field.Expr) IJourneyEventDo {
+	return j.withDO(j.DO.RightJoin(table, on...))
+}
+
+func (j journeyEventDo) Group(cols ...field.Expr) IJourneyEventDo {
+	return j.withDO(j.DO.Group(cols...))
+}
+
+func (j journeyEventDo) Having(conds ...gen.Condition) IJourneyEventDo {
+	return j.withDO(j.DO.Having(conds...))
+}
+
+func (j journeyEventDo) Limit(limit int) IJourneyEventDo {
+	return j.withDO(j.DO.Limit(limit))
+}
+
+func (j journeyEventDo) Offset(offset int) IJourneyEventDo {
+	return j.withDO(j.DO.Offset(offset))
+}
+
+func (j journeyEventDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IJourneyEventDo {
+	return j.withDO(j.DO.Scopes(funcs...))
+}
+
+func (j journeyEventDo) Unscoped() IJourneyEventDo {
+	return j.withDO(j.DO.Unscoped())
+}
+
+func (j journeyEventDo) Create(values ...*model.JourneyEvent) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return j.DO.Create(values)
+}
+
+func (j journeyEventDo) CreateInBatches(values []*model.JourneyEvent, batchSize int) error {
+	return j.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (j journeyEventDo) Save(values ...*model.JourneyEvent) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return j.DO.Save(values)
+}
+
+func (j journeyEventDo) First() (*model.JourneyEvent, error) {
+	if result, err := j.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JourneyEvent), nil
+	}
+}
+
+func (j journeyEventDo) Take() (*model.JourneyEvent, error) {
+	if result, err := j.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JourneyEvent), nil
+	}
+}
+
+func (j journeyEventDo) Last() (*model.JourneyEvent, error) {
+	if result, err := j.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JourneyEvent), nil
+	}
+}
+
+func (j journeyEventDo) Find() ([]*model.JourneyEvent, error) {
+	result, err := j.DO.Find()
+	return result.([]*model.JourneyEvent), err
+}
+
+func (j journeyEventDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.JourneyEvent, err error) {
+	buf := make([]*model.JourneyEvent, 0, batchSize)
+	err = j.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (j journeyEventDo) FindInBatches(result *[]*model.JourneyEvent, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return j.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (j journeyEventDo) Attrs(attrs ...field.AssignExpr) IJourneyEventDo {
+	return j.withDO(j.DO.Attrs(attrs...))
+}
+
+func (j journeyEventDo) Assign(attrs ...field.AssignExpr) IJourneyEventDo {
+	return j.withDO(j.DO.Assign(attrs...))
+}
+
+func (j journeyEventDo) Joins(fields ...field.RelationField) IJourneyEventDo {
+	for _, _f := range fields {
+		j = *j.withDO(j.DO.Joins(_f))
+	}
+	return &j
+}
+
+func (j journeyEventDo) Preload(fields ...field.RelationField) IJourneyEventDo {
+	for _, _f := range fields {
+		j = *j.withDO(j.DO.Preload(_f))
+	}
+	return &j
+}
+
+func (j journeyEventDo) FirstOrInit() (*model.JourneyEvent, error) {
+	if result, err := j.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JourneyEvent), nil
+	}
+}
+
+func (j journeyEventDo) FirstOrCreate() (*model.JourneyEvent, error) {
+	if result, err := j.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JourneyEvent), nil
+	}
+}
+
+func (j journeyEventDo) FindByPage(offset int, limit int) (result []*model.JourneyEvent, count int64, err error) {
+	result, err = j.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = j.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (j journeyEventDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = j.Count()
+	if err != nil {
+		return
+	}
+
+	err = j.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (j journeyEventDo) Scan(result interface{}) (err error) {
+	return j.DO.Scan(result)
+}
+
+func (j journeyEventDo) Delete(models ...*model.JourneyEvent) (result gen.ResultInfo, err error) {
+	return j.DO.Delete(models)
+}
+
+func (j *journeyEventDo) withDO(do gen.Dao) *journeyEventDo {
+	j.DO = *do.(*gen.DO)
+	return j
+}
@@ -0,0 +1,414 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"AreYouOK/internal/model"
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+func newJourneyLocation(db *gorm.DB, opts ...gen.DOOption) journeyLocation {
+	_journeyLocation := journeyLocation{}
+
+	_journeyLocation.journeyLocationDo.UseDB(db, opts...)
+	_journeyLocation.journeyLocationDo.UseModel(&model.JourneyLocation{})
+
+	tableName := _journeyLocation.journeyLocationDo.TableName()
+	_journeyLocation.ALL = field.NewAsterisk(tableName)
+	_journeyLocation.RecordedAt = field.NewTime(tableName, "recorded_at")
+	_journeyLocation.Latitude = field.NewFloat64(tableName, "latitude")
+	_journeyLocation.Longitude = field.NewFloat64(tableName, "longitude")
+	_journeyLocation.Accuracy = field.NewFloat64(tableName, "accuracy")
+	_journeyLocation.CreatedAt = field.NewTime(tableName, "created_at")
+	_journeyLocation.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_journeyLocation.DeletedAt = field.NewField(tableName, "deleted_at")
+	_journeyLocation.ID = field.NewInt64(tableName, "id")
+	_journeyLocation.JourneyID = field.NewInt64(tableName, "journey_id")
+
+	_journeyLocation.fillFieldMap()
+
+	return _journeyLocation
+}
+
+type journeyLocation struct {
+	journeyLocationDo
+
+	ALL        field.Asterisk
+	RecordedAt field.Time
+	Latitude   field.Float64
+	Longitude  field.Float64
+	Accuracy   field.Float64
+	CreatedAt  field.Time
+	UpdatedAt  field.Time
+	DeletedAt  field.Field
+	ID         field.Int64
+	JourneyID  field.Int64
+
+	fieldMap map[string]field.Expr
+}
+
+func (j journeyLocation) Table(newTableName string) *journeyLocation {
+	j.journeyLocationDo.UseTable(newTableName)
+	return j.updateTableName(newTableName)
+}
+
+func (j journeyLocation) As(alias string) *journeyLocation {
+	j.journeyLocationDo.DO = *(j.journeyLocationDo.As(alias).(*gen.DO))
+	return j.updateTableName(alias)
+}
+
+func (j *journeyLocation) updateTableName(table string) *journeyLocation {
+	j.ALL = field.NewAsterisk(table)
+	j.RecordedAt = field.NewTime(table, "recorded_at")
+	j.Latitude = field.NewFloat64(table, "latitude")
+	j.Longitude = field.NewFloat64(table, "longitude")
+	j.Accuracy = field.NewFloat64(table, "accuracy")
+	j.CreatedAt = field.NewTime(table, "created_at")
+	j.UpdatedAt = field.NewTime(table, "updated_at")
+	j.DeletedAt = field.NewField(table, "deleted_at")
+	j.ID = field.NewInt64(table, "id")
+	j.JourneyID = field.NewInt64(table, "journey_id")
+
+	j.fillFieldMap()
+
+	return j
+}
+
+func (j *journeyLocation) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := j.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (j *journeyLocation) fillFieldMap() {
+	j.fieldMap = make(map[string]field.Expr, 9)
+	j.fieldMap["recorded_at"] = j.RecordedAt
+	j.fieldMap["latitude"] = j.Latitude
+	j.fieldMap["longitude"] = j.Longitude
+	j.fieldMap["accuracy"] = j.Accuracy
+	j.fieldMap["created_at"] = j.CreatedAt
+	j.fieldMap["updated_at"] = j.UpdatedAt
+	j.fieldMap["deleted_at"] = j.DeletedAt
+	j.fieldMap["id"] = j.ID
+	j.fieldMap["journey_id"] = j.JourneyID
+}
+
+func (j journeyLocation) clone(db *gorm.DB) journeyLocation {
+	j.journeyLocationDo.ReplaceConnPool(db.Statement.ConnPool)
+	return j
+}
+
+func (j journeyLocation) replaceDB(db *gorm.DB) journeyLocation {
+	j.journeyLocationDo.ReplaceDB(db)
+	return j
+}
+
+type journeyLocationDo struct{ gen.DO }
+
+type IJourneyLocationDo interface {
+	gen.SubQuery
+	Debug() IJourneyLocationDo
+	WithContext(ctx context.Context) IJourneyLocationDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IJourneyLocationDo
+	WriteDB() IJourneyLocationDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IJourneyLocationDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IJourneyLocationDo
+	Not(conds ...gen.Condition) IJourneyLocationDo
+	Or(conds ...gen.Condition) IJourneyLocationDo
+	Select(conds ...field.Expr) IJourneyLocationDo
+	Where(conds ...gen.Condition) IJourneyLocationDo
+	Order(conds ...field.Expr) IJourneyLocationDo
+	Distinct(cols ...field.Expr) IJourneyLocationDo
+	Omit(cols ...field.Expr) IJourneyLocationDo
+	Join(table schema.Tabler, on ...field.Expr) IJourneyLocationDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IJourneyLocationDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IJourneyLocationDo
+	Group(cols ...field.Expr) IJourneyLocationDo
+	Having(conds ...gen.Condition) IJourneyLocationDo
+	Limit(limit int) IJourneyLocationDo
+	Offset(offset int) IJourneyLocationDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IJourneyLocationDo
+	Unscoped() IJourneyLocationDo
+	Create(values ...*model.JourneyLocation) error
+	CreateInBatches(values []*model.JourneyLocation, batchSize int) error
+	Save(values ...*model.JourneyLocation) error
+	First() (*model.JourneyLocation, error)
+	Take() (*model.JourneyLocation, error)
+	Last() (*model.JourneyLocation, error)
+	Find() ([]*model.JourneyLocation, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.JourneyLocation, err error)
+	FindInBatches(result *[]*model.JourneyLocation, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.JourneyLocation) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IJourneyLocationDo
+	Assign(attrs ...field.AssignExpr) IJourneyLocationDo
+	Joins(fields ...field.RelationField) IJourneyLocationDo
+	Preload(fields ...field.RelationField) IJourneyLocationDo
+	FirstOrInit() (*model.JourneyLocation, error)
+	FirstOrCreate() (*model.JourneyLocation, error)
+	FindByPage(offset int, limit int) (result []*model.JourneyLocation, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IJourneyLocationDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (j journeyLocationDo) Debug() IJourneyLocationDo {
+	return j.withDO(j.DO.Debug())
+}
+
+func (j journeyLocationDo) WithContext(ctx context.Context) IJourneyLocationDo {
+	return j.withDO(j.DO.WithContext(ctx))
+}
+
+func (j journeyLocationDo) ReadDB() IJourneyLocationDo {
+	return j.Clauses(dbresolver.Read)
+}
+
+func (j journeyLocationDo) WriteDB() IJourneyLocationDo {
+	return j.Clauses(dbresolver.Write)
+}
+
+func (j journeyLocationDo) Session(config *gorm.Session) IJourneyLocationDo {
+	return j.withDO(j.DO.Session(config))
+}
+
+func (j journeyLocationDo) Clauses(conds ...clause.Expression) IJourneyLocationDo {
+	return j.withDO(j.DO.Clauses(conds...))
+}
+
+func (j journeyLocationDo) Returning(value interface{}, columns ...string) IJourneyLocationDo {
+	return j.withDO(j.DO.Returning(value, columns...))
+}
+
+func (j journeyLocationDo) Not(conds ...gen.Condition) IJourneyLocationDo {
+	return j.withDO(j.DO.Not(conds...))
+}
+
+func (j journeyLocationDo) Or(conds ...gen.Condition) IJourneyLocationDo {
+	return j.withDO(j.DO.Or(conds...))
+}
+
+func (j journeyLocationDo) Select(conds ...field.Expr) IJourneyLocationDo {
+	return j.withDO(j.DO.Select(conds...))
+}
+
+func (j journeyLocationDo) Where(conds ...gen.Condition) IJourneyLocationDo {
+	return j.withDO(j.DO.Where(conds...))
+}
+
+func (j journeyLocationDo) Order(conds ...field.Expr) IJourneyLocationDo {
+	return j.withDO(j.DO.Order(conds...))
+}
+
+func (j journeyLocationDo) Distinct(cols ...field.Expr) IJourneyLocationDo {
+	return j.withDO(j.DO.Distinct(cols...))
+}
+
+func (j journeyLocationDo) Omit(cols ...field.Expr) IJourneyLocationDo {
+	return j.withDO(j.DO.Omit(cols...))
+}
+
+func (j journeyLocationDo) Join(table schema.Tabler, on ...field.Expr) IJourneyLocationDo {
+	return j.withDO(j.DO.Join(table, on...))
+}
+
+func (j journeyLocationDo) LeftJoin(table schema.Tabler, on ...field.Expr) IJourneyLocationDo {
+	return j.withDO(j.DO.LeftJoin(table, on...))
+}
+
+func (j journeyLocationDo) RightJoin(table schema.Tabler, on ...field.Expr) IJourneyLocationDo {
+	return j.withDO(j.DO.RightJoin(table, on...))
+}
+
+func (j journeyLocationDo) Group(cols ...field.Expr) IJourneyLocationDo {
+	return j.withDO(j.DO.Group(cols...))
+}
+
+func (j journeyLocationDo) Having(conds ...gen.Condition) IJourneyLocationDo {
+	return j.withDO(j.DO.Having(conds...))
+}
+
+func (j journeyLocationDo) Limit(limit int) IJourneyLocationDo {
+	return j.withDO(j.DO.Limit(limit))
+}
+
+func (j journeyLocationDo) Offset(offset int) IJourneyLocationDo {
+	return j.withDO(j.DO.Offset(offset))
+}
+
+func (j journeyLocationDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IJourneyLocationDo {
+	return j.withDO(j.DO.Scopes(funcs...))
+}
+
+func (j journeyLocationDo) Unscoped() IJourneyLocationDo {
+	return j.withDO(j.DO.Unscoped())
+}
+
+func (j journeyLocationDo) Create(values ...*model.JourneyLocation) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return j.DO.Create(values)
+}
+
+func (j journeyLocationDo) CreateInBatches(values []*model.JourneyLocation, batchSize int) error {
+	return j.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (j journeyLocationDo) Save(values ...*model.JourneyLocation) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return j.DO.Save(values)
+}
+
+func (j journeyLocationDo) First() (*model.JourneyLocation, error) {
+	if result, err := j.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JourneyLocation), nil
+	}
+}
+
+func (j journeyLocationDo) Take() (*model.JourneyLocation, error) {
+	if result, err := j.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JourneyLocation), nil
+	}
+}
+
+func (j journeyLocationDo) Last() (*model.JourneyLocation, error) {
+	if result, err := j.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JourneyLocation), nil
+	}
+}
+
+func (j journeyLocationDo) Find() ([]*model.JourneyLocation, error) {
+	result, err := j.DO.Find()
+	return result.([]*model.JourneyLocation), err
+}
+
+func (j journeyLocationDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.JourneyLocation, err error) {
+	buf := make([]*model.JourneyLocation, 0, batchSize)
+	err = j.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (j journeyLocationDo) FindInBatches(result *[]*model.JourneyLocation, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return j.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (j journeyLocationDo) Attrs(attrs ...field.AssignExpr) IJourneyLocationDo {
+	return j.withDO(j.DO.Attrs(attrs...))
+}
+
+func (j journeyLocationDo) Assign(attrs ...field.AssignExpr) IJourneyLocationDo {
+	return j.withDO(j.DO.Assign(attrs...))
+}
+
+func (j journeyLocationDo) Joins(fields ...field.RelationField) IJourneyLocationDo {
+	for _, _f := range fields {
+		j = *j.withDO(j.DO.Joins(_f))
+	}
+	return &j
+}
+
+func (j journeyLocationDo) Preload(fields ...field.RelationField) IJourneyLocationDo {
+	for _, _f := range fields {
+		j = *j.withDO(j.DO.Preload(_f))
+	}
+	return &j
+}
+
+func (j journeyLocationDo) FirstOrInit() (*model.JourneyLocation, error) {
+	if result, err := j.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JourneyLocation), nil
+	}
+}
+
+func (j journeyLocationDo) FirstOrCreate() (*model.JourneyLocation, error) {
+	if result, err := j.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JourneyLocation), nil
+	}
+}
+
+func (j journeyLocationDo) FindByPage(offset int, limit int) (result []*model.JourneyLocation, count int64, err error) {
+	result, err = j.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = j.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (j journeyLocationDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = j.Count()
+	if err != nil {
+		return
+	}
+
+	err = j.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (j journeyLocationDo) Scan(result interface{}) (err error) {
+	return j.DO.Scan(result)
+}
+
+func (j journeyLocationDo) Delete(models ...*model.JourneyLocation) (result gen.ResultInfo, err error) {
+	return j.DO.Delete(models)
+}
+
+func (j *journeyLocationDo) withDO(do gen.Dao) *journeyLocationDo {
+	j.DO = *do.(*gen.DO)
+	return j
+}
@@ -0,0 +1,422 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"AreYouOK/internal/model"
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+func newJourneyOccurrence(db *gorm.DB, opts ...gen.DOOption) journeyOccurrence {
+	_journeyOccurrence := journeyOccurrence{}
+
+	_journeyOccurrence.journeyOccurrenceDo.UseDB(db, opts...)
+	_journeyOccurrence.journeyOccurrenceDo.UseModel(&model.JourneyOccurrence{})
+
+	tableName := _journeyOccurrence.journeyOccurrenceDo.TableName()
+	_journeyOccurrence.ALL = field.NewAsterisk(tableName)
+	_journeyOccurrence.StartAt = field.NewTime(tableName, "start_at")
+	_journeyOccurrence.ReminderSentAt = field.NewTime(tableName, "reminder_sent_at")
+	_journeyOccurrence.JourneyID = field.NewInt64(tableName, "journey_id")
+	_journeyOccurrence.Status = field.NewString(tableName, "status")
+	_journeyOccurrence.SkipReason = field.NewString(tableName, "skip_reason")
+	_journeyOccurrence.CreatedAt = field.NewTime(tableName, "created_at")
+	_journeyOccurrence.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_journeyOccurrence.DeletedAt = field.NewField(tableName, "deleted_at")
+	_journeyOccurrence.ID = field.NewInt64(tableName, "id")
+	_journeyOccurrence.TemplateID = field.NewInt64(tableName, "template_id")
+	_journeyOccurrence.UserID = field.NewInt64(tableName, "user_id")
+
+	_journeyOccurrence.fillFieldMap()
+
+	return _journeyOccurrence
+}
+
+type journeyOccurrence struct {
+	journeyOccurrenceDo
+
+	ALL            field.Asterisk
+	StartAt        field.Time
+	ReminderSentAt field.Time
+	JourneyID      field.Int64
+	Status         field.String
+	SkipReason     field.String
+	CreatedAt      field.Time
+	UpdatedAt      field.Time
+	DeletedAt      field.Field
+	ID             field.Int64
+	TemplateID     field.Int64
+	UserID         field.Int64
+
+	fieldMap map[string]field.Expr
+}
+
+func (j journeyOccurrence) Table(newTableName string) *journeyOccurrence {
+	j.journeyOccurrenceDo.UseTable(newTableName)
+	return j.updateTableName(newTableName)
+}
+
+func (j journeyOccurrence) As(alias string) *journeyOccurrence {
+	j.journeyOccurrenceDo.DO = *(j.journeyOccurrenceDo.As(alias).(*gen.DO))
+	return j.updateTableName(alias)
+}
+
+func (j *journeyOccurrence) updateTableName(table string) *journeyOccurrence {
+	j.ALL = field.NewAsterisk(table)
+	j.StartAt = field.NewTime(table, "start_at")
+	j.ReminderSentAt = field.NewTime(table, "reminder_sent_at")
+	j.JourneyID = field.NewInt64(table, "journey_id")
+	j.Status = field.NewString(table, "status")
+	j.SkipReason = field.NewString(table, "skip_reason")
+	j.CreatedAt = field.NewTime(table, "created_at")
+	j.UpdatedAt = field.NewTime(table, "updated_at")
+	j.DeletedAt = field.NewField(table, "deleted_at")
+	j.ID = field.NewInt64(table, "id")
+	j.TemplateID = field.NewInt64(table, "template_id")
+	j.UserID = field.NewInt64(table, "user_id")
+
+	j.fillFieldMap()
+
+	return j
+}
+
+func (j *journeyOccurrence) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := j.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (j *journeyOccurrence) fillFieldMap() {
+	j.fieldMap = make(map[string]field.Expr, 11)
+	j.fieldMap["start_at"] = j.StartAt
+	j.fieldMap["reminder_sent_at"] = j.ReminderSentAt
+	j.fieldMap["journey_id"] = j.JourneyID
+	j.fieldMap["status"] = j.Status
+	j.fieldMap["skip_reason"] = j.SkipReason
+	j.fieldMap["created_at"] = j.CreatedAt
+	j.fieldMap["updated_at"] = j.UpdatedAt
+	j.fieldMap["deleted_at"] = j.DeletedAt
+	j.fieldMap["id"] = j.ID
+	j.fieldMap["template_id"] = j.TemplateID
+	j.fieldMap["user_id"] = j.UserID
+}
+
+func (j journeyOccurrence) clone(db *gorm.DB) journeyOccurrence {
+	j.journeyOccurrenceDo.ReplaceConnPool(db.Statement.ConnPool)
+	return j
+}
+
+func (j journeyOccurrence) replaceDB(db *gorm.DB) journeyOccurrence {
+	j.journeyOccurrenceDo.ReplaceDB(db)
+	return j
+}
+
+type journeyOccurrenceDo struct{ gen.DO }
+
+type IJourneyOccurrenceDo interface {
+	gen.SubQuery
+	Debug() IJourneyOccurrenceDo
+	WithContext(ctx context.Context) IJourneyOccurrenceDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IJourneyOccurrenceDo
+	WriteDB() IJourneyOccurrenceDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IJourneyOccurrenceDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IJourneyOccurrenceDo
+	Not(conds ...gen.Condition) IJourneyOccurrenceDo
+	Or(conds ...gen.Condition) IJourneyOccurrenceDo
+	Select(conds ...field.Expr) IJourneyOccurrenceDo
+	Where(conds ...gen.Condition) IJourneyOccurrenceDo
+	Order(conds ...field.Expr) IJourneyOccurrenceDo
+	Distinct(cols ...field.Expr) IJourneyOccurrenceDo
+	Omit(cols ...field.Expr) IJourneyOccurrenceDo
+	Join(table schema.Tabler, on ...field.Expr) IJourneyOccurrenceDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IJourneyOccurrenceDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IJourneyOccurrenceDo
+	Group(cols ...field.Expr) IJourneyOccurrenceDo
+	Having(conds ...gen.Condition) IJourneyOccurrenceDo
+	Limit(limit int) IJourneyOccurrenceDo
+	Offset(offset int) IJourneyOccurrenceDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IJourneyOccurrenceDo
+	Unscoped() IJourneyOccurrenceDo
+	Create(values ...*model.JourneyOccurrence) error
+	CreateInBatches(values []*model.JourneyOccurrence, batchSize int) error
+	Save(values ...*model.JourneyOccurrence) error
+	First() (*model.JourneyOccurrence, error)
+	Take() (*model.JourneyOccurrence, error)
+	Last() (*model.JourneyOccurrence, error)
+	Find() ([]*model.JourneyOccurrence, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.JourneyOccurrence, err error)
+	FindInBatches(result *[]*model.JourneyOccurrence, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.JourneyOccurrence) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IJourneyOccurrenceDo
+	Assign(attrs ...field.AssignExpr) IJourneyOccurrenceDo
+	Joins(fields ...field.RelationField) IJourneyOccurrenceDo
+	Preload(fields ...field.RelationField) IJourneyOccurrenceDo
+	FirstOrInit() (*model.JourneyOccurrence, error)
+	FirstOrCreate() (*model.JourneyOccurrence, error)
+	FindByPage(offset int, limit int) (result []*model.JourneyOccurrence, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IJourneyOccurrenceDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (j journeyOccurrenceDo) Debug() IJourneyOccurrenceDo {
+	return j.withDO(j.DO.Debug())
+}
+
+func (j journeyOccurrenceDo) WithContext(ctx context.Context) IJourneyOccurrenceDo {
+	return j.withDO(j.DO.WithContext(ctx))
+}
+
+func (j journeyOccurrenceDo) ReadDB() IJourneyOccurrenceDo {
+	return j.Clauses(dbresolver.Read)
+}
+
+func (j journeyOccurrenceDo) WriteDB() IJourneyOccurrenceDo {
+	return j.Clauses(dbresolver.Write)
+}
+
+func (j journeyOccurrenceDo) Session(config *gorm.Session) IJourneyOccurrenceDo {
+	return j.withDO(j.DO.Session(config))
+}
+
+func (j journeyOccurrenceDo) Clauses(conds ...clause.Expression) IJourneyOccurrenceDo {
+	return j.withDO(j.DO.Clauses(conds...))
+}
+
+func (j journeyOccurrenceDo) Returning(value interface{}, columns ...string) IJourneyOccurrenceDo {
+	return j.withDO(j.DO.Returning(value, columns...))
+}
+
+func (j journeyOccurrenceDo) Not(conds ...gen.Condition) IJourneyOccurrenceDo {
+	return j.withDO(j.DO.Not(conds...))
+}
+
+func (j journeyOccurrenceDo) Or(conds ...gen.Condition) IJourneyOccurrenceDo {
+	return j.withDO(j.DO.Or(conds...))
+}
+
+func (j journeyOccurrenceDo) Select(conds ...field.Expr) IJourneyOccurrenceDo {
+	return j.withDO(j.DO.Select(conds...))
+}
+
+func (j journeyOccurrenceDo) Where(conds ...gen.Condition) IJourneyOccurrenceDo {
+	return j.withDO(j.DO.Where(conds...))
+}
+
+func (j journeyOccurrenceDo) Order(conds ...field.Expr) IJourneyOccurrenceDo {
+	return j.withDO(j.DO.Order(conds...))
+}
+
+func (j journeyOccurrenceDo) Distinct(cols ...field.Expr) IJourneyOccurrenceDo {
+	return j.withDO(j.DO.Distinct(cols...))
+}
+
+func (j journeyOccurrenceDo) Omit(cols ...field.Expr) IJourneyOccurrenceDo {
+	return j.withDO(j.DO.Omit(cols...))
+}
+
+func (j journeyOccurrenceDo) Join(table schema.Tabler, on ...field.Expr) IJourneyOccurrenceDo {
+	return j.withDO(j.DO.Join(table, on...))
+}
+
+func (j journeyOccurrenceDo) LeftJoin(table schema.Tabler, on ...field.Expr) IJourneyOccurrenceDo {
+	return j.withDO(j.DO.LeftJoin(table, on...))
+}
+
+func (j journeyOccurrenceDo) RightJoin(table schema.Tabler, on ...field.Expr) IJourneyOccurrenceDo {
+	return j.withDO(j.DO.RightJoin(table, on...))
+}
+
+func (j journeyOccurrenceDo) Group(cols ...field.Expr) IJourneyOccurrenceDo {
+	return j.withDO(j.DO.Group(cols...))
+}
+
+func (j journeyOccurrenceDo) Having(conds ...gen.Condition) IJourneyOccurrenceDo {
+	return j.withDO(j.DO.Having(conds...))
+}
+
+func (j journeyOccurrenceDo) Limit(limit int) IJourneyOccurrenceDo {
+	return j.withDO(j.DO.Limit(limit))
+}
+
+func (j journeyOccurrenceDo) Offset(offset int) IJourneyOccurrenceDo {
+	return j.withDO(j.DO.Offset(offset))
+}
+
+func (j journeyOccurrenceDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IJourneyOccurrenceDo {
+	return j.withDO(j.DO.Scopes(funcs...))
+}
+
+func (j journeyOccurrenceDo) Unscoped() IJourneyOccurrenceDo {
+	return j.withDO(j.DO.Unscoped())
+}
+
+func (j journeyOccurrenceDo) Create(values ...*model.JourneyOccurrence) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return j.DO.Create(values)
+}
+
+func (j journeyOccurrenceDo) CreateInBatches(values []*model.JourneyOccurrence, batchSize int) error {
+	return j.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (j journeyOccurrenceDo) Save(values ...*model.JourneyOccurrence) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return j.DO.Save(values)
+}
+
+func (j journeyOccurrenceDo) First() (*model.JourneyOccurrence, error) {
+	if result, err := j.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JourneyOccurrence), nil
+	}
+}
+
+func (j journeyOccurrenceDo) Take() (*model.JourneyOccurrence, error) {
+	if result, err := j.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JourneyOccurrence), nil
+	}
+}
+
+func (j journeyOccurrenceDo) Last() (*model.JourneyOccurrence, error) {
+	if result, err := j.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JourneyOccurrence), nil
+	}
+}
+
+func (j journeyOccurrenceDo) Find() ([]*model.JourneyOccurrence, error) {
+	result, err := j.DO.Find()
+	return result.([]*model.JourneyOccurrence), err
+}
+
+func (j journeyOccurrenceDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.JourneyOccurrence, err error) {
+	buf := make([]*model.JourneyOccurrence, 0, batchSize)
+	err = j.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (j journeyOccurrenceDo) FindInBatches(result *[]*model.JourneyOccurrence, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return j.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (j journeyOccurrenceDo) Attrs(attrs ...field.AssignExpr) IJourneyOccurrenceDo {
+	return j.withDO(j.DO.Attrs(attrs...))
+}
+
+func (j journeyOccurrenceDo) Assign(attrs ...field.AssignExpr) IJourneyOccurrenceDo {
+	return j.withDO(j.DO.Assign(attrs...))
+}
+
+func (j journeyOccurrenceDo) Joins(fields ...field.RelationField) IJourneyOccurrenceDo {
+	for _, _f := range fields {
+		j = *j.withDO(j.DO.Joins(_f))
+	}
+	return &j
+}
+
+func (j journeyOccurrenceDo) Preload(fields ...field.RelationField) IJourneyOccurrenceDo {
+	for _, _f := range fields {
+		j = *j.withDO(j.DO.Preload(_f))
+	}
+	return &j
+}
+
+func (j journeyOccurrenceDo) FirstOrInit() (*model.JourneyOccurrence, error) {
+	if result, err := j.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JourneyOccurrence), nil
+	}
+}
+
+func (j journeyOccurrenceDo) FirstOrCreate() (*model.JourneyOccurrence, error) {
+	if result, err := j.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JourneyOccurrence), nil
+	}
+}
+
+func (j journeyOccurrenceDo) FindByPage(offset int, limit int) (result []*model.JourneyOccurrence, count int64, err error) {
+	result, err = j.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = j.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (j journeyOccurrenceDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = j.Count()
+	if err != nil {
+		return
+	}
+
+	err = j.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (j journeyOccurrenceDo) Scan(result interface{}) (err error) {
+	return j.DO.Scan(result)
+}
+
+func (j journeyOccurrenceDo) Delete(models ...*model.JourneyOccurrence) (result gen.ResultInfo, err error) {
+	return j.DO.Delete(models)
+}
+
+func (j *journeyOccurrenceDo) withDO(do gen.Dao) *journeyOccurrenceDo {
+	j.DO = *do.(*gen.DO)
+	return j
+}
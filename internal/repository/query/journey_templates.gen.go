@@ -0,0 +1,434 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"AreYouOK/internal/model"
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+func newJourneyTemplate(db *gorm.DB, opts ...gen.DOOption) journeyTemplate {
+	_journeyTemplate := journeyTemplate{}
+
+	_journeyTemplate.journeyTemplateDo.UseDB(db, opts...)
+	_journeyTemplate.journeyTemplateDo.UseModel(&model.JourneyTemplate{})
+
+	tableName := _journeyTemplate.journeyTemplateDo.TableName()
+	_journeyTemplate.ALL = field.NewAsterisk(tableName)
+	_journeyTemplate.NextOccurrenceAt = field.NewTime(tableName, "next_occurrence_at")
+	_journeyTemplate.Title = field.NewString(tableName, "title")
+	_journeyTemplate.Note = field.NewString(tableName, "note")
+	_journeyTemplate.RRule = field.NewString(tableName, "r_rule")
+	_journeyTemplate.Checkpoints = field.NewField(tableName, "checkpoints")
+	_journeyTemplate.ContactPriorities = field.NewField(tableName, "contact_priorities")
+	_journeyTemplate.AlertMessage = field.NewString(tableName, "alert_message")
+	_journeyTemplate.CreatedAt = field.NewTime(tableName, "created_at")
+	_journeyTemplate.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_journeyTemplate.DeletedAt = field.NewField(tableName, "deleted_at")
+	_journeyTemplate.ID = field.NewInt64(tableName, "id")
+	_journeyTemplate.UserID = field.NewInt64(tableName, "user_id")
+	_journeyTemplate.DurationMinutes = field.NewInt(tableName, "duration_minutes")
+	_journeyTemplate.AutoStart = field.NewBool(tableName, "auto_start")
+
+	_journeyTemplate.fillFieldMap()
+
+	return _journeyTemplate
+}
+
+type journeyTemplate struct {
+	journeyTemplateDo
+
+	ALL               field.Asterisk
+	NextOccurrenceAt  field.Time
+	Title             field.String
+	Note              field.String
+	RRule             field.String
+	Checkpoints       field.Field
+	ContactPriorities field.Field
+	AlertMessage      field.String
+	CreatedAt         field.Time
+	UpdatedAt         field.Time
+	DeletedAt         field.Field
+	ID                field.Int64
+	UserID            field.Int64
+	DurationMinutes   field.Int
+	AutoStart         field.Bool
+
+	fieldMap map[string]field.Expr
+}
+
+func (j journeyTemplate) Table(newTableName string) *journeyTemplate {
+	j.journeyTemplateDo.UseTable(newTableName)
+	return j.updateTableName(newTableName)
+}
+
+func (j journeyTemplate) As(alias string) *journeyTemplate {
+	j.journeyTemplateDo.DO = *(j.journeyTemplateDo.As(alias).(*gen.DO))
+	return j.updateTableName(alias)
+}
+
+func (j *journeyTemplate) updateTableName(table string) *journeyTemplate {
+	j.ALL = field.NewAsterisk(table)
+	j.NextOccurrenceAt = field.NewTime(table, "next_occurrence_at")
+	j.Title = field.NewString(table, "title")
+	j.Note = field.NewString(table, "note")
+	j.RRule = field.NewString(table, "r_rule")
+	j.Checkpoints = field.NewField(table, "checkpoints")
+	j.ContactPriorities = field.NewField(table, "contact_priorities")
+	j.AlertMessage = field.NewString(table, "alert_message")
+	j.CreatedAt = field.NewTime(table, "created_at")
+	j.UpdatedAt = field.NewTime(table, "updated_at")
+	j.DeletedAt = field.NewField(table, "deleted_at")
+	j.ID = field.NewInt64(table, "id")
+	j.UserID = field.NewInt64(table, "user_id")
+	j.DurationMinutes = field.NewInt(table, "duration_minutes")
+	j.AutoStart = field.NewBool(table, "auto_start")
+
+	j.fillFieldMap()
+
+	return j
+}
+
+func (j *journeyTemplate) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := j.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (j *journeyTemplate) fillFieldMap() {
+	j.fieldMap = make(map[string]field.Expr, 14)
+	j.fieldMap["next_occurrence_at"] = j.NextOccurrenceAt
+	j.fieldMap["title"] = j.Title
+	j.fieldMap["note"] = j.Note
+	j.fieldMap["r_rule"] = j.RRule
+	j.fieldMap["checkpoints"] = j.Checkpoints
+	j.fieldMap["contact_priorities"] = j.ContactPriorities
+	j.fieldMap["alert_message"] = j.AlertMessage
+	j.fieldMap["created_at"] = j.CreatedAt
+	j.fieldMap["updated_at"] = j.UpdatedAt
+	j.fieldMap["deleted_at"] = j.DeletedAt
+	j.fieldMap["id"] = j.ID
+	j.fieldMap["user_id"] = j.UserID
+	j.fieldMap["duration_minutes"] = j.DurationMinutes
+	j.fieldMap["auto_start"] = j.AutoStart
+}
+
+func (j journeyTemplate) clone(db *gorm.DB) journeyTemplate {
+	j.journeyTemplateDo.ReplaceConnPool(db.Statement.ConnPool)
+	return j
+}
+
+func (j journeyTemplate) replaceDB(db *gorm.DB) journeyTemplate {
+	j.journeyTemplateDo.ReplaceDB(db)
+	return j
+}
+
+type journeyTemplateDo struct{ gen.DO }
+
+type IJourneyTemplateDo interface {
+	gen.SubQuery
+	Debug() IJourneyTemplateDo
+	WithContext(ctx context.Context) IJourneyTemplateDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IJourneyTemplateDo
+	WriteDB() IJourneyTemplateDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IJourneyTemplateDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IJourneyTemplateDo
+	Not(conds ...gen.Condition) IJourneyTemplateDo
+	Or(conds ...gen.Condition) IJourneyTemplateDo
+	Select(conds ...field.Expr) IJourneyTemplateDo
+	Where(conds ...gen.Condition) IJourneyTemplateDo
+	Order(conds ...field.Expr) IJourneyTemplateDo
+	Distinct(cols ...field.Expr) IJourneyTemplateDo
+	Omit(cols ...field.Expr) IJourneyTemplateDo
+	Join(table schema.Tabler, on ...field.Expr) IJourneyTemplateDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IJourneyTemplateDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IJourneyTemplateDo
+	Group(cols ...field.Expr) IJourneyTemplateDo
+	Having(conds ...gen.Condition) IJourneyTemplateDo
+	Limit(limit int) IJourneyTemplateDo
+	Offset(offset int) IJourneyTemplateDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IJourneyTemplateDo
+	Unscoped() IJourneyTemplateDo
+	Create(values ...*model.JourneyTemplate) error
+	CreateInBatches(values []*model.JourneyTemplate, batchSize int) error
+	Save(values ...*model.JourneyTemplate) error
+	First() (*model.JourneyTemplate, error)
+	Take() (*model.JourneyTemplate, error)
+	Last() (*model.JourneyTemplate, error)
+	Find() ([]*model.JourneyTemplate, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.JourneyTemplate, err error)
+	FindInBatches(result *[]*model.JourneyTemplate, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.JourneyTemplate) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IJourneyTemplateDo
+	Assign(attrs ...field.AssignExpr) IJourneyTemplateDo
+	Joins(fields ...field.RelationField) IJourneyTemplateDo
+	Preload(fields ...field.RelationField) IJourneyTemplateDo
+	FirstOrInit() (*model.JourneyTemplate, error)
+	FirstOrCreate() (*model.JourneyTemplate, error)
+	FindByPage(offset int, limit int) (result []*model.JourneyTemplate, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IJourneyTemplateDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (j journeyTemplateDo) Debug() IJourneyTemplateDo {
+	return j.withDO(j.DO.Debug())
+}
+
+func (j journeyTemplateDo) WithContext(ctx context.Context) IJourneyTemplateDo {
+	return j.withDO(j.DO.WithContext(ctx))
+}
+
+func (j journeyTemplateDo) ReadDB() IJourneyTemplateDo {
+	return j.Clauses(dbresolver.Read)
+}
+
+func (j journeyTemplateDo) WriteDB() IJourneyTemplateDo {
+	return j.Clauses(dbresolver.Write)
+}
+
+func (j journeyTemplateDo) Session(config *gorm.Session) IJourneyTemplateDo {
+	return j.withDO(j.DO.Session(config))
+}
+
+func (j journeyTemplateDo) Clauses(conds ...clause.Expression) IJourneyTemplateDo {
+	return j.withDO(j.DO.Clauses(conds...))
+}
+
+func (j journeyTemplateDo) Returning(value interface{}, columns ...string) IJourneyTemplateDo {
+	return j.withDO(j.DO.Returning(value, columns...))
+}
+
+func (j journeyTemplateDo) Not(conds ...gen.Condition) IJourneyTemplateDo {
+	return j.withDO(j.DO.Not(conds...))
+}
+
+func (j journeyTemplateDo) Or(conds ...gen.Condition) IJourneyTemplateDo {
+	return j.withDO(j.DO.Or(conds...))
+}
+
+func (j journeyTemplateDo) Select(conds ...field.Expr) IJourneyTemplateDo {
+	return j.withDO(j.DO.Select(conds...))
+}
+
+func (j journeyTemplateDo) Where(conds ...gen.Condition) IJourneyTemplateDo {
+	return j.withDO(j.DO.Where(conds...))
+}
+
+func (j journeyTemplateDo) Order(conds ...field.Expr) IJourneyTemplateDo {
+	return j.withDO(j.DO.Order(conds...))
+}
+
+func (j journeyTemplateDo) Distinct(cols ...field.Expr) IJourneyTemplateDo {
+	return j.withDO(j.DO.Distinct(cols...))
+}
+
+func (j journeyTemplateDo) Omit(cols ...field.Expr) IJourneyTemplateDo {
+	return j.withDO(j.DO.Omit(cols...))
+}
+
+func (j journeyTemplateDo) Join(table schema.Tabler, on ...field.Expr) IJourneyTemplateDo {
+	return j.withDO(j.DO.Join(table, on...))
+}
+
+func (j journeyTemplateDo) LeftJoin(table schema.Tabler, on ...field.Expr) IJourneyTemplateDo {
+	return j.withDO(j.DO.LeftJoin(table, on...))
+}
+
+func (j journeyTemplateDo) RightJoin(table schema.Tabler, on ...field.Expr) IJourneyTemplateDo {
+	return j.withDO(j.DO.RightJoin(table, on...))
+}
+
+func (j journeyTemplateDo) Group(cols ...field.Expr) IJourneyTemplateDo {
+	return j.withDO(j.DO.Group(cols...))
+}
+
+func (j journeyTemplateDo) Having(conds ...gen.Condition) IJourneyTemplateDo {
+	return j.withDO(j.DO.Having(conds...))
+}
+
+func (j journeyTemplateDo) Limit(limit int) IJourneyTemplateDo {
+	return j.withDO(j.DO.Limit(limit))
+}
+
+func (j journeyTemplateDo) Offset(offset int) IJourneyTemplateDo {
+	return j.withDO(j.DO.Offset(offset))
+}
+
+func (j journeyTemplateDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IJourneyTemplateDo {
+	return j.withDO(j.DO.Scopes(funcs...))
+}
+
+func (j journeyTemplateDo) Unscoped() IJourneyTemplateDo {
+	return j.withDO(j.DO.Unscoped())
+}
+
+func (j journeyTemplateDo) Create(values ...*model.JourneyTemplate) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return j.DO.Create(values)
+}
+
+func (j journeyTemplateDo) CreateInBatches(values []*model.JourneyTemplate, batchSize int) error {
+	return j.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (j journeyTemplateDo) Save(values ...*model.JourneyTemplate) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return j.DO.Save(values)
+}
+
+func (j journeyTemplateDo) First() (*model.JourneyTemplate, error) {
+	if result, err := j.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JourneyTemplate), nil
+	}
+}
+
+func (j journeyTemplateDo) Take() (*model.JourneyTemplate, error) {
+	if result, err := j.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JourneyTemplate), nil
+	}
+}
+
+func (j journeyTemplateDo) Last() (*model.JourneyTemplate, error) {
+	if result, err := j.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JourneyTemplate), nil
+	}
+}
+
+func (j journeyTemplateDo) Find() ([]*model.JourneyTemplate, error) {
+	result, err := j.DO.Find()
+	return result.([]*model.JourneyTemplate), err
+}
+
+func (j journeyTemplateDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.JourneyTemplate, err error) {
+	buf := make([]*model.JourneyTemplate, 0, batchSize)
+	err = j.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (j journeyTemplateDo) FindInBatches(result *[]*model.JourneyTemplate, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return j.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (j journeyTemplateDo) Attrs(attrs ...field.AssignExpr) IJourneyTemplateDo {
+	return j.withDO(j.DO.Attrs(attrs...))
+}
+
+func (j journeyTemplateDo) Assign(attrs ...field.AssignExpr) IJourneyTemplateDo {
+	return j.withDO(j.DO.Assign(attrs...))
+}
+
+func (j journeyTemplateDo) Joins(fields ...field.RelationField) IJourneyTemplateDo {
+	for _, _f := range fields {
+		j = *j.withDO(j.DO.Joins(_f))
+	}
+	return &j
+}
+
+func (j journeyTemplateDo) Preload(fields ...field.RelationField) IJourneyTemplateDo {
+	for _, _f := range fields {
+		j = *j.withDO(j.DO.Preload(_f))
+	}
+	return &j
+}
+
+func (j journeyTemplateDo) FirstOrInit() (*model.JourneyTemplate, error) {
+	if result, err := j.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JourneyTemplate), nil
+	}
+}
+
+func (j journeyTemplateDo) FirstOrCreate() (*model.JourneyTemplate, error) {
+	if result, err := j.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.JourneyTemplate), nil
+	}
+}
+
+func (j journeyTemplateDo) FindByPage(offset int, limit int) (result []*model.JourneyTemplate, count int64, err error) {
+	result, err = j.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = j.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (j journeyTemplateDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = j.Count()
+	if err != nil {
+		return
+	}
+
+	err = j.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (j journeyTemplateDo) Scan(result interface{}) (err error) {
+	return j.DO.Scan(result)
+}
+
+func (j journeyTemplateDo) Delete(models ...*model.JourneyTemplate) (result gen.ResultInfo, err error) {
+	return j.DO.Delete(models)
+}
+
+func (j *journeyTemplateDo) withDO(do gen.Dao) *journeyTemplateDo {
+	j.DO = *do.(*gen.DO)
+	return j
+}
@@ -37,6 +37,16 @@ func newJourney(db *gorm.DB, opts ...gen.DOOption) journey {
 	_journey.Note = field.NewString(tableName, "note")
 	_journey.Status = field.NewString(tableName, "status")
 	_journey.AlertStatus = field.NewString(tableName, "alert_status")
+	_journey.DestLatitude = field.NewFloat64(tableName, "dest_latitude")
+	_journey.DestLongitude = field.NewFloat64(tableName, "dest_longitude")
+	_journey.GeofenceLeftAt = field.NewTime(tableName, "geofence_left_at")
+	_journey.CompletedBy = field.NewString(tableName, "completed_by")
+	_journey.DestRadius = field.NewInt(tableName, "dest_radius")
+	_journey.ShareTokenHash = field.NewString(tableName, "share_token_hash")
+	_journey.ShareExpiresAt = field.NewTime(tableName, "share_expires_at")
+	_journey.ShareLocation = field.NewBool(tableName, "share_location")
+	_journey.ContactPriorities = field.NewField(tableName, "contact_priorities")
+	_journey.AlertMessage = field.NewString(tableName, "alert_message")
 	_journey.TimeoutMessageID = field.NewString(tableName, "timeout_message_id")
 	_journey.CreatedAt = field.NewTime(tableName, "created_at")
 	_journey.UpdatedAt = field.NewTime(tableName, "updated_at")
@@ -44,6 +54,8 @@ func newJourney(db *gorm.DB, opts ...gen.DOOption) journey {
 	_journey.ID = field.NewInt64(tableName, "id")
 	_journey.UserID = field.NewInt64(tableName, "user_id")
 	_journey.AlertAttempts = field.NewInt(tableName, "alert_attempts")
+	_journey.ExtensionCount = field.NewInt(tableName, "extension_count")
+	_journey.ExtendedMinutes = field.NewInt(tableName, "extended_minutes")
 
 	_journey.fillFieldMap()
 
@@ -63,6 +75,16 @@ type journey struct {
 	Note               field.String
 	Status             field.String
 	AlertStatus        field.String
+	DestLatitude       field.Float64
+	DestLongitude      field.Float64
+	GeofenceLeftAt     field.Time
+	CompletedBy        field.String
+	DestRadius         field.Int
+	ShareTokenHash     field.String
+	ShareExpiresAt     field.Time
+	ShareLocation      field.Bool
+	ContactPriorities  field.Field
+	AlertMessage       field.String
 	TimeoutMessageID   field.String
 	CreatedAt          field.Time
 	UpdatedAt          field.Time
@@ -70,6 +92,8 @@ type journey struct {
 	ID                 field.Int64
 	UserID             field.Int64
 	AlertAttempts      field.Int
+	ExtensionCount     field.Int
+	ExtendedMinutes    field.Int
 
 	fieldMap map[string]field.Expr
 }
@@ -95,6 +119,16 @@ func (j *journey) updateTableName(table string) *journey {
 	j.Note = field.NewString(table, "note")
 	j.Status = field.NewString(table, "status")
 	j.AlertStatus = field.NewString(table, "alert_status")
+	j.DestLatitude = field.NewFloat64(table, "dest_latitude")
+	j.DestLongitude = field.NewFloat64(table, "dest_longitude")
+	j.GeofenceLeftAt = field.NewTime(table, "geofence_left_at")
+	j.CompletedBy = field.NewString(table, "completed_by")
+	j.DestRadius = field.NewInt(table, "dest_radius")
+	j.ShareTokenHash = field.NewString(table, "share_token_hash")
+	j.ShareExpiresAt = field.NewTime(table, "share_expires_at")
+	j.ShareLocation = field.NewBool(table, "share_location")
+	j.ContactPriorities = field.NewField(table, "contact_priorities")
+	j.AlertMessage = field.NewString(table, "alert_message")
 	j.TimeoutMessageID = field.NewString(table, "timeout_message_id")
 	j.CreatedAt = field.NewTime(table, "created_at")
 	j.UpdatedAt = field.NewTime(table, "updated_at")
@@ -102,6 +136,8 @@ func (j *journey) updateTableName(table string) *journey {
 	j.ID = field.NewInt64(table, "id")
 	j.UserID = field.NewInt64(table, "user_id")
 	j.AlertAttempts = field.NewInt(table, "alert_attempts")
+	j.ExtensionCount = field.NewInt(table, "extension_count")
+	j.ExtendedMinutes = field.NewInt(table, "extended_minutes")
 
 	j.fillFieldMap()
 
@@ -118,7 +154,7 @@ func (j *journey) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (j *journey) fillFieldMap() {
-	j.fieldMap = make(map[string]field.Expr, 16)
+	j.fieldMap = make(map[string]field.Expr, 28)
 	j.fieldMap["expected_return_time"] = j.ExpectedReturnTime
 	j.fieldMap["actual_return_time"] = j.ActualReturnTime
 	j.fieldMap["reminder_sent_at"] = j.ReminderSentAt
@@ -128,6 +164,16 @@ func (j *journey) fillFieldMap() {
 	j.fieldMap["note"] = j.Note
 	j.fieldMap["status"] = j.Status
 	j.fieldMap["alert_status"] = j.AlertStatus
+	j.fieldMap["dest_latitude"] = j.DestLatitude
+	j.fieldMap["dest_longitude"] = j.DestLongitude
+	j.fieldMap["geofence_left_at"] = j.GeofenceLeftAt
+	j.fieldMap["completed_by"] = j.CompletedBy
+	j.fieldMap["dest_radius"] = j.DestRadius
+	j.fieldMap["share_token_hash"] = j.ShareTokenHash
+	j.fieldMap["share_expires_at"] = j.ShareExpiresAt
+	j.fieldMap["share_location"] = j.ShareLocation
+	j.fieldMap["contact_priorities"] = j.ContactPriorities
+	j.fieldMap["alert_message"] = j.AlertMessage
 	j.fieldMap["timeout_message_id"] = j.TimeoutMessageID
 	j.fieldMap["created_at"] = j.CreatedAt
 	j.fieldMap["updated_at"] = j.UpdatedAt
@@ -135,6 +181,8 @@ func (j *journey) fillFieldMap() {
 	j.fieldMap["id"] = j.ID
 	j.fieldMap["user_id"] = j.UserID
 	j.fieldMap["alert_attempts"] = j.AlertAttempts
+	j.fieldMap["extension_count"] = j.ExtensionCount
+	j.fieldMap["extended_minutes"] = j.ExtendedMinutes
 }
 
 func (j journey) clone(db *gorm.DB) journey {
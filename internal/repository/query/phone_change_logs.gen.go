@@ -0,0 +1,422 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"AreYouOK/internal/model"
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+func newPhoneChangeLog(db *gorm.DB, opts ...gen.DOOption) phoneChangeLog {
+	_phoneChangeLog := phoneChangeLog{}
+
+	_phoneChangeLog.phoneChangeLogDo.UseDB(db, opts...)
+	_phoneChangeLog.phoneChangeLogDo.UseModel(&model.PhoneChangeLog{})
+
+	tableName := _phoneChangeLog.phoneChangeLogDo.TableName()
+	_phoneChangeLog.ALL = field.NewAsterisk(tableName)
+	_phoneChangeLog.ChangedAt = field.NewTime(tableName, "changed_at")
+	_phoneChangeLog.OldPhoneHash = field.NewString(tableName, "old_phone_hash")
+	_phoneChangeLog.NewPhoneHash = field.NewString(tableName, "new_phone_hash")
+	_phoneChangeLog.IP = field.NewString(tableName, "ip")
+	_phoneChangeLog.CreatedAt = field.NewTime(tableName, "created_at")
+	_phoneChangeLog.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_phoneChangeLog.DeletedAt = field.NewField(tableName, "deleted_at")
+	_phoneChangeLog.ID = field.NewInt64(tableName, "id")
+	_phoneChangeLog.UserID = field.NewInt64(tableName, "user_id")
+	_phoneChangeLog.SessionID = field.NewInt64(tableName, "session_id")
+	_phoneChangeLog.OldNotified = field.NewBool(tableName, "old_notified")
+
+	_phoneChangeLog.fillFieldMap()
+
+	return _phoneChangeLog
+}
+
+type phoneChangeLog struct {
+	phoneChangeLogDo
+
+	ALL          field.Asterisk
+	ChangedAt    field.Time
+	OldPhoneHash field.String
+	NewPhoneHash field.String
+	IP           field.String
+	CreatedAt    field.Time
+	UpdatedAt    field.Time
+	DeletedAt    field.Field
+	ID           field.Int64
+	UserID       field.Int64
+	SessionID    field.Int64
+	OldNotified  field.Bool
+
+	fieldMap map[string]field.Expr
+}
+
+func (p phoneChangeLog) Table(newTableName string) *phoneChangeLog {
+	p.phoneChangeLogDo.UseTable(newTableName)
+	return p.updateTableName(newTableName)
+}
+
+func (p phoneChangeLog) As(alias string) *phoneChangeLog {
+	p.phoneChangeLogDo.DO = *(p.phoneChangeLogDo.As(alias).(*gen.DO))
+	return p.updateTableName(alias)
+}
+
+func (p *phoneChangeLog) updateTableName(table string) *phoneChangeLog {
+	p.ALL = field.NewAsterisk(table)
+	p.ChangedAt = field.NewTime(table, "changed_at")
+	p.OldPhoneHash = field.NewString(table, "old_phone_hash")
+	p.NewPhoneHash = field.NewString(table, "new_phone_hash")
+	p.IP = field.NewString(table, "ip")
+	p.CreatedAt = field.NewTime(table, "created_at")
+	p.UpdatedAt = field.NewTime(table, "updated_at")
+	p.DeletedAt = field.NewField(table, "deleted_at")
+	p.ID = field.NewInt64(table, "id")
+	p.UserID = field.NewInt64(table, "user_id")
+	p.SessionID = field.NewInt64(table, "session_id")
+	p.OldNotified = field.NewBool(table, "old_notified")
+
+	p.fillFieldMap()
+
+	return p
+}
+
+func (p *phoneChangeLog) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := p.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (p *phoneChangeLog) fillFieldMap() {
+	p.fieldMap = make(map[string]field.Expr, 11)
+	p.fieldMap["changed_at"] = p.ChangedAt
+	p.fieldMap["old_phone_hash"] = p.OldPhoneHash
+	p.fieldMap["new_phone_hash"] = p.NewPhoneHash
+	p.fieldMap["ip"] = p.IP
+	p.fieldMap["created_at"] = p.CreatedAt
+	p.fieldMap["updated_at"] = p.UpdatedAt
+	p.fieldMap["deleted_at"] = p.DeletedAt
+	p.fieldMap["id"] = p.ID
+	p.fieldMap["user_id"] = p.UserID
+	p.fieldMap["session_id"] = p.SessionID
+	p.fieldMap["old_notified"] = p.OldNotified
+}
+
+func (p phoneChangeLog) clone(db *gorm.DB) phoneChangeLog {
+	p.phoneChangeLogDo.ReplaceConnPool(db.Statement.ConnPool)
+	return p
+}
+
+func (p phoneChangeLog) replaceDB(db *gorm.DB) phoneChangeLog {
+	p.phoneChangeLogDo.ReplaceDB(db)
+	return p
+}
+
+type phoneChangeLogDo struct{ gen.DO }
+
+type IPhoneChangeLogDo interface {
+	gen.SubQuery
+	Debug() IPhoneChangeLogDo
+	WithContext(ctx context.Context) IPhoneChangeLogDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IPhoneChangeLogDo
+	WriteDB() IPhoneChangeLogDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IPhoneChangeLogDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IPhoneChangeLogDo
+	Not(conds ...gen.Condition) IPhoneChangeLogDo
+	Or(conds ...gen.Condition) IPhoneChangeLogDo
+	Select(conds ...field.Expr) IPhoneChangeLogDo
+	Where(conds ...gen.Condition) IPhoneChangeLogDo
+	Order(conds ...field.Expr) IPhoneChangeLogDo
+	Distinct(cols ...field.Expr) IPhoneChangeLogDo
+	Omit(cols ...field.Expr) IPhoneChangeLogDo
+	Join(table schema.Tabler, on ...field.Expr) IPhoneChangeLogDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IPhoneChangeLogDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IPhoneChangeLogDo
+	Group(cols ...field.Expr) IPhoneChangeLogDo
+	Having(conds ...gen.Condition) IPhoneChangeLogDo
+	Limit(limit int) IPhoneChangeLogDo
+	Offset(offset int) IPhoneChangeLogDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IPhoneChangeLogDo
+	Unscoped() IPhoneChangeLogDo
+	Create(values ...*model.PhoneChangeLog) error
+	CreateInBatches(values []*model.PhoneChangeLog, batchSize int) error
+	Save(values ...*model.PhoneChangeLog) error
+	First() (*model.PhoneChangeLog, error)
+	Take() (*model.PhoneChangeLog, error)
+	Last() (*model.PhoneChangeLog, error)
+	Find() ([]*model.PhoneChangeLog, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.PhoneChangeLog, err error)
+	FindInBatches(result *[]*model.PhoneChangeLog, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.PhoneChangeLog) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IPhoneChangeLogDo
+	Assign(attrs ...field.AssignExpr) IPhoneChangeLogDo
+	Joins(fields ...field.RelationField) IPhoneChangeLogDo
+	Preload(fields ...field.RelationField) IPhoneChangeLogDo
+	FirstOrInit() (*model.PhoneChangeLog, error)
+	FirstOrCreate() (*model.PhoneChangeLog, error)
+	FindByPage(offset int, limit int) (result []*model.PhoneChangeLog, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IPhoneChangeLogDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (p phoneChangeLogDo) Debug() IPhoneChangeLogDo {
+	return p.withDO(p.DO.Debug())
+}
+
+func (p phoneChangeLogDo) WithContext(ctx context.Context) IPhoneChangeLogDo {
+	return p.withDO(p.DO.WithContext(ctx))
+}
+
+func (p phoneChangeLogDo) ReadDB() IPhoneChangeLogDo {
+	return p.Clauses(dbresolver.Read)
+}
+
+func (p phoneChangeLogDo) WriteDB() IPhoneChangeLogDo {
+	return p.Clauses(dbresolver.Write)
+}
+
+func (p phoneChangeLogDo) Session(config *gorm.Session) IPhoneChangeLogDo {
+	return p.withDO(p.DO.Session(config))
+}
+
+func (p phoneChangeLogDo) Clauses(conds ...clause.Expression) IPhoneChangeLogDo {
+	return p.withDO(p.DO.Clauses(conds...))
+}
+
+func (p phoneChangeLogDo) Returning(value interface{}, columns ...string) IPhoneChangeLogDo {
+	return p.withDO(p.DO.Returning(value, columns...))
+}
+
+func (p phoneChangeLogDo) Not(conds ...gen.Condition) IPhoneChangeLogDo {
+	return p.withDO(p.DO.Not(conds...))
+}
+
+func (p phoneChangeLogDo) Or(conds ...gen.Condition) IPhoneChangeLogDo {
+	return p.withDO(p.DO.Or(conds...))
+}
+
+func (p phoneChangeLogDo) Select(conds ...field.Expr) IPhoneChangeLogDo {
+	return p.withDO(p.DO.Select(conds...))
+}
+
+func (p phoneChangeLogDo) Where(conds ...gen.Condition) IPhoneChangeLogDo {
+	return p.withDO(p.DO.Where(conds...))
+}
+
+func (p phoneChangeLogDo) Order(conds ...field.Expr) IPhoneChangeLogDo {
+	return p.withDO(p.DO.Order(conds...))
+}
+
+func (p phoneChangeLogDo) Distinct(cols ...field.Expr) IPhoneChangeLogDo {
+	return p.withDO(p.DO.Distinct(cols...))
+}
+
+func (p phoneChangeLogDo) Omit(cols ...field.Expr) IPhoneChangeLogDo {
+	return p.withDO(p.DO.Omit(cols...))
+}
+
+func (p phoneChangeLogDo) Join(table schema.Tabler, on ...field.Expr) IPhoneChangeLogDo {
+	return p.withDO(p.DO.Join(table, on...))
+}
+
+func (p phoneChangeLogDo) LeftJoin(table schema.Tabler, on ...field.Expr) IPhoneChangeLogDo {
+	return p.withDO(p.DO.LeftJoin(table, on...))
+}
+
+func (p phoneChangeLogDo) RightJoin(table schema.Tabler, on ...field.Expr) IPhoneChangeLogDo {
+	return p.withDO(p.DO.RightJoin(table, on...))
+}
+
+func (p phoneChangeLogDo) Group(cols ...field.Expr) IPhoneChangeLogDo {
+	return p.withDO(p.DO.Group(cols...))
+}
+
+func (p phoneChangeLogDo) Having(conds ...gen.Condition) IPhoneChangeLogDo {
+	return p.withDO(p.DO.Having(conds...))
+}
+
+func (p phoneChangeLogDo) Limit(limit int) IPhoneChangeLogDo {
+	return p.withDO(p.DO.Limit(limit))
+}
+
+func (p phoneChangeLogDo) Offset(offset int) IPhoneChangeLogDo {
+	return p.withDO(p.DO.Offset(offset))
+}
+
+func (p phoneChangeLogDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IPhoneChangeLogDo {
+	return p.withDO(p.DO.Scopes(funcs...))
+}
+
+func (p phoneChangeLogDo) Unscoped() IPhoneChangeLogDo {
+	return p.withDO(p.DO.Unscoped())
+}
+
+func (p phoneChangeLogDo) Create(values ...*model.PhoneChangeLog) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return p.DO.Create(values)
+}
+
+func (p phoneChangeLogDo) CreateInBatches(values []*model.PhoneChangeLog, batchSize int) error {
+	return p.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (p phoneChangeLogDo) Save(values ...*model.PhoneChangeLog) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return p.DO.Save(values)
+}
+
+func (p phoneChangeLogDo) First() (*model.PhoneChangeLog, error) {
+	if result, err := p.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.PhoneChangeLog), nil
+	}
+}
+
+func (p phoneChangeLogDo) Take() (*model.PhoneChangeLog, error) {
+	if result, err := p.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.PhoneChangeLog), nil
+	}
+}
+
+func (p phoneChangeLogDo) Last() (*model.PhoneChangeLog, error) {
+	if result, err := p.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.PhoneChangeLog), nil
+	}
+}
+
+func (p phoneChangeLogDo) Find() ([]*model.PhoneChangeLog, error) {
+	result, err := p.DO.Find()
+	return result.([]*model.PhoneChangeLog), err
+}
+
+func (p phoneChangeLogDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.PhoneChangeLog, err error) {
+	buf := make([]*model.PhoneChangeLog, 0, batchSize)
+	err = p.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (p phoneChangeLogDo) FindInBatches(result *[]*model.PhoneChangeLog, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return p.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (p phoneChangeLogDo) Attrs(attrs ...field.AssignExpr) IPhoneChangeLogDo {
+	return p.withDO(p.DO.Attrs(attrs...))
+}
+
+func (p phoneChangeLogDo) Assign(attrs ...field.AssignExpr) IPhoneChangeLogDo {
+	return p.withDO(p.DO.Assign(attrs...))
+}
+
+func (p phoneChangeLogDo) Joins(fields ...field.RelationField) IPhoneChangeLogDo {
+	for _, _f := range fields {
+		p = *p.withDO(p.DO.Joins(_f))
+	}
+	return &p
+}
+
+func (p phoneChangeLogDo) Preload(fields ...field.RelationField) IPhoneChangeLogDo {
+	for _, _f := range fields {
+		p = *p.withDO(p.DO.Preload(_f))
+	}
+	return &p
+}
+
+func (p phoneChangeLogDo) FirstOrInit() (*model.PhoneChangeLog, error) {
+	if result, err := p.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.PhoneChangeLog), nil
+	}
+}
+
+func (p phoneChangeLogDo) FirstOrCreate() (*model.PhoneChangeLog, error) {
+	if result, err := p.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.PhoneChangeLog), nil
+	}
+}
+
+func (p phoneChangeLogDo) FindByPage(offset int, limit int) (result []*model.PhoneChangeLog, count int64, err error) {
+	result, err = p.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = p.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (p phoneChangeLogDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = p.Count()
+	if err != nil {
+		return
+	}
+
+	err = p.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (p phoneChangeLogDo) Scan(result interface{}) (err error) {
+	return p.DO.Scan(result)
+}
+
+func (p phoneChangeLogDo) Delete(models ...*model.PhoneChangeLog) (result gen.ResultInfo, err error) {
+	return p.DO.Delete(models)
+}
+
+func (p *phoneChangeLogDo) withDO(do gen.Dao) *phoneChangeLogDo {
+	p.DO = *do.(*gen.DO)
+	return p
+}
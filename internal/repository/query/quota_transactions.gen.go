@@ -36,6 +36,7 @@ func newQuotaTransaction(db *gorm.DB, opts ...gen.DOOption) quotaTransaction {
 	_quotaTransaction.DeletedAt = field.NewField(tableName, "deleted_at")
 	_quotaTransaction.ID = field.NewInt64(tableName, "id")
 	_quotaTransaction.UserID = field.NewInt64(tableName, "user_id")
+	_quotaTransaction.WalletID = field.NewInt64(tableName, "wallet_id")
 	_quotaTransaction.Amount = field.NewInt(tableName, "amount")
 	_quotaTransaction.BalanceAfter = field.NewInt(tableName, "balance_after")
 
@@ -56,6 +57,7 @@ type quotaTransaction struct {
 	DeletedAt       field.Field
 	ID              field.Int64
 	UserID          field.Int64
+	WalletID        field.Int64
 	Amount          field.Int
 	BalanceAfter    field.Int
 
@@ -82,6 +84,7 @@ func (q *quotaTransaction) updateTableName(table string) *quotaTransaction {
 	q.DeletedAt = field.NewField(table, "deleted_at")
 	q.ID = field.NewInt64(table, "id")
 	q.UserID = field.NewInt64(table, "user_id")
+	q.WalletID = field.NewInt64(table, "wallet_id")
 	q.Amount = field.NewInt(table, "amount")
 	q.BalanceAfter = field.NewInt(table, "balance_after")
 
@@ -100,7 +103,7 @@ func (q *quotaTransaction) GetFieldByName(fieldName string) (field.OrderExpr, bo
 }
 
 func (q *quotaTransaction) fillFieldMap() {
-	q.fieldMap = make(map[string]field.Expr, 10)
+	q.fieldMap = make(map[string]field.Expr, 11)
 	q.fieldMap["channel"] = q.Channel
 	q.fieldMap["transaction_type"] = q.TransactionType
 	q.fieldMap["reason"] = q.Reason
@@ -109,6 +112,7 @@ func (q *quotaTransaction) fillFieldMap() {
 	q.fieldMap["deleted_at"] = q.DeletedAt
 	q.fieldMap["id"] = q.ID
 	q.fieldMap["user_id"] = q.UserID
+	q.fieldMap["wallet_id"] = q.WalletID
 	q.fieldMap["amount"] = q.Amount
 	q.fieldMap["balance_after"] = q.BalanceAfter
 }
@@ -29,6 +29,7 @@ func newQuotaWallet(db *gorm.DB, opts ...gen.DOOption) quotaWallet {
 	_quotaWallet.ALL = field.NewAsterisk(tableName)
 	_quotaWallet.ID = field.NewInt64(tableName, "id")
 	_quotaWallet.UserID = field.NewInt64(tableName, "user_id")
+	_quotaWallet.GroupID = field.NewInt64(tableName, "group_id")
 	_quotaWallet.Channel = field.NewString(tableName, "channel")
 	_quotaWallet.AvailableAmount = field.NewInt(tableName, "available_amount")
 	_quotaWallet.FrozenAmount = field.NewInt(tableName, "frozen_amount")
@@ -48,6 +49,7 @@ type quotaWallet struct {
 	ALL             field.Asterisk
 	ID              field.Int64
 	UserID          field.Int64
+	GroupID         field.Int64
 	Channel         field.String
 	AvailableAmount field.Int
 	FrozenAmount    field.Int
@@ -73,6 +75,7 @@ func (q *quotaWallet) updateTableName(table string) *quotaWallet {
 	q.ALL = field.NewAsterisk(table)
 	q.ID = field.NewInt64(table, "id")
 	q.UserID = field.NewInt64(table, "user_id")
+	q.GroupID = field.NewInt64(table, "group_id")
 	q.Channel = field.NewString(table, "channel")
 	q.AvailableAmount = field.NewInt(table, "available_amount")
 	q.FrozenAmount = field.NewInt(table, "frozen_amount")
@@ -96,9 +99,10 @@ func (q *quotaWallet) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (q *quotaWallet) fillFieldMap() {
-	q.fieldMap = make(map[string]field.Expr, 9)
+	q.fieldMap = make(map[string]field.Expr, 10)
 	q.fieldMap["id"] = q.ID
 	q.fieldMap["user_id"] = q.UserID
+	q.fieldMap["group_id"] = q.GroupID
 	q.fieldMap["channel"] = q.Channel
 	q.fieldMap["available_amount"] = q.AvailableAmount
 	q.fieldMap["frozen_amount"] = q.FrozenAmount
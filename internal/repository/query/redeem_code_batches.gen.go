@@ -0,0 +1,426 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"AreYouOK/internal/model"
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+func newRedeemCodeBatch(db *gorm.DB, opts ...gen.DOOption) redeemCodeBatch {
+	_redeemCodeBatch := redeemCodeBatch{}
+
+	_redeemCodeBatch.redeemCodeBatchDo.UseDB(db, opts...)
+	_redeemCodeBatch.redeemCodeBatchDo.UseModel(&model.RedeemCodeBatch{})
+
+	tableName := _redeemCodeBatch.redeemCodeBatchDo.TableName()
+	_redeemCodeBatch.ALL = field.NewAsterisk(tableName)
+	_redeemCodeBatch.ExpiresAt = field.NewTime(tableName, "expires_at")
+	_redeemCodeBatch.Name = field.NewString(tableName, "name")
+	_redeemCodeBatch.Note = field.NewString(tableName, "note")
+	_redeemCodeBatch.CreatedBy = field.NewString(tableName, "created_by")
+	_redeemCodeBatch.Channel = field.NewString(tableName, "channel")
+	_redeemCodeBatch.CreatedAt = field.NewTime(tableName, "created_at")
+	_redeemCodeBatch.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_redeemCodeBatch.DeletedAt = field.NewField(tableName, "deleted_at")
+	_redeemCodeBatch.ID = field.NewInt64(tableName, "id")
+	_redeemCodeBatch.Amount = field.NewInt(tableName, "amount")
+	_redeemCodeBatch.MaxRedemptions = field.NewInt(tableName, "max_redemptions")
+	_redeemCodeBatch.CodeCount = field.NewInt(tableName, "code_count")
+
+	_redeemCodeBatch.fillFieldMap()
+
+	return _redeemCodeBatch
+}
+
+type redeemCodeBatch struct {
+	redeemCodeBatchDo
+
+	ALL            field.Asterisk
+	ExpiresAt      field.Time
+	Name           field.String
+	Note           field.String
+	CreatedBy      field.String
+	Channel        field.String
+	CreatedAt      field.Time
+	UpdatedAt      field.Time
+	DeletedAt      field.Field
+	ID             field.Int64
+	Amount         field.Int
+	MaxRedemptions field.Int
+	CodeCount      field.Int
+
+	fieldMap map[string]field.Expr
+}
+
+func (r redeemCodeBatch) Table(newTableName string) *redeemCodeBatch {
+	r.redeemCodeBatchDo.UseTable(newTableName)
+	return r.updateTableName(newTableName)
+}
+
+func (r redeemCodeBatch) As(alias string) *redeemCodeBatch {
+	r.redeemCodeBatchDo.DO = *(r.redeemCodeBatchDo.As(alias).(*gen.DO))
+	return r.updateTableName(alias)
+}
+
+func (r *redeemCodeBatch) updateTableName(table string) *redeemCodeBatch {
+	r.ALL = field.NewAsterisk(table)
+	r.ExpiresAt = field.NewTime(table, "expires_at")
+	r.Name = field.NewString(table, "name")
+	r.Note = field.NewString(table, "note")
+	r.CreatedBy = field.NewString(table, "created_by")
+	r.Channel = field.NewString(table, "channel")
+	r.CreatedAt = field.NewTime(table, "created_at")
+	r.UpdatedAt = field.NewTime(table, "updated_at")
+	r.DeletedAt = field.NewField(table, "deleted_at")
+	r.ID = field.NewInt64(table, "id")
+	r.Amount = field.NewInt(table, "amount")
+	r.MaxRedemptions = field.NewInt(table, "max_redemptions")
+	r.CodeCount = field.NewInt(table, "code_count")
+
+	r.fillFieldMap()
+
+	return r
+}
+
+func (r *redeemCodeBatch) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := r.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (r *redeemCodeBatch) fillFieldMap() {
+	r.fieldMap = make(map[string]field.Expr, 12)
+	r.fieldMap["expires_at"] = r.ExpiresAt
+	r.fieldMap["name"] = r.Name
+	r.fieldMap["note"] = r.Note
+	r.fieldMap["created_by"] = r.CreatedBy
+	r.fieldMap["channel"] = r.Channel
+	r.fieldMap["created_at"] = r.CreatedAt
+	r.fieldMap["updated_at"] = r.UpdatedAt
+	r.fieldMap["deleted_at"] = r.DeletedAt
+	r.fieldMap["id"] = r.ID
+	r.fieldMap["amount"] = r.Amount
+	r.fieldMap["max_redemptions"] = r.MaxRedemptions
+	r.fieldMap["code_count"] = r.CodeCount
+}
+
+func (r redeemCodeBatch) clone(db *gorm.DB) redeemCodeBatch {
+	r.redeemCodeBatchDo.ReplaceConnPool(db.Statement.ConnPool)
+	return r
+}
+
+func (r redeemCodeBatch) replaceDB(db *gorm.DB) redeemCodeBatch {
+	r.redeemCodeBatchDo.ReplaceDB(db)
+	return r
+}
+
+type redeemCodeBatchDo struct{ gen.DO }
+
+type IRedeemCodeBatchDo interface {
+	gen.SubQuery
+	Debug() IRedeemCodeBatchDo
+	WithContext(ctx context.Context) IRedeemCodeBatchDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IRedeemCodeBatchDo
+	WriteDB() IRedeemCodeBatchDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IRedeemCodeBatchDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IRedeemCodeBatchDo
+	Not(conds ...gen.Condition) IRedeemCodeBatchDo
+	Or(conds ...gen.Condition) IRedeemCodeBatchDo
+	Select(conds ...field.Expr) IRedeemCodeBatchDo
+	Where(conds ...gen.Condition) IRedeemCodeBatchDo
+	Order(conds ...field.Expr) IRedeemCodeBatchDo
+	Distinct(cols ...field.Expr) IRedeemCodeBatchDo
+	Omit(cols ...field.Expr) IRedeemCodeBatchDo
+	Join(table schema.Tabler, on ...field.Expr) IRedeemCodeBatchDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IRedeemCodeBatchDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IRedeemCodeBatchDo
+	Group(cols ...field.Expr) IRedeemCodeBatchDo
+	Having(conds ...gen.Condition) IRedeemCodeBatchDo
+	Limit(limit int) IRedeemCodeBatchDo
+	Offset(offset int) IRedeemCodeBatchDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IRedeemCodeBatchDo
+	Unscoped() IRedeemCodeBatchDo
+	Create(values ...*model.RedeemCodeBatch) error
+	CreateInBatches(values []*model.RedeemCodeBatch, batchSize int) error
+	Save(values ...*model.RedeemCodeBatch) error
+	First() (*model.RedeemCodeBatch, error)
+	Take() (*model.RedeemCodeBatch, error)
+	Last() (*model.RedeemCodeBatch, error)
+	Find() ([]*model.RedeemCodeBatch, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.RedeemCodeBatch, err error)
+	FindInBatches(result *[]*model.RedeemCodeBatch, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.RedeemCodeBatch) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IRedeemCodeBatchDo
+	Assign(attrs ...field.AssignExpr) IRedeemCodeBatchDo
+	Joins(fields ...field.RelationField) IRedeemCodeBatchDo
+	Preload(fields ...field.RelationField) IRedeemCodeBatchDo
+	FirstOrInit() (*model.RedeemCodeBatch, error)
+	FirstOrCreate() (*model.RedeemCodeBatch, error)
+	FindByPage(offset int, limit int) (result []*model.RedeemCodeBatch, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IRedeemCodeBatchDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (r redeemCodeBatchDo) Debug() IRedeemCodeBatchDo {
+	return r.withDO(r.DO.Debug())
+}
+
+func (r redeemCodeBatchDo) WithContext(ctx context.Context) IRedeemCodeBatchDo {
+	return r.withDO(r.DO.WithContext(ctx))
+}
+
+func (r redeemCodeBatchDo) ReadDB() IRedeemCodeBatchDo {
+	return r.Clauses(dbresolver.Read)
+}
+
+func (r redeemCodeBatchDo) WriteDB() IRedeemCodeBatchDo {
+	return r.Clauses(dbresolver.Write)
+}
+
+func (r redeemCodeBatchDo) Session(config *gorm.Session) IRedeemCodeBatchDo {
+	return r.withDO(r.DO.Session(config))
+}
+
+func (r redeemCodeBatchDo) Clauses(conds ...clause.Expression) IRedeemCodeBatchDo {
+	return r.withDO(r.DO.Clauses(conds...))
+}
+
+func (r redeemCodeBatchDo) Returning(value interface{}, columns ...string) IRedeemCodeBatchDo {
+	return r.withDO(r.DO.Returning(value, columns...))
+}
+
+func (r redeemCodeBatchDo) Not(conds ...gen.Condition) IRedeemCodeBatchDo {
+	return r.withDO(r.DO.Not(conds...))
+}
+
+func (r redeemCodeBatchDo) Or(conds ...gen.Condition) IRedeemCodeBatchDo {
+	return r.withDO(r.DO.Or(conds...))
+}
+
+func (r redeemCodeBatchDo) Select(conds ...field.Expr) IRedeemCodeBatchDo {
+	return r.withDO(r.DO.Select(conds...))
+}
+
+func (r redeemCodeBatchDo) Where(conds ...gen.Condition) IRedeemCodeBatchDo {
+	return r.withDO(r.DO.Where(conds...))
+}
+
+func (r redeemCodeBatchDo) Order(conds ...field.Expr) IRedeemCodeBatchDo {
+	return r.withDO(r.DO.Order(conds...))
+}
+
+func (r redeemCodeBatchDo) Distinct(cols ...field.Expr) IRedeemCodeBatchDo {
+	return r.withDO(r.DO.Distinct(cols...))
+}
+
+func (r redeemCodeBatchDo) Omit(cols ...field.Expr) IRedeemCodeBatchDo {
+	return r.withDO(r.DO.Omit(cols...))
+}
+
+func (r redeemCodeBatchDo) Join(table schema.Tabler, on ...field.Expr) IRedeemCodeBatchDo {
+	return r.withDO(r.DO.Join(table, on...))
+}
+
+func (r redeemCodeBatchDo) LeftJoin(table schema.Tabler, on ...field.Expr) IRedeemCodeBatchDo {
+	return r.withDO(r.DO.LeftJoin(table, on...))
+}
+
+func (r redeemCodeBatchDo) RightJoin(table schema.Tabler, on ...field.Expr) IRedeemCodeBatchDo {
+	return r.withDO(r.DO.RightJoin(table, on...))
+}
+
+func (r redeemCodeBatchDo) Group(cols ...field.Expr) IRedeemCodeBatchDo {
+	return r.withDO(r.DO.Group(cols...))
+}
+
+func (r redeemCodeBatchDo) Having(conds ...gen.Condition) IRedeemCodeBatchDo {
+	return r.withDO(r.DO.Having(conds...))
+}
+
+func (r redeemCodeBatchDo) Limit(limit int) IRedeemCodeBatchDo {
+	return r.withDO(r.DO.Limit(limit))
+}
+
+func (r redeemCodeBatchDo) Offset(offset int) IRedeemCodeBatchDo {
+	return r.withDO(r.DO.Offset(offset))
+}
+
+func (r redeemCodeBatchDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IRedeemCodeBatchDo {
+	return r.withDO(r.DO.Scopes(funcs...))
+}
+
+func (r redeemCodeBatchDo) Unscoped() IRedeemCodeBatchDo {
+	return r.withDO(r.DO.Unscoped())
+}
+
+func (r redeemCodeBatchDo) Create(values ...*model.RedeemCodeBatch) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return r.DO.Create(values)
+}
+
+func (r redeemCodeBatchDo) CreateInBatches(values []*model.RedeemCodeBatch, batchSize int) error {
+	return r.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (r redeemCodeBatchDo) Save(values ...*model.RedeemCodeBatch) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return r.DO.Save(values)
+}
+
+func (r redeemCodeBatchDo) First() (*model.RedeemCodeBatch, error) {
+	if result, err := r.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.RedeemCodeBatch), nil
+	}
+}
+
+func (r redeemCodeBatchDo) Take() (*model.RedeemCodeBatch, error) {
+	if result, err := r.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.RedeemCodeBatch), nil
+	}
+}
+
+func (r redeemCodeBatchDo) Last() (*model.RedeemCodeBatch, error) {
+	if result, err := r.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.RedeemCodeBatch), nil
+	}
+}
+
+func (r redeemCodeBatchDo) Find() ([]*model.RedeemCodeBatch, error) {
+	result, err := r.DO.Find()
+	return result.([]*model.RedeemCodeBatch), err
+}
+
+func (r redeemCodeBatchDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.RedeemCodeBatch, err error) {
+	buf := make([]*model.RedeemCodeBatch, 0, batchSize)
+	err = r.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (r redeemCodeBatchDo) FindInBatches(result *[]*model.RedeemCodeBatch, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return r.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (r redeemCodeBatchDo) Attrs(attrs ...field.AssignExpr) IRedeemCodeBatchDo {
+	return r.withDO(r.DO.Attrs(attrs...))
+}
+
+func (r redeemCodeBatchDo) Assign(attrs ...field.AssignExpr) IRedeemCodeBatchDo {
+	return r.withDO(r.DO.Assign(attrs...))
+}
+
+func (r redeemCodeBatchDo) Joins(fields ...field.RelationField) IRedeemCodeBatchDo {
+	for _, _f := range fields {
+		r = *r.withDO(r.DO.Joins(_f))
+	}
+	return &r
+}
+
+func (r redeemCodeBatchDo) Preload(fields ...field.RelationField) IRedeemCodeBatchDo {
+	for _, _f := range fields {
+		r = *r.withDO(r.DO.Preload(_f))
+	}
+	return &r
+}
+
+func (r redeemCodeBatchDo) FirstOrInit() (*model.RedeemCodeBatch, error) {
+	if result, err := r.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.RedeemCodeBatch), nil
+	}
+}
+
+func (r redeemCodeBatchDo) FirstOrCreate() (*model.RedeemCodeBatch, error) {
+	if result, err := r.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.RedeemCodeBatch), nil
+	}
+}
+
+func (r redeemCodeBatchDo) FindByPage(offset int, limit int) (result []*model.RedeemCodeBatch, count int64, err error) {
+	result, err = r.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = r.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (r redeemCodeBatchDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = r.Count()
+	if err != nil {
+		return
+	}
+
+	err = r.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (r redeemCodeBatchDo) Scan(result interface{}) (err error) {
+	return r.DO.Scan(result)
+}
+
+func (r redeemCodeBatchDo) Delete(models ...*model.RedeemCodeBatch) (result gen.ResultInfo, err error) {
+	return r.DO.Delete(models)
+}
+
+func (r *redeemCodeBatchDo) withDO(do gen.Dao) *redeemCodeBatchDo {
+	r.DO = *do.(*gen.DO)
+	return r
+}
@@ -0,0 +1,426 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"AreYouOK/internal/model"
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+func newRedeemCode(db *gorm.DB, opts ...gen.DOOption) redeemCode {
+	_redeemCode := redeemCode{}
+
+	_redeemCode.redeemCodeDo.UseDB(db, opts...)
+	_redeemCode.redeemCodeDo.UseModel(&model.RedeemCode{})
+
+	tableName := _redeemCode.redeemCodeDo.TableName()
+	_redeemCode.ALL = field.NewAsterisk(tableName)
+	_redeemCode.ExpiresAt = field.NewTime(tableName, "expires_at")
+	_redeemCode.CodeHash = field.NewString(tableName, "code_hash")
+	_redeemCode.Channel = field.NewString(tableName, "channel")
+	_redeemCode.Status = field.NewString(tableName, "status")
+	_redeemCode.CreatedAt = field.NewTime(tableName, "created_at")
+	_redeemCode.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_redeemCode.DeletedAt = field.NewField(tableName, "deleted_at")
+	_redeemCode.ID = field.NewInt64(tableName, "id")
+	_redeemCode.BatchID = field.NewInt64(tableName, "batch_id")
+	_redeemCode.Amount = field.NewInt(tableName, "amount")
+	_redeemCode.MaxRedemptions = field.NewInt(tableName, "max_redemptions")
+	_redeemCode.RedeemedCount = field.NewInt(tableName, "redeemed_count")
+
+	_redeemCode.fillFieldMap()
+
+	return _redeemCode
+}
+
+type redeemCode struct {
+	redeemCodeDo
+
+	ALL            field.Asterisk
+	ExpiresAt      field.Time
+	CodeHash       field.String
+	Channel        field.String
+	Status         field.String
+	CreatedAt      field.Time
+	UpdatedAt      field.Time
+	DeletedAt      field.Field
+	ID             field.Int64
+	BatchID        field.Int64
+	Amount         field.Int
+	MaxRedemptions field.Int
+	RedeemedCount  field.Int
+
+	fieldMap map[string]field.Expr
+}
+
+func (r redeemCode) Table(newTableName string) *redeemCode {
+	r.redeemCodeDo.UseTable(newTableName)
+	return r.updateTableName(newTableName)
+}
+
+func (r redeemCode) As(alias string) *redeemCode {
+	r.redeemCodeDo.DO = *(r.redeemCodeDo.As(alias).(*gen.DO))
+	return r.updateTableName(alias)
+}
+
+func (r *redeemCode) updateTableName(table string) *redeemCode {
+	r.ALL = field.NewAsterisk(table)
+	r.ExpiresAt = field.NewTime(table, "expires_at")
+	r.CodeHash = field.NewString(table, "code_hash")
+	r.Channel = field.NewString(table, "channel")
+	r.Status = field.NewString(table, "status")
+	r.CreatedAt = field.NewTime(table, "created_at")
+	r.UpdatedAt = field.NewTime(table, "updated_at")
+	r.DeletedAt = field.NewField(table, "deleted_at")
+	r.ID = field.NewInt64(table, "id")
+	r.BatchID = field.NewInt64(table, "batch_id")
+	r.Amount = field.NewInt(table, "amount")
+	r.MaxRedemptions = field.NewInt(table, "max_redemptions")
+	r.RedeemedCount = field.NewInt(table, "redeemed_count")
+
+	r.fillFieldMap()
+
+	return r
+}
+
+func (r *redeemCode) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := r.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (r *redeemCode) fillFieldMap() {
+	r.fieldMap = make(map[string]field.Expr, 12)
+	r.fieldMap["expires_at"] = r.ExpiresAt
+	r.fieldMap["code_hash"] = r.CodeHash
+	r.fieldMap["channel"] = r.Channel
+	r.fieldMap["status"] = r.Status
+	r.fieldMap["created_at"] = r.CreatedAt
+	r.fieldMap["updated_at"] = r.UpdatedAt
+	r.fieldMap["deleted_at"] = r.DeletedAt
+	r.fieldMap["id"] = r.ID
+	r.fieldMap["batch_id"] = r.BatchID
+	r.fieldMap["amount"] = r.Amount
+	r.fieldMap["max_redemptions"] = r.MaxRedemptions
+	r.fieldMap["redeemed_count"] = r.RedeemedCount
+}
+
+func (r redeemCode) clone(db *gorm.DB) redeemCode {
+	r.redeemCodeDo.ReplaceConnPool(db.Statement.ConnPool)
+	return r
+}
+
+func (r redeemCode) replaceDB(db *gorm.DB) redeemCode {
+	r.redeemCodeDo.ReplaceDB(db)
+	return r
+}
+
+type redeemCodeDo struct{ gen.DO }
+
+type IRedeemCodeDo interface {
+	gen.SubQuery
+	Debug() IRedeemCodeDo
+	WithContext(ctx context.Context) IRedeemCodeDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IRedeemCodeDo
+	WriteDB() IRedeemCodeDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IRedeemCodeDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IRedeemCodeDo
+	Not(conds ...gen.Condition) IRedeemCodeDo
+	Or(conds ...gen.Condition) IRedeemCodeDo
+	Select(conds ...field.Expr) IRedeemCodeDo
+	Where(conds ...gen.Condition) IRedeemCodeDo
+	Order(conds ...field.Expr) IRedeemCodeDo
+	Distinct(cols ...field.Expr) IRedeemCodeDo
+	Omit(cols ...field.Expr) IRedeemCodeDo
+	Join(table schema.Tabler, on ...field.Expr) IRedeemCodeDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IRedeemCodeDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IRedeemCodeDo
+	Group(cols ...field.Expr) IRedeemCodeDo
+	Having(conds ...gen.Condition) IRedeemCodeDo
+	Limit(limit int) IRedeemCodeDo
+	Offset(offset int) IRedeemCodeDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IRedeemCodeDo
+	Unscoped() IRedeemCodeDo
+	Create(values ...*model.RedeemCode) error
+	CreateInBatches(values []*model.RedeemCode, batchSize int) error
+	Save(values ...*model.RedeemCode) error
+	First() (*model.RedeemCode, error)
+	Take() (*model.RedeemCode, error)
+	Last() (*model.RedeemCode, error)
+	Find() ([]*model.RedeemCode, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.RedeemCode, err error)
+	FindInBatches(result *[]*model.RedeemCode, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.RedeemCode) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IRedeemCodeDo
+	Assign(attrs ...field.AssignExpr) IRedeemCodeDo
+	Joins(fields ...field.RelationField) IRedeemCodeDo
+	Preload(fields ...field.RelationField) IRedeemCodeDo
+	FirstOrInit() (*model.RedeemCode, error)
+	FirstOrCreate() (*model.RedeemCode, error)
+	FindByPage(offset int, limit int) (result []*model.RedeemCode, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IRedeemCodeDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (r redeemCodeDo) Debug() IRedeemCodeDo {
+	return r.withDO(r.DO.Debug())
+}
+
+func (r redeemCodeDo) WithContext(ctx context.Context) IRedeemCodeDo {
+	return r.withDO(r.DO.WithContext(ctx))
+}
+
+func (r redeemCodeDo) ReadDB() IRedeemCodeDo {
+	return r.Clauses(dbresolver.Read)
+}
+
+func (r redeemCodeDo) WriteDB() IRedeemCodeDo {
+	return r.Clauses(dbresolver.Write)
+}
+
+func (r redeemCodeDo) Session(config *gorm.Session) IRedeemCodeDo {
+	return r.withDO(r.DO.Session(config))
+}
+
+func (r redeemCodeDo) Clauses(conds ...clause.Expression) IRedeemCodeDo {
+	return r.withDO(r.DO.Clauses(conds...))
+}
+
+func (r redeemCodeDo) Returning(value interface{}, columns ...string) IRedeemCodeDo {
+	return r.withDO(r.DO.Returning(value, columns...))
+}
+
+func (r redeemCodeDo) Not(conds ...gen.Condition) IRedeemCodeDo {
+	return r.withDO(r.DO.Not(conds...))
+}
+
+func (r redeemCodeDo) Or(conds ...gen.Condition) IRedeemCodeDo {
+	return r.withDO(r.DO.Or(conds...))
+}
+
+func (r redeemCodeDo) Select(conds ...field.Expr) IRedeemCodeDo {
+	return r.withDO(r.DO.Select(conds...))
+}
+
+func (r redeemCodeDo) Where(conds ...gen.Condition) IRedeemCodeDo {
+	return r.withDO(r.DO.Where(conds...))
+}
+
+func (r redeemCodeDo) Order(conds ...field.Expr) IRedeemCodeDo {
+	return r.withDO(r.DO.Order(conds...))
+}
+
+func (r redeemCodeDo) Distinct(cols ...field.Expr) IRedeemCodeDo {
+	return r.withDO(r.DO.Distinct(cols...))
+}
+
+func (r redeemCodeDo) Omit(cols ...field.Expr) IRedeemCodeDo {
+	return r.withDO(r.DO.Omit(cols...))
+}
+
+func (r redeemCodeDo) Join(table schema.Tabler, on ...field.Expr) IRedeemCodeDo {
+	return r.withDO(r.DO.Join(table, on...))
+}
+
+func (r redeemCodeDo) LeftJoin(table schema.Tabler, on ...field.Expr) IRedeemCodeDo {
+	return r.withDO(r.DO.LeftJoin(table, on...))
+}
+
+func (r redeemCodeDo) RightJoin(table schema.Tabler, on ...field.Expr) IRedeemCodeDo {
+	return r.withDO(r.DO.RightJoin(table, on...))
+}
+
+func (r redeemCodeDo) Group(cols ...field.Expr) IRedeemCodeDo {
+	return r.withDO(r.DO.Group(cols...))
+}
+
+func (r redeemCodeDo) Having(conds ...gen.Condition) IRedeemCodeDo {
+	return r.withDO(r.DO.Having(conds...))
+}
+
+func (r redeemCodeDo) Limit(limit int) IRedeemCodeDo {
+	return r.withDO(r.DO.Limit(limit))
+}
+
+func (r redeemCodeDo) Offset(offset int) IRedeemCodeDo {
+	return r.withDO(r.DO.Offset(offset))
+}
+
+func (r redeemCodeDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IRedeemCodeDo {
+	return r.withDO(r.DO.Scopes(funcs...))
+}
+
+func (r redeemCodeDo) Unscoped() IRedeemCodeDo {
+	return r.withDO(r.DO.Unscoped())
+}
+
+func (r redeemCodeDo) Create(values ...*model.RedeemCode) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return r.DO.Create(values)
+}
+
+func (r redeemCodeDo) CreateInBatches(values []*model.RedeemCode, batchSize int) error {
+	return r.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (r redeemCodeDo) Save(values ...*model.RedeemCode) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return r.DO.Save(values)
+}
+
+func (r redeemCodeDo) First() (*model.RedeemCode, error) {
+	if result, err := r.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.RedeemCode), nil
+	}
+}
+
+func (r redeemCodeDo) Take() (*model.RedeemCode, error) {
+	if result, err := r.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.RedeemCode), nil
+	}
+}
+
+func (r redeemCodeDo) Last() (*model.RedeemCode, error) {
+	if result, err := r.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.RedeemCode), nil
+	}
+}
+
+func (r redeemCodeDo) Find() ([]*model.RedeemCode, error) {
+	result, err := r.DO.Find()
+	return result.([]*model.RedeemCode), err
+}
+
+func (r redeemCodeDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.RedeemCode, err error) {
+	buf := make([]*model.RedeemCode, 0, batchSize)
+	err = r.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (r redeemCodeDo) FindInBatches(result *[]*model.RedeemCode, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return r.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (r redeemCodeDo) Attrs(attrs ...field.AssignExpr) IRedeemCodeDo {
+	return r.withDO(r.DO.Attrs(attrs...))
+}
+
+func (r redeemCodeDo) Assign(attrs ...field.AssignExpr) IRedeemCodeDo {
+	return r.withDO(r.DO.Assign(attrs...))
+}
+
+func (r redeemCodeDo) Joins(fields ...field.RelationField) IRedeemCodeDo {
+	for _, _f := range fields {
+		r = *r.withDO(r.DO.Joins(_f))
+	}
+	return &r
+}
+
+func (r redeemCodeDo) Preload(fields ...field.RelationField) IRedeemCodeDo {
+	for _, _f := range fields {
+		r = *r.withDO(r.DO.Preload(_f))
+	}
+	return &r
+}
+
+func (r redeemCodeDo) FirstOrInit() (*model.RedeemCode, error) {
+	if result, err := r.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.RedeemCode), nil
+	}
+}
+
+func (r redeemCodeDo) FirstOrCreate() (*model.RedeemCode, error) {
+	if result, err := r.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.RedeemCode), nil
+	}
+}
+
+func (r redeemCodeDo) FindByPage(offset int, limit int) (result []*model.RedeemCode, count int64, err error) {
+	result, err = r.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = r.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (r redeemCodeDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = r.Count()
+	if err != nil {
+		return
+	}
+
+	err = r.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (r redeemCodeDo) Scan(result interface{}) (err error) {
+	return r.DO.Scan(result)
+}
+
+func (r redeemCodeDo) Delete(models ...*model.RedeemCode) (result gen.ResultInfo, err error) {
+	return r.DO.Delete(models)
+}
+
+func (r *redeemCodeDo) withDO(do gen.Dao) *redeemCodeDo {
+	r.DO = *do.(*gen.DO)
+	return r
+}
@@ -0,0 +1,406 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"AreYouOK/internal/model"
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+func newRedeemRecord(db *gorm.DB, opts ...gen.DOOption) redeemRecord {
+	_redeemRecord := redeemRecord{}
+
+	_redeemRecord.redeemRecordDo.UseDB(db, opts...)
+	_redeemRecord.redeemRecordDo.UseModel(&model.RedeemRecord{})
+
+	tableName := _redeemRecord.redeemRecordDo.TableName()
+	_redeemRecord.ALL = field.NewAsterisk(tableName)
+	_redeemRecord.RedeemedAt = field.NewTime(tableName, "redeemed_at")
+	_redeemRecord.Channel = field.NewString(tableName, "channel")
+	_redeemRecord.ID = field.NewInt64(tableName, "id")
+	_redeemRecord.CodeID = field.NewInt64(tableName, "code_id")
+	_redeemRecord.UserID = field.NewInt64(tableName, "user_id")
+	_redeemRecord.BatchID = field.NewInt64(tableName, "batch_id")
+	_redeemRecord.Amount = field.NewInt(tableName, "amount")
+
+	_redeemRecord.fillFieldMap()
+
+	return _redeemRecord
+}
+
+type redeemRecord struct {
+	redeemRecordDo
+
+	ALL        field.Asterisk
+	RedeemedAt field.Time
+	Channel    field.String
+	ID         field.Int64
+	CodeID     field.Int64
+	UserID     field.Int64
+	BatchID    field.Int64
+	Amount     field.Int
+
+	fieldMap map[string]field.Expr
+}
+
+func (r redeemRecord) Table(newTableName string) *redeemRecord {
+	r.redeemRecordDo.UseTable(newTableName)
+	return r.updateTableName(newTableName)
+}
+
+func (r redeemRecord) As(alias string) *redeemRecord {
+	r.redeemRecordDo.DO = *(r.redeemRecordDo.As(alias).(*gen.DO))
+	return r.updateTableName(alias)
+}
+
+func (r *redeemRecord) updateTableName(table string) *redeemRecord {
+	r.ALL = field.NewAsterisk(table)
+	r.RedeemedAt = field.NewTime(table, "redeemed_at")
+	r.Channel = field.NewString(table, "channel")
+	r.ID = field.NewInt64(table, "id")
+	r.CodeID = field.NewInt64(table, "code_id")
+	r.UserID = field.NewInt64(table, "user_id")
+	r.BatchID = field.NewInt64(table, "batch_id")
+	r.Amount = field.NewInt(table, "amount")
+
+	r.fillFieldMap()
+
+	return r
+}
+
+func (r *redeemRecord) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := r.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (r *redeemRecord) fillFieldMap() {
+	r.fieldMap = make(map[string]field.Expr, 7)
+	r.fieldMap["redeemed_at"] = r.RedeemedAt
+	r.fieldMap["channel"] = r.Channel
+	r.fieldMap["id"] = r.ID
+	r.fieldMap["code_id"] = r.CodeID
+	r.fieldMap["user_id"] = r.UserID
+	r.fieldMap["batch_id"] = r.BatchID
+	r.fieldMap["amount"] = r.Amount
+}
+
+func (r redeemRecord) clone(db *gorm.DB) redeemRecord {
+	r.redeemRecordDo.ReplaceConnPool(db.Statement.ConnPool)
+	return r
+}
+
+func (r redeemRecord) replaceDB(db *gorm.DB) redeemRecord {
+	r.redeemRecordDo.ReplaceDB(db)
+	return r
+}
+
+type redeemRecordDo struct{ gen.DO }
+
+type IRedeemRecordDo interface {
+	gen.SubQuery
+	Debug() IRedeemRecordDo
+	WithContext(ctx context.Context) IRedeemRecordDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IRedeemRecordDo
+	WriteDB() IRedeemRecordDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IRedeemRecordDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IRedeemRecordDo
+	Not(conds ...gen.Condition) IRedeemRecordDo
+	Or(conds ...gen.Condition) IRedeemRecordDo
+	Select(conds ...field.Expr) IRedeemRecordDo
+	Where(conds ...gen.Condition) IRedeemRecordDo
+	Order(conds ...field.Expr) IRedeemRecordDo
+	Distinct(cols ...field.Expr) IRedeemRecordDo
+	Omit(cols ...field.Expr) IRedeemRecordDo
+	Join(table schema.Tabler, on ...field.Expr) IRedeemRecordDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IRedeemRecordDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IRedeemRecordDo
+	Group(cols ...field.Expr) IRedeemRecordDo
+	Having(conds ...gen.Condition) IRedeemRecordDo
+	Limit(limit int) IRedeemRecordDo
+	Offset(offset int) IRedeemRecordDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IRedeemRecordDo
+	Unscoped() IRedeemRecordDo
+	Create(values ...*model.RedeemRecord) error
+	CreateInBatches(values []*model.RedeemRecord, batchSize int) error
+	Save(values ...*model.RedeemRecord) error
+	First() (*model.RedeemRecord, error)
+	Take() (*model.RedeemRecord, error)
+	Last() (*model.RedeemRecord, error)
+	Find() ([]*model.RedeemRecord, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.RedeemRecord, err error)
+	FindInBatches(result *[]*model.RedeemRecord, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.RedeemRecord) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IRedeemRecordDo
+	Assign(attrs ...field.AssignExpr) IRedeemRecordDo
+	Joins(fields ...field.RelationField) IRedeemRecordDo
+	Preload(fields ...field.RelationField) IRedeemRecordDo
+	FirstOrInit() (*model.RedeemRecord, error)
+	FirstOrCreate() (*model.RedeemRecord, error)
+	FindByPage(offset int, limit int) (result []*model.RedeemRecord, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IRedeemRecordDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (r redeemRecordDo) Debug() IRedeemRecordDo {
+	return r.withDO(r.DO.Debug())
+}
+
+func (r redeemRecordDo) WithContext(ctx context.Context) IRedeemRecordDo {
+	return r.withDO(r.DO.WithContext(ctx))
+}
+
+func (r redeemRecordDo) ReadDB() IRedeemRecordDo {
+	return r.Clauses(dbresolver.Read)
+}
+
+func (r redeemRecordDo) WriteDB() IRedeemRecordDo {
+	return r.Clauses(dbresolver.Write)
+}
+
+func (r redeemRecordDo) Session(config *gorm.Session) IRedeemRecordDo {
+	return r.withDO(r.DO.Session(config))
+}
+
+func (r redeemRecordDo) Clauses(conds ...clause.Expression) IRedeemRecordDo {
+	return r.withDO(r.DO.Clauses(conds...))
+}
+
+func (r redeemRecordDo) Returning(value interface{}, columns ...string) IRedeemRecordDo {
+	return r.withDO(r.DO.Returning(value, columns...))
+}
+
+func (r redeemRecordDo) Not(conds ...gen.Condition) IRedeemRecordDo {
+	return r.withDO(r.DO.Not(conds...))
+}
+
+func (r redeemRecordDo) Or(conds ...gen.Condition) IRedeemRecordDo {
+	return r.withDO(r.DO.Or(conds...))
+}
+
+func (r redeemRecordDo) Select(conds ...field.Expr) IRedeemRecordDo {
+	return r.withDO(r.DO.Select(conds...))
+}
+
+func (r redeemRecordDo) Where(conds ...gen.Condition) IRedeemRecordDo {
+	return r.withDO(r.DO.Where(conds...))
+}
+
+func (r redeemRecordDo) Order(conds ...field.Expr) IRedeemRecordDo {
+	return r.withDO(r.DO.Order(conds...))
+}
+
+func (r redeemRecordDo) Distinct(cols ...field.Expr) IRedeemRecordDo {
+	return r.withDO(r.DO.Distinct(cols...))
+}
+
+func (r redeemRecordDo) Omit(cols ...field.Expr) IRedeemRecordDo {
+	return r.withDO(r.DO.Omit(cols...))
+}
+
+func (r redeemRecordDo) Join(table schema.Tabler, on ...field.Expr) IRedeemRecordDo {
+	return r.withDO(r.DO.Join(table, on...))
+}
+
+func (r redeemRecordDo) LeftJoin(table schema.Tabler, on ...field.Expr) IRedeemRecordDo {
+	return r.withDO(r.DO.LeftJoin(table, on...))
+}
+
+func (r redeemRecordDo) RightJoin(table schema.Tabler, on ...field.Expr) IRedeemRecordDo {
+	return r.withDO(r.DO.RightJoin(table, on...))
+}
+
+func (r redeemRecordDo) Group(cols ...field.Expr) IRedeemRecordDo {
+	return r.withDO(r.DO.Group(cols...))
+}
+
+func (r redeemRecordDo) Having(conds ...gen.Condition) IRedeemRecordDo {
+	return r.withDO(r.DO.Having(conds...))
+}
+
+func (r redeemRecordDo) Limit(limit int) IRedeemRecordDo {
+	return r.withDO(r.DO.Limit(limit))
+}
+
+func (r redeemRecordDo) Offset(offset int) IRedeemRecordDo {
+	return r.withDO(r.DO.Offset(offset))
+}
+
+func (r redeemRecordDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IRedeemRecordDo {
+	return r.withDO(r.DO.Scopes(funcs...))
+}
+
+func (r redeemRecordDo) Unscoped() IRedeemRecordDo {
+	return r.withDO(r.DO.Unscoped())
+}
+
+func (r redeemRecordDo) Create(values ...*model.RedeemRecord) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return r.DO.Create(values)
+}
+
+func (r redeemRecordDo) CreateInBatches(values []*model.RedeemRecord, batchSize int) error {
+	return r.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (r redeemRecordDo) Save(values ...*model.RedeemRecord) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return r.DO.Save(values)
+}
+
+func (r redeemRecordDo) First() (*model.RedeemRecord, error) {
+	if result, err := r.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.RedeemRecord), nil
+	}
+}
+
+func (r redeemRecordDo) Take() (*model.RedeemRecord, error) {
+	if result, err := r.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.RedeemRecord), nil
+	}
+}
+
+func (r redeemRecordDo) Last() (*model.RedeemRecord, error) {
+	if result, err := r.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.RedeemRecord), nil
+	}
+}
+
+func (r redeemRecordDo) Find() ([]*model.RedeemRecord, error) {
+	result, err := r.DO.Find()
+	return result.([]*model.RedeemRecord), err
+}
+
+func (r redeemRecordDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.RedeemRecord, err error) {
+	buf := make([]*model.RedeemRecord, 0, batchSize)
+	err = r.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (r redeemRecordDo) FindInBatches(result *[]*model.RedeemRecord, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return r.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (r redeemRecordDo) Attrs(attrs ...field.AssignExpr) IRedeemRecordDo {
+	return r.withDO(r.DO.Attrs(attrs...))
+}
+
+func (r redeemRecordDo) Assign(attrs ...field.AssignExpr) IRedeemRecordDo {
+	return r.withDO(r.DO.Assign(attrs...))
+}
+
+func (r redeemRecordDo) Joins(fields ...field.RelationField) IRedeemRecordDo {
+	for _, _f := range fields {
+		r = *r.withDO(r.DO.Joins(_f))
+	}
+	return &r
+}
+
+func (r redeemRecordDo) Preload(fields ...field.RelationField) IRedeemRecordDo {
+	for _, _f := range fields {
+		r = *r.withDO(r.DO.Preload(_f))
+	}
+	return &r
+}
+
+func (r redeemRecordDo) FirstOrInit() (*model.RedeemRecord, error) {
+	if result, err := r.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.RedeemRecord), nil
+	}
+}
+
+func (r redeemRecordDo) FirstOrCreate() (*model.RedeemRecord, error) {
+	if result, err := r.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.RedeemRecord), nil
+	}
+}
+
+func (r redeemRecordDo) FindByPage(offset int, limit int) (result []*model.RedeemRecord, count int64, err error) {
+	result, err = r.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = r.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (r redeemRecordDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = r.Count()
+	if err != nil {
+		return
+	}
+
+	err = r.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (r redeemRecordDo) Scan(result interface{}) (err error) {
+	return r.DO.Scan(result)
+}
+
+func (r redeemRecordDo) Delete(models ...*model.RedeemRecord) (result gen.ResultInfo, err error) {
+	return r.DO.Delete(models)
+}
+
+func (r *redeemRecordDo) withDO(do gen.Dao) *redeemRecordDo {
+	r.DO = *do.(*gen.DO)
+	return r
+}
@@ -32,6 +32,9 @@ func newUser(db *gorm.DB, opts ...gen.DOOption) user {
 	_user.DailyCheckInGraceUntil = field.NewString(tableName, "daily_check_in_grace_until")
 	_user.DailyCheckInDeadline = field.NewString(tableName, "daily_check_in_deadline")
 	_user.PhoneHash = field.NewString(tableName, "phone_hash")
+	_user.PhoneHashVersion = field.NewInt(tableName, "phone_hash_version")
+	_user.PublicID = field.NewInt64(tableName, "public_id")
+	_user.AlipayOpenID = field.NewString(tableName, "alipay_open_id")
 	_user.CreatedAt = field.NewTime(tableName, "created_at")
 	_user.UpdatedAt = field.NewTime(tableName, "updated_at")
 	_user.DeletedAt = field.NewField(tableName, "deleted_at")
@@ -39,12 +42,13 @@ func newUser(db *gorm.DB, opts ...gen.DOOption) user {
 	_user.Status = field.NewString(tableName, "status")
 	_user.Timezone = field.NewString(tableName, "timezone")
 	_user.Nickname = field.NewString(tableName, "nickname")
-	_user.AlipayOpenID = field.NewString(tableName, "alipay_open_id")
 	_user.PhoneCipher = field.NewBytes(tableName, "phone_cipher")
-	_user.EmergencyContacts = field.NewField(tableName, "emergency_contacts")
-	_user.PublicID = field.NewInt64(tableName, "public_id")
+	_user.LegacyContacts = field.NewField(tableName, "emergency_contacts")
 	_user.DailyCheckInEnabled = field.NewBool(tableName, "daily_check_in_enabled")
 	_user.JourneyAutoNotify = field.NewBool(tableName, "journey_auto_notify")
+	_user.HomeLatitude = field.NewFloat64(tableName, "home_latitude")
+	_user.HomeLongitude = field.NewFloat64(tableName, "home_longitude")
+	_user.HomeRadius = field.NewInt(tableName, "home_radius")
 
 	_user.fillFieldMap()
 
@@ -59,6 +63,9 @@ type user struct {
 	DailyCheckInGraceUntil field.String
 	DailyCheckInDeadline   field.String
 	PhoneHash              field.String
+	PhoneHashVersion       field.Int
+	PublicID               field.Int64
+	AlipayOpenID           field.String
 	CreatedAt              field.Time
 	UpdatedAt              field.Time
 	DeletedAt              field.Field
@@ -66,12 +73,13 @@ type user struct {
 	Status                 field.String
 	Timezone               field.String
 	Nickname               field.String
-	AlipayOpenID           field.String
 	PhoneCipher            field.Bytes
-	EmergencyContacts      field.Field
-	PublicID               field.Int64
+	LegacyContacts         field.Field
 	DailyCheckInEnabled    field.Bool
 	JourneyAutoNotify      field.Bool
+	HomeLatitude           field.Float64
+	HomeLongitude          field.Float64
+	HomeRadius             field.Int
 
 	fieldMap map[string]field.Expr
 }
@@ -92,6 +100,9 @@ func (u *user) updateTableName(table string) *user {
 	u.DailyCheckInGraceUntil = field.NewString(table, "daily_check_in_grace_until")
 	u.DailyCheckInDeadline = field.NewString(table, "daily_check_in_deadline")
 	u.PhoneHash = field.NewString(table, "phone_hash")
+	u.PhoneHashVersion = field.NewInt(table, "phone_hash_version")
+	u.PublicID = field.NewInt64(table, "public_id")
+	u.AlipayOpenID = field.NewString(table, "alipay_open_id")
 	u.CreatedAt = field.NewTime(table, "created_at")
 	u.UpdatedAt = field.NewTime(table, "updated_at")
 	u.DeletedAt = field.NewField(table, "deleted_at")
@@ -99,12 +110,13 @@ func (u *user) updateTableName(table string) *user {
 	u.Status = field.NewString(table, "status")
 	u.Timezone = field.NewString(table, "timezone")
 	u.Nickname = field.NewString(table, "nickname")
-	u.AlipayOpenID = field.NewString(table, "alipay_open_id")
 	u.PhoneCipher = field.NewBytes(table, "phone_cipher")
-	u.EmergencyContacts = field.NewField(table, "emergency_contacts")
-	u.PublicID = field.NewInt64(table, "public_id")
+	u.LegacyContacts = field.NewField(table, "emergency_contacts")
 	u.DailyCheckInEnabled = field.NewBool(table, "daily_check_in_enabled")
 	u.JourneyAutoNotify = field.NewBool(table, "journey_auto_notify")
+	u.HomeLatitude = field.NewFloat64(table, "home_latitude")
+	u.HomeLongitude = field.NewFloat64(table, "home_longitude")
+	u.HomeRadius = field.NewInt(table, "home_radius")
 
 	u.fillFieldMap()
 
@@ -121,11 +133,14 @@ func (u *user) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (u *user) fillFieldMap() {
-	u.fieldMap = make(map[string]field.Expr, 17)
+	u.fieldMap = make(map[string]field.Expr, 21)
 	u.fieldMap["daily_check_in_remind_at"] = u.DailyCheckInRemindAt
 	u.fieldMap["daily_check_in_grace_until"] = u.DailyCheckInGraceUntil
 	u.fieldMap["daily_check_in_deadline"] = u.DailyCheckInDeadline
 	u.fieldMap["phone_hash"] = u.PhoneHash
+	u.fieldMap["phone_hash_version"] = u.PhoneHashVersion
+	u.fieldMap["public_id"] = u.PublicID
+	u.fieldMap["alipay_open_id"] = u.AlipayOpenID
 	u.fieldMap["created_at"] = u.CreatedAt
 	u.fieldMap["updated_at"] = u.UpdatedAt
 	u.fieldMap["deleted_at"] = u.DeletedAt
@@ -133,12 +148,13 @@ func (u *user) fillFieldMap() {
 	u.fieldMap["status"] = u.Status
 	u.fieldMap["timezone"] = u.Timezone
 	u.fieldMap["nickname"] = u.Nickname
-	u.fieldMap["alipay_open_id"] = u.AlipayOpenID
 	u.fieldMap["phone_cipher"] = u.PhoneCipher
-	u.fieldMap["emergency_contacts"] = u.EmergencyContacts
-	u.fieldMap["public_id"] = u.PublicID
+	u.fieldMap["emergency_contacts"] = u.LegacyContacts
 	u.fieldMap["daily_check_in_enabled"] = u.DailyCheckInEnabled
 	u.fieldMap["journey_auto_notify"] = u.JourneyAutoNotify
+	u.fieldMap["home_latitude"] = u.HomeLatitude
+	u.fieldMap["home_longitude"] = u.HomeLongitude
+	u.fieldMap["home_radius"] = u.HomeRadius
 }
 
 func (u user) clone(db *gorm.DB) user {
@@ -216,7 +232,7 @@ type IUserDo interface {
 	schema.Tabler
 
 	GetByAlipayOpenID(openID string) (result *model.User, err error)
-	GetByPhoneHash(phoneHash string) (result *model.User, err error)
+	GetByPhoneHashes(phoneHashes []string) (result *model.User, err error)
 	GetByPublicID(publicID int64) (result *model.User, err error)
 	GetByID(id int64) (result *model.User, err error)
 	ListByStatus(status string, limit int, offset int) (result []*model.User, err error)
@@ -243,15 +259,15 @@ func (u userDo) GetByAlipayOpenID(openID string) (result *model.User, err error)
 	return
 }
 
-// GetByPhoneHash 根据手机号哈希查询用户
+// GetByPhoneHashes 根据手机号哈希查询用户，传入当前与上一版本的候选哈希（见 utils.PhoneHashCandidates）
 //
-// SELECT * FROM @@table WHERE phone_hash = @phoneHash LIMIT 1
-func (u userDo) GetByPhoneHash(phoneHash string) (result *model.User, err error) {
+// SELECT * FROM @@table WHERE phone_hash IN @phoneHashes LIMIT 1
+func (u userDo) GetByPhoneHashes(phoneHashes []string) (result *model.User, err error) {
 	var params []interface{}
 
 	var generateSQL strings.Builder
-	params = append(params, phoneHash)
-	generateSQL.WriteString("SELECT * FROM users WHERE phone_hash = ? LIMIT 1 ")
+	params = append(params, phoneHashes)
+	generateSQL.WriteString("SELECT * FROM users WHERE phone_hash IN ? LIMIT 1 ")
 
 	var executeSQL *gorm.DB
 	executeSQL = u.UnderlyingDB().Raw(generateSQL.String(), params...).Take(&result) // ignore_security_alert
@@ -367,8 +383,8 @@ func (u userDo) CountByStatus() (result []map[string]interface{}, err error) {
 //	COALESCE(qw_voice.available_amount, 0) as voice_balance
 //
 // FROM @@table u
-// LEFT JOIN quota_wallets qw_sms ON qw_sms.user_id = u.id AND qw_sms.channel = 'sms'
-// LEFT JOIN quota_wallets qw_voice ON qw_voice.user_id = u.id AND qw_voice.channel = 'voice'
+// LEFT JOIN quota_wallets qw_sms ON qw_sms.user_id = u.id AND qw_sms.channel = 'sms' AND qw_sms.group_id IS NULL
+// LEFT JOIN quota_wallets qw_voice ON qw_voice.user_id = u.id AND qw_voice.channel = 'voice' AND qw_voice.group_id IS NULL
 // WHERE u.id = @userID
 // LIMIT 1
 func (u userDo) GetWithQuotas(userID int64) (result map[string]interface{}, err error) {
@@ -376,7 +392,7 @@ func (u userDo) GetWithQuotas(userID int64) (result map[string]interface{}, err
 
 	var generateSQL strings.Builder
 	params = append(params, userID)
-	generateSQL.WriteString("SELECT u.*, COALESCE(qw_sms.available_amount, 0) as sms_balance, COALESCE(qw_voice.available_amount, 0) as voice_balance FROM users u LEFT JOIN quota_wallets qw_sms ON qw_sms.user_id = u.id AND qw_sms.channel = 'sms' LEFT JOIN quota_wallets qw_voice ON qw_voice.user_id = u.id AND qw_voice.channel = 'voice' WHERE u.id = ? LIMIT 1 ")
+	generateSQL.WriteString("SELECT u.*, COALESCE(qw_sms.available_amount, 0) as sms_balance, COALESCE(qw_voice.available_amount, 0) as voice_balance FROM users u LEFT JOIN quota_wallets qw_sms ON qw_sms.user_id = u.id AND qw_sms.channel = 'sms' AND qw_sms.group_id IS NULL LEFT JOIN quota_wallets qw_voice ON qw_voice.user_id = u.id AND qw_voice.channel = 'voice' AND qw_voice.group_id IS NULL WHERE u.id = ? LIMIT 1 ")
 
 	result = make(map[string]interface{})
 	var executeSQL *gorm.DB
@@ -394,8 +410,8 @@ func (u userDo) GetWithQuotas(userID int64) (result map[string]interface{}, err
 //	COALESCE(qw_voice.available_amount, 0) as voice_balance
 //
 // FROM @@table u
-// LEFT JOIN quota_wallets qw_sms ON qw_sms.user_id = u.id AND qw_sms.channel = 'sms'
-// LEFT JOIN quota_wallets qw_voice ON qw_voice.user_id = u.id AND qw_voice.channel = 'voice'
+// LEFT JOIN quota_wallets qw_sms ON qw_sms.user_id = u.id AND qw_sms.channel = 'sms' AND qw_sms.group_id IS NULL
+// LEFT JOIN quota_wallets qw_voice ON qw_voice.user_id = u.id AND qw_voice.channel = 'voice' AND qw_voice.group_id IS NULL
 // WHERE u.public_id = @publicID
 // LIMIT 1
 func (u userDo) GetByPublicIDWithQuotas(publicID int64) (result map[string]interface{}, err error) {
@@ -403,7 +419,7 @@ func (u userDo) GetByPublicIDWithQuotas(publicID int64) (result map[string]inter
 
 	var generateSQL strings.Builder
 	params = append(params, publicID)
-	generateSQL.WriteString("SELECT u.*, COALESCE(qw_sms.available_amount, 0) as sms_balance, COALESCE(qw_voice.available_amount, 0) as voice_balance FROM users u LEFT JOIN quota_wallets qw_sms ON qw_sms.user_id = u.id AND qw_sms.channel = 'sms' LEFT JOIN quota_wallets qw_voice ON qw_voice.user_id = u.id AND qw_voice.channel = 'voice' WHERE u.public_id = ? LIMIT 1 ")
+	generateSQL.WriteString("SELECT u.*, COALESCE(qw_sms.available_amount, 0) as sms_balance, COALESCE(qw_voice.available_amount, 0) as voice_balance FROM users u LEFT JOIN quota_wallets qw_sms ON qw_sms.user_id = u.id AND qw_sms.channel = 'sms' AND qw_sms.group_id IS NULL LEFT JOIN quota_wallets qw_voice ON qw_voice.user_id = u.id AND qw_voice.channel = 'voice' AND qw_voice.group_id IS NULL WHERE u.public_id = ? LIMIT 1 ")
 
 	result = make(map[string]interface{})
 	var executeSQL *gorm.DB
@@ -0,0 +1,414 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"AreYouOK/internal/model"
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+func newWalletGroupInvitation(db *gorm.DB, opts ...gen.DOOption) walletGroupInvitation {
+	_walletGroupInvitation := walletGroupInvitation{}
+
+	_walletGroupInvitation.walletGroupInvitationDo.UseDB(db, opts...)
+	_walletGroupInvitation.walletGroupInvitationDo.UseModel(&model.WalletGroupInvitation{})
+
+	tableName := _walletGroupInvitation.walletGroupInvitationDo.TableName()
+	_walletGroupInvitation.ALL = field.NewAsterisk(tableName)
+	_walletGroupInvitation.ExpiresAt = field.NewTime(tableName, "expires_at")
+	_walletGroupInvitation.CreatedAt = field.NewTime(tableName, "created_at")
+	_walletGroupInvitation.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_walletGroupInvitation.DeletedAt = field.NewField(tableName, "deleted_at")
+	_walletGroupInvitation.ID = field.NewInt64(tableName, "id")
+	_walletGroupInvitation.GroupID = field.NewInt64(tableName, "group_id")
+	_walletGroupInvitation.UserID = field.NewInt64(tableName, "user_id")
+	_walletGroupInvitation.InviterID = field.NewInt64(tableName, "inviter_id")
+	_walletGroupInvitation.MonthlyCap = field.NewInt(tableName, "monthly_cap")
+
+	_walletGroupInvitation.fillFieldMap()
+
+	return _walletGroupInvitation
+}
+
+type walletGroupInvitation struct {
+	walletGroupInvitationDo
+
+	ALL        field.Asterisk
+	ExpiresAt  field.Time
+	CreatedAt  field.Time
+	UpdatedAt  field.Time
+	DeletedAt  field.Field
+	ID         field.Int64
+	GroupID    field.Int64
+	UserID     field.Int64
+	InviterID  field.Int64
+	MonthlyCap field.Int
+
+	fieldMap map[string]field.Expr
+}
+
+func (w walletGroupInvitation) Table(newTableName string) *walletGroupInvitation {
+	w.walletGroupInvitationDo.UseTable(newTableName)
+	return w.updateTableName(newTableName)
+}
+
+func (w walletGroupInvitation) As(alias string) *walletGroupInvitation {
+	w.walletGroupInvitationDo.DO = *(w.walletGroupInvitationDo.As(alias).(*gen.DO))
+	return w.updateTableName(alias)
+}
+
+func (w *walletGroupInvitation) updateTableName(table string) *walletGroupInvitation {
+	w.ALL = field.NewAsterisk(table)
+	w.ExpiresAt = field.NewTime(table, "expires_at")
+	w.CreatedAt = field.NewTime(table, "created_at")
+	w.UpdatedAt = field.NewTime(table, "updated_at")
+	w.DeletedAt = field.NewField(table, "deleted_at")
+	w.ID = field.NewInt64(table, "id")
+	w.GroupID = field.NewInt64(table, "group_id")
+	w.UserID = field.NewInt64(table, "user_id")
+	w.InviterID = field.NewInt64(table, "inviter_id")
+	w.MonthlyCap = field.NewInt(table, "monthly_cap")
+
+	w.fillFieldMap()
+
+	return w
+}
+
+func (w *walletGroupInvitation) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := w.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (w *walletGroupInvitation) fillFieldMap() {
+	w.fieldMap = make(map[string]field.Expr, 9)
+	w.fieldMap["expires_at"] = w.ExpiresAt
+	w.fieldMap["created_at"] = w.CreatedAt
+	w.fieldMap["updated_at"] = w.UpdatedAt
+	w.fieldMap["deleted_at"] = w.DeletedAt
+	w.fieldMap["id"] = w.ID
+	w.fieldMap["group_id"] = w.GroupID
+	w.fieldMap["user_id"] = w.UserID
+	w.fieldMap["inviter_id"] = w.InviterID
+	w.fieldMap["monthly_cap"] = w.MonthlyCap
+}
+
+func (w walletGroupInvitation) clone(db *gorm.DB) walletGroupInvitation {
+	w.walletGroupInvitationDo.ReplaceConnPool(db.Statement.ConnPool)
+	return w
+}
+
+func (w walletGroupInvitation) replaceDB(db *gorm.DB) walletGroupInvitation {
+	w.walletGroupInvitationDo.ReplaceDB(db)
+	return w
+}
+
+type walletGroupInvitationDo struct{ gen.DO }
+
+type IWalletGroupInvitationDo interface {
+	gen.SubQuery
+	Debug() IWalletGroupInvitationDo
+	WithContext(ctx context.Context) IWalletGroupInvitationDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IWalletGroupInvitationDo
+	WriteDB() IWalletGroupInvitationDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IWalletGroupInvitationDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IWalletGroupInvitationDo
+	Not(conds ...gen.Condition) IWalletGroupInvitationDo
+	Or(conds ...gen.Condition) IWalletGroupInvitationDo
+	Select(conds ...field.Expr) IWalletGroupInvitationDo
+	Where(conds ...gen.Condition) IWalletGroupInvitationDo
+	Order(conds ...field.Expr) IWalletGroupInvitationDo
+	Distinct(cols ...field.Expr) IWalletGroupInvitationDo
+	Omit(cols ...field.Expr) IWalletGroupInvitationDo
+	Join(table schema.Tabler, on ...field.Expr) IWalletGroupInvitationDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IWalletGroupInvitationDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IWalletGroupInvitationDo
+	Group(cols ...field.Expr) IWalletGroupInvitationDo
+	Having(conds ...gen.Condition) IWalletGroupInvitationDo
+	Limit(limit int) IWalletGroupInvitationDo
+	Offset(offset int) IWalletGroupInvitationDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IWalletGroupInvitationDo
+	Unscoped() IWalletGroupInvitationDo
+	Create(values ...*model.WalletGroupInvitation) error
+	CreateInBatches(values []*model.WalletGroupInvitation, batchSize int) error
+	Save(values ...*model.WalletGroupInvitation) error
+	First() (*model.WalletGroupInvitation, error)
+	Take() (*model.WalletGroupInvitation, error)
+	Last() (*model.WalletGroupInvitation, error)
+	Find() ([]*model.WalletGroupInvitation, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.WalletGroupInvitation, err error)
+	FindInBatches(result *[]*model.WalletGroupInvitation, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.WalletGroupInvitation) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IWalletGroupInvitationDo
+	Assign(attrs ...field.AssignExpr) IWalletGroupInvitationDo
+	Joins(fields ...field.RelationField) IWalletGroupInvitationDo
+	Preload(fields ...field.RelationField) IWalletGroupInvitationDo
+	FirstOrInit() (*model.WalletGroupInvitation, error)
+	FirstOrCreate() (*model.WalletGroupInvitation, error)
+	FindByPage(offset int, limit int) (result []*model.WalletGroupInvitation, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IWalletGroupInvitationDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (w walletGroupInvitationDo) Debug() IWalletGroupInvitationDo {
+	return w.withDO(w.DO.Debug())
+}
+
+func (w walletGroupInvitationDo) WithContext(ctx context.Context) IWalletGroupInvitationDo {
+	return w.withDO(w.DO.WithContext(ctx))
+}
+
+func (w walletGroupInvitationDo) ReadDB() IWalletGroupInvitationDo {
+	return w.Clauses(dbresolver.Read)
+}
+
+func (w walletGroupInvitationDo) WriteDB() IWalletGroupInvitationDo {
+	return w.Clauses(dbresolver.Write)
+}
+
+func (w walletGroupInvitationDo) Session(config *gorm.Session) IWalletGroupInvitationDo {
+	return w.withDO(w.DO.Session(config))
+}
+
+func (w walletGroupInvitationDo) Clauses(conds ...clause.Expression) IWalletGroupInvitationDo {
+	return w.withDO(w.DO.Clauses(conds...))
+}
+
+func (w walletGroupInvitationDo) Returning(value interface{}, columns ...string) IWalletGroupInvitationDo {
+	return w.withDO(w.DO.Returning(value, columns...))
+}
+
+func (w walletGroupInvitationDo) Not(conds ...gen.Condition) IWalletGroupInvitationDo {
+	return w.withDO(w.DO.Not(conds...))
+}
+
+func (w walletGroupInvitationDo) Or(conds ...gen.Condition) IWalletGroupInvitationDo {
+	return w.withDO(w.DO.Or(conds...))
+}
+
+func (w walletGroupInvitationDo) Select(conds ...field.Expr) IWalletGroupInvitationDo {
+	return w.withDO(w.DO.Select(conds...))
+}
+
+func (w walletGroupInvitationDo) Where(conds ...gen.Condition) IWalletGroupInvitationDo {
+	return w.withDO(w.DO.Where(conds...))
+}
+
+func (w walletGroupInvitationDo) Order(conds ...field.Expr) IWalletGroupInvitationDo {
+	return w.withDO(w.DO.Order(conds...))
+}
+
+func (w walletGroupInvitationDo) Distinct(cols ...field.Expr) IWalletGroupInvitationDo {
+	return w.withDO(w.DO.Distinct(cols...))
+}
+
+func (w walletGroupInvitationDo) Omit(cols ...field.Expr) IWalletGroupInvitationDo {
+	return w.withDO(w.DO.Omit(cols...))
+}
+
+func (w walletGroupInvitationDo) Join(table schema.Tabler, on ...field.Expr) IWalletGroupInvitationDo {
+	return w.withDO(w.DO.Join(table, on...))
+}
+
+func (w walletGroupInvitationDo) LeftJoin(table schema.Tabler, on ...field.Expr) IWalletGroupInvitationDo {
+	return w.withDO(w.DO.LeftJoin(table, on...))
+}
+
+func (w walletGroupInvitationDo) RightJoin(table schema.Tabler, on ...field.Expr) IWalletGroupInvitationDo {
+	return w.withDO(w.DO.RightJoin(table, on...))
+}
+
+func (w walletGroupInvitationDo) Group(cols ...field.Expr) IWalletGroupInvitationDo {
+	return w.withDO(w.DO.Group(cols...))
+}
+
+func (w walletGroupInvitationDo) Having(conds ...gen.Condition) IWalletGroupInvitationDo {
+	return w.withDO(w.DO.Having(conds...))
+}
+
+func (w walletGroupInvitationDo) Limit(limit int) IWalletGroupInvitationDo {
+	return w.withDO(w.DO.Limit(limit))
+}
+
+func (w walletGroupInvitationDo) Offset(offset int) IWalletGroupInvitationDo {
+	return w.withDO(w.DO.Offset(offset))
+}
+
+func (w walletGroupInvitationDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IWalletGroupInvitationDo {
+	return w.withDO(w.DO.Scopes(funcs...))
+}
+
+func (w walletGroupInvitationDo) Unscoped() IWalletGroupInvitationDo {
+	return w.withDO(w.DO.Unscoped())
+}
+
+func (w walletGroupInvitationDo) Create(values ...*model.WalletGroupInvitation) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return w.DO.Create(values)
+}
+
+func (w walletGroupInvitationDo) CreateInBatches(values []*model.WalletGroupInvitation, batchSize int) error {
+	return w.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (w walletGroupInvitationDo) Save(values ...*model.WalletGroupInvitation) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return w.DO.Save(values)
+}
+
+func (w walletGroupInvitationDo) First() (*model.WalletGroupInvitation, error) {
+	if result, err := w.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.WalletGroupInvitation), nil
+	}
+}
+
+func (w walletGroupInvitationDo) Take() (*model.WalletGroupInvitation, error) {
+	if result, err := w.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.WalletGroupInvitation), nil
+	}
+}
+
+func (w walletGroupInvitationDo) Last() (*model.WalletGroupInvitation, error) {
+	if result, err := w.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.WalletGroupInvitation), nil
+	}
+}
+
+func (w walletGroupInvitationDo) Find() ([]*model.WalletGroupInvitation, error) {
+	result, err := w.DO.Find()
+	return result.([]*model.WalletGroupInvitation), err
+}
+
+func (w walletGroupInvitationDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.WalletGroupInvitation, err error) {
+	buf := make([]*model.WalletGroupInvitation, 0, batchSize)
+	err = w.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (w walletGroupInvitationDo) FindInBatches(result *[]*model.WalletGroupInvitation, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return w.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (w walletGroupInvitationDo) Attrs(attrs ...field.AssignExpr) IWalletGroupInvitationDo {
+	return w.withDO(w.DO.Attrs(attrs...))
+}
+
+func (w walletGroupInvitationDo) Assign(attrs ...field.AssignExpr) IWalletGroupInvitationDo {
+	return w.withDO(w.DO.Assign(attrs...))
+}
+
+func (w walletGroupInvitationDo) Joins(fields ...field.RelationField) IWalletGroupInvitationDo {
+	for _, _f := range fields {
+		w = *w.withDO(w.DO.Joins(_f))
+	}
+	return &w
+}
+
+func (w walletGroupInvitationDo) Preload(fields ...field.RelationField) IWalletGroupInvitationDo {
+	for _, _f := range fields {
+		w = *w.withDO(w.DO.Preload(_f))
+	}
+	return &w
+}
+
+func (w walletGroupInvitationDo) FirstOrInit() (*model.WalletGroupInvitation, error) {
+	if result, err := w.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.WalletGroupInvitation), nil
+	}
+}
+
+func (w walletGroupInvitationDo) FirstOrCreate() (*model.WalletGroupInvitation, error) {
+	if result, err := w.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.WalletGroupInvitation), nil
+	}
+}
+
+func (w walletGroupInvitationDo) FindByPage(offset int, limit int) (result []*model.WalletGroupInvitation, count int64, err error) {
+	result, err = w.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = w.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (w walletGroupInvitationDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = w.Count()
+	if err != nil {
+		return
+	}
+
+	err = w.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (w walletGroupInvitationDo) Scan(result interface{}) (err error) {
+	return w.DO.Scan(result)
+}
+
+func (w walletGroupInvitationDo) Delete(models ...*model.WalletGroupInvitation) (result gen.ResultInfo, err error) {
+	return w.DO.Delete(models)
+}
+
+func (w *walletGroupInvitationDo) withDO(do gen.Dao) *walletGroupInvitationDo {
+	w.DO = *do.(*gen.DO)
+	return w
+}
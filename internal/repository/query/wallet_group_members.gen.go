@@ -0,0 +1,414 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"AreYouOK/internal/model"
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+func newWalletGroupMember(db *gorm.DB, opts ...gen.DOOption) walletGroupMember {
+	_walletGroupMember := walletGroupMember{}
+
+	_walletGroupMember.walletGroupMemberDo.UseDB(db, opts...)
+	_walletGroupMember.walletGroupMemberDo.UseModel(&model.WalletGroupMember{})
+
+	tableName := _walletGroupMember.walletGroupMemberDo.TableName()
+	_walletGroupMember.ALL = field.NewAsterisk(tableName)
+	_walletGroupMember.Role = field.NewString(tableName, "role")
+	_walletGroupMember.FundingOrder = field.NewString(tableName, "funding_order")
+	_walletGroupMember.CreatedAt = field.NewTime(tableName, "created_at")
+	_walletGroupMember.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_walletGroupMember.DeletedAt = field.NewField(tableName, "deleted_at")
+	_walletGroupMember.ID = field.NewInt64(tableName, "id")
+	_walletGroupMember.GroupID = field.NewInt64(tableName, "group_id")
+	_walletGroupMember.UserID = field.NewInt64(tableName, "user_id")
+	_walletGroupMember.MonthlyCap = field.NewInt(tableName, "monthly_cap")
+
+	_walletGroupMember.fillFieldMap()
+
+	return _walletGroupMember
+}
+
+type walletGroupMember struct {
+	walletGroupMemberDo
+
+	ALL          field.Asterisk
+	Role         field.String
+	FundingOrder field.String
+	CreatedAt    field.Time
+	UpdatedAt    field.Time
+	DeletedAt    field.Field
+	ID           field.Int64
+	GroupID      field.Int64
+	UserID       field.Int64
+	MonthlyCap   field.Int
+
+	fieldMap map[string]field.Expr
+}
+
+func (w walletGroupMember) Table(newTableName string) *walletGroupMember {
+	w.walletGroupMemberDo.UseTable(newTableName)
+	return w.updateTableName(newTableName)
+}
+
+func (w walletGroupMember) As(alias string) *walletGroupMember {
+	w.walletGroupMemberDo.DO = *(w.walletGroupMemberDo.As(alias).(*gen.DO))
+	return w.updateTableName(alias)
+}
+
+func (w *walletGroupMember) updateTableName(table string) *walletGroupMember {
+	w.ALL = field.NewAsterisk(table)
+	w.Role = field.NewString(table, "role")
+	w.FundingOrder = field.NewString(table, "funding_order")
+	w.CreatedAt = field.NewTime(table, "created_at")
+	w.UpdatedAt = field.NewTime(table, "updated_at")
+	w.DeletedAt = field.NewField(table, "deleted_at")
+	w.ID = field.NewInt64(table, "id")
+	w.GroupID = field.NewInt64(table, "group_id")
+	w.UserID = field.NewInt64(table, "user_id")
+	w.MonthlyCap = field.NewInt(table, "monthly_cap")
+
+	w.fillFieldMap()
+
+	return w
+}
+
+func (w *walletGroupMember) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := w.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (w *walletGroupMember) fillFieldMap() {
+	w.fieldMap = make(map[string]field.Expr, 9)
+	w.fieldMap["role"] = w.Role
+	w.fieldMap["funding_order"] = w.FundingOrder
+	w.fieldMap["created_at"] = w.CreatedAt
+	w.fieldMap["updated_at"] = w.UpdatedAt
+	w.fieldMap["deleted_at"] = w.DeletedAt
+	w.fieldMap["id"] = w.ID
+	w.fieldMap["group_id"] = w.GroupID
+	w.fieldMap["user_id"] = w.UserID
+	w.fieldMap["monthly_cap"] = w.MonthlyCap
+}
+
+func (w walletGroupMember) clone(db *gorm.DB) walletGroupMember {
+	w.walletGroupMemberDo.ReplaceConnPool(db.Statement.ConnPool)
+	return w
+}
+
+func (w walletGroupMember) replaceDB(db *gorm.DB) walletGroupMember {
+	w.walletGroupMemberDo.ReplaceDB(db)
+	return w
+}
+
+type walletGroupMemberDo struct{ gen.DO }
+
+type IWalletGroupMemberDo interface {
+	gen.SubQuery
+	Debug() IWalletGroupMemberDo
+	WithContext(ctx context.Context) IWalletGroupMemberDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IWalletGroupMemberDo
+	WriteDB() IWalletGroupMemberDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IWalletGroupMemberDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IWalletGroupMemberDo
+	Not(conds ...gen.Condition) IWalletGroupMemberDo
+	Or(conds ...gen.Condition) IWalletGroupMemberDo
+	Select(conds ...field.Expr) IWalletGroupMemberDo
+	Where(conds ...gen.Condition) IWalletGroupMemberDo
+	Order(conds ...field.Expr) IWalletGroupMemberDo
+	Distinct(cols ...field.Expr) IWalletGroupMemberDo
+	Omit(cols ...field.Expr) IWalletGroupMemberDo
+	Join(table schema.Tabler, on ...field.Expr) IWalletGroupMemberDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IWalletGroupMemberDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IWalletGroupMemberDo
+	Group(cols ...field.Expr) IWalletGroupMemberDo
+	Having(conds ...gen.Condition) IWalletGroupMemberDo
+	Limit(limit int) IWalletGroupMemberDo
+	Offset(offset int) IWalletGroupMemberDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IWalletGroupMemberDo
+	Unscoped() IWalletGroupMemberDo
+	Create(values ...*model.WalletGroupMember) error
+	CreateInBatches(values []*model.WalletGroupMember, batchSize int) error
+	Save(values ...*model.WalletGroupMember) error
+	First() (*model.WalletGroupMember, error)
+	Take() (*model.WalletGroupMember, error)
+	Last() (*model.WalletGroupMember, error)
+	Find() ([]*model.WalletGroupMember, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.WalletGroupMember, err error)
+	FindInBatches(result *[]*model.WalletGroupMember, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.WalletGroupMember) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IWalletGroupMemberDo
+	Assign(attrs ...field.AssignExpr) IWalletGroupMemberDo
+	Joins(fields ...field.RelationField) IWalletGroupMemberDo
+	Preload(fields ...field.RelationField) IWalletGroupMemberDo
+	FirstOrInit() (*model.WalletGroupMember, error)
+	FirstOrCreate() (*model.WalletGroupMember, error)
+	FindByPage(offset int, limit int) (result []*model.WalletGroupMember, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IWalletGroupMemberDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (w walletGroupMemberDo) Debug() IWalletGroupMemberDo {
+	return w.withDO(w.DO.Debug())
+}
+
+func (w walletGroupMemberDo) WithContext(ctx context.Context) IWalletGroupMemberDo {
+	return w.withDO(w.DO.WithContext(ctx))
+}
+
+func (w walletGroupMemberDo) ReadDB() IWalletGroupMemberDo {
+	return w.Clauses(dbresolver.Read)
+}
+
+func (w walletGroupMemberDo) WriteDB() IWalletGroupMemberDo {
+	return w.Clauses(dbresolver.Write)
+}
+
+func (w walletGroupMemberDo) Session(config *gorm.Session) IWalletGroupMemberDo {
+	return w.withDO(w.DO.Session(config))
+}
+
+func (w walletGroupMemberDo) Clauses(conds ...clause.Expression) IWalletGroupMemberDo {
+	return w.withDO(w.DO.Clauses(conds...))
+}
+
+func (w walletGroupMemberDo) Returning(value interface{}, columns ...string) IWalletGroupMemberDo {
+	return w.withDO(w.DO.Returning(value, columns...))
+}
+
+func (w walletGroupMemberDo) Not(conds ...gen.Condition) IWalletGroupMemberDo {
+	return w.withDO(w.DO.Not(conds...))
+}
+
+func (w walletGroupMemberDo) Or(conds ...gen.Condition) IWalletGroupMemberDo {
+	return w.withDO(w.DO.Or(conds...))
+}
+
+func (w walletGroupMemberDo) Select(conds ...field.Expr) IWalletGroupMemberDo {
+	return w.withDO(w.DO.Select(conds...))
+}
+
+func (w walletGroupMemberDo) Where(conds ...gen.Condition) IWalletGroupMemberDo {
+	return w.withDO(w.DO.Where(conds...))
+}
+
+func (w walletGroupMemberDo) Order(conds ...field.Expr) IWalletGroupMemberDo {
+	return w.withDO(w.DO.Order(conds...))
+}
+
+func (w walletGroupMemberDo) Distinct(cols ...field.Expr) IWalletGroupMemberDo {
+	return w.withDO(w.DO.Distinct(cols...))
+}
+
+func (w walletGroupMemberDo) Omit(cols ...field.Expr) IWalletGroupMemberDo {
+	return w.withDO(w.DO.Omit(cols...))
+}
+
+func (w walletGroupMemberDo) Join(table schema.Tabler, on ...field.Expr) IWalletGroupMemberDo {
+	return w.withDO(w.DO.Join(table, on...))
+}
+
+func (w walletGroupMemberDo) LeftJoin(table schema.Tabler, on ...field.Expr) IWalletGroupMemberDo {
+	return w.withDO(w.DO.LeftJoin(table, on...))
+}
+
+func (w walletGroupMemberDo) RightJoin(table schema.Tabler, on ...field.Expr) IWalletGroupMemberDo {
+	return w.withDO(w.DO.RightJoin(table, on...))
+}
+
+func (w walletGroupMemberDo) Group(cols ...field.Expr) IWalletGroupMemberDo {
+	return w.withDO(w.DO.Group(cols...))
+}
+
+func (w walletGroupMemberDo) Having(conds ...gen.Condition) IWalletGroupMemberDo {
+	return w.withDO(w.DO.Having(conds...))
+}
+
+func (w walletGroupMemberDo) Limit(limit int) IWalletGroupMemberDo {
+	return w.withDO(w.DO.Limit(limit))
+}
+
+func (w walletGroupMemberDo) Offset(offset int) IWalletGroupMemberDo {
+	return w.withDO(w.DO.Offset(offset))
+}
+
+func (w walletGroupMemberDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IWalletGroupMemberDo {
+	return w.withDO(w.DO.Scopes(funcs...))
+}
+
+func (w walletGroupMemberDo) Unscoped() IWalletGroupMemberDo {
+	return w.withDO(w.DO.Unscoped())
+}
+
+func (w walletGroupMemberDo) Create(values ...*model.WalletGroupMember) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return w.DO.Create(values)
+}
+
+func (w walletGroupMemberDo) CreateInBatches(values []*model.WalletGroupMember, batchSize int) error {
+	return w.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (w walletGroupMemberDo) Save(values ...*model.WalletGroupMember) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return w.DO.Save(values)
+}
+
+func (w walletGroupMemberDo) First() (*model.WalletGroupMember, error) {
+	if result, err := w.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.WalletGroupMember), nil
+	}
+}
+
+func (w walletGroupMemberDo) Take() (*model.WalletGroupMember, error) {
+	if result, err := w.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.WalletGroupMember), nil
+	}
+}
+
+func (w walletGroupMemberDo) Last() (*model.WalletGroupMember, error) {
+	if result, err := w.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.WalletGroupMember), nil
+	}
+}
+
+func (w walletGroupMemberDo) Find() ([]*model.WalletGroupMember, error) {
+	result, err := w.DO.Find()
+	return result.([]*model.WalletGroupMember), err
+}
+
+func (w walletGroupMemberDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.WalletGroupMember, err error) {
+	buf := make([]*model.WalletGroupMember, 0, batchSize)
+	err = w.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (w walletGroupMemberDo) FindInBatches(result *[]*model.WalletGroupMember, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return w.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (w walletGroupMemberDo) Attrs(attrs ...field.AssignExpr) IWalletGroupMemberDo {
+	return w.withDO(w.DO.Attrs(attrs...))
+}
+
+func (w walletGroupMemberDo) Assign(attrs ...field.AssignExpr) IWalletGroupMemberDo {
+	return w.withDO(w.DO.Assign(attrs...))
+}
+
+func (w walletGroupMemberDo) Joins(fields ...field.RelationField) IWalletGroupMemberDo {
+	for _, _f := range fields {
+		w = *w.withDO(w.DO.Joins(_f))
+	}
+	return &w
+}
+
+func (w walletGroupMemberDo) Preload(fields ...field.RelationField) IWalletGroupMemberDo {
+	for _, _f := range fields {
+		w = *w.withDO(w.DO.Preload(_f))
+	}
+	return &w
+}
+
+func (w walletGroupMemberDo) FirstOrInit() (*model.WalletGroupMember, error) {
+	if result, err := w.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.WalletGroupMember), nil
+	}
+}
+
+func (w walletGroupMemberDo) FirstOrCreate() (*model.WalletGroupMember, error) {
+	if result, err := w.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.WalletGroupMember), nil
+	}
+}
+
+func (w walletGroupMemberDo) FindByPage(offset int, limit int) (result []*model.WalletGroupMember, count int64, err error) {
+	result, err = w.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = w.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (w walletGroupMemberDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = w.Count()
+	if err != nil {
+		return
+	}
+
+	err = w.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (w walletGroupMemberDo) Scan(result interface{}) (err error) {
+	return w.DO.Scan(result)
+}
+
+func (w walletGroupMemberDo) Delete(models ...*model.WalletGroupMember) (result gen.ResultInfo, err error) {
+	return w.DO.Delete(models)
+}
+
+func (w *walletGroupMemberDo) withDO(do gen.Dao) *walletGroupMemberDo {
+	w.DO = *do.(*gen.DO)
+	return w
+}
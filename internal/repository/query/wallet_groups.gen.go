@@ -0,0 +1,402 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package query
+
+import (
+	"AreYouOK/internal/model"
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+func newWalletGroup(db *gorm.DB, opts ...gen.DOOption) walletGroup {
+	_walletGroup := walletGroup{}
+
+	_walletGroup.walletGroupDo.UseDB(db, opts...)
+	_walletGroup.walletGroupDo.UseModel(&model.WalletGroup{})
+
+	tableName := _walletGroup.walletGroupDo.TableName()
+	_walletGroup.ALL = field.NewAsterisk(tableName)
+	_walletGroup.Name = field.NewString(tableName, "name")
+	_walletGroup.CreatedAt = field.NewTime(tableName, "created_at")
+	_walletGroup.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_walletGroup.DeletedAt = field.NewField(tableName, "deleted_at")
+	_walletGroup.ID = field.NewInt64(tableName, "id")
+	_walletGroup.OwnerID = field.NewInt64(tableName, "owner_id")
+
+	_walletGroup.fillFieldMap()
+
+	return _walletGroup
+}
+
+type walletGroup struct {
+	walletGroupDo
+
+	ALL       field.Asterisk
+	Name      field.String
+	CreatedAt field.Time
+	UpdatedAt field.Time
+	DeletedAt field.Field
+	ID        field.Int64
+	OwnerID   field.Int64
+
+	fieldMap map[string]field.Expr
+}
+
+func (w walletGroup) Table(newTableName string) *walletGroup {
+	w.walletGroupDo.UseTable(newTableName)
+	return w.updateTableName(newTableName)
+}
+
+func (w walletGroup) As(alias string) *walletGroup {
+	w.walletGroupDo.DO = *(w.walletGroupDo.As(alias).(*gen.DO))
+	return w.updateTableName(alias)
+}
+
+func (w *walletGroup) updateTableName(table string) *walletGroup {
+	w.ALL = field.NewAsterisk(table)
+	w.Name = field.NewString(table, "name")
+	w.CreatedAt = field.NewTime(table, "created_at")
+	w.UpdatedAt = field.NewTime(table, "updated_at")
+	w.DeletedAt = field.NewField(table, "deleted_at")
+	w.ID = field.NewInt64(table, "id")
+	w.OwnerID = field.NewInt64(table, "owner_id")
+
+	w.fillFieldMap()
+
+	return w
+}
+
+func (w *walletGroup) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := w.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (w *walletGroup) fillFieldMap() {
+	w.fieldMap = make(map[string]field.Expr, 6)
+	w.fieldMap["name"] = w.Name
+	w.fieldMap["created_at"] = w.CreatedAt
+	w.fieldMap["updated_at"] = w.UpdatedAt
+	w.fieldMap["deleted_at"] = w.DeletedAt
+	w.fieldMap["id"] = w.ID
+	w.fieldMap["owner_id"] = w.OwnerID
+}
+
+func (w walletGroup) clone(db *gorm.DB) walletGroup {
+	w.walletGroupDo.ReplaceConnPool(db.Statement.ConnPool)
+	return w
+}
+
+func (w walletGroup) replaceDB(db *gorm.DB) walletGroup {
+	w.walletGroupDo.ReplaceDB(db)
+	return w
+}
+
+type walletGroupDo struct{ gen.DO }
+
+type IWalletGroupDo interface {
+	gen.SubQuery
+	Debug() IWalletGroupDo
+	WithContext(ctx context.Context) IWalletGroupDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IWalletGroupDo
+	WriteDB() IWalletGroupDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IWalletGroupDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IWalletGroupDo
+	Not(conds ...gen.Condition) IWalletGroupDo
+	Or(conds ...gen.Condition) IWalletGroupDo
+	Select(conds ...field.Expr) IWalletGroupDo
+	Where(conds ...gen.Condition) IWalletGroupDo
+	Order(conds ...field.Expr) IWalletGroupDo
+	Distinct(cols ...field.Expr) IWalletGroupDo
+	Omit(cols ...field.Expr) IWalletGroupDo
+	Join(table schema.Tabler, on ...field.Expr) IWalletGroupDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IWalletGroupDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IWalletGroupDo
+	Group(cols ...field.Expr) IWalletGroupDo
+	Having(conds ...gen.Condition) IWalletGroupDo
+	Limit(limit int) IWalletGroupDo
+	Offset(offset int) IWalletGroupDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IWalletGroupDo
+	Unscoped() IWalletGroupDo
+	Create(values ...*model.WalletGroup) error
+	CreateInBatches(values []*model.WalletGroup, batchSize int) error
+	Save(values ...*model.WalletGroup) error
+	First() (*model.WalletGroup, error)
+	Take() (*model.WalletGroup, error)
+	Last() (*model.WalletGroup, error)
+	Find() ([]*model.WalletGroup, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.WalletGroup, err error)
+	FindInBatches(result *[]*model.WalletGroup, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.WalletGroup) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IWalletGroupDo
+	Assign(attrs ...field.AssignExpr) IWalletGroupDo
+	Joins(fields ...field.RelationField) IWalletGroupDo
+	Preload(fields ...field.RelationField) IWalletGroupDo
+	FirstOrInit() (*model.WalletGroup, error)
+	FirstOrCreate() (*model.WalletGroup, error)
+	FindByPage(offset int, limit int) (result []*model.WalletGroup, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IWalletGroupDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (w walletGroupDo) Debug() IWalletGroupDo {
+	return w.withDO(w.DO.Debug())
+}
+
+func (w walletGroupDo) WithContext(ctx context.Context) IWalletGroupDo {
+	return w.withDO(w.DO.WithContext(ctx))
+}
+
+func (w walletGroupDo) ReadDB() IWalletGroupDo {
+	return w.Clauses(dbresolver.Read)
+}
+
+func (w walletGroupDo) WriteDB() IWalletGroupDo {
+	return w.Clauses(dbresolver.Write)
+}
+
+func (w walletGroupDo) Session(config *gorm.Session) IWalletGroupDo {
+	return w.withDO(w.DO.Session(config))
+}
+
+func (w walletGroupDo) Clauses(conds ...clause.Expression) IWalletGroupDo {
+	return w.withDO(w.DO.Clauses(conds...))
+}
+
+func (w walletGroupDo) Returning(value interface{}, columns ...string) IWalletGroupDo {
+	return w.withDO(w.DO.Returning(value, columns...))
+}
+
+func (w walletGroupDo) Not(conds ...gen.Condition) IWalletGroupDo {
+	return w.withDO(w.DO.Not(conds...))
+}
+
+func (w walletGroupDo) Or(conds ...gen.Condition) IWalletGroupDo {
+	return w.withDO(w.DO.Or(conds...))
+}
+
+func (w walletGroupDo) Select(conds ...field.Expr) IWalletGroupDo {
+	return w.withDO(w.DO.Select(conds...))
+}
+
+func (w walletGroupDo) Where(conds ...gen.Condition) IWalletGroupDo {
+	return w.withDO(w.DO.Where(conds...))
+}
+
+func (w walletGroupDo) Order(conds ...field.Expr) IWalletGroupDo {
+	return w.withDO(w.DO.Order(conds...))
+}
+
+func (w walletGroupDo) Distinct(cols ...field.Expr) IWalletGroupDo {
+	return w.withDO(w.DO.Distinct(cols...))
+}
+
+func (w walletGroupDo) Omit(cols ...field.Expr) IWalletGroupDo {
+	return w.withDO(w.DO.Omit(cols...))
+}
+
+func (w walletGroupDo) Join(table schema.Tabler, on ...field.Expr) IWalletGroupDo {
+	return w.withDO(w.DO.Join(table, on...))
+}
+
+func (w walletGroupDo) LeftJoin(table schema.Tabler, on ...field.Expr) IWalletGroupDo {
+	return w.withDO(w.DO.LeftJoin(table, on...))
+}
+
+func (w walletGroupDo) RightJoin(table schema.Tabler, on ...field.Expr) IWalletGroupDo {
+	return w.withDO(w.DO.RightJoin(table, on...))
+}
+
+func (w walletGroupDo) Group(cols ...field.Expr) IWalletGroupDo {
+	return w.withDO(w.DO.Group(cols...))
+}
+
+func (w walletGroupDo) Having(conds ...gen.Condition) IWalletGroupDo {
+	return w.withDO(w.DO.Having(conds...))
+}
+
+func (w walletGroupDo) Limit(limit int) IWalletGroupDo {
+	return w.withDO(w.DO.Limit(limit))
+}
+
+func (w walletGroupDo) Offset(offset int) IWalletGroupDo {
+	return w.withDO(w.DO.Offset(offset))
+}
+
+func (w walletGroupDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IWalletGroupDo {
+	return w.withDO(w.DO.Scopes(funcs...))
+}
+
+func (w walletGroupDo) Unscoped() IWalletGroupDo {
+	return w.withDO(w.DO.Unscoped())
+}
+
+func (w walletGroupDo) Create(values ...*model.WalletGroup) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return w.DO.Create(values)
+}
+
+func (w walletGroupDo) CreateInBatches(values []*model.WalletGroup, batchSize int) error {
+	return w.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (w walletGroupDo) Save(values ...*model.WalletGroup) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return w.DO.Save(values)
+}
+
+func (w walletGroupDo) First() (*model.WalletGroup, error) {
+	if result, err := w.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.WalletGroup), nil
+	}
+}
+
+func (w walletGroupDo) Take() (*model.WalletGroup, error) {
+	if result, err := w.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.WalletGroup), nil
+	}
+}
+
+func (w walletGroupDo) Last() (*model.WalletGroup, error) {
+	if result, err := w.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.WalletGroup), nil
+	}
+}
+
+func (w walletGroupDo) Find() ([]*model.WalletGroup, error) {
+	result, err := w.DO.Find()
+	return result.([]*model.WalletGroup), err
+}
+
+func (w walletGroupDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.WalletGroup, err error) {
+	buf := make([]*model.WalletGroup, 0, batchSize)
+	err = w.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (w walletGroupDo) FindInBatches(result *[]*model.WalletGroup, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return w.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (w walletGroupDo) Attrs(attrs ...field.AssignExpr) IWalletGroupDo {
+	return w.withDO(w.DO.Attrs(attrs...))
+}
+
+func (w walletGroupDo) Assign(attrs ...field.AssignExpr) IWalletGroupDo {
+	return w.withDO(w.DO.Assign(attrs...))
+}
+
+func (w walletGroupDo) Joins(fields ...field.RelationField) IWalletGroupDo {
+	for _, _f := range fields {
+		w = *w.withDO(w.DO.Joins(_f))
+	}
+	return &w
+}
+
+func (w walletGroupDo) Preload(fields ...field.RelationField) IWalletGroupDo {
+	for _, _f := range fields {
+		w = *w.withDO(w.DO.Preload(_f))
+	}
+	return &w
+}
+
+func (w walletGroupDo) FirstOrInit() (*model.WalletGroup, error) {
+	if result, err := w.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.WalletGroup), nil
+	}
+}
+
+func (w walletGroupDo) FirstOrCreate() (*model.WalletGroup, error) {
+	if result, err := w.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.WalletGroup), nil
+	}
+}
+
+func (w walletGroupDo) FindByPage(offset int, limit int) (result []*model.WalletGroup, count int64, err error) {
+	result, err = w.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = w.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (w walletGroupDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = w.Count()
+	if err != nil {
+		return
+	}
+
+	err = w.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (w walletGroupDo) Scan(result interface{}) (err error) {
+	return w.DO.Scan(result)
+}
+
+func (w walletGroupDo) Delete(models ...*model.WalletGroup) (result gen.ResultInfo, err error) {
+	return w.DO.Delete(models)
+}
+
+func (w *walletGroupDo) withDO(do gen.Dao) *walletGroupDo {
+	w.DO = *do.(*gen.DO)
+	return w
+}
@@ -12,6 +12,7 @@ func Register(h *server.Hertz) {
 	h.Use(middleware.RecoverMiddleware())
 	h.Use(middleware.CORSMiddleware())
 	h.Use(middleware.OpenTelemetryMiddleware())
+	h.Use(middleware.RequestMetaMiddleware()) // 请求来源写入 context，供审计日志使用
 	//h.Use(middleware.CSRFMiddleware()) csrf 中间件，支付宝小程序似乎不需要
 	v1 := h.Group("/v1")
 
@@ -19,9 +20,11 @@ func Register(h *server.Hertz) {
 	auth := v1.Group("/auth")
 	auth.GET("/waitlist/status", handler.GetWaitlistStatus)
 	auth.POST("/token/refresh", handler.RefreshToken)
+	auth.POST("/logout", middleware.AuthMiddleware(), handler.Logout)
 	auth.Use(middleware.AuthRateLimitMiddleware()) // 认证接口限流
 	{
 		auth.POST("/miniapp/alipay/exchange", handler.ExchangeAlipayAuth)
+		auth.POST("/miniapp/wechat/exchange", handler.ExchangeWechatAuth)
 		
 
 		// 验证码相关路由
@@ -46,10 +49,46 @@ func Register(h *server.Hertz) {
 		users.GET("/me", handler.GetUserProfile)
 		users.PUT("/me/settings", /*middleware.UserSettingsRateLimitMiddleware(),*/ handler.UpdateUserSettings) // 用户设置修改限流
 		users.GET("/me/quotas", handler.GetUserQuotas)
+		users.POST("/me/quotas/redeem", middleware.RedeemRateLimitMiddleware(), handler.RedeemQuotaCode) // 兑换码限流，防止暴力枚举
+		users.GET("/me/subscription", handler.GetUserSubscription)
+		users.GET("/me/sessions", handler.ListSessions)
+		users.DELETE("/me/sessions/:id", handler.RevokeSession)
 		users.DELETE("/me", handler.DeleteUserProfile)
+		users.POST("/me/restore", handler.RestoreUserProfile)
+		users.POST("/me/export", handler.CreateDataExport)
+		users.GET("/me/exports/:id", handler.GetDataExport)
+		users.POST("/me/step-up/send-captcha", middleware.CaptchaRateLimitMiddleware(), handler.SendStepUpCaptcha)
+		users.POST("/me/step-up", handler.VerifyStepUp) // 二次验证，之后可导出完整的联系人号码
+		users.POST("/me/phone/send-captcha", middleware.CaptchaRateLimitMiddleware(), handler.SendChangePhoneCaptcha)
+		users.PUT("/me/phone", handler.ChangePhone)
+		users.GET("/me/identities", handler.ListIdentities)
+		users.POST("/me/identities", handler.LinkIdentity)
+		users.DELETE("/me/identities/:provider", handler.UnlinkIdentity)
+		users.POST("/me/merge", handler.MergeAccount)
+		users.GET("/me/activity", handler.ListActivity)
 		
 	}
 
+	// 数据导出下载（签名链接鉴权，无需登录）
+	v1.GET("/exports/:id/download", handler.DownloadDataExport)
+
+	// 共享钱包路由
+	walletGroups := v1.Group("/wallet-groups")
+	walletGroups.Use(middleware.AuthMiddleware())
+	{
+		walletGroups.POST("", handler.CreateWalletGroup)
+		walletGroups.GET("/me", handler.GetMyWalletGroup)
+		walletGroups.POST("/me/members", handler.InviteWalletGroupMember) // 对方接受邀请后加入
+		walletGroups.DELETE("/me/invitations/:invitation_id", handler.CancelWalletGroupInvitation)
+		walletGroups.PATCH("/me/members/:user_id", handler.UpdateWalletGroupMember)
+		walletGroups.DELETE("/me/members/:user_id", handler.RemoveWalletGroupMember)
+		walletGroups.POST("/me/transfer", handler.TransferToWalletGroup)
+		walletGroups.GET("/me/usage", handler.GetWalletGroupUsage)
+		walletGroups.GET("/invitations", handler.ListWalletGroupInvitations)
+		walletGroups.POST("/invitations/:invitation_id/accept", handler.AcceptWalletGroupInvitation)
+		walletGroups.POST("/invitations/:invitation_id/decline", handler.DeclineWalletGroupInvitation)
+	}
+
 	// 紧急联系人路由
 	contacts := v1.Group("/contacts")
 	contacts.Use(middleware.AuthMiddleware())
@@ -57,10 +96,47 @@ func Register(h *server.Hertz) {
 		contacts.GET("", handler.ListContacts)
 		contacts.POST("", handler.CreateContact)
 		contacts.PUT("", handler.ReplaceContacts) // 批量替换联系人
+		contacts.POST("/import", handler.ImportContacts) // 从 vCard 导入
+		contacts.GET("/export.vcf", handler.ExportContacts) // 导出 vCard
 		contacts.DELETE("/:priority", handler.DeleteContact)
 		contacts.PATCH("", handler.UpdateContact)
+		contacts.POST("/:priority/consent", handler.ResendContactConsent) // 重新发送确认短信
+	}
+
+	// 联系人确认链接（短信中的 token 鉴权，无需登录）
+	consents := v1.Group("/contact-consents", middleware.ContactConsentRateLimitMiddleware())
+	{
+		consents.GET("/:token", handler.GetContactConsent)
+		consents.POST("/:token/confirm", handler.ConfirmContactConsent)
+		consents.POST("/:token/decline", handler.DeclineContactConsent)
 	}
 
+	// 联系人退订（告警短信中的签名链接鉴权，无需登录）
+	v1.POST("/contact-opt-outs", middleware.ContactOptOutRateLimitMiddleware(), handler.OptOutContact)
+
+	// 联系人门户（手机验证码登录，联系人会话 token 与用户 token 互不通用）
+	portal := v1.Group("/contact-portal")
+	{
+		phone := portal.Group("/phone", middleware.CaptchaRateLimitMiddleware())
+		{
+			phone.POST("/send-captcha", handler.SendCaptcha)
+			phone.POST("/verify-slider", handler.VerifySlider)
+			phone.POST("/verify", handler.LoginContactPortal)
+		}
+
+		me := portal.Group("/me", middleware.ContactAuthMiddleware())
+		{
+			me.GET("/requesters", handler.ListContactRequesters)
+			me.GET("/alerts", handler.ListContactAlerts)
+			me.POST("/requesters/:contact_id/accept", handler.AcceptContactRequest)
+			me.POST("/requesters/:contact_id/decline", handler.DeclineContactRequest)
+			me.DELETE("/requesters/:contact_id", handler.LeaveContactList)
+		}
+	}
+
+	// 短信上行回调（SMS_INBOUND_TOKEN 鉴权）
+	v1.POST("/webhooks/sms/inbound", middleware.SMSInboundAuthMiddleware(), handler.ReceiveInboundSMS)
+
 	// 平安打卡路由
 	checkIns := v1.Group("/check-ins")
 	checkIns.Use(middleware.AuthMiddleware())
@@ -80,10 +156,47 @@ func Register(h *server.Hertz) {
 		journeys.GET("/:journey_id", handler.GetJourneyDetail)
 		journeys.PATCH("/:journey_id", middleware.JourneySettingsRateLimitMiddleware(), handler.UpdateJourney) //行程修改限流，这里最后还需要考虑最后几分钟就不能修改了的问题
 		journeys.POST("/:journey_id/complete", handler.CompleteJourney)
+		journeys.POST("/:journey_id/extend", handler.ExtendJourney) // 晚点了，次数与累计时长有上限
+		journeys.GET("/:journey_id/timeline", handler.GetJourneyTimeline)
+		journeys.POST("/:journey_id/checkpoints/:seq/check-in", handler.CheckInJourneyCheckpoint)
+		journeys.POST("/:journey_id/locations", handler.ReportJourneyLocations)
+		journeys.POST("/:journey_id/share", handler.ShareJourney) // 公开分享链接，重新生成后旧链接失效
+		journeys.DELETE("/:journey_id/share", handler.RevokeJourneyShare)
 		//journeys.POST("/:journey_id/ack-alert", handler.AckJourneyAlert)
 		journeys.GET("/:journey_id/alerts", handler.GetJourneyAlerts)
 		journeys.DELETE("/:journey_id", handler.CancelJourney)
 	}
+
+	// 行程模板与重复行程
+	journeyTemplates := v1.Group("/journey-templates")
+	journeyTemplates.Use(middleware.AuthMiddleware())
+	{
+		journeyTemplates.GET("", handler.ListJourneyTemplates)
+		journeyTemplates.POST("", handler.CreateJourneyTemplate)
+		journeyTemplates.PUT("/:template_id", handler.UpdateJourneyTemplate) // 全量替换，尚未开始的安排重新生成
+		journeyTemplates.DELETE("/:template_id", handler.DeleteJourneyTemplate)
+		journeyTemplates.POST("/:template_id/start", handler.StartJourneyFromTemplate)
+	}
+
+	journeyOccurrences := v1.Group("/journey-occurrences")
+	journeyOccurrences.Use(middleware.AuthMiddleware())
+	{
+		journeyOccurrences.GET("", handler.ListJourneyOccurrences)
+		journeyOccurrences.POST("/:occurrence_id/confirm", handler.ConfirmJourneyOccurrence) // 出发时间之后宽限期内仍可确认
+		journeyOccurrences.POST("/:occurrence_id/skip", handler.SkipJourneyOccurrence)
+	}
+
+	// 行程分享页（分享链接 token 鉴权，无需登录）
+	v1.GET("/shared/journeys/:token", middleware.JourneyShareRateLimitMiddleware(), handler.GetSharedJourney)
+
+	// 运维接口（ADMIN_API_TOKEN 鉴权）
+	admin := v1.Group("/admin")
+	admin.Use(middleware.AdminAuthMiddleware())
+	{
+		admin.GET("/audit-events", handler.QueryAuditEvents)
+		admin.PUT("/users/:user_id/subscription", handler.AdminSubscribe)
+		admin.DELETE("/users/:user_id/subscription", handler.AdminCancelSubscription)
+	}
 }
 
 	
@@ -0,0 +1,71 @@
+package schedule
+
+// 联系人调度器：定期把确认链接已过期仍未回应的联系人标记为 expired
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"AreYouOK/internal/service"
+	"AreYouOK/pkg/logger"
+)
+
+var (
+	contactSchedulerOnce sync.Once
+	contactSchedulerInst *ContactScheduler
+)
+
+// ContactScheduler 联系人调度器
+type ContactScheduler struct {
+	logger              *zap.Logger
+	expireJobRunning    bool
+	expireJobMu         sync.Mutex
+	lastExpireCheckTime time.Time
+}
+
+// GetContactScheduler 获取联系人调度器单例
+func GetContactScheduler() *ContactScheduler {
+	contactSchedulerOnce.Do(func() {
+		contactSchedulerInst = &ContactScheduler{
+			logger: logger.Logger,
+		}
+	})
+	return contactSchedulerInst
+}
+
+// ExpireContactConsents 标记确认链接已过期的联系人（定时任务调用）
+func (s *ContactScheduler) ExpireContactConsents(ctx context.Context) error {
+	s.expireJobMu.Lock()
+	if s.expireJobRunning {
+		s.expireJobMu.Unlock()
+		s.logger.Info("Contact consent expiry job already running, skipping")
+		return nil
+	}
+	s.expireJobRunning = true
+	s.expireJobMu.Unlock()
+
+	defer func() {
+		s.expireJobMu.Lock()
+		s.expireJobRunning = false
+		s.expireJobMu.Unlock()
+	}()
+
+	startTime := time.Now()
+	s.lastExpireCheckTime = startTime
+
+	expired, err := service.Contact().ExpireConsents(ctx, startTime)
+	if err != nil {
+		s.logger.Error("Failed to expire contact consents", zap.Error(err))
+		return err
+	}
+
+	s.logger.Info("Contact consent expiry check completed",
+		zap.Int("expired_count", expired),
+		zap.Duration("duration", time.Since(startTime)),
+	)
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package schedule
+
+// 账号清除调度器：定期扫描恢复窗口已结束的注销账号，清除个人数据
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"AreYouOK/internal/service"
+	"AreYouOK/pkg/logger"
+)
+
+var (
+	erasureSchedulerOnce sync.Once
+	erasureSchedulerInst *ErasureScheduler
+)
+
+// 单次扫描最多清除的账号数量，剩余的留给下一轮
+const accountPurgeBatchSize = 100
+
+// ErasureScheduler 账号清除调度器
+type ErasureScheduler struct {
+	logger             *zap.Logger
+	purgeJobRunning    bool
+	purgeJobMu         sync.Mutex
+	lastPurgeCheckTime time.Time
+}
+
+// GetErasureScheduler 获取账号清除调度器单例
+func GetErasureScheduler() *ErasureScheduler {
+	erasureSchedulerOnce.Do(func() {
+		erasureSchedulerInst = &ErasureScheduler{
+			logger: logger.Logger,
+		}
+	})
+	return erasureSchedulerInst
+}
+
+// PurgeDueAccounts 清除恢复窗口已结束的注销账号（定时任务调用）
+func (s *ErasureScheduler) PurgeDueAccounts(ctx context.Context) error {
+	s.purgeJobMu.Lock()
+	if s.purgeJobRunning {
+		s.purgeJobMu.Unlock()
+		s.logger.Info("Account purge job already running, skipping")
+		return nil
+	}
+	s.purgeJobRunning = true
+	s.purgeJobMu.Unlock()
+
+	defer func() {
+		s.purgeJobMu.Lock()
+		s.purgeJobRunning = false
+		s.purgeJobMu.Unlock()
+	}()
+
+	startTime := time.Now()
+	s.lastPurgeCheckTime = startTime
+
+	purged, err := service.Erasure().PurgeDue(ctx, startTime, accountPurgeBatchSize)
+	if err != nil {
+		s.logger.Error("Failed to purge erased accounts", zap.Error(err))
+		return err
+	}
+
+	s.logger.Info("Account purge check completed",
+		zap.Int("purged_count", purged),
+		zap.Duration("duration", time.Since(startTime)),
+	)
+
+	return nil
+}
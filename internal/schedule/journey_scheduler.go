@@ -15,6 +15,7 @@ import (
 	"AreYouOK/internal/model"
 	"AreYouOK/internal/queue"
 	"AreYouOK/internal/repository/query"
+	"AreYouOK/internal/service"
 	"AreYouOK/pkg/logger"
 	"AreYouOK/pkg/snowflake"
 )
@@ -30,6 +31,10 @@ type JourneyScheduler struct {
 	timeoutJobRunning    bool
 	timeoutJobMu         sync.Mutex
 	lastTimeoutCheckTime time.Time
+	purgeJobRunning      bool
+	purgeJobMu           sync.Mutex
+	recurrenceJobRunning bool
+	recurrenceJobMu      sync.Mutex
 }
 
 // GetJourneyScheduler 获取行程调度器单例
@@ -249,6 +254,15 @@ func (s *JourneyScheduler) CheckJourneyTimeouts(ctx context.Context, timeWindow
 				DelaySeconds: int(timeoutDelay.Seconds()),
 			}
 
+			if claimed, err := s.claimJourneyTimeout(ctx, j, timeoutMsg.MessageID); err != nil || !claimed {
+				if err != nil {
+					errorsMu.Lock()
+					errors = append(errors, err)
+					errorsMu.Unlock()
+				}
+				return
+			}
+
 			if err := queue.PublishJourneyTimeout(timeoutMsg); err != nil {
 				s.logger.Error("Failed to publish journey timeout message",
 					zap.Int64("journey_id", j.ID),
@@ -368,6 +382,15 @@ func (s *JourneyScheduler) CheckOverdueJourneys(ctx context.Context) error {
 				DelaySeconds: 0, // 立即处理
 			}
 
+			if claimed, err := s.claimJourneyTimeout(ctx, j, timeoutMsg.MessageID); err != nil || !claimed {
+				if err != nil {
+					errorsMu.Lock()
+					errors = append(errors, err)
+					errorsMu.Unlock()
+				}
+				return
+			}
+
 			// 发布消息
 			if err := queue.PublishJourneyTimeout(timeoutMsg); err != nil {
 				s.logger.Error("Failed to publish overdue journey timeout message",
@@ -397,3 +420,277 @@ func (s *JourneyScheduler) CheckOverdueJourneys(ctx context.Context) error {
 
 	return nil
 }
+
+// claimJourneyTimeout 发布超时消息前把 message_id 写入行程，consumer 只处理与当前值一致的消息
+// 行程在查询之后被完成、改期或延长时不再投递，返回 false
+func (s *JourneyScheduler) claimJourneyTimeout(ctx context.Context, j *model.Journey, messageID string) (bool, error) {
+	info, err := query.Journey.WithContext(ctx).
+		Where(query.Journey.ID.Eq(j.ID)).
+		Where(query.Journey.Status.Eq(string(model.JourneyStatusOngoing))).
+		Where(query.Journey.ExpectedReturnTime.Eq(j.ExpectedReturnTime)).
+		Update(query.Journey.TimeoutMessageID, messageID)
+	if err != nil {
+		s.logger.Error("Failed to store journey timeout message ID",
+			zap.Int64("journey_id", j.ID),
+			zap.Error(err),
+		)
+		return false, fmt.Errorf("failed to store timeout message ID for journey %d: %w", j.ID, err)
+	}
+	if info.RowsAffected == 0 {
+		s.logger.Debug("Journey changed since query, skipping timeout message",
+			zap.Int64("journey_id", j.ID),
+		)
+		return false, nil
+	}
+	return true, nil
+}
+
+// CheckCheckpointDeadlines 扫描即将到达或已过预计时间的待打卡检查点，投递检查点提醒与超时消息
+// 提醒在预计时间投递给用户本人，超时消息在预计时间 + 10 分钟到达，未打卡则通知紧急联系人
+// 只回看最近 24 小时的检查点，更早的检查点所在行程已由行程超时处理
+func (s *JourneyScheduler) CheckCheckpointDeadlines(ctx context.Context, timeWindow time.Duration) error {
+	now := time.Now()
+
+	checkpoints, err := query.JourneyCheckpoint.WithContext(ctx).
+		Where(query.JourneyCheckpoint.Status.Eq(string(model.JourneyCheckpointPending))).
+		Where(query.JourneyCheckpoint.ExpectedAt.Gte(now.Add(-24 * time.Hour))).
+		Where(query.JourneyCheckpoint.ExpectedAt.Lte(now.Add(timeWindow))).
+		Find()
+	if err != nil {
+		s.logger.Error("Failed to query pending journey checkpoints",
+			zap.Error(err),
+		)
+		return fmt.Errorf("failed to query pending journey checkpoints: %w", err)
+	}
+
+	if len(checkpoints) == 0 {
+		return nil
+	}
+
+	s.logger.Info("Found journey checkpoints approaching deadline",
+		zap.Int("checkpoint_count", len(checkpoints)),
+		zap.Duration("time_window", timeWindow),
+	)
+
+	errorCount := 0
+	for _, cp := range checkpoints {
+		if err := s.scheduleCheckpoint(ctx, cp, now); err != nil {
+			s.logger.Error("Failed to schedule journey checkpoint",
+				zap.Int64("checkpoint_id", cp.ID),
+				zap.Int64("journey_id", cp.JourneyID),
+				zap.Error(err),
+			)
+			errorCount++
+		}
+	}
+
+	if errorCount > 0 {
+		return fmt.Errorf("journey checkpoint check completed with %d errors", errorCount)
+	}
+	return nil
+}
+
+// scheduleCheckpoint 为单个检查点投递提醒与超时消息，行程已结束时跳过
+func (s *JourneyScheduler) scheduleCheckpoint(ctx context.Context, cp *model.JourneyCheckpoint, now time.Time) error {
+	journey, err := query.Journey.WithContext(ctx).
+		Where(query.Journey.ID.Eq(cp.JourneyID)).
+		First()
+	if err != nil {
+		return fmt.Errorf("failed to query journey: %w", err)
+	}
+	if journey.Status != model.JourneyStatusOngoing {
+		return nil
+	}
+
+	user, err := query.User.WithContext(ctx).
+		Where(query.User.ID.Eq(journey.UserID)).
+		First()
+	if err != nil {
+		return fmt.Errorf("failed to query user: %w", err)
+	}
+
+	timeoutAt := cp.ExpectedAt.Add(10 * time.Minute)
+
+	if cp.ReminderSentAt == nil && now.Before(timeoutAt) {
+		scheduled, err := cache.IsJourneyCheckpointReminderScheduled(ctx, cp.ID)
+		if err != nil {
+			s.logger.Warn("Failed to check checkpoint reminder scheduled status",
+				zap.Int64("checkpoint_id", cp.ID),
+				zap.Error(err),
+			)
+		}
+		if !scheduled {
+			msgID, err := snowflake.NextID(snowflake.GeneratorTypeMessage)
+			if err != nil {
+				return fmt.Errorf("failed to generate reminder message ID: %w", err)
+			}
+
+			reminderMsg := model.JourneyReminderMessage{
+				MessageID:    fmt.Sprintf("journey_checkpoint_reminder_%d", msgID),
+				ScheduledAt:  now.Format(time.RFC3339),
+				JourneyID:    journey.ID,
+				UserID:       user.PublicID,
+				CheckpointID: cp.ID,
+				DelaySeconds: int(max(cp.ExpectedAt.Sub(now), 0).Seconds()),
+			}
+			if err := queue.PublishJourneyReminder(reminderMsg); err != nil {
+				return fmt.Errorf("failed to publish checkpoint reminder message: %w", err)
+			}
+			if err := cache.MarkJourneyCheckpointReminderScheduled(ctx, cp.ID); err != nil {
+				s.logger.Warn("Failed to mark checkpoint reminder scheduled",
+					zap.Int64("checkpoint_id", cp.ID),
+					zap.Error(err),
+				)
+			}
+		}
+	}
+
+	scheduled, err := cache.IsJourneyCheckpointTimeoutScheduled(ctx, cp.ID)
+	if err != nil {
+		s.logger.Warn("Failed to check checkpoint timeout scheduled status",
+			zap.Int64("checkpoint_id", cp.ID),
+			zap.Error(err),
+		)
+	}
+	if scheduled {
+		return nil
+	}
+
+	msgID, err := snowflake.NextID(snowflake.GeneratorTypeMessage)
+	if err != nil {
+		return fmt.Errorf("failed to generate timeout message ID: %w", err)
+	}
+
+	timeoutMsg := model.JourneyTimeoutMessage{
+		MessageID:    fmt.Sprintf("journey_checkpoint_timeout_%d", msgID),
+		ScheduledAt:  now.Format(time.RFC3339),
+		JourneyID:    journey.ID,
+		UserID:       user.PublicID,
+		CheckpointID: cp.ID,
+		DelaySeconds: int(max(timeoutAt.Sub(now), 0).Seconds()),
+	}
+
+	// 先记录消息 ID，consumer 只处理与检查点当前 timeout_message_id 一致的消息
+	info, err := query.JourneyCheckpoint.WithContext(ctx).
+		Where(query.JourneyCheckpoint.ID.Eq(cp.ID)).
+		Where(query.JourneyCheckpoint.Status.Eq(string(model.JourneyCheckpointPending))).
+		Update(query.JourneyCheckpoint.TimeoutMessageID, timeoutMsg.MessageID)
+	if err != nil {
+		return fmt.Errorf("failed to store checkpoint timeout message ID: %w", err)
+	}
+	if info.RowsAffected == 0 {
+		return nil
+	}
+
+	if err := queue.PublishJourneyTimeout(timeoutMsg); err != nil {
+		return fmt.Errorf("failed to publish checkpoint timeout message: %w", err)
+	}
+	if err := cache.MarkJourneyCheckpointTimeoutScheduled(ctx, cp.ID); err != nil {
+		s.logger.Warn("Failed to mark checkpoint timeout scheduled",
+			zap.Int64("checkpoint_id", cp.ID),
+			zap.Error(err),
+		)
+	}
+
+	s.logger.Info("Published journey checkpoint messages",
+		zap.Int64("checkpoint_id", cp.ID),
+		zap.Int64("journey_id", journey.ID),
+		zap.Time("expected_at", cp.ExpectedAt),
+	)
+	return nil
+}
+
+// PurgeJourneyLocations 删除结束超过保留天数的行程的位置点（定时任务调用）
+func (s *JourneyScheduler) PurgeJourneyLocations(ctx context.Context) error {
+	s.purgeJobMu.Lock()
+	if s.purgeJobRunning {
+		s.purgeJobMu.Unlock()
+		s.logger.Info("Journey location purge job already running, skipping")
+		return nil
+	}
+	s.purgeJobRunning = true
+	s.purgeJobMu.Unlock()
+
+	defer func() {
+		s.purgeJobMu.Lock()
+		s.purgeJobRunning = false
+		s.purgeJobMu.Unlock()
+	}()
+
+	startTime := time.Now()
+	purged, err := service.Journey().PurgeLocations(ctx, startTime)
+	if err != nil {
+		s.logger.Error("Failed to purge journey locations", zap.Error(err))
+		return err
+	}
+
+	s.logger.Info("Journey location purge completed",
+		zap.Int64("purged_count", purged),
+		zap.Duration("duration", time.Since(startTime)),
+	)
+	return nil
+}
+
+// RunJourneyRecurrence 重复行程：生成未来的安排、出发前提醒确认、到点自动开始或记为跳过（定时任务调用）
+func (s *JourneyScheduler) RunJourneyRecurrence(ctx context.Context) error {
+	s.recurrenceJobMu.Lock()
+	if s.recurrenceJobRunning {
+		s.recurrenceJobMu.Unlock()
+		s.logger.Info("Journey recurrence job already running, skipping")
+		return nil
+	}
+	s.recurrenceJobRunning = true
+	s.recurrenceJobMu.Unlock()
+
+	defer func() {
+		s.recurrenceJobMu.Lock()
+		s.recurrenceJobRunning = false
+		s.recurrenceJobMu.Unlock()
+	}()
+
+	startTime := time.Now()
+	journeyService := service.Journey()
+
+	created, err := journeyService.MaterializeJourneyOccurrences(ctx, startTime)
+	if err != nil {
+		s.logger.Error("Failed to materialize journey occurrences", zap.Error(err))
+		return err
+	}
+
+	reminders, err := journeyService.RemindJourneyOccurrences(ctx, startTime)
+	for _, msg := range reminders {
+		if err := queue.PublishSMSNotification(msg); err != nil {
+			s.logger.Error("Failed to publish journey prestart reminder",
+				zap.Int64("task_code", msg.TaskCode),
+				zap.Error(err),
+			)
+		}
+	}
+	if err != nil {
+		s.logger.Error("Failed to remind journey occurrences", zap.Error(err))
+		return err
+	}
+
+	timeoutMsgs, err := journeyService.AdvanceJourneyOccurrences(ctx, startTime)
+	for _, msg := range timeoutMsgs {
+		if err := queue.PublishJourneyTimeout(*msg); err != nil {
+			// 行程已创建，超时消息由超时扫描任务补投
+			s.logger.Error("Failed to publish journey timeout message",
+				zap.Int64("journey_id", msg.JourneyID),
+				zap.Error(err),
+			)
+		}
+	}
+	if err != nil {
+		s.logger.Error("Failed to advance journey occurrences", zap.Error(err))
+		return err
+	}
+
+	s.logger.Info("Journey recurrence completed",
+		zap.Int("created_count", created),
+		zap.Int("reminded_count", len(reminders)),
+		zap.Int("started_count", len(timeoutMsgs)),
+		zap.Duration("duration", time.Since(startTime)),
+	)
+	return nil
+}
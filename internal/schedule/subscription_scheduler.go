@@ -0,0 +1,74 @@
+package schedule
+
+// 订阅调度器：定期扫描到达计费周年日的订阅，发放套餐额度
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"AreYouOK/internal/service"
+	"AreYouOK/pkg/logger"
+)
+
+var (
+	subscriptionSchedulerOnce sync.Once
+	subscriptionSchedulerInst *SubscriptionScheduler
+)
+
+// 单次扫描最多处理的订阅数量，剩余的留给下一轮
+const subscriptionGrantBatchSize = 500
+
+// SubscriptionScheduler 订阅调度器
+type SubscriptionScheduler struct {
+	logger             *zap.Logger
+	grantJobRunning    bool
+	grantJobMu         sync.Mutex
+	lastGrantCheckTime time.Time
+}
+
+// GetSubscriptionScheduler 获取订阅调度器单例
+func GetSubscriptionScheduler() *SubscriptionScheduler {
+	subscriptionSchedulerOnce.Do(func() {
+		subscriptionSchedulerInst = &SubscriptionScheduler{
+			logger: logger.Logger,
+		}
+	})
+	return subscriptionSchedulerInst
+}
+
+// GrantDueSubscriptionQuotas 为到达周年日的订阅发放额度（定时任务调用）
+func (s *SubscriptionScheduler) GrantDueSubscriptionQuotas(ctx context.Context) error {
+	s.grantJobMu.Lock()
+	if s.grantJobRunning {
+		s.grantJobMu.Unlock()
+		s.logger.Info("Subscription grant job already running, skipping")
+		return nil
+	}
+	s.grantJobRunning = true
+	s.grantJobMu.Unlock()
+
+	defer func() {
+		s.grantJobMu.Lock()
+		s.grantJobRunning = false
+		s.grantJobMu.Unlock()
+	}()
+
+	startTime := time.Now()
+	s.lastGrantCheckTime = startTime
+
+	granted, err := service.Subscription().GrantDueQuotas(ctx, startTime, subscriptionGrantBatchSize)
+	if err != nil {
+		s.logger.Error("Failed to grant subscription quotas", zap.Error(err))
+		return err
+	}
+
+	s.logger.Info("Subscription grant check completed",
+		zap.Int("granted_count", granted),
+		zap.Duration("duration", time.Since(startTime)),
+	)
+
+	return nil
+}
@@ -0,0 +1,581 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"AreYouOK/internal/cache"
+	"AreYouOK/internal/model"
+	"AreYouOK/internal/model/dto"
+	"AreYouOK/internal/repository/query"
+	pkgerrors "AreYouOK/pkg/errors"
+	"AreYouOK/pkg/logger"
+	"AreYouOK/storage/database"
+	"AreYouOK/utils"
+)
+
+var (
+	accountMergeService *AccountMergeService
+	accountMergeOnce    sync.Once
+)
+
+func AccountMerge() *AccountMergeService {
+	accountMergeOnce.Do(func() {
+		accountMergeService = &AccountMergeService{}
+	})
+	return accountMergeService
+}
+
+// AccountMergeService 合并同一个人的两个账号
+// 典型场景：分别通过支付宝、微信注册了两个账号，之后在一端登录时因手机号已属于另一个账号返回 PHONE_ALREADY_REGISTERED。
+// 当前登录账号为保留账号（target），被合并账号（source）通过其登录身份的授权码证明归属，并验证任一账号的手机号
+type AccountMergeService struct{}
+
+// Merge 把 source 的紧急联系人、行程、打卡记录、个人钱包余额、订阅与登录身份转移到当前账号，随后软删除 source
+// source 有进行中的行程、待发送的通知或加入了共享钱包时拒绝合并，需先处理完
+func (s *AccountMergeService) Merge(
+	ctx context.Context,
+	userID string,
+	sessionID string,
+	ip string,
+	req dto.MergeAccountRequest,
+) (*dto.AccountMergeData, error) {
+	target, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	claim, err := resolveIdentityClaim(ctx, req.Provider, req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	db := database.DB().WithContext(ctx)
+	q := query.Use(db)
+
+	source, err := findUserByIdentity(q, claim)
+	if err != nil {
+		return nil, err
+	}
+	if source == nil {
+		return nil, pkgerrors.IdentityNotFound
+	}
+	if source.ID == target.ID {
+		return nil, pkgerrors.AccountMergeSelf
+	}
+
+	// 手机号须属于两个账号之一，验证码证明当前操作者持有该号码
+	if !ownsPhone(target, req.Phone) && !ownsPhone(source, req.Phone) {
+		return nil, pkgerrors.AccountMergePhoneInvalid
+	}
+	if err := Verification().VerifyCaptcha(ctx, req.Phone, req.VerifyCode); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkMergeable(q, source, target); err != nil {
+		return nil, err
+	}
+
+	moveSubscription, err := s.shouldMoveSubscription(q, source.ID, target.ID)
+	if err != nil {
+		return nil, err
+	}
+	planOwner := target.ID
+	if moveSubscription {
+		planOwner = source.ID
+	}
+	plan, err := Subscription().PlanForUser(ctx, planOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	var keepSessionID int64
+	if sessionID != "" {
+		keepSessionID, _ = strconv.ParseInt(sessionID, 10, 64)
+	}
+
+	now := time.Now()
+	result := &dto.AccountMergeData{
+		MergedUserID: strconv.FormatInt(source.PublicID, 10),
+	}
+	summary := model.JSONB{}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+
+		// 锁定两个账号后再读取联系人，避免与并发的联系人修改冲突
+		if err := lockContactOwner(txQ, target.ID); err != nil {
+			return err
+		}
+		if err := lockContactOwner(txQ, source.ID); err != nil {
+			return err
+		}
+		targetContacts, err := listContacts(txQ, target.ID)
+		if err != nil {
+			return err
+		}
+		sourceContacts, err := listContacts(txQ, source.ID)
+		if err != nil {
+			return err
+		}
+
+		// 追加的联系人转移到 target 名下，其余的随 source 软删除保留为历史
+		moved := mergeContacts(targetContacts, sourceContacts, plan.MaxContacts)
+		for _, contact := range moved {
+			if _, err := txQ.Contact.
+				Where(txQ.Contact.ID.Eq(contact.ID)).
+				Updates(map[string]interface{}{
+					"user_id":    target.ID,
+					"priority":   contact.Priority,
+					"version":    gorm.Expr("version + ?", 1),
+					"updated_at": now,
+				}); err != nil {
+				return fmt.Errorf("failed to move contact: %w", err)
+			}
+		}
+		if _, err := txQ.Contact.
+			Where(txQ.Contact.UserID.Eq(source.ID)).
+			Delete(); err != nil {
+			return fmt.Errorf("failed to delete merged contacts: %w", err)
+		}
+		result.Contacts = len(moved)
+		summary["contacts"] = len(moved)
+
+		// 合并后有已确认的联系人时激活账号，与联系人确认时的规则一致
+		confirmedCount := 0
+		for _, contact := range append(targetContacts, moved...) {
+			if contact.Status == model.ContactStatusConfirmed {
+				confirmedCount++
+			}
+		}
+
+		quota, err := s.moveQuota(tx, txQ, source.ID, target.ID)
+		if err != nil {
+			return err
+		}
+		result.Quota = quota
+		summary["quota"] = quota
+
+		journeys, err := txQ.Journey.
+			Where(txQ.Journey.UserID.Eq(source.ID)).
+			Update(txQ.Journey.UserID, target.ID)
+		if err != nil {
+			return fmt.Errorf("failed to move journeys: %w", err)
+		}
+		result.Journeys = int(journeys.RowsAffected)
+		summary["journeys"] = journeys.RowsAffected
+
+		if _, err := txQ.JourneyTemplate.
+			Where(txQ.JourneyTemplate.UserID.Eq(source.ID)).
+			Update(txQ.JourneyTemplate.UserID, target.ID); err != nil {
+			return fmt.Errorf("failed to move journey templates: %w", err)
+		}
+		if _, err := txQ.JourneyOccurrence.
+			Where(txQ.JourneyOccurrence.UserID.Eq(source.ID)).
+			Update(txQ.JourneyOccurrence.UserID, target.ID); err != nil {
+			return fmt.Errorf("failed to move journey occurrences: %w", err)
+		}
+
+		// 同一天两个账号都有打卡记录时保留当前账号的记录，source 的记录随 source 保留
+		targetDates := txQ.DailyCheckIn.Unscoped().
+			Select(txQ.DailyCheckIn.CheckInDate).
+			Where(txQ.DailyCheckIn.UserID.Eq(target.ID))
+		checkIns, err := txQ.DailyCheckIn.
+			Where(txQ.DailyCheckIn.UserID.Eq(source.ID)).
+			Where(txQ.DailyCheckIn.Columns(txQ.DailyCheckIn.CheckInDate).NotIn(targetDates)).
+			Update(txQ.DailyCheckIn.UserID, target.ID)
+		if err != nil {
+			return fmt.Errorf("failed to move check-ins: %w", err)
+		}
+		result.CheckIns = int(checkIns.RowsAffected)
+		summary["check_ins"] = checkIns.RowsAffected
+
+		if moveSubscription {
+			if _, err := txQ.Subscription.Unscoped().
+				Where(txQ.Subscription.UserID.Eq(target.ID)).
+				Delete(); err != nil {
+				return fmt.Errorf("failed to delete subscription: %w", err)
+			}
+			if _, err := txQ.Subscription.
+				Where(txQ.Subscription.UserID.Eq(source.ID)).
+				Update(txQ.Subscription.UserID, target.ID); err != nil {
+				return fmt.Errorf("failed to move subscription: %w", err)
+			}
+		}
+		summary["subscription"] = moveSubscription
+
+		identities, err := txQ.UserIdentity.
+			Where(txQ.UserIdentity.UserID.Eq(source.ID)).
+			Update(txQ.UserIdentity.UserID, target.ID)
+		if err != nil {
+			return fmt.Errorf("failed to move user identities: %w", err)
+		}
+		summary["identities"] = identities.RowsAffected
+
+		// source 先释放手机号与支付宝 open_id，避免写入 target 时撞唯一键
+		if _, err := txQ.User.
+			Where(txQ.User.ID.Eq(source.ID)).
+			Updates(map[string]interface{}{
+				"phone_hash":     nil,
+				"phone_cipher":   nil,
+				"alipay_open_id": "",
+				"updated_at":     now,
+				"deleted_at":     now,
+			}); err != nil {
+			return fmt.Errorf("failed to retire merged user: %w", err)
+		}
+
+		updates := map[string]interface{}{
+			"updated_at": now,
+		}
+		if target.AlipayOpenID == "" && source.AlipayOpenID != "" {
+			updates["alipay_open_id"] = source.AlipayOpenID
+		}
+		if (target.PhoneHash == nil || *target.PhoneHash == "") && source.PhoneHash != nil {
+			updates["phone_hash"] = *source.PhoneHash
+			updates["phone_hash_version"] = source.PhoneHashVersion
+			updates["phone_cipher"] = source.PhoneCipher
+		}
+		if confirmedCount > 0 && (target.Status == model.UserStatusContact || target.Status == model.UserStatusOnboarding) {
+			updates["status"] = string(model.UserStatusActive)
+		}
+		if _, err := txQ.User.Where(txQ.User.ID.Eq(target.ID)).Updates(updates); err != nil {
+			return fmt.Errorf("failed to update user: %w", err)
+		}
+
+		merge := &model.AccountMerge{
+			SourceUserID:   source.ID,
+			SourcePublicID: source.PublicID,
+			TargetUserID:   target.ID,
+			TargetPublicID: target.PublicID,
+			Provider:       claim.Provider,
+			SessionID:      keepSessionID,
+			IP:             ip,
+			Summary:        summary,
+			MergedAt:       now,
+		}
+		if err := txQ.AccountMerge.Create(merge); err != nil {
+			return fmt.Errorf("failed to create account merge: %w", err)
+		}
+
+		mergeID := strconv.FormatInt(merge.ID, 10)
+		if err := recordAudit(ctx, txQ, auditEntry{
+			UserID:     target.ID,
+			SessionID:  keepSessionID,
+			Action:     model.AuditActionAccountMerge,
+			TargetType: "account_merge",
+			TargetID:   mergeID,
+			IP:         ip,
+			Diff:       summary,
+		}); err != nil {
+			return err
+		}
+		return recordAudit(ctx, txQ, auditEntry{
+			UserID:     source.ID,
+			Action:     model.AuditActionAccountMergedTo,
+			TargetType: "account_merge",
+			TargetID:   mergeID,
+			IP:         ip,
+			Diff:       model.JSONB{"provider": string(claim.Provider)},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.retireSource(ctx, source, now)
+
+	identities, err := listIdentities(q, target.ID)
+	if err != nil {
+		return nil, err
+	}
+	result.Identities = make([]string, 0, len(identities))
+	for _, identity := range identities {
+		result.Identities = append(result.Identities, identity.Provider)
+	}
+
+	logger.Logger.Info("Accounts merged",
+		zap.Int64("source_user_id", source.ID),
+		zap.Int64("target_user_id", target.ID),
+		zap.String("provider", string(claim.Provider)),
+		zap.Int("contacts", result.Contacts),
+		zap.Int("journeys", result.Journeys),
+		zap.Int("check_ins", result.CheckIns),
+		zap.Int("quota", result.Quota),
+	)
+
+	return result, nil
+}
+
+// checkMergeable 检查两个账号能否合并
+// 每个平台只能保留一个身份，两个账号绑定了同一平台的不同身份时无法合并；
+// source 的进行中行程、待发送通知与冻结额度依赖 source 的用户 ID，需等处理完再合并
+func (s *AccountMergeService) checkMergeable(q *query.Query, source, target *model.User) error {
+	identities, err := q.UserIdentity.
+		Where(q.UserIdentity.UserID.In(source.ID, target.ID)).
+		Find()
+	if err != nil {
+		return fmt.Errorf("failed to query user identities: %w", err)
+	}
+	providers := make(map[model.IdentityProvider]int64, len(identities))
+	for _, identity := range identities {
+		if owner, ok := providers[identity.Provider]; ok && owner != identity.UserID {
+			return pkgerrors.AccountMergeConflict
+		}
+		providers[identity.Provider] = identity.UserID
+	}
+
+	ongoing, err := q.Journey.
+		Where(q.Journey.UserID.Eq(source.ID)).
+		Where(q.Journey.Status.Eq(string(model.JourneyStatusOngoing))).
+		Count()
+	if err != nil {
+		return fmt.Errorf("failed to count journeys: %w", err)
+	}
+	if ongoing > 0 {
+		return pkgerrors.AccountMergeBlocked
+	}
+
+	pending, err := q.NotificationTask.
+		Where(q.NotificationTask.UserID.Eq(source.ID)).
+		Where(q.NotificationTask.Status.Eq(string(model.NotificationTaskStatusPending))).
+		Count()
+	if err != nil {
+		return fmt.Errorf("failed to count notification tasks: %w", err)
+	}
+	if pending > 0 {
+		return pkgerrors.AccountMergeBlocked
+	}
+
+	members, err := q.WalletGroupMember.Where(q.WalletGroupMember.UserID.Eq(source.ID)).Count()
+	if err != nil {
+		return fmt.Errorf("failed to count wallet group members: %w", err)
+	}
+	if members > 0 {
+		return pkgerrors.AccountMergeBlocked
+	}
+
+	frozen, err := q.QuotaWallet.
+		Where(q.QuotaWallet.UserID.Eq(source.ID)).
+		Where(q.QuotaWallet.GroupID.IsNull()).
+		Where(q.QuotaWallet.FrozenAmount.Gt(0)).
+		Count()
+	if err != nil {
+		return fmt.Errorf("failed to count frozen wallets: %w", err)
+	}
+	if frozen > 0 {
+		return pkgerrors.AccountMergeBlocked
+	}
+
+	return nil
+}
+
+// shouldMoveSubscription source 有生效中的付费订阅时转移到 target；两个账号都有付费订阅时拒绝合并
+func (s *AccountMergeService) shouldMoveSubscription(q *query.Query, sourceID, targetID int64) (bool, error) {
+	subs, err := q.Subscription.
+		Where(q.Subscription.UserID.In(sourceID, targetID)).
+		Where(q.Subscription.Status.Eq(string(model.SubscriptionStatusActive))).
+		Where(q.Subscription.Plan.Neq(string(model.PlanFree))).
+		Find()
+	if err != nil {
+		return false, fmt.Errorf("failed to query subscriptions: %w", err)
+	}
+
+	sourcePaid, targetPaid := false, false
+	for _, sub := range subs {
+		if sub.UserID == sourceID {
+			sourcePaid = true
+		} else {
+			targetPaid = true
+		}
+	}
+	if sourcePaid && targetPaid {
+		return false, pkgerrors.AccountMergeConflict
+	}
+	return sourcePaid, nil
+}
+
+// moveQuota 把 source 个人钱包的可用余额转入 target 个人钱包，双方各记一条流水
+// 返回转入的额度
+func (s *AccountMergeService) moveQuota(tx *gorm.DB, txQ *query.Query, sourceID, targetID int64) (int, error) {
+	var from model.QuotaWallet
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(personalWalletCond, sourceID, model.QuotaChannelSMS).
+		First(&from).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to query wallet: %w", err)
+	}
+	amount := from.AvailableAmount
+	if amount <= 0 {
+		return 0, nil
+	}
+
+	var to model.QuotaWallet
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(personalWalletCond, targetID, model.QuotaChannelSMS).
+		First(&to).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, fmt.Errorf("failed to query wallet: %w", err)
+		}
+		to = model.QuotaWallet{UserID: targetID, Channel: model.QuotaChannelSMS}
+		if err := txQ.QuotaWallet.Create(&to); err != nil {
+			return 0, fmt.Errorf("failed to create quota wallet: %w", err)
+		}
+	}
+
+	now := time.Now()
+	if err := tx.Model(&from).Updates(map[string]interface{}{
+		"available_amount": gorm.Expr("available_amount - ?", amount),
+		"updated_at":       now,
+	}).Error; err != nil {
+		return 0, fmt.Errorf("failed to deduct merged wallet: %w", err)
+	}
+	if err := tx.Model(&to).Updates(map[string]interface{}{
+		"available_amount": gorm.Expr("available_amount + ?", amount),
+		"total_granted":    gorm.Expr("total_granted + ?", amount),
+		"updated_at":       now,
+	}).Error; err != nil {
+		return 0, fmt.Errorf("failed to credit wallet: %w", err)
+	}
+
+	transactions := []*model.QuotaTransaction{
+		{
+			UserID:          sourceID,
+			WalletID:        from.ID,
+			Channel:         model.QuotaChannelSMS,
+			TransactionType: model.TransactionTypeDeduct,
+			Reason:          model.QuotaReasonMergeOut,
+			Amount:          amount,
+			BalanceAfter:    0,
+		},
+		{
+			UserID:          targetID,
+			WalletID:        to.ID,
+			Channel:         model.QuotaChannelSMS,
+			TransactionType: model.TransactionTypeGrant,
+			Reason:          model.QuotaReasonGrantMerge,
+			Amount:          amount,
+			BalanceAfter:    to.AvailableAmount + amount,
+		},
+	}
+	if err := txQ.QuotaTransaction.Create(transactions...); err != nil {
+		return 0, fmt.Errorf("failed to create merge transactions: %w", err)
+	}
+
+	return amount, nil
+}
+
+// retireSource 合并后让 source 的登录与打卡提醒失效，失败只记录日志
+func (s *AccountMergeService) retireSource(ctx context.Context, source *model.User, now time.Time) {
+	if err := cache.SetUserSettings(ctx, source.PublicID, &cache.UserSettingsCache{
+		DailyCheckInEnabled:    false,
+		DailyCheckInRemindAt:   source.DailyCheckInRemindAt,
+		DailyCheckInDeadline:   source.DailyCheckInDeadline,
+		DailyCheckInGraceUntil: source.DailyCheckInGraceUntil,
+		UpdatedAt:              now.Unix(),
+	}); err != nil {
+		logger.Logger.Warn("Failed to update user settings cache after merge",
+			zap.Int64("user_id", source.ID),
+			zap.Error(err),
+		)
+	}
+
+	if err := Session().RevokeAll(ctx, source.ID, model.SessionRevokeReasonMerged); err != nil {
+		logger.Logger.Warn("Failed to revoke sessions after merge",
+			zap.Int64("user_id", source.ID),
+			zap.Error(err),
+		)
+	}
+	if err := cache.DeleteRefreshToken(ctx, strconv.FormatInt(source.PublicID, 10)); err != nil {
+		logger.Logger.Warn("Failed to delete legacy refresh token after merge",
+			zap.Int64("user_id", source.ID),
+			zap.Error(err),
+		)
+	}
+}
+
+func (s *AccountMergeService) getUser(ctx context.Context, userID string) (*model.User, error) {
+	var userIDInt int64
+	if _, err := fmt.Sscanf(userID, "%d", &userIDInt); err != nil {
+		return nil, pkgerrors.InvalidUserID
+	}
+
+	q := query.Use(database.DB().WithContext(ctx))
+	user, err := q.User.GetByPublicID(userIDInt)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgerrors.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to query user: %w", err)
+	}
+	return user, nil
+}
+
+// ownsPhone 手机号是否为该账号绑定的号码
+func ownsPhone(user *model.User, phone string) bool {
+	return user.PhoneHash != nil && utils.MatchPhoneHash(phone, *user.PhoneHash)
+}
+
+// mergeContacts 挑出要追加到 target 之后的 source 联系人，号码重复的跳过，超出套餐上限的丢弃
+// 返回的联系人 Priority 已改为 target 中空闲的优先级
+func mergeContacts(target, source []*model.Contact, maxContacts int) []*model.Contact {
+	merged := make([]*model.Contact, 0, len(target)+len(source))
+	merged = append(merged, target...)
+
+	used := make(map[int]bool, len(target))
+	for _, contact := range target {
+		used[contact.Priority] = true
+	}
+
+	moved := make([]*model.Contact, 0, len(source))
+	for _, contact := range source {
+		if len(merged) >= maxContacts {
+			break
+		}
+		if containsContact(merged, contact) {
+			continue
+		}
+
+		priority := 0
+		for p := 1; p <= maxContacts; p++ {
+			if !used[p] {
+				priority = p
+				break
+			}
+		}
+		if priority == 0 {
+			break
+		}
+
+		used[priority] = true
+		contact.Priority = priority
+		merged = append(merged, contact)
+		moved = append(moved, contact)
+	}
+
+	return moved
+}
+
+// containsContact 列表中是否已有相同号码的联系人，哈希版本不同时解密后比较
+func containsContact(contacts []*model.Contact, contact *model.Contact) bool {
+	phone, err := decryptContactPhone(contact)
+	for _, existing := range contacts {
+		if existing.PhoneHash == contact.PhoneHash {
+			return true
+		}
+		if err == nil && utils.MatchPhoneHash(phone, existing.PhoneHash) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"AreYouOK/internal/model"
+	"AreYouOK/internal/model/dto"
+	"AreYouOK/internal/repository/query"
+	pkgerrors "AreYouOK/pkg/errors"
+	"AreYouOK/pkg/reqmeta"
+	"AreYouOK/storage/database"
+)
+
+var (
+	auditService *AuditService
+	auditOnce    sync.Once
+)
+
+func Audit() *AuditService {
+	auditOnce.Do(func() {
+		auditService = &AuditService{}
+	})
+	return auditService
+}
+
+// AuditService 查询账号敏感操作的审计事件
+// 事件由各业务在自己的事务中通过 recordAudit 写入
+type AuditService struct{}
+
+// auditEntry 一条待写入的审计事件，IP、User-Agent 与 trace ID 默认从 context 读取
+type auditEntry struct {
+	Action     string
+	TargetType string
+	TargetID   string
+	IP         string // 为空时使用请求来源 IP
+	Device     string // 为空时使用请求的 User-Agent
+	Diff       model.JSONB
+	UserID     int64
+	SessionID  int64
+	System     bool  // 后台流程触发，操作者记为 system
+	ContactID  int64 // 联系人通过确认链接操作，操作者记为 contact
+}
+
+// sensitiveAuditFields 只记录是否变化、不记录取值的字段
+var sensitiveAuditFields = map[string]bool{
+	"phone":              true,
+	"phone_hash":         true,
+	"phone_cipher":       true,
+	"alipay_open_id":     true,
+	"emergency_contacts": true,
+	"home_latitude":      true,
+	"home_longitude":     true,
+}
+
+// recordAudit 在调用方的事务中写入审计事件，写入失败时整个变更回滚
+func recordAudit(ctx context.Context, q *query.Query, entry auditEntry) error {
+	meta := reqmeta.From(ctx)
+
+	event := &model.AuditEvent{
+		UserID:     entry.UserID,
+		ActorType:  model.AuditActorUser,
+		ActorID:    entry.UserID,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		SessionID:  entry.SessionID,
+		IP:         firstNonEmpty(entry.IP, meta.IP),
+		Device:     truncateRunes(firstNonEmpty(entry.Device, meta.UserAgent), 255),
+		TraceID:    reqmeta.TraceID(ctx),
+		Diff:       entry.Diff,
+		OccurredAt: time.Now(),
+	}
+	if entry.System {
+		event.ActorType = model.AuditActorSystem
+		event.ActorID = 0
+	}
+	if entry.ContactID != 0 {
+		event.ActorType = model.AuditActorContact
+		event.ActorID = entry.ContactID
+	}
+	if event.Diff == nil {
+		event.Diff = model.JSONB{}
+	}
+
+	if err := q.AuditEvent.Create(event); err != nil {
+		return fmt.Errorf("failed to record audit event: %w", err)
+	}
+	return nil
+}
+
+// auditDiff 生成脱敏后的字段差异 {"field": {"from": x, "to": y}}，取值未变的字段不记录
+// 敏感字段只记录 {"changed": true}
+func auditDiff(before, after map[string]interface{}) model.JSONB {
+	diff := model.JSONB{}
+	for key, to := range after {
+		from := auditValue(before[key])
+		to = auditValue(to)
+		if reflect.DeepEqual(from, to) {
+			continue
+		}
+		if sensitiveAuditFields[key] {
+			diff[key] = model.JSONB{"changed": true}
+			continue
+		}
+		diff[key] = model.JSONB{"from": from, "to": to}
+	}
+	return diff
+}
+
+// auditValue 解引用指针，使模型中的可空字段（*float64）与更新中的取值（float64 或 nil）可以比较
+func auditValue(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr {
+		return v
+	}
+	if rv.IsNil() {
+		return nil
+	}
+	return rv.Elem().Interface()
+}
+
+// auditContacts 联系人列表的脱敏摘要：只保留优先级与关系
+func auditContacts(contacts []*model.Contact) []model.JSONB {
+	summary := make([]model.JSONB, 0, len(contacts))
+	for _, contact := range contacts {
+		summary = append(summary, model.JSONB{
+			"priority":     contact.Priority,
+			"relationship": contact.Relationship,
+		})
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		return summary[i]["priority"].(int) < summary[j]["priority"].(int)
+	})
+	return summary
+}
+
+// auditDevice 登录时客户端上报的设备信息
+func auditDevice(device dto.DeviceInfo) string {
+	parts := make([]string, 0, 3)
+	for _, part := range []string{device.Platform, device.Model, device.AppVersion} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, " / ")
+}
+
+// ListMine 用户查看本人账号的活动记录，按时间倒序
+func (s *AuditService) ListMine(
+	ctx context.Context,
+	userID string,
+	cursorID int64,
+	limit int,
+) ([]dto.ActivityItem, int64, error) {
+	var userIDInt int64
+	if _, err := fmt.Sscanf(userID, "%d", &userIDInt); err != nil {
+		return nil, 0, pkgerrors.InvalidUserID
+	}
+
+	q := query.Use(database.DB().WithContext(ctx))
+	user, err := q.User.GetByPublicID(userIDInt)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, 0, pkgerrors.ErrUserNotFound
+		}
+		return nil, 0, fmt.Errorf("failed to query user: %w", err)
+	}
+
+	do := q.AuditEvent.Where(q.AuditEvent.UserID.Eq(user.ID))
+	if cursorID > 0 {
+		do = do.Where(q.AuditEvent.ID.Lte(cursorID))
+	}
+	events, err := do.Order(q.AuditEvent.ID.Desc()).Limit(limit + 1).Find()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query audit events: %w", err)
+	}
+
+	var nextCursor int64
+	if len(events) > limit {
+		nextCursor = events[limit].ID
+		events = events[:limit]
+	}
+
+	items := make([]dto.ActivityItem, 0, len(events))
+	for _, event := range events {
+		items = append(items, activityItem(event))
+	}
+	return items, nextCursor, nil
+}
+
+// Query 运维按用户、事件类型、trace ID 与时间范围查询审计事件，按时间倒序
+func (s *AuditService) Query(ctx context.Context, req dto.AdminAuditQuery, cursorID int64) ([]dto.AdminAuditEvent, int64, error) {
+	q := query.Use(database.DB().WithContext(ctx))
+	do := q.AuditEvent.WithContext(ctx)
+
+	if req.UserID != "" {
+		publicID, err := strconv.ParseInt(req.UserID, 10, 64)
+		if err != nil {
+			return nil, 0, pkgerrors.InvalidUserID
+		}
+		// 已注销的用户同样可以查询
+		user, err := q.User.Unscoped().Where(q.User.PublicID.Eq(publicID)).First()
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, 0, pkgerrors.ErrUserNotFound
+			}
+			return nil, 0, fmt.Errorf("failed to query user: %w", err)
+		}
+		do = do.Where(q.AuditEvent.UserID.Eq(user.ID))
+	}
+	if req.Action != "" {
+		do = do.Where(q.AuditEvent.Action.Eq(req.Action))
+	}
+	if req.TraceID != "" {
+		do = do.Where(q.AuditEvent.TraceID.Eq(req.TraceID))
+	}
+	if req.Since != "" {
+		since, err := time.Parse(time.RFC3339, req.Since)
+		if err != nil {
+			return nil, 0, pkgerrors.AuditQueryInvalid
+		}
+		do = do.Where(q.AuditEvent.OccurredAt.Gte(since))
+	}
+	if req.Until != "" {
+		until, err := time.Parse(time.RFC3339, req.Until)
+		if err != nil {
+			return nil, 0, pkgerrors.AuditQueryInvalid
+		}
+		do = do.Where(q.AuditEvent.OccurredAt.Lt(until))
+	}
+	if cursorID > 0 {
+		do = do.Where(q.AuditEvent.ID.Lte(cursorID))
+	}
+
+	events, err := do.Order(q.AuditEvent.ID.Desc()).Limit(req.Limit + 1).Find()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query audit events: %w", err)
+	}
+
+	var nextCursor int64
+	if len(events) > req.Limit {
+		nextCursor = events[req.Limit].ID
+		events = events[:req.Limit]
+	}
+
+	// 返回 public_id，与其他接口保持一致
+	userIDs := make([]int64, 0, len(events))
+	for _, event := range events {
+		userIDs = append(userIDs, event.UserID)
+	}
+	publicIDs := make(map[int64]int64, len(userIDs))
+	if len(userIDs) > 0 {
+		users, err := q.User.Unscoped().
+			Select(q.User.ID, q.User.PublicID).
+			Where(q.User.ID.In(userIDs...)).
+			Find()
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to query users: %w", err)
+		}
+		for _, user := range users {
+			publicIDs[user.ID] = user.PublicID
+		}
+	}
+
+	items := make([]dto.AdminAuditEvent, 0, len(events))
+	for _, event := range events {
+		item := dto.AdminAuditEvent{
+			ActivityItem: activityItem(event),
+			UserID:       strconv.FormatInt(publicIDs[event.UserID], 10),
+			ActorType:    string(event.ActorType),
+			TraceID:      event.TraceID,
+		}
+		if event.SessionID != 0 {
+			item.SessionID = strconv.FormatInt(event.SessionID, 10)
+		}
+		items = append(items, item)
+	}
+	return items, nextCursor, nil
+}
+
+func activityItem(event *model.AuditEvent) dto.ActivityItem {
+	item := dto.ActivityItem{
+		ID:         strconv.FormatInt(event.ID, 10),
+		Action:     event.Action,
+		TargetType: event.TargetType,
+		TargetID:   event.TargetID,
+		IP:         event.IP,
+		Device:     event.Device,
+		OccurredAt: event.OccurredAt,
+	}
+	if len(event.Diff) > 0 {
+		item.Diff = event.Diff
+	}
+	return item
+}
+
+// truncateRunes 按字符截断，避免超出列宽
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
@@ -13,14 +13,13 @@ import (
 	"gorm.io/gorm"
 
 	"AreYouOK/config"
-	"AreYouOK/internal/cache"
 	"AreYouOK/internal/model"
 	"AreYouOK/internal/model/dto"
 	"AreYouOK/internal/repository/query"
 	pkgerrors "AreYouOK/pkg/errors"
 	"AreYouOK/pkg/logger"
 	"AreYouOK/pkg/snowflake"
-	"AreYouOK/pkg/token"
+	"AreYouOK/pkg/wechat"
 	"AreYouOK/storage/database"
 	"AreYouOK/utils"
 )
@@ -46,6 +45,7 @@ func (s *AuthService) ExchangeAlipayAuthCode(
 	encryptedData string,
 	device dto.DeviceInfo,
 	alipayOpenID string,
+	ip string,
 ) (*dto.AuthExchangeResponse, error) {
 
 	if alipayOpenID == "" {
@@ -61,35 +61,115 @@ func (s *AuthService) ExchangeAlipayAuthCode(
 		return nil, pkgerrors.InvalidPhone
 	}
 
+	identity := model.IdentityClaim{
+		Provider: model.IdentityProviderAlipay,
+		Subject:  alipayOpenID,
+	}
+	return s.loginWithMiniappPhone(ctx, identity, phone, device, ip)
+}
+
+// ExchangeWechatAuthCode 微信小程序登录：code2session 换取 openid 与 session_key，再解密 getPhoneNumber 返回的手机号
+// 前端需先调用 wx.login 再调用 getPhoneNumber，保证 encrypted_data 由同一个 session_key 加密
+func (s *AuthService) ExchangeWechatAuthCode(
+	ctx context.Context,
+	req dto.WechatExchangeRequest,
+	ip string,
+) (*dto.AuthExchangeResponse, error) {
+	session, err := wechat.Code2Session(ctx, req.Code)
+	if err != nil {
+		logger.Logger.Warn("WeChat code2session failed", zap.Error(err))
+		return nil, pkgerrors.WechatLoginFailed
+	}
+
+	phoneInfo, err := wechat.DecryptPhone(session.SessionKey, req.EncryptedData, req.IV, config.Cfg.WechatAppID)
+	if err != nil {
+		logger.Logger.Warn("Failed to decrypt WeChat phone",
+			zap.String("openid", session.OpenID),
+			zap.Error(err),
+		)
+		return nil, pkgerrors.WechatPhoneInvalid
+	}
+
+	// 目前只支持大陆手机号
+	if phoneInfo.CountryCode != "" && phoneInfo.CountryCode != "86" {
+		return nil, pkgerrors.InvalidPhone
+	}
+	phone := phoneInfo.PurePhoneNumber
+	if !utils.ValidatePhone(phone) {
+		return nil, pkgerrors.InvalidPhone
+	}
+
+	identity := model.IdentityClaim{
+		Provider: model.IdentityProviderWechat,
+		Subject:  session.OpenID,
+		UnionID:  session.UnionID,
+	}
+	return s.loginWithMiniappPhone(ctx, identity, phone, req.Device, ip)
+}
+
+// loginWithMiniappPhone 小程序登录的公共流程：按登录身份与平台验证过的手机号匹配账号，没有则注册
+// 身份未绑定但手机号已属于某个账号、且该账号还没有同平台的身份时，把身份关联到该账号；
+// 身份与手机号分别属于两个账号时返回 PhoneAlreadyRegistered，由用户通过账号合并处理（见 AccountMergeService）
+func (s *AuthService) loginWithMiniappPhone(
+	ctx context.Context,
+	identity model.IdentityClaim,
+	phone string,
+	device dto.DeviceInfo,
+	ip string,
+) (*dto.AuthExchangeResponse, error) {
 	phoneHash := utils.HashPhone(phone)
+	phoneHashes := utils.PhoneHashCandidates(phone) // 迁移窗口内同时匹配上一版本哈希
+
+	// 恢复窗口内重新登录：登录到原账号，由前端引导恢复
+	if pendingUser, erasure, err := Erasure().FindPendingUser(ctx, identity, phoneHashes); err != nil {
+		return nil, err
+	} else if pendingUser != nil {
+		pair, err := Session().CreateSession(ctx, pendingUser, device, ip)
+		if err != nil {
+			return nil, err
+		}
+		return &dto.AuthExchangeResponse{
+			AccessToken:  pair.AccessToken,
+			RefreshToken: pair.RefreshToken,
+			ExpiresIn:    pair.ExpiresIn,
+			User:         pendingErasureSnapshot(pendingUser, erasure),
+		}, nil
+	}
 
 	db := database.DB().WithContext(ctx)
 	q := query.Use(db)
 
-	user, err := q.User.Where(q.User.AlipayOpenID.Eq(alipayOpenID)).Where(q.User.PhoneHash.Eq(phoneHash)).First()
-	isNewUser := false
-
+	user, err := findUserByIdentity(q, identity)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			// 未找到匹配手机号的记录，再按 open_id 查找是否已有未绑定手机号的用户
-			user, err = q.User.Where(q.User.AlipayOpenID.Eq(alipayOpenID)).First()
-			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-				return nil, fmt.Errorf("failed to query user by alipay_open_id: %w", err)
-			}
-		} else {
-			return nil, fmt.Errorf("failed to query user by alipay_open_id and phone: %w", err)
-		}
+		return nil, err
 	}
+	linkIdentity := user == nil
+	isNewUser := false
 
 	// phone_hash 全局唯一，若已被其他用户占用则直接报错，避免撞唯一键
-	if existingByPhone, phoneErr := q.User.GetByPhoneHash(phoneHash); phoneErr == nil && existingByPhone != nil {
-		if user == nil || existingByPhone.ID != user.ID {
+	if existingByPhone, phoneErr := q.User.GetByPhoneHashes(phoneHashes); phoneErr == nil && existingByPhone != nil {
+		if user == nil {
+			linked, err := hasProviderIdentity(q, existingByPhone.ID, identity.Provider)
+			if err != nil {
+				return nil, err
+			}
+			if linked || existingByPhone.DeletedAt.Valid {
+				return nil, pkgerrors.PhoneAlreadyRegistered
+			}
+			// 同一手机号在另一个平台已有账号，关联新的登录身份
+			user = existingByPhone
+		} else if existingByPhone.ID != user.ID {
 			return nil, pkgerrors.PhoneAlreadyRegistered
 		}
 	} else if phoneErr != nil && !errors.Is(phoneErr, gorm.ErrRecordNotFound) {
 		return nil, fmt.Errorf("failed to query user by phone_hash: %w", phoneErr)
 	}
 
+	alipayOpenID := ""
+	if identity.Provider == model.IdentityProviderAlipay {
+		alipayOpenID = identity.Subject
+	}
+
 	if user == nil {
 		userCount, countErr := query.User.Count()
 		if countErr != nil {
@@ -129,6 +209,8 @@ func (s *AuthService) ExchangeAlipayAuthCode(
 			Status:       model.UserStatusContact, // 已绑定手机号，进入填写紧急联系人阶段
 			Timezone:     "Asia/Shanghai",
 			PhoneHash:    &phoneHash,
+
+			PhoneHashVersion: utils.PhoneHashVersion(),
 		}
 
 		user.PhoneCipher = phoneCipherBytes
@@ -140,6 +222,10 @@ func (s *AuthService) ExchangeAlipayAuthCode(
 				return fmt.Errorf("failed to create user: %w", err)
 			}
 
+			if err := bindIdentity(txQ, user.ID, identity); err != nil {
+				return err
+			}
+
 			defaultQuotaCents := config.Cfg.DefaultSMSQuota
 			if defaultQuotaCents <= 0 {
 				defaultQuotaCents = 100
@@ -171,21 +257,15 @@ func (s *AuthService) ExchangeAlipayAuthCode(
 				return fmt.Errorf("failed to grant default SMS quota: %w", err)
 			}
 
-			logger.Logger.Info("User created with default SMS quota in transaction",
-				zap.Int64("public_id", publicID),
-				zap.Int64("user_id", user.ID),
-				zap.Int("quota_cents", defaultQuotaCents),
-			)
-
 			return nil
 		})
-
 		if err != nil {
-			return nil, fmt.Errorf("failed to create user with quota: %w", err)
+			return nil, err
 		}
 
 		isNewUser = true
-		logger.Logger.Info("New user created via alipay phone",
+		logger.Logger.Info("New user created via miniapp phone",
+			zap.String("provider", string(identity.Provider)),
 			zap.Int64("public_id", publicID),
 			zap.String("phone_hash", phoneHash),
 		)
@@ -201,86 +281,100 @@ func (s *AuthService) ExchangeAlipayAuthCode(
 		}
 
 		updates := map[string]interface{}{
-			"phone_cipher": phoneCipherBytes,
-			"phone_hash":   phoneHash,
+			"phone_cipher":       phoneCipherBytes,
+			"phone_hash":         phoneHash,
+			"phone_hash_version": utils.PhoneHashVersion(),
 		}
 
 		if user.Status == model.UserStatusWaitlisted || user.Status == model.UserStatusOnboarding {
 			updates["status"] = string(model.UserStatusContact)
 		}
 
-		if user.AlipayOpenID == "" {
+		if alipayOpenID != "" && user.AlipayOpenID == "" {
 			updates["alipay_open_id"] = alipayOpenID
 		}
 
 		needInit := user.PhoneHash == nil || *user.PhoneHash == ""
 
-		if needInit {
-			err = db.Transaction(func(tx *gorm.DB) error {
-				txQ := query.Use(tx)
+		err = db.Transaction(func(tx *gorm.DB) error {
+			txQ := query.Use(tx)
 
-				if _, updateErr := txQ.User.Where(txQ.User.ID.Eq(user.ID)).Updates(updates); updateErr != nil {
-					return fmt.Errorf("failed to update user: %w", updateErr)
+			if _, updateErr := txQ.User.Where(txQ.User.ID.Eq(user.ID)).Updates(updates); updateErr != nil {
+				return fmt.Errorf("failed to update user: %w", updateErr)
+			}
+
+			if linkIdentity {
+				if err := bindIdentity(txQ, user.ID, identity); err != nil {
+					return err
 				}
+			}
 
-				// 初次绑定手机号时初始化默认钱包
-				if _, walletErr := txQ.QuotaWallet.Where(txQ.QuotaWallet.UserID.Eq(user.ID)).First(); walletErr != nil {
-					if !errors.Is(walletErr, gorm.ErrRecordNotFound) {
-						return fmt.Errorf("failed to query wallet: %w", walletErr)
-					}
+			if !needInit {
+				return nil
+			}
 
-					defaultQuotaCents := config.Cfg.DefaultSMSQuota
-					if defaultQuotaCents <= 0 {
-						defaultQuotaCents = 100
-					}
+			// 初次绑定手机号时初始化默认钱包
+			if _, walletErr := txQ.QuotaWallet.Where(txQ.QuotaWallet.UserID.Eq(user.ID), txQ.QuotaWallet.GroupID.IsNull()).First(); walletErr != nil {
+				if !errors.Is(walletErr, gorm.ErrRecordNotFound) {
+					return fmt.Errorf("failed to query wallet: %w", walletErr)
+				}
 
-					wallet := &model.QuotaWallet{
-						UserID:          user.ID,
-						Channel:         model.QuotaChannelSMS,
-						AvailableAmount: defaultQuotaCents,
-						FrozenAmount:    0,
-						UsedAmount:      0,
-						TotalGranted:    defaultQuotaCents,
-					}
+				defaultQuotaCents := config.Cfg.DefaultSMSQuota
+				if defaultQuotaCents <= 0 {
+					defaultQuotaCents = 100
+				}
 
-					if err := txQ.QuotaWallet.Create(wallet); err != nil {
-						return fmt.Errorf("failed to create quota wallet: %w", err)
-					}
+				wallet := &model.QuotaWallet{
+					UserID:          user.ID,
+					Channel:         model.QuotaChannelSMS,
+					AvailableAmount: defaultQuotaCents,
+					FrozenAmount:    0,
+					UsedAmount:      0,
+					TotalGranted:    defaultQuotaCents,
+				}
 
-					quotaTransaction := &model.QuotaTransaction{
-						UserID:          user.ID,
-						Channel:         model.QuotaChannelSMS,
-						TransactionType: model.TransactionTypeGrant,
-						Reason:          "new_user_bonus",
-						Amount:          defaultQuotaCents,
-						BalanceAfter:    defaultQuotaCents,
-					}
+				if err := txQ.QuotaWallet.Create(wallet); err != nil {
+					return fmt.Errorf("failed to create quota wallet: %w", err)
+				}
 
-					if err := txQ.QuotaTransaction.Create(quotaTransaction); err != nil {
-						return fmt.Errorf("failed to grant default SMS quota: %w", err)
-					}
+				quotaTransaction := &model.QuotaTransaction{
+					UserID:          user.ID,
+					Channel:         model.QuotaChannelSMS,
+					TransactionType: model.TransactionTypeGrant,
+					Reason:          "new_user_bonus",
+					Amount:          defaultQuotaCents,
+					BalanceAfter:    defaultQuotaCents,
 				}
 
-				return nil
-			})
-			if err != nil {
-				return nil, err
+				if err := txQ.QuotaTransaction.Create(quotaTransaction); err != nil {
+					return fmt.Errorf("failed to grant default SMS quota: %w", err)
+				}
 			}
 
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if needInit {
 			isNewUser = true
-			logger.Logger.Info("New user initialized by alipay phone",
+			logger.Logger.Info("New user initialized by miniapp phone",
+				zap.String("provider", string(identity.Provider)),
 				zap.Int64("user_id", user.ID),
 				zap.Int64("public_id", user.PublicID),
-				zap.String("alipay_open_id", user.AlipayOpenID),
 				zap.String("phone_hash", phoneHash),
 			)
-		} else {
-			if _, updateErr := q.User.Where(q.User.ID.Eq(user.ID)).Updates(updates); updateErr != nil {
-				return nil, fmt.Errorf("failed to update user: %w", updateErr)
-			}
+		}
+		if linkIdentity {
+			logger.Logger.Info("Login identity linked to existing account by phone",
+				zap.String("provider", string(identity.Provider)),
+				zap.Int64("user_id", user.ID),
+			)
 		}
 
 		user.PhoneHash = &phoneHash
+		user.PhoneHashVersion = utils.PhoneHashVersion()
 		user.PhoneCipher = phoneCipherBytes
 		if newStatus, ok := updates["status"]; ok {
 			user.Status = model.UserStatus(newStatus.(string))
@@ -291,29 +385,20 @@ func (s *AuthService) ExchangeAlipayAuthCode(
 	}
 
 	userIDStr := fmt.Sprintf("%d", user.PublicID)
-	accessToken, refreshToken, expiresIn, err := token.GenerateTokenPair(userIDStr)
+	pair, err := Session().CreateSession(ctx, user, device, ip)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate token: %w", err)
-	}
-
-	// 存储 refresh token 到 Redis
-	if err := cache.SetRefreshToken(ctx, userIDStr, refreshToken); err != nil {
-		logger.Logger.Warn("Failed to store refresh token in Redis",
-			zap.String("user_id", userIDStr),
-			zap.Error(err),
-		)
-		// 不返回错误，因为 token 已经生成成功
+		return nil, err
 	}
 
-	// 手机号已验证（因为是从支付宝获取的）
+	// 手机号已验证（由小程序平台提供）
 	phoneVerified := true
 
 	nextStep := resolveNextStep(user.Status, phoneVerified)
 
 	return &dto.AuthExchangeResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		ExpiresIn:    expiresIn,
+		AccessToken:  pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		ExpiresIn:    pair.ExpiresIn,
 		User: dto.AuthUserSnapshot{
 			ID:            userIDStr,
 			Nickname:      user.Nickname,
@@ -321,7 +406,7 @@ func (s *AuthService) ExchangeAlipayAuthCode(
 			NextStep:      nextStep,
 			PhoneVerified: phoneVerified,
 			IsNewUser:     isNewUser,
-			AlipayOpenID:  user.AlipayOpenID,
+			AlipayOpenID:  alipayOpenID,
 		},
 	}, nil
 }
@@ -481,6 +566,8 @@ func (s *AuthService) VerifyPhoneCaptchaAndLogin(
 	phone string,
 	code string,
 	alipayOpenID string,
+	device dto.DeviceInfo,
+	ip string,
 ) (*dto.VerifyCaptchaResponse, error) {
 	// 验证验证码
 	verifiService := Verification()
@@ -493,14 +580,36 @@ func (s *AuthService) VerifyPhoneCaptchaAndLogin(
 	}
 
 	phoneHash := utils.HashPhone(phone)
+	phoneHashes := utils.PhoneHashCandidates(phone) // 迁移窗口内同时匹配上一版本哈希
+	identity := model.IdentityClaim{
+		Provider: model.IdentityProviderAlipay,
+		Subject:  alipayOpenID,
+	}
+
+	// 恢复窗口内重新登录：登录到原账号，由前端引导恢复
+	if pendingUser, erasure, err := Erasure().FindPendingUser(ctx, identity, phoneHashes); err != nil {
+		return nil, err
+	} else if pendingUser != nil {
+		pair, err := Session().CreateSession(ctx, pendingUser, device, ip)
+		if err != nil {
+			return nil, err
+		}
+		return &dto.VerifyCaptchaResponse{
+			AccessToken:  pair.AccessToken,
+			RefreshToken: pair.RefreshToken,
+			ExpiresIn:    pair.ExpiresIn,
+			User:         pendingErasureSnapshot(pendingUser, erasure),
+		}, nil
+	}
 
 	db := database.DB().WithContext(ctx)
 	q := query.Use(db)
 
 	// 先确保 phone_hash 未被其他用户占用
-	if existingByPhone, phoneErr := q.User.GetByPhoneHash(phoneHash); phoneErr == nil && existingByPhone != nil {
-		// 如已存在其它用户占用该手机号，则直接报错
-		if existingByPhone.AlipayOpenID != alipayOpenID {
+	existingByPhone, phoneErr := q.User.GetByPhoneHashes(phoneHashes)
+	if phoneErr == nil && existingByPhone != nil {
+		// 如已存在其它支付宝用户占用该手机号，则直接报错；仅通过微信登录的账号会在下面补写支付宝身份
+		if existingByPhone.AlipayOpenID != "" && existingByPhone.AlipayOpenID != alipayOpenID {
 			return nil, pkgerrors.PhoneAlreadyRegistered
 		}
 	}
@@ -510,9 +619,14 @@ func (s *AuthService) VerifyPhoneCaptchaAndLogin(
 	user, err := q.User.Where(q.User.AlipayOpenID.Eq(alipayOpenID)).First()
 	isNewUser := false
 
+	if err == nil && existingByPhone != nil && existingByPhone.ID != user.ID {
+		// 该支付宝账号与手机号分属两个账号
+		return nil, pkgerrors.PhoneAlreadyRegistered
+	}
+
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			user, err = q.User.GetByPhoneHash(phoneHash)
+			user, err = q.User.GetByPhoneHashes(phoneHashes)
 		}
 	}
 
@@ -559,6 +673,8 @@ func (s *AuthService) VerifyPhoneCaptchaAndLogin(
 				Timezone:     "Asia/Shanghai",
 				PhoneHash:    &phoneHash,
 				PhoneCipher:  phoneCipherBytes,
+
+				PhoneHashVersion: utils.PhoneHashVersion(),
 			}
 
 			err = db.Transaction(func(tx *gorm.DB) error {
@@ -568,6 +684,10 @@ func (s *AuthService) VerifyPhoneCaptchaAndLogin(
 					return fmt.Errorf("failed to create user: %w", err)
 				}
 
+				if err := bindIdentity(txQ, user.ID, identity); err != nil {
+					return err
+				}
+
 				defaultQuotaCents := config.Cfg.DefaultSMSQuota
 				if defaultQuotaCents <= 0 {
 					defaultQuotaCents = 100 // 默认 100 cents = 20 次短信
@@ -622,10 +742,18 @@ func (s *AuthService) VerifyPhoneCaptchaAndLogin(
 			return nil, fmt.Errorf("failed to query user: %w", err)
 		}
 	} else {
-		// 老用户：如缺少 open_id 则补写
+		// 老用户：如缺少 open_id 则补写，同时写入支付宝登录身份
 		if user.AlipayOpenID == "" {
-			if _, updateErr := q.User.Where(q.User.ID.Eq(user.ID)).Update(q.User.AlipayOpenID, alipayOpenID); updateErr != nil {
-				return nil, fmt.Errorf("failed to update alipay_open_id: %w", updateErr)
+			err := db.Transaction(func(tx *gorm.DB) error {
+				txQ := query.Use(tx)
+
+				if _, updateErr := txQ.User.Where(txQ.User.ID.Eq(user.ID)).Update(txQ.User.AlipayOpenID, alipayOpenID); updateErr != nil {
+					return fmt.Errorf("failed to update alipay_open_id: %w", updateErr)
+				}
+				return bindIdentity(txQ, user.ID, identity)
+			})
+			if err != nil {
+				return nil, err
 			}
 			user.AlipayOpenID = alipayOpenID
 		}
@@ -644,8 +772,9 @@ func (s *AuthService) VerifyPhoneCaptchaAndLogin(
 		}
 
 		updates := map[string]interface{}{
-			"phone_cipher": phoneCipherBytes,
-			"phone_hash":   phoneHash,
+			"phone_cipher":       phoneCipherBytes,
+			"phone_hash":         phoneHash,
+			"phone_hash_version": utils.PhoneHashVersion(),
 		}
 
 		if user.Status == model.UserStatusWaitlisted || user.Status == model.UserStatusOnboarding {
@@ -660,7 +789,7 @@ func (s *AuthService) VerifyPhoneCaptchaAndLogin(
 					return fmt.Errorf("failed to update user: %w", updateErr)
 				}
 
-				if _, walletErr := txQ.QuotaWallet.Where(txQ.QuotaWallet.UserID.Eq(user.ID)).First(); walletErr != nil {
+				if _, walletErr := txQ.QuotaWallet.Where(txQ.QuotaWallet.UserID.Eq(user.ID), txQ.QuotaWallet.GroupID.IsNull()).First(); walletErr != nil {
 					if !errors.Is(walletErr, gorm.ErrRecordNotFound) {
 						return fmt.Errorf("failed to query wallet: %w", walletErr)
 					}
@@ -717,31 +846,24 @@ func (s *AuthService) VerifyPhoneCaptchaAndLogin(
 		}
 
 		user.PhoneHash = &phoneHash
+		user.PhoneHashVersion = utils.PhoneHashVersion()
 		user.PhoneCipher = phoneCipherBytes
 		if newStatus, ok := updates["status"]; ok {
 			user.Status = model.UserStatus(newStatus.(string))
 		}
 	}
 
-	// 生成 token
+	// 创建会话并生成 token
 	userIDStr := fmt.Sprintf("%d", user.PublicID)
-	accessToken, refreshToken, expiresIn, err := token.GenerateTokenPair(userIDStr)
+	pair, err := Session().CreateSession(ctx, user, device, ip)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate token: %w", err)
-	}
-
-	// 存储 refresh token 到 Redis
-	if err := cache.SetRefreshToken(ctx, userIDStr, refreshToken); err != nil {
-		logger.Logger.Warn("Failed to store refresh token in Redis",
-			zap.String("user_id", userIDStr),
-			zap.Error(err),
-		)
+		return nil, err
 	}
 
 	return &dto.VerifyCaptchaResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		ExpiresIn:    expiresIn,
+		AccessToken:  pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		ExpiresIn:    pair.ExpiresIn,
 		User: dto.AuthUserSnapshot{
 			ID:            userIDStr,
 			Nickname:      user.Nickname,
@@ -754,49 +876,24 @@ func (s *AuthService) VerifyPhoneCaptchaAndLogin(
 	}, nil
 }
 
+// RefreshToken 轮换 refresh token，旧 token 重复使用会撤销整个会话
 func (s *AuthService) RefreshToken(
 	ctx context.Context,
 	refreshToken string,
+	ip string,
 ) (*dto.AuthExchangeResponse, error) {
-	// 将对应的 pkg 层的方法在 service 层中具体实现
-	userIDStr, err := token.ValidateRefreshToken(refreshToken)
-	if err != nil {
-		return nil, pkgerrors.AuthCodeInvalid
-	}
-
-	// 检验是否存在且匹配
-	if !cache.ValidateRefreshTokenExists(ctx, userIDStr, refreshToken) {
-		return nil, pkgerrors.AuthCodeInvalid
-	}
-
-	var userID int64
-	fmt.Sscanf(userIDStr, "%d", &userID)
-	user, err := query.User.Where(query.User.PublicID.Eq(userID)).First()
+	user, pair, err := Session().Rotate(ctx, refreshToken, ip)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, fmt.Errorf("user not found")
-		}
-		return nil, fmt.Errorf("failed to query user: %w", err)
-	}
-
-	accessToken, newRefreshToken, expiresIn, err := token.GenerateTokenPair(userIDStr)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate token: %w", err)
-	}
-
-	if err := cache.SetRefreshToken(ctx, userIDStr, newRefreshToken); err != nil {
-		logger.Logger.Warn("Failed to update refresh token in Redis",
-			zap.String("user_id", userIDStr),
-			zap.Error(err),
-		)
+		return nil, err
 	}
 
+	userIDStr := fmt.Sprintf("%d", user.PublicID)
 	phoneVerified := user.PhoneHash != nil && *user.PhoneHash != ""
 
 	return &dto.AuthExchangeResponse{
-		AccessToken:  accessToken,
-		RefreshToken: newRefreshToken,
-		ExpiresIn:    expiresIn,
+		AccessToken:  pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		ExpiresIn:    pair.ExpiresIn,
 		User: dto.AuthUserSnapshot{
 			ID:            userIDStr,
 			Nickname:      user.Nickname,
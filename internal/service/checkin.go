@@ -13,7 +13,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"sort"
 	"strconv"
 	"sync"
 	"time"
@@ -244,6 +243,18 @@ func (s *CheckInService) ProcessReminderBatch(
 		userMap[user.PublicID] = user
 	}
 
+	// 批量查询用户套餐，用于月度提醒上限
+	internalIDs := make([]int64, 0, len(users))
+	for _, user := range users {
+		internalIDs = append(internalIDs, user.ID)
+	}
+	planMap, err := Subscription().PlansForUsers(ctx, internalIDs)
+	if err != nil {
+		logger.Logger.Warn("Failed to query user plans, falling back to free plan",
+			zap.Error(err),
+		)
+	}
+
 	// 跟踪创建的任务数量
 	var createdCount int
 
@@ -289,8 +300,9 @@ func (s *CheckInService) ProcessReminderBatch(
 				continue // 继续处理其他用户，但不回滚事务
 			}
 
-			// 检查月度提醒限制（每用户每月最多 5 条提醒）
-			allowed, count, err := cache.CheckMonthlyReminderLimit(ctx, user.ID)
+			// 检查月度提醒限制（上限由用户套餐决定）
+			reminderLimit := planMap[user.ID].MonthlyReminderLimit
+			allowed, count, err := cache.CheckMonthlyReminderLimit(ctx, user.ID, reminderLimit)
 			if err != nil {
 				logger.Logger.Warn("Failed to check monthly reminder limit, allowing send",
 					zap.Int64("user_id", publicID),
@@ -301,7 +313,7 @@ func (s *CheckInService) ProcessReminderBatch(
 				logger.Logger.Info("User exceeded monthly check-in reminder limit, skipping",
 					zap.Int64("user_id", publicID),
 					zap.Int("count", count),
-					zap.Int("limit", cache.MonthlyReminderLimit),
+					zap.Int("limit", reminderLimit),
 				)
 				continue // 超过限制，跳过该用户
 			}
@@ -624,11 +636,11 @@ func (s *CheckInService) ProcessTimeoutBatch(
 			}
 		}
 
-		// 批量查询额度钱包，避免 N+1 查询
+		// 批量查询可用额度（个人钱包 + 共享钱包），避免 N+1 查询
 		quotaService := Quota()
-		walletMap := make(map[int64]*model.QuotaWallet)
+		balanceMap := make(map[int64]int)
 		for _, userID := range userIDsForQuota {
-			wallet, err := quotaService.GetWallet(ctx, userID, model.QuotaChannelSMS)
+			balance, err := quotaService.SpendableBalance(ctx, userID, model.QuotaChannelSMS)
 			if err != nil {
 				logger.Logger.Error("Failed to query SMS quota wallet",
 					zap.Int64("user_id", userID),
@@ -636,7 +648,21 @@ func (s *CheckInService) ProcessTimeoutBatch(
 				)
 				continue // 跳过这个用户
 			}
-			walletMap[userID] = wallet
+			balanceMap[userID] = balance
+		}
+
+		// 批量查询用户套餐，用于确定通知联系人上限
+		planMap, err := Subscription().PlansForUsers(ctx, userIDsForQuota)
+		if err != nil {
+			logger.Logger.Warn("Failed to query user plans, falling back to free plan",
+				zap.Error(err),
+			)
+		}
+
+		// 批量查询已确认的紧急联系人（按优先级排序），未确认或已退订的联系人不通知
+		contactMap, err := confirmedContactsByUser(txQ, userIDsForQuota)
+		if err != nil {
+			return err
 		}
 
 		for _, publicID := range userIDs {
@@ -667,7 +693,7 @@ func (s *CheckInService) ProcessTimeoutBatch(
 			}
 
 			// 使用批量查询的额度信息
-			wallet, ok := walletMap[user.ID]
+			smsBalance, ok := balanceMap[user.ID]
 			if !ok {
 				logger.Logger.Warn("No quota wallet found for user",
 					zap.Int64("user_id", user.ID),
@@ -675,10 +701,13 @@ func (s *CheckInService) ProcessTimeoutBatch(
 				continue
 			}
 
-			smsBalance := wallet.AvailableAmount
-
+			// 通知人数不超过套餐的联系人上限（降级后可能存在超出上限的联系人）
+			maxContacts := planMap[user.ID].MaxContacts
 			smsUnitPriceCents := 5
-			contactCount := len(user.EmergencyContacts)
+			contactCount := len(contactMap[user.ID])
+			if contactCount > maxContacts {
+				contactCount = maxContacts
+			}
 			totalCost := smsUnitPriceCents * contactCount
 
 			if smsBalance < totalCost {
@@ -816,13 +845,9 @@ func (s *CheckInService) ProcessTimeoutBatch(
 				return fmt.Errorf("failed to update check-in: %w", err)
 			}
 
-			contacts := user.EmergencyContacts
-			sort.Slice(contacts, func(i, j int) bool {
-				return contacts[i].Priority < contacts[j].Priority
-			})
+			contacts := contactMap[user.ID]
 
-			// 最多通知3位紧急联系人
-			maxContacts := 3
+			// 最多通知套餐上限内的紧急联系人
 			if len(contacts) > maxContacts {
 				contacts = contacts[:maxContacts]
 			}
@@ -848,8 +873,9 @@ func (s *CheckInService) ProcessTimeoutBatch(
 				}
 
 				payload := model.JSONB{
-					"type": "checkin_reminder_contact",
-					"name": contact.DisplayName,
+					"type":   "checkin_reminder_contact",
+					"name":   contact.DisplayName,
+					"optout": contactOptOutLink(contact),
 				}
 
 				// 创建通知任务
@@ -6,11 +6,13 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strconv"
 	"sync"
 	"time"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"AreYouOK/config"
 	"AreYouOK/internal/model"
@@ -18,6 +20,7 @@ import (
 	"AreYouOK/internal/repository/query"
 	pkgerrors "AreYouOK/pkg/errors"
 	"AreYouOK/pkg/logger"
+	"AreYouOK/pkg/snowflake"
 	"AreYouOK/storage/database"
 	"AreYouOK/utils"
 )
@@ -37,151 +40,155 @@ func Contact() *ContactService {
 	return contactService
 }
 
+// ContactService 紧急联系人，存储在 emergency_contacts 表
+// 修改联系人时锁定用户行，同一用户的联系人修改串行执行；单个联系人的修改另以 version 做乐观锁
 type ContactService struct{}
 
-// 应该是一个一个添加的
-// CreateContact 创建一个新的联系人，但不超过三，需要更新优先级，以及注册的 status
-
+// CreateContact 创建一个新的联系人，数量不超过套餐上限
+// 新联系人处于 pending 状态并收到确认短信，确认后才会收到告警，用户状态在第一位联系人确认时更新
 // 生产环境中紧急联系人还不应该是自己
 func (s *ContactService) CreateContact(
 	ctx context.Context,
 	userID string,
 	req dto.CreateContactRequest,
 ) (*dto.CreateContactResponse, error) {
-	// 验证现在的优先级
-
-	if req.Priority < 1 || req.Priority > 3 {
-		return nil, pkgerrors.ContactPriorityConflict
-	}
-
 	// 在 handler 层验证 if !utils.ValidatePhone(req.Phone)
 
-	var userIDInt int64
-
-	if _, err := fmt.Sscanf(userID, "%d", &userIDInt); err != nil {
-		return nil, pkgerrors.InvalidUserID
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
 	}
 
-	user, err := query.User.GetByPublicID(userIDInt)
-
+	plan, err := Subscription().PlanForUser(ctx, user.ID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, pkgerrors.ErrUserNotFound
-		}
-
-		return nil, fmt.Errorf("failed to query user: %w", err)
+		return nil, err
 	}
 
-	contacts := user.EmergencyContacts
-
-	// 三个就达到了上限
-	if len(contacts) >= 3 {
-		return nil, pkgerrors.ContactLimitReached
-	} // 如果是 0 的话需要更新 user 的 status
+	// 验证现在的优先级
+	if req.Priority < 1 || req.Priority > plan.MaxContacts {
+		return nil, pkgerrors.ContactPriorityConflict
+	}
 
-	for _, contact := range contacts {
-		if contact.Priority == req.Priority {
-			return nil, pkgerrors.ContactPriorityConflict
+	// 防止将自己设为紧急联系人
+	if config.Cfg.Environment == "production" && user.PhoneHash != nil {
+		if utils.MatchPhoneHash(req.Phone, *user.PhoneHash) {
+			return nil, fmt.Errorf("emergencyContact can not be yourself")
 		}
 	}
 
-	phoneCipherBase64, err := utils.EncryptPhone(req.Phone)
+	phoneCipher, err := encryptContactPhone(req.Phone)
 	if err != nil {
-		return nil, fmt.Errorf("failed to encrypt phone: %w", err)
+		return nil, err
 	}
 
-	phoneHash := utils.HashPhone(req.Phone)
-
-	// 防止将自己设为紧急联系人
-	if config.Cfg.Environment == "production" {
-		if phoneHash == *user.PhoneHash {
-			return nil, fmt.Errorf("emergencyContact can not be yourself")
-		}
+	contactID, err := snowflake.NextID(snowflake.GeneratorTypeContact)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate contact ID: %w", err)
 	}
 
-	newContact := model.EmergencyContact{
-		DisplayName:       req.DisplayName,
-		Relationship:      req.Relationship,
-		PhoneCipherBase64: phoneCipherBase64,
-		PhoneHash:         phoneHash,
-		Priority:          req.Priority,
-		CreatedAt:         time.Now().Format(time.RFC3339),
+	newContact := &model.Contact{
+		ContactID:        contactID,
+		UserID:           user.ID,
+		DisplayName:      req.DisplayName,
+		Relationship:     req.Relationship,
+		PhoneHash:        utils.HashPhone(req.Phone),
+		PhoneHashVersion: utils.PhoneHashVersion(),
+		PhoneCipher:      phoneCipher,
+		Priority:         req.Priority,
+		Version:          1,
+	}
+	token, err := issueContactConsent(newContact, time.Now())
+	if err != nil {
+		return nil, err
 	}
 
-	isFirstContact := len(contacts) == 0
+	err = database.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+		if err := lockContactOwner(txQ, user.ID); err != nil {
+			return err
+		}
 
-	updatedContacts := append(contacts, newContact)
+		contacts, err := listContacts(txQ, user.ID)
+		if err != nil {
+			return err
+		}
 
-	// 更新用户记录
-	updates := map[string]interface{}{
-		"emergency_contacts": updatedContacts,
-	}
+		// 达到套餐的联系人上限
+		if len(contacts) >= plan.MaxContacts {
+			return pkgerrors.ContactLimitReached
+		}
+		for _, contact := range contacts {
+			if contact.Priority == req.Priority {
+				return pkgerrors.ContactPriorityConflict
+			}
+		}
 
-	// 如果这是第一个联系人，且用户状态为 contact 或 onboarding，更新为 active
-	if isFirstContact && (user.Status == model.UserStatusContact) {
-		updates["status"] = string(model.UserStatusActive)
-		logger.Logger.Info("User activated by adding first contact",
-			zap.String("user_id", userID),
-			zap.Int64("public_id", user.PublicID),
-		)
-	}
+		// 已退订的号码不能再被添加为联系人
+		optedOut, err := isPhoneOptedOut(txQ, req.Phone)
+		if err != nil {
+			return err
+		}
+		if optedOut {
+			return pkgerrors.ContactOptedOut
+		}
+
+		if err := txQ.Contact.Create(newContact); err != nil {
+			return fmt.Errorf("failed to create contact: %w", err)
+		}
 
-	if _, err := query.User.Where(query.User.PublicID.Eq(userIDInt)).Updates(updates); err != nil {
-		return nil, fmt.Errorf("failed to update user contacts: %w", err)
+		return recordAudit(ctx, txQ, auditEntry{
+			UserID:     user.ID,
+			Action:     model.AuditActionContactCreate,
+			TargetType: "contact",
+			TargetID:   strconv.FormatInt(newContact.ContactID, 10),
+			Diff: model.JSONB{
+				"priority":     newContact.Priority,
+				"relationship": newContact.Relationship,
+			},
+		})
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	sendContactConsents(ctx, user, []contactConsentInvite{{phone: req.Phone, token: token}})
+
 	// 脱敏手机号用于响应, 不再处理
 	phoneMasked := req.Phone
 
 	return &dto.CreateContactResponse{
+		ID:           strconv.FormatInt(newContact.ContactID, 10),
 		DisplayName:  newContact.DisplayName,
 		Relationship: newContact.Relationship,
 		PhoneMasked:  phoneMasked,
+		Status:       string(newContact.Status),
 		Priority:     newContact.Priority,
+		Version:      newContact.Version,
 	}, nil
 }
 
-// 删除后要对优先级重新进行排序, 也只需要优先级即可找到
+// ListContacts 按优先级列出紧急联系人
 func (s *ContactService) ListContacts(
 	ctx context.Context,
 	userID string,
 ) ([]dto.ContactItem, error) {
-	var userIDInt int64
-
-	if _, err := fmt.Sscanf(userID, "%d", &userIDInt); err != nil {
-		return nil, pkgerrors.InvalidUserID
-	}
-
-	user, err := query.User.GetByPublicID(userIDInt)
-
+	user, err := s.getUser(ctx, userID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, pkgerrors.ErrUserNotFound
-		}
-		return nil, fmt.Errorf("failed to query user: %w", err)
+		return nil, err
 	}
 
-	contacts := user.EmergencyContacts
-
-	if len(contacts) == 0 {
-		return []dto.ContactItem{}, nil
+	contacts, err := listContacts(query.Use(database.DB().WithContext(ctx)), user.ID)
+	if err != nil {
+		return nil, err
 	}
 
 	result := make([]dto.ContactItem, 0, len(contacts))
 
 	for _, contact := range contacts {
-		phoneCipherBytes, err := base64.StdEncoding.DecodeString(contact.PhoneCipherBase64)
-		if err != nil {
-			logger.Logger.Warn("Failed to decode phone cipher",
-				zap.String("user_id", userID),
-				zap.Int("priority", contact.Priority),
-				zap.Error(err),
-			)
-			continue
-		}
-
-		phone, err := utils.DecryptPhone(phoneCipherBytes)
+		// 定时任务尚未标记的过期邀请按 expired 展示
+		contact.Status = contactConsentState(contact, time.Now())
 
+		phone, err := utils.DecryptPhone(contact.PhoneCipher)
 		if err != nil {
 			logger.Logger.Warn("Failed to decrypt phone",
 				zap.String("user_id", userID),
@@ -191,240 +198,279 @@ func (s *ContactService) ListContacts(
 			continue
 		}
 
-		phoneMasked := phone //直接返回不保密的部分
-
-		createdAt, _ := time.Parse(time.RFC3339, contact.CreatedAt)
-
-		result = append(result, dto.ContactItem{
-			DisplayName:  contact.DisplayName,
-			Relationship: contact.Relationship,
-			PhoneMasked:  phoneMasked,
-			Priority:     contact.Priority,
-			CreatedAt:    createdAt,
-		})
+		result = append(result, contactItem(contact, phone)) //直接返回不保密的部分
 	}
 
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].Priority < result[j].Priority
-	})
 	return result, nil
 }
 
+// DeleteContact 删除联系人（软删除，保留历史），之后对优先级重新排序
 func (s *ContactService) DeleteContact(
 	ctx context.Context,
 	userID string,
 	priority int,
 ) error {
-	if priority < 1 || priority > 3 {
+	if priority < 1 {
 		return pkgerrors.ContactPriorityConflict
 	}
 
-	var userIDInt int64
-
-	if _, err := fmt.Sscanf(userID, "%d", &userIDInt); err != nil {
-		return pkgerrors.InvalidUserID
-	}
-
-	user, err := query.User.GetByPublicID(userIDInt)
-
+	user, err := s.getUser(ctx, userID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+		if errors.Is(err, pkgerrors.ErrUserNotFound) {
 			return pkgerrors.ErrUserIDNotFound
 		}
-		return fmt.Errorf("failed to query user: %w", err)
+		return err
 	}
 
-	contacts := user.EmergencyContacts
-
-	if len(contacts) <= 1 {
-		return pkgerrors.Definition{
-			Code:    "CONTACT_MIN_REQUIRED",
-			Message: "At least one contact is required",
+	remaining := 0
+	err = database.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+		if err := lockContactOwner(txQ, user.ID); err != nil {
+			return err
 		}
-	}
 
-	newContacts := make(model.EmergencyContacts, 0, len(contacts))
-	found := false
+		contacts, err := listContacts(txQ, user.ID)
+		if err != nil {
+			return err
+		}
+		if len(contacts) <= 1 {
+			return pkgerrors.ContactMinRequired
+		}
 
-	for _, contact := range contacts {
-		if contact.Priority == priority {
-			found = true
-			continue // 跳过要删除的联系人
+		var target *model.Contact
+		others := make([]*model.Contact, 0, len(contacts))
+		for _, contact := range contacts {
+			if contact.Priority == priority {
+				target = contact
+				continue // 跳过要删除的联系人
+			}
+			others = append(others, contact)
+		}
+		if target == nil {
+			return pkgerrors.ContactPriorityConflict
 		}
-		newContacts = append(newContacts, contact)
-	}
 
-	if !found {
-		return pkgerrors.ContactPriorityConflict
-	}
+		if _, err := txQ.Contact.Where(txQ.Contact.ID.Eq(target.ID)).Delete(); err != nil {
+			return fmt.Errorf("failed to delete contact: %w", err)
+		}
 
-	sort.Slice(newContacts, func(i, j int) bool {
-		return newContacts[i].Priority < newContacts[j].Priority
+		// 重新分配优先级，确保连续, 避免出现 1,3 的状况
+		// 按原优先级从小到大逐个前移，目标位置总是已空出，不会触发唯一约束
+		if err := compactContactPriorities(txQ, others); err != nil {
+			return err
+		}
+		remaining = len(others)
+
+		return recordAudit(ctx, txQ, auditEntry{
+			UserID:     user.ID,
+			Action:     model.AuditActionContactDelete,
+			TargetType: "contact",
+			TargetID:   strconv.FormatInt(target.ContactID, 10),
+			Diff: model.JSONB{
+				"priority":  priority,
+				"remaining": remaining,
+			},
+		})
 	})
-
-	// 重新分配优先级，确保连续, 避免出现 1,3 的状况
-	for i := range newContacts {
-		newContacts[i].Priority = i + 1
-	}
-
-	updates := map[string]interface{}{
-		"emergency_contacts": newContacts,
-	}
-
-	if _, err := query.User.Where(query.User.PublicID.Eq(userIDInt)).Updates(updates); err != nil {
-		return fmt.Errorf("failed to delete contact: %w", err)
+	if err != nil {
+		return err
 	}
 
 	logger.Logger.Info("Contact deleted",
 		zap.String("user_id", userID),
 		zap.Int("priority", priority),
+		zap.Int("remaining", remaining),
 	)
 
 	return nil
 }
 
+// UpdateContact 按优先级修改联系人，请求带 version 时与当前版本比对
+// 更换号码后联系人重新进入 pending 状态，向新号码发送确认短信
 func (s *ContactService) UpdateContact(
 	ctx context.Context,
 	userID string,
 	req *dto.UpdateContactRequest,
 ) (*dto.UpdateContactResponse, error) {
-	var userIDInt int64
-	if _, err := fmt.Sscanf(userID, "%d", &userIDInt); err != nil {
-		return nil, pkgerrors.InvalidUserID
-	}
-
-	db := database.DB().WithContext(ctx)
-	q := query.Use(db)
-
-	user, err := q.User.GetByPublicID(userIDInt)
+	user, err := s.getUser(ctx, userID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, pkgerrors.ErrUserNotFound
-		}
-		return nil, fmt.Errorf("failed to query user: %w", err)
+		return nil, err
 	}
 
-	contacts := user.EmergencyContacts
-	if len(contacts) == 0 {
-		return nil, pkgerrors.Definition{
-			Code:    "CONTACT_NOT_FOUND",
-			Message: "No contacts to update",
+	var (
+		phoneCipher []byte
+		phoneHash   string
+	)
+	if req.Phone != "" {
+		// 防止将自己设为紧急联系人（仅生产环境）
+		if config.Cfg.Environment == "production" && user.PhoneHash != nil {
+			if utils.MatchPhoneHash(req.Phone, *user.PhoneHash) {
+				return nil, fmt.Errorf("emergencyContact can not be yourself")
+			}
 		}
-	}
 
-	// 按 priority 找到要更新的联系人
-	targetIdx := -1
-	for i, c := range contacts {
-		if c.Priority == req.Priority {
-			targetIdx = i
-			break
+		// 加密手机号
+		phoneCipher, err = encryptContactPhone(req.Phone)
+		if err != nil {
+			return nil, err
 		}
+		phoneHash = utils.HashPhone(req.Phone)
 	}
-	if targetIdx == -1 {
-		return nil, pkgerrors.ContactPriorityConflict // 或者自定义 CONTACT_NOT_FOUND
-	}
-
-	updatedContacts := make(model.EmergencyContacts, len(contacts))
-	copy(updatedContacts, contacts)
 
-	target := &updatedContacts[targetIdx]
+	var (
+		target  *model.Contact
+		invites []contactConsentInvite
+	)
+	err = database.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+		if err := lockContactOwner(txQ, user.ID); err != nil {
+			return err
+		}
 
-	// 按需更新字段
-	if req.DisplayName != "" {
-		target.DisplayName = req.DisplayName
-	}
-	if req.Relationship != "" {
-		target.Relationship = req.Relationship
-	}
+		if req.Phone != "" {
+			optedOut, err := isPhoneOptedOut(txQ, req.Phone)
+			if err != nil {
+				return err
+			}
+			if optedOut {
+				return pkgerrors.ContactOptedOut
+			}
+		}
 
-	phoneForResponse := ""
-	if req.Phone != "" {
-		// 加密手机号
-		phoneCipherBase64, err := utils.EncryptPhone(req.Phone)
+		// 按 priority 找到要更新的联系人
+		target, err = txQ.Contact.
+			Where(txQ.Contact.UserID.Eq(user.ID)).
+			Where(txQ.Contact.Priority.Eq(req.Priority)).
+			First()
 		if err != nil {
-			return nil, fmt.Errorf("failed to encrypt phone: %w", err)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return pkgerrors.ContactPriorityConflict
+			}
+			return fmt.Errorf("failed to query contact: %w", err)
+		}
+		if req.Version > 0 && req.Version != target.Version {
+			return pkgerrors.ContactVersionConflict
 		}
-		phoneHash := utils.HashPhone(req.Phone)
 
-		// 防止将自己设为紧急联系人（仅生产环境）
-		if config.Cfg.Environment == "production" && user.PhoneHash != nil {
-			if phoneHash == *user.PhoneHash {
-				return nil, fmt.Errorf("emergencyContact can not be yourself")
+		// 按需更新字段，只记录变更了哪些字段，不记录姓名与手机号
+		updates := map[string]interface{}{}
+		changed := make([]string, 0, 3)
+		if req.DisplayName != "" && req.DisplayName != target.DisplayName {
+			updates["display_name"] = req.DisplayName
+			target.DisplayName = req.DisplayName
+			changed = append(changed, "display_name")
+		}
+		if req.Relationship != "" && req.Relationship != target.Relationship {
+			updates["relationship"] = req.Relationship
+			target.Relationship = req.Relationship
+			changed = append(changed, "relationship")
+		}
+		if phoneHash != "" && phoneHash != target.PhoneHash {
+			updates["phone_cipher"] = phoneCipher
+			updates["phone_hash"] = phoneHash
+			updates["phone_hash_version"] = utils.PhoneHashVersion()
+			target.PhoneCipher = phoneCipher
+			target.PhoneHash = phoneHash
+			changed = append(changed, "phone")
+
+			// 新号码重新计算发送次数
+			target.ConsentSends = 0
+			token, err := issueContactConsent(target, time.Now())
+			if err != nil {
+				return err
+			}
+			for column, value := range contactConsentColumns(target) {
+				updates[column] = value
 			}
+			invites = append(invites, contactConsentInvite{phone: req.Phone, token: token})
+		}
+		if len(updates) == 0 {
+			return nil
 		}
 
-		target.PhoneCipherBase64 = phoneCipherBase64
-		target.PhoneHash = phoneHash
-		phoneForResponse = req.Phone
-	} else {
-		// 没改手机号，从已有数据解密，用于响应
-		if target.PhoneCipherBase64 != "" {
-			if cipherBytes, err := base64.StdEncoding.DecodeString(target.PhoneCipherBase64); err == nil {
-				if phone, err := utils.DecryptPhone(cipherBytes); err == nil {
-					phoneForResponse = phone
-				}
-			}
+		updates["version"] = target.Version + 1
+		updates["updated_at"] = time.Now()
+		info, err := txQ.Contact.
+			Where(txQ.Contact.ID.Eq(target.ID)).
+			Where(txQ.Contact.Version.Eq(target.Version)).
+			Updates(updates)
+		if err != nil {
+			return fmt.Errorf("failed to update contact: %w", err)
+		}
+		if info.RowsAffected == 0 {
+			return pkgerrors.ContactVersionConflict
 		}
+		target.Version++
+
+		return recordAudit(ctx, txQ, auditEntry{
+			UserID:     user.ID,
+			Action:     model.AuditActionContactUpdate,
+			TargetType: "contact",
+			TargetID:   strconv.FormatInt(target.ContactID, 10),
+			Diff:       model.JSONB{"changed": changed},
+		})
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// 写回 contacts
-	if _, err := q.User.
-		Where(q.User.PublicID.Eq(userIDInt)).
-		Updates(map[string]interface{}{"emergency_contacts": updatedContacts}); err != nil {
-		return nil, fmt.Errorf("failed to update user contacts: %w", err)
-	}
+	sendContactConsents(ctx, user, invites)
 
 	logger.Logger.Info("Contact updated",
 		zap.String("user_id", userID),
 		zap.Int("priority", req.Priority),
+		zap.Int("version", target.Version),
 	)
 
+	phoneForResponse := req.Phone
+	if phoneForResponse == "" {
+		// 没改手机号，从已有数据解密，用于响应
+		phoneForResponse, _ = utils.DecryptPhone(target.PhoneCipher)
+	}
+
 	return &dto.UpdateContactResponse{
+		ID:           strconv.FormatInt(target.ContactID, 10),
 		DisplayName:  target.DisplayName,
 		Relationship: target.Relationship,
 		PhoneMasked:  phoneForResponse,
+		Status:       string(target.Status),
 		Priority:     target.Priority,
+		Version:      target.Version,
 	}, nil
 }
 
 // ReplaceContacts 全量替换紧急联系人
 // 规则：
-// 1. 删除所有现有联系人，用新列表完全替换
-// 2. 联系人数量 1-3 个
-// 3. 优先级必须唯一且在 1-3 范围内
+// 1. 软删除所有现有联系人，用新列表完全替换
+// 2. 联系人数量 1 到套餐上限
+// 3. 优先级必须唯一且在 1 到套餐上限范围内
 // 4. 联系人手机号不能是用户自己（生产环境）
-// 5. 如果是第一次添加联系人，更新用户状态为 active
+// 5. 号码未变的联系人沿用原来的同意状态，新号码发送确认短信
 func (s *ContactService) ReplaceContacts(
 	ctx context.Context,
 	userID string,
 	req dto.ReplaceContactsRequest,
 ) ([]dto.ContactItem, error) {
-	var userIDInt int64
-	if _, err := fmt.Sscanf(userID, "%d", &userIDInt); err != nil {
-		return nil, pkgerrors.InvalidUserID
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
 	}
 
-	db := database.DB().WithContext(ctx)
-	q := query.Use(db)
-
-	user, err := q.User.GetByPublicID(userIDInt)
+	plan, err := Subscription().PlanForUser(ctx, user.ID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, pkgerrors.ErrUserNotFound
-		}
-		return nil, fmt.Errorf("failed to query user: %w", err)
+		return nil, err
 	}
 
-	if len(req.Contacts) < 1 || len(req.Contacts) > 3 {
+	if len(req.Contacts) < 1 || len(req.Contacts) > plan.MaxContacts {
 		return nil, pkgerrors.Definition{
 			Code:    "INVALID_CONTACTS_COUNT",
-			Message: "Contacts count must be between 1 and 3",
+			Message: fmt.Sprintf("Contacts count must be between 1 and %d", plan.MaxContacts),
 		}
 	}
 
 	prioritySet := make(map[int]bool)
 	for _, contact := range req.Contacts {
-		if contact.Priority < 1 || contact.Priority > 3 {
+		if contact.Priority < 1 || contact.Priority > plan.MaxContacts {
 			return nil, pkgerrors.ContactPriorityConflict
 		}
 		if prioritySet[contact.Priority] {
@@ -436,12 +482,10 @@ func (s *ContactService) ReplaceContacts(
 		prioritySet[contact.Priority] = true
 	}
 
-
-	newContacts := make(model.EmergencyContacts, 0, len(req.Contacts))
-	now := time.Now().Format(time.RFC3339)
+	newContacts := make([]*model.Contact, 0, len(req.Contacts))
+	phones := make(map[int64]string, len(req.Contacts))
 
 	for _, contact := range req.Contacts {
-
 		if !utils.ValidatePhone(contact.Phone) {
 			return nil, pkgerrors.Definition{
 				Code:    "INVALID_PHONE",
@@ -449,17 +493,8 @@ func (s *ContactService) ReplaceContacts(
 			}
 		}
 
-
-		phoneCipherBase64, err := utils.EncryptPhone(contact.Phone)
-		if err != nil {
-			return nil, fmt.Errorf("failed to encrypt phone: %w", err)
-		}
-
-		phoneHash := utils.HashPhone(contact.Phone)
-
-
 		if config.Cfg.Environment == "production" && user.PhoneHash != nil {
-			if phoneHash == *user.PhoneHash {
+			if utils.MatchPhoneHash(contact.Phone, *user.PhoneHash) {
 				return nil, pkgerrors.Definition{
 					Code:    "SELF_AS_CONTACT",
 					Message: "Emergency contact cannot be yourself",
@@ -467,14 +502,28 @@ func (s *ContactService) ReplaceContacts(
 			}
 		}
 
-		newContacts = append(newContacts, model.EmergencyContact{
-			DisplayName:       contact.DisplayName,
-			Relationship:      contact.Relationship,
-			PhoneCipherBase64: phoneCipherBase64,
-			PhoneHash:         phoneHash,
-			Priority:          contact.Priority,
-			CreatedAt:         now,
+		phoneCipher, err := encryptContactPhone(contact.Phone)
+		if err != nil {
+			return nil, err
+		}
+
+		contactID, err := snowflake.NextID(snowflake.GeneratorTypeContact)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate contact ID: %w", err)
+		}
+
+		newContacts = append(newContacts, &model.Contact{
+			ContactID:        contactID,
+			UserID:           user.ID,
+			DisplayName:      contact.DisplayName,
+			Relationship:     contact.Relationship,
+			PhoneHash:        utils.HashPhone(contact.Phone),
+			PhoneHashVersion: utils.PhoneHashVersion(),
+			PhoneCipher:      phoneCipher,
+			Priority:         contact.Priority,
+			Version:          1,
 		})
+		phones[contactID] = contact.Phone
 	}
 
 	// 按优先级排序
@@ -482,48 +531,241 @@ func (s *ContactService) ReplaceContacts(
 		return newContacts[i].Priority < newContacts[j].Priority
 	})
 
-	// 准备更新
-	updates := map[string]interface{}{
-		"emergency_contacts": newContacts,
-	}
+	var invites []contactConsentInvite
+	err = database.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+		if err := lockContactOwner(txQ, user.ID); err != nil {
+			return err
+		}
 
-	// 如果之前没有联系人，且用户状态为 contact，更新为 active
-	wasEmpty := len(user.EmergencyContacts) == 0
-	if wasEmpty && user.Status == model.UserStatusContact {
-		updates["status"] = string(model.UserStatusActive)
-		logger.Logger.Info("User activated by replacing contacts",
-			zap.String("user_id", userID),
-			zap.Int64("public_id", user.PublicID),
-		)
-	}
+		oldContacts, err := listContacts(txQ, user.ID)
+		if err != nil {
+			return err
+		}
+
+		invites = invites[:0]
+		now := time.Now()
+		for _, contact := range newContacts {
+			phone := phones[contact.ContactID]
+			optedOut, err := isPhoneOptedOut(txQ, phone)
+			if err != nil {
+				return err
+			}
+			if optedOut {
+				return pkgerrors.ContactOptedOut
+			}
+
+			var previous *model.Contact
+			for _, old := range oldContacts {
+				if utils.MatchPhoneHash(phone, old.PhoneHash) {
+					previous = old
+					break
+				}
+			}
+			if previous != nil {
+				copyContactConsent(contact, previous)
+				continue
+			}
+
+			token, err := issueContactConsent(contact, now)
+			if err != nil {
+				return err
+			}
+			invites = append(invites, contactConsentInvite{phone: phone, token: token})
+		}
+
+		// 旧联系人软删除后释放优先级，再写入新列表
+		if len(oldContacts) > 0 {
+			if _, err := txQ.Contact.Where(txQ.Contact.UserID.Eq(user.ID)).Delete(); err != nil {
+				return fmt.Errorf("failed to delete contacts: %w", err)
+			}
+		}
+		if err := txQ.Contact.Create(newContacts...); err != nil {
+			return fmt.Errorf("failed to replace contacts: %w", err)
+		}
 
-	// 执行更新
-	if _, err := q.User.Where(q.User.PublicID.Eq(userIDInt)).Updates(updates); err != nil {
-		return nil, fmt.Errorf("failed to replace contacts: %w", err)
+		return recordAudit(ctx, txQ, auditEntry{
+			UserID:     user.ID,
+			Action:     model.AuditActionContactReplace,
+			TargetType: "contact",
+			Diff: model.JSONB{
+				"from": auditContacts(oldContacts),
+				"to":   auditContacts(newContacts),
+			},
+		})
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	sendContactConsents(ctx, user, invites)
+
 	logger.Logger.Info("Contacts replaced",
 		zap.String("user_id", userID),
 		zap.Int("count", len(newContacts)),
+		zap.Int("consent_requests", len(invites)),
 	)
 
 	// 构建响应（返回完整的联系人列表）
 	result := make([]dto.ContactItem, 0, len(newContacts))
 	for _, contact := range newContacts {
-		// 解密手机号用于响应
-		phoneCipherBytes, _ := base64.StdEncoding.DecodeString(contact.PhoneCipherBase64)
-		phone, _ := utils.DecryptPhone(phoneCipherBytes)
-
-		createdAt, _ := time.Parse(time.RFC3339, contact.CreatedAt)
-
-		result = append(result, dto.ContactItem{
-			DisplayName:  contact.DisplayName,
-			Relationship: contact.Relationship,
-			PhoneMasked:  phone, // 返回完整手机号
-			Priority:     contact.Priority,
-			CreatedAt:    createdAt,
-		})
+		result = append(result, contactItem(contact, phones[contact.ContactID])) // 返回完整手机号
 	}
 
 	return result, nil
 }
+
+func (s *ContactService) getUser(ctx context.Context, userID string) (*model.User, error) {
+	var userIDInt int64
+	if _, err := fmt.Sscanf(userID, "%d", &userIDInt); err != nil {
+		return nil, pkgerrors.InvalidUserID
+	}
+
+	q := query.Use(database.DB().WithContext(ctx))
+	user, err := q.User.GetByPublicID(userIDInt)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgerrors.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to query user: %w", err)
+	}
+	return user, nil
+}
+
+// listContacts 用户当前的紧急联系人（不含已删除），按优先级排序
+func listContacts(q *query.Query, userID int64) ([]*model.Contact, error) {
+	contacts, err := q.Contact.
+		Where(q.Contact.UserID.Eq(userID)).
+		Order(q.Contact.Priority).
+		Find()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query contacts: %w", err)
+	}
+	return contacts, nil
+}
+
+// listConfirmedContacts 用户已确认的紧急联系人，告警只发给这些联系人，按优先级排序
+// 号码在免打扰名单中的联系人不返回
+func listConfirmedContacts(q *query.Query, userID int64) ([]*model.Contact, error) {
+	contacts, err := q.Contact.
+		Where(q.Contact.UserID.Eq(userID)).
+		Where(q.Contact.Status.Eq(string(model.ContactStatusConfirmed))).
+		Order(q.Contact.Priority).
+		Find()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query contacts: %w", err)
+	}
+	return filterOptedOutContacts(q, contacts)
+}
+
+// confirmedContactsByUser 批量查询多个用户已确认的紧急联系人，每个用户的联系人按优先级排序
+// 号码在免打扰名单中的联系人不返回
+func confirmedContactsByUser(q *query.Query, userIDs []int64) (map[int64][]*model.Contact, error) {
+	result := make(map[int64][]*model.Contact, len(userIDs))
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+
+	contacts, err := q.Contact.
+		Where(q.Contact.UserID.In(userIDs...)).
+		Where(q.Contact.Status.Eq(string(model.ContactStatusConfirmed))).
+		Order(q.Contact.UserID, q.Contact.Priority).
+		Find()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query contacts: %w", err)
+	}
+	contacts, err = filterOptedOutContacts(q, contacts)
+	if err != nil {
+		return nil, err
+	}
+	for _, contact := range contacts {
+		result[contact.UserID] = append(result[contact.UserID], contact)
+	}
+	return result, nil
+}
+
+// countContacts 用户当前的紧急联系人数量
+func countContacts(q *query.Query, userID int64) (int, error) {
+	count, err := q.Contact.Where(q.Contact.UserID.Eq(userID)).Count()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count contacts: %w", err)
+	}
+	return int(count), nil
+}
+
+// countConfirmedContacts 用户已确认的紧急联系人数量，用于估算告警消耗的额度
+func countConfirmedContacts(q *query.Query, userID int64) (int, error) {
+	count, err := q.Contact.
+		Where(q.Contact.UserID.Eq(userID)).
+		Where(q.Contact.Status.Eq(string(model.ContactStatusConfirmed))).
+		Count()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count contacts: %w", err)
+	}
+	return int(count), nil
+}
+
+// lockContactOwner 锁定用户行，串行化同一用户的联系人修改
+func lockContactOwner(txQ *query.Query, userID int64) error {
+	if _, err := txQ.User.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(txQ.User.ID.Eq(userID)).
+		First(); err != nil {
+		return fmt.Errorf("failed to lock user: %w", err)
+	}
+	return nil
+}
+
+// compactContactPriorities 按当前顺序把优先级重排为 1..n，变更的联系人版本号加一
+// contacts 需按原优先级升序排列
+func compactContactPriorities(txQ *query.Query, contacts []*model.Contact) error {
+	now := time.Now()
+	for i, contact := range contacts {
+		if contact.Priority == i+1 {
+			continue
+		}
+		if _, err := txQ.Contact.
+			Where(txQ.Contact.ID.Eq(contact.ID)).
+			Updates(map[string]interface{}{
+				"priority":   i + 1,
+				"version":    gorm.Expr("version + ?", 1),
+				"updated_at": now,
+			}); err != nil {
+			return fmt.Errorf("failed to reorder contacts: %w", err)
+		}
+		contact.Priority = i + 1
+		contact.Version++
+	}
+	return nil
+}
+
+// encryptContactPhone 加密联系人手机号，返回写入 bytea 列的原始密文
+func encryptContactPhone(phone string) ([]byte, error) {
+	cipherBase64, err := utils.EncryptPhone(phone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt phone: %w", err)
+	}
+	cipher, err := base64.StdEncoding.DecodeString(cipherBase64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode phone cipher: %w", err)
+	}
+	return cipher, nil
+}
+
+func contactItem(contact *model.Contact, phone string) dto.ContactItem {
+	item := dto.ContactItem{
+		ID:           strconv.FormatInt(contact.ContactID, 10),
+		DisplayName:  contact.DisplayName,
+		Relationship: contact.Relationship,
+		PhoneMasked:  phone,
+		Status:       string(contact.Status),
+		Priority:     contact.Priority,
+		Version:      contact.Version,
+		ResendsLeft:  contactResendsLeft(contact),
+		CreatedAt:    contact.CreatedAt,
+	}
+	if contact.Status == model.ContactStatusPending {
+		item.ConsentExpiresAt = contact.ConsentExpiresAt
+	}
+	return item
+}
@@ -0,0 +1,405 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"AreYouOK/config"
+	"AreYouOK/internal/model"
+	"AreYouOK/internal/model/dto"
+	"AreYouOK/internal/repository/query"
+	pkgerrors "AreYouOK/pkg/errors"
+	"AreYouOK/pkg/logger"
+	"AreYouOK/pkg/sms"
+	"AreYouOK/storage/database"
+	"AreYouOK/utils"
+)
+
+// 联系人同意流程：
+// 新增联系人或更换号码后，联系人收到带 token 的确认链接，确认后才会收到告警；
+// 链接在有效期内可以改变主意（确认 / 拒绝），过期未回应的联系人由定时任务标记为 expired，用户可重新发送
+// 号码退订后确认 token 被清除，链接随之失效（见 OptOutService）
+// 同一号码的发送次数与间隔受限，避免骚扰陌生人
+
+// contactConsentInvite 事务提交后待发送的确认短信
+type contactConsentInvite struct {
+	phone string
+	token string
+}
+
+// ResendConsent 重新发送联系人确认短信，只对等待确认或已过期的联系人有效
+func (s *ContactService) ResendConsent(
+	ctx context.Context,
+	userID string,
+	priority int,
+) (*dto.ContactItem, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var (
+		target *model.Contact
+		invite contactConsentInvite
+	)
+	err = database.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+		if err := lockContactOwner(txQ, user.ID); err != nil {
+			return err
+		}
+
+		target, err = txQ.Contact.
+			Where(txQ.Contact.UserID.Eq(user.ID)).
+			Where(txQ.Contact.Priority.Eq(priority)).
+			First()
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return pkgerrors.ContactPriorityConflict
+			}
+			return fmt.Errorf("failed to query contact: %w", err)
+		}
+
+		switch contactConsentState(target, now) {
+		case model.ContactStatusConfirmed, model.ContactStatusDeclined:
+			return pkgerrors.ContactConsentNotPending
+		case model.ContactStatusOptedOut:
+			return pkgerrors.ContactOptedOut
+		}
+		if target.ConsentSends >= config.Cfg.ContactConsentMaxSends {
+			return pkgerrors.ContactConsentLimitReached
+		}
+		cooldown := time.Duration(config.Cfg.ContactConsentCooldown) * time.Minute
+		if target.ConsentSentAt != nil && now.Before(target.ConsentSentAt.Add(cooldown)) {
+			return pkgerrors.ContactConsentCooldown
+		}
+
+		phone, err := decryptContactPhone(target)
+		if err != nil {
+			return err
+		}
+		token, err := issueContactConsent(target, now)
+		if err != nil {
+			return err
+		}
+		invite = contactConsentInvite{phone: phone, token: token}
+
+		updates := contactConsentColumns(target)
+		updates["version"] = target.Version + 1
+		updates["updated_at"] = now
+		info, err := txQ.Contact.
+			Where(txQ.Contact.ID.Eq(target.ID)).
+			Where(txQ.Contact.Version.Eq(target.Version)).
+			Updates(updates)
+		if err != nil {
+			return fmt.Errorf("failed to update contact: %w", err)
+		}
+		if info.RowsAffected == 0 {
+			return pkgerrors.ContactVersionConflict
+		}
+		target.Version++
+
+		return recordAudit(ctx, txQ, auditEntry{
+			UserID:     user.ID,
+			Action:     model.AuditActionContactResend,
+			TargetType: "contact",
+			TargetID:   strconv.FormatInt(target.ContactID, 10),
+			Diff: model.JSONB{
+				"priority": target.Priority,
+				"sends":    target.ConsentSends,
+			},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sendContactConsents(ctx, user, []contactConsentInvite{invite})
+
+	item := contactItem(target, invite.phone)
+	return &item, nil
+}
+
+// GetConsent 联系人打开确认链接，查看邀请信息
+func (s *ContactService) GetConsent(ctx context.Context, token string) (*dto.ContactConsentData, error) {
+	q := query.Use(database.DB().WithContext(ctx))
+
+	contact, err := findContactByConsentToken(q, token, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := q.User.Where(q.User.ID.Eq(contact.UserID)).First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgerrors.ContactConsentNotFound
+		}
+		return nil, fmt.Errorf("failed to query user: %w", err)
+	}
+
+	return contactConsentData(contact, user), nil
+}
+
+// RespondConsent 联系人确认或拒绝成为紧急联系人，有效期内可以改变主意
+// 用户的第一位联系人确认后，处于 contact 状态的用户变为 active
+func (s *ContactService) RespondConsent(
+	ctx context.Context,
+	token string,
+	accept bool,
+) (*dto.ContactConsentData, error) {
+	now := time.Now()
+	status := model.ContactStatusDeclined
+	if accept {
+		status = model.ContactStatusConfirmed
+	}
+
+	var (
+		contact *model.Contact
+		user    *model.User
+	)
+	err := database.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+
+		found, err := findContactByConsentToken(txQ, token, now)
+		if err != nil {
+			return err
+		}
+		if err := lockContactOwner(txQ, found.UserID); err != nil {
+			return err
+		}
+
+		// 锁定后重新读取，期间用户可能修改或删除了该联系人
+		contact, err = findContactByConsentToken(txQ, token, now)
+		if err != nil {
+			return err
+		}
+		user, err = txQ.User.Where(txQ.User.ID.Eq(contact.UserID)).First()
+		if err != nil {
+			return fmt.Errorf("failed to query user: %w", err)
+		}
+
+		return applyContactResponse(ctx, txQ, user, contact, status, "link", now)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Logger.Info("Contact responded to consent request",
+		zap.Int64("contact_id", contact.ContactID),
+		zap.String("status", string(status)),
+	)
+
+	return contactConsentData(contact, user), nil
+}
+
+// applyContactResponse 在调用方的事务中写入联系人的确认或拒绝，调用方需已锁定联系人所属用户
+// 用户的第一位联系人确认后，处于 contact 状态的用户变为 active；via 记录回应渠道（link / portal）
+func applyContactResponse(
+	ctx context.Context,
+	txQ *query.Query,
+	user *model.User,
+	contact *model.Contact,
+	status model.ContactStatus,
+	via string,
+	now time.Time,
+) error {
+	if contact.Status == status {
+		return nil
+	}
+
+	from := contact.Status
+	info, err := txQ.Contact.
+		Where(txQ.Contact.ID.Eq(contact.ID)).
+		Where(txQ.Contact.Version.Eq(contact.Version)).
+		Updates(map[string]interface{}{
+			"status":       string(status),
+			"responded_at": now,
+			"version":      contact.Version + 1,
+			"updated_at":   now,
+		})
+	if err != nil {
+		return fmt.Errorf("failed to update contact: %w", err)
+	}
+	if info.RowsAffected == 0 {
+		return pkgerrors.ContactVersionConflict
+	}
+	contact.Status = status
+	contact.RespondedAt = &now
+	contact.Version++
+
+	if status == model.ContactStatusConfirmed && user.Status == model.UserStatusContact {
+		if _, err := txQ.User.
+			Where(txQ.User.ID.Eq(user.ID)).
+			Update(txQ.User.Status, string(model.UserStatusActive)); err != nil {
+			return fmt.Errorf("failed to update user status: %w", err)
+		}
+		user.Status = model.UserStatusActive
+		logger.Logger.Info("User activated by first confirmed contact",
+			zap.Int64("public_id", user.PublicID),
+		)
+	}
+
+	return recordAudit(ctx, txQ, auditEntry{
+		UserID:     user.ID,
+		ContactID:  contact.ContactID,
+		Action:     model.AuditActionContactConsent,
+		TargetType: "contact",
+		TargetID:   strconv.FormatInt(contact.ContactID, 10),
+		Diff: model.JSONB{
+			"status": model.JSONB{"from": string(from), "to": string(status)},
+			"via":    via,
+		},
+	})
+}
+
+// ExpireConsents 把确认链接已过期仍未回应的联系人标记为 expired（定时任务调用）
+// 返回本次标记的联系人数量
+func (s *ContactService) ExpireConsents(ctx context.Context, now time.Time) (int, error) {
+	q := query.Use(database.DB().WithContext(ctx))
+
+	info, err := q.Contact.
+		Where(q.Contact.Status.Eq(string(model.ContactStatusPending))).
+		Where(q.Contact.ConsentExpiresAt.Lt(now)).
+		Updates(map[string]interface{}{
+			"status":     string(model.ContactStatusExpired),
+			"version":    gorm.Expr("version + ?", 1),
+			"updated_at": now,
+		})
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire contact consents: %w", err)
+	}
+	return int(info.RowsAffected), nil
+}
+
+// findContactByConsentToken 按确认链接 token 查找联系人，链接过期返回 ContactConsentExpired
+func findContactByConsentToken(q *query.Query, token string, now time.Time) (*model.Contact, error) {
+	if token == "" {
+		return nil, pkgerrors.ContactConsentNotFound
+	}
+
+	contact, err := q.Contact.
+		Where(q.Contact.ConsentTokenHash.Eq(utils.HashConsentToken(token))).
+		First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgerrors.ContactConsentNotFound
+		}
+		return nil, fmt.Errorf("failed to query contact: %w", err)
+	}
+
+	if contact.ConsentExpiresAt == nil || now.After(*contact.ConsentExpiresAt) {
+		return nil, pkgerrors.ContactConsentExpired
+	}
+	return contact, nil
+}
+
+// contactConsentState 联系人当前的同意状态，pending 且链接已过期的按 expired 处理（定时任务尚未标记时）
+func contactConsentState(contact *model.Contact, now time.Time) model.ContactStatus {
+	if contact.Status == model.ContactStatusPending &&
+		contact.ConsentExpiresAt != nil && now.After(*contact.ConsentExpiresAt) {
+		return model.ContactStatusExpired
+	}
+	return contact.Status
+}
+
+// issueContactConsent 为联系人生成新的确认 token，状态重置为 pending，返回明文 token
+// 只修改内存中的字段，由调用方写入数据库
+func issueContactConsent(contact *model.Contact, now time.Time) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate consent token: %w", err)
+	}
+	token := hex.EncodeToString(b)
+
+	hash := utils.HashConsentToken(token)
+	expiresAt := now.Add(time.Duration(config.Cfg.ContactConsentHours) * time.Hour)
+	sentAt := now
+
+	contact.Status = model.ContactStatusPending
+	contact.ConsentTokenHash = &hash
+	contact.ConsentSentAt = &sentAt
+	contact.ConsentExpiresAt = &expiresAt
+	contact.ConsentSends++
+	contact.RespondedAt = nil
+	return token, nil
+}
+
+// contactConsentColumns 同意流程相关的列，用于 Updates
+func contactConsentColumns(contact *model.Contact) map[string]interface{} {
+	return map[string]interface{}{
+		"status":             string(contact.Status),
+		"consent_token_hash": contact.ConsentTokenHash,
+		"consent_sent_at":    contact.ConsentSentAt,
+		"consent_expires_at": contact.ConsentExpiresAt,
+		"consent_sends":      contact.ConsentSends,
+		"responded_at":       contact.RespondedAt,
+	}
+}
+
+// copyContactConsent 全量替换时号码未变的联系人沿用原来的同意状态，不重复发送确认短信
+func copyContactConsent(dst, src *model.Contact) {
+	dst.Status = src.Status
+	dst.ConsentTokenHash = src.ConsentTokenHash
+	dst.ConsentSentAt = src.ConsentSentAt
+	dst.ConsentExpiresAt = src.ConsentExpiresAt
+	dst.ConsentSends = src.ConsentSends
+	dst.RespondedAt = src.RespondedAt
+}
+
+// contactResendsLeft 当前号码还可以发送确认短信的次数
+func contactResendsLeft(contact *model.Contact) int {
+	left := config.Cfg.ContactConsentMaxSends - contact.ConsentSends
+	if left < 0 {
+		return 0
+	}
+	return left
+}
+
+// sendContactConsents 事务提交后发送确认短信，发送失败只记录日志，用户可以重新发送
+func sendContactConsents(ctx context.Context, user *model.User, invites []contactConsentInvite) {
+	name := user.Nickname
+	if name == "" {
+		name = "您的好友"
+	}
+
+	for _, invite := range invites {
+		if _, err := sms.SendContactConsentSMS(ctx, invite.phone, name, contactConsentLink(invite.token)); err != nil {
+			logger.Logger.Error("Failed to send contact consent SMS",
+				zap.Int64("public_id", user.PublicID),
+				zap.String("phone", utils.MaskPhone(invite.phone)),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// contactConsentLink 确认页链接，未配置 CONTACT_CONSENT_URL 时只发送 token
+func contactConsentLink(token string) string {
+	base := config.Cfg.ContactConsentURL
+	if base == "" {
+		return token
+	}
+	return base + "?token=" + url.QueryEscape(token)
+}
+
+func contactConsentData(contact *model.Contact, user *model.User) *dto.ContactConsentData {
+	data := &dto.ContactConsentData{
+		Requester:    user.Nickname,
+		DisplayName:  contact.DisplayName,
+		Relationship: contact.Relationship,
+		Status:       string(contact.Status),
+	}
+	if contact.ConsentExpiresAt != nil {
+		data.ExpiresAt = *contact.ConsentExpiresAt
+	}
+	return data
+}
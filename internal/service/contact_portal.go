@@ -0,0 +1,385 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"AreYouOK/internal/model"
+	"AreYouOK/internal/model/dto"
+	"AreYouOK/internal/repository/query"
+	pkgerrors "AreYouOK/pkg/errors"
+	"AreYouOK/pkg/logger"
+	"AreYouOK/pkg/token"
+	"AreYouOK/storage/database"
+	"AreYouOK/utils"
+)
+
+var (
+	contactPortalService *ContactPortalService
+	contactPortalOnce    sync.Once
+)
+
+func ContactPortal() *ContactPortalService {
+	contactPortalOnce.Do(func() {
+		contactPortalService = &ContactPortalService{}
+	})
+	return contactPortalService
+}
+
+// ContactPortalService 联系人门户
+// 被设为紧急联系人的人用手机验证码登录（复用 VerificationService 的验证码流程），
+// 按手机号哈希查看哪些用户把自己设为联系人、收到过哪些告警，并可以确认、拒绝或退出联系人列表
+type ContactPortalService struct{}
+
+// contactAlertLimit 告警记录最多返回的条数
+const contactAlertLimit = 50
+
+// Login 校验手机验证码后签发联系人会话 token
+func (s *ContactPortalService) Login(ctx context.Context, phone, code string) (*dto.ContactPortalSession, error) {
+	if err := Verification().VerifyCaptcha(ctx, phone, code); err != nil {
+		return nil, err
+	}
+
+	phoneCipher, err := utils.EncryptPhone(phone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt phone: %w", err)
+	}
+
+	accessToken, expiresIn, err := token.GenerateContactToken(phoneCipher)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Logger.Info("Contact portal session created",
+		zap.String("phone", utils.MaskPhone(phone)),
+	)
+
+	return &dto.ContactPortalSession{
+		AccessToken: accessToken,
+		PhoneMasked: utils.MaskPhone(phone),
+		ExpiresIn:   expiresIn,
+	}, nil
+}
+
+// ListRequesters 把当前号码设为紧急联系人的用户（不含已删除的联系人与已注销的用户）
+func (s *ContactPortalService) ListRequesters(ctx context.Context, phoneCipher string) ([]dto.ContactRequesterItem, error) {
+	phone, err := contactPortalPhone(phoneCipher)
+	if err != nil {
+		return nil, err
+	}
+
+	q := query.Use(database.DB().WithContext(ctx))
+	contacts, err := q.Contact.
+		Where(q.Contact.PhoneHash.In(utils.PhoneHashCandidates(phone)...)).
+		Order(q.Contact.CreatedAt.Desc()).
+		Find()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query contacts: %w", err)
+	}
+
+	userIDs := make([]int64, 0, len(contacts))
+	for _, contact := range contacts {
+		userIDs = append(userIDs, contact.UserID)
+	}
+	nicknames, err := contactPortalNicknames(q, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	items := make([]dto.ContactRequesterItem, 0, len(contacts))
+	for _, contact := range contacts {
+		nickname, ok := nicknames[contact.UserID]
+		if !ok {
+			continue
+		}
+		items = append(items, dto.ContactRequesterItem{
+			CreatedAt:        contact.CreatedAt,
+			ConsentExpiresAt: contact.ConsentExpiresAt,
+			RespondedAt:      contact.RespondedAt,
+			ContactID:        strconv.FormatInt(contact.ContactID, 10),
+			Requester:        nickname,
+			DisplayName:      contact.DisplayName,
+			Relationship:     contact.Relationship,
+			Status:           string(contactConsentState(contact, now)),
+			Priority:         contact.Priority,
+		})
+	}
+	return items, nil
+}
+
+// ListAlerts 当前号码收到的告警短信（最近 50 条），包括已退出的联系人列表中的历史告警
+func (s *ContactPortalService) ListAlerts(ctx context.Context, phoneCipher string) ([]dto.ContactAlertItem, error) {
+	phone, err := contactPortalPhone(phoneCipher)
+	if err != nil {
+		return nil, err
+	}
+
+	q := query.Use(database.DB().WithContext(ctx))
+
+	// 先按号码找到相关用户（含已删除的联系人记录），再在这些用户的通知任务中查找，利用 user_id 索引
+	contacts, err := q.Contact.Unscoped().
+		Where(q.Contact.PhoneHash.In(utils.PhoneHashCandidates(phone)...)).
+		Find()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query contacts: %w", err)
+	}
+	if len(contacts) == 0 {
+		return []dto.ContactAlertItem{}, nil
+	}
+
+	userIDSet := make(map[int64]bool, len(contacts))
+	hashSet := make(map[string]bool, len(contacts))
+	for _, hash := range utils.PhoneHashCandidates(phone) {
+		hashSet[hash] = true
+	}
+	userIDs := make([]int64, 0, len(contacts))
+	for _, contact := range contacts {
+		if !userIDSet[contact.UserID] {
+			userIDSet[contact.UserID] = true
+			userIDs = append(userIDs, contact.UserID)
+		}
+		hashSet[contact.PhoneHash] = true
+	}
+	hashes := make([]string, 0, len(hashSet))
+	for hash := range hashSet {
+		hashes = append(hashes, hash)
+	}
+
+	tasks, err := q.NotificationTask.
+		Where(q.NotificationTask.UserID.In(userIDs...)).
+		Where(q.NotificationTask.ContactPhoneHash.In(hashes...)).
+		Where(q.NotificationTask.Category.In(
+			string(model.NotificationCategoryCheckInTimeout),
+			string(model.NotificationCategoryJourneyTimeout),
+		)).
+		Where(q.NotificationTask.Status.Eq(string(model.NotificationTaskStatusSuccess))).
+		Order(q.NotificationTask.ScheduledAt.Desc()).
+		Limit(contactAlertLimit).
+		Find()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notification tasks: %w", err)
+	}
+
+	nicknames, err := contactPortalNicknames(q, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.ContactAlertItem, 0, len(tasks))
+	for _, task := range tasks {
+		sentAt := task.ScheduledAt
+		if task.ProcessedAt != nil {
+			sentAt = *task.ProcessedAt
+		}
+		items = append(items, dto.ContactAlertItem{
+			SentAt:    sentAt,
+			ID:        strconv.FormatInt(task.TaskCode, 10),
+			Requester: nicknames[task.UserID],
+			Category:  string(task.Category),
+		})
+	}
+	return items, nil
+}
+
+// Respond 联系人在门户中确认或拒绝成为某位用户的紧急联系人
+// 已验证手机号，不受确认链接有效期限制；号码已退订时不能确认
+func (s *ContactPortalService) Respond(
+	ctx context.Context,
+	phoneCipher string,
+	contactID string,
+	accept bool,
+) (*dto.ContactRequesterItem, error) {
+	phone, err := contactPortalPhone(phoneCipher)
+	if err != nil {
+		return nil, err
+	}
+
+	status := model.ContactStatusDeclined
+	if accept {
+		status = model.ContactStatusConfirmed
+	}
+
+	now := time.Now()
+	var (
+		contact *model.Contact
+		user    *model.User
+	)
+	err = database.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+
+		found, err := findPortalContact(txQ, phone, contactID)
+		if err != nil {
+			return err
+		}
+		if err := lockContactOwner(txQ, found.UserID); err != nil {
+			return err
+		}
+
+		// 锁定后重新读取，期间用户可能修改或删除了该联系人
+		contact, err = findPortalContact(txQ, phone, contactID)
+		if err != nil {
+			return err
+		}
+		if accept {
+			optedOut, err := isPhoneOptedOut(txQ, phone)
+			if err != nil {
+				return err
+			}
+			if optedOut || contact.Status == model.ContactStatusOptedOut {
+				return pkgerrors.ContactOptedOut
+			}
+		}
+
+		user, err = txQ.User.Where(txQ.User.ID.Eq(contact.UserID)).First()
+		if err != nil {
+			return fmt.Errorf("failed to query user: %w", err)
+		}
+
+		return applyContactResponse(ctx, txQ, user, contact, status, "portal", now)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Logger.Info("Contact responded via portal",
+		zap.Int64("contact_id", contact.ContactID),
+		zap.String("status", string(status)),
+	)
+
+	return &dto.ContactRequesterItem{
+		CreatedAt:        contact.CreatedAt,
+		ConsentExpiresAt: contact.ConsentExpiresAt,
+		RespondedAt:      contact.RespondedAt,
+		ContactID:        strconv.FormatInt(contact.ContactID, 10),
+		Requester:        user.Nickname,
+		DisplayName:      contact.DisplayName,
+		Relationship:     contact.Relationship,
+		Status:           string(contact.Status),
+		Priority:         contact.Priority,
+	}, nil
+}
+
+// Leave 联系人退出某位用户的联系人列表：联系人记录软删除，其余联系人的优先级前移
+// 与用户删除联系人不同，即使是用户的最后一位联系人也允许退出
+func (s *ContactPortalService) Leave(ctx context.Context, phoneCipher string, contactID string) error {
+	phone, err := contactPortalPhone(phoneCipher)
+	if err != nil {
+		return err
+	}
+
+	var target *model.Contact
+	err = database.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+
+		found, err := findPortalContact(txQ, phone, contactID)
+		if err != nil {
+			return err
+		}
+		if err := lockContactOwner(txQ, found.UserID); err != nil {
+			return err
+		}
+
+		target, err = findPortalContact(txQ, phone, contactID)
+		if err != nil {
+			return err
+		}
+
+		contacts, err := listContacts(txQ, target.UserID)
+		if err != nil {
+			return err
+		}
+		others := make([]*model.Contact, 0, len(contacts))
+		for _, contact := range contacts {
+			if contact.ID != target.ID {
+				others = append(others, contact)
+			}
+		}
+
+		if _, err := txQ.Contact.Where(txQ.Contact.ID.Eq(target.ID)).Delete(); err != nil {
+			return fmt.Errorf("failed to delete contact: %w", err)
+		}
+		if err := compactContactPriorities(txQ, others); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, txQ, auditEntry{
+			UserID:     target.UserID,
+			ContactID:  target.ContactID,
+			Action:     model.AuditActionContactLeave,
+			TargetType: "contact",
+			TargetID:   strconv.FormatInt(target.ContactID, 10),
+			Diff: model.JSONB{
+				"priority":  target.Priority,
+				"remaining": len(others),
+			},
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Logger.Info("Contact left user's contact list via portal",
+		zap.Int64("contact_id", target.ContactID),
+		zap.Int64("user_id", target.UserID),
+	)
+	return nil
+}
+
+// findPortalContact 按 contact_id 查找属于当前号码的联系人，不属于当前号码时返回 ContactLinkNotFound
+func findPortalContact(q *query.Query, phone string, contactID string) (*model.Contact, error) {
+	id, err := strconv.ParseInt(contactID, 10, 64)
+	if err != nil {
+		return nil, pkgerrors.ContactLinkNotFound
+	}
+
+	contact, err := q.Contact.
+		Where(q.Contact.ContactID.Eq(id)).
+		Where(q.Contact.PhoneHash.In(utils.PhoneHashCandidates(phone)...)).
+		First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgerrors.ContactLinkNotFound
+		}
+		return nil, fmt.Errorf("failed to query contact: %w", err)
+	}
+	return contact, nil
+}
+
+// contactPortalNicknames 批量查询用户昵称，已注销的用户不返回
+func contactPortalNicknames(q *query.Query, userIDs []int64) (map[int64]string, error) {
+	result := make(map[int64]string, len(userIDs))
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+
+	users, err := q.User.Where(q.User.ID.In(userIDs...)).Find()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query users: %w", err)
+	}
+	for _, user := range users {
+		result[user.ID] = user.Nickname
+	}
+	return result, nil
+}
+
+// contactPortalPhone 解密联系人会话 token 中的手机号
+func contactPortalPhone(phoneCipher string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(phoneCipher)
+	if err != nil {
+		return "", pkgerrors.Unauthorized
+	}
+	phone, err := utils.DecryptPhone(raw)
+	if err != nil {
+		return "", pkgerrors.Unauthorized
+	}
+	return phone, nil
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"AreYouOK/config"
+	"AreYouOK/internal/model"
+	"AreYouOK/internal/model/dto"
+	"AreYouOK/internal/repository/query"
+	pkgerrors "AreYouOK/pkg/errors"
+	"AreYouOK/pkg/logger"
+	"AreYouOK/pkg/vcard"
+	"AreYouOK/storage/database"
+	"AreYouOK/utils"
+)
+
+const (
+	contactImportMaxBytes = 256 << 10 // vCard 文本上限，通讯录导出的单张名片通常不足 1KB
+	contactImportMaxCards = 200
+
+	// contactImportRelationship 名片中没有关系信息时使用的默认值
+	contactImportRelationship = "其他"
+
+	// vCard 扩展属性，导出时写入，重新导入时恢复关系与优先级
+	vcardRelationship = "X-AYOK-RELATIONSHIP"
+	vcardPriority     = "X-AYOK-PRIORITY"
+)
+
+// 导入时跳过名片的原因
+const (
+	contactImportSkipNoMobile  = "no_mobile"
+	contactImportSkipDuplicate = "duplicate"
+	contactImportSkipSelf      = "self"
+	contactImportSkipOptedOut  = "opted_out"
+	contactImportSkipLimit     = "limit"
+)
+
+var phoneDigits = regexp.MustCompile(`[^\d+]`)
+
+// importCandidate 可以导入的名片及其在文件中的顺序
+type importCandidate struct {
+	item      dto.ReplaceContactItem
+	order     int
+	priority  int // 名片中的 X-AYOK-PRIORITY，没有时为 0
+	preferred bool
+}
+
+// ImportContacts 从 vCard 导入紧急联系人
+// 每张名片取一个大陆手机号（首选号码优先，其次标记为手机的号码），规范化后按以下顺序建议优先级：
+// 本服务导出的 X-AYOK-PRIORITY、首选名片、文件中的顺序；超出套餐上限的名片跳过
+// apply 为 false 时只返回建议；为 true 时按建议走 ReplaceContacts 全量替换，号码未变的联系人保留同意状态
+func (s *ContactService) ImportContacts(
+	ctx context.Context,
+	userID string,
+	req dto.ContactImportRequest,
+) (*dto.ContactImportResponse, error) {
+	if len(req.VCard) > contactImportMaxBytes {
+		return nil, pkgerrors.ContactImportInvalid
+	}
+
+	cards, err := vcard.Parse(req.VCard)
+	if err != nil {
+		logger.Logger.Debug("Failed to parse vcard",
+			zap.String("user_id", userID),
+			zap.Error(err),
+		)
+		return nil, pkgerrors.ContactImportInvalid
+	}
+	if len(cards) > contactImportMaxCards {
+		return nil, pkgerrors.ContactImportInvalid
+	}
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := Subscription().PlanForUser(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	q := query.Use(database.DB().WithContext(ctx))
+	result := &dto.ContactImportResponse{
+		Proposed: []dto.ReplaceContactItem{},
+		Skipped:  []dto.ContactImportSkipped{},
+	}
+
+	seen := make(map[string]bool, len(cards))
+	candidates := make([]importCandidate, 0, len(cards))
+	for i, card := range cards {
+		name := truncateRunes(card.DisplayName(), 64)
+
+		phone, preferred := pickImportPhone(card)
+		if phone == "" {
+			result.Skipped = append(result.Skipped, dto.ContactImportSkipped{
+				Name:   name,
+				Reason: contactImportSkipNoMobile,
+			})
+			continue
+		}
+
+		skip := func(reason string) {
+			result.Skipped = append(result.Skipped, dto.ContactImportSkipped{
+				Name:        name,
+				PhoneMasked: utils.MaskPhone(phone),
+				Reason:      reason,
+			})
+		}
+		if seen[phone] {
+			skip(contactImportSkipDuplicate)
+			continue
+		}
+		seen[phone] = true
+
+		if config.Cfg.Environment == "production" && user.PhoneHash != nil && utils.MatchPhoneHash(phone, *user.PhoneHash) {
+			skip(contactImportSkipSelf)
+			continue
+		}
+
+		optedOut, err := isPhoneOptedOut(q, phone)
+		if err != nil {
+			return nil, err
+		}
+		if optedOut {
+			skip(contactImportSkipOptedOut)
+			continue
+		}
+
+		priority, _ := strconv.Atoi(card.Extensions[vcardPriority])
+		candidates = append(candidates, importCandidate{
+			item: dto.ReplaceContactItem{
+				DisplayName: firstNonEmpty(name, utils.MaskPhone(phone)),
+				Relationship: truncateRunes(firstNonEmpty(
+					card.Extensions[vcardRelationship],
+					card.Role,
+					contactImportRelationship,
+				), 32),
+				Phone: phone,
+			},
+			order:     i,
+			priority:  priority,
+			preferred: preferred,
+		})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if (a.priority > 0) != (b.priority > 0) {
+			return a.priority > 0
+		}
+		if a.priority != b.priority {
+			return a.priority < b.priority
+		}
+		if a.preferred != b.preferred {
+			return a.preferred
+		}
+		return a.order < b.order
+	})
+
+	for _, candidate := range candidates {
+		if len(result.Proposed) >= plan.MaxContacts {
+			result.Skipped = append(result.Skipped, dto.ContactImportSkipped{
+				Name:        candidate.item.DisplayName,
+				PhoneMasked: utils.MaskPhone(candidate.item.Phone),
+				Reason:      contactImportSkipLimit,
+			})
+			continue
+		}
+		candidate.item.Priority = len(result.Proposed) + 1
+		result.Proposed = append(result.Proposed, candidate.item)
+	}
+
+	if !req.Apply {
+		return result, nil
+	}
+
+	contacts, err := s.ReplaceContacts(ctx, userID, dto.ReplaceContactsRequest{Contacts: result.Proposed})
+	if err != nil {
+		return nil, err
+	}
+	result.Contacts = contacts
+	result.Applied = true
+
+	logger.Logger.Info("Contacts imported from vcard",
+		zap.String("user_id", userID),
+		zap.Int("cards", len(cards)),
+		zap.Int("imported", len(contacts)),
+		zap.Int("skipped", len(result.Skipped)),
+	)
+	return result, nil
+}
+
+// ExportContacts 导出紧急联系人 vCard（3.0）
+// 当前会话完成二次验证后导出完整号码，否则号码脱敏；返回是否包含完整号码
+func (s *ContactService) ExportContacts(ctx context.Context, userID, sessionID string) ([]byte, bool, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	q := query.Use(database.DB().WithContext(ctx))
+	contacts, err := listContacts(q, user.ID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	unmasked := StepUp().IsVerified(ctx, sessionID)
+
+	cards := make([]vcard.Card, 0, len(contacts))
+	for _, contact := range contacts {
+		phone, err := decryptContactPhone(contact)
+		if err != nil {
+			logger.Logger.Warn("Failed to decrypt phone",
+				zap.String("user_id", userID),
+				zap.Int("priority", contact.Priority),
+				zap.Error(err),
+			)
+			continue
+		}
+		if !unmasked {
+			phone = utils.MaskPhone(phone)
+		}
+
+		cards = append(cards, vcard.Card{
+			FormattedName: contact.DisplayName,
+			Phones:        []vcard.Phone{{Number: phone, Preferred: contact.Priority == 1}},
+			Extensions: map[string]string{
+				vcardRelationship: contact.Relationship,
+				vcardPriority:     strconv.Itoa(contact.Priority),
+			},
+		})
+	}
+
+	sid, _ := strconv.ParseInt(sessionID, 10, 64)
+	if err := recordAudit(ctx, q, auditEntry{
+		UserID:     user.ID,
+		SessionID:  sid,
+		Action:     model.AuditActionContactExport,
+		TargetType: "contact",
+		Diff: model.JSONB{
+			"count":    len(cards),
+			"unmasked": unmasked,
+		},
+	}); err != nil {
+		return nil, false, err
+	}
+
+	return []byte(vcard.Encode(cards)), unmasked, nil
+}
+
+// pickImportPhone 从名片中选出一个大陆手机号，返回规范化后的号码与是否为首选号码
+// 顺序：首选号码、标记为手机的号码、其余号码
+func pickImportPhone(card vcard.Card) (string, bool) {
+	phones := make([]vcard.Phone, len(card.Phones))
+	copy(phones, card.Phones)
+	sort.SliceStable(phones, func(i, j int) bool {
+		if phones[i].Preferred != phones[j].Preferred {
+			return phones[i].Preferred
+		}
+		return phones[i].IsCell() && !phones[j].IsCell()
+	})
+
+	for _, phone := range phones {
+		if normalized := normalizeImportPhone(phone.Number); utils.ValidatePhone(normalized) {
+			return normalized, phone.Preferred
+		}
+	}
+	return "", false
+}
+
+// normalizeImportPhone 去掉空格、横线、括号与 +86 / 0086 国家码前缀
+func normalizeImportPhone(raw string) string {
+	phone := phoneDigits.ReplaceAllString(raw, "")
+	phone = strings.TrimPrefix(phone, "00")
+	return normalizeInboundPhone(phone)
+}
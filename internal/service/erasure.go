@@ -0,0 +1,693 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"AreYouOK/config"
+	"AreYouOK/internal/cache"
+	"AreYouOK/internal/model"
+	"AreYouOK/internal/model/dto"
+	"AreYouOK/internal/repository/query"
+	pkgerrors "AreYouOK/pkg/errors"
+	"AreYouOK/pkg/logger"
+	"AreYouOK/storage/database"
+)
+
+var (
+	erasureService *ErasureService
+	erasureOnce    sync.Once
+)
+
+func Erasure() *ErasureService {
+	erasureOnce.Do(func() {
+		erasureService = &ErasureService{}
+	})
+	return erasureService
+}
+
+// ErasureService 账号注销：注销后进入恢复窗口，窗口结束后清除个人数据
+// 恢复窗口内 users.deleted_at 非空，所有默认查询都视为用户不存在
+type ErasureService struct{}
+
+// restoreNextStep 恢复窗口内重新登录时返回给前端的下一步
+const restoreNextStep = "restore"
+
+// Request 注销账号：软删除用户，并立即取消所有待执行的提醒、行程与通知
+func (s *ErasureService) Request(ctx context.Context, userID string) error {
+	var userIDInt int64
+	if _, err := fmt.Sscanf(userID, "%d", &userIDInt); err != nil {
+		return pkgerrors.InvalidUserID
+	}
+
+	db := database.DB().WithContext(ctx)
+	q := query.Use(db)
+
+	user, err := q.User.GetByPublicID(userIDInt)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return pkgerrors.ErrUserNotFound
+		}
+		return fmt.Errorf("failed to query user: %w", err)
+	}
+
+	now := time.Now()
+	erasure := &model.AccountErasure{
+		UserID:      user.ID,
+		PublicID:    user.PublicID,
+		RequestedAt: now,
+		PurgeAfter:  now.AddDate(0, 0, config.Cfg.AccountRestoreDays),
+		Status:      model.AccountErasureStatusPending,
+		Summary:     model.JSONB{},
+	}
+
+	var exportCodes []int64
+	err = db.Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+
+		if _, err := txQ.User.
+			Where(txQ.User.ID.Eq(user.ID)).
+			Updates(map[string]interface{}{
+				"updated_at": now,
+				"deleted_at": now,
+			}); err != nil {
+			return fmt.Errorf("failed to soft delete user: %w", err)
+		}
+
+		// 进行中的行程直接取消，已投递的提醒/超时消息消费时会因状态不是 ongoing 而跳过
+		if _, err := txQ.Journey.
+			Where(txQ.Journey.UserID.Eq(user.ID)).
+			Where(txQ.Journey.Status.Eq(string(model.JourneyStatusOngoing))).
+			Updates(map[string]interface{}{
+				"status":     string(model.JourneyStatusCancelled),
+				"updated_at": now,
+			}); err != nil {
+			return fmt.Errorf("failed to cancel journeys: %w", err)
+		}
+
+		// 重复行程停止生成，尚未开始的安排删除
+		if _, err := txQ.JourneyTemplate.
+			Where(txQ.JourneyTemplate.UserID.Eq(user.ID)).
+			Updates(map[string]interface{}{
+				"next_occurrence_at": nil,
+				"updated_at":         now,
+			}); err != nil {
+			return fmt.Errorf("failed to stop journey templates: %w", err)
+		}
+		if _, err := txQ.JourneyOccurrence.Unscoped().
+			Where(txQ.JourneyOccurrence.UserID.Eq(user.ID)).
+			Where(txQ.JourneyOccurrence.Status.Eq(string(model.JourneyOccurrenceScheduled))).
+			Delete(); err != nil {
+			return fmt.Errorf("failed to delete journey occurrences: %w", err)
+		}
+
+		if _, err := txQ.NotificationTask.
+			Where(txQ.NotificationTask.UserID.Eq(user.ID)).
+			Where(txQ.NotificationTask.Status.Eq(string(model.NotificationTaskStatusPending))).
+			Updates(map[string]interface{}{
+				"status":       string(model.NotificationTaskStatusCancelled),
+				"processed_at": now,
+				"updated_at":   now,
+			}); err != nil {
+			return fmt.Errorf("failed to cancel notification tasks: %w", err)
+		}
+
+		// 未完成的数据导出作废，已生成的归档在事务提交后删除
+		if err := txQ.DataExport.
+			Where(txQ.DataExport.UserID.Eq(user.ID)).
+			Where(txQ.DataExport.Status.In(
+				string(model.DataExportStatusPending),
+				string(model.DataExportStatusProcessing),
+				string(model.DataExportStatusReady),
+			)).
+			Pluck(txQ.DataExport.ExportCode, &exportCodes); err != nil {
+			return fmt.Errorf("failed to query data exports: %w", err)
+		}
+		if len(exportCodes) > 0 {
+			if _, err := txQ.DataExport.
+				Where(txQ.DataExport.ExportCode.In(exportCodes...)).
+				Updates(map[string]interface{}{
+					"status":     string(model.DataExportStatusExpired),
+					"updated_at": now,
+				}); err != nil {
+				return fmt.Errorf("failed to expire data exports: %w", err)
+			}
+		}
+
+		if err := txQ.AccountErasure.Create(erasure); err != nil {
+			return fmt.Errorf("failed to create account erasure: %w", err)
+		}
+
+		return recordAudit(ctx, txQ, auditEntry{
+			UserID:     user.ID,
+			Action:     model.AuditActionEraseRequest,
+			TargetType: "user",
+			TargetID:   strconv.FormatInt(user.PublicID, 10),
+			Diff:       model.JSONB{"purge_after": erasure.PurgeAfter},
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, exportCode := range exportCodes {
+		if err := cache.DeleteExportArchive(ctx, exportCode); err != nil {
+			logger.Logger.Warn("Failed to delete export archive after erasure request",
+				zap.Int64("export_code", exportCode),
+				zap.Error(err),
+			)
+		}
+	}
+
+	// 写入关闭打卡的设置版本，已投递的打卡提醒消息校验时会被丢弃
+	if err := cache.SetUserSettings(ctx, user.PublicID, &cache.UserSettingsCache{
+		DailyCheckInEnabled:    false,
+		DailyCheckInRemindAt:   user.DailyCheckInRemindAt,
+		DailyCheckInDeadline:   user.DailyCheckInDeadline,
+		DailyCheckInGraceUntil: user.DailyCheckInGraceUntil,
+		UpdatedAt:              now.Unix(),
+	}); err != nil {
+		logger.Logger.Warn("Failed to update user settings cache after erasure request",
+			zap.Int64("user_id", user.ID),
+			zap.Error(err),
+		)
+	}
+
+	if err := Session().RevokeAll(ctx, user.ID, model.SessionRevokeReasonAccountErase); err != nil {
+		logger.Logger.Warn("Failed to revoke sessions after erasure request",
+			zap.Int64("user_id", user.ID),
+			zap.Error(err),
+		)
+	}
+	if err := cache.DeleteRefreshToken(ctx, userID); err != nil {
+		logger.Logger.Warn("Failed to delete legacy refresh token after erasure request",
+			zap.Int64("user_id", user.ID),
+			zap.Error(err),
+		)
+	}
+
+	logger.Logger.Info("Account erasure requested",
+		zap.Int64("user_id", user.ID),
+		zap.Int64("public_id", user.PublicID),
+		zap.Time("purge_after", erasure.PurgeAfter),
+	)
+
+	return nil
+}
+
+// Restore 恢复窗口内恢复账号（用户需重新登录后调用）
+func (s *ErasureService) Restore(ctx context.Context, userID string) (*dto.AuthUserSnapshot, error) {
+	var userIDInt int64
+	if _, err := fmt.Sscanf(userID, "%d", &userIDInt); err != nil {
+		return nil, pkgerrors.InvalidUserID
+	}
+
+	db := database.DB().WithContext(ctx)
+	q := query.Use(db)
+
+	user, err := q.User.Unscoped().Where(q.User.PublicID.Eq(userIDInt)).First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgerrors.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to query user: %w", err)
+	}
+	if !user.DeletedAt.Valid {
+		return nil, pkgerrors.AccountNotPendingErasure
+	}
+
+	erasure, err := s.pendingErasure(q, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if erasure == nil {
+		return nil, pkgerrors.AccountNotPendingErasure
+	}
+
+	now := time.Now()
+	if !now.Before(erasure.PurgeAfter) {
+		return nil, pkgerrors.AccountRestoreExpired
+	}
+
+	// 恢复窗口内手机号可能已被其他账号绑定，恢复会撞唯一索引
+	if user.PhoneHash != nil && *user.PhoneHash != "" {
+		taken, err := q.User.
+			Where(q.User.PhoneHash.In(userPhoneHashes(user)...)).
+			Where(q.User.ID.Neq(user.ID)).
+			Count()
+		if err != nil {
+			return nil, fmt.Errorf("failed to query user by phone_hash: %w", err)
+		}
+		if taken > 0 {
+			return nil, pkgerrors.PhoneAlreadyRegistered
+		}
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+
+		if _, err := txQ.User.Unscoped().
+			Where(txQ.User.ID.Eq(user.ID)).
+			Updates(map[string]interface{}{
+				"updated_at": now,
+				"deleted_at": nil,
+			}); err != nil {
+			return fmt.Errorf("failed to restore user: %w", err)
+		}
+
+		info, err := txQ.AccountErasure.
+			Where(txQ.AccountErasure.ID.Eq(erasure.ID)).
+			Where(txQ.AccountErasure.Status.Eq(string(model.AccountErasureStatusPending))).
+			Updates(map[string]interface{}{
+				"status":      string(model.AccountErasureStatusRestored),
+				"restored_at": now,
+				"updated_at":  now,
+			})
+		if err != nil {
+			return fmt.Errorf("failed to update account erasure: %w", err)
+		}
+		if info.RowsAffected == 0 {
+			return pkgerrors.AccountNotPendingErasure
+		}
+
+		return recordAudit(ctx, txQ, auditEntry{
+			UserID:     user.ID,
+			Action:     model.AuditActionRestore,
+			TargetType: "user",
+			TargetID:   strconv.FormatInt(user.PublicID, 10),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 以当前时间作为设置版本，注销前投递的提醒消息仍会被丢弃，次日调度恢复正常
+	if err := cache.SetUserSettings(ctx, user.PublicID, &cache.UserSettingsCache{
+		DailyCheckInEnabled:    user.DailyCheckInEnabled,
+		DailyCheckInRemindAt:   user.DailyCheckInRemindAt,
+		DailyCheckInDeadline:   user.DailyCheckInDeadline,
+		DailyCheckInGraceUntil: user.DailyCheckInGraceUntil,
+		UpdatedAt:              now.Unix(),
+	}); err != nil {
+		logger.Logger.Warn("Failed to update user settings cache after restore",
+			zap.Int64("user_id", user.ID),
+			zap.Error(err),
+		)
+	}
+
+	logger.Logger.Info("Account restored",
+		zap.Int64("user_id", user.ID),
+		zap.Int64("public_id", user.PublicID),
+	)
+
+	phoneVerified := user.PhoneHash != nil && *user.PhoneHash != ""
+	return &dto.AuthUserSnapshot{
+		ID:            strconv.FormatInt(user.PublicID, 10),
+		Nickname:      user.Nickname,
+		Status:        model.StatusToStringMap[user.Status],
+		PhoneVerified: phoneVerified,
+		NextStep:      resolveNextStep(user.Status, phoneVerified),
+	}, nil
+}
+
+// FindPendingUser 按登录身份 / 手机号查找仍在恢复窗口内的已注销账号
+// 登录时命中则登录到原账号并引导恢复，避免窗口内重复注册
+func (s *ErasureService) FindPendingUser(
+	ctx context.Context,
+	identity model.IdentityClaim,
+	phoneHashes []string,
+) (*model.User, *model.AccountErasure, error) {
+	q := query.Use(database.DB().WithContext(ctx))
+
+	candidates := make([]*model.User, 0, 2)
+	if identity.Subject != "" {
+		// 登录身份在清除时才删除，恢复窗口内仍可按身份找到账号
+		userIDs := q.UserIdentity.
+			Select(q.UserIdentity.UserID).
+			Where(q.UserIdentity.Provider.Eq(string(identity.Provider))).
+			Where(q.UserIdentity.Subject.Eq(identity.Subject))
+		users, err := q.User.Unscoped().
+			Where(q.User.Columns(q.User.ID).In(userIDs)).
+			Where(q.User.DeletedAt.IsNotNull()).
+			Order(q.User.DeletedAt.Desc()).
+			Find()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to query erased user by identity: %w", err)
+		}
+		candidates = append(candidates, users...)
+	}
+	if len(phoneHashes) > 0 {
+		users, err := q.User.Unscoped().
+			Where(q.User.PhoneHash.In(phoneHashes...)).
+			Where(q.User.DeletedAt.IsNotNull()).
+			Order(q.User.DeletedAt.Desc()).
+			Find()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to query erased user by phone_hash: %w", err)
+		}
+		candidates = append(candidates, users...)
+	}
+
+	now := time.Now()
+	for _, user := range candidates {
+		erasure, err := s.pendingErasure(q, user.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if erasure != nil && now.Before(erasure.PurgeAfter) {
+			return user, erasure, nil
+		}
+	}
+
+	return nil, nil, nil
+}
+
+// PurgeDue 清除恢复窗口已结束账号的个人数据（定时任务调用）
+// 返回本次清除的账号数量
+func (s *ErasureService) PurgeDue(ctx context.Context, now time.Time, limit int) (int, error) {
+	q := query.Use(database.DB().WithContext(ctx))
+
+	erasures, err := q.AccountErasure.
+		Where(q.AccountErasure.Status.Eq(string(model.AccountErasureStatusPending))).
+		Where(q.AccountErasure.PurgeAfter.Lte(now)).
+		Order(q.AccountErasure.PurgeAfter).
+		Limit(limit).
+		Find()
+	if err != nil {
+		return 0, fmt.Errorf("failed to query due account erasures: %w", err)
+	}
+
+	purged := 0
+	for _, erasure := range erasures {
+		if err := s.purge(ctx, erasure, now); err != nil {
+			logger.Logger.Error("Failed to purge account",
+				zap.Int64("erasure_id", erasure.ID),
+				zap.Int64("user_id", erasure.UserID),
+				zap.Error(err),
+			)
+			continue
+		}
+		purged++
+	}
+
+	return purged, nil
+}
+
+// purge 清除单个账号：删除行程、打卡、通知、联系人与会话，匿名化用户行
+// 额度钱包、流水、订阅与兑换记录不含个人信息，作为账务记录保留
+func (s *ErasureService) purge(ctx context.Context, erasure *model.AccountErasure, now time.Time) error {
+	db := database.DB().WithContext(ctx)
+	userID := erasure.UserID
+	summary := model.JSONB{}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+
+		var taskIDs []int64
+		if err := txQ.NotificationTask.Unscoped().
+			Where(txQ.NotificationTask.UserID.Eq(userID)).
+			Pluck(txQ.NotificationTask.ID, &taskIDs); err != nil {
+			return fmt.Errorf("failed to query notification tasks: %w", err)
+		}
+
+		if len(taskIDs) > 0 {
+			info, err := txQ.ContactAttempt.Unscoped().
+				Where(txQ.ContactAttempt.TaskID.In(taskIDs...)).
+				Delete()
+			if err != nil {
+				return fmt.Errorf("failed to delete contact attempts: %w", err)
+			}
+			summary["contact_attempts"] = info.RowsAffected
+		}
+
+		info, err := txQ.NotificationTask.Unscoped().
+			Where(txQ.NotificationTask.UserID.Eq(userID)).
+			Delete()
+		if err != nil {
+			return fmt.Errorf("failed to delete notification tasks: %w", err)
+		}
+		summary["notification_tasks"] = info.RowsAffected
+
+		info, err = txQ.DailyCheckIn.Unscoped().
+			Where(txQ.DailyCheckIn.UserID.Eq(userID)).
+			Delete()
+		if err != nil {
+			return fmt.Errorf("failed to delete daily check-ins: %w", err)
+		}
+		summary["daily_check_ins"] = info.RowsAffected
+
+		// 行程子表的外键不级联，先删除子表
+		userJourneys := txQ.Journey.Unscoped().
+			Select(txQ.Journey.ID).
+			Where(txQ.Journey.UserID.Eq(userID))
+
+		info, err = txQ.JourneyEvent.Unscoped().
+			Where(txQ.JourneyEvent.Columns(txQ.JourneyEvent.JourneyID).In(userJourneys)).
+			Delete()
+		if err != nil {
+			return fmt.Errorf("failed to delete journey events: %w", err)
+		}
+		summary["journey_events"] = info.RowsAffected
+
+		info, err = txQ.JourneyCheckpoint.Unscoped().
+			Where(txQ.JourneyCheckpoint.Columns(txQ.JourneyCheckpoint.JourneyID).In(userJourneys)).
+			Delete()
+		if err != nil {
+			return fmt.Errorf("failed to delete journey checkpoints: %w", err)
+		}
+		summary["journey_checkpoints"] = info.RowsAffected
+
+		info, err = txQ.JourneyLocation.Unscoped().
+			Where(txQ.JourneyLocation.Columns(txQ.JourneyLocation.JourneyID).In(userJourneys)).
+			Delete()
+		if err != nil {
+			return fmt.Errorf("failed to delete journey locations: %w", err)
+		}
+		summary["journey_locations"] = info.RowsAffected
+
+		info, err = txQ.JourneyOccurrence.Unscoped().
+			Where(txQ.JourneyOccurrence.UserID.Eq(userID)).
+			Delete()
+		if err != nil {
+			return fmt.Errorf("failed to delete journey occurrences: %w", err)
+		}
+		summary["journey_occurrences"] = info.RowsAffected
+
+		info, err = txQ.Journey.Unscoped().
+			Where(txQ.Journey.UserID.Eq(userID)).
+			Delete()
+		if err != nil {
+			return fmt.Errorf("failed to delete journeys: %w", err)
+		}
+		summary["journeys"] = info.RowsAffected
+
+		info, err = txQ.JourneyTemplate.Unscoped().
+			Where(txQ.JourneyTemplate.UserID.Eq(userID)).
+			Delete()
+		if err != nil {
+			return fmt.Errorf("failed to delete journey templates: %w", err)
+		}
+		summary["journey_templates"] = info.RowsAffected
+
+		info, err = txQ.UserSession.Unscoped().
+			Where(txQ.UserSession.UserID.Eq(userID)).
+			Delete()
+		if err != nil {
+			return fmt.Errorf("failed to delete user sessions: %w", err)
+		}
+		summary["user_sessions"] = info.RowsAffected
+
+		info, err = txQ.DataExport.Unscoped().
+			Where(txQ.DataExport.UserID.Eq(userID)).
+			Delete()
+		if err != nil {
+			return fmt.Errorf("failed to delete data exports: %w", err)
+		}
+		summary["data_exports"] = info.RowsAffected
+
+		info, err = txQ.PhoneChangeLog.Unscoped().
+			Where(txQ.PhoneChangeLog.UserID.Eq(userID)).
+			Delete()
+		if err != nil {
+			return fmt.Errorf("failed to delete phone change logs: %w", err)
+		}
+		summary["phone_change_logs"] = info.RowsAffected
+
+		info, err = txQ.UserIdentity.Unscoped().
+			Where(txQ.UserIdentity.UserID.Eq(userID)).
+			Delete()
+		if err != nil {
+			return fmt.Errorf("failed to delete user identities: %w", err)
+		}
+		summary["user_identities"] = info.RowsAffected
+
+		// 联系人连同已删除的历史记录一并清除
+		info, err = txQ.Contact.Unscoped().
+			Where(txQ.Contact.UserID.Eq(userID)).
+			Delete()
+		if err != nil {
+			return fmt.Errorf("failed to delete emergency contacts: %w", err)
+		}
+		summary["emergency_contacts"] = info.RowsAffected
+
+		// 共享钱包：创建者注销时解散整个组，成员注销时仅移除自己
+		info, err = txQ.WalletGroupInvitation.Unscoped().
+			Where(txQ.WalletGroupInvitation.UserID.Eq(userID)).
+			Delete()
+		if err != nil {
+			return fmt.Errorf("failed to delete wallet group invitations: %w", err)
+		}
+		summary["wallet_group_invitations"] = info.RowsAffected
+
+		member, err := txQ.WalletGroupMember.
+			Where(txQ.WalletGroupMember.UserID.Eq(userID)).
+			First()
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to query wallet group membership: %w", err)
+		}
+		if member != nil {
+			memberQuery := txQ.WalletGroupMember.Unscoped().
+				Where(txQ.WalletGroupMember.GroupID.Eq(member.GroupID))
+			if member.Role != model.WalletGroupRoleOwner {
+				memberQuery = memberQuery.Where(txQ.WalletGroupMember.UserID.Eq(userID))
+			}
+			info, err := memberQuery.Delete()
+			if err != nil {
+				return fmt.Errorf("failed to delete wallet group members: %w", err)
+			}
+			summary["wallet_group_members"] = info.RowsAffected
+
+			if member.Role == model.WalletGroupRoleOwner {
+				// 共享钱包余额按成员转入比例退回，避免解散后余额无人可用
+				refunded, err := WalletGroup().settleSharedWalletsTx(tx, member.GroupID, userID)
+				if err != nil {
+					return err
+				}
+				summary["wallet_group_refunded"] = refunded
+
+				if _, err := txQ.WalletGroupInvitation.Unscoped().
+					Where(txQ.WalletGroupInvitation.GroupID.Eq(member.GroupID)).
+					Delete(); err != nil {
+					return fmt.Errorf("failed to delete wallet group invitations: %w", err)
+				}
+
+				if _, err := txQ.WalletGroup.
+					Where(txQ.WalletGroup.ID.Eq(member.GroupID)).
+					Delete(); err != nil {
+					return fmt.Errorf("failed to delete wallet group: %w", err)
+				}
+				summary["wallet_groups"] = 1
+			}
+		}
+
+		if _, err := txQ.Subscription.
+			Where(txQ.Subscription.UserID.Eq(userID)).
+			Where(txQ.Subscription.Status.Eq(string(model.SubscriptionStatusActive))).
+			Updates(map[string]interface{}{
+				"status":     string(model.SubscriptionStatusCancelled),
+				"updated_at": now,
+			}); err != nil {
+			return fmt.Errorf("failed to cancel subscription: %w", err)
+		}
+
+		// 用户行保留（外键与账务记录引用），清空所有个人信息；open_id 改写后释放唯一索引
+		if _, err := txQ.User.Unscoped().
+			Where(txQ.User.ID.Eq(userID)).
+			Updates(map[string]interface{}{
+				"alipay_open_id":     fmt.Sprintf("purged_%d", userID),
+				"nickname":           "",
+				"phone_hash":         nil,
+				"phone_cipher":       nil,
+				"emergency_contacts": model.EmergencyContacts{},
+				"updated_at":         now,
+			}); err != nil {
+			return fmt.Errorf("failed to anonymize user: %w", err)
+		}
+
+		info, err = txQ.AccountErasure.
+			Where(txQ.AccountErasure.ID.Eq(erasure.ID)).
+			Where(txQ.AccountErasure.Status.Eq(string(model.AccountErasureStatusPending))).
+			Updates(map[string]interface{}{
+				"status":     string(model.AccountErasureStatusPurged),
+				"purged_at":  now,
+				"summary":    summary,
+				"updated_at": now,
+			})
+		if err != nil {
+			return fmt.Errorf("failed to update account erasure: %w", err)
+		}
+		if info.RowsAffected == 0 {
+			// 已被恢复或其他实例处理，回滚本次清除
+			return pkgerrors.AccountNotPendingErasure
+		}
+
+		// 审计事件不随个人数据清除，保留清除摘要
+		return recordAudit(ctx, txQ, auditEntry{
+			UserID:     userID,
+			System:     true,
+			Action:     model.AuditActionPurge,
+			TargetType: "user",
+			TargetID:   strconv.FormatInt(erasure.PublicID, 10),
+			Diff:       summary,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := cache.DeleteUserSettings(ctx, erasure.PublicID); err != nil {
+		logger.Logger.Warn("Failed to delete user settings cache after purge",
+			zap.Int64("user_id", userID),
+			zap.Error(err),
+		)
+	}
+	if err := cache.DeleteRefreshToken(ctx, strconv.FormatInt(erasure.PublicID, 10)); err != nil {
+		logger.Logger.Warn("Failed to delete refresh token after purge",
+			zap.Int64("user_id", userID),
+			zap.Error(err),
+		)
+	}
+
+	logger.Logger.Info("Account purged",
+		zap.Int64("erasure_id", erasure.ID),
+		zap.Int64("user_id", userID),
+		zap.Any("summary", summary),
+	)
+	return nil
+}
+
+func (s *ErasureService) pendingErasure(q *query.Query, userID int64) (*model.AccountErasure, error) {
+	erasure, err := q.AccountErasure.
+		Where(q.AccountErasure.UserID.Eq(userID)).
+		Where(q.AccountErasure.Status.Eq(string(model.AccountErasureStatusPending))).
+		Order(q.AccountErasure.RequestedAt.Desc()).
+		First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query account erasure: %w", err)
+	}
+	return erasure, nil
+}
+
+// pendingErasureSnapshot 恢复窗口内重新登录时返回的用户快照
+func pendingErasureSnapshot(user *model.User, erasure *model.AccountErasure) dto.AuthUserSnapshot {
+	purgeAfter := erasure.PurgeAfter
+	return dto.AuthUserSnapshot{
+		ID:                  strconv.FormatInt(user.PublicID, 10),
+		Nickname:            user.Nickname,
+		Status:              model.StatusToStringMap[user.Status],
+		PhoneVerified:       user.PhoneHash != nil && *user.PhoneHash != "",
+		NextStep:            restoreNextStep,
+		AlipayOpenID:        user.AlipayOpenID,
+		PendingErasureUntil: &purgeAfter,
+	}
+}
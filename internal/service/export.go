@@ -0,0 +1,678 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"AreYouOK/config"
+	"AreYouOK/internal/cache"
+	"AreYouOK/internal/model"
+	"AreYouOK/internal/model/dto"
+	"AreYouOK/internal/repository/query"
+	pkgerrors "AreYouOK/pkg/errors"
+	"AreYouOK/pkg/logger"
+	"AreYouOK/pkg/snowflake"
+	"AreYouOK/storage/database"
+	"AreYouOK/utils"
+)
+
+var (
+	exportService *ExportService
+	exportOnce    sync.Once
+)
+
+func Export() *ExportService {
+	exportOnce.Do(func() {
+		exportService = &ExportService{}
+	})
+	return exportService
+}
+
+// ExportService 个人数据导出
+// 归档由 worker 异步生成后暂存在 Redis，通过短时签名链接下载一次后立即删除
+type ExportService struct{}
+
+// exportArchive 归档中 data.json 的结构
+type exportArchive struct {
+	GeneratedAt       time.Time                 `json:"generated_at"`
+	Profile           exportProfile             `json:"profile"`
+	Contacts          []exportContact           `json:"contacts"`
+	CheckIns          []*model.DailyCheckIn     `json:"check_ins"`
+	Journeys          []*model.Journey          `json:"journeys"`
+	JourneyLocations  []*model.JourneyLocation  `json:"journey_locations"`
+	NotificationTasks []*model.NotificationTask `json:"notification_tasks"`
+	ContactAttempts   []*model.ContactAttempt   `json:"contact_attempts"`
+	QuotaWallets      []*model.QuotaWallet      `json:"quota_wallets"`
+	QuotaTransactions []*model.QuotaTransaction `json:"quota_transactions"`
+}
+
+type exportProfile struct {
+	PublicID               string    `json:"public_id"`
+	Nickname               string    `json:"nickname"`
+	Phone                  string    `json:"phone"`
+	Status                 string    `json:"status"`
+	Timezone               string    `json:"timezone"`
+	DailyCheckInEnabled    bool      `json:"daily_check_in_enabled"`
+	DailyCheckInRemindAt   string    `json:"daily_check_in_remind_at"`
+	DailyCheckInDeadline   string    `json:"daily_check_in_deadline"`
+	DailyCheckInGraceUntil string    `json:"daily_check_in_grace_until"`
+	JourneyAutoNotify      bool      `json:"journey_auto_notify"`
+	CreatedAt              time.Time `json:"created_at"`
+}
+
+type exportContact struct {
+	DisplayName  string `json:"display_name"`
+	Relationship string `json:"relationship"`
+	Phone        string `json:"phone"`
+	Status       string `json:"status"`
+	Priority     int    `json:"priority"`
+	CreatedAt    string `json:"created_at"`
+}
+
+// Request 发起个人数据导出，返回需要投递给 worker 的消息
+// 同一用户同时只允许一个未完成（pending/processing/ready）的导出
+func (s *ExportService) Request(
+	ctx context.Context,
+	userID string,
+	sessionID string,
+	req dto.CreateDataExportRequest,
+) (*dto.DataExportResponse, *model.UserExportMessage, error) {
+	var userIDInt int64
+	if _, err := fmt.Sscanf(userID, "%d", &userIDInt); err != nil {
+		return nil, nil, pkgerrors.InvalidUserID
+	}
+
+	db := database.DB().WithContext(ctx)
+	q := query.Use(db)
+
+	user, err := q.User.GetByPublicID(userIDInt)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, pkgerrors.ErrUserNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to query user: %w", err)
+	}
+
+	// 导出完整联系人手机号需要当前会话已完成二次验证
+	if req.FullContacts && !StepUp().IsVerified(ctx, sessionID) {
+		return nil, nil, pkgerrors.DataExportVerifyNeeded
+	}
+
+	now := time.Now()
+
+	// 归档已过期的 ready 任务不再占用名额
+	if _, err := q.DataExport.
+		Where(
+			q.DataExport.UserID.Eq(user.ID),
+			q.DataExport.Status.Eq(string(model.DataExportStatusReady)),
+			q.DataExport.ExpiresAt.Lte(now),
+		).
+		Updates(map[string]interface{}{
+			"status":     model.DataExportStatusExpired,
+			"updated_at": now,
+		}); err != nil {
+		return nil, nil, fmt.Errorf("failed to expire stale exports: %w", err)
+	}
+
+	inProgress, err := q.DataExport.
+		Where(
+			q.DataExport.UserID.Eq(user.ID),
+			q.DataExport.Status.In(
+				string(model.DataExportStatusPending),
+				string(model.DataExportStatusProcessing),
+				string(model.DataExportStatusReady),
+			),
+		).
+		Count()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to count exports: %w", err)
+	}
+	if inProgress > 0 {
+		return nil, nil, pkgerrors.DataExportInProgress
+	}
+
+	exportCode, err := snowflake.NextID(snowflake.GeneratorTypeExport)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate export ID: %w", err)
+	}
+
+	export := &model.DataExport{
+		UserID:       user.ID,
+		ExportCode:   exportCode,
+		RequestedAt:  now,
+		Status:       model.DataExportStatusPending,
+		FullContacts: req.FullContacts,
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+		if err := txQ.DataExport.Create(export); err != nil {
+			return fmt.Errorf("failed to create export: %w", err)
+		}
+
+		return recordAudit(ctx, txQ, auditEntry{
+			UserID:     user.ID,
+			Action:     model.AuditActionDataExport,
+			TargetType: "data_export",
+			TargetID:   strconv.FormatInt(exportCode, 10),
+			Diff:       model.JSONB{"full_contacts": req.FullContacts},
+		})
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	logger.Logger.Info("Data export requested",
+		zap.String("user_id", userID),
+		zap.Int64("export_code", exportCode),
+		zap.Bool("full_contacts", req.FullContacts),
+	)
+
+	msg := &model.UserExportMessage{
+		MessageID:  fmt.Sprintf("user_export_%d", exportCode),
+		ExportCode: exportCode,
+		UserID:     user.PublicID,
+	}
+
+	return s.toResponse(export, now), msg, nil
+}
+
+// Build 生成导出归档（worker 调用）
+// 任务已被处理或已不存在时返回 SkipMessageError
+func (s *ExportService) Build(ctx context.Context, exportCode int64) error {
+	db := database.DB().WithContext(ctx)
+	q := query.Use(db)
+
+	now := time.Now()
+
+	// 条件更新保证同一任务只会被一个 worker 处理
+	result, err := q.DataExport.
+		Where(
+			q.DataExport.ExportCode.Eq(exportCode),
+			q.DataExport.Status.Eq(string(model.DataExportStatusPending)),
+		).
+		Updates(map[string]interface{}{
+			"status":     model.DataExportStatusProcessing,
+			"updated_at": now,
+		})
+	if err != nil {
+		return fmt.Errorf("failed to mark export processing: %w", err)
+	}
+	if result.RowsAffected == 0 {
+		return &pkgerrors.SkipMessageError{Reason: fmt.Sprintf("export %d is not pending", exportCode)}
+	}
+
+	export, err := q.DataExport.Where(q.DataExport.ExportCode.Eq(exportCode)).First()
+	if err != nil {
+		return fmt.Errorf("failed to query export: %w", err)
+	}
+
+	archive, err := s.buildArchive(ctx, export)
+	if err != nil {
+		logger.Logger.Error("Failed to build data export archive",
+			zap.Int64("export_code", exportCode),
+			zap.Error(err),
+		)
+		if _, updateErr := q.DataExport.
+			Where(q.DataExport.ID.Eq(export.ID)).
+			Updates(map[string]interface{}{
+				"status":        model.DataExportStatusFailed,
+				"error_message": truncateErrorMessage(err.Error()),
+				"updated_at":    time.Now(),
+			}); updateErr != nil {
+			return fmt.Errorf("failed to mark export failed: %w", updateErr)
+		}
+		return &pkgerrors.SkipMessageError{Reason: fmt.Sprintf("export %d failed: %v", exportCode, err)}
+	}
+
+	retain := time.Duration(config.Cfg.DataExportRetainHours) * time.Hour
+	if err := cache.SetExportArchive(ctx, exportCode, archive, retain); err != nil {
+		// 回滚为 pending，交给消息重试
+		_, _ = q.DataExport.
+			Where(q.DataExport.ID.Eq(export.ID)).
+			Updates(map[string]interface{}{
+				"status":     model.DataExportStatusPending,
+				"updated_at": time.Now(),
+			})
+		return fmt.Errorf("failed to store export archive: %w", err)
+	}
+
+	readyAt := time.Now()
+	if _, err := q.DataExport.
+		Where(q.DataExport.ID.Eq(export.ID)).
+		Updates(map[string]interface{}{
+			"status":     model.DataExportStatusReady,
+			"ready_at":   readyAt,
+			"expires_at": readyAt.Add(retain),
+			"size_bytes": len(archive),
+			"updated_at": readyAt,
+		}); err != nil {
+		return fmt.Errorf("failed to mark export ready: %w", err)
+	}
+
+	logger.Logger.Info("Data export ready",
+		zap.Int64("export_code", exportCode),
+		zap.Int("size_bytes", len(archive)),
+	)
+
+	return nil
+}
+
+// MarkFailed 将未开始处理的导出标记为失败（任务投递失败时调用），避免占用导出名额
+func (s *ExportService) MarkFailed(ctx context.Context, exportCode int64, reason string) error {
+	db := database.DB().WithContext(ctx)
+	q := query.Use(db)
+
+	if _, err := q.DataExport.
+		Where(
+			q.DataExport.ExportCode.Eq(exportCode),
+			q.DataExport.Status.Eq(string(model.DataExportStatusPending)),
+		).
+		Updates(map[string]interface{}{
+			"status":        model.DataExportStatusFailed,
+			"error_message": truncateErrorMessage(reason),
+			"updated_at":    time.Now(),
+		}); err != nil {
+		return fmt.Errorf("failed to mark export failed: %w", err)
+	}
+	return nil
+}
+
+// GetStatus 查询导出任务状态，ready 时附带短时有效的下载链接
+func (s *ExportService) GetStatus(ctx context.Context, userID string, exportID string) (*dto.DataExportResponse, error) {
+	var userIDInt int64
+	if _, err := fmt.Sscanf(userID, "%d", &userIDInt); err != nil {
+		return nil, pkgerrors.InvalidUserID
+	}
+
+	exportCode, err := strconv.ParseInt(exportID, 10, 64)
+	if err != nil {
+		return nil, pkgerrors.DataExportNotFound
+	}
+
+	db := database.DB().WithContext(ctx)
+	q := query.Use(db)
+
+	user, err := q.User.GetByPublicID(userIDInt)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgerrors.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to query user: %w", err)
+	}
+
+	export, err := q.DataExport.
+		Where(q.DataExport.ExportCode.Eq(exportCode), q.DataExport.UserID.Eq(user.ID)).
+		First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgerrors.DataExportNotFound
+		}
+		return nil, fmt.Errorf("failed to query export: %w", err)
+	}
+
+	now := time.Now()
+	if export.Status == model.DataExportStatusReady && export.ExpiresAt != nil && !now.Before(*export.ExpiresAt) {
+		if _, err := q.DataExport.
+			Where(q.DataExport.ID.Eq(export.ID), q.DataExport.Status.Eq(string(model.DataExportStatusReady))).
+			Updates(map[string]interface{}{
+				"status":     model.DataExportStatusExpired,
+				"updated_at": now,
+			}); err != nil {
+			return nil, fmt.Errorf("failed to mark export expired: %w", err)
+		}
+		export.Status = model.DataExportStatusExpired
+	}
+
+	return s.toResponse(export, now), nil
+}
+
+// Download 校验签名链接并取出归档，归档取出后即从 Redis 删除
+// 返回归档内容与文件名
+func (s *ExportService) Download(ctx context.Context, exportID, expires, signature string) ([]byte, string, error) {
+	exportCode, err := strconv.ParseInt(exportID, 10, 64)
+	if err != nil {
+		return nil, "", pkgerrors.DataExportLinkInvalid
+	}
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return nil, "", pkgerrors.DataExportLinkInvalid
+	}
+
+	now := time.Now()
+	if now.Unix() >= expiresAt || !utils.VerifyExportDownload(exportCode, expiresAt, signature) {
+		return nil, "", pkgerrors.DataExportLinkInvalid
+	}
+
+	db := database.DB().WithContext(ctx)
+	q := query.Use(db)
+
+	export, err := q.DataExport.Where(q.DataExport.ExportCode.Eq(exportCode)).First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", pkgerrors.DataExportNotFound
+		}
+		return nil, "", fmt.Errorf("failed to query export: %w", err)
+	}
+	if export.Status != model.DataExportStatusReady {
+		return nil, "", pkgerrors.DataExportUnavailable
+	}
+
+	archive, err := cache.TakeExportArchive(ctx, exportCode)
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			// 归档已被取走或 TTL 到期
+			if _, updateErr := q.DataExport.
+				Where(q.DataExport.ID.Eq(export.ID), q.DataExport.Status.Eq(string(model.DataExportStatusReady))).
+				Updates(map[string]interface{}{
+					"status":     model.DataExportStatusExpired,
+					"updated_at": now,
+				}); updateErr != nil {
+				logger.Logger.Warn("Failed to mark export expired",
+					zap.Int64("export_code", exportCode),
+					zap.Error(updateErr),
+				)
+			}
+			return nil, "", pkgerrors.DataExportUnavailable
+		}
+		return nil, "", fmt.Errorf("failed to get export archive: %w", err)
+	}
+
+	if _, err := q.DataExport.
+		Where(q.DataExport.ID.Eq(export.ID)).
+		Updates(map[string]interface{}{
+			"status":        model.DataExportStatusDownloaded,
+			"downloaded_at": now,
+			"updated_at":    now,
+		}); err != nil {
+		// 归档已删除，状态更新失败不影响本次下载
+		logger.Logger.Warn("Failed to mark export downloaded",
+			zap.Int64("export_code", exportCode),
+			zap.Error(err),
+		)
+	}
+
+	logger.Logger.Info("Data export downloaded",
+		zap.Int64("export_code", exportCode),
+	)
+
+	return archive, fmt.Sprintf("areyouok-export-%d.zip", exportCode), nil
+}
+
+// toResponse 转换为响应，ready 状态生成签名下载链接
+func (s *ExportService) toResponse(export *model.DataExport, now time.Time) *dto.DataExportResponse {
+	resp := &dto.DataExportResponse{
+		ID:           strconv.FormatInt(export.ExportCode, 10),
+		Status:       string(export.Status),
+		FullContacts: export.FullContacts,
+		RequestedAt:  export.RequestedAt,
+		ReadyAt:      export.ReadyAt,
+		ExpiresAt:    export.ExpiresAt,
+		SizeBytes:    export.SizeBytes,
+	}
+
+	if export.Status == model.DataExportStatusReady {
+		urlExpiresAt := now.Add(time.Duration(config.Cfg.DataExportURLMinutes) * time.Minute)
+		if export.ExpiresAt != nil && export.ExpiresAt.Before(urlExpiresAt) {
+			urlExpiresAt = *export.ExpiresAt
+		}
+		expires := urlExpiresAt.Unix()
+
+		params := url.Values{}
+		params.Set("expires", strconv.FormatInt(expires, 10))
+		params.Set("signature", utils.SignExportDownload(export.ExportCode, expires))
+
+		resp.DownloadURL = fmt.Sprintf("/v1/exports/%d/download?%s", export.ExportCode, params.Encode())
+		resp.URLExpiresAt = &urlExpiresAt
+	}
+
+	return resp
+}
+
+// buildArchive 汇总用户数据并打包为 zip：data.json 包含全部数据，各类记录另附 CSV
+func (s *ExportService) buildArchive(ctx context.Context, export *model.DataExport) ([]byte, error) {
+	db := database.DB().WithContext(ctx)
+	q := query.Use(db)
+
+	user, err := q.User.Where(q.User.ID.Eq(export.UserID)).First()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user: %w", err)
+	}
+
+	data := exportArchive{
+		GeneratedAt: time.Now(),
+		Profile: exportProfile{
+			PublicID:               strconv.FormatInt(user.PublicID, 10),
+			Nickname:               user.Nickname,
+			Status:                 string(user.Status),
+			Timezone:               user.Timezone,
+			DailyCheckInEnabled:    user.DailyCheckInEnabled,
+			DailyCheckInRemindAt:   user.DailyCheckInRemindAt,
+			DailyCheckInDeadline:   user.DailyCheckInDeadline,
+			DailyCheckInGraceUntil: user.DailyCheckInGraceUntil,
+			JourneyAutoNotify:      user.JourneyAutoNotify,
+			CreatedAt:              user.CreatedAt,
+		},
+	}
+
+	if len(user.PhoneCipher) > 0 {
+		if phone, err := utils.DecryptPhone(user.PhoneCipher); err == nil {
+			data.Profile.Phone = phone
+		}
+	}
+
+	contacts, err := listContacts(q, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, contact := range contacts {
+		item := exportContact{
+			DisplayName:  contact.DisplayName,
+			Relationship: contact.Relationship,
+			Status:       string(contact.Status),
+			Priority:     contact.Priority,
+			CreatedAt:    contact.CreatedAt.Format(time.RFC3339),
+		}
+		if phone, err := utils.DecryptPhone(contact.PhoneCipher); err == nil {
+			if export.FullContacts {
+				item.Phone = phone
+			} else {
+				item.Phone = utils.MaskPhone(phone)
+			}
+		}
+		data.Contacts = append(data.Contacts, item)
+	}
+
+	if data.CheckIns, err = q.DailyCheckIn.
+		Where(q.DailyCheckIn.UserID.Eq(user.ID)).
+		Order(q.DailyCheckIn.CheckInDate).
+		Find(); err != nil {
+		return nil, fmt.Errorf("failed to query check-ins: %w", err)
+	}
+
+	if data.Journeys, err = q.Journey.
+		Where(q.Journey.UserID.Eq(user.ID)).
+		Order(q.Journey.CreatedAt).
+		Find(); err != nil {
+		return nil, fmt.Errorf("failed to query journeys: %w", err)
+	}
+
+	if data.JourneyLocations, err = q.JourneyLocation.
+		Where(q.JourneyLocation.Columns(q.JourneyLocation.JourneyID).In(
+			q.Journey.Select(q.Journey.ID).Where(q.Journey.UserID.Eq(user.ID)),
+		)).
+		Order(q.JourneyLocation.JourneyID, q.JourneyLocation.RecordedAt).
+		Find(); err != nil {
+		return nil, fmt.Errorf("failed to query journey locations: %w", err)
+	}
+
+	if data.NotificationTasks, err = q.NotificationTask.
+		Where(q.NotificationTask.UserID.Eq(user.ID)).
+		Order(q.NotificationTask.ScheduledAt).
+		Find(); err != nil {
+		return nil, fmt.Errorf("failed to query notification tasks: %w", err)
+	}
+
+	if len(data.NotificationTasks) > 0 {
+		taskIDs := make([]int64, 0, len(data.NotificationTasks))
+		for _, task := range data.NotificationTasks {
+			taskIDs = append(taskIDs, task.ID)
+		}
+		if data.ContactAttempts, err = q.ContactAttempt.
+			Where(q.ContactAttempt.TaskID.In(taskIDs...)).
+			Order(q.ContactAttempt.AttemptedAt).
+			Find(); err != nil {
+			return nil, fmt.Errorf("failed to query contact attempts: %w", err)
+		}
+	}
+
+	if data.QuotaWallets, err = q.QuotaWallet.
+		Where(q.QuotaWallet.UserID.Eq(user.ID), q.QuotaWallet.GroupID.IsNull()).
+		Find(); err != nil {
+		return nil, fmt.Errorf("failed to query quota wallets: %w", err)
+	}
+
+	if data.QuotaTransactions, err = q.QuotaTransaction.
+		Where(q.QuotaTransaction.UserID.Eq(user.ID)).
+		Order(q.QuotaTransaction.CreatedAt).
+		Find(); err != nil {
+		return nil, fmt.Errorf("failed to query quota transactions: %w", err)
+	}
+
+	return writeExportZip(&data)
+}
+
+// writeExportZip 写出 zip 归档
+func writeExportZip(data *exportArchive) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+
+	jsonBytes, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal export data: %w", err)
+	}
+	w, err := zw.Create("data.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create data.json: %w", err)
+	}
+	if _, err := w.Write(jsonBytes); err != nil {
+		return nil, fmt.Errorf("failed to write data.json: %w", err)
+	}
+
+	files := []struct {
+		name   string
+		header []string
+		rows   [][]string
+	}{
+		{
+			name:   "profile.csv",
+			header: []string{"public_id", "nickname", "phone", "status", "timezone", "daily_check_in_enabled", "daily_check_in_remind_at", "daily_check_in_deadline", "daily_check_in_grace_until", "journey_auto_notify", "created_at"},
+			rows: [][]string{{
+				data.Profile.PublicID, data.Profile.Nickname, data.Profile.Phone, data.Profile.Status, data.Profile.Timezone,
+				strconv.FormatBool(data.Profile.DailyCheckInEnabled), data.Profile.DailyCheckInRemindAt,
+				data.Profile.DailyCheckInDeadline, data.Profile.DailyCheckInGraceUntil,
+				strconv.FormatBool(data.Profile.JourneyAutoNotify), formatExportTime(&data.Profile.CreatedAt),
+			}},
+		},
+		{
+			name:   "contacts.csv",
+			header: []string{"priority", "display_name", "relationship", "phone", "created_at"},
+			rows: exportRows(data.Contacts, func(c exportContact) []string {
+				return []string{strconv.Itoa(c.Priority), c.DisplayName, c.Relationship, c.Phone, c.CreatedAt}
+			}),
+		},
+		{
+			name:   "check_ins.csv",
+			header: []string{"check_in_date", "status", "check_in_at", "reminder_sent_at", "alert_triggered_at"},
+			rows: exportRows(data.CheckIns, func(c *model.DailyCheckIn) []string {
+				return []string{c.CheckInDate.Format("2006-01-02"), string(c.Status), formatExportTime(c.CheckInAt), formatExportTime(c.ReminderSentAt), formatExportTime(c.AlertTriggeredAt)}
+			}),
+		},
+		{
+			name:   "journeys.csv",
+			header: []string{"id", "title", "note", "status", "expected_return_time", "actual_return_time", "alert_status", "alert_triggered_at", "created_at"},
+			rows: exportRows(data.Journeys, func(j *model.Journey) []string {
+				return []string{strconv.FormatInt(j.ID, 10), j.Title, j.Note, string(j.Status), formatExportTime(&j.ExpectedReturnTime), formatExportTime(j.ActualReturnTime), string(j.AlertStatus), formatExportTime(j.AlertTriggeredAt), formatExportTime(&j.CreatedAt)}
+			}),
+		},
+		{
+			name:   "journey_locations.csv",
+			header: []string{"journey_id", "recorded_at", "latitude", "longitude", "accuracy"},
+			rows: exportRows(data.JourneyLocations, func(l *model.JourneyLocation) []string {
+				return []string{strconv.FormatInt(l.JourneyID, 10), formatExportTime(&l.RecordedAt), strconv.FormatFloat(l.Latitude, 'f', -1, 64), strconv.FormatFloat(l.Longitude, 'f', -1, 64), strconv.FormatFloat(l.Accuracy, 'f', -1, 64)}
+			}),
+		},
+		{
+			name:   "notification_tasks.csv",
+			header: []string{"id", "task_code", "category", "channel", "status", "contact_priority", "scheduled_at", "processed_at", "cost_cents"},
+			rows: exportRows(data.NotificationTasks, func(t *model.NotificationTask) []string {
+				priority := ""
+				if t.ContactPriority != nil {
+					priority = strconv.Itoa(*t.ContactPriority)
+				}
+				return []string{strconv.FormatInt(t.ID, 10), strconv.FormatInt(t.TaskCode, 10), string(t.Category), string(t.Channel), string(t.Status), priority, formatExportTime(&t.ScheduledAt), formatExportTime(t.ProcessedAt), strconv.Itoa(t.CostCents)}
+			}),
+		},
+		{
+			name:   "contact_attempts.csv",
+			header: []string{"task_id", "contact_priority", "channel", "status", "attempted_at", "cost_cents"},
+			rows: exportRows(data.ContactAttempts, func(a *model.ContactAttempt) []string {
+				return []string{strconv.FormatInt(a.TaskID, 10), strconv.Itoa(a.ContactPriority), string(a.Channel), string(a.Status), formatExportTime(&a.AttemptedAt), strconv.Itoa(a.CostCents)}
+			}),
+		},
+		{
+			name:   "quota_transactions.csv",
+			header: []string{"created_at", "channel", "transaction_type", "reason", "amount", "balance_after", "wallet_id"},
+			rows: exportRows(data.QuotaTransactions, func(t *model.QuotaTransaction) []string {
+				return []string{formatExportTime(&t.CreatedAt), string(t.Channel), string(t.TransactionType), t.Reason, strconv.Itoa(t.Amount), strconv.Itoa(t.BalanceAfter), strconv.FormatInt(t.WalletID, 10)}
+			}),
+		},
+	}
+
+	for _, f := range files {
+		w, err := zw.Create(f.name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", f.name, err)
+		}
+		cw := csv.NewWriter(w)
+		if err := cw.Write(f.header); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", f.name, err)
+		}
+		if err := cw.WriteAll(f.rows); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", f.name, err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close export archive: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func exportRows[T any](items []T, toRow func(T) []string) [][]string {
+	rows := make([][]string, 0, len(items))
+	for _, item := range items {
+		rows = append(rows, toRow(item))
+	}
+	return rows
+}
+
+func formatExportTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
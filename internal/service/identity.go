@@ -0,0 +1,326 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"AreYouOK/internal/model"
+	"AreYouOK/internal/model/dto"
+	"AreYouOK/internal/repository/query"
+	pkgerrors "AreYouOK/pkg/errors"
+	"AreYouOK/pkg/logger"
+	"AreYouOK/pkg/wechat"
+	"AreYouOK/storage/database"
+	"AreYouOK/utils"
+)
+
+var (
+	identityService *IdentityService
+	identityOnce    sync.Once
+)
+
+func Identity() *IdentityService {
+	identityOnce.Do(func() {
+		identityService = &IdentityService{}
+	})
+	return identityService
+}
+
+// IdentityService 管理账号绑定的登录身份，每个平台最多绑定一个身份
+// 身份已属于其他账号时需通过账号合并处理，见 AccountMergeService
+type IdentityService struct{}
+
+// List 列出当前账号绑定的登录身份
+func (s *IdentityService) List(ctx context.Context, userID string) ([]dto.IdentityItem, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	q := query.Use(database.DB().WithContext(ctx))
+	return listIdentities(q, user.ID)
+}
+
+// Link 为当前账号绑定另一个平台的登录身份，code 由服务端换取身份作为归属证明
+func (s *IdentityService) Link(ctx context.Context, userID string, req dto.LinkIdentityRequest) ([]dto.IdentityItem, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	claim, err := resolveIdentityClaim(ctx, req.Provider, req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	db := database.DB().WithContext(ctx)
+	q := query.Use(db)
+
+	existing, err := q.UserIdentity.
+		Where(q.UserIdentity.Provider.Eq(string(claim.Provider))).
+		Where(q.UserIdentity.Subject.Eq(claim.Subject)).
+		First()
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to query user identity: %w", err)
+	}
+	if existing != nil {
+		if existing.UserID != user.ID {
+			return nil, pkgerrors.IdentityLinkedElsewhere
+		}
+		return listIdentities(q, user.ID)
+	}
+
+	linked, err := hasProviderIdentity(q, user.ID, claim.Provider)
+	if err != nil {
+		return nil, err
+	}
+	if linked {
+		return nil, pkgerrors.IdentityAlreadyLinked
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+
+		if err := bindIdentity(txQ, user.ID, claim); err != nil {
+			return err
+		}
+		if claim.Provider == model.IdentityProviderAlipay {
+			if _, err := txQ.User.Where(txQ.User.ID.Eq(user.ID)).Update(txQ.User.AlipayOpenID, claim.Subject); err != nil {
+				return fmt.Errorf("failed to update alipay_open_id: %w", err)
+			}
+		}
+
+		return recordAudit(ctx, txQ, auditEntry{
+			UserID:     user.ID,
+			Action:     model.AuditActionIdentityLink,
+			TargetType: "identity",
+			TargetID:   string(claim.Provider),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Logger.Info("User identity linked",
+		zap.Int64("user_id", user.ID),
+		zap.String("provider", string(claim.Provider)),
+	)
+
+	return listIdentities(q, user.ID)
+}
+
+// Unlink 解绑当前账号某个平台的登录身份，身份记录直接删除，之后可被其他账号绑定
+// 账号至少保留一个登录身份
+func (s *IdentityService) Unlink(ctx context.Context, userID string, provider string) error {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	identityProvider, err := parseIdentityProvider(provider)
+	if err != nil {
+		return err
+	}
+
+	db := database.DB().WithContext(ctx)
+	q := query.Use(db)
+
+	identities, err := q.UserIdentity.Where(q.UserIdentity.UserID.Eq(user.ID)).Find()
+	if err != nil {
+		return fmt.Errorf("failed to query user identities: %w", err)
+	}
+
+	found := false
+	for _, identity := range identities {
+		if identity.Provider == identityProvider {
+			found = true
+		}
+	}
+	if !found {
+		return pkgerrors.IdentityNotFound
+	}
+	if len(identities) <= 1 {
+		return pkgerrors.IdentityLastRemaining
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+
+		if _, err := txQ.UserIdentity.Unscoped().
+			Where(txQ.UserIdentity.UserID.Eq(user.ID)).
+			Where(txQ.UserIdentity.Provider.Eq(string(identityProvider))).
+			Delete(); err != nil {
+			return fmt.Errorf("failed to delete user identity: %w", err)
+		}
+		if identityProvider == model.IdentityProviderAlipay {
+			if _, err := txQ.User.Where(txQ.User.ID.Eq(user.ID)).Update(txQ.User.AlipayOpenID, ""); err != nil {
+				return fmt.Errorf("failed to clear alipay_open_id: %w", err)
+			}
+		}
+
+		return recordAudit(ctx, txQ, auditEntry{
+			UserID:     user.ID,
+			Action:     model.AuditActionIdentityUnlink,
+			TargetType: "identity",
+			TargetID:   string(identityProvider),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Logger.Info("User identity unlinked",
+		zap.Int64("user_id", user.ID),
+		zap.String("provider", string(identityProvider)),
+	)
+	return nil
+}
+
+func (s *IdentityService) getUser(ctx context.Context, userID string) (*model.User, error) {
+	var userIDInt int64
+	if _, err := fmt.Sscanf(userID, "%d", &userIDInt); err != nil {
+		return nil, pkgerrors.InvalidUserID
+	}
+
+	q := query.Use(database.DB().WithContext(ctx))
+	user, err := q.User.GetByPublicID(userIDInt)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgerrors.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to query user: %w", err)
+	}
+	return user, nil
+}
+
+// parseIdentityProvider 校验客户端传入的平台名称
+func parseIdentityProvider(provider string) (model.IdentityProvider, error) {
+	switch p := model.IdentityProvider(provider); p {
+	case model.IdentityProviderAlipay, model.IdentityProviderWechat:
+		return p, nil
+	default:
+		return "", pkgerrors.IdentityProviderInvalid
+	}
+}
+
+// resolveIdentityClaim 用平台授权码换取登录身份，作为绑定或合并时的归属证明
+func resolveIdentityClaim(ctx context.Context, provider string, code string) (model.IdentityClaim, error) {
+	identityProvider, err := parseIdentityProvider(provider)
+	if err != nil {
+		return model.IdentityClaim{}, err
+	}
+
+	claim := model.IdentityClaim{Provider: identityProvider}
+	switch identityProvider {
+	case model.IdentityProviderAlipay:
+		openID, err := utils.ExchangeAlipayAuthCode(ctx, code)
+		if err != nil || openID == "" {
+			logger.Logger.Warn("Failed to exchange alipay auth code for identity", zap.Error(err))
+			return claim, pkgerrors.IdentityProofInvalid
+		}
+		claim.Subject = openID
+	case model.IdentityProviderWechat:
+		session, err := wechat.Code2Session(ctx, code)
+		if err != nil {
+			logger.Logger.Warn("WeChat code2session failed for identity", zap.Error(err))
+			return claim, pkgerrors.IdentityProofInvalid
+		}
+		claim.Subject = session.OpenID
+		claim.UnionID = session.UnionID
+	}
+	return claim, nil
+}
+
+// listIdentities 查询账号绑定的登录身份，按平台名称排序
+func listIdentities(q *query.Query, userID int64) ([]dto.IdentityItem, error) {
+	identities, err := q.UserIdentity.Where(q.UserIdentity.UserID.Eq(userID)).Find()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user identities: %w", err)
+	}
+
+	items := make([]dto.IdentityItem, 0, len(identities))
+	for _, identity := range identities {
+		items = append(items, dto.IdentityItem{
+			Provider: string(identity.Provider),
+			LinkedAt: identity.CreatedAt,
+		})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Provider < items[j].Provider
+	})
+	return items, nil
+}
+
+// findUserByIdentity 按登录身份查找账号，身份未绑定或账号已注销时返回 nil
+func findUserByIdentity(q *query.Query, claim model.IdentityClaim) (*model.User, error) {
+	identity, err := q.UserIdentity.
+		Where(q.UserIdentity.Provider.Eq(string(claim.Provider))).
+		Where(q.UserIdentity.Subject.Eq(claim.Subject)).
+		First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query user identity: %w", err)
+	}
+
+	user, err := q.User.Where(q.User.ID.Eq(identity.UserID)).First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query user by identity: %w", err)
+	}
+	return user, nil
+}
+
+// hasProviderIdentity 账号是否已绑定该平台的身份
+func hasProviderIdentity(q *query.Query, userID int64, provider model.IdentityProvider) (bool, error) {
+	count, err := q.UserIdentity.
+		Where(q.UserIdentity.UserID.Eq(userID)).
+		Where(q.UserIdentity.Provider.Eq(string(provider))).
+		Count()
+	if err != nil {
+		return false, fmt.Errorf("failed to query user identity: %w", err)
+	}
+	return count > 0, nil
+}
+
+// bindIdentity 为账号写入登录身份，已存在时忽略
+// 支付宝身份需要调用方同步写入 users.alipay_open_id
+func bindIdentity(q *query.Query, userID int64, claim model.IdentityClaim) error {
+	if claim.Subject == "" {
+		return nil
+	}
+
+	exists, err := q.UserIdentity.
+		Where(q.UserIdentity.Provider.Eq(string(claim.Provider))).
+		Where(q.UserIdentity.Subject.Eq(claim.Subject)).
+		Count()
+	if err != nil {
+		return fmt.Errorf("failed to query user identity: %w", err)
+	}
+	if exists > 0 {
+		return nil
+	}
+
+	identity := &model.UserIdentity{
+		UserID:   userID,
+		Provider: claim.Provider,
+		Subject:  claim.Subject,
+	}
+	if claim.UnionID != "" {
+		identity.UnionID = &claim.UnionID
+	}
+
+	if err := q.UserIdentity.Create(identity); err != nil {
+		return fmt.Errorf("failed to create user identity: %w", err)
+	}
+	return nil
+}
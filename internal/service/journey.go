@@ -1,6 +1,7 @@
 package service
 
 import (
+	"AreYouOK/internal/cache"
 	"AreYouOK/internal/model"
 	"AreYouOK/internal/model/dto"
 	"AreYouOK/internal/repository/query"
@@ -10,7 +11,6 @@ import (
 	"AreYouOK/storage/database"
 	"context"
 	"fmt"
-	"sort"
 	"strconv"
 	"sync"
 	"time"
@@ -70,12 +70,37 @@ func (s *JourneyService) CreateJourney(
 	}
 	// 这里还需要考虑要不要限定前几分钟不能修改？
 
+	// 超时只通知选定的联系人，留言代替备注发给联系人
+	contacts, err := normalizeContactPriorities(ctx, user.ID, req.ContactPriorities)
+	if err != nil {
+		return nil, nil, err
+	}
+	alertMessage, err := normalizeJourneyAlertMessage(req.AlertMessage)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	journeyID, err := snowflake.NextID(snowflake.GeneratorTypeJourney)
 
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to generate journey ID: %w", err)
 	}
 
+	checkpoints, err := normalizeJourneyCheckpoints(journeyID, req.Checkpoints, req.ExpectedReturnTime, now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 超时消息与行程一起生成，行程记录当前有效的 message_id
+	messageID, timeoutMsg, err := prepareJourneyTimeout(journeyID, userID, req.ExpectedReturnTime, user.JourneyAutoNotify, now)
+	if err != nil {
+		logger.Logger.Error("Failed to prepare journey timeout message",
+			zap.Int64("journey_id", journeyID),
+			zap.Error(err),
+		)
+		// 不阻塞主流程，由定时任务投递
+	}
+
 	journey := &model.Journey{
 		BaseModel: model.BaseModel{
 			ID: journeyID,
@@ -87,10 +112,37 @@ func (s *JourneyService) CreateJourney(
 		Status:             model.JourneyStatusOngoing,
 		AlertStatus:        model.AlertStatusPending,
 		AlertAttempts:      0,
+		TimeoutMessageID:   &messageID,
+		ContactPriorities:  contacts,
+		AlertMessage:       alertMessage,
+	}
+	if req.Destination != nil {
+		dest, err := resolveJourneyDestination(user, req.Destination)
+		if err != nil {
+			return nil, nil, err
+		}
+		lat, lon := dest["dest_latitude"].(float64), dest["dest_longitude"].(float64)
+		journey.DestLatitude = &lat
+		journey.DestLongitude = &lon
+		journey.DestRadius = dest["dest_radius"].(int)
 	}
 
-	// journey 的 create 和消息的投放应该是一个事务吗，应该是完整的部分才对
-	if err := q.Journey.Create(journey); err != nil {
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+		if err := txQ.Journey.Create(journey); err != nil {
+			return err
+		}
+		if len(checkpoints) > 0 {
+			if err := txQ.JourneyCheckpoint.Create(checkpoints...); err != nil {
+				return err
+			}
+		}
+		return recordJourneyEvent(txQ, journeyID, model.JourneyEventCreated, model.JSONB{
+			"expected_return_time": journey.ExpectedReturnTime.Format(time.RFC3339),
+			"checkpoints":          len(checkpoints),
+			"geofence":             journey.DestRadius > 0,
+		}, now)
+	}); err != nil {
 		logger.Logger.Error("Failed to create journey",
 			zap.Int64("user_id", user.ID),
 			zap.Error(err),
@@ -98,59 +150,6 @@ func (s *JourneyService) CreateJourney(
 		return nil, nil, fmt.Errorf("failed to create journey: %w", err)
 	}
 
-	// 开始集成延迟消息的投放，这里的判断消息投递
-
-	// 1. 检查用户设置 journey_auto_notify
-	// 2. 如果开启，构建通知消息（但不发布，返回给调用方）
-
-	var timeoutMsg *model.JourneyTimeoutMessage
-
-	if user.JourneyAutoNotify {
-		delayTime := journey.ExpectedReturnTime.Add(10 * time.Minute)
-		now := time.Now()
-
-		if delayTime.After(now) {
-			delay := delayTime.Sub(now)
-
-			// 如果延迟时间 <= 1天，构建消息返回给调用方
-			if delay <= 24*time.Hour {
-				messageID, err := snowflake.NextID(snowflake.GeneratorTypeMessage)
-				if err != nil {
-					logger.Logger.Error("Failed to generate message ID",
-						zap.Int64("journey_id", journeyID),
-						zap.Error(err),
-					)
-					// 不阻塞主流程，继续向下执行
-				} else {
-					timeoutMsg = &model.JourneyTimeoutMessage{
-						MessageID:    fmt.Sprintf("journey_timeout_%d", messageID),
-						ScheduledAt:  now.Format(time.RFC3339),
-						JourneyID:    journeyID,
-						UserID:       userID,
-						DelaySeconds: int(delay.Seconds()),
-					}
-					logger.Logger.Info("Journey timeout message prepared",
-						zap.Int64("journey_id", journeyID),
-						zap.Int64("user_id", userID),
-						zap.Time("expected_return_time", journey.ExpectedReturnTime),
-					)
-				}
-			} else {
-				logger.Logger.Info("Journey delay exceeds 24 hours, will be handled by scheduled task",
-					zap.Int64("journey_id", journeyID),
-					zap.Int64("user_id", userID),
-					zap.Duration("delay", delay),
-					zap.Time("expected_return_time", journey.ExpectedReturnTime),
-				)
-			}
-		} else {
-			logger.Logger.Warn("Journey expected return time is in the past",
-				zap.Int64("journey_id", journeyID),
-				zap.Time("expected_return_time", journey.ExpectedReturnTime),
-			)
-		}
-	}
-
 	logger.Logger.Info("Journey created",
 		zap.Int64("journey_id", journeyID),
 		zap.Int64("user_id", user.ID),
@@ -209,6 +208,33 @@ func (s *JourneyService) UpdateJourney(
 	if req.Note != nil {
 		updates["note"] = *req.Note
 	}
+	if req.ContactPriorities != nil {
+		contacts, err := normalizeContactPriorities(ctx, journey.UserID, *req.ContactPriorities)
+		if err != nil {
+			return nil, nil, err
+		}
+		updates["contact_priorities"] = contacts
+	}
+	if req.AlertMessage != nil {
+		alertMessage, err := normalizeJourneyAlertMessage(*req.AlertMessage)
+		if err != nil {
+			return nil, nil, err
+		}
+		updates["alert_message"] = alertMessage
+	}
+	if req.Destination != nil {
+		user, err := q.User.GetByID(journey.UserID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to query user: %w", err)
+		}
+		dest, err := resolveJourneyDestination(user, req.Destination)
+		if err != nil {
+			return nil, nil, err
+		}
+		for column, value := range dest {
+			updates[column] = value
+		}
+	}
 	if req.ExpectedReturnTime != nil {
 		// 验证预计返回时间必须晚于当前时间
 		if req.ExpectedReturnTime.Before(time.Now()) {
@@ -217,6 +243,14 @@ func (s *JourneyService) UpdateJourney(
 				Message: "Expected return time must be in the future",
 			}
 		}
+		// 预计返回时间不能早于尚未打卡的检查点
+		lastCheckpointAt, err := lastPendingCheckpointAt(q, journey.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !req.ExpectedReturnTime.After(lastCheckpointAt) {
+			return nil, nil, pkgerrors.JourneyCheckpointInvalid
+		}
 		oldExpectedReturnTime = journey.ExpectedReturnTime
 		updates["expected_return_time"] = *req.ExpectedReturnTime
 		needReschedule = true // 标记需要重新调度
@@ -226,11 +260,43 @@ func (s *JourneyService) UpdateJourney(
 		return journey, nil, nil
 	}
 
+	// 改期时分配新的超时消息 ID，之前投递的超时消息在 consumer 中作废
+	var timeoutMsg *model.JourneyTimeoutMessage
+	if needReschedule {
+		user, err := q.User.GetByID(journey.UserID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to query user: %w", err)
+		}
+
+		var messageID string
+		messageID, timeoutMsg, err = prepareJourneyTimeout(journey.ID, userID, *req.ExpectedReturnTime, user.JourneyAutoNotify, now)
+		if err != nil {
+			logger.Logger.Error("Failed to generate message ID for reschedule",
+				zap.Int64("journey_id", journeyID),
+				zap.Error(err),
+			)
+		}
+		updates["timeout_message_id"] = messageID
+		updates["reminder_sent_at"] = nil
+	}
+
 	// 执行更新
 	updates["updated_at"] = time.Now()
-	_, err = q.Journey.
-		Where(q.Journey.ID.Eq(journey.ID)).
-		Updates(updates)
+	err = db.Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+		if _, err := txQ.Journey.
+			Where(txQ.Journey.ID.Eq(journey.ID)).
+			Updates(updates); err != nil {
+			return err
+		}
+		if !needReschedule {
+			return nil
+		}
+		return recordJourneyEvent(txQ, journey.ID, model.JourneyEventRescheduled, model.JSONB{
+			"from": oldExpectedReturnTime.Format(time.RFC3339),
+			"to":   req.ExpectedReturnTime.Format(time.RFC3339),
+		}, now)
+	})
 	if err != nil {
 		logger.Logger.Error("Failed to update journey",
 			zap.Int64("journey_id", journeyID),
@@ -245,52 +311,19 @@ func (s *JourneyService) UpdateJourney(
 		return nil, nil, fmt.Errorf("failed to query updated journey: %w", err)
 	}
 
-	// 如果 expected_return_time 更新了，重新构建消息对象
-	var timeoutMsg *model.JourneyTimeoutMessage
-	if needReschedule && req.ExpectedReturnTime != nil {
-		// 查询用户设置
-		user, err := q.User.GetByID(updatedJourney.UserID)
-		if err == nil && user.JourneyAutoNotify {
-			// 计算新的延迟时间
-			delayTime := updatedJourney.ExpectedReturnTime.Add(10 * time.Minute)
-			now := time.Now()
-
-			if delayTime.After(now) {
-				delay := delayTime.Sub(now)
-
-				if delay <= 24*time.Hour {
-					// 构建新的消息对象（但不发布）
-					messageID, err := snowflake.NextID(snowflake.GeneratorTypeMessage)
-					if err != nil {
-						logger.Logger.Error("Failed to generate message ID for reschedule",
-							zap.Int64("journey_id", journeyID),
-							zap.Error(err),
-						)
-					} else {
-						timeoutMsg = &model.JourneyTimeoutMessage{
-							MessageID:    fmt.Sprintf("journey_timeout_%d", messageID),
-							ScheduledAt:  now.Format(time.RFC3339),
-							JourneyID:    journeyID,
-							UserID:       userID,
-							DelaySeconds: int(delay.Seconds()),
-							// 可以添加旧时间用于日志
-							// OldExpectedReturnTime: oldExpectedReturnTime,
-						}
-						logger.Logger.Info("Journey timeout message prepared for reschedule",
-							zap.Int64("journey_id", journeyID),
-							zap.Time("old_time", oldExpectedReturnTime),
-							zap.Time("new_time", updatedJourney.ExpectedReturnTime),
-						)
-					}
-				} else {
-					logger.Logger.Info("Journey reschedule delay exceeds 24 hours, will be handled by scheduled task",
-						zap.Int64("journey_id", journeyID),
-						zap.Duration("delay", delay),
-						zap.Time("expected_return_time", updatedJourney.ExpectedReturnTime),
-					)
-				}
-			}
+	if needReschedule {
+		// 清除定时任务的投递标记，按新的预计返回时间重新投递
+		if err := cache.UnmarkJourneyScheduled(ctx, journey.ID); err != nil {
+			logger.Logger.Warn("Failed to unmark journey scheduled",
+				zap.Int64("journey_id", journeyID),
+				zap.Error(err),
+			)
 		}
+		logger.Logger.Info("Journey rescheduled",
+			zap.Int64("journey_id", journeyID),
+			zap.Time("old_time", oldExpectedReturnTime),
+			zap.Time("new_time", updatedJourney.ExpectedReturnTime),
+		)
 	}
 
 	logger.Logger.Info("Journey updated",
@@ -396,6 +429,16 @@ func (s *JourneyService) GetJourneyDetail(
 		return nil, fmt.Errorf("failed to query journey: %w", err)
 	}
 
+	checkpoints, err := listJourneyCheckpoints(q, journey.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	lastLocation, err := latestJourneyLocation(q, journey.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	return &dto.JourneyDetail{
 		JourneyItem: dto.JourneyItem{
 			ID:                 strconv.FormatInt(journey.ID, 10),
@@ -411,14 +454,24 @@ func (s *JourneyService) GetJourneyDetail(
 		AlertLastAttemptAt: journey.AlertLastAttemptAt,
 		AlertStatus:        string(journey.AlertStatus),
 		AlertAttempts:      journey.AlertAttempts,
+		ExtensionCount:     journey.ExtensionCount,
+		ExtendedMinutes:    journey.ExtendedMinutes,
+		Checkpoints:        journeyCheckpointItems(checkpoints),
+		LastLocation:       journeyLastLocation(lastLocation),
+		Destination:        journeyDestination(journey),
+		CompletedBy:        string(journey.CompletedBy),
+		ContactPriorities:  []int(journey.ContactPriorities),
+		AlertMessage:       journey.AlertMessage,
 	}, nil
 }
 
 // CompleteJourney 归来打卡，标记行程结束
+// completedBy 为 geofence 时只结束进行中的行程，已超时（联系人已收到告警）的行程需要用户手动打卡
 func (s *JourneyService) CompleteJourney(
 	ctx context.Context,
 	userID int64,
 	journeyID int64,
+	completedBy model.JourneyCompletedBy,
 ) (*dto.JourneyItem, error) {
 	db := database.DB().WithContext(ctx)
 	q := query.Use(db)
@@ -435,22 +488,33 @@ func (s *JourneyService) CompleteJourney(
 		return nil, fmt.Errorf("failed to query journey: %w", err)
 	}
 
+	alreadyEnded := pkgerrors.Definition{
+		Code:    "JOURNEY_ALREADY_ENDED",
+		Message: "Journey has already been completed",
+	}
 	if journey.Status == model.JourneyStatusEnded {
-		return nil, pkgerrors.Definition{
-			Code:    "JOURNEY_ALREADY_ENDED",
-			Message: "Journey has already been completed",
-		}
+		return nil, alreadyEnded
+	}
+	if completedBy == model.JourneyCompletedByGeofence && journey.Status != model.JourneyStatusOngoing {
+		return nil, pkgerrors.JourneyNotModifiable
 	}
 
-	// 更新行程状态
+	// 更新行程状态，按状态条件更新，与超时处理并发时只有一方生效
+	// 清空 timeout_message_id，已投递的超时消息在 consumer 中按 ID 作废
 	now := time.Now()
-	_, err = q.Journey.
-		Where(q.Journey.ID.Eq(journey.ID)).
-		Updates(map[string]interface{}{
-			"status":             model.JourneyStatusEnded,
-			"actual_return_time": now,
-			"updated_at":         now,
-		})
+	do := q.Journey.Where(q.Journey.ID.Eq(journey.ID))
+	if completedBy == model.JourneyCompletedByGeofence {
+		do = do.Where(q.Journey.Status.Eq(string(model.JourneyStatusOngoing)))
+	} else {
+		do = do.Where(q.Journey.Status.Neq(string(model.JourneyStatusEnded)))
+	}
+	info, err := do.Updates(map[string]interface{}{
+		"status":             model.JourneyStatusEnded,
+		"actual_return_time": now,
+		"completed_by":       completedBy,
+		"timeout_message_id": "",
+		"updated_at":         now,
+	})
 	if err != nil {
 		logger.Logger.Error("Failed to complete journey",
 			zap.Int64("journey_id", journeyID),
@@ -458,6 +522,20 @@ func (s *JourneyService) CompleteJourney(
 		)
 		return nil, fmt.Errorf("failed to complete journey: %w", err)
 	}
+	if info.RowsAffected == 0 {
+		return nil, alreadyEnded
+	}
+	recordJourneyEventBestEffort(q, journey.ID, model.JourneyEventCompleted, model.JSONB{
+		"late":         now.After(journey.ExpectedReturnTime),
+		"completed_by": completedBy,
+	}, now)
+
+	if err := cache.UnmarkJourneyScheduled(ctx, journey.ID); err != nil {
+		logger.Logger.Warn("Failed to unmark journey scheduled",
+			zap.Int64("journey_id", journey.ID),
+			zap.Error(err),
+		)
+	}
 
 	completedJourney, err := q.Journey.GetByID(journey.ID)
 	if err != nil {
@@ -469,6 +547,7 @@ func (s *JourneyService) CompleteJourney(
 	logger.Logger.Info("Journey completed",
 		zap.Int64("journey_id", journeyID),
 		zap.Int64("user_id", userID),
+		zap.String("completed_by", string(completedBy)),
 	)
 
 	return &dto.JourneyItem{
@@ -540,6 +619,15 @@ func (s *JourneyService) ProcessReminder(
 		return nil, nil
 	}
 
+	// 延长或改期后，按旧预计返回时间投递的提醒会提前到达，跳过
+	if time.Now().Before(journey.ExpectedReturnTime.Add(-time.Minute)) {
+		logger.Logger.Debug("Journey reminder arrived before expected return time, skipping",
+			zap.Int64("journey_id", journeyID),
+			zap.Time("expected_return_time", journey.ExpectedReturnTime),
+		)
+		return nil, nil
+	}
+
 	// 检查是否已经发送过提醒（防止重复发送）
 	if journey.ReminderSentAt != nil {
 		logger.Logger.Debug("Journey reminder already sent, skipping",
@@ -555,19 +643,19 @@ func (s *JourneyService) ProcessReminder(
 		return nil, fmt.Errorf("failed to query user: %w", err)
 	}
 
-	// 检查用户额度（提醒短信也需要扣费）
+	// 检查用户额度（提醒短信也需要扣费，个人钱包与共享钱包合计）
 	quotaService := Quota()
-	wallet, err := quotaService.GetWallet(ctx, user.ID, model.QuotaChannelSMS)
+	smsBalance, err := quotaService.SpendableBalance(ctx, user.ID, model.QuotaChannelSMS)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query SMS quota wallet: %w", err)
 	}
 
 	smsUnitPriceCents := 5
-	if wallet.AvailableAmount < smsUnitPriceCents {
+	if smsBalance < smsUnitPriceCents {
 		logger.Logger.Warn("Insufficient quota for journey reminder",
 			zap.Int64("user_id", user.ID),
 			zap.Int64("journey_id", journeyID),
-			zap.Int("balance", wallet.AvailableAmount),
+			zap.Int("balance", smsBalance),
 		)
 		// 额度不足，跳过提醒但不报错
 		return nil, nil
@@ -626,11 +714,13 @@ func (s *JourneyService) ProcessReminder(
 }
 
 // ProcessTimeout 处理行程超时
+// messageID 为延迟消息的 ID，与行程当前的 timeout_message_id 不一致时说明已被改期或延长取代
 // 返回创建的通知任务列表（用于发布到队列）
 func (s *JourneyService) ProcessTimeout(
 	ctx context.Context,
 	journeyID int64,
 	userID int64,
+	messageID string,
 ) ([]*model.NotificationTask, error) {
 	db := database.DB().WithContext(ctx)
 	q := query.Use(db)
@@ -654,6 +744,22 @@ func (s *JourneyService) ProcessTimeout(
 		return nil, nil // 已处理或已结束，跳过
 	}
 
+	// 改期或延长后之前投递的消息作废；timeout_message_id 为空（NULL）的旧行程仍按时间判断
+	if journey.TimeoutMessageID != nil && *journey.TimeoutMessageID != messageID {
+		logger.Logger.Info("Stale journey timeout message, skipping",
+			zap.Int64("journey_id", journeyID),
+			zap.String("message_id", messageID),
+			zap.String("current_message_id", *journey.TimeoutMessageID),
+		)
+		return nil, nil
+	}
+
+	// 上面的判断没有加锁，更新时再按当前消息（或旧行程的预计返回时间）匹配，期间被延长或改期则不再处理
+	current := q.Journey.ExpectedReturnTime.Eq(journey.ExpectedReturnTime)
+	if journey.TimeoutMessageID != nil {
+		current = q.Journey.TimeoutMessageID.Eq(messageID)
+	}
+
 	// 安全校验：只有在当前时间晚于「预计返回时间 + 10 分钟」时才允许执行超时处理
 	now := time.Now()
 	timeoutThreshold := journey.ExpectedReturnTime.Add(10 * time.Minute)
@@ -673,49 +779,105 @@ func (s *JourneyService) ProcessTimeout(
 		return nil, fmt.Errorf("failed to query user: %w", err)
 	}
 
-	// 检查用户额度 - 使用新的 QuotaService
-	quotaService := Quota()
-	wallet, err := quotaService.GetWallet(ctx, user.ID, model.QuotaChannelSMS)
+	// 通知套餐上限内已确认的联系人，检查用户额度（个人钱包与共享钱包合计）
+	alert, err := planJourneyAlert(ctx, q, user.ID, journey.ContactPriorities)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query SMS quota wallet: %w", err)
+		return nil, err
 	}
 
-	smsBalance := wallet.AvailableAmount
+	// 指定的联系人都不可用：不改为通知其他联系人，告警记为失败并通知用户本人
+	if alert.unavailable {
+		var userTask *model.NotificationTask
+		var ended bool
+		err := db.Transaction(func(tx *gorm.DB) error {
+			txQ := query.Use(tx)
+			info, err := txQ.Journey.
+				Where(txQ.Journey.ID.Eq(journey.ID)).
+				Where(txQ.Journey.Status.Eq(string(model.JourneyStatusOngoing))).
+				Where(current).
+				Updates(map[string]interface{}{
+					"status":             model.JourneyStatusTimeout,
+					"alert_status":       model.AlertStatusFailed,
+					"alert_triggered_at": now,
+					"updated_at":         now,
+				})
+			if err != nil {
+				return fmt.Errorf("failed to update journey: %w", err)
+			}
+			if info.RowsAffected == 0 {
+				ended = true
+				return nil
+			}
 
-	contactCount := len(user.EmergencyContacts)
-	smsUnitPriceCents := 5
-	totalCost := smsUnitPriceCents * contactCount
+			userTask, err = createJourneyAlertUndeliveredTask(txQ, journey, alert.balance, now)
+			if err != nil {
+				return err
+			}
+			return recordJourneyEvent(txQ, journey.ID, model.JourneyEventTimeout, model.JSONB{
+				"contacts": 0,
+				"reason":   "contacts_unavailable",
+			}, now)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if ended || userTask == nil {
+			return nil, nil
+		}
+		return []*model.NotificationTask{userTask}, nil
+	}
 
-	if smsBalance < totalCost {
+	if !alert.affordable() {
 		logger.Logger.Warn("Insufficient quota for journey timeout alert",
 			zap.Int64("user_id", user.ID),
 			zap.Int64("journey_id", journeyID),
-			zap.Int("balance", smsBalance),
-			zap.Int("required", totalCost),
+			zap.Int("balance", alert.balance),
+			zap.Int("required", alert.totalCost),
 		)
 		// 额度不足，更新行程状态但不发送通知
 		now := time.Now()
-		_, err = q.Journey.
+		info, err := q.Journey.
 			Where(q.Journey.ID.Eq(journey.ID)).
+			Where(q.Journey.Status.Eq(string(model.JourneyStatusOngoing))).
+			Where(current).
 			Updates(map[string]interface{}{
 				"status":             model.JourneyStatusTimeout,
 				"alert_status":       model.AlertStatusFailed,
 				"alert_triggered_at": now,
 				"updated_at":         now,
 			})
+		if err != nil {
+			return nil, err
+		}
+		if info.RowsAffected == 0 {
+			return nil, nil // 期间行程已结束（归来打卡或到达目的地）
+		}
+		recordJourneyEventBestEffort(q, journey.ID, model.JourneyEventTimeout, model.JSONB{
+			"contacts": 0,
+			"reason":   "insufficient_quota",
+		}, now)
+		return nil, nil
+	}
+
+	// 最后已知位置随告警发给联系人
+	lastLocation, err := latestJourneyLocation(q, journey.ID)
+	if err != nil {
 		return nil, err
 	}
 
 	// 收集创建的任务
 	var createdTasks []*model.NotificationTask
+	var ended bool
 
 	// 使用事务处理
 	err = db.Transaction(func(tx *gorm.DB) error {
 		txQ := query.Use(tx)
 
-		// 更新行程状态
-		_, err = txQ.Journey.
+		// 更新行程状态，期间行程已结束（归来打卡或到达目的地）则不再通知
+		info, err := txQ.Journey.
 			Where(txQ.Journey.ID.Eq(journey.ID)).
+			Where(txQ.Journey.Status.Eq(string(model.JourneyStatusOngoing))).
+			Where(current).
 			Updates(map[string]interface{}{
 				"status":                model.JourneyStatusTimeout,
 				"alert_status":          model.AlertStatusTriggered,
@@ -727,75 +889,29 @@ func (s *JourneyService) ProcessTimeout(
 		if err != nil {
 			return fmt.Errorf("failed to update journey: %w", err)
 		}
-
-		// 按优先级排序紧急联系人
-		contacts := user.EmergencyContacts
-		sort.Slice(contacts, func(i, j int) bool {
-			return contacts[i].Priority < contacts[j].Priority
-		})
-
-		// 最多通知3位
-		maxContacts := 3
-		if len(contacts) > maxContacts {
-			contacts = contacts[:maxContacts]
+		if info.RowsAffected == 0 {
+			ended = true
+			return nil
 		}
 
-		// // 获取用户昵称
-		// userName := user.Nickname
-		// if userName == "" {
-		// 	userName = fmt.Sprintf("%d", userID)
-		// }
-
 		// 为每个紧急联系人创建通知任务
-		for _, contact := range contacts {
-			taskCode, err := snowflake.NextID(snowflake.GeneratorTypeTask)
-			if err != nil {
-				logger.Logger.Error("Failed to generate task code",
-					zap.Int64("journey_id", journeyID),
-					zap.Error(err),
-				)
-				continue
-			}
-
-			// 构建 payload（包含行程信息）
-			// 使用 journey_reminder_contact 类型发给紧急联系人
-			// 字段需要与阿里云模板变量匹配：name, trip, time, note
-			payload := model.JSONB{
-				"type": "journey_reminder_contact",
-				"name": contact.DisplayName,
-				"trip": journey.Title,
-				"time": journey.ExpectedReturnTime.Format("2006-01-02 15:04"),
-				"note": journey.Note,
-			}
-
-			notificationTask := &model.NotificationTask{
-				TaskCode:         taskCode,
-				UserID:           user.ID,
-				Category:         model.NotificationCategoryJourneyTimeout,
-				Channel:          model.NotificationChannelSMS,
-				Status:           model.NotificationTaskStatusPending,
-				Payload:          payload,
-				ContactPriority:  &contact.Priority,
-				ContactPhoneHash: &contact.PhoneHash,
-				ScheduledAt:      now,
-			}
-
-			if err := txQ.NotificationTask.Create(notificationTask); err != nil {
-				logger.Logger.Error("Failed to create notification task",
-					zap.Int64("journey_id", journeyID),
-					zap.Error(err),
-				)
-				return fmt.Errorf("failed to create notification task: %w", err)
-			}
+		createdTasks, err = createJourneyAlertTasks(txQ, journey, alert.contacts, func(contact *model.Contact) model.JSONB {
+			return journeyContactPayload(journey, contact, lastLocation)
+		}, now)
+		if err != nil {
+			return err
+		}
 
-			// 收集创建的任务
-			createdTasks = append(createdTasks, notificationTask)
+		if err := recordJourneyEvent(txQ, journey.ID, model.JourneyEventTimeout, model.JSONB{
+			"contacts": len(createdTasks),
+		}, now); err != nil {
+			return err
 		}
 
 		logger.Logger.Info("Journey timeout processed",
 			zap.Int64("journey_id", journeyID),
 			zap.Int64("user_id", user.ID),
-			zap.Int("contact_count", len(contacts)),
+			zap.Int("contact_count", len(alert.contacts)),
 		)
 
 		return nil
@@ -804,6 +920,12 @@ func (s *JourneyService) ProcessTimeout(
 	if err != nil {
 		return nil, err
 	}
+	if ended {
+		logger.Logger.Info("Journey ended before timeout processing, skipping",
+			zap.Int64("journey_id", journeyID),
+		)
+		return nil, nil
+	}
 
 	return createdTasks, nil
 }
@@ -850,6 +972,7 @@ func (s *JourneyService) DeleteJourney(
 		Updates(updates); err != nil {
 		return fmt.Errorf("failed to cancel journey: %w", err)
 	}
+	recordJourneyEventBestEffort(q, journey.ID, model.JourneyEventCancelled, nil, now)
 
 	logger.Logger.Info("Journey cancelled by user",
 		zap.Int64("journey_id", journeyID),
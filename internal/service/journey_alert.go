@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+
+	"AreYouOK/internal/model"
+	"AreYouOK/internal/repository/query"
+	pkgerrors "AreYouOK/pkg/errors"
+	"AreYouOK/pkg/logger"
+	"AreYouOK/pkg/snowflake"
+	"AreYouOK/storage/database"
+)
+
+const (
+	journeyAlertSMSCents      = 5  // 每条告警短信的费用（分）
+	journeyAlertMessageMaxLen = 60 // 告警留言的最大字数
+)
+
+// journeyAlertURLPattern 留言中的链接，短信变量里的链接会被运营商拦截
+var journeyAlertURLPattern = regexp.MustCompile(`(?i)(?:https?://|www\.)\S*`)
+
+// journeyAlertPlan 一次行程告警要通知的联系人与费用
+type journeyAlertPlan struct {
+	contacts    []*model.Contact
+	totalCost   int
+	balance     int
+	unavailable bool // 行程指定的联系人都已删除、未确认或已退订，告警无人可发
+}
+
+// affordable 额度是否足够通知全部联系人
+func (p *journeyAlertPlan) affordable() bool {
+	return p.balance >= p.totalCost
+}
+
+// planJourneyAlert 套餐上限内已确认的联系人（按优先级，已退订的号码不通知），以及个人钱包与共享钱包合计的可用额度
+// selected 为行程指定的联系人优先级，为空表示全部；指定的联系人都不可用时不通知其他联系人，标记为 unavailable
+func planJourneyAlert(
+	ctx context.Context,
+	q *query.Query,
+	userID int64,
+	selected model.ContactPriorities,
+) (*journeyAlertPlan, error) {
+	balance, err := Quota().SpendableBalance(ctx, userID, model.QuotaChannelSMS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query SMS quota wallet: %w", err)
+	}
+
+	plan, err := Subscription().PlanForUser(ctx, userID)
+	if err != nil {
+		logger.Logger.Warn("Failed to query user plan, falling back to free plan",
+			zap.Int64("user_id", userID),
+			zap.Error(err),
+		)
+	}
+
+	contacts, err := listConfirmedContacts(q, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(selected) > 0 {
+		filtered := make([]*model.Contact, 0, len(contacts))
+		for _, contact := range contacts {
+			if selected.Contains(contact.Priority) {
+				filtered = append(filtered, contact)
+			}
+		}
+		contacts = filtered
+	}
+	if len(contacts) > plan.MaxContacts {
+		contacts = contacts[:plan.MaxContacts]
+	}
+
+	unavailable := len(selected) > 0 && len(contacts) == 0
+	if unavailable {
+		logger.Logger.Warn("Selected journey contacts unavailable",
+			zap.Int64("user_id", userID),
+			zap.Ints("priorities", selected),
+		)
+	}
+
+	return &journeyAlertPlan{
+		contacts:    contacts,
+		totalCost:   journeyAlertSMSCents * len(contacts),
+		balance:     balance,
+		unavailable: unavailable,
+	}, nil
+}
+
+// journeyContactPayload 发给紧急联系人的行程告警短信参数
+// 字段需要与阿里云模板变量匹配：name, trip, time, note, optout；行程设置了告警留言时 note 使用留言；
+// 有上报位置时附带最后位置（坐标、定位时间与地图链接），发送时并入 note
+func journeyContactPayload(journey *model.Journey, contact *model.Contact, last *model.JourneyLocation) model.JSONB {
+	note := journey.Note
+	if journey.AlertMessage != "" {
+		note = journey.AlertMessage
+	}
+	payload := model.JSONB{
+		"type":   "journey_reminder_contact",
+		"name":   contact.DisplayName,
+		"trip":   journey.Title,
+		"time":   journey.ExpectedReturnTime.Format("2006-01-02 15:04"),
+		"note":   note,
+		"optout": contactOptOutLink(contact),
+	}
+	if last != nil {
+		payload["lat"] = last.Latitude
+		payload["lon"] = last.Longitude
+		payload["located_at"] = last.RecordedAt.Format("2006-01-02 15:04")
+		payload["map"] = journeyMapLink(last.Latitude, last.Longitude)
+	}
+	return payload
+}
+
+// createJourneyAlertTasks 为每个联系人创建行程告警通知任务，返回创建的任务供 consumer 发布
+func createJourneyAlertTasks(
+	q *query.Query,
+	journey *model.Journey,
+	contacts []*model.Contact,
+	payload func(contact *model.Contact) model.JSONB,
+	now time.Time,
+) ([]*model.NotificationTask, error) {
+	tasks := make([]*model.NotificationTask, 0, len(contacts))
+	for _, contact := range contacts {
+		taskCode, err := snowflake.NextID(snowflake.GeneratorTypeTask)
+		if err != nil {
+			logger.Logger.Error("Failed to generate task code",
+				zap.Int64("journey_id", journey.ID),
+				zap.Error(err),
+			)
+			continue
+		}
+
+		task := &model.NotificationTask{
+			TaskCode:         taskCode,
+			UserID:           journey.UserID,
+			Category:         model.NotificationCategoryJourneyTimeout,
+			Channel:          model.NotificationChannelSMS,
+			Status:           model.NotificationTaskStatusPending,
+			Payload:          payload(contact),
+			ContactPriority:  &contact.Priority,
+			ContactPhoneHash: &contact.PhoneHash,
+			ScheduledAt:      now,
+		}
+		if err := q.NotificationTask.Create(task); err != nil {
+			logger.Logger.Error("Failed to create notification task",
+				zap.Int64("journey_id", journey.ID),
+				zap.Error(err),
+			)
+			return nil, fmt.Errorf("failed to create notification task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// createJourneyAlertUndeliveredTask 告警无法送达指定联系人时通知用户本人，额度不足时不发送（返回 nil）
+func createJourneyAlertUndeliveredTask(
+	q *query.Query,
+	journey *model.Journey,
+	balance int,
+	now time.Time,
+) (*model.NotificationTask, error) {
+	if balance < journeyAlertSMSCents {
+		logger.Logger.Warn("Insufficient quota for journey alert undelivered notice",
+			zap.Int64("user_id", journey.UserID),
+			zap.Int64("journey_id", journey.ID),
+			zap.Int("balance", balance),
+		)
+		return nil, nil
+	}
+
+	taskCode, err := snowflake.NextID(snowflake.GeneratorTypeTask)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate task code: %w", err)
+	}
+	task := &model.NotificationTask{
+		TaskCode: taskCode,
+		UserID:   journey.UserID,
+		Category: model.NotificationCategoryJourneyTimeout,
+		Channel:  model.NotificationChannelSMS,
+		Status:   model.NotificationTaskStatusPending,
+		Payload: model.JSONB{
+			"type": "journey_alert_undelivered",
+			"trip": journey.Title,
+		},
+		ScheduledAt: now,
+	}
+	if err := q.NotificationTask.Create(task); err != nil {
+		return nil, fmt.Errorf("failed to create notification task: %w", err)
+	}
+	return task, nil
+}
+
+// normalizeContactPriorities 校验行程指定的联系人优先级：不重复、在套餐联系人上限内且对应的联系人存在（未拒绝、过期或退订），结果升序
+func normalizeContactPriorities(ctx context.Context, userID int64, priorities []int) (model.ContactPriorities, error) {
+	if len(priorities) == 0 {
+		return model.ContactPriorities{}, nil
+	}
+
+	plan, err := Subscription().PlanForUser(ctx, userID)
+	if err != nil {
+		logger.Logger.Warn("Failed to query user plan, falling back to free plan",
+			zap.Int64("user_id", userID),
+			zap.Error(err),
+		)
+	}
+
+	seen := make(map[int]bool, len(priorities))
+	result := make(model.ContactPriorities, 0, len(priorities))
+	for _, priority := range priorities {
+		if priority < 1 || priority > plan.MaxContacts || seen[priority] {
+			return nil, pkgerrors.JourneyContactsInvalid
+		}
+		seen[priority] = true
+		result = append(result, priority)
+	}
+
+	q := query.Use(database.DB().WithContext(ctx))
+	count, err := q.Contact.
+		Where(q.Contact.UserID.Eq(userID)).
+		Where(q.Contact.Priority.In(result...)).
+		Where(q.Contact.Status.In(string(model.ContactStatusPending), string(model.ContactStatusConfirmed))).
+		Count()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query contacts: %w", err)
+	}
+	if int(count) != len(result) {
+		return nil, pkgerrors.JourneyContactsInvalid
+	}
+
+	sort.Ints(result)
+	return result, nil
+}
+
+// normalizeJourneyAlertMessage 告警留言作为短信模板变量发给联系人：
+// 去掉链接、模板占位符用到的 $ { } 与不可见字符，连续空白合并为一个空格，超过 60 字返回 JourneyAlertMessageTooLong
+func normalizeJourneyAlertMessage(message string) (string, error) {
+	message = journeyAlertURLPattern.ReplaceAllString(message, " ")
+	message = strings.Map(func(r rune) rune {
+		switch {
+		case r == '$' || r == '{' || r == '}':
+			return -1
+		case unicode.IsControl(r):
+			return ' '
+		case unicode.Is(unicode.Cf, r):
+			return -1
+		}
+		return r
+	}, message)
+	message = strings.Join(strings.Fields(message), " ")
+	if utf8.RuneCountInString(message) > journeyAlertMessageMaxLen {
+		return "", pkgerrors.JourneyAlertMessageTooLong
+	}
+	return message, nil
+}
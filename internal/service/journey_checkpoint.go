@@ -0,0 +1,470 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"AreYouOK/internal/model"
+	"AreYouOK/internal/model/dto"
+	"AreYouOK/internal/repository/query"
+	pkgerrors "AreYouOK/pkg/errors"
+	"AreYouOK/pkg/logger"
+	"AreYouOK/pkg/snowflake"
+	"AreYouOK/storage/database"
+)
+
+const journeyMaxCheckpoints = 10
+
+// normalizeJourneyCheckpoints 校验创建行程时的检查点：不超过 10 个，预计到达时间在未来、严格递增且早于预计返回时间
+// 经纬度需要成对出现
+func normalizeJourneyCheckpoints(
+	journeyID int64,
+	inputs []dto.JourneyCheckpointInput,
+	expectedReturnTime time.Time,
+	now time.Time,
+) ([]*model.JourneyCheckpoint, error) {
+	if len(inputs) > journeyMaxCheckpoints {
+		return nil, pkgerrors.JourneyCheckpointInvalid
+	}
+
+	checkpoints := make([]*model.JourneyCheckpoint, 0, len(inputs))
+	last := now
+	for i, input := range inputs {
+		label := strings.TrimSpace(input.Label)
+		location := strings.TrimSpace(input.Location)
+		if label == "" || utf8.RuneCountInString(label) > 64 || utf8.RuneCountInString(location) > 128 {
+			return nil, pkgerrors.JourneyCheckpointInvalid
+		}
+		if !input.ExpectedAt.After(last) || !input.ExpectedAt.Before(expectedReturnTime) {
+			return nil, pkgerrors.JourneyCheckpointInvalid
+		}
+		if (input.Latitude == nil) != (input.Longitude == nil) {
+			return nil, pkgerrors.JourneyCheckpointInvalid
+		}
+		if input.Latitude != nil && (*input.Latitude < -90 || *input.Latitude > 90 || *input.Longitude < -180 || *input.Longitude > 180) {
+			return nil, pkgerrors.JourneyCheckpointInvalid
+		}
+		last = input.ExpectedAt
+
+		checkpoints = append(checkpoints, &model.JourneyCheckpoint{
+			JourneyID:  journeyID,
+			Seq:        i + 1,
+			Label:      label,
+			Location:   location,
+			Latitude:   input.Latitude,
+			Longitude:  input.Longitude,
+			ExpectedAt: input.ExpectedAt,
+			Status:     model.JourneyCheckpointPending,
+		})
+	}
+	return checkpoints, nil
+}
+
+// listJourneyCheckpoints 行程的检查点，按顺序排列
+func listJourneyCheckpoints(q *query.Query, journeyID int64) ([]*model.JourneyCheckpoint, error) {
+	checkpoints, err := q.JourneyCheckpoint.
+		Where(q.JourneyCheckpoint.JourneyID.Eq(journeyID)).
+		Order(q.JourneyCheckpoint.Seq).
+		Find()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query journey checkpoints: %w", err)
+	}
+	return checkpoints, nil
+}
+
+// lastPendingCheckpointAt 最后一个待打卡检查点的预计到达时间，没有时返回零值
+func lastPendingCheckpointAt(q *query.Query, journeyID int64) (time.Time, error) {
+	checkpoints, err := listJourneyCheckpoints(q, journeyID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var last time.Time
+	for _, checkpoint := range checkpoints {
+		if checkpoint.Status == model.JourneyCheckpointPending && checkpoint.ExpectedAt.After(last) {
+			last = checkpoint.ExpectedAt
+		}
+	}
+	return last, nil
+}
+
+func journeyCheckpointItem(checkpoint *model.JourneyCheckpoint) dto.JourneyCheckpointItem {
+	return dto.JourneyCheckpointItem{
+		Seq:              checkpoint.Seq,
+		Label:            checkpoint.Label,
+		Location:         checkpoint.Location,
+		Latitude:         checkpoint.Latitude,
+		Longitude:        checkpoint.Longitude,
+		ExpectedAt:       checkpoint.ExpectedAt,
+		CheckedInAt:      checkpoint.CheckedInAt,
+		AlertTriggeredAt: checkpoint.AlertTriggeredAt,
+		Status:           string(checkpoint.Status),
+	}
+}
+
+func journeyCheckpointItems(checkpoints []*model.JourneyCheckpoint) []dto.JourneyCheckpointItem {
+	items := make([]dto.JourneyCheckpointItem, 0, len(checkpoints))
+	for _, checkpoint := range checkpoints {
+		items = append(items, journeyCheckpointItem(checkpoint))
+	}
+	return items
+}
+
+// CheckInCheckpoint 检查点打卡
+// 已超时通知过紧急联系人的检查点仍可补打卡，时间线中记录为迟到
+func (s *JourneyService) CheckInCheckpoint(
+	ctx context.Context,
+	publicUserID int64,
+	journeyID int64,
+	seq int,
+) (*dto.JourneyCheckpointItem, error) {
+	db := database.DB().WithContext(ctx)
+	q := query.Use(db)
+
+	journey, err := q.Journey.GetByPublicIDAndJourneyID(publicUserID, journeyID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, pkgerrors.Definition{
+				Code:    "JOURNEY_NOT_FOUND",
+				Message: "Journey not found",
+			}
+		}
+		return nil, fmt.Errorf("failed to query journey: %w", err)
+	}
+	if journey.Status != model.JourneyStatusOngoing {
+		return nil, pkgerrors.JourneyNotModifiable
+	}
+
+	checkpoint, err := q.JourneyCheckpoint.
+		Where(q.JourneyCheckpoint.JourneyID.Eq(journey.ID), q.JourneyCheckpoint.Seq.Eq(seq)).
+		First()
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, pkgerrors.JourneyCheckpointNotFound
+		}
+		return nil, fmt.Errorf("failed to query journey checkpoint: %w", err)
+	}
+	if checkpoint.Status == model.JourneyCheckpointCheckedIn {
+		return nil, pkgerrors.JourneyCheckpointClosed
+	}
+
+	now := time.Now()
+	missed := checkpoint.Status == model.JourneyCheckpointMissed
+	err = db.Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+
+		// 与超时处理并发时以先写入的为准
+		info, err := txQ.JourneyCheckpoint.
+			Where(txQ.JourneyCheckpoint.ID.Eq(checkpoint.ID)).
+			Where(txQ.JourneyCheckpoint.Status.Eq(string(checkpoint.Status))).
+			Updates(map[string]interface{}{
+				"status":        model.JourneyCheckpointCheckedIn,
+				"checked_in_at": now,
+				"updated_at":    now,
+			})
+		if err != nil {
+			return fmt.Errorf("failed to check in journey checkpoint: %w", err)
+		}
+		if info.RowsAffected == 0 {
+			return pkgerrors.JourneyCheckpointClosed
+		}
+
+		return recordJourneyEvent(txQ, journey.ID, model.JourneyEventCheckpointCheckedIn, model.JSONB{
+			"seq":    checkpoint.Seq,
+			"label":  checkpoint.Label,
+			"late":   now.After(checkpoint.ExpectedAt),
+			"missed": missed,
+		}, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Logger.Info("Journey checkpoint checked in",
+		zap.Int64("journey_id", journey.ID),
+		zap.Int("seq", checkpoint.Seq),
+		zap.Bool("missed", missed),
+	)
+
+	checkpoint.Status = model.JourneyCheckpointCheckedIn
+	checkpoint.CheckedInAt = &now
+	item := journeyCheckpointItem(checkpoint)
+	return &item, nil
+}
+
+// loadCheckpointForMessage 延迟消息对应的行程与检查点
+// 行程或检查点不存在时返回可跳过错误
+func loadCheckpointForMessage(
+	q *query.Query,
+	publicUserID int64,
+	journeyID int64,
+	checkpointID int64,
+) (*model.Journey, *model.JourneyCheckpoint, error) {
+	journey, err := q.Journey.GetByPublicIDAndJourneyID(publicUserID, journeyID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, &pkgerrors.SkipMessageError{Reason: "Journey not found for checkpoint"}
+		}
+		return nil, nil, fmt.Errorf("failed to query journey: %w", err)
+	}
+
+	checkpoint, err := q.JourneyCheckpoint.
+		Where(q.JourneyCheckpoint.ID.Eq(checkpointID), q.JourneyCheckpoint.JourneyID.Eq(journey.ID)).
+		First()
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, &pkgerrors.SkipMessageError{Reason: "Journey checkpoint not found"}
+		}
+		return nil, nil, fmt.Errorf("failed to query journey checkpoint: %w", err)
+	}
+	return journey, checkpoint, nil
+}
+
+// ProcessCheckpointReminder 检查点到达预计时间时提醒用户本人打卡
+// 返回创建的通知任务（如果创建了的话），供 consumer 发布到短信队列
+func (s *JourneyService) ProcessCheckpointReminder(
+	ctx context.Context,
+	journeyID int64,
+	checkpointID int64,
+	userID int64,
+) (*model.NotificationTask, error) {
+	db := database.DB().WithContext(ctx)
+	q := query.Use(db)
+
+	journey, checkpoint, err := loadCheckpointForMessage(q, userID, journeyID, checkpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	if journey.Status != model.JourneyStatusOngoing ||
+		checkpoint.Status != model.JourneyCheckpointPending ||
+		checkpoint.ReminderSentAt != nil {
+		logger.Logger.Debug("Journey checkpoint reminder not needed, skipping",
+			zap.Int64("journey_id", journeyID),
+			zap.Int64("checkpoint_id", checkpointID),
+			zap.String("status", string(checkpoint.Status)),
+		)
+		return nil, nil
+	}
+
+	now := time.Now()
+	if now.Before(checkpoint.ExpectedAt.Add(-time.Minute)) {
+		return nil, nil
+	}
+
+	balance, err := Quota().SpendableBalance(ctx, journey.UserID, model.QuotaChannelSMS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query SMS quota wallet: %w", err)
+	}
+	if balance < journeyAlertSMSCents {
+		logger.Logger.Warn("Insufficient quota for journey checkpoint reminder",
+			zap.Int64("user_id", journey.UserID),
+			zap.Int64("journey_id", journeyID),
+			zap.Int("balance", balance),
+		)
+		return nil, nil
+	}
+
+	taskCode, err := snowflake.NextID(snowflake.GeneratorTypeTask)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate task code: %w", err)
+	}
+
+	// 发送给用户本人，复用行程提醒的无参数模板
+	task := &model.NotificationTask{
+		TaskCode:    taskCode,
+		UserID:      journey.UserID,
+		Category:    model.NotificationCategoryJourneyReminder,
+		Channel:     model.NotificationChannelSMS,
+		Status:      model.NotificationTaskStatusPending,
+		Payload:     model.JSONB{"type": "journey_timeout"},
+		ScheduledAt: now,
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+
+		// 已发送过提醒的检查点不再创建任务
+		info, err := txQ.JourneyCheckpoint.
+			Where(txQ.JourneyCheckpoint.ID.Eq(checkpoint.ID), txQ.JourneyCheckpoint.ReminderSentAt.IsNull()).
+			Updates(map[string]interface{}{
+				"reminder_sent_at": now,
+				"updated_at":       now,
+			})
+		if err != nil {
+			return fmt.Errorf("failed to update checkpoint reminder_sent_at: %w", err)
+		}
+		if info.RowsAffected == 0 {
+			task = nil
+			return nil
+		}
+
+		if err := txQ.NotificationTask.Create(task); err != nil {
+			return fmt.Errorf("failed to create notification task: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if task != nil {
+		logger.Logger.Info("Journey checkpoint reminder processed",
+			zap.Int64("journey_id", journeyID),
+			zap.Int64("checkpoint_id", checkpointID),
+			zap.Int64("task_code", taskCode),
+		)
+	}
+	return task, nil
+}
+
+// ProcessCheckpointTimeout 检查点超过宽限期仍未打卡，通知紧急联系人
+// 与行程整体超时不同，行程保持进行中：用户仍可补打卡或归来打卡，后续检查点与预计返回时间照常检查
+// 返回创建的通知任务列表（用于发布到队列）
+func (s *JourneyService) ProcessCheckpointTimeout(
+	ctx context.Context,
+	journeyID int64,
+	checkpointID int64,
+	userID int64,
+	messageID string,
+) ([]*model.NotificationTask, error) {
+	db := database.DB().WithContext(ctx)
+	q := query.Use(db)
+
+	journey, checkpoint, err := loadCheckpointForMessage(q, userID, journeyID, checkpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	if journey.Status != model.JourneyStatusOngoing || checkpoint.Status != model.JourneyCheckpointPending {
+		logger.Logger.Debug("Journey checkpoint is not pending, skipping timeout processing",
+			zap.Int64("journey_id", journeyID),
+			zap.Int64("checkpoint_id", checkpointID),
+			zap.String("journey_status", string(journey.Status)),
+			zap.String("status", string(checkpoint.Status)),
+		)
+		return nil, nil
+	}
+
+	if checkpoint.TimeoutMessageID != nil && *checkpoint.TimeoutMessageID != messageID {
+		logger.Logger.Info("Stale journey checkpoint timeout message, skipping",
+			zap.Int64("checkpoint_id", checkpointID),
+			zap.String("message_id", messageID),
+		)
+		return nil, nil
+	}
+
+	now := time.Now()
+	if now.Before(checkpoint.ExpectedAt.Add(journeyTimeoutGrace)) {
+		return nil, nil
+	}
+
+	alert, err := planJourneyAlert(ctx, q, journey.UserID, journey.ContactPriorities)
+	if err != nil {
+		return nil, err
+	}
+
+	contacts := alert.contacts
+	if !alert.affordable() {
+		logger.Logger.Warn("Insufficient quota for journey checkpoint alert",
+			zap.Int64("user_id", journey.UserID),
+			zap.Int64("journey_id", journeyID),
+			zap.Int("balance", alert.balance),
+			zap.Int("required", alert.totalCost),
+		)
+		contacts = nil
+	}
+	reason := "insufficient_quota"
+	if alert.unavailable {
+		// 指定的联系人都不可用：不改为通知其他联系人，告警记为失败并通知用户本人
+		contacts = nil
+		reason = "contacts_unavailable"
+	}
+
+	lastLocation, err := latestJourneyLocation(q, journey.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var createdTasks []*model.NotificationTask
+	err = db.Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+
+		// 与检查点打卡并发时以先写入的为准
+		info, err := txQ.JourneyCheckpoint.
+			Where(txQ.JourneyCheckpoint.ID.Eq(checkpoint.ID)).
+			Where(txQ.JourneyCheckpoint.Status.Eq(string(model.JourneyCheckpointPending))).
+			Updates(map[string]interface{}{
+				"status":             model.JourneyCheckpointMissed,
+				"alert_triggered_at": now,
+				"updated_at":         now,
+			})
+		if err != nil {
+			return fmt.Errorf("failed to update journey checkpoint: %w", err)
+		}
+		if info.RowsAffected == 0 {
+			return nil
+		}
+
+		alertStatus := model.AlertStatusTriggered
+		if contacts == nil {
+			alertStatus = model.AlertStatusFailed
+		}
+		if _, err := txQ.Journey.
+			Where(txQ.Journey.ID.Eq(journey.ID)).
+			Updates(map[string]interface{}{
+				"alert_status":          alertStatus,
+				"alert_triggered_at":    now,
+				"alert_last_attempt_at": now,
+				"alert_attempts":        gorm.Expr("alert_attempts + ?", 1),
+				"updated_at":            now,
+			}); err != nil {
+			return fmt.Errorf("failed to update journey: %w", err)
+		}
+
+		createdTasks, err = createJourneyAlertTasks(txQ, journey, contacts, func(contact *model.Contact) model.JSONB {
+			payload := journeyContactPayload(journey, contact, lastLocation)
+			payload["checkpoint"] = checkpoint.Label
+			payload["time"] = checkpoint.ExpectedAt.Format("2006-01-02 15:04")
+			return payload
+		}, now)
+		if err != nil {
+			return err
+		}
+
+		data := model.JSONB{
+			"seq":      checkpoint.Seq,
+			"label":    checkpoint.Label,
+			"contacts": len(createdTasks),
+		}
+		if contacts == nil {
+			data["reason"] = reason
+		}
+		if alert.unavailable {
+			userTask, err := createJourneyAlertUndeliveredTask(txQ, journey, alert.balance, now)
+			if err != nil {
+				return err
+			}
+			if userTask != nil {
+				createdTasks = append(createdTasks, userTask)
+			}
+		}
+		return recordJourneyEvent(txQ, journey.ID, model.JourneyEventCheckpointMissed, data, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Logger.Info("Journey checkpoint timeout processed",
+		zap.Int64("journey_id", journeyID),
+		zap.Int64("checkpoint_id", checkpointID),
+		zap.Int("contact_count", len(createdTasks)),
+	)
+	return createdTasks, nil
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"AreYouOK/internal/model"
+	"AreYouOK/internal/model/dto"
+	"AreYouOK/internal/repository/query"
+	pkgerrors "AreYouOK/pkg/errors"
+	"AreYouOK/pkg/logger"
+)
+
+const (
+	geofenceMinRadius     = 50
+	geofenceMaxRadius     = 2000
+	geofenceDefaultRadius = 200
+	earthRadiusMeters     = 6371000
+)
+
+// normalizeGeofence 校验围栏中心与半径，半径为 0 时使用默认值
+func normalizeGeofence(fence dto.GeofenceDTO) (float64, float64, int, error) {
+	if fence.Latitude == nil || fence.Longitude == nil {
+		return 0, 0, 0, pkgerrors.JourneyGeofenceInvalid
+	}
+	lat, lon := *fence.Latitude, *fence.Longitude
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 || (lat == 0 && lon == 0) {
+		return 0, 0, 0, pkgerrors.JourneyGeofenceInvalid
+	}
+
+	radius := fence.Radius
+	if radius == 0 {
+		radius = geofenceDefaultRadius
+	}
+	if radius < geofenceMinRadius || radius > geofenceMaxRadius {
+		return 0, 0, 0, pkgerrors.JourneyGeofenceInvalid
+	}
+	return lat, lon, radius, nil
+}
+
+// resolveJourneyDestination 行程目的地围栏写入 journeys 的字段，use_home 时复制用户当前的家
+// 围栏在创建（修改）时确定，之后修改家的位置不影响进行中的行程
+func resolveJourneyDestination(user *model.User, dest *dto.JourneyDestinationInput) (map[string]interface{}, error) {
+	fence := dest.GeofenceDTO
+	if dest.UseHome {
+		if user.HomeLatitude == nil || user.HomeLongitude == nil || user.HomeRadius == 0 {
+			return nil, pkgerrors.HomeLocationNotSet
+		}
+		fence = dto.GeofenceDTO{
+			Latitude:  user.HomeLatitude,
+			Longitude: user.HomeLongitude,
+			Radius:    user.HomeRadius,
+		}
+	}
+
+	lat, lon, radius, err := normalizeGeofence(fence)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"dest_latitude":    lat,
+		"dest_longitude":   lon,
+		"dest_radius":      radius,
+		"geofence_left_at": nil,
+	}, nil
+}
+
+// journeyDestination 行程目的地围栏，没有时返回 nil
+func journeyDestination(journey *model.Journey) *dto.GeofenceDTO {
+	if journey.DestRadius == 0 || journey.DestLatitude == nil || journey.DestLongitude == nil {
+		return nil
+	}
+	return &dto.GeofenceDTO{
+		Latitude:  journey.DestLatitude,
+		Longitude: journey.DestLongitude,
+		Radius:    journey.DestRadius,
+	}
+}
+
+// geoDistanceMeters 两点间的球面距离（米）
+func geoDistanceMeters(lat1, lon1, lat2, lon2 float64) float64 {
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLon := (lon2 - lon1) * rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// applyJourneyGeofence 按新上报的定位点判断是否到达目的地，到达时自动结束行程
+// 行程开始时往往就在围栏内（比如以家为目的地），只有先出现一个确定在围栏外的点，之后再出现确定在围栏内的点才算到达；
+// 精度圆与围栏边界相交的点不参与判断
+func (s *JourneyService) applyJourneyGeofence(
+	ctx context.Context,
+	q *query.Query,
+	publicUserID int64,
+	journey *model.Journey,
+	locations []*model.JourneyLocation,
+) (bool, error) {
+	fence := journeyDestination(journey)
+	if fence == nil {
+		return false, nil
+	}
+
+	sorted := make([]*model.JourneyLocation, len(locations))
+	copy(sorted, locations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].RecordedAt.Before(sorted[j].RecordedAt)
+	})
+
+	radius := float64(fence.Radius)
+	leftAt := journey.GeofenceLeftAt
+	var arrivedAt *time.Time
+	for _, location := range sorted {
+		distance := geoDistanceMeters(*fence.Latitude, *fence.Longitude, location.Latitude, location.Longitude)
+		if leftAt == nil {
+			if distance > radius+location.Accuracy {
+				recordedAt := location.RecordedAt
+				leftAt = &recordedAt
+			}
+			continue
+		}
+		if distance+location.Accuracy <= radius && location.RecordedAt.After(*leftAt) {
+			recordedAt := location.RecordedAt
+			arrivedAt = &recordedAt
+			break
+		}
+	}
+
+	if journey.GeofenceLeftAt == nil && leftAt != nil {
+		if _, err := q.Journey.
+			Where(q.Journey.ID.Eq(journey.ID)).
+			Where(q.Journey.GeofenceLeftAt.IsNull()).
+			Update(q.Journey.GeofenceLeftAt, *leftAt); err != nil {
+			return false, fmt.Errorf("failed to record geofence departure: %w", err)
+		}
+	}
+
+	if arrivedAt == nil {
+		return false, nil
+	}
+
+	if _, err := s.CompleteJourney(ctx, publicUserID, journey.ID, model.JourneyCompletedByGeofence); err != nil {
+		// 行程已经结束或超时，不再自动结束
+		var def pkgerrors.Definition
+		if errors.As(err, &def) {
+			logger.Logger.Info("Journey not completed by geofence",
+				zap.Int64("journey_id", journey.ID),
+				zap.String("reason", def.Code),
+			)
+			return false, nil
+		}
+		return false, err
+	}
+
+	logger.Logger.Info("Journey completed by geofence",
+		zap.Int64("journey_id", journey.ID),
+		zap.Time("arrived_at", *arrivedAt),
+	)
+	return true, nil
+}
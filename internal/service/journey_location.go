@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"AreYouOK/config"
+	"AreYouOK/internal/model"
+	"AreYouOK/internal/model/dto"
+	"AreYouOK/internal/repository/query"
+	pkgerrors "AreYouOK/pkg/errors"
+	"AreYouOK/pkg/logger"
+	"AreYouOK/storage/database"
+)
+
+const (
+	journeyLocationMaxBatch = 50
+	journeyLocationMaxSkew  = 2 * time.Minute // 允许设备时钟比服务器快的误差
+	journeyLocationMaxAcc   = 5000            // 精度差于 5 公里的点没有参考价值
+
+	journeyLocationPurgeBatch = 500 // 清理位置点时每批处理的行程数
+)
+
+// ReportLocations 上报进行中行程的定位点
+// 丢弃坐标不合法、精度过差、早于行程创建或晚于当前时间的点；每个行程只保留最近 JOURNEY_LOCATION_MAX_POINTS 个点
+// 行程设置了目的地围栏时，离开围栏后再进入即自动结束行程
+func (s *JourneyService) ReportLocations(
+	ctx context.Context,
+	publicUserID int64,
+	journeyID int64,
+	req dto.ReportJourneyLocationsRequest,
+) (*dto.ReportJourneyLocationsResponse, error) {
+	if len(req.Points) == 0 || len(req.Points) > journeyLocationMaxBatch {
+		return nil, pkgerrors.JourneyLocationInvalid
+	}
+
+	db := database.DB().WithContext(ctx)
+	q := query.Use(db)
+
+	journey, err := q.Journey.GetByPublicIDAndJourneyID(publicUserID, journeyID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, pkgerrors.Definition{
+				Code:    "JOURNEY_NOT_FOUND",
+				Message: "Journey not found",
+			}
+		}
+		return nil, fmt.Errorf("failed to query journey: %w", err)
+	}
+	if journey.Status != model.JourneyStatusOngoing {
+		return nil, pkgerrors.JourneyNotModifiable
+	}
+
+	now := time.Now()
+	locations := make([]*model.JourneyLocation, 0, len(req.Points))
+	for _, point := range req.Points {
+		if !validJourneyLocation(point, journey.CreatedAt, now) {
+			continue
+		}
+		locations = append(locations, &model.JourneyLocation{
+			JourneyID:  journey.ID,
+			Latitude:   *point.Latitude,
+			Longitude:  *point.Longitude,
+			Accuracy:   point.Accuracy,
+			RecordedAt: point.Timestamp,
+		})
+	}
+
+	result := &dto.ReportJourneyLocationsResponse{
+		Accepted: len(locations),
+		Rejected: len(req.Points) - len(locations),
+	}
+
+	if len(locations) > 0 {
+		err = db.Transaction(func(tx *gorm.DB) error {
+			txQ := query.Use(tx)
+			if err := txQ.JourneyLocation.CreateInBatches(locations, journeyLocationMaxBatch); err != nil {
+				return fmt.Errorf("failed to save journey locations: %w", err)
+			}
+			return trimJourneyLocations(txQ, journey.ID)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	last, err := latestJourneyLocation(q, journey.ID)
+	if err != nil {
+		return nil, err
+	}
+	result.LastLocation = journeyLastLocation(last)
+
+	if len(locations) > 0 {
+		// 位置已经保存，自动结束失败不影响上报结果
+		completed, err := s.applyJourneyGeofence(ctx, q, publicUserID, journey, locations)
+		if err != nil {
+			logger.Logger.Error("Failed to apply journey geofence",
+				zap.Int64("journey_id", journey.ID),
+				zap.Error(err),
+			)
+		}
+		result.Completed = completed
+	}
+
+	return result, nil
+}
+
+func validJourneyLocation(point dto.JourneyLocationPoint, journeyCreatedAt, now time.Time) bool {
+	if point.Latitude == nil || point.Longitude == nil {
+		return false
+	}
+	lat, lon := *point.Latitude, *point.Longitude
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 || (lat == 0 && lon == 0) {
+		return false
+	}
+	if point.Accuracy < 0 || point.Accuracy > journeyLocationMaxAcc {
+		return false
+	}
+	if point.Timestamp.Before(journeyCreatedAt.Add(-journeyLocationMaxSkew)) || point.Timestamp.After(now.Add(journeyLocationMaxSkew)) {
+		return false
+	}
+	return true
+}
+
+// trimJourneyLocations 只保留行程最近的 JOURNEY_LOCATION_MAX_POINTS 个点
+func trimJourneyLocations(q *query.Query, journeyID int64) error {
+	var stale []int64
+	err := q.JourneyLocation.
+		Where(q.JourneyLocation.JourneyID.Eq(journeyID)).
+		Order(q.JourneyLocation.RecordedAt.Desc(), q.JourneyLocation.ID.Desc()).
+		Offset(config.Cfg.JourneyLocationMax).
+		Pluck(q.JourneyLocation.ID, &stale)
+	if err != nil {
+		return fmt.Errorf("failed to query stale journey locations: %w", err)
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+
+	if _, err := q.JourneyLocation.Unscoped().
+		Where(q.JourneyLocation.ID.In(stale...)).
+		Delete(); err != nil {
+		return fmt.Errorf("failed to trim journey locations: %w", err)
+	}
+	return nil
+}
+
+// latestJourneyLocation 行程最后已知位置，没有上报过时返回 nil
+func latestJourneyLocation(q *query.Query, journeyID int64) (*model.JourneyLocation, error) {
+	locations, err := q.JourneyLocation.
+		Where(q.JourneyLocation.JourneyID.Eq(journeyID)).
+		Order(q.JourneyLocation.RecordedAt.Desc(), q.JourneyLocation.ID.Desc()).
+		Limit(1).
+		Find()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query journey location: %w", err)
+	}
+	if len(locations) == 0 {
+		return nil, nil
+	}
+	return locations[0], nil
+}
+
+func journeyLastLocation(location *model.JourneyLocation) *dto.JourneyLastLocation {
+	if location == nil {
+		return nil
+	}
+	return &dto.JourneyLastLocation{
+		Latitude:   location.Latitude,
+		Longitude:  location.Longitude,
+		Accuracy:   location.Accuracy,
+		RecordedAt: location.RecordedAt,
+		MapLink:    journeyMapLink(location.Latitude, location.Longitude),
+	}
+}
+
+// journeyMapLink 按 JOURNEY_MAP_LINK_URL 生成地图链接
+func journeyMapLink(lat, lon float64) string {
+	return strings.NewReplacer(
+		"{lat}", strconv.FormatFloat(lat, 'f', 6, 64),
+		"{lon}", strconv.FormatFloat(lon, 'f', 6, 64),
+	).Replace(config.Cfg.JourneyMapLinkURL)
+}
+
+// PurgeLocations 删除结束超过 JOURNEY_LOCATION_RETAIN_DAYS 天的行程的位置点，返回删除的行数
+// 结束时间取归来时间与告警时间中较晚的一个，都为空（取消的行程）时使用 updated_at；按批处理，避免一次加载全部行程
+func (s *JourneyService) PurgeLocations(ctx context.Context, now time.Time) (int64, error) {
+	db := database.DB().WithContext(ctx)
+	cutoff := now.AddDate(0, 0, -config.Cfg.JourneyLocationDays)
+
+	var purged int64
+	journeyCount := 0
+	for {
+		var ended []int64
+		if err := db.Unscoped().Model(&model.Journey{}).
+			Where("status <> ?", model.JourneyStatusOngoing).
+			Where("COALESCE(GREATEST(actual_return_time, alert_triggered_at), updated_at) < ?", cutoff).
+			Where("EXISTS (SELECT 1 FROM journey_locations WHERE journey_locations.journey_id = journeys.id)").
+			Order("id").
+			Limit(journeyLocationPurgeBatch).
+			Pluck("id", &ended).Error; err != nil {
+			return purged, fmt.Errorf("failed to query ended journeys: %w", err)
+		}
+		if len(ended) == 0 {
+			break
+		}
+
+		q := query.Use(db)
+		info, err := q.JourneyLocation.Unscoped().
+			Where(q.JourneyLocation.JourneyID.In(ended...)).
+			Delete()
+		if err != nil {
+			return purged, fmt.Errorf("failed to purge journey locations: %w", err)
+		}
+		purged += info.RowsAffected
+		journeyCount += len(ended)
+
+		if len(ended) < journeyLocationPurgeBatch {
+			break
+		}
+	}
+
+	if journeyCount > 0 {
+		logger.Logger.Info("Journey locations purged",
+			zap.Int("journey_count", journeyCount),
+			zap.Int64("location_count", purged),
+		)
+	}
+	return purged, nil
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"AreYouOK/internal/model"
+	pkgerrors "AreYouOK/pkg/errors"
+)
+
+// journeyRRule 重复规则，只支持 RRULE 的子集：每周的某几天、固定时刻
+// FREQ=WEEKLY;BYDAY=MO,WE,FR;BYHOUR=22;BYMINUTE=0
+type journeyRRule struct {
+	days   [7]bool // 按 time.Weekday 索引
+	hour   int
+	minute int
+}
+
+var rruleWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// parseJourneyRRule 解析重复规则，允许带 RRULE: 前缀，不支持的字段返回 JourneyRecurrenceInvalid
+func parseJourneyRRule(rule string) (*journeyRRule, error) {
+	rule = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(rule)), "RRULE:")
+
+	r := &journeyRRule{hour: -1, minute: -1}
+	var freq string
+	for _, part := range strings.Split(rule, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, pkgerrors.JourneyRecurrenceInvalid
+		}
+		switch key {
+		case "FREQ":
+			freq = value
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, ok := rruleWeekdays[day]
+				if !ok {
+					return nil, pkgerrors.JourneyRecurrenceInvalid
+				}
+				r.days[weekday] = true
+			}
+		case "BYHOUR":
+			hour, err := strconv.Atoi(value)
+			if err != nil || hour < 0 || hour > 23 {
+				return nil, pkgerrors.JourneyRecurrenceInvalid
+			}
+			r.hour = hour
+		case "BYMINUTE":
+			minute, err := strconv.Atoi(value)
+			if err != nil || minute < 0 || minute > 59 {
+				return nil, pkgerrors.JourneyRecurrenceInvalid
+			}
+			r.minute = minute
+		default:
+			return nil, pkgerrors.JourneyRecurrenceInvalid
+		}
+	}
+
+	if freq != "WEEKLY" || r.hour < 0 || r.minute < 0 || r.days == [7]bool{} {
+		return nil, pkgerrors.JourneyRecurrenceInvalid
+	}
+	return r, nil
+}
+
+// String 规范化后的规则，星期按 MO..SU 排列
+func (r *journeyRRule) String() string {
+	days := make([]string, 0, 7)
+	for code, weekday := range rruleWeekdays {
+		if r.days[weekday] {
+			days = append(days, code)
+		}
+	}
+	// 周一在前，周日在后
+	sort.Slice(days, func(i, j int) bool {
+		return (rruleWeekdays[days[i]]+6)%7 < (rruleWeekdays[days[j]]+6)%7
+	})
+	return fmt.Sprintf("FREQ=WEEKLY;BYDAY=%s;BYHOUR=%d;BYMINUTE=%d", strings.Join(days, ","), r.hour, r.minute)
+}
+
+// next 严格晚于 after 的下一次出发时间，按用户时区计算（夏令时切换当天以 time.Date 的归一化为准）
+func (r *journeyRRule) next(after time.Time, loc *time.Location) time.Time {
+	local := after.In(loc)
+	for i := 0; i <= 7; i++ {
+		day := local.AddDate(0, 0, i)
+		candidate := time.Date(day.Year(), day.Month(), day.Day(), r.hour, r.minute, 0, 0, loc)
+		if r.days[candidate.Weekday()] && candidate.After(after) {
+			return candidate
+		}
+	}
+	// BYDAY 至少有一天，一周内必然命中
+	return local.AddDate(0, 0, 7)
+}
+
+// userLocation 用户设置的时区，未设置或无法识别时使用北京时间
+func userLocation(user *model.User) *time.Location {
+	if user.Timezone != "" {
+		if loc, err := time.LoadLocation(user.Timezone); err == nil {
+			return loc
+		}
+	}
+	return time.FixedZone("CST", 8*3600)
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"AreYouOK/config"
+	"AreYouOK/internal/model"
+	"AreYouOK/internal/model/dto"
+	"AreYouOK/internal/repository/query"
+	pkgerrors "AreYouOK/pkg/errors"
+	"AreYouOK/pkg/logger"
+	"AreYouOK/storage/database"
+	"AreYouOK/utils"
+)
+
+// 行程公开分享：
+// 用户为进行中的行程生成分享链接发给朋友，朋友无需登录即可查看行程进度；
+// 数据库只保存 token 哈希，每个行程同时只有一个有效链接，重新生成或撤销后旧链接失效；
+// 分享页只展示标题、状态、预计返回时间与检查点进度，最后位置需要用户生成链接时单独开启，且行程结束后不再展示
+
+const journeyShareDefaultHours = 24
+
+// ShareJourney 为进行中的行程生成公开分享链接
+func (s *JourneyService) ShareJourney(
+	ctx context.Context,
+	publicUserID int64,
+	journeyID int64,
+	req dto.CreateJourneyShareRequest,
+) (*dto.JourneyShareData, error) {
+	hours := req.Hours
+	if hours == 0 {
+		hours = journeyShareDefaultHours
+	}
+	if hours < 1 || hours > config.Cfg.JourneyShareMaxHours {
+		return nil, pkgerrors.JourneyShareInvalid
+	}
+
+	db := database.DB().WithContext(ctx)
+	q := query.Use(db)
+
+	journey, err := q.Journey.GetByPublicIDAndJourneyID(publicUserID, journeyID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgerrors.Definition{
+				Code:    "JOURNEY_NOT_FOUND",
+				Message: "Journey not found",
+			}
+		}
+		return nil, fmt.Errorf("failed to query journey: %w", err)
+	}
+	if journey.Status != model.JourneyStatusOngoing {
+		return nil, pkgerrors.JourneyNotModifiable
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("failed to generate share token: %w", err)
+	}
+	token := hex.EncodeToString(b)
+	hash := utils.HashShareToken(token)
+	now := time.Now()
+	expiresAt := now.Add(time.Duration(hours) * time.Hour)
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+		if _, err := txQ.Journey.
+			Where(txQ.Journey.ID.Eq(journey.ID)).
+			Updates(map[string]interface{}{
+				"share_token_hash": hash,
+				"share_expires_at": expiresAt,
+				"share_location":   req.IncludeLocation,
+				"updated_at":       now,
+			}); err != nil {
+			return fmt.Errorf("failed to share journey: %w", err)
+		}
+
+		return recordAudit(ctx, txQ, auditEntry{
+			UserID:     journey.UserID,
+			Action:     model.AuditActionJourneyShare,
+			TargetType: "journey",
+			TargetID:   strconv.FormatInt(journey.ID, 10),
+			Diff: model.JSONB{
+				"hours":            hours,
+				"include_location": req.IncludeLocation,
+			},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Logger.Info("Journey shared",
+		zap.Int64("journey_id", journey.ID),
+		zap.Int64("user_id", publicUserID),
+		zap.Time("expires_at", expiresAt),
+		zap.Bool("include_location", req.IncludeLocation),
+	)
+
+	return &dto.JourneyShareData{
+		ExpiresAt:       expiresAt,
+		Token:           token,
+		URL:             journeyShareLink(token),
+		IncludeLocation: req.IncludeLocation,
+	}, nil
+}
+
+// RevokeJourneyShare 撤销行程分享链接，没有分享时直接返回
+func (s *JourneyService) RevokeJourneyShare(
+	ctx context.Context,
+	publicUserID int64,
+	journeyID int64,
+) error {
+	db := database.DB().WithContext(ctx)
+	q := query.Use(db)
+
+	journey, err := q.Journey.GetByPublicIDAndJourneyID(publicUserID, journeyID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return pkgerrors.Definition{
+				Code:    "JOURNEY_NOT_FOUND",
+				Message: "Journey not found",
+			}
+		}
+		return fmt.Errorf("failed to query journey: %w", err)
+	}
+	if journey.ShareTokenHash == nil {
+		return nil
+	}
+
+	now := time.Now()
+	err = db.Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+		if _, err := txQ.Journey.
+			Where(txQ.Journey.ID.Eq(journey.ID)).
+			Updates(map[string]interface{}{
+				"share_token_hash": nil,
+				"share_expires_at": nil,
+				"share_location":   false,
+				"updated_at":       now,
+			}); err != nil {
+			return fmt.Errorf("failed to revoke journey share: %w", err)
+		}
+
+		return recordAudit(ctx, txQ, auditEntry{
+			UserID:     journey.UserID,
+			Action:     model.AuditActionJourneyUnshare,
+			TargetType: "journey",
+			TargetID:   strconv.FormatInt(journey.ID, 10),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Logger.Info("Journey share revoked",
+		zap.Int64("journey_id", journey.ID),
+		zap.Int64("user_id", publicUserID),
+	)
+	return nil
+}
+
+// GetSharedJourney 分享页查看行程，链接不存在、已撤销或已过期统一返回 JourneyShareNotFound
+func (s *JourneyService) GetSharedJourney(ctx context.Context, token string) (*dto.SharedJourneyData, error) {
+	if token == "" {
+		return nil, pkgerrors.JourneyShareNotFound
+	}
+
+	q := query.Use(database.DB().WithContext(ctx))
+
+	journey, err := q.Journey.
+		Where(q.Journey.ShareTokenHash.Eq(utils.HashShareToken(token))).
+		First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgerrors.JourneyShareNotFound
+		}
+		return nil, fmt.Errorf("failed to query shared journey: %w", err)
+	}
+	if journey.ShareExpiresAt == nil || time.Now().After(*journey.ShareExpiresAt) {
+		return nil, pkgerrors.JourneyShareNotFound
+	}
+
+	checkpoints, err := listJourneyCheckpoints(q, journey.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &dto.SharedJourneyData{
+		ExpectedReturnTime: journey.ExpectedReturnTime,
+		ActualReturnTime:   journey.ActualReturnTime,
+		Title:              journey.Title,
+		Status:             string(journey.Status),
+		Checkpoints:        make([]dto.SharedJourneyCheckpoint, 0, len(checkpoints)),
+	}
+	for _, checkpoint := range checkpoints {
+		data.Checkpoints = append(data.Checkpoints, dto.SharedJourneyCheckpoint{
+			ExpectedAt:  checkpoint.ExpectedAt,
+			CheckedInAt: checkpoint.CheckedInAt,
+			Label:       checkpoint.Label,
+			Status:      string(checkpoint.Status),
+			Seq:         checkpoint.Seq,
+		})
+	}
+
+	// 已归来的行程不再展示位置
+	if journey.ShareLocation && journey.Status != model.JourneyStatusEnded {
+		last, err := latestJourneyLocation(q, journey.ID)
+		if err != nil {
+			return nil, err
+		}
+		data.LastLocation = journeyLastLocation(last)
+	}
+
+	return data, nil
+}
+
+// journeyShareLink 分享页链接，未配置 JOURNEY_SHARE_URL 时只返回 token
+func journeyShareLink(token string) string {
+	base := config.Cfg.JourneyShareURL
+	if base == "" {
+		return token
+	}
+	return base + "?token=" + url.QueryEscape(token)
+}
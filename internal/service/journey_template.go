@@ -0,0 +1,843 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"AreYouOK/config"
+	"AreYouOK/internal/model"
+	"AreYouOK/internal/model/dto"
+	"AreYouOK/internal/repository/query"
+	pkgerrors "AreYouOK/pkg/errors"
+	"AreYouOK/pkg/logger"
+	"AreYouOK/pkg/snowflake"
+	"AreYouOK/storage/database"
+)
+
+// 行程模板与重复行程：
+// 模板保存标题、备注、时长、相对出发时间的检查点与要通知的联系人，可以直接从模板开始行程；
+// 设置每周重复规则后，定时任务提前 JOURNEY_RECURRENCE_LEAD_HOURS 生成每一次的安排（journey_occurrences），
+// 出发前 JOURNEY_PRESTART_REMIND_MINUTES 短信提醒用户确认出发；自动开始的模板到点直接开始行程，
+// 否则超过出发时间 JOURNEY_CONFIRM_GRACE_MINUTES 仍未确认的安排记为跳过
+
+const (
+	journeyMaxTemplates        = 20
+	journeyTemplateMinDuration = 15
+	journeyTemplateMaxDuration = 72 * 60
+	journeyRecurrenceBatchSize = 100
+)
+
+// normalizeJourneyTemplate 校验模板内容，返回规范化后的模板（不含 ID、用户与下一次出发时间）
+func normalizeJourneyTemplate(ctx context.Context, userID int64, req dto.JourneyTemplateRequest) (*model.JourneyTemplate, error) {
+	title := strings.TrimSpace(req.Title)
+	if title == "" || utf8.RuneCountInString(title) > 64 || utf8.RuneCountInString(req.Note) > 500 {
+		return nil, pkgerrors.JourneyTemplateInvalid
+	}
+	if req.DurationMinutes < journeyTemplateMinDuration || req.DurationMinutes > journeyTemplateMaxDuration {
+		return nil, pkgerrors.JourneyTemplateInvalid
+	}
+
+	var rrule string
+	if strings.TrimSpace(req.RRule) != "" {
+		rule, err := parseJourneyRRule(req.RRule)
+		if err != nil {
+			return nil, err
+		}
+		rrule = rule.String()
+	}
+
+	// 检查点按任意出发时间展开后复用行程检查点的校验：偏移量严格递增且在时长之内
+	checkpoints := make(model.JourneyTemplateCheckpoints, 0, len(req.Checkpoints))
+	for _, cp := range req.Checkpoints {
+		if cp.OffsetMinutes <= 0 {
+			return nil, pkgerrors.JourneyCheckpointInvalid
+		}
+		checkpoints = append(checkpoints, model.JourneyTemplateCheckpoint{
+			Latitude:      cp.Latitude,
+			Longitude:     cp.Longitude,
+			Label:         strings.TrimSpace(cp.Label),
+			Location:      strings.TrimSpace(cp.Location),
+			OffsetMinutes: cp.OffsetMinutes,
+		})
+	}
+	tmpl := &model.JourneyTemplate{
+		Title:           title,
+		Note:            req.Note,
+		RRule:           rrule,
+		Checkpoints:     checkpoints,
+		DurationMinutes: req.DurationMinutes,
+		AutoStart:       req.AutoStart,
+	}
+	now := time.Now()
+	probe := journeyRequestFromTemplate(tmpl, now)
+	if _, err := normalizeJourneyCheckpoints(0, probe.Checkpoints, probe.ExpectedReturnTime, now); err != nil {
+		return nil, err
+	}
+
+	contacts, err := normalizeContactPriorities(ctx, userID, req.ContactPriorities)
+	if err != nil {
+		return nil, err
+	}
+	tmpl.ContactPriorities = contacts
+
+	if tmpl.AlertMessage, err = normalizeJourneyAlertMessage(req.AlertMessage); err != nil {
+		return nil, err
+	}
+
+	return tmpl, nil
+}
+
+// journeyRequestFromTemplate 按出发时间展开模板，得到创建行程的请求
+func journeyRequestFromTemplate(tmpl *model.JourneyTemplate, startAt time.Time) dto.CreateJourneyRequest {
+	req := dto.CreateJourneyRequest{
+		Title:              tmpl.Title,
+		Note:               tmpl.Note,
+		ExpectedReturnTime: startAt.Add(time.Duration(tmpl.DurationMinutes) * time.Minute),
+		Checkpoints:        make([]dto.JourneyCheckpointInput, 0, len(tmpl.Checkpoints)),
+		ContactPriorities:  tmpl.ContactPriorities,
+		AlertMessage:       tmpl.AlertMessage,
+	}
+	for _, cp := range tmpl.Checkpoints {
+		req.Checkpoints = append(req.Checkpoints, dto.JourneyCheckpointInput{
+			ExpectedAt: startAt.Add(time.Duration(cp.OffsetMinutes) * time.Minute),
+			Latitude:   cp.Latitude,
+			Longitude:  cp.Longitude,
+			Label:      cp.Label,
+			Location:   cp.Location,
+		})
+	}
+	return req
+}
+
+// nextTemplateOccurrence 模板在 after 之后的下一次出发时间，没有重复规则时返回 nil
+func nextTemplateOccurrence(tmpl *model.JourneyTemplate, user *model.User, after time.Time) *time.Time {
+	if tmpl.RRule == "" {
+		return nil
+	}
+	rule, err := parseJourneyRRule(tmpl.RRule)
+	if err != nil {
+		return nil
+	}
+	next := rule.next(after, userLocation(user))
+	return &next
+}
+
+// toJourneyTemplateItem 转换为接口返回的模板
+func toJourneyTemplateItem(tmpl *model.JourneyTemplate) dto.JourneyTemplateItem {
+	item := dto.JourneyTemplateItem{
+		NextOccurrenceAt:  tmpl.NextOccurrenceAt,
+		CreatedAt:         tmpl.CreatedAt,
+		ID:                strconv.FormatInt(tmpl.ID, 10),
+		Title:             tmpl.Title,
+		Note:              tmpl.Note,
+		RRule:             tmpl.RRule,
+		Checkpoints:       make([]dto.JourneyTemplateCheckpoint, 0, len(tmpl.Checkpoints)),
+		ContactPriorities: []int(tmpl.ContactPriorities),
+		AlertMessage:      tmpl.AlertMessage,
+		DurationMinutes:   tmpl.DurationMinutes,
+		AutoStart:         tmpl.AutoStart,
+	}
+	if item.ContactPriorities == nil {
+		item.ContactPriorities = []int{}
+	}
+	for _, cp := range tmpl.Checkpoints {
+		item.Checkpoints = append(item.Checkpoints, dto.JourneyTemplateCheckpoint{
+			Latitude:      cp.Latitude,
+			Longitude:     cp.Longitude,
+			Label:         cp.Label,
+			Location:      cp.Location,
+			OffsetMinutes: cp.OffsetMinutes,
+		})
+	}
+	return item
+}
+
+// getJourneyUser 按 public_id 查询用户
+func getJourneyUser(q *query.Query, publicUserID int64) (*model.User, error) {
+	user, err := q.User.GetByPublicID(publicUserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgerrors.Definition{
+				Code:    "USER_NOT_FOUND",
+				Message: "User not found",
+			}
+		}
+		return nil, fmt.Errorf("failed to query user: %w", err)
+	}
+	return user, nil
+}
+
+// getJourneyTemplate 查询用户自己的模板
+func getJourneyTemplate(q *query.Query, userID int64, templateID int64) (*model.JourneyTemplate, error) {
+	tmpl, err := q.JourneyTemplate.
+		Where(q.JourneyTemplate.ID.Eq(templateID)).
+		Where(q.JourneyTemplate.UserID.Eq(userID)).
+		First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgerrors.JourneyTemplateNotFound
+		}
+		return nil, fmt.Errorf("failed to query journey template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// CreateJourneyTemplate 创建行程模板
+func (s *JourneyService) CreateJourneyTemplate(
+	ctx context.Context,
+	publicUserID int64,
+	req dto.JourneyTemplateRequest,
+) (*dto.JourneyTemplateItem, error) {
+	q := query.Use(database.DB().WithContext(ctx))
+
+	user, err := getJourneyUser(q, publicUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := q.JourneyTemplate.Where(q.JourneyTemplate.UserID.Eq(user.ID)).Count()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count journey templates: %w", err)
+	}
+	if count >= journeyMaxTemplates {
+		return nil, pkgerrors.JourneyTemplateLimit
+	}
+
+	tmpl, err := normalizeJourneyTemplate(ctx, user.ID, req)
+	if err != nil {
+		return nil, err
+	}
+	tmpl.UserID = user.ID
+	tmpl.NextOccurrenceAt = nextTemplateOccurrence(tmpl, user, time.Now())
+
+	if err := q.JourneyTemplate.Create(tmpl); err != nil {
+		return nil, fmt.Errorf("failed to create journey template: %w", err)
+	}
+
+	logger.Logger.Info("Journey template created",
+		zap.Int64("template_id", tmpl.ID),
+		zap.Int64("user_id", user.ID),
+		zap.String("rrule", tmpl.RRule),
+	)
+
+	item := toJourneyTemplateItem(tmpl)
+	return &item, nil
+}
+
+// ListJourneyTemplates 用户的行程模板
+func (s *JourneyService) ListJourneyTemplates(ctx context.Context, publicUserID int64) ([]dto.JourneyTemplateItem, error) {
+	q := query.Use(database.DB().WithContext(ctx))
+
+	user, err := getJourneyUser(q, publicUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	templates, err := q.JourneyTemplate.
+		Where(q.JourneyTemplate.UserID.Eq(user.ID)).
+		Order(q.JourneyTemplate.ID).
+		Find()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query journey templates: %w", err)
+	}
+
+	items := make([]dto.JourneyTemplateItem, 0, len(templates))
+	for _, tmpl := range templates {
+		items = append(items, toJourneyTemplateItem(tmpl))
+	}
+	return items, nil
+}
+
+// UpdateJourneyTemplate 修改行程模板（全量替换）
+// 尚未开始的安排按旧模板生成，一并删除后按新规则重新生成
+func (s *JourneyService) UpdateJourneyTemplate(
+	ctx context.Context,
+	publicUserID int64,
+	templateID int64,
+	req dto.JourneyTemplateRequest,
+) (*dto.JourneyTemplateItem, error) {
+	db := database.DB().WithContext(ctx)
+	q := query.Use(db)
+
+	user, err := getJourneyUser(q, publicUserID)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := getJourneyTemplate(q, user.ID, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := normalizeJourneyTemplate(ctx, user.ID, req)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	updated.NextOccurrenceAt = nextTemplateOccurrence(updated, user, now)
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+		if _, err := txQ.JourneyTemplate.
+			Where(txQ.JourneyTemplate.ID.Eq(tmpl.ID)).
+			Updates(map[string]interface{}{
+				"title":              updated.Title,
+				"note":               updated.Note,
+				"rrule":              updated.RRule,
+				"checkpoints":        updated.Checkpoints,
+				"contact_priorities": updated.ContactPriorities,
+				"alert_message":      updated.AlertMessage,
+				"duration_minutes":   updated.DurationMinutes,
+				"auto_start":         updated.AutoStart,
+				"next_occurrence_at": updated.NextOccurrenceAt,
+				"updated_at":         now,
+			}); err != nil {
+			return fmt.Errorf("failed to update journey template: %w", err)
+		}
+		return deleteScheduledOccurrences(txQ, tmpl.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err = getJourneyTemplate(q, user.ID, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Logger.Info("Journey template updated",
+		zap.Int64("template_id", tmpl.ID),
+		zap.Int64("user_id", user.ID),
+		zap.String("rrule", tmpl.RRule),
+	)
+
+	item := toJourneyTemplateItem(tmpl)
+	return &item, nil
+}
+
+// DeleteJourneyTemplate 删除行程模板及尚未开始的安排，已开始或跳过的安排保留记录
+func (s *JourneyService) DeleteJourneyTemplate(ctx context.Context, publicUserID int64, templateID int64) error {
+	db := database.DB().WithContext(ctx)
+	q := query.Use(db)
+
+	user, err := getJourneyUser(q, publicUserID)
+	if err != nil {
+		return err
+	}
+	tmpl, err := getJourneyTemplate(q, user.ID, templateID)
+	if err != nil {
+		return err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+		if _, err := txQ.JourneyTemplate.Where(txQ.JourneyTemplate.ID.Eq(tmpl.ID)).Delete(); err != nil {
+			return fmt.Errorf("failed to delete journey template: %w", err)
+		}
+		return deleteScheduledOccurrences(txQ, tmpl.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Logger.Info("Journey template deleted",
+		zap.Int64("template_id", tmpl.ID),
+		zap.Int64("user_id", user.ID),
+	)
+	return nil
+}
+
+// deleteScheduledOccurrences 删除模板尚未开始的安排
+func deleteScheduledOccurrences(q *query.Query, templateID int64) error {
+	if _, err := q.JourneyOccurrence.
+		Unscoped().
+		Where(q.JourneyOccurrence.TemplateID.Eq(templateID)).
+		Where(q.JourneyOccurrence.Status.Eq(string(model.JourneyOccurrenceScheduled))).
+		Delete(); err != nil {
+		return fmt.Errorf("failed to delete scheduled journey occurrences: %w", err)
+	}
+	return nil
+}
+
+// StartJourneyFromTemplate 以当前时间为出发时间，按模板开始行程
+func (s *JourneyService) StartJourneyFromTemplate(
+	ctx context.Context,
+	publicUserID int64,
+	templateID int64,
+) (*model.Journey, *model.JourneyTimeoutMessage, error) {
+	q := query.Use(database.DB().WithContext(ctx))
+
+	user, err := getJourneyUser(q, publicUserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	tmpl, err := getJourneyTemplate(q, user.ID, templateID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s.CreateJourney(ctx, publicUserID, journeyRequestFromTemplate(tmpl, time.Now()))
+}
+
+// ListJourneyOccurrences 用户的重复行程安排，按出发时间倒序；status 为空时返回全部
+func (s *JourneyService) ListJourneyOccurrences(
+	ctx context.Context,
+	publicUserID int64,
+	status string,
+	limit int,
+) ([]dto.JourneyOccurrenceItem, error) {
+	q := query.Use(database.DB().WithContext(ctx))
+
+	user, err := getJourneyUser(q, publicUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	do := q.JourneyOccurrence.Where(q.JourneyOccurrence.UserID.Eq(user.ID))
+	if status != "" {
+		do = do.Where(q.JourneyOccurrence.Status.Eq(status))
+	}
+	occurrences, err := do.Order(q.JourneyOccurrence.StartAt.Desc()).Limit(limit).Find()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query journey occurrences: %w", err)
+	}
+
+	// 模板删除后仍保留历史安排，标题从已删除的模板中读取
+	templateIDs := make([]int64, 0, len(occurrences))
+	for _, occurrence := range occurrences {
+		templateIDs = append(templateIDs, occurrence.TemplateID)
+	}
+	titles := make(map[int64]string, len(templateIDs))
+	if len(templateIDs) > 0 {
+		templates, err := q.JourneyTemplate.Unscoped().Where(q.JourneyTemplate.ID.In(templateIDs...)).Find()
+		if err != nil {
+			return nil, fmt.Errorf("failed to query journey templates: %w", err)
+		}
+		for _, tmpl := range templates {
+			titles[tmpl.ID] = tmpl.Title
+		}
+	}
+
+	items := make([]dto.JourneyOccurrenceItem, 0, len(occurrences))
+	for _, occurrence := range occurrences {
+		item := dto.JourneyOccurrenceItem{
+			StartAt:        occurrence.StartAt,
+			ReminderSentAt: occurrence.ReminderSentAt,
+			ID:             strconv.FormatInt(occurrence.ID, 10),
+			TemplateID:     strconv.FormatInt(occurrence.TemplateID, 10),
+			Title:          titles[occurrence.TemplateID],
+			Status:         string(occurrence.Status),
+			SkipReason:     string(occurrence.SkipReason),
+		}
+		if occurrence.JourneyID != nil {
+			item.JourneyID = strconv.FormatInt(*occurrence.JourneyID, 10)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// getJourneyOccurrence 查询用户自己的安排
+func getJourneyOccurrence(q *query.Query, userID int64, occurrenceID int64) (*model.JourneyOccurrence, error) {
+	occurrence, err := q.JourneyOccurrence.
+		Where(q.JourneyOccurrence.ID.Eq(occurrenceID)).
+		Where(q.JourneyOccurrence.UserID.Eq(userID)).
+		First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgerrors.JourneyOccurrenceNotFound
+		}
+		return nil, fmt.Errorf("failed to query journey occurrence: %w", err)
+	}
+	return occurrence, nil
+}
+
+// ConfirmJourneyOccurrence 用户确认出发，按安排开始行程
+// 在出发时间之后、宽限期之内确认时，以确认时间作为出发时间
+func (s *JourneyService) ConfirmJourneyOccurrence(
+	ctx context.Context,
+	publicUserID int64,
+	occurrenceID int64,
+) (*model.Journey, *model.JourneyTimeoutMessage, error) {
+	q := query.Use(database.DB().WithContext(ctx))
+
+	user, err := getJourneyUser(q, publicUserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	occurrence, err := getJourneyOccurrence(q, user.ID, occurrenceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	if occurrence.Status != model.JourneyOccurrenceScheduled || now.After(occurrence.StartAt.Add(journeyConfirmGrace())) {
+		return nil, nil, pkgerrors.JourneyOccurrenceClosed
+	}
+
+	journey, timeoutMsg, err := s.startJourneyOccurrence(ctx, q, user, occurrence, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	if journey == nil {
+		return nil, nil, pkgerrors.JourneyOccurrenceClosed
+	}
+	return journey, timeoutMsg, nil
+}
+
+// SkipJourneyOccurrence 用户跳过一次安排
+func (s *JourneyService) SkipJourneyOccurrence(ctx context.Context, publicUserID int64, occurrenceID int64) error {
+	q := query.Use(database.DB().WithContext(ctx))
+
+	user, err := getJourneyUser(q, publicUserID)
+	if err != nil {
+		return err
+	}
+	occurrence, err := getJourneyOccurrence(q, user.ID, occurrenceID)
+	if err != nil {
+		return err
+	}
+
+	skipped, err := skipJourneyOccurrence(q, occurrence.ID, model.JourneyOccurrenceSkipUser)
+	if err != nil {
+		return err
+	}
+	if !skipped {
+		return pkgerrors.JourneyOccurrenceClosed
+	}
+
+	logger.Logger.Info("Journey occurrence skipped",
+		zap.Int64("occurrence_id", occurrence.ID),
+		zap.Int64("user_id", user.ID),
+		zap.String("reason", string(model.JourneyOccurrenceSkipUser)),
+	)
+	return nil
+}
+
+// skipJourneyOccurrence 将尚未开始的安排记为跳过，安排已开始或已跳过时返回 false
+func skipJourneyOccurrence(q *query.Query, occurrenceID int64, reason model.JourneyOccurrenceSkipReason) (bool, error) {
+	info, err := q.JourneyOccurrence.
+		Where(q.JourneyOccurrence.ID.Eq(occurrenceID)).
+		Where(q.JourneyOccurrence.Status.Eq(string(model.JourneyOccurrenceScheduled))).
+		Updates(map[string]interface{}{
+			"status":      model.JourneyOccurrenceSkipped,
+			"skip_reason": reason,
+			"updated_at":  time.Now(),
+		})
+	if err != nil {
+		return false, fmt.Errorf("failed to skip journey occurrence: %w", err)
+	}
+	return info.RowsAffected > 0, nil
+}
+
+// startJourneyOccurrence 按安排开始行程，出发时间取安排时间与当前时间中较晚的一个
+// 先把安排标记为已开始防止重复开始，创建行程失败时恢复为待出发；安排已不是待出发时返回 nil
+func (s *JourneyService) startJourneyOccurrence(
+	ctx context.Context,
+	q *query.Query,
+	user *model.User,
+	occurrence *model.JourneyOccurrence,
+	now time.Time,
+) (*model.Journey, *model.JourneyTimeoutMessage, error) {
+	tmpl, err := getJourneyTemplate(q, user.ID, occurrence.TemplateID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info, err := q.JourneyOccurrence.
+		Where(q.JourneyOccurrence.ID.Eq(occurrence.ID)).
+		Where(q.JourneyOccurrence.Status.Eq(string(model.JourneyOccurrenceScheduled))).
+		Updates(map[string]interface{}{
+			"status":     model.JourneyOccurrenceStarted,
+			"updated_at": now,
+		})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to claim journey occurrence: %w", err)
+	}
+	if info.RowsAffected == 0 {
+		return nil, nil, nil
+	}
+
+	startAt := occurrence.StartAt
+	if now.After(startAt) {
+		startAt = now
+	}
+	journey, timeoutMsg, err := s.CreateJourney(ctx, user.PublicID, journeyRequestFromTemplate(tmpl, startAt))
+	if err != nil {
+		if _, rollbackErr := q.JourneyOccurrence.
+			Where(q.JourneyOccurrence.ID.Eq(occurrence.ID)).
+			Updates(map[string]interface{}{
+				"status":     model.JourneyOccurrenceScheduled,
+				"updated_at": time.Now(),
+			}); rollbackErr != nil {
+			logger.Logger.Error("Failed to restore journey occurrence",
+				zap.Int64("occurrence_id", occurrence.ID),
+				zap.Error(rollbackErr),
+			)
+		}
+		return nil, nil, err
+	}
+
+	if _, err := q.JourneyOccurrence.
+		Where(q.JourneyOccurrence.ID.Eq(occurrence.ID)).
+		Update(q.JourneyOccurrence.JourneyID, journey.ID); err != nil {
+		logger.Logger.Warn("Failed to link journey occurrence",
+			zap.Int64("occurrence_id", occurrence.ID),
+			zap.Int64("journey_id", journey.ID),
+			zap.Error(err),
+		)
+	}
+
+	logger.Logger.Info("Journey occurrence started",
+		zap.Int64("occurrence_id", occurrence.ID),
+		zap.Int64("journey_id", journey.ID),
+		zap.Int64("user_id", user.ID),
+	)
+	return journey, timeoutMsg, nil
+}
+
+// journeyConfirmGrace 出发时间之后仍可确认出发的宽限期
+func journeyConfirmGrace() time.Duration {
+	return time.Duration(config.Cfg.JourneyConfirmGrace) * time.Minute
+}
+
+// MaterializeJourneyOccurrences 为重复模板生成未来 JOURNEY_RECURRENCE_LEAD_HOURS 内的安排（定时任务调用）
+// 定时任务停摆期间错过确认宽限期的出发时间不再生成，返回生成的安排数
+func (s *JourneyService) MaterializeJourneyOccurrences(ctx context.Context, now time.Time) (int, error) {
+	db := database.DB().WithContext(ctx)
+	q := query.Use(db)
+
+	horizon := now.Add(time.Duration(config.Cfg.JourneyRecurLeadHours) * time.Hour)
+	templates, err := q.JourneyTemplate.
+		Where(q.JourneyTemplate.NextOccurrenceAt.IsNotNull()).
+		Where(q.JourneyTemplate.NextOccurrenceAt.Lte(horizon)).
+		Order(q.JourneyTemplate.NextOccurrenceAt).
+		Limit(journeyRecurrenceBatchSize).
+		Find()
+	if err != nil {
+		return 0, fmt.Errorf("failed to query due journey templates: %w", err)
+	}
+
+	created := 0
+	for _, tmpl := range templates {
+		user, err := q.User.GetByID(tmpl.UserID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return created, fmt.Errorf("failed to query journey template owner: %w", err)
+		}
+		rule, ruleErr := parseJourneyRRule(tmpl.RRule)
+		if err != nil || ruleErr != nil {
+			// 用户已注销或规则无法解析，停止生成
+			logger.Logger.Warn("Journey template cannot recur, disabling recurrence",
+				zap.Int64("template_id", tmpl.ID),
+				zap.String("rrule", tmpl.RRule),
+			)
+			if _, err := q.JourneyTemplate.
+				Where(q.JourneyTemplate.ID.Eq(tmpl.ID)).
+				Updates(map[string]interface{}{
+					"next_occurrence_at": nil,
+					"updated_at":         now,
+				}); err != nil {
+				return created, fmt.Errorf("failed to disable journey template recurrence: %w", err)
+			}
+			continue
+		}
+
+		loc := userLocation(user)
+		next := *tmpl.NextOccurrenceAt
+		occurrences := make([]*model.JourneyOccurrence, 0, 1)
+		for !next.After(horizon) {
+			if next.Add(journeyConfirmGrace()).After(now) {
+				occurrences = append(occurrences, &model.JourneyOccurrence{
+					TemplateID: tmpl.ID,
+					UserID:     tmpl.UserID,
+					StartAt:    next,
+					Status:     model.JourneyOccurrenceScheduled,
+				})
+			}
+			next = rule.next(next, loc)
+		}
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			txQ := query.Use(tx)
+			// 模板在此期间被修改时下一次出发时间已重新计算，放弃本次生成
+			info, err := txQ.JourneyTemplate.
+				Where(txQ.JourneyTemplate.ID.Eq(tmpl.ID)).
+				Where(txQ.JourneyTemplate.NextOccurrenceAt.Eq(*tmpl.NextOccurrenceAt)).
+				Update(txQ.JourneyTemplate.NextOccurrenceAt, next)
+			if err != nil {
+				return fmt.Errorf("failed to advance journey template: %w", err)
+			}
+			if info.RowsAffected == 0 || len(occurrences) == 0 {
+				occurrences = nil
+				return nil
+			}
+			return txQ.JourneyOccurrence.Clauses(clause.OnConflict{DoNothing: true}).Create(occurrences...)
+		})
+		if err != nil {
+			return created, fmt.Errorf("failed to create journey occurrences: %w", err)
+		}
+		created += len(occurrences)
+	}
+	return created, nil
+}
+
+// RemindJourneyOccurrences 出发前提醒用户确认（定时任务调用），额度不足时不提醒
+// 返回待发布的短信通知消息
+func (s *JourneyService) RemindJourneyOccurrences(ctx context.Context, now time.Time) ([]model.NotificationMessage, error) {
+	q := query.Use(database.DB().WithContext(ctx))
+
+	occurrences, err := q.JourneyOccurrence.
+		Where(q.JourneyOccurrence.Status.Eq(string(model.JourneyOccurrenceScheduled))).
+		Where(q.JourneyOccurrence.ReminderSentAt.IsNull()).
+		Where(q.JourneyOccurrence.StartAt.Lte(now.Add(time.Duration(config.Cfg.JourneyPrestartMinutes) * time.Minute))).
+		Where(q.JourneyOccurrence.StartAt.Gt(now.Add(-journeyConfirmGrace()))).
+		Order(q.JourneyOccurrence.StartAt).
+		Limit(journeyRecurrenceBatchSize).
+		Find()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query journey occurrences to remind: %w", err)
+	}
+
+	messages := make([]model.NotificationMessage, 0, len(occurrences))
+	for _, occurrence := range occurrences {
+		// 先占用提醒标记，避免多个实例重复提醒
+		info, err := q.JourneyOccurrence.
+			Where(q.JourneyOccurrence.ID.Eq(occurrence.ID)).
+			Where(q.JourneyOccurrence.ReminderSentAt.IsNull()).
+			Update(q.JourneyOccurrence.ReminderSentAt, now)
+		if err != nil {
+			return messages, fmt.Errorf("failed to mark journey occurrence reminded: %w", err)
+		}
+		if info.RowsAffected == 0 {
+			continue
+		}
+
+		user, err := q.User.GetByID(occurrence.UserID)
+		if err != nil {
+			logger.Logger.Warn("Failed to query journey occurrence owner",
+				zap.Int64("occurrence_id", occurrence.ID),
+				zap.Error(err),
+			)
+			continue
+		}
+		tmpl, err := getJourneyTemplate(q, user.ID, occurrence.TemplateID)
+		if err != nil {
+			logger.Logger.Warn("Failed to query journey occurrence template",
+				zap.Int64("occurrence_id", occurrence.ID),
+				zap.Error(err),
+			)
+			continue
+		}
+
+		balance, err := Quota().SpendableBalance(ctx, user.ID, model.QuotaChannelSMS)
+		if err != nil {
+			return messages, fmt.Errorf("failed to query SMS quota wallet: %w", err)
+		}
+		if balance < journeyAlertSMSCents {
+			logger.Logger.Warn("Insufficient quota for journey prestart reminder",
+				zap.Int64("user_id", user.ID),
+				zap.Int64("occurrence_id", occurrence.ID),
+				zap.Int("balance", balance),
+			)
+			continue
+		}
+
+		taskCode, err := snowflake.NextID(snowflake.GeneratorTypeTask)
+		if err != nil {
+			return messages, fmt.Errorf("failed to generate task code: %w", err)
+		}
+		task := &model.NotificationTask{
+			TaskCode: taskCode,
+			UserID:   user.ID,
+			Category: model.NotificationCategoryJourneyReminder,
+			Channel:  model.NotificationChannelSMS,
+			Status:   model.NotificationTaskStatusPending,
+			Payload: model.JSONB{
+				"type": "journey_prestart",
+				"trip": tmpl.Title,
+				"time": occurrence.StartAt.In(userLocation(user)).Format("01-02 15:04"),
+			},
+			ScheduledAt: now,
+		}
+		if err := q.NotificationTask.Create(task); err != nil {
+			return messages, fmt.Errorf("failed to create notification task: %w", err)
+		}
+
+		messages = append(messages, model.NotificationMessage{
+			MessageID: fmt.Sprintf("notification_%d", task.TaskCode),
+			TaskCode:  task.TaskCode,
+			UserID:    user.PublicID,
+			Category:  string(task.Category),
+			Channel:   string(task.Channel),
+			Payload:   task.Payload,
+		})
+	}
+	return messages, nil
+}
+
+// AdvanceJourneyOccurrences 处理到达出发时间的安排（定时任务调用）
+// 自动开始的模板在宽限期内开始行程，失败时记为跳过；超过宽限期仍未开始的安排记为未确认跳过
+// 返回新行程的超时消息
+func (s *JourneyService) AdvanceJourneyOccurrences(ctx context.Context, now time.Time) ([]*model.JourneyTimeoutMessage, error) {
+	q := query.Use(database.DB().WithContext(ctx))
+
+	occurrences, err := q.JourneyOccurrence.
+		Where(q.JourneyOccurrence.Status.Eq(string(model.JourneyOccurrenceScheduled))).
+		Where(q.JourneyOccurrence.StartAt.Lte(now)).
+		Order(q.JourneyOccurrence.StartAt).
+		Limit(journeyRecurrenceBatchSize).
+		Find()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query due journey occurrences: %w", err)
+	}
+
+	messages := make([]*model.JourneyTimeoutMessage, 0)
+	for _, occurrence := range occurrences {
+		if !now.Before(occurrence.StartAt.Add(journeyConfirmGrace())) {
+			if _, err := skipJourneyOccurrence(q, occurrence.ID, model.JourneyOccurrenceSkipUnconfirmed); err != nil {
+				return messages, err
+			}
+			logger.Logger.Info("Journey occurrence skipped",
+				zap.Int64("occurrence_id", occurrence.ID),
+				zap.String("reason", string(model.JourneyOccurrenceSkipUnconfirmed)),
+			)
+			continue
+		}
+
+		user, err := q.User.GetByID(occurrence.UserID)
+		if err != nil {
+			logger.Logger.Warn("Failed to query journey occurrence owner",
+				zap.Int64("occurrence_id", occurrence.ID),
+				zap.Error(err),
+			)
+			continue
+		}
+		tmpl, err := getJourneyTemplate(q, user.ID, occurrence.TemplateID)
+		if err != nil || !tmpl.AutoStart {
+			continue
+		}
+
+		_, timeoutMsg, err := s.startJourneyOccurrence(ctx, q, user, occurrence, now)
+		if err != nil {
+			logger.Logger.Warn("Failed to auto start journey occurrence",
+				zap.Int64("occurrence_id", occurrence.ID),
+				zap.Error(err),
+			)
+			if _, err := skipJourneyOccurrence(q, occurrence.ID, model.JourneyOccurrenceSkipFailed); err != nil {
+				return messages, err
+			}
+			continue
+		}
+		if timeoutMsg != nil {
+			messages = append(messages, timeoutMsg)
+		}
+	}
+	return messages, nil
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"AreYouOK/config"
+	"AreYouOK/internal/cache"
+	"AreYouOK/internal/model"
+	"AreYouOK/internal/model/dto"
+	"AreYouOK/internal/repository/query"
+	pkgerrors "AreYouOK/pkg/errors"
+	"AreYouOK/pkg/logger"
+	"AreYouOK/pkg/snowflake"
+	"AreYouOK/storage/database"
+)
+
+const (
+	journeyTimeoutGrace     = 10 * time.Minute // 预计返回时间之后多久视为超时
+	journeyTimeoutMaxDelay  = 24 * time.Hour   // 延迟消息的最大延迟，更远的由定时任务投递
+	journeyExtendMinMinutes = 5
+)
+
+// prepareJourneyTimeout 为行程生成新的超时消息
+// 返回需要写入 journeys.timeout_message_id 的消息 ID 与待发布的消息；
+// 未开启自动通知或延迟超过 24 小时时不构建消息，返回空 ID，由定时任务投递时再分配，
+// 之前投递的消息都会因为 ID 不一致而作废
+func prepareJourneyTimeout(
+	journeyID int64,
+	publicUserID int64,
+	expectedReturnTime time.Time,
+	autoNotify bool,
+	now time.Time,
+) (string, *model.JourneyTimeoutMessage, error) {
+	if !autoNotify {
+		return "", nil, nil
+	}
+
+	delay := expectedReturnTime.Add(journeyTimeoutGrace).Sub(now)
+	if delay <= 0 || delay > journeyTimeoutMaxDelay {
+		logger.Logger.Info("Journey timeout will be handled by scheduled task",
+			zap.Int64("journey_id", journeyID),
+			zap.Duration("delay", delay),
+			zap.Time("expected_return_time", expectedReturnTime),
+		)
+		return "", nil, nil
+	}
+
+	id, err := snowflake.NextID(snowflake.GeneratorTypeMessage)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate message ID: %w", err)
+	}
+
+	msg := &model.JourneyTimeoutMessage{
+		MessageID:    fmt.Sprintf("journey_timeout_%d", id),
+		ScheduledAt:  now.Format(time.RFC3339),
+		JourneyID:    journeyID,
+		UserID:       publicUserID,
+		DelaySeconds: int(delay.Seconds()),
+	}
+	return msg.MessageID, msg, nil
+}
+
+// recordJourneyEvent 追加一条行程时间线事件
+func recordJourneyEvent(q *query.Query, journeyID int64, eventType model.JourneyEventType, data model.JSONB, now time.Time) error {
+	if data == nil {
+		data = model.JSONB{}
+	}
+
+	if err := q.JourneyEvent.Create(&model.JourneyEvent{
+		JourneyID:  journeyID,
+		Type:       eventType,
+		Data:       data,
+		OccurredAt: now,
+	}); err != nil {
+		return fmt.Errorf("failed to record journey event: %w", err)
+	}
+	return nil
+}
+
+// recordJourneyEventBestEffort 时间线写入失败不影响主流程，只记录日志
+func recordJourneyEventBestEffort(q *query.Query, journeyID int64, eventType model.JourneyEventType, data model.JSONB, now time.Time) {
+	if err := recordJourneyEvent(q, journeyID, eventType, data, now); err != nil {
+		logger.Logger.Warn("Failed to record journey event",
+			zap.Int64("journey_id", journeyID),
+			zap.String("type", string(eventType)),
+			zap.Error(err),
+		)
+	}
+}
+
+// ExtendJourney 延长进行中的行程（晚点了）
+// 从当前预计返回时间顺延，已过预计返回时间则从现在开始顺延；次数与累计时长受 JOURNEY_MAX_EXTENSIONS / JOURNEY_MAX_EXTEND_MINUTES 限制
+// 每次延长都会分配新的超时消息 ID，之前投递的超时消息在 consumer 中按 ID 判定为过期
+func (s *JourneyService) ExtendJourney(
+	ctx context.Context,
+	publicUserID int64,
+	journeyID int64,
+	minutes int,
+) (*dto.ExtendJourneyResponse, *model.JourneyTimeoutMessage, error) {
+	if minutes < journeyExtendMinMinutes || minutes > config.Cfg.JourneyMaxExtendMins {
+		return nil, nil, pkgerrors.Definition{
+			Code:    "INVALID_EXTEND_MINUTES",
+			Message: fmt.Sprintf("Extension must be between %d and %d minutes", journeyExtendMinMinutes, config.Cfg.JourneyMaxExtendMins),
+		}
+	}
+
+	var (
+		journey    *model.Journey
+		timeoutMsg *model.JourneyTimeoutMessage
+	)
+
+	err := database.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+
+		found, err := txQ.Journey.GetByPublicIDAndJourneyID(publicUserID, journeyID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return pkgerrors.Definition{
+					Code:    "JOURNEY_NOT_FOUND",
+					Message: "Journey not found",
+				}
+			}
+			return fmt.Errorf("failed to query journey: %w", err)
+		}
+
+		// 锁定行程，避免并发延长或与超时处理交错
+		journey, err = txQ.Journey.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where(txQ.Journey.ID.Eq(found.ID)).
+			First()
+		if err != nil {
+			return fmt.Errorf("failed to lock journey: %w", err)
+		}
+
+		if journey.Status != model.JourneyStatusOngoing {
+			return pkgerrors.Definition{
+				Code:    "JOURNEY_NOT_MODIFIABLE",
+				Message: "Only ongoing journeys can be extended",
+			}
+		}
+		if journey.ExtensionCount >= config.Cfg.JourneyMaxExtensions ||
+			journey.ExtendedMinutes+minutes > config.Cfg.JourneyMaxExtendMins {
+			return pkgerrors.JourneyExtendLimit
+		}
+
+		user, err := txQ.User.GetByID(journey.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to query user: %w", err)
+		}
+
+		now := time.Now()
+		from := journey.ExpectedReturnTime
+		base := from
+		if base.Before(now) {
+			base = now
+		}
+		to := base.Add(time.Duration(minutes) * time.Minute)
+
+		messageID, msg, err := prepareJourneyTimeout(journey.ID, publicUserID, to, user.JourneyAutoNotify, now)
+		if err != nil {
+			return err
+		}
+		timeoutMsg = msg
+
+		if _, err := txQ.Journey.
+			Where(txQ.Journey.ID.Eq(journey.ID)).
+			Updates(map[string]interface{}{
+				"expected_return_time": to,
+				"extension_count":      gorm.Expr("extension_count + ?", 1),
+				"extended_minutes":     gorm.Expr("extended_minutes + ?", minutes),
+				"timeout_message_id":   messageID,
+				"reminder_sent_at":     nil,
+				"updated_at":           now,
+			}); err != nil {
+			return fmt.Errorf("failed to extend journey: %w", err)
+		}
+
+		if err := recordJourneyEvent(txQ, journey.ID, model.JourneyEventExtended, model.JSONB{
+			"from":    from.Format(time.RFC3339),
+			"to":      to.Format(time.RFC3339),
+			"minutes": minutes,
+			"count":   journey.ExtensionCount + 1,
+		}, now); err != nil {
+			return err
+		}
+
+		journey, err = txQ.Journey.GetByID(journey.ID)
+		if err != nil {
+			return fmt.Errorf("failed to query extended journey: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 清除定时任务的投递标记，按新的预计返回时间重新投递提醒与超时消息
+	if err := cache.UnmarkJourneyScheduled(ctx, journey.ID); err != nil {
+		logger.Logger.Warn("Failed to unmark journey scheduled",
+			zap.Int64("journey_id", journey.ID),
+			zap.Error(err),
+		)
+	}
+
+	logger.Logger.Info("Journey extended",
+		zap.Int64("journey_id", journey.ID),
+		zap.Int64("user_id", publicUserID),
+		zap.Int("minutes", minutes),
+		zap.Int("extension_count", journey.ExtensionCount),
+		zap.Time("expected_return_time", journey.ExpectedReturnTime),
+	)
+
+	return &dto.ExtendJourneyResponse{
+		JourneyItem: dto.JourneyItem{
+			ID:                 strconv.FormatInt(journey.ID, 10),
+			Title:              journey.Title,
+			Note:               journey.Note,
+			Status:             string(journey.Status),
+			ExpectedReturnTime: journey.ExpectedReturnTime,
+			ActualReturnTime:   journey.ActualReturnTime,
+			CreatedAt:          journey.CreatedAt,
+		},
+		ExtensionCount:  journey.ExtensionCount,
+		ExtendedMinutes: journey.ExtendedMinutes,
+		ExtensionsLeft:  max(config.Cfg.JourneyMaxExtensions-journey.ExtensionCount, 0),
+		MinutesLeft:     max(config.Cfg.JourneyMaxExtendMins-journey.ExtendedMinutes, 0),
+	}, timeoutMsg, nil
+}
+
+// GetJourneyTimeline 行程时间线，按发生时间正序
+func (s *JourneyService) GetJourneyTimeline(
+	ctx context.Context,
+	publicUserID int64,
+	journeyID int64,
+) ([]dto.JourneyEventItem, error) {
+	q := query.Use(database.DB().WithContext(ctx))
+
+	journey, err := q.Journey.GetByPublicIDAndJourneyID(publicUserID, journeyID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, pkgerrors.Definition{
+				Code:    "JOURNEY_NOT_FOUND",
+				Message: "Journey not found",
+			}
+		}
+		return nil, fmt.Errorf("failed to query journey: %w", err)
+	}
+
+	events, err := q.JourneyEvent.
+		Where(q.JourneyEvent.JourneyID.Eq(journey.ID)).
+		Order(q.JourneyEvent.OccurredAt, q.JourneyEvent.ID).
+		Find()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query journey events: %w", err)
+	}
+
+	items := make([]dto.JourneyEventItem, 0, len(events))
+	for _, event := range events {
+		items = append(items, dto.JourneyEventItem{
+			OccurredAt: event.OccurredAt,
+			Type:       string(event.Type),
+			Data:       event.Data,
+		})
+	}
+	return items, nil
+}
@@ -2,7 +2,7 @@ package service
 
 import (
 	"context"
-	"encoding/base64"
+	stderrors "errors"
 	"fmt"
 	"sync"
 	"time"
@@ -80,6 +80,10 @@ func (s *NotificationService) SendSMS(
 		return nil
 	}
 
+	if task.Status == model.NotificationTaskStatusCancelled {
+		return &errors.SkipMessageError{Reason: "task cancelled"}
+	}
+
 	if task.Status == model.NotificationTaskStatusProcessing {
 		logger.Logger.Warn("Notification task is being processed by another consumer",
 			zap.Int64("task_code", taskCode),
@@ -95,10 +99,34 @@ func (s *NotificationService) SendSMS(
 		return fmt.Errorf("failed to query user: %w", err)
 	}
 
+	// 联系人号码已退订（免打扰名单），不发送也不扣费
+	if contactHash := firstNonEmpty(phoneHash, derefString(task.ContactPhoneHash)); contactHash != "" {
+		optedOut, err := isPhoneHashOptedOut(q, user.ID, contactHash)
+		if err != nil {
+			return err
+		}
+		if optedOut {
+			now := time.Now()
+			_, updateErr := q.NotificationTask.WithContext(ctx).
+				Where(q.NotificationTask.ID.Eq(task.ID)).
+				Updates(map[string]interface{}{
+					"status":            model.NotificationTaskStatusFailed,
+					"processed_at":      now,
+					"sms_status_code":   "OPTED_OUT",
+					"sms_error_message": "联系人已退订",
+				})
+			if updateErr != nil {
+				logger.Logger.Error("Failed to update task status", zap.Error(updateErr))
+			}
+			return &errors.SkipMessageError{Reason: "contact opted out"}
+		}
+	}
+
 	smsUnitPriceCents := 5
 	quotaService := Quota()
 	// 发送成功才扣减
-	if err := quotaService.PreDeduct(ctx, user.ID, model.QuotaChannelSMS, smsUnitPriceCents); err != nil {
+	walletID, err := quotaService.PreDeduct(ctx, user.ID, model.QuotaChannelSMS, smsUnitPriceCents)
+	if err != nil {
 		if errors.IsQuotaInsufficient(err) {
 			// 额度不足，更新任务状态为失败
 			now := time.Now()
@@ -115,6 +143,22 @@ func (s *NotificationService) SendSMS(
 			}
 			return &errors.SkipMessageError{Reason: fmt.Sprintf("quota insufficient: %v", err)}
 		}
+		if stderrors.Is(err, errors.QuotaChannelNotInPlan) {
+			// 套餐不包含短信渠道，更新任务状态为失败
+			now := time.Now()
+			_, updateErr := q.NotificationTask.WithContext(ctx).
+				Where(q.NotificationTask.ID.Eq(task.ID)).
+				Updates(map[string]interface{}{
+					"status":            model.NotificationTaskStatusFailed,
+					"processed_at":      now,
+					"sms_status_code":   "CHANNEL_NOT_IN_PLAN",
+					"sms_error_message": "套餐不包含短信渠道",
+				})
+			if updateErr != nil {
+				logger.Logger.Error("Failed to update task status", zap.Error(updateErr))
+			}
+			return &errors.SkipMessageError{Reason: "channel not in plan"}
+		}
 		return fmt.Errorf("failed to pre-deduct quota: %w", err)
 	}
 
@@ -122,7 +166,7 @@ func (s *NotificationService) SendSMS(
 	smsMsg, err := model.ParseSMSMessage(payload)
 	if err != nil {
 		// 解析失败，退款并更新任务状态
-		refundErr := quotaService.Refund(ctx, user.ID, model.QuotaChannelSMS, smsUnitPriceCents)
+		refundErr := quotaService.Refund(ctx, user.ID, walletID, smsUnitPriceCents)
 		if refundErr != nil {
 			logger.Logger.Error("Failed to refund quota after parse failure",
 				zap.Int64("user_id", user.ID),
@@ -145,9 +189,9 @@ func (s *NotificationService) SendSMS(
 		return &errors.SkipMessageError{Reason: fmt.Sprintf("failed to parse SMS message: %v", err)}
 	}
 
-	phone, err := resolveNotificationPhone(user, task, phoneHash)
+	phone, err := resolveNotificationPhone(q, user, task, phoneHash)
 	if err != nil {
-		refundErr := quotaService.Refund(ctx, user.ID, model.QuotaChannelSMS, smsUnitPriceCents)
+		refundErr := quotaService.Refund(ctx, user.ID, walletID, smsUnitPriceCents)
 		if refundErr != nil {
 			logger.Logger.Error("Failed to refund quota after phone resolution failure",
 				zap.Int64("user_id", user.ID),
@@ -178,7 +222,7 @@ func (s *NotificationService) SendSMS(
 		signName, templateCode, err := cfg.GetSMSTemplateConfig(smsMsg.GetMessageType())
 		if err != nil {
 			// 配置错误，退款
-			quotaService.Refund(ctx, user.ID, model.QuotaChannelSMS, smsUnitPriceCents)
+			quotaService.Refund(ctx, user.ID, walletID, smsUnitPriceCents)
 
 			now := time.Now()
 			_, updateErr := q.NotificationTask.WithContext(ctx).
@@ -206,7 +250,7 @@ func (s *NotificationService) SendSMS(
 	templateParams, err := smsMsg.GetTemplateParams()
 	if err != nil {
 		// 参数错误，退款
-		quotaService.Refund(ctx, user.ID, model.QuotaChannelSMS, smsUnitPriceCents)
+		quotaService.Refund(ctx, user.ID, walletID, smsUnitPriceCents)
 
 		now := time.Now()
 		_, updateErr := q.NotificationTask.WithContext(ctx).
@@ -251,7 +295,7 @@ func (s *NotificationService) SendSMS(
 		metrics.RecordSMSFailed(templateCode, provider, statusCode, smsDuration)
 
 		// 发送失败，退款
-		refundErr := quotaService.Refund(ctx, user.ID, model.QuotaChannelSMS, smsUnitPriceCents)
+		refundErr := quotaService.Refund(ctx, user.ID, walletID, smsUnitPriceCents)
 		if refundErr != nil {
 			logger.Logger.Error("Failed to refund quota after SMS send failure",
 				zap.Int64("user_id", user.ID),
@@ -308,7 +352,7 @@ func (s *NotificationService) SendSMS(
 		txQ := query.Use(tx)
 
 		// 确认扣减
-		if err := quotaService.ConfirmDeduction(ctx, user.ID, model.QuotaChannelSMS, smsUnitPriceCents); err != nil {
+		if err := quotaService.ConfirmDeduction(ctx, user.ID, walletID, smsUnitPriceCents); err != nil {
 			return fmt.Errorf("failed to confirm deduction: %w", err)
 		}
 
@@ -383,10 +427,10 @@ func (s *NotificationService) SendSMS(
 // 	return nil
 // }
 
-func resolveNotificationPhone(user *model.User, task *model.NotificationTask, messagePhoneHash string) (string, error) {
+func resolveNotificationPhone(q *query.Query, user *model.User, task *model.NotificationTask, messagePhoneHash string) (string, error) {
 	hash := firstNonEmpty(messagePhoneHash, derefString(task.ContactPhoneHash))
 	if hash != "" {
-		phone, err := findContactPhoneByHash(user.EmergencyContacts, hash)
+		phone, err := findContactPhoneByHash(q, user.ID, hash)
 		if err != nil {
 			return "", err
 		}
@@ -404,30 +448,49 @@ func resolveNotificationPhone(user *model.User, task *model.NotificationTask, me
 	return "", fmt.Errorf("user phone not available")
 }
 
-func findContactPhoneByHash(contacts model.EmergencyContacts, hash string) (string, error) {
+func findContactPhoneByHash(q *query.Query, userID int64, hash string) (string, error) {
+	// 任务创建后联系人可能已拒绝，只发给仍处于确认状态的联系人
+	contact, err := q.Contact.
+		Where(q.Contact.UserID.Eq(userID)).
+		Where(q.Contact.PhoneHash.Eq(hash)).
+		Where(q.Contact.Status.Eq(string(model.ContactStatusConfirmed))).
+		First()
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return "", fmt.Errorf("failed to query contact: %w", err)
+	}
+	if contact != nil {
+		return decryptContactPhone(contact)
+	}
+
+	// 任务创建后联系人哈希可能已被 rehash 任务重算，按明文匹配当前/上一版本哈希
+	contacts, err := listConfirmedContacts(q, userID)
+	if err != nil {
+		return "", err
+	}
 	for _, contact := range contacts {
-		if contact.PhoneHash != hash {
+		phone, err := decryptContactPhone(contact)
+		if err != nil {
 			continue
 		}
-
-		if contact.PhoneCipherBase64 == "" {
-			return "", fmt.Errorf("contact phone cipher is empty for hash %s", hash)
+		if utils.MatchPhoneHash(phone, hash) {
+			return phone, nil
 		}
+	}
 
-		cipherBytes, err := base64.StdEncoding.DecodeString(contact.PhoneCipherBase64)
-		if err != nil {
-			return "", fmt.Errorf("failed to decode phone cipher: %w", err)
-		}
+	return "", fmt.Errorf("contact phone hash %s not found", hash)
+}
 
-		phone, err := utils.DecryptPhone(cipherBytes)
-		if err != nil {
-			return "", fmt.Errorf("failed to decrypt contact phone: %w", err)
-		}
+func decryptContactPhone(contact *model.Contact) (string, error) {
+	if len(contact.PhoneCipher) == 0 {
+		return "", fmt.Errorf("contact phone cipher is empty")
+	}
 
-		return phone, nil
+	phone, err := utils.DecryptPhone(contact.PhoneCipher)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt contact phone: %w", err)
 	}
 
-	return "", fmt.Errorf("contact phone hash %s not found", hash)
+	return phone, nil
 }
 
 func firstNonEmpty(values ...string) string {
@@ -0,0 +1,377 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"AreYouOK/config"
+	"AreYouOK/internal/model"
+	"AreYouOK/internal/model/dto"
+	"AreYouOK/internal/repository/query"
+	pkgerrors "AreYouOK/pkg/errors"
+	"AreYouOK/pkg/logger"
+	"AreYouOK/pkg/sms"
+	"AreYouOK/storage/database"
+	"AreYouOK/utils"
+)
+
+var (
+	optOutService *OptOutService
+	optOutOnce    sync.Once
+)
+
+func OptOut() *OptOutService {
+	optOutOnce.Do(func() {
+		optOutService = &OptOutService{}
+	})
+	return optOutService
+}
+
+// OptOutService 全局免打扰名单
+// 联系人回复退订关键词（短信上行回调）或点击告警短信中的退订链接后，号码写入 contact_opt_outs；
+// 所有用户下该号码的联系人标记为 opted_out，受影响的用户收到短信提醒更换联系人
+type OptOutService struct{}
+
+// optOutKeywords 运营商要求识别的退订关键词（不区分大小写，忽略首尾空白与标点）
+var optOutKeywords = map[string]bool{
+	"TD":          true,
+	"T":           true,
+	"N":           true,
+	"退订":          true,
+	"STOP":        true,
+	"UNSUBSCRIBE": true,
+}
+
+// optedOutContactNotice 事务提交后待发送给用户的退订提醒
+type optedOutContactNotice struct {
+	userID int64
+	name   string
+}
+
+// HandleInbound 处理短信上行消息，内容为退订关键词的号码加入免打扰名单
+// 返回本次新退订的号码数量；数据库错误时返回 error，由短信平台重试推送
+func (s *OptOutService) HandleInbound(ctx context.Context, messages []sms.InboundMessage) (int, error) {
+	registered := 0
+	for _, msg := range messages {
+		keyword, ok := matchOptOutKeyword(msg.Content)
+		if !ok {
+			logger.Logger.Debug("Ignored inbound SMS without opt-out keyword",
+				zap.String("phone", utils.MaskPhone(msg.Phone)),
+			)
+			continue
+		}
+
+		phone := normalizeInboundPhone(msg.Phone)
+		if !utils.ValidatePhone(phone) {
+			logger.Logger.Warn("Ignored inbound SMS with invalid phone",
+				zap.String("phone", utils.MaskPhone(msg.Phone)),
+			)
+			continue
+		}
+
+		created, err := s.register(ctx, phone, model.ContactOptOutSourceSMSReply, keyword, msg.ReceivedAt)
+		if err != nil {
+			return registered, err
+		}
+		if created {
+			registered++
+		}
+	}
+	return registered, nil
+}
+
+// OptOutByLink 联系人点击告警短信中的退订链接
+// 联系人可能已被用户删除，按 contact_id 查找时包含已删除的记录
+func (s *OptOutService) OptOutByLink(ctx context.Context, contactIDStr, signature string) (*dto.ContactOptOutResponse, error) {
+	contactID, err := strconv.ParseInt(contactIDStr, 10, 64)
+	if err != nil || !utils.VerifyContactOptOut(contactID, signature) {
+		return nil, pkgerrors.ContactOptOutLinkInvalid
+	}
+
+	q := query.Use(database.DB().WithContext(ctx))
+	contact, err := q.Contact.Unscoped().
+		Where(q.Contact.ContactID.Eq(contactID)).
+		First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgerrors.ContactOptOutLinkInvalid
+		}
+		return nil, fmt.Errorf("failed to query contact: %w", err)
+	}
+
+	phone, err := decryptContactPhone(contact)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.register(ctx, phone, model.ContactOptOutSourceLink, "", time.Now()); err != nil {
+		return nil, err
+	}
+
+	return &dto.ContactOptOutResponse{PhoneMasked: utils.MaskPhone(phone)}, nil
+}
+
+// register 把号码加入免打扰名单，并把所有用户下该号码的联系人标记为 opted_out
+// 号码已在名单中时仍会处理遗漏的联系人（例如哈希版本轮换期间添加的），返回是否新写入名单
+func (s *OptOutService) register(
+	ctx context.Context,
+	phone string,
+	source model.ContactOptOutSource,
+	keyword string,
+	at time.Time,
+) (bool, error) {
+	candidates := utils.PhoneHashCandidates(phone)
+	phoneCipher, err := encryptContactPhone(phone)
+	if err != nil {
+		return false, err
+	}
+
+	var (
+		created bool
+		notices []optedOutContactNotice
+	)
+	err = database.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+
+		count, err := txQ.ContactOptOut.
+			Where(txQ.ContactOptOut.PhoneHash.In(candidates...)).
+			Count()
+		if err != nil {
+			return fmt.Errorf("failed to query opt-out registry: %w", err)
+		}
+		if count == 0 {
+			// 并发写入同一号码时只保留一条
+			info := &model.ContactOptOut{
+				OptedOutAt:       at,
+				PhoneHash:        utils.HashPhone(phone),
+				PhoneHashVersion: utils.PhoneHashVersion(),
+				PhoneCipher:      phoneCipher,
+				Source:           source,
+				Keyword:          truncateRunes(keyword, 32),
+			}
+			if err := txQ.ContactOptOut.Clauses(clause.OnConflict{DoNothing: true}).Create(info); err != nil {
+				return fmt.Errorf("failed to create opt-out record: %w", err)
+			}
+			created = info.ID != 0
+		}
+
+		contacts, err := txQ.Contact.
+			Where(txQ.Contact.PhoneHash.In(candidates...)).
+			Where(txQ.Contact.Status.Neq(string(model.ContactStatusOptedOut))).
+			Find()
+		if err != nil {
+			return fmt.Errorf("failed to query contacts: %w", err)
+		}
+
+		now := time.Now()
+		for _, contact := range contacts {
+			// 清除确认 token，退订后确认链接失效
+			info, err := txQ.Contact.
+				Where(txQ.Contact.ID.Eq(contact.ID)).
+				Where(txQ.Contact.Status.Neq(string(model.ContactStatusOptedOut))).
+				Updates(map[string]interface{}{
+					"status":             string(model.ContactStatusOptedOut),
+					"consent_token_hash": nil,
+					"consent_expires_at": nil,
+					"version":            gorm.Expr("version + ?", 1),
+					"updated_at":         now,
+				})
+			if err != nil {
+				return fmt.Errorf("failed to update contact: %w", err)
+			}
+			if info.RowsAffected == 0 {
+				continue
+			}
+
+			if err := recordAudit(ctx, txQ, auditEntry{
+				UserID:     contact.UserID,
+				ContactID:  contact.ContactID,
+				Action:     model.AuditActionContactOptOut,
+				TargetType: "contact",
+				TargetID:   strconv.FormatInt(contact.ContactID, 10),
+				Diff: model.JSONB{
+					"status": model.JSONB{"from": string(contact.Status), "to": string(model.ContactStatusOptedOut)},
+					"source": string(source),
+				},
+			}); err != nil {
+				return err
+			}
+			notices = append(notices, optedOutContactNotice{userID: contact.UserID, name: contact.DisplayName})
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	logger.Logger.Info("Phone opted out of contact notifications",
+		zap.String("phone", utils.MaskPhone(phone)),
+		zap.String("source", string(source)),
+		zap.Bool("created", created),
+		zap.Int("contacts", len(notices)),
+	)
+
+	s.notifyUsers(ctx, notices)
+	return created, nil
+}
+
+// notifyUsers 提醒用户联系人已退订、需要更换联系人，发送失败只记录日志
+func (s *OptOutService) notifyUsers(ctx context.Context, notices []optedOutContactNotice) {
+	q := query.Use(database.DB().WithContext(ctx))
+
+	for _, notice := range notices {
+		user, err := q.User.Where(q.User.ID.Eq(notice.userID)).First()
+		if err != nil {
+			logger.Logger.Warn("Failed to query user for opt-out notice",
+				zap.Int64("user_id", notice.userID),
+				zap.Error(err),
+			)
+			continue
+		}
+		if len(user.PhoneCipher) == 0 {
+			continue
+		}
+
+		phone, err := utils.DecryptPhone(user.PhoneCipher)
+		if err != nil {
+			logger.Logger.Error("Failed to decrypt user phone for opt-out notice",
+				zap.Int64("public_id", user.PublicID),
+				zap.Error(err),
+			)
+			continue
+		}
+
+		if _, err := sms.SendContactOptedOutSMS(ctx, phone, notice.name); err != nil {
+			logger.Logger.Error("Failed to send contact opted-out SMS",
+				zap.Int64("public_id", user.PublicID),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// isPhoneOptedOut 号码是否在免打扰名单中（匹配当前与上一版本哈希）
+func isPhoneOptedOut(q *query.Query, phone string) (bool, error) {
+	count, err := q.ContactOptOut.
+		Where(q.ContactOptOut.PhoneHash.In(utils.PhoneHashCandidates(phone)...)).
+		Count()
+	if err != nil {
+		return false, fmt.Errorf("failed to query opt-out registry: %w", err)
+	}
+	return count > 0, nil
+}
+
+// isPhoneHashOptedOut 按任务中的联系人手机号哈希判断号码是否在免打扰名单中
+// 哈希可能是 rehash 前的版本，先找回联系人明文号码再按当前与上一版本哈希匹配，找不到时只能按原哈希匹配
+func isPhoneHashOptedOut(q *query.Query, userID int64, hash string) (bool, error) {
+	contacts, err := q.Contact.
+		Where(q.Contact.UserID.Eq(userID)).
+		Find()
+	if err != nil {
+		return false, fmt.Errorf("failed to query contacts: %w", err)
+	}
+	for _, contact := range contacts {
+		phone, err := decryptContactPhone(contact)
+		if err != nil {
+			continue
+		}
+		if contact.PhoneHash == hash || utils.MatchPhoneHash(phone, hash) {
+			return isPhoneOptedOut(q, phone)
+		}
+	}
+
+	count, err := q.ContactOptOut.
+		Where(q.ContactOptOut.PhoneHash.Eq(hash)).
+		Count()
+	if err != nil {
+		return false, fmt.Errorf("failed to query opt-out registry: %w", err)
+	}
+	return count > 0, nil
+}
+
+// filterOptedOutContacts 去掉号码在免打扰名单中的联系人（匹配当前与上一版本哈希）
+// 退订时联系人已标记为 opted_out，这里按名单再过滤一次，覆盖退订与生成告警并发的情况
+func filterOptedOutContacts(q *query.Query, contacts []*model.Contact) ([]*model.Contact, error) {
+	if len(contacts) == 0 {
+		return contacts, nil
+	}
+
+	// 名单与联系人的哈希版本可能不同，按明文号码算出各版本哈希再匹配
+	candidates := make(map[*model.Contact][]string, len(contacts))
+	hashes := make([]string, 0, len(contacts)*2)
+	for _, contact := range contacts {
+		contactHashes := []string{contact.PhoneHash}
+		if phone, err := decryptContactPhone(contact); err == nil {
+			contactHashes = append(contactHashes, utils.PhoneHashCandidates(phone)...)
+		}
+		candidates[contact] = contactHashes
+		hashes = append(hashes, contactHashes...)
+	}
+
+	records, err := q.ContactOptOut.
+		Where(q.ContactOptOut.PhoneHash.In(hashes...)).
+		Find()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query opt-out registry: %w", err)
+	}
+	if len(records) == 0 {
+		return contacts, nil
+	}
+
+	optedOut := make(map[string]bool, len(records))
+	for _, record := range records {
+		optedOut[record.PhoneHash] = true
+	}
+
+	filtered := make([]*model.Contact, 0, len(contacts))
+	for _, contact := range contacts {
+		if containsOptedOutHash(optedOut, candidates[contact]) {
+			continue
+		}
+		filtered = append(filtered, contact)
+	}
+	return filtered, nil
+}
+
+func containsOptedOutHash(optedOut map[string]bool, hashes []string) bool {
+	for _, hash := range hashes {
+		if optedOut[hash] {
+			return true
+		}
+	}
+	return false
+}
+
+// contactOptOutLink 告警短信中的退订链接，未配置 CONTACT_OPT_OUT_URL 时提示回复 TD 退订
+func contactOptOutLink(contact *model.Contact) string {
+	base := config.Cfg.ContactOptOutURL
+	if base == "" {
+		return "回复TD退订"
+	}
+	return fmt.Sprintf("%s?c=%d&sig=%s", base, contact.ContactID, utils.SignContactOptOut(contact.ContactID))
+}
+
+// matchOptOutKeyword 短信内容是否为退订关键词，返回规范化后的关键词
+func matchOptOutKeyword(content string) (string, bool) {
+	keyword := strings.ToUpper(strings.Trim(content, " \t\r\n.。!！"))
+	return keyword, optOutKeywords[keyword]
+}
+
+// normalizeInboundPhone 去掉上行号码中的国家码前缀
+func normalizeInboundPhone(phone string) string {
+	phone = strings.TrimSpace(phone)
+	phone = strings.TrimPrefix(phone, "+")
+	if len(phone) == 13 && strings.HasPrefix(phone, "86") {
+		phone = phone[2:]
+	}
+	return phone
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"AreYouOK/config"
+	"AreYouOK/internal/cache"
+	"AreYouOK/internal/model"
+	"AreYouOK/internal/model/dto"
+	"AreYouOK/internal/repository/query"
+	pkgerrors "AreYouOK/pkg/errors"
+	"AreYouOK/pkg/logger"
+	"AreYouOK/pkg/sms"
+	"AreYouOK/storage/database"
+	"AreYouOK/utils"
+)
+
+var (
+	phoneChangeService *PhoneChangeService
+	phoneChangeOnce    sync.Once
+)
+
+func PhoneChange() *PhoneChangeService {
+	phoneChangeOnce.Do(func() {
+		phoneChangeService = &PhoneChangeService{}
+	})
+	return phoneChangeService
+}
+
+// PhoneChangeService 更换绑定手机号
+// 新号码验证复用登录的验证码/滑块流程，更换后其他设备需要重新登录，旧号码会收到短信通知
+type PhoneChangeService struct{}
+
+// SendCaptcha 向新号码发送验证码
+// 发送前先检查二次验证、冷却期与号码占用，避免给不可用的号码发送短信
+func (s *PhoneChangeService) SendCaptcha(
+	ctx context.Context,
+	userID string,
+	sessionID string,
+	req dto.SendCaptchaRequest,
+) error {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkStepUp(ctx, user, sessionID); err != nil {
+		return err
+	}
+	if err := s.checkAvailable(ctx, user, req.Phone); err != nil {
+		return err
+	}
+
+	return Verification().SendCaptcha(ctx, req.Phone, req.SceneId, req.VerifyToken)
+}
+
+// ChangePhone 校验新号码验证码并更换绑定手机号
+// 已绑定手机号的用户需先在当前会话完成旧号码的二次验证，防止会话被盗用后直接改绑
+// sessionID 为发起请求的会话，更换后保留该会话，撤销其余会话
+func (s *PhoneChangeService) ChangePhone(
+	ctx context.Context,
+	userID string,
+	sessionID string,
+	ip string,
+	req dto.ChangePhoneRequest,
+) (*dto.PhoneInfo, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkStepUp(ctx, user, sessionID); err != nil {
+		return nil, err
+	}
+	if err := s.checkAvailable(ctx, user, req.Phone); err != nil {
+		return nil, err
+	}
+
+	if err := Verification().VerifyCaptcha(ctx, req.Phone, req.VerifyCode); err != nil {
+		return nil, err
+	}
+
+	cipherBase64, err := utils.EncryptPhone(req.Phone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt phone: %w", err)
+	}
+	phoneCipher, err := base64.StdEncoding.DecodeString(cipherBase64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode phone cipher: %w", err)
+	}
+
+	// 旧号码在更新前解密，用于事后通知
+	var oldPhone string
+	if len(user.PhoneCipher) > 0 {
+		if oldPhone, err = utils.DecryptPhone(user.PhoneCipher); err != nil {
+			logger.Logger.Warn("Failed to decrypt old phone before change",
+				zap.Int64("user_id", user.ID),
+				zap.Error(err),
+			)
+			oldPhone = ""
+		}
+	}
+
+	var keepSessionID int64
+	if sessionID != "" {
+		keepSessionID, _ = strconv.ParseInt(sessionID, 10, 64)
+	}
+
+	newHash := utils.HashPhone(req.Phone)
+	now := time.Now()
+	changeLog := &model.PhoneChangeLog{
+		UserID:       user.ID,
+		OldPhoneHash: derefString(user.PhoneHash),
+		NewPhoneHash: newHash,
+		SessionID:    keepSessionID,
+		IP:           ip,
+		ChangedAt:    now,
+	}
+
+	db := database.DB().WithContext(ctx)
+	err = db.Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+
+		if _, err := txQ.User.
+			Where(txQ.User.ID.Eq(user.ID)).
+			Updates(map[string]interface{}{
+				"phone_hash":         newHash,
+				"phone_hash_version": utils.PhoneHashVersion(),
+				"phone_cipher":       phoneCipher,
+				"updated_at":         now,
+			}); err != nil {
+			return fmt.Errorf("failed to update phone: %w", err)
+		}
+
+		if err := txQ.PhoneChangeLog.Create(changeLog); err != nil {
+			return fmt.Errorf("failed to create phone change log: %w", err)
+		}
+
+		return recordAudit(ctx, txQ, auditEntry{
+			UserID:     user.ID,
+			SessionID:  keepSessionID,
+			Action:     model.AuditActionPhoneChange,
+			TargetType: "user",
+			TargetID:   userID,
+			Diff:       model.JSONB{"phone": model.JSONB{"changed": true}},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := Session().RevokeOthers(ctx, user.ID, keepSessionID, model.SessionRevokeReasonPhoneChanged); err != nil {
+		logger.Logger.Warn("Failed to revoke other sessions after phone change",
+			zap.Int64("user_id", user.ID),
+			zap.Error(err),
+		)
+	}
+	if keepSessionID != 0 {
+		// 旧版单设备 refresh token 不属于任何会话，一并失效
+		if err := cache.DeleteRefreshToken(ctx, userID); err != nil {
+			logger.Logger.Warn("Failed to delete legacy refresh token after phone change",
+				zap.Int64("user_id", user.ID),
+				zap.Error(err),
+			)
+		}
+	}
+
+	newMasked := utils.MaskPhone(req.Phone)
+	if oldPhone != "" {
+		s.notifyOldPhone(ctx, changeLog, oldPhone, newMasked)
+	}
+
+	logger.Logger.Info("User phone changed",
+		zap.Int64("user_id", user.ID),
+		zap.Int64("session_id", keepSessionID),
+	)
+
+	return &dto.PhoneInfo{
+		NumberMasked: newMasked,
+		Verified:     true,
+	}, nil
+}
+
+// checkStepUp 已绑定手机号时，要求当前会话在有效期内完成了旧号码的二次验证（POST /v1/users/me/step-up）
+// 尚未绑定手机号的账号（首次绑定）无需验证
+func (s *PhoneChangeService) checkStepUp(ctx context.Context, user *model.User, sessionID string) error {
+	if len(user.PhoneCipher) == 0 {
+		return nil
+	}
+	if !StepUp().IsVerified(ctx, sessionID) {
+		return pkgerrors.StepUpRequired
+	}
+	return nil
+}
+
+// checkAvailable 检查冷却期、新号码是否与当前相同以及是否已被占用
+// 占用检查包含恢复窗口内的注销账号，这些账号恢复后仍使用原号码
+func (s *PhoneChangeService) checkAvailable(ctx context.Context, user *model.User, phone string) error {
+	q := query.Use(database.DB().WithContext(ctx))
+
+	if user.PhoneHash != nil && utils.MatchPhoneHash(phone, *user.PhoneHash) {
+		return pkgerrors.PhoneUnchanged
+	}
+
+	if config.Cfg.PhoneChangeCooldown > 0 {
+		since := time.Now().AddDate(0, 0, -config.Cfg.PhoneChangeCooldown)
+		recent, err := q.PhoneChangeLog.
+			Where(q.PhoneChangeLog.UserID.Eq(user.ID)).
+			Where(q.PhoneChangeLog.ChangedAt.Gt(since)).
+			Count()
+		if err != nil {
+			return fmt.Errorf("failed to count phone changes: %w", err)
+		}
+		if recent > 0 {
+			return pkgerrors.PhoneChangeCooldown
+		}
+	}
+
+	taken, err := q.User.Unscoped().
+		Where(q.User.PhoneHash.In(utils.PhoneHashCandidates(phone)...)).
+		Count()
+	if err != nil {
+		return fmt.Errorf("failed to query user by phone_hash: %w", err)
+	}
+	if taken > 0 {
+		return pkgerrors.PhoneAlreadyRegistered
+	}
+
+	return nil
+}
+
+// notifyOldPhone 短信通知旧号码，失败只记录日志
+func (s *PhoneChangeService) notifyOldPhone(ctx context.Context, changeLog *model.PhoneChangeLog, oldPhone, newMasked string) {
+	if _, err := sms.SendPhoneChangedSMS(ctx, oldPhone, newMasked); err != nil {
+		logger.Logger.Warn("Failed to notify old phone after phone change",
+			zap.Int64("user_id", changeLog.UserID),
+			zap.Error(err),
+		)
+		return
+	}
+
+	q := query.Use(database.DB().WithContext(ctx))
+	if _, err := q.PhoneChangeLog.
+		Where(q.PhoneChangeLog.ID.Eq(changeLog.ID)).
+		Update(q.PhoneChangeLog.OldNotified, true); err != nil {
+		logger.Logger.Warn("Failed to mark old phone notified",
+			zap.Int64("log_id", changeLog.ID),
+			zap.Error(err),
+		)
+	}
+}
+
+func (s *PhoneChangeService) getUser(ctx context.Context, userID string) (*model.User, error) {
+	var userIDInt int64
+	if _, err := fmt.Sscanf(userID, "%d", &userIDInt); err != nil {
+		return nil, pkgerrors.InvalidUserID
+	}
+
+	q := query.Use(database.DB().WithContext(ctx))
+	user, err := q.User.GetByPublicID(userIDInt)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgerrors.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to query user: %w", err)
+	}
+	return user, nil
+}
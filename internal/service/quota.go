@@ -1,7 +1,10 @@
 package service
 
 import (
+	"AreYouOK/config"
 	"AreYouOK/internal/model"
+	"AreYouOK/internal/repository/query"
+	"AreYouOK/pkg/snowflake"
 
 	"AreYouOK/storage/database"
 	"context"
@@ -14,6 +17,7 @@ import (
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type QuotaService struct{}
@@ -32,16 +36,35 @@ func Quota() *QuotaService {
 	return quotaService
 }
 
+// personalWalletCond 个人钱包查询条件（排除共享钱包）
+const personalWalletCond = "user_id = ? AND channel = ? AND group_id IS NULL"
+
 // PreDeduct 预扣减额度（冻结）
 // 将 available 减少，通过创建一条 reason='pre_deduct' 的交易记录实现
+// 按成员设置的顺序在个人钱包与共享钱包之间选择付款钱包，返回实际冻结的钱包 ID，
+// 调用方需要用它调用 ConfirmDeduction 或 Refund
+
+func (s *QuotaService) PreDeduct(ctx context.Context, userID int64, channel model.QuotaChannel, amount int) (int64, error) {
+	// 套餐不包含的渠道不允许扣费
+	plan, err := Subscription().PlanForUser(ctx, userID)
+	if err != nil {
+		logger.Logger.Warn("Failed to query user plan, falling back to free plan",
+			zap.Int64("user_id", userID),
+			zap.Error(err),
+		)
+	}
+	if !plan.HasChannel(channel) {
+		return 0, fmt.Errorf("%w", errors.QuotaChannelNotInPlan)
+	}
 
-func (s *QuotaService) PreDeduct(ctx context.Context, userID int64, channel model.QuotaChannel, amount int) error {
 	db := database.DB().WithContext(ctx)
 
-	return db.Transaction(func(tx *gorm.DB) error {
+	var walletID int64
+	err = db.Transaction(func(tx *gorm.DB) error {
 		// 1. 查询钱包（使用悲观锁防止竞态）
 		var wallet model.QuotaWallet
-		if err := tx.Where("user_id = ? AND channel = ?", userID, channel).
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where(personalWalletCond, userID, channel).
 			First(&wallet).Error; err != nil {
 			if err == gorm.ErrRecordNotFound {
 				// 钱包不存在，创建新钱包, 理论上来讲，用户创建的时候钱包就已经创建好了
@@ -61,25 +84,30 @@ func (s *QuotaService) PreDeduct(ctx context.Context, userID int64, channel mode
 			}
 		}
 
-		// 2. 检查可用额度
-		if wallet.AvailableAmount < amount {
+		// 2. 按顺序选出可用额度足够的钱包
+		payer, err := s.resolvePayingWalletTx(tx, userID, &wallet, amount)
+		if err != nil {
+			return err
+		}
+		if payer == nil {
 			return fmt.Errorf("%w", errors.QuotaInsufficient)
 		}
 
 		// 3. 冻结额度
 		updates := map[string]interface{}{
 			"available_amount": gorm.Expr("available_amount - ?", amount),
-			"frozen_amount":   gorm.Expr("frozen_amount + ?", amount),
-			"updated_at":      time.Now(),
+			"frozen_amount":    gorm.Expr("frozen_amount + ?", amount),
+			"updated_at":       time.Now(),
 		}
-		if err := tx.Model(&wallet).Updates(updates).Error; err != nil {
+		if err := tx.Model(payer).Updates(updates).Error; err != nil {
 			return fmt.Errorf("failed to freeze quota: %w", err)
 		}
 
 		// 4. 创建预扣减交易记录
-		newBalance := wallet.AvailableAmount - amount
+		newBalance := payer.AvailableAmount - amount
 		transaction := &model.QuotaTransaction{
 			UserID:          userID,
+			WalletID:        payer.ID,
 			Channel:         channel,
 			TransactionType: model.TransactionTypeDeduct,
 			Reason:          model.QuotaReasonPreDeduct, // 预扣减标识
@@ -93,28 +121,148 @@ func (s *QuotaService) PreDeduct(ctx context.Context, userID int64, channel mode
 
 		logger.Logger.Info("Quota pre-deducted",
 			zap.Int64("user_id", userID),
+			zap.Int64("wallet_id", payer.ID),
+			zap.Bool("shared_wallet", payer.GroupID != nil),
 			zap.String("channel", string(channel)),
 			zap.Int("amount", amount),
-			zap.Int("available_before", wallet.AvailableAmount),
+			zap.Int("available_before", payer.AvailableAmount),
 			zap.Int("available_after", newBalance),
-			zap.Int("frozen_after", wallet.FrozenAmount+amount),
+			zap.Int("frozen_after", payer.FrozenAmount+amount),
 		)
 
+		walletID = payer.ID
 		return nil
 	})
+	if err != nil {
+		return 0, err
+	}
+
+	return walletID, nil
+}
+
+// resolvePayingWalletTx 选出本次付款的钱包
+// 没有加入共享钱包时只使用个人钱包；加入后按成员的 funding_order 依次尝试，
+// 共享钱包还需满足成员的月度消费上限。都不满足时返回 nil
+func (s *QuotaService) resolvePayingWalletTx(tx *gorm.DB, userID int64, personal *model.QuotaWallet, amount int) (*model.QuotaWallet, error) {
+	member, shared, err := s.sharedWalletForUserTx(tx, userID, personal.Channel, true)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := []*model.QuotaWallet{personal}
+	if shared != nil {
+		if member.FundingOrder == model.FundingOrderSharedFirst {
+			candidates = []*model.QuotaWallet{shared, personal}
+		} else {
+			candidates = append(candidates, shared)
+		}
+	}
+
+	for _, wallet := range candidates {
+		if wallet.AvailableAmount < amount {
+			continue
+		}
+		if wallet.GroupID != nil && member.MonthlyCap > 0 {
+			usage, err := s.walletUsageByUser(tx, wallet.ID, []int64{userID}, monthStart(time.Now()), time.Time{})
+			if err != nil {
+				return nil, err
+			}
+			if usage[userID]+amount > member.MonthlyCap {
+				continue
+			}
+		}
+		return wallet, nil
+	}
+
+	return nil, nil
+}
+
+// sharedWalletForUserTx 查询用户所在组的共享钱包，未加入共享钱包时返回 nil
+func (s *QuotaService) sharedWalletForUserTx(
+	tx *gorm.DB,
+	userID int64,
+	channel model.QuotaChannel,
+	forUpdate bool,
+) (*model.WalletGroupMember, *model.QuotaWallet, error) {
+	var member model.WalletGroupMember
+	if err := tx.Where("user_id = ?", userID).First(&member).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("failed to query wallet group member: %w", err)
+	}
+
+	walletTx := tx
+	if forUpdate {
+		walletTx = tx.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+
+	var shared model.QuotaWallet
+	if err := walletTx.Where("group_id = ? AND channel = ?", member.GroupID, channel).
+		First(&shared).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return &member, nil, nil
+		}
+		return nil, nil, fmt.Errorf("failed to query shared wallet: %w", err)
+	}
+
+	return &member, &shared, nil
+}
+
+// walletUsageByUser 统计各用户在 [since, until) 内从指定钱包消费的额度
+// 消费 = 预扣减 - 退款，until 为零值时不限制结束时间
+func (s *QuotaService) walletUsageByUser(
+	db *gorm.DB,
+	walletID int64,
+	userIDs []int64,
+	since time.Time,
+	until time.Time,
+) (map[int64]int, error) {
+	type usageRow struct {
+		UserID int64
+		Spent  int
+	}
+
+	stmt := db.Model(&model.QuotaTransaction{}).
+		Select("user_id, COALESCE(SUM(CASE WHEN reason = ? THEN amount WHEN reason = ? THEN -amount ELSE 0 END), 0) AS spent",
+			model.QuotaReasonPreDeduct, model.QuotaReasonGrantRefund).
+		Where("wallet_id = ? AND created_at >= ?", walletID, since)
+	if !until.IsZero() {
+		stmt = stmt.Where("created_at < ?", until)
+	}
+	if len(userIDs) > 0 {
+		stmt = stmt.Where("user_id IN ?", userIDs)
+	}
+
+	var rows []usageRow
+	if err := stmt.Group("user_id").Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to query wallet usage: %w", err)
+	}
+
+	usage := make(map[int64]int, len(rows))
+	for _, row := range rows {
+		usage[row.UserID] = row.Spent
+	}
+	return usage, nil
+}
+
+// monthStart 所在自然月的第一天零点
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
 }
 
 // ConfirmDeduction 确认扣减（解冻并正式扣除）
 // 余额不变（因为已经预扣减了），但创建一条确认记录用于审计
 // 也就是消费者正式消费后扣减, 需要根据消费是否成功来扣减
+// walletID 为 PreDeduct 返回的付款钱包
 
-func (s *QuotaService) ConfirmDeduction(ctx context.Context, userID int64, channel model.QuotaChannel, amount int) error {
+func (s *QuotaService) ConfirmDeduction(ctx context.Context, userID int64, walletID int64, amount int) error {
 	db := database.DB().WithContext(ctx)
 
 	return db.Transaction(func(tx *gorm.DB) error {
 		// 1. 查询钱包
 		var wallet model.QuotaWallet
-		if err := tx.Where("user_id = ? AND channel = ?", userID, channel).
+		if err := tx.Where("id = ?", walletID).
 			First(&wallet).Error; err != nil {
 			return fmt.Errorf("failed to query wallet: %w", err)
 		}
@@ -137,7 +285,8 @@ func (s *QuotaService) ConfirmDeduction(ctx context.Context, userID int64, chann
 		// 4. 创建确认扣减交易记录
 		transaction := &model.QuotaTransaction{
 			UserID:          userID,
-			Channel:         channel,
+			WalletID:        wallet.ID,
+			Channel:         wallet.Channel,
 			TransactionType: model.TransactionTypeDeduct,
 			Reason:          model.QuotaReasonConfirmDeduct, // 确认扣减标识
 			Amount:          amount,
@@ -150,7 +299,8 @@ func (s *QuotaService) ConfirmDeduction(ctx context.Context, userID int64, chann
 
 		logger.Logger.Info("Quota deduction confirmed",
 			zap.Int64("user_id", userID),
-			zap.String("channel", string(channel)),
+			zap.Int64("wallet_id", wallet.ID),
+			zap.String("channel", string(wallet.Channel)),
 			zap.Int("amount", amount),
 			zap.Int("frozen_before", wallet.FrozenAmount),
 			zap.Int("frozen_after", wallet.FrozenAmount-amount),
@@ -162,15 +312,15 @@ func (s *QuotaService) ConfirmDeduction(ctx context.Context, userID int64, chann
 }
 
 // Refund 退款（解冻）
-// 将余额回复(balance_after 增加)
+// 将余额回复(balance_after 增加)，walletID 为 PreDeduct 返回的付款钱包
 
-func (s *QuotaService) Refund(ctx context.Context, userID int64, channel model.QuotaChannel, amount int) error {
+func (s *QuotaService) Refund(ctx context.Context, userID int64, walletID int64, amount int) error {
 	db := database.DB().WithContext(ctx)
 
 	return db.Transaction(func(tx *gorm.DB) error {
 		// 1. 查询钱包
 		var wallet model.QuotaWallet
-		if err := tx.Where("user_id = ? AND channel = ?", userID, channel).
+		if err := tx.Where("id = ?", walletID).
 			First(&wallet).Error; err != nil {
 			return fmt.Errorf("failed to query wallet: %w", err)
 		}
@@ -183,8 +333,8 @@ func (s *QuotaService) Refund(ctx context.Context, userID int64, channel model.Q
 		// 3. 解冻并恢复到可用额度
 		updates := map[string]interface{}{
 			"available_amount": gorm.Expr("available_amount + ?", amount),
-			"frozen_amount":   gorm.Expr("frozen_amount - ?", amount),
-			"updated_at":      time.Now(),
+			"frozen_amount":    gorm.Expr("frozen_amount - ?", amount),
+			"updated_at":       time.Now(),
 		}
 		if err := tx.Model(&wallet).Updates(updates).Error; err != nil {
 			return fmt.Errorf("failed to refund quota: %w", err)
@@ -194,7 +344,8 @@ func (s *QuotaService) Refund(ctx context.Context, userID int64, channel model.Q
 		newBalance := wallet.AvailableAmount + amount
 		transaction := &model.QuotaTransaction{
 			UserID:          userID,
-			Channel:         channel,
+			WalletID:        wallet.ID,
+			Channel:         wallet.Channel,
 			TransactionType: model.TransactionTypeGrant,   // 退款算作充值
 			Reason:          model.QuotaReasonGrantRefund, // 退款标识
 			Amount:          amount,
@@ -207,7 +358,8 @@ func (s *QuotaService) Refund(ctx context.Context, userID int64, channel model.Q
 
 		logger.Logger.Info("Quota refunded",
 			zap.Int64("user_id", userID),
-			zap.String("channel", string(channel)),
+			zap.Int64("wallet_id", wallet.ID),
+			zap.String("channel", string(wallet.Channel)),
 			zap.Int("amount", amount),
 			zap.Int("available_before", wallet.AvailableAmount),
 			zap.Int("available_after", newBalance),
@@ -219,12 +371,12 @@ func (s *QuotaService) Refund(ctx context.Context, userID int64, channel model.Q
 	})
 }
 
-// GetWallet 获取用户钱包信息
+// GetWallet 获取用户个人钱包信息（不含共享钱包）
 func (s *QuotaService) GetWallet(ctx context.Context, userID int64, channel model.QuotaChannel) (*model.QuotaWallet, error) {
 	db := database.DB().WithContext(ctx)
 
 	var wallet model.QuotaWallet
-	if err := db.Where("user_id = ? AND channel = ?", userID, channel).
+	if err := db.Where(personalWalletCond, userID, channel).
 		First(&wallet).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			// 钱包不存在，返回空钱包
@@ -243,66 +395,233 @@ func (s *QuotaService) GetWallet(ctx context.Context, userID int64, channel mode
 	return &wallet, nil
 }
 
+// SpendableBalance 用户当前可用于扣费的额度
+// 个人钱包余额 + 共享钱包中本月消费上限内仍可使用的部分
+func (s *QuotaService) SpendableBalance(ctx context.Context, userID int64, channel model.QuotaChannel) (int, error) {
+	return s.spendableBalanceTx(database.DB().WithContext(ctx), userID, channel)
+}
+
+func (s *QuotaService) spendableBalanceTx(tx *gorm.DB, userID int64, channel model.QuotaChannel) (int, error) {
+	balance := 0
+
+	var wallet model.QuotaWallet
+	if err := tx.Where(personalWalletCond, userID, channel).First(&wallet).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return 0, fmt.Errorf("failed to query wallet: %w", err)
+		}
+	} else {
+		balance = wallet.AvailableAmount
+	}
+
+	member, shared, err := s.sharedWalletForUserTx(tx, userID, channel, false)
+	if err != nil {
+		return 0, err
+	}
+	if shared == nil {
+		return balance, nil
+	}
+
+	sharedAvailable := shared.AvailableAmount
+	if member.MonthlyCap > 0 {
+		usage, err := s.walletUsageByUser(tx, shared.ID, []int64{userID}, monthStart(time.Now()), time.Time{})
+		if err != nil {
+			return 0, err
+		}
+		remaining := member.MonthlyCap - usage[userID]
+		if remaining < 0 {
+			remaining = 0
+		}
+		if remaining < sharedAvailable {
+			sharedAvailable = remaining
+		}
+	}
+
+	return balance + sharedAvailable, nil
+}
+
 // GrantQuota 充值额度
 func (s *QuotaService) GrantQuota(ctx context.Context, userID int64, channel model.QuotaChannel, amount int, reason string) error {
 	db := database.DB().WithContext(ctx)
 
 	return db.Transaction(func(tx *gorm.DB) error {
-		// 1. 查询或创建钱包
-		var wallet model.QuotaWallet
-		if err := tx.Where("user_id = ? AND channel = ?", userID, channel).
-			First(&wallet).Error; err != nil {
-			if err == gorm.ErrRecordNotFound {
-				// 创建新钱包
-				wallet = model.QuotaWallet{
-					UserID:          userID,
-					Channel:         channel,
-					AvailableAmount: amount,
-					FrozenAmount:    0,
-					UsedAmount:      0,
-					TotalGranted:    amount,
-				}
-				if err := tx.Create(&wallet).Error; err != nil {
-					return fmt.Errorf("failed to create wallet: %w", err)
-				}
-			} else {
-				return fmt.Errorf("failed to query wallet: %w", err)
+		return s.grantQuotaTx(tx, userID, channel, amount, reason)
+	})
+}
+
+// grantQuotaTx 在调用方的事务中充值额度，便于与其他写操作保持原子性
+func (s *QuotaService) grantQuotaTx(tx *gorm.DB, userID int64, channel model.QuotaChannel, amount int, reason string) error {
+	// 1. 查询或创建钱包
+	var wallet model.QuotaWallet
+	if err := tx.Where(personalWalletCond, userID, channel).
+		First(&wallet).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			// 创建新钱包
+			wallet = model.QuotaWallet{
+				UserID:          userID,
+				Channel:         channel,
+				AvailableAmount: amount,
+				FrozenAmount:    0,
+				UsedAmount:      0,
+				TotalGranted:    amount,
+			}
+			if err := tx.Create(&wallet).Error; err != nil {
+				return fmt.Errorf("failed to create wallet: %w", err)
 			}
 		} else {
-			// 更新现有钱包
-			updates := map[string]interface{}{
-				"available_amount": gorm.Expr("available_amount + ?", amount),
-				"total_granted":   gorm.Expr("total_granted + ?", amount),
-				"updated_at":      time.Now(),
+			return fmt.Errorf("failed to query wallet: %w", err)
+		}
+	} else {
+		// 更新现有钱包
+		updates := map[string]interface{}{
+			"available_amount": gorm.Expr("available_amount + ?", amount),
+			"total_granted":    gorm.Expr("total_granted + ?", amount),
+			"updated_at":       time.Now(),
+		}
+		if err := tx.Model(&wallet).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to grant quota: %w", err)
+		}
+		wallet.AvailableAmount += amount
+	}
+
+	// 2. 创建充值交易记录
+	transaction := &model.QuotaTransaction{
+		UserID:          userID,
+		WalletID:        wallet.ID,
+		Channel:         channel,
+		TransactionType: model.TransactionTypeGrant,
+		Reason:          reason,
+		Amount:          amount,
+		BalanceAfter:    wallet.AvailableAmount,
+	}
+
+	if err := tx.Create(transaction).Error; err != nil {
+		return fmt.Errorf("failed to create grant transaction: %w", err)
+	}
+
+	logger.Logger.Info("Quota granted",
+		zap.Int64("user_id", userID),
+		zap.String("channel", string(channel)),
+		zap.Int("amount", amount),
+		zap.String("reason", reason),
+		zap.Int("balance_after", wallet.AvailableAmount),
+	)
+
+	return nil
+}
+
+// LowBalanceThreshold 额度预警阈值
+// 以"完整通知一轮紧急联系人"为单位，阈值 = N 轮 * 单价 * 联系人数
+func (s *QuotaService) LowBalanceThreshold(contactCount int) int {
+	smsUnitPriceCents := 5
+	fanouts := config.Cfg.QuotaLowBalanceFanouts
+	if fanouts <= 0 || contactCount <= 0 {
+		return 0
+	}
+	return fanouts * smsUnitPriceCents * contactCount
+}
+
+// IsLowBalance 判断余额是否已低于预警阈值
+func (s *QuotaService) IsLowBalance(balance int, contactCount int) bool {
+	threshold := s.LowBalanceThreshold(contactCount)
+	return threshold > 0 && balance < threshold
+}
+
+// CheckLowBalance 扣减后检查额度是否跌破预警阈值
+// 跌破阈值时为用户本人创建一条 quota_low 通知任务，直到下次充值前只提醒一次
+// 返回创建的任务（无需提醒时返回 nil），由调用方负责投递
+func (s *QuotaService) CheckLowBalance(ctx context.Context, user *model.User) (*model.NotificationTask, error) {
+	db := database.DB().WithContext(ctx)
+
+	contactCount, err := countConfirmedContacts(query.Use(db), user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if s.LowBalanceThreshold(contactCount) == 0 {
+		return nil, nil
+	}
+
+	var createdTask *model.NotificationTask
+	err = db.Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+
+		// 锁住个人钱包，串行化并发的扣费检查，避免重复创建预警任务
+		var wallet model.QuotaWallet
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where(personalWalletCond, user.ID, model.QuotaChannelSMS).
+			First(&wallet).Error; err != nil && err != gorm.ErrRecordNotFound {
+			return fmt.Errorf("failed to lock wallet: %w", err)
+		}
+
+		// 共享钱包成员按个人 + 共享钱包的可用额度计算
+		balance, err := s.spendableBalanceTx(tx, user.ID, model.QuotaChannelSMS)
+		if err != nil {
+			return err
+		}
+
+		if !s.IsLowBalance(balance, contactCount) {
+			return nil
+		}
+
+		// 与额度耗尽提醒一致：上次预警之后有过充值（不含退款）才再次提醒
+		lastQuotaLowTask, err := txQ.NotificationTask.
+			Where(txQ.NotificationTask.UserID.Eq(user.ID)).
+			Where(txQ.NotificationTask.Category.Eq(string(model.NotificationCategoryQuotaLow))).
+			Order(txQ.NotificationTask.ScheduledAt.Desc()).
+			First()
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return fmt.Errorf("failed to query last quota low notification: %w", err)
+		}
+		if err == nil {
+			rechargeCount, err := txQ.QuotaTransaction.
+				Where(txQ.QuotaTransaction.UserID.Eq(user.ID)).
+				Where(txQ.QuotaTransaction.Channel.Eq(string(model.QuotaChannelSMS))).
+				Where(txQ.QuotaTransaction.TransactionType.Eq(string(model.TransactionTypeGrant))).
+				Where(txQ.QuotaTransaction.Reason.Neq(model.QuotaReasonGrantRefund)).
+				Where(txQ.QuotaTransaction.CreatedAt.Gt(lastQuotaLowTask.ScheduledAt)).
+				Count()
+			if err != nil {
+				return fmt.Errorf("failed to check quota recharge after last low balance notification: %w", err)
 			}
-			if err := tx.Model(&wallet).Updates(updates).Error; err != nil {
-				return fmt.Errorf("failed to grant quota: %w", err)
+			if rechargeCount == 0 {
+				return nil
 			}
-			wallet.AvailableAmount += amount
 		}
 
-		// 2. 创建充值交易记录
-		transaction := &model.QuotaTransaction{
-			UserID:          userID,
-			Channel:         channel,
-			TransactionType: model.TransactionTypeGrant,
-			Reason:          reason,
-			Amount:          amount,
-			BalanceAfter:    wallet.AvailableAmount,
+		taskCode, err := snowflake.NextID(snowflake.GeneratorTypeTask)
+		if err != nil {
+			return fmt.Errorf("failed to generate task code: %w", err)
 		}
 
-		if err := tx.Create(transaction).Error; err != nil {
-			return fmt.Errorf("failed to create grant transaction: %w", err)
+		task := &model.NotificationTask{
+			TaskCode: taskCode,
+			UserID:   user.ID,
+			Category: model.NotificationCategoryQuotaLow,
+			Channel:  model.NotificationChannelSMS,
+			Status:   model.NotificationTaskStatusPending,
+			Payload: model.JSONB{
+				"type":    "quota_low",
+				"balance": balance,
+				"fanouts": config.Cfg.QuotaLowBalanceFanouts,
+			},
+			ScheduledAt: time.Now(),
+		}
+		if err := txQ.NotificationTask.Create(task); err != nil {
+			return fmt.Errorf("failed to create quota low notification task: %w", err)
 		}
 
-		logger.Logger.Info("Quota granted",
-			zap.Int64("user_id", userID),
-			zap.String("channel", string(channel)),
-			zap.Int("amount", amount),
-			zap.String("reason", reason),
-			zap.Int("balance_after", wallet.AvailableAmount),
-		)
-
+		createdTask = task
 		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	if createdTask != nil {
+		logger.Logger.Info("Created quota low notification task",
+			zap.Int64("user_id", user.ID),
+			zap.Int64("task_code", createdTask.TaskCode),
+		)
+	}
+
+	return createdTask, nil
 }
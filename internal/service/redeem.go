@@ -0,0 +1,318 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"AreYouOK/internal/model"
+	"AreYouOK/internal/model/dto"
+	"AreYouOK/internal/repository/query"
+	pkgerrors "AreYouOK/pkg/errors"
+	"AreYouOK/pkg/logger"
+	"AreYouOK/storage/database"
+	"AreYouOK/utils"
+)
+
+var (
+	redeemService *RedeemService
+	redeemOnce    sync.Once
+)
+
+func Redeem() *RedeemService {
+	redeemOnce.Do(func() {
+		redeemService = &RedeemService{}
+	})
+	return redeemService
+}
+
+type RedeemService struct{}
+
+const (
+	// 去掉易混淆的 0/O、1/I/L
+	redeemCodeAlphabet = "23456789ABCDEFGHJKMNPQRSTUVWXYZ"
+	redeemCodeLength   = 16
+	redeemCodeGroup    = 4
+	maxRedeemBatchSize = 10000
+)
+
+// RedeemBatchSpec 生成兑换码批次的参数
+type RedeemBatchSpec struct {
+	ExpiresAt      time.Time
+	Name           string
+	Note           string
+	CreatedBy      string
+	Channel        model.QuotaChannel
+	Count          int // 生成的兑换码数量
+	Amount         int // 每次兑换发放的额度（cents）
+	MaxRedemptions int // 每个码可兑换次数，1 表示一次性
+}
+
+// GenerateBatch 生成一批兑换码（后台使用）
+// 返回的明文兑换码只在这里出现一次，数据库只保存哈希
+func (s *RedeemService) GenerateBatch(ctx context.Context, spec RedeemBatchSpec) (*model.RedeemCodeBatch, []string, error) {
+	if spec.Channel != model.QuotaChannelSMS {
+		return nil, nil, pkgerrors.QuotaChannelInvalid
+	}
+	if spec.Count <= 0 || spec.Count > maxRedeemBatchSize {
+		return nil, nil, fmt.Errorf("code count must be between 1 and %d", maxRedeemBatchSize)
+	}
+	if spec.Amount <= 0 {
+		return nil, nil, fmt.Errorf("amount must be positive")
+	}
+	if spec.MaxRedemptions <= 0 {
+		spec.MaxRedemptions = 1
+	}
+	if !spec.ExpiresAt.After(time.Now()) {
+		return nil, nil, fmt.Errorf("expires_at must be in the future")
+	}
+
+	codes := make([]string, 0, spec.Count)
+	seen := make(map[string]bool, spec.Count)
+	for len(codes) < spec.Count {
+		code, err := generateRedeemCode()
+		if err != nil {
+			return nil, nil, err
+		}
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+		codes = append(codes, code)
+	}
+
+	batch := &model.RedeemCodeBatch{
+		Name:           spec.Name,
+		Note:           spec.Note,
+		CreatedBy:      spec.CreatedBy,
+		Channel:        spec.Channel,
+		Amount:         spec.Amount,
+		MaxRedemptions: spec.MaxRedemptions,
+		CodeCount:      spec.Count,
+		ExpiresAt:      spec.ExpiresAt,
+	}
+
+	db := database.DB().WithContext(ctx)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+
+		if err := txQ.RedeemCodeBatch.Create(batch); err != nil {
+			return fmt.Errorf("failed to create redeem code batch: %w", err)
+		}
+
+		rows := make([]*model.RedeemCode, 0, len(codes))
+		for _, code := range codes {
+			rows = append(rows, &model.RedeemCode{
+				BatchID:        batch.ID,
+				CodeHash:       utils.HashRedeemCode(normalizeRedeemCode(code)),
+				Channel:        spec.Channel,
+				Amount:         spec.Amount,
+				MaxRedemptions: spec.MaxRedemptions,
+				Status:         model.RedeemCodeStatusActive,
+				ExpiresAt:      spec.ExpiresAt,
+			})
+		}
+
+		if err := txQ.RedeemCode.CreateInBatches(rows, 500); err != nil {
+			return fmt.Errorf("failed to create redeem codes: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	logger.Logger.Info("Redeem code batch generated",
+		zap.Int64("batch_id", batch.ID),
+		zap.String("name", spec.Name),
+		zap.Int("count", spec.Count),
+		zap.Int("amount", spec.Amount),
+		zap.Int("max_redemptions", spec.MaxRedemptions),
+		zap.Time("expires_at", spec.ExpiresAt),
+	)
+
+	return batch, codes, nil
+}
+
+// DisableBatch 作废整个批次中尚未用完的兑换码
+func (s *RedeemService) DisableBatch(ctx context.Context, batchID int64) error {
+	q := query.Use(database.DB().WithContext(ctx))
+
+	if _, err := q.RedeemCode.
+		Where(q.RedeemCode.BatchID.Eq(batchID)).
+		Updates(map[string]interface{}{
+			"status":     string(model.RedeemCodeStatusDisabled),
+			"updated_at": time.Now(),
+		}); err != nil {
+		return fmt.Errorf("failed to disable redeem code batch: %w", err)
+	}
+	return nil
+}
+
+// RedeemCode 用户兑换额度
+// 在同一个事务中：条件更新兑换次数（防止超兑）、写入兑换记录（唯一索引防止同一用户重复兑换）、发放额度
+func (s *RedeemService) RedeemCode(
+	ctx context.Context,
+	userID string,
+	code string,
+) (*dto.RedeemQuotaResponse, error) {
+	var userIDInt int64
+	if _, err := fmt.Sscanf(userID, "%d", &userIDInt); err != nil {
+		return nil, pkgerrors.InvalidUserID
+	}
+
+	normalized := normalizeRedeemCode(code)
+	if len(normalized) != redeemCodeLength {
+		return nil, pkgerrors.RedeemCodeInvalid
+	}
+
+	db := database.DB().WithContext(ctx)
+	q := query.Use(db)
+
+	user, err := q.User.GetByPublicID(userIDInt)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgerrors.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to query user: %w", err)
+	}
+
+	var result *dto.RedeemQuotaResponse
+	err = db.Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+		now := time.Now()
+
+		redeemCode, err := txQ.RedeemCode.
+			Where(txQ.RedeemCode.CodeHash.Eq(utils.HashRedeemCode(normalized))).
+			First()
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return pkgerrors.RedeemCodeInvalid
+			}
+			return fmt.Errorf("failed to query redeem code: %w", err)
+		}
+
+		if redeemCode.Status != model.RedeemCodeStatusActive {
+			return pkgerrors.RedeemCodeInvalid
+		}
+		if !now.Before(redeemCode.ExpiresAt) {
+			return pkgerrors.RedeemCodeExpired
+		}
+
+		redeemedBefore, err := txQ.RedeemRecord.
+			Where(txQ.RedeemRecord.CodeID.Eq(redeemCode.ID)).
+			Where(txQ.RedeemRecord.UserID.Eq(user.ID)).
+			Count()
+		if err != nil {
+			return fmt.Errorf("failed to query redeem record: %w", err)
+		}
+		if redeemedBefore > 0 {
+			return pkgerrors.RedeemCodeAlreadyUsed
+		}
+
+		// 条件更新，并发兑换时只有未超过次数的请求能成功
+		info, err := txQ.RedeemCode.
+			Where(txQ.RedeemCode.ID.Eq(redeemCode.ID)).
+			Where(txQ.RedeemCode.Status.Eq(string(model.RedeemCodeStatusActive))).
+			Where(txQ.RedeemCode.RedeemedCount.LtCol(txQ.RedeemCode.MaxRedemptions)).
+			Where(txQ.RedeemCode.ExpiresAt.Gt(now)).
+			Updates(map[string]interface{}{
+				"redeemed_count": gorm.Expr("redeemed_count + ?", 1),
+				"updated_at":     now,
+			})
+		if err != nil {
+			return fmt.Errorf("failed to update redeem code: %w", err)
+		}
+		if info.RowsAffected == 0 {
+			return pkgerrors.RedeemCodeExhausted
+		}
+
+		// 唯一索引 (code_id, user_id) 兜底并发下的重复兑换
+		record := &model.RedeemRecord{
+			CodeID:     redeemCode.ID,
+			BatchID:    redeemCode.BatchID,
+			UserID:     user.ID,
+			Channel:    redeemCode.Channel,
+			Amount:     redeemCode.Amount,
+			RedeemedAt: now,
+		}
+		if err := txQ.RedeemRecord.Create(record); err != nil {
+			if isUniqueViolation(err, "redeem_records_code_user_key") {
+				return pkgerrors.RedeemCodeAlreadyUsed
+			}
+			return fmt.Errorf("failed to create redeem record: %w", err)
+		}
+
+		if err := Quota().grantQuotaTx(tx, user.ID, redeemCode.Channel, redeemCode.Amount, model.QuotaReasonGrantRedeem); err != nil {
+			return err
+		}
+
+		wallet, err := txQ.QuotaWallet.
+			Where(txQ.QuotaWallet.UserID.Eq(user.ID)).
+			Where(txQ.QuotaWallet.Channel.Eq(string(redeemCode.Channel))).
+			Where(txQ.QuotaWallet.GroupID.IsNull()).
+			First()
+		if err != nil {
+			return fmt.Errorf("failed to query wallet: %w", err)
+		}
+
+		result = &dto.RedeemQuotaResponse{
+			Channel:    string(redeemCode.Channel),
+			Amount:     redeemCode.Amount,
+			SMSBalance: wallet.AvailableAmount,
+		}
+
+		logger.Logger.Info("Redeem code redeemed",
+			zap.Int64("user_id", user.ID),
+			zap.Int64("code_id", redeemCode.ID),
+			zap.Int64("batch_id", redeemCode.BatchID),
+			zap.Int("amount", redeemCode.Amount),
+		)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// normalizeRedeemCode 统一大小写并去掉分隔符，用户输入 abcd-efgh 与 ABCDEFGH 等价
+func normalizeRedeemCode(code string) string {
+	code = strings.ToUpper(code)
+	code = strings.ReplaceAll(code, "-", "")
+	code = strings.ReplaceAll(code, " ", "")
+	return code
+}
+
+// generateRedeemCode 生成形如 XXXX-XXXX-XXXX-XXXX 的兑换码
+func generateRedeemCode() (string, error) {
+	var sb strings.Builder
+	max := big.NewInt(int64(len(redeemCodeAlphabet)))
+	for i := 0; i < redeemCodeLength; i++ {
+		if i > 0 && i%redeemCodeGroup == 0 {
+			sb.WriteByte('-')
+		}
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate redeem code: %w", err)
+		}
+		sb.WriteByte(redeemCodeAlphabet[n.Int64()])
+	}
+	return sb.String(), nil
+}
+
+// isUniqueViolation 是否为指定唯一索引的冲突（PostgreSQL 23505）
+func isUniqueViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == constraint
+}
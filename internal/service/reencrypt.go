@@ -0,0 +1,293 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"AreYouOK/config"
+	"AreYouOK/internal/cache"
+	"AreYouOK/internal/model"
+	"AreYouOK/internal/repository/query"
+	"AreYouOK/pkg/logger"
+	"AreYouOK/storage/database"
+	"AreYouOK/utils"
+)
+
+var (
+	reencryptService *ReencryptService
+	reencryptOnce    sync.Once
+)
+
+func Reencrypt() *ReencryptService {
+	reencryptOnce.Do(func() {
+		reencryptService = &ReencryptService{}
+	})
+	return reencryptService
+}
+
+// ReencryptService 密钥轮换后将手机号密文重加密为当前密钥
+// 按 users.id 分批遍历 users.phone_cipher 与 emergency_contacts 表中的联系人密文（含已删除的历史记录），进度保存在 Redis 中，中断后可继续
+// 遍历完用户后再重加密免打扰名单（contact_opt_outs）中的密文
+type ReencryptService struct{}
+
+// ReencryptOptions 重加密任务参数
+type ReencryptOptions struct {
+	BatchSize int           // 每批处理的用户数
+	Pause     time.Duration // 批次之间的间隔，降低数据库压力
+	Restart   bool          // 忽略已保存的进度，从头开始
+	DryRun    bool          // 只统计需要重加密的数量，不写入
+}
+
+// ReencryptResult 重加密任务统计
+type ReencryptResult struct {
+	Scanned   int   // 扫描的用户数
+	Updated   int   // 写回的用户数
+	Ciphers   int   // 重加密的密文数量（本人手机号 + 联系人）
+	Conflicts int   // 读取后被并发修改而跳过的用户或联系人数，需重新执行一次
+	Failed    int   // 无法解密的用户或联系人数（密钥环中缺少对应密钥）
+	LastID    int64 // 已处理到的 users.id
+}
+
+// Run 从上次进度开始执行重加密，直到遍历完所有用户或 ctx 取消
+func (s *ReencryptService) Run(ctx context.Context, opts ReencryptOptions) (*ReencryptResult, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 200
+	}
+
+	keyID := config.Cfg.EncryptionKeyID
+	total := &ReencryptResult{}
+
+	if !opts.Restart {
+		cursor, err := cache.GetReencryptCursor(ctx, keyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get reencrypt cursor: %w", err)
+		}
+		total.LastID = cursor
+	}
+
+	logger.Logger.Info("Reencrypt job started",
+		zap.String("key_id", keyID),
+		zap.Int64("after_user_id", total.LastID),
+		zap.Int("batch_size", opts.BatchSize),
+		zap.Bool("dry_run", opts.DryRun),
+	)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		batch, err := s.runBatch(ctx, total.LastID, opts.BatchSize, opts.DryRun)
+		if err != nil {
+			return total, err
+		}
+
+		total.Scanned += batch.Scanned
+		total.Updated += batch.Updated
+		total.Ciphers += batch.Ciphers
+		total.Conflicts += batch.Conflicts
+		total.Failed += batch.Failed
+
+		if batch.Scanned == 0 {
+			break
+		}
+		total.LastID = batch.LastID
+
+		if !opts.DryRun {
+			if err := cache.SetReencryptCursor(ctx, keyID, total.LastID); err != nil {
+				return total, fmt.Errorf("failed to save reencrypt cursor: %w", err)
+			}
+		}
+
+		logger.Logger.Info("Reencrypt batch finished",
+			zap.Int64("last_user_id", batch.LastID),
+			zap.Int("scanned", batch.Scanned),
+			zap.Int("updated", batch.Updated),
+			zap.Int("conflicts", batch.Conflicts),
+			zap.Int("failed", batch.Failed),
+		)
+
+		if opts.Pause > 0 {
+			select {
+			case <-ctx.Done():
+				return total, ctx.Err()
+			case <-time.After(opts.Pause):
+			}
+		}
+	}
+
+	if err := s.reencryptOptOuts(ctx, opts.DryRun, total); err != nil {
+		return total, err
+	}
+
+	return total, nil
+}
+
+// reencryptOptOuts 重加密免打扰名单中的号码密文，名单不大，每次执行都完整遍历
+func (s *ReencryptService) reencryptOptOuts(ctx context.Context, dryRun bool, result *ReencryptResult) error {
+	q := query.Use(database.DB().WithContext(ctx))
+
+	records, err := q.ContactOptOut.Find()
+	if err != nil {
+		return fmt.Errorf("failed to query opt-out registry: %w", err)
+	}
+
+	for _, record := range records {
+		newCipher, changed, err := utils.ReencryptPhone(record.PhoneCipher)
+		if err != nil {
+			result.Failed++
+			logger.Logger.Error("Failed to reencrypt opt-out ciphertext",
+				zap.Int64("opt_out_id", record.ID),
+				zap.Error(err),
+			)
+			continue
+		}
+		if !changed {
+			continue
+		}
+
+		result.Ciphers++
+		if dryRun {
+			continue
+		}
+
+		if _, err := q.ContactOptOut.
+			Where(q.ContactOptOut.ID.Eq(record.ID)).
+			UpdateColumns(map[string]interface{}{"phone_cipher": newCipher}); err != nil {
+			return fmt.Errorf("failed to update opt-out record %d: %w", record.ID, err)
+		}
+	}
+	return nil
+}
+
+// runBatch 处理 id > afterID 的一批用户（包括恢复窗口内的注销用户）
+func (s *ReencryptService) runBatch(ctx context.Context, afterID int64, limit int, dryRun bool) (*ReencryptResult, error) {
+	q := query.Use(database.DB().WithContext(ctx))
+
+	users, err := q.User.Unscoped().
+		Where(q.User.ID.Gt(afterID)).
+		Order(q.User.ID).
+		Limit(limit).
+		Find()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query users: %w", err)
+	}
+
+	result := &ReencryptResult{}
+	userIDs := make([]int64, 0, len(users))
+	for _, user := range users {
+		result.Scanned++
+		result.LastID = user.ID
+		userIDs = append(userIDs, user.ID)
+
+		updates, ciphers, err := reencryptUser(user)
+		if err != nil {
+			result.Failed++
+			logger.Logger.Error("Failed to reencrypt user ciphertexts",
+				zap.Int64("user_id", user.ID),
+				zap.Error(err),
+			)
+			continue
+		}
+		if len(updates) == 0 {
+			continue
+		}
+
+		result.Ciphers += ciphers
+		if dryRun {
+			result.Updated++
+			continue
+		}
+
+		// 以 updated_at 作为乐观锁，读取后用户修改过手机号则跳过，避免覆盖新数据；
+		// UpdateColumns 不刷新 updated_at，重加密对用户不可见
+		info, err := q.User.Unscoped().
+			Where(q.User.ID.Eq(user.ID)).
+			Where(q.User.UpdatedAt.Eq(user.UpdatedAt)).
+			UpdateColumns(updates)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update user %d: %w", user.ID, err)
+		}
+		if info.RowsAffected == 0 {
+			result.Conflicts++
+			continue
+		}
+		result.Updated++
+	}
+
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+	if err := s.reencryptContacts(q, userIDs, dryRun, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// reencryptContacts 重加密这批用户的联系人密文，已删除的历史记录一并处理
+// 以 version 作为乐观锁，重加密不增加版本号，对用户不可见
+func (s *ReencryptService) reencryptContacts(q *query.Query, userIDs []int64, dryRun bool, result *ReencryptResult) error {
+	contacts, err := q.Contact.Unscoped().
+		Where(q.Contact.UserID.In(userIDs...)).
+		Find()
+	if err != nil {
+		return fmt.Errorf("failed to query contacts: %w", err)
+	}
+
+	for _, contact := range contacts {
+		newCipher, changed, err := utils.ReencryptPhone(contact.PhoneCipher)
+		if err != nil {
+			result.Failed++
+			logger.Logger.Error("Failed to reencrypt contact ciphertext",
+				zap.Int64("user_id", contact.UserID),
+				zap.Int64("contact_id", contact.ContactID),
+				zap.Error(err),
+			)
+			continue
+		}
+		if !changed {
+			continue
+		}
+
+		result.Ciphers++
+		if dryRun {
+			continue
+		}
+
+		info, err := q.Contact.Unscoped().
+			Where(q.Contact.ID.Eq(contact.ID)).
+			Where(q.Contact.Version.Eq(contact.Version)).
+			UpdateColumns(map[string]interface{}{"phone_cipher": newCipher})
+		if err != nil {
+			return fmt.Errorf("failed to update contact %d: %w", contact.ContactID, err)
+		}
+		if info.RowsAffected == 0 {
+			result.Conflicts++
+		}
+	}
+	return nil
+}
+
+// reencryptUser 计算需要写回的字段，返回重加密的密文数量
+func reencryptUser(user *model.User) (map[string]interface{}, int, error) {
+	updates := map[string]interface{}{}
+	ciphers := 0
+
+	if len(user.PhoneCipher) > 0 {
+		newCipher, changed, err := utils.ReencryptPhone(user.PhoneCipher)
+		if err != nil {
+			return nil, 0, fmt.Errorf("phone_cipher: %w", err)
+		}
+		if changed {
+			updates["phone_cipher"] = newCipher
+			ciphers++
+		}
+	}
+
+	return updates, ciphers, nil
+}
@@ -0,0 +1,383 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"AreYouOK/internal/cache"
+	"AreYouOK/internal/model"
+	"AreYouOK/internal/repository/query"
+	"AreYouOK/pkg/logger"
+	"AreYouOK/storage/database"
+	"AreYouOK/utils"
+)
+
+var (
+	rehashService *RehashService
+	rehashOnce    sync.Once
+)
+
+func Rehash() *RehashService {
+	rehashOnce.Do(func() {
+		rehashService = &RehashService{}
+	})
+	return rehashService
+}
+
+// RehashService 手机号哈希版本轮换后按当前版本重算哈希
+// 按 users.id 分批遍历，解密 users.phone_cipher 与 emergency_contacts 表中的联系人密文（含已删除的历史记录）重算哈希，
+// 同时替换通知任务、通知尝试与换号记录中引用的旧哈希；进度保存在 Redis 中，中断后可继续
+// 遍历完用户后再重算免打扰名单（contact_opt_outs）的哈希
+type RehashService struct{}
+
+// RehashOptions 重新哈希任务参数
+type RehashOptions struct {
+	BatchSize int           // 每批处理的用户数
+	Pause     time.Duration // 批次之间的间隔，降低数据库压力
+	Restart   bool          // 忽略已保存的进度，从头开始
+	DryRun    bool          // 只统计需要重算的数量，不写入
+}
+
+// RehashResult 重新哈希任务统计
+type RehashResult struct {
+	Scanned   int   // 扫描的用户数
+	Updated   int   // 写回的用户数
+	Hashes    int   // 重算的哈希数量（本人手机号 + 联系人）
+	Conflicts int   // 读取后用户或其联系人被并发修改而跳过的用户数，需重新执行一次
+	Failed    int   // 无法解密的用户数
+	LastID    int64 // 已处理到的 users.id
+}
+
+// Run 从上次进度开始执行重新哈希，直到遍历完所有用户或 ctx 取消
+func (s *RehashService) Run(ctx context.Context, opts RehashOptions) (*RehashResult, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 200
+	}
+
+	version := utils.PhoneHashVersion()
+	total := &RehashResult{}
+
+	if !opts.Restart {
+		cursor, err := cache.GetRehashCursor(ctx, version)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rehash cursor: %w", err)
+		}
+		total.LastID = cursor
+	}
+
+	logger.Logger.Info("Rehash job started",
+		zap.Int("version", version),
+		zap.Int64("after_user_id", total.LastID),
+		zap.Int("batch_size", opts.BatchSize),
+		zap.Bool("dry_run", opts.DryRun),
+	)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		batch, err := s.runBatch(ctx, total.LastID, opts.BatchSize, opts.DryRun)
+		if err != nil {
+			return total, err
+		}
+
+		total.Scanned += batch.Scanned
+		total.Updated += batch.Updated
+		total.Hashes += batch.Hashes
+		total.Conflicts += batch.Conflicts
+		total.Failed += batch.Failed
+
+		if batch.Scanned == 0 {
+			break
+		}
+		total.LastID = batch.LastID
+
+		if !opts.DryRun {
+			if err := cache.SetRehashCursor(ctx, version, total.LastID); err != nil {
+				return total, fmt.Errorf("failed to save rehash cursor: %w", err)
+			}
+		}
+
+		logger.Logger.Info("Rehash batch finished",
+			zap.Int64("last_user_id", batch.LastID),
+			zap.Int("scanned", batch.Scanned),
+			zap.Int("updated", batch.Updated),
+			zap.Int("conflicts", batch.Conflicts),
+			zap.Int("failed", batch.Failed),
+		)
+
+		if opts.Pause > 0 {
+			select {
+			case <-ctx.Done():
+				return total, ctx.Err()
+			case <-time.After(opts.Pause):
+			}
+		}
+	}
+
+	if err := s.rehashOptOuts(ctx, opts.DryRun, total); err != nil {
+		return total, err
+	}
+
+	return total, nil
+}
+
+// rehashOptOuts 重算免打扰名单中的号码哈希，名单不大，每次执行都完整遍历
+func (s *RehashService) rehashOptOuts(ctx context.Context, dryRun bool, result *RehashResult) error {
+	q := query.Use(database.DB().WithContext(ctx))
+	version := utils.PhoneHashVersion()
+
+	records, err := q.ContactOptOut.
+		Where(q.ContactOptOut.PhoneHashVersion.Neq(version)).
+		Find()
+	if err != nil {
+		return fmt.Errorf("failed to query opt-out registry: %w", err)
+	}
+
+	for _, record := range records {
+		phone, err := utils.DecryptPhone(record.PhoneCipher)
+		if err != nil {
+			result.Failed++
+			logger.Logger.Error("Failed to rehash opt-out phone",
+				zap.Int64("opt_out_id", record.ID),
+				zap.Error(err),
+			)
+			continue
+		}
+
+		result.Hashes++
+		if dryRun {
+			continue
+		}
+
+		if _, err := q.ContactOptOut.
+			Where(q.ContactOptOut.ID.Eq(record.ID)).
+			UpdateColumns(map[string]interface{}{
+				"phone_hash":         utils.HashPhone(phone),
+				"phone_hash_version": version,
+			}); err != nil {
+			return fmt.Errorf("failed to update opt-out record %d: %w", record.ID, err)
+		}
+	}
+	return nil
+}
+
+// runBatch 处理 id > afterID 的一批用户（包括恢复窗口内的注销用户）
+func (s *RehashService) runBatch(ctx context.Context, afterID int64, limit int, dryRun bool) (*RehashResult, error) {
+	db := database.DB().WithContext(ctx)
+	q := query.Use(db)
+
+	users, err := q.User.Unscoped().
+		Where(q.User.ID.Gt(afterID)).
+		Order(q.User.ID).
+		Limit(limit).
+		Find()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query users: %w", err)
+	}
+
+	userIDs := make([]int64, 0, len(users))
+	for _, user := range users {
+		userIDs = append(userIDs, user.ID)
+	}
+	contactMap := make(map[int64][]*model.Contact, len(users))
+	if len(userIDs) > 0 {
+		contacts, err := q.Contact.Unscoped().
+			Where(q.Contact.UserID.In(userIDs...)).
+			Find()
+		if err != nil {
+			return nil, fmt.Errorf("failed to query contacts: %w", err)
+		}
+		for _, contact := range contacts {
+			contactMap[contact.UserID] = append(contactMap[contact.UserID], contact)
+		}
+	}
+
+	result := &RehashResult{}
+	for _, user := range users {
+		result.Scanned++
+		result.LastID = user.ID
+
+		updates, contactUpdates, replaced, err := rehashUser(user, contactMap[user.ID])
+		if err != nil {
+			result.Failed++
+			logger.Logger.Error("Failed to rehash user phone hashes",
+				zap.Int64("user_id", user.ID),
+				zap.Error(err),
+			)
+			continue
+		}
+		if len(updates) == 0 && len(contactUpdates) == 0 {
+			continue
+		}
+
+		result.Hashes += len(replaced)
+		if dryRun {
+			result.Updated++
+			continue
+		}
+
+		conflict := false
+		err = db.Transaction(func(tx *gorm.DB) error {
+			txQ := query.Use(tx)
+
+			// 以 updated_at 作为乐观锁，读取后用户修改过手机号则跳过；
+			// UpdateColumns 不刷新 updated_at，重算对用户不可见
+			if len(updates) > 0 {
+				info, err := txQ.User.Unscoped().
+					Where(txQ.User.ID.Eq(user.ID)).
+					Where(txQ.User.UpdatedAt.Eq(user.UpdatedAt)).
+					UpdateColumns(updates)
+				if err != nil {
+					return fmt.Errorf("failed to update user: %w", err)
+				}
+				if info.RowsAffected == 0 {
+					return errRehashConflict
+				}
+			}
+
+			// 联系人以 version 作为乐观锁，重算不增加版本号
+			for _, contact := range contactMap[user.ID] {
+				contactUpdate, ok := contactUpdates[contact.ID]
+				if !ok {
+					continue
+				}
+				info, err := txQ.Contact.Unscoped().
+					Where(txQ.Contact.ID.Eq(contact.ID)).
+					Where(txQ.Contact.Version.Eq(contact.Version)).
+					UpdateColumns(contactUpdate)
+				if err != nil {
+					return fmt.Errorf("failed to update contact %d: %w", contact.ContactID, err)
+				}
+				if info.RowsAffected == 0 {
+					return errRehashConflict
+				}
+			}
+
+			return replacePhoneHashRefs(txQ, user.ID, replaced)
+		})
+		if errors.Is(err, errRehashConflict) {
+			conflict = true
+			err = nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to rehash user %d: %w", user.ID, err)
+		}
+		if conflict {
+			result.Conflicts++
+			continue
+		}
+		result.Updated++
+	}
+
+	return result, nil
+}
+
+// errRehashConflict 用户或联系人在读取后被修改，回滚该用户的全部重算
+var errRehashConflict = errors.New("rehash conflict")
+
+// rehashUser 计算用户与联系人需要写回的字段（联系人按 emergency_contacts.id 索引），返回旧哈希到新哈希的映射
+func rehashUser(user *model.User, contacts []*model.Contact) (map[string]interface{}, map[int64]map[string]interface{}, map[string]string, error) {
+	version := utils.PhoneHashVersion()
+	updates := map[string]interface{}{}
+	contactUpdates := map[int64]map[string]interface{}{}
+	replaced := map[string]string{}
+
+	if user.PhoneHash != nil && *user.PhoneHash != "" && len(user.PhoneCipher) > 0 {
+		phone, err := utils.DecryptPhone(user.PhoneCipher)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("phone_cipher: %w", err)
+		}
+
+		newHash := utils.HashPhone(phone)
+		if newHash != *user.PhoneHash || user.PhoneHashVersion != version {
+			updates["phone_hash"] = newHash
+			updates["phone_hash_version"] = version
+			if newHash != *user.PhoneHash {
+				replaced[*user.PhoneHash] = newHash
+			}
+		}
+	}
+
+	for _, contact := range contacts {
+		if len(contact.PhoneCipher) == 0 {
+			continue
+		}
+
+		phone, err := decryptContactPhone(contact)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("contact %d: %w", contact.ContactID, err)
+		}
+
+		newHash := utils.HashPhone(phone)
+		if newHash != contact.PhoneHash || contact.PhoneHashVersion != version {
+			contactUpdates[contact.ID] = map[string]interface{}{
+				"phone_hash":         newHash,
+				"phone_hash_version": version,
+			}
+			if newHash != contact.PhoneHash && contact.PhoneHash != "" {
+				replaced[contact.PhoneHash] = newHash
+			}
+		}
+	}
+
+	return updates, contactUpdates, replaced, nil
+}
+
+// replacePhoneHashRefs 替换该用户名下通知任务、通知尝试与换号记录中引用的旧哈希
+// 换号记录中已解绑的旧号码没有密文，无法重算，保留原值
+func replacePhoneHashRefs(txQ *query.Query, userID int64, replaced map[string]string) error {
+	for oldHash, newHash := range replaced {
+		userTasks := txQ.NotificationTask.
+			Select(txQ.NotificationTask.ID).
+			Where(txQ.NotificationTask.UserID.Eq(userID))
+		if _, err := txQ.ContactAttempt.
+			Where(txQ.ContactAttempt.Columns(txQ.ContactAttempt.TaskID).In(userTasks)).
+			Where(txQ.ContactAttempt.ContactPhoneHash.Eq(oldHash)).
+			UpdateColumn(txQ.ContactAttempt.ContactPhoneHash, newHash); err != nil {
+			return fmt.Errorf("failed to update contact attempts: %w", err)
+		}
+
+		if _, err := txQ.NotificationTask.
+			Where(txQ.NotificationTask.UserID.Eq(userID)).
+			Where(txQ.NotificationTask.ContactPhoneHash.Eq(oldHash)).
+			UpdateColumn(txQ.NotificationTask.ContactPhoneHash, newHash); err != nil {
+			return fmt.Errorf("failed to update notification tasks: %w", err)
+		}
+
+		if _, err := txQ.PhoneChangeLog.
+			Where(txQ.PhoneChangeLog.UserID.Eq(userID)).
+			Where(txQ.PhoneChangeLog.NewPhoneHash.Eq(oldHash)).
+			UpdateColumn(txQ.PhoneChangeLog.NewPhoneHash, newHash); err != nil {
+			return fmt.Errorf("failed to update phone change logs: %w", err)
+		}
+		if _, err := txQ.PhoneChangeLog.
+			Where(txQ.PhoneChangeLog.UserID.Eq(userID)).
+			Where(txQ.PhoneChangeLog.OldPhoneHash.Eq(oldHash)).
+			UpdateColumn(txQ.PhoneChangeLog.OldPhoneHash, newHash); err != nil {
+			return fmt.Errorf("failed to update phone change logs: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// userPhoneHashes 用户手机号的全部候选哈希：已存储的哈希，以及由密文算出的当前/上一版本哈希
+func userPhoneHashes(user *model.User) []string {
+	hashes := []string{derefString(user.PhoneHash)}
+
+	if len(user.PhoneCipher) > 0 {
+		if phone, err := utils.DecryptPhone(user.PhoneCipher); err == nil {
+			hashes = append(hashes, utils.PhoneHashCandidates(phone)...)
+		}
+	}
+
+	return hashes
+}
@@ -0,0 +1,441 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"AreYouOK/config"
+	"AreYouOK/internal/cache"
+	"AreYouOK/internal/model"
+	"AreYouOK/internal/model/dto"
+	"AreYouOK/internal/repository/query"
+	pkgerrors "AreYouOK/pkg/errors"
+	"AreYouOK/pkg/logger"
+	"AreYouOK/pkg/token"
+	"AreYouOK/storage/database"
+)
+
+var (
+	sessionService *SessionService
+	sessionOnce    sync.Once
+)
+
+func Session() *SessionService {
+	sessionOnce.Do(func() {
+		sessionService = &SessionService{}
+	})
+	return sessionService
+}
+
+// SessionService 登录会话：每台设备一个会话，refresh token 每次使用都轮换
+type SessionService struct{}
+
+// CreateSession 登录成功后创建会话并签发 token
+func (s *SessionService) CreateSession(
+	ctx context.Context,
+	user *model.User,
+	device dto.DeviceInfo,
+	ip string,
+) (*token.TokenPair, error) {
+	db := database.DB().WithContext(ctx)
+
+	now := time.Now()
+	session := &model.UserSession{
+		UserID:      user.ID,
+		Platform:    device.Platform,
+		DeviceModel: device.Model,
+		AppVersion:  device.AppVersion,
+		IP:          ip,
+		LastSeenAt:  now,
+		ExpiresAt:   now.Add(refreshTTL()),
+	}
+
+	var pair *token.TokenPair
+	err := db.Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+
+		// 会话 ID 需写入 token，先建会话再回填 refresh token jti
+		if err := txQ.UserSession.Create(session); err != nil {
+			return fmt.Errorf("failed to create session: %w", err)
+		}
+
+		userIDStr := strconv.FormatInt(user.PublicID, 10)
+		sessionIDStr := strconv.FormatInt(session.ID, 10)
+
+		var err error
+		pair, err = token.GenerateTokenPair(userIDStr, sessionIDStr)
+		if err != nil {
+			return fmt.Errorf("failed to generate token: %w", err)
+		}
+
+		if _, err := txQ.UserSession.
+			Where(txQ.UserSession.ID.Eq(session.ID)).
+			Update(txQ.UserSession.CurrentRefreshJTI, pair.RefreshJTI); err != nil {
+			return fmt.Errorf("failed to store session refresh token: %w", err)
+		}
+
+		return recordAudit(ctx, txQ, auditEntry{
+			UserID:     user.ID,
+			SessionID:  session.ID,
+			Action:     model.AuditActionLogin,
+			TargetType: "session",
+			TargetID:   sessionIDStr,
+			IP:         ip,
+			Device:     auditDevice(device),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Logger.Info("Session created",
+		zap.Int64("user_id", user.ID),
+		zap.Int64("session_id", session.ID),
+		zap.String("platform", device.Platform),
+		zap.String("ip", ip),
+	)
+
+	return pair, nil
+}
+
+// Rotate 使用 refresh token 换取新的 token，并使旧 refresh token 失效
+// 旧 refresh token 再次使用时视为泄露，撤销整个会话
+func (s *SessionService) Rotate(
+	ctx context.Context,
+	refreshToken string,
+	ip string,
+) (*model.User, *token.TokenPair, error) {
+	claims, err := token.ValidateRefreshToken(refreshToken)
+	if err != nil {
+		return nil, nil, pkgerrors.AuthCodeInvalid
+	}
+
+	var publicID int64
+	if _, err := fmt.Sscanf(claims.UserID, "%d", &publicID); err != nil {
+		return nil, nil, pkgerrors.AuthCodeInvalid
+	}
+
+	db := database.DB().WithContext(ctx)
+	q := query.Use(db)
+
+	user, err := q.User.GetByPublicID(publicID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, pkgerrors.ErrUserNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to query user: %w", err)
+	}
+
+	// 旧版 refresh token 没有 sid：与 Redis 中保存的单设备 token 比对后迁移为新会话
+	if claims.SessionID == "" {
+		if !cache.ValidateRefreshTokenExists(ctx, claims.UserID, refreshToken) {
+			return nil, nil, pkgerrors.AuthCodeInvalid
+		}
+		if err := cache.DeleteRefreshToken(ctx, claims.UserID); err != nil {
+			logger.Logger.Warn("Failed to delete legacy refresh token",
+				zap.String("user_id", claims.UserID),
+				zap.Error(err),
+			)
+		}
+		pair, err := s.CreateSession(ctx, user, dto.DeviceInfo{}, ip)
+		if err != nil {
+			return nil, nil, err
+		}
+		return user, pair, nil
+	}
+
+	var sessionID int64
+	if _, err := fmt.Sscanf(claims.SessionID, "%d", &sessionID); err != nil {
+		return nil, nil, pkgerrors.AuthCodeInvalid
+	}
+
+	session, err := q.UserSession.
+		Where(q.UserSession.ID.Eq(sessionID)).
+		Where(q.UserSession.UserID.Eq(user.ID)).
+		First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, pkgerrors.SessionRevoked
+		}
+		return nil, nil, fmt.Errorf("failed to query session: %w", err)
+	}
+
+	now := time.Now()
+	if session.RevokedAt != nil || !now.Before(session.ExpiresAt) {
+		return nil, nil, pkgerrors.SessionRevoked
+	}
+
+	if claims.JTI == "" || claims.JTI != session.CurrentRefreshJTI {
+		s.revokeForReuse(ctx, session)
+		return nil, nil, pkgerrors.RefreshTokenReused
+	}
+
+	pair, err := token.GenerateTokenPair(claims.UserID, claims.SessionID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+
+		// 以旧 jti 作为条件更新，并发刷新时只有一个请求能轮换成功
+		info, err := txQ.UserSession.
+			Where(txQ.UserSession.ID.Eq(session.ID)).
+			Where(txQ.UserSession.CurrentRefreshJTI.Eq(claims.JTI)).
+			Where(txQ.UserSession.RevokedAt.IsNull()).
+			Updates(map[string]interface{}{
+				"current_refresh_jti": pair.RefreshJTI,
+				"refresh_count":       gorm.Expr("refresh_count + ?", 1),
+				"last_seen_at":        now,
+				"expires_at":          now.Add(refreshTTL()),
+				"ip":                  ip,
+				"updated_at":          now,
+			})
+		if err != nil {
+			return fmt.Errorf("failed to rotate refresh token: %w", err)
+		}
+		if info.RowsAffected == 0 {
+			return pkgerrors.RefreshTokenReused
+		}
+
+		return recordAudit(ctx, txQ, auditEntry{
+			UserID:     user.ID,
+			SessionID:  session.ID,
+			Action:     model.AuditActionTokenRefresh,
+			TargetType: "session",
+			TargetID:   claims.SessionID,
+			IP:         ip,
+		})
+	})
+	if errors.Is(err, pkgerrors.RefreshTokenReused) {
+		s.revokeForReuse(ctx, session)
+		return nil, nil, err
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, pair, nil
+}
+
+// ListSessions 列出用户当前有效的登录会话
+func (s *SessionService) ListSessions(
+	ctx context.Context,
+	userID string,
+	currentSessionID string,
+) ([]dto.SessionItem, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	q := query.Use(database.DB().WithContext(ctx))
+	sessions, err := q.UserSession.
+		Where(q.UserSession.UserID.Eq(user.ID)).
+		Where(q.UserSession.RevokedAt.IsNull()).
+		Where(q.UserSession.ExpiresAt.Gt(time.Now())).
+		Order(q.UserSession.LastSeenAt.Desc()).
+		Find()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sessions: %w", err)
+	}
+
+	items := make([]dto.SessionItem, 0, len(sessions))
+	for _, session := range sessions {
+		id := strconv.FormatInt(session.ID, 10)
+		items = append(items, dto.SessionItem{
+			ID:         id,
+			Platform:   session.Platform,
+			Model:      session.DeviceModel,
+			AppVersion: session.AppVersion,
+			IP:         session.IP,
+			LastSeenAt: session.LastSeenAt,
+			CreatedAt:  session.CreatedAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    id == currentSessionID,
+		})
+	}
+
+	return items, nil
+}
+
+// RevokeSession 撤销用户的某个会话（例如在新手机上移除旧手机的登录）
+func (s *SessionService) RevokeSession(ctx context.Context, userID string, sessionID string) error {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	var sessionIDInt int64
+	if _, err := fmt.Sscanf(sessionID, "%d", &sessionIDInt); err != nil {
+		return pkgerrors.SessionNotFound
+	}
+
+	return s.revoke(ctx, user.ID, sessionIDInt, model.SessionRevokeReasonUserRevoked)
+}
+
+// Logout 登出当前设备：拉黑当前 access token 直到过期，并撤销所属会话
+func (s *SessionService) Logout(
+	ctx context.Context,
+	userID string,
+	sessionID string,
+	jti string,
+	expiresAt time.Time,
+) error {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if jti != "" {
+		if err := cache.RevokeAccessToken(ctx, jti, time.Until(expiresAt)); err != nil {
+			return fmt.Errorf("failed to revoke access token: %w", err)
+		}
+	}
+
+	if sessionID == "" {
+		// 旧版 token 没有会话，清理单设备 refresh token
+		return cache.DeleteRefreshToken(ctx, userID)
+	}
+
+	var sessionIDInt int64
+	if _, err := fmt.Sscanf(sessionID, "%d", &sessionIDInt); err != nil {
+		return nil
+	}
+
+	err = s.revoke(ctx, user.ID, sessionIDInt, model.SessionRevokeReasonLogout)
+	if errors.Is(err, pkgerrors.SessionNotFound) {
+		return nil
+	}
+	return err
+}
+
+// revoke 撤销会话，并让该会话已签发的 access token 立即失效
+func (s *SessionService) revoke(ctx context.Context, userID int64, sessionID int64, reason string) error {
+	db := database.DB().WithContext(ctx)
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+
+		now := time.Now()
+		info, err := txQ.UserSession.
+			Where(txQ.UserSession.ID.Eq(sessionID)).
+			Where(txQ.UserSession.UserID.Eq(userID)).
+			Where(txQ.UserSession.RevokedAt.IsNull()).
+			Updates(map[string]interface{}{
+				"revoked_at":     now,
+				"revoked_reason": reason,
+				"updated_at":     now,
+			})
+		if err != nil {
+			return fmt.Errorf("failed to revoke session: %w", err)
+		}
+		if info.RowsAffected == 0 {
+			return pkgerrors.SessionNotFound
+		}
+
+		action := model.AuditActionSessionRevoke
+		if reason == model.SessionRevokeReasonLogout {
+			action = model.AuditActionLogout
+		}
+		return recordAudit(ctx, txQ, auditEntry{
+			UserID:     userID,
+			SessionID:  sessionID,
+			Action:     action,
+			TargetType: "session",
+			TargetID:   strconv.FormatInt(sessionID, 10),
+			Diff:       model.JSONB{"reason": reason},
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := cache.RevokeSession(ctx, strconv.FormatInt(sessionID, 10)); err != nil {
+		logger.Logger.Warn("Failed to mark session revoked in Redis",
+			zap.Int64("session_id", sessionID),
+			zap.Error(err),
+		)
+	}
+
+	logger.Logger.Info("Session revoked",
+		zap.Int64("user_id", userID),
+		zap.Int64("session_id", sessionID),
+		zap.String("reason", reason),
+	)
+	return nil
+}
+
+// RevokeAll 撤销用户的全部会话（用户注销账号时调用）
+func (s *SessionService) RevokeAll(ctx context.Context, userID int64, reason string) error {
+	return s.RevokeOthers(ctx, userID, 0, reason)
+}
+
+// RevokeOthers 撤销除 keepSessionID 以外的全部会话（例如更换手机号后让其他设备重新登录）
+// keepSessionID 为 0 时撤销全部会话
+func (s *SessionService) RevokeOthers(ctx context.Context, userID int64, keepSessionID int64, reason string) error {
+	q := query.Use(database.DB().WithContext(ctx))
+
+	sessions, err := q.UserSession.
+		Where(q.UserSession.UserID.Eq(userID)).
+		Where(q.UserSession.RevokedAt.IsNull()).
+		Find()
+	if err != nil {
+		return fmt.Errorf("failed to query sessions: %w", err)
+	}
+
+	for _, session := range sessions {
+		if session.ID == keepSessionID {
+			continue
+		}
+		if err := s.revoke(ctx, userID, session.ID, reason); err != nil &&
+			!errors.Is(err, pkgerrors.SessionNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// revokeForReuse 检测到 refresh token 重放时撤销整个会话
+func (s *SessionService) revokeForReuse(ctx context.Context, session *model.UserSession) {
+	logger.Logger.Warn("Refresh token reuse detected, revoking session",
+		zap.Int64("user_id", session.UserID),
+		zap.Int64("session_id", session.ID),
+	)
+
+	if err := s.revoke(ctx, session.UserID, session.ID, model.SessionRevokeReasonRefreshReuse); err != nil &&
+		!errors.Is(err, pkgerrors.SessionNotFound) {
+		logger.Logger.Error("Failed to revoke session after refresh token reuse",
+			zap.Int64("session_id", session.ID),
+			zap.Error(err),
+		)
+	}
+}
+
+func (s *SessionService) getUser(ctx context.Context, userID string) (*model.User, error) {
+	var userIDInt int64
+	if _, err := fmt.Sscanf(userID, "%d", &userIDInt); err != nil {
+		return nil, pkgerrors.InvalidUserID
+	}
+
+	q := query.Use(database.DB().WithContext(ctx))
+	user, err := q.User.GetByPublicID(userIDInt)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgerrors.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to query user: %w", err)
+	}
+	return user, nil
+}
+
+func refreshTTL() time.Duration {
+	return time.Duration(config.Cfg.JWTRefreshDays) * 24 * time.Hour
+}
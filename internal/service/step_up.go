@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"AreYouOK/config"
+	"AreYouOK/internal/cache"
+	"AreYouOK/internal/model"
+	"AreYouOK/internal/model/dto"
+	"AreYouOK/internal/repository/query"
+	pkgerrors "AreYouOK/pkg/errors"
+	"AreYouOK/pkg/logger"
+	"AreYouOK/storage/database"
+	"AreYouOK/utils"
+)
+
+var (
+	stepUpService *StepUpService
+	stepUpOnce    sync.Once
+)
+
+func StepUp() *StepUpService {
+	stepUpOnce.Do(func() {
+		stepUpService = &StepUpService{}
+	})
+	return stepUpService
+}
+
+// StepUpService 二次验证
+// 已登录用户再次验证本人绑定的手机号，当前会话在 STEP_UP_MINUTES 内可以查看完整号码等敏感信息
+type StepUpService struct{}
+
+// SendCaptcha 向本人绑定的手机号发送验证码，复用登录的验证码/滑块流程
+func (s *StepUpService) SendCaptcha(ctx context.Context, userID string, req dto.StepUpCaptchaRequest) error {
+	phone, _, err := s.userPhone(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	return Verification().SendCaptcha(ctx, phone, req.SceneId, req.VerifyToken)
+}
+
+// Verify 校验验证码，标记当前会话已完成二次验证
+func (s *StepUpService) Verify(ctx context.Context, userID, sessionID, code string) (*dto.StepUpResponse, error) {
+	if sessionID == "" {
+		return nil, pkgerrors.Unauthorized
+	}
+
+	phone, user, err := s.userPhone(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := Verification().VerifyCaptcha(ctx, phone, code); err != nil {
+		return nil, err
+	}
+
+	sid, _ := strconv.ParseInt(sessionID, 10, 64)
+	if err := recordAudit(ctx, query.Use(database.DB().WithContext(ctx)), auditEntry{
+		UserID:     user.ID,
+		SessionID:  sid,
+		Action:     model.AuditActionStepUp,
+		TargetType: "session",
+		TargetID:   sessionID,
+	}); err != nil {
+		return nil, err
+	}
+
+	ttl := time.Duration(config.Cfg.StepUpMinutes) * time.Minute
+	if err := cache.SetStepUp(ctx, sessionID, ttl); err != nil {
+		return nil, fmt.Errorf("failed to store step-up: %w", err)
+	}
+
+	return &dto.StepUpResponse{ExpiresAt: time.Now().Add(ttl)}, nil
+}
+
+// IsVerified 当前会话是否在有效期内完成了二次验证，Redis 不可用时按未验证处理
+func (s *StepUpService) IsVerified(ctx context.Context, sessionID string) bool {
+	if sessionID == "" {
+		return false
+	}
+
+	verified, err := cache.HasStepUp(ctx, sessionID)
+	if err != nil {
+		logger.Logger.Warn("Failed to check step-up",
+			zap.String("session_id", sessionID),
+			zap.Error(err),
+		)
+		return false
+	}
+	return verified
+}
+
+// userPhone 用户本人绑定的手机号
+func (s *StepUpService) userPhone(ctx context.Context, userID string) (string, *model.User, error) {
+	publicID, err := strconv.ParseInt(userID, 10, 64)
+	if err != nil {
+		return "", nil, pkgerrors.InvalidUserID
+	}
+
+	user, err := query.Use(database.DB().WithContext(ctx)).User.GetByPublicID(publicID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil, pkgerrors.ErrUserNotFound
+		}
+		return "", nil, fmt.Errorf("failed to query user: %w", err)
+	}
+	if len(user.PhoneCipher) == 0 {
+		return "", nil, pkgerrors.StepUpUnavailable
+	}
+
+	phone, err := utils.DecryptPhone(user.PhoneCipher)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to decrypt phone: %w", err)
+	}
+	return phone, user, nil
+}
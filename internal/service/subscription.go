@@ -0,0 +1,339 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"AreYouOK/internal/model"
+	"AreYouOK/internal/model/dto"
+	"AreYouOK/internal/repository/query"
+	pkgerrors "AreYouOK/pkg/errors"
+	"AreYouOK/pkg/logger"
+	"AreYouOK/storage/database"
+)
+
+var (
+	subscriptionService *SubscriptionService
+	subscriptionOnce    sync.Once
+)
+
+func Subscription() *SubscriptionService {
+	subscriptionOnce.Do(func() {
+		subscriptionService = &SubscriptionService{}
+	})
+	return subscriptionService
+}
+
+type SubscriptionService struct{}
+
+// PlanForUser 获取用户当前生效的套餐（userID 为数据库主键）
+// 没有订阅或订阅已取消时返回免费版
+func (s *SubscriptionService) PlanForUser(ctx context.Context, userID int64) (model.Plan, error) {
+	q := query.Use(database.DB().WithContext(ctx))
+
+	sub, err := q.Subscription.
+		Where(q.Subscription.UserID.Eq(userID)).
+		Where(q.Subscription.Status.Eq(string(model.SubscriptionStatusActive))).
+		First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.GetPlan(model.PlanFree), nil
+		}
+		return model.GetPlan(model.PlanFree), fmt.Errorf("failed to query subscription: %w", err)
+	}
+
+	return model.GetPlan(sub.Plan), nil
+}
+
+// PlansForUsers 批量获取用户套餐，避免批处理中的 N+1 查询
+func (s *SubscriptionService) PlansForUsers(ctx context.Context, userIDs []int64) (map[int64]model.Plan, error) {
+	plans := make(map[int64]model.Plan, len(userIDs))
+	for _, id := range userIDs {
+		plans[id] = model.GetPlan(model.PlanFree)
+	}
+	if len(userIDs) == 0 {
+		return plans, nil
+	}
+
+	q := query.Use(database.DB().WithContext(ctx))
+	subs, err := q.Subscription.
+		Where(q.Subscription.UserID.In(userIDs...)).
+		Where(q.Subscription.Status.Eq(string(model.SubscriptionStatusActive))).
+		Find()
+	if err != nil {
+		return plans, fmt.Errorf("failed to query subscriptions: %w", err)
+	}
+
+	for _, sub := range subs {
+		plans[sub.UserID] = model.GetPlan(sub.Plan)
+	}
+	return plans, nil
+}
+
+// GetUserSubscription 获取用户订阅详情
+func (s *SubscriptionService) GetUserSubscription(
+	ctx context.Context,
+	userID string,
+) (*dto.SubscriptionData, error) {
+	user, err := subscriptionUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	q := query.Use(database.DB().WithContext(ctx))
+
+	plan := model.GetPlan(model.PlanFree)
+	result := &dto.SubscriptionData{Status: string(model.SubscriptionStatusActive)}
+
+	sub, err := q.Subscription.Where(q.Subscription.UserID.Eq(user.ID)).First()
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to query subscription: %w", err)
+	}
+	if err == nil {
+		result.Status = string(sub.Status)
+		if sub.Status == model.SubscriptionStatusActive {
+			plan = model.GetPlan(sub.Plan)
+			nextGrantAt := sub.NextGrantAt
+			result.NextGrantAt = &nextGrantAt
+		}
+	}
+
+	channels := make([]string, 0, len(plan.Channels))
+	for _, c := range plan.Channels {
+		channels = append(channels, string(c))
+	}
+
+	result.Plan = string(plan.Code)
+	result.PlanName = plan.Name
+	result.MonthlyGrant = plan.MonthlyGrant
+	result.MaxContacts = plan.MaxContacts
+	result.MonthlyReminderLimit = plan.MonthlyReminderLimit
+	result.Channels = channels
+
+	return result, nil
+}
+
+// subscriptionUser 按 public_id 查询用户
+func subscriptionUser(ctx context.Context, userID string) (*model.User, error) {
+	var userIDInt int64
+	if _, err := fmt.Sscanf(userID, "%d", &userIDInt); err != nil {
+		return nil, pkgerrors.InvalidUserID
+	}
+
+	user, err := query.Use(database.DB().WithContext(ctx)).User.GetByPublicID(userIDInt)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgerrors.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to query user: %w", err)
+	}
+	return user, nil
+}
+
+// AdminSubscribe 运维为用户开通或变更套餐（userID 为 public_id），返回变更后的订阅
+func (s *SubscriptionService) AdminSubscribe(
+	ctx context.Context,
+	userID string,
+	planCode model.PlanCode,
+) (*dto.SubscriptionData, error) {
+	user, err := subscriptionUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Subscribe(ctx, user.ID, planCode); err != nil {
+		return nil, err
+	}
+	return s.GetUserSubscription(ctx, userID)
+}
+
+// AdminCancelSubscription 运维取消用户订阅（userID 为 public_id），返回取消后的订阅
+func (s *SubscriptionService) AdminCancelSubscription(ctx context.Context, userID string) (*dto.SubscriptionData, error) {
+	user, err := subscriptionUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.CancelSubscription(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	return s.GetUserSubscription(ctx, userID)
+}
+
+// Subscribe 开通或变更套餐（userID 为数据库主键，供支付回调或后台调用）
+// 以开通时间作为新的计费锚点，并立即发放第一个周期的额度；重复开通同一套餐时保持不变
+func (s *SubscriptionService) Subscribe(ctx context.Context, userID int64, planCode model.PlanCode) error {
+	plan, ok := model.Plans[planCode]
+	if !ok {
+		return pkgerrors.SubscriptionPlanInvalid
+	}
+
+	db := database.DB().WithContext(ctx)
+	now := time.Now()
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+
+		// 锁定订阅，避免重复开通并发发放额度
+		sub, err := txQ.Subscription.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where(txQ.Subscription.UserID.Eq(userID)).
+			First()
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to query subscription: %w", err)
+		}
+
+		// 套餐未变且当前周期已发放（下次发放时间未到）时不重复发放，也不重置计费锚点
+		if err == nil && sub.Status == model.SubscriptionStatusActive && sub.Plan == plan.Code && now.Before(sub.NextGrantAt) {
+			logger.Logger.Info("Subscription unchanged, skipping grant",
+				zap.Int64("user_id", userID),
+				zap.String("plan", string(plan.Code)),
+			)
+			return nil
+		}
+
+		grantedCycles := 0
+		if plan.MonthlyGrant > 0 {
+			if err := Quota().grantQuotaTx(tx, userID, model.QuotaChannelSMS, plan.MonthlyGrant, model.QuotaReasonGrantPlan); err != nil {
+				return err
+			}
+			grantedCycles = 1
+		}
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			sub = &model.Subscription{
+				UserID:        userID,
+				Plan:          plan.Code,
+				Status:        model.SubscriptionStatusActive,
+				BillingAnchor: now,
+				NextGrantAt:   model.NextBillingAnniversary(now, 1),
+				GrantedCycles: grantedCycles,
+			}
+			if err := txQ.Subscription.Create(sub); err != nil {
+				return fmt.Errorf("failed to create subscription: %w", err)
+			}
+		} else {
+			if _, err := txQ.Subscription.
+				Where(txQ.Subscription.ID.Eq(sub.ID)).
+				Updates(map[string]interface{}{
+					"plan":           string(plan.Code),
+					"status":         string(model.SubscriptionStatusActive),
+					"billing_anchor": now,
+					"next_grant_at":  model.NextBillingAnniversary(now, 1),
+					"granted_cycles": grantedCycles,
+					"updated_at":     now,
+				}); err != nil {
+				return fmt.Errorf("failed to update subscription: %w", err)
+			}
+		}
+
+		logger.Logger.Info("Subscription activated",
+			zap.Int64("user_id", userID),
+			zap.String("plan", string(plan.Code)),
+			zap.Int("monthly_grant", plan.MonthlyGrant),
+		)
+
+		return nil
+	})
+}
+
+// CancelSubscription 取消订阅，之后按免费版处理，不再发放周期额度（已发放的额度保留）
+func (s *SubscriptionService) CancelSubscription(ctx context.Context, userID int64) error {
+	q := query.Use(database.DB().WithContext(ctx))
+
+	if _, err := q.Subscription.
+		Where(q.Subscription.UserID.Eq(userID)).
+		Updates(map[string]interface{}{
+			"status":     string(model.SubscriptionStatusCancelled),
+			"updated_at": time.Now(),
+		}); err != nil {
+		return fmt.Errorf("failed to cancel subscription: %w", err)
+	}
+
+	logger.Logger.Info("Subscription cancelled", zap.Int64("user_id", userID))
+	return nil
+}
+
+// GrantDueQuotas 为已到周年日的订阅发放额度（由调度器调用）
+// 每次每个订阅只发放一个周期，错过多个周期时由后续轮次依次补发
+// 通过 granted_cycles 做乐观锁，多实例并发执行时不会重复发放
+func (s *SubscriptionService) GrantDueQuotas(ctx context.Context, now time.Time, limit int) (int, error) {
+	db := database.DB().WithContext(ctx)
+	q := query.Use(db)
+
+	subs, err := q.Subscription.
+		Where(q.Subscription.Status.Eq(string(model.SubscriptionStatusActive))).
+		Where(q.Subscription.NextGrantAt.Lte(now)).
+		Order(q.Subscription.NextGrantAt).
+		Limit(limit).
+		Find()
+	if err != nil {
+		return 0, fmt.Errorf("failed to query due subscriptions: %w", err)
+	}
+
+	granted := 0
+	for _, sub := range subs {
+		plan := model.GetPlan(sub.Plan)
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			txQ := query.Use(tx)
+
+			nextCycle := sub.GrantedCycles + 1
+			if plan.MonthlyGrant <= 0 {
+				nextCycle = sub.GrantedCycles
+			}
+
+			info, err := txQ.Subscription.
+				Where(txQ.Subscription.ID.Eq(sub.ID)).
+				Where(txQ.Subscription.GrantedCycles.Eq(sub.GrantedCycles)).
+				Where(txQ.Subscription.NextGrantAt.Eq(sub.NextGrantAt)).
+				Updates(map[string]interface{}{
+					"granted_cycles": nextCycle,
+					"next_grant_at":  nextGrantAfter(sub.BillingAnchor, sub.NextGrantAt),
+					"updated_at":     now,
+				})
+			if err != nil {
+				return fmt.Errorf("failed to advance subscription: %w", err)
+			}
+			if info.RowsAffected == 0 {
+				// 已被其他实例处理
+				return nil
+			}
+
+			if plan.MonthlyGrant <= 0 {
+				return nil
+			}
+
+			if err := Quota().grantQuotaTx(tx, sub.UserID, model.QuotaChannelSMS, plan.MonthlyGrant, model.QuotaReasonGrantPlan); err != nil {
+				return err
+			}
+			granted++
+			return nil
+		})
+		if err != nil {
+			logger.Logger.Error("Failed to grant subscription quota",
+				zap.Int64("subscription_id", sub.ID),
+				zap.Int64("user_id", sub.UserID),
+				zap.Error(err),
+			)
+			continue
+		}
+	}
+
+	return granted, nil
+}
+
+// nextGrantAfter 计算 current 之后的下一个周年日
+func nextGrantAfter(anchor, current time.Time) time.Time {
+	for cycles := 1; ; cycles++ {
+		next := model.NextBillingAnniversary(anchor, cycles)
+		if next.After(current) {
+			return next
+		}
+	}
+}
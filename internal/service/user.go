@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
@@ -60,10 +61,27 @@ func (s *UserService) GetUserStatus(
 		return nil, fmt.Errorf("failed to query user: %w", err)
 	}
 
+	spendable, err := Quota().SpendableBalance(ctx, user.ID, model.QuotaChannelSMS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query SMS quota wallet: %w", err)
+	}
+
+	q := query.Use(database.DB().WithContext(ctx))
+	contactCount, err := countContacts(q, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	// 额度预警按会收到告警的联系人数量计算
+	confirmedCount, err := countConfirmedContacts(q, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	result := &dto.UserStatusData{
 		Status:        model.StatusToStringMap[user.Status],
 		PhoneVerified: user.PhoneHash != nil && *user.PhoneHash != "",
-		HasContacts:   len(user.EmergencyContacts) > 0,
+		HasContacts:   contactCount > 0,
+		LowBalance:    Quota().IsLowBalance(spendable, confirmedCount),
 	}
 
 	return result, nil
@@ -133,6 +151,27 @@ func (s *UserService) GetUserProfile(
 		phoneVerified = ok && phoneHash != nil && *phoneHash != ""
 	}
 
+	// 家的位置，未设置时不返回
+	var home *dto.GeofenceDTO
+	homeLat, latOK := resultMap["home_latitude"].(float64)
+	homeLon, lonOK := resultMap["home_longitude"].(float64)
+	var homeRadius int
+	switch v := resultMap["home_radius"].(type) {
+	case int32:
+		homeRadius = int(v)
+	case int64:
+		homeRadius = int(v)
+	case int:
+		homeRadius = v
+	}
+	if latOK && lonOK && homeRadius > 0 {
+		home = &dto.GeofenceDTO{
+			Latitude:  &homeLat,
+			Longitude: &homeLon,
+			Radius:    homeRadius,
+		}
+	}
+
 	var smsBalance int
 	if smsVal, ok := resultMap["sms_balance"]; ok && smsVal != nil {
 		switch v := smsVal.(type) {
@@ -161,6 +200,7 @@ func (s *UserService) GetUserProfile(
 			DailyCheckInGraceUntil: dailyCheckInGraceUntil,
 			Timezone:               timezone,
 			JourneyAutoNotify:      journeyAutoNotify,
+			Home:                   home,
 		},
 		Quotas: dto.QuotaBalance{
 			SMSBalance: smsBalance,
@@ -188,7 +228,7 @@ func (s *UserService) UpdateUserSettings(
 		return nil, pkgerrors.InvalidUserID
 	}
 
-	_, err := query.User.GetByPublicID(userIDInt)
+	user, err := query.User.GetByPublicID(userIDInt)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, pkgerrors.ErrUserNotFound
@@ -215,7 +255,7 @@ func (s *UserService) UpdateUserSettings(
 	updates := make(map[string]interface{})
 
 	if req.NickName != nil {
-		updates["nick_name"] = *req.NickName
+		updates["nickname"] = *req.NickName
 	}
 	if req.DailyCheckInEnabled != nil {
 		updates["daily_check_in_enabled"] = *req.DailyCheckInEnabled
@@ -235,18 +275,64 @@ func (s *UserService) UpdateUserSettings(
 	if req.Timezone != nil {
 		updates["timezone"] = *req.Timezone
 	}
+	if req.ClearHome {
+		updates["home_latitude"] = nil
+		updates["home_longitude"] = nil
+		updates["home_radius"] = 0
+	} else if req.Home != nil {
+		lat, lon, radius, err := normalizeGeofence(*req.Home)
+		if err != nil {
+			return nil, err
+		}
+		updates["home_latitude"] = lat
+		updates["home_longitude"] = lon
+		updates["home_radius"] = radius
+	}
 
 	if len(updates) == 0 {
 		return nil, nil
 	}
 
-	if _, err := query.User.Where(query.User.PublicID.Eq(userIDInt)).Updates(updates); err != nil {
-		return nil, fmt.Errorf("failed to update user settings: %w", err)
+	before := map[string]interface{}{
+		"nickname":                   user.Nickname,
+		"daily_check_in_enabled":     user.DailyCheckInEnabled,
+		"daily_check_in_remind_at":   user.DailyCheckInRemindAt,
+		"daily_check_in_deadline":    user.DailyCheckInDeadline,
+		"daily_check_in_grace_until": user.DailyCheckInGraceUntil,
+		"journey_auto_notify":        user.JourneyAutoNotify,
+		"timezone":                   user.Timezone,
+		"home_latitude":              user.HomeLatitude,
+		"home_longitude":             user.HomeLongitude,
+		"home_radius":                user.HomeRadius,
+	}
+
+	err = database.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+		if _, err := txQ.User.Where(txQ.User.PublicID.Eq(userIDInt)).Updates(updates); err != nil {
+			return fmt.Errorf("failed to update user settings: %w", err)
+		}
+
+		return recordAudit(ctx, txQ, auditEntry{
+			UserID:     user.ID,
+			Action:     model.AuditActionSettingsUpdate,
+			TargetType: "user",
+			TargetID:   userID,
+			Diff:       auditDiff(before, updates),
+		})
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	// 只记录字段名，取值见审计事件
+	fields := make([]string, 0, len(updates))
+	for field := range updates {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
 	logger.Logger.Info("User settings updated",
 		zap.String("user_id", userID),
-		zap.Any("updates", updates),
+		zap.Strings("fields", fields),
 	)
 
 	updatedUser, err := query.User.GetByPublicID(userIDInt)
@@ -327,11 +413,23 @@ func (s *UserService) GetUserQuotas(
 	smsWallet, err := query.QuotaWallet.
 		Where(query.QuotaWallet.UserID.Eq(user.ID)).
 		Where(query.QuotaWallet.Channel.Eq(string(model.QuotaChannelSMS))).
+		Where(query.QuotaWallet.GroupID.IsNull()).
 		First()
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, fmt.Errorf("failed to query SMS quota wallet: %w", err)
 	}
 
+	// 加入共享钱包时，可用额度包含共享钱包中本月上限内的部分
+	spendable, err := Quota().SpendableBalance(ctx, user.ID, model.QuotaChannelSMS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query SMS quota wallet: %w", err)
+	}
+
+	contactCount, err := countConfirmedContacts(query.Use(database.DB().WithContext(ctx)), user.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	// 查询 Voice 渠道额度
 	// voiceWallet, err := query.QuotaWallet.
 	// 	Where(query.QuotaWallet.UserID.Eq(user.ID)).
@@ -355,6 +453,8 @@ func (s *UserService) GetUserQuotas(
 		//VoiceBalance:   voiceBalance,
 		SMSUnitPrice: 5,
 		//VoiceUnitPrice: 0.1,
+		SpendableBalance: spendable,
+		LowBalance:       Quota().IsLowBalance(spendable, contactCount),
 	}
 
 	return result, nil
@@ -374,46 +474,13 @@ func (s *UserService) GetWaitlistStatus(ctx context.Context) (*dto.WaitlistStatu
 	return result, nil
 }
 
-// 删除当前的联系人， 软删除
+// DeleteUser 注销账号
 func (s *UserService) DeleteUser(
 	ctx context.Context,
 	userID string,
 ) error {
-	var userIDInt int64
-	if _, err := fmt.Sscanf(userID, "%d", &userIDInt); err != nil {
-		return pkgerrors.InvalidUserID
-	}
-
-	db := database.DB().WithContext(ctx)
-	q := query.Use(db)
-
-	user, err := q.User.GetByPublicID(userIDInt)
-
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return pkgerrors.ErrUserNotFound
-		}
-		return fmt.Errorf("failed to query user: %w", err)
-	}
-
-	now := time.Now()
-	updates := map[string]interface{}{
-		"updated_at": now,
-		"deleted_at": now,
-	}
-
-	if _, err := q.User.
-		Where(q.User.ID.Eq(user.ID)).
-		Updates(updates); err != nil {
-		return fmt.Errorf("failed to soft delete user: %w", err)
-	}
-
-	logger.Logger.Info("User soft-deleted",
-		zap.Int64("user_id", user.ID),
-		zap.Int64("public_id", user.PublicID),
-	)
-
-	return nil
+	// 注销进入恢复窗口，窗口结束后由 scheduler 清除个人数据
+	return Erasure().Request(ctx, userID)
 }
 
 // GetWaitListInfo 基于 auth_code / alipay_open_id 查找或创建最小用户，并返回引导步骤
@@ -488,8 +555,19 @@ func (s *UserService) GetWaitListInfo(ctx context.Context, authCode string, alip
 			Nickname:     fmt.Sprintf("用户%d", publicID%100000),
 		}
 
-		if err := q.User.Create(newUser); err != nil {
-			return nil, fmt.Errorf("failed to create waitlist user: %w", err)
+		err := db.Transaction(func(tx *gorm.DB) error {
+			txQ := query.Use(tx)
+
+			if err := txQ.User.Create(newUser); err != nil {
+				return fmt.Errorf("failed to create waitlist user: %w", err)
+			}
+			return bindIdentity(txQ, newUser.ID, model.IdentityClaim{
+				Provider: model.IdentityProviderAlipay,
+				Subject:  alipayID,
+			})
+		})
+		if err != nil {
+			return nil, err
 		}
 
 		user = newUser
@@ -0,0 +1,880 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"AreYouOK/internal/model"
+	"AreYouOK/internal/model/dto"
+	"AreYouOK/internal/repository/query"
+	pkgerrors "AreYouOK/pkg/errors"
+	"AreYouOK/pkg/logger"
+	"AreYouOK/storage/database"
+	"AreYouOK/utils"
+)
+
+var (
+	walletGroupService *WalletGroupService
+	walletGroupOnce    sync.Once
+)
+
+func WalletGroup() *WalletGroupService {
+	walletGroupOnce.Do(func() {
+		walletGroupService = &WalletGroupService{}
+	})
+	return walletGroupService
+}
+
+// walletGroupInvitationTTL 共享钱包邀请的有效期
+const walletGroupInvitationTTL = 7 * 24 * time.Hour
+
+// WalletGroupService 共享钱包：多个用户共用一个额度钱包，由创建者管理成员与月度上限
+type WalletGroupService struct{}
+
+// CreateGroup 创建共享钱包，当前用户成为创建者
+func (s *WalletGroupService) CreateGroup(
+	ctx context.Context,
+	userID string,
+	req dto.CreateWalletGroupRequest,
+) (*dto.WalletGroupData, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	db := database.DB().WithContext(ctx)
+	err = db.Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+
+		joined, err := txQ.WalletGroupMember.Where(txQ.WalletGroupMember.UserID.Eq(user.ID)).Count()
+		if err != nil {
+			return fmt.Errorf("failed to query wallet group member: %w", err)
+		}
+		if joined > 0 {
+			return pkgerrors.WalletGroupAlreadyJoined
+		}
+
+		group := &model.WalletGroup{
+			Name:    req.Name,
+			OwnerID: user.ID,
+		}
+		if err := txQ.WalletGroup.Create(group); err != nil {
+			return fmt.Errorf("failed to create wallet group: %w", err)
+		}
+
+		member := &model.WalletGroupMember{
+			GroupID:      group.ID,
+			UserID:       user.ID,
+			Role:         model.WalletGroupRoleOwner,
+			FundingOrder: model.FundingOrderPersonalFirst,
+		}
+		if err := txQ.WalletGroupMember.Create(member); err != nil {
+			return fmt.Errorf("failed to create wallet group member: %w", err)
+		}
+
+		groupID := group.ID
+		wallet := &model.QuotaWallet{
+			UserID:  user.ID,
+			GroupID: &groupID,
+			Channel: model.QuotaChannelSMS,
+		}
+		if err := txQ.QuotaWallet.Create(wallet); err != nil {
+			return fmt.Errorf("failed to create shared wallet: %w", err)
+		}
+
+		logger.Logger.Info("Wallet group created",
+			zap.Int64("group_id", group.ID),
+			zap.Int64("owner_id", user.ID),
+		)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetMyGroup(ctx, userID)
+}
+
+// GetMyGroup 获取当前用户所在的共享钱包
+func (s *WalletGroupService) GetMyGroup(ctx context.Context, userID string) (*dto.WalletGroupData, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	db := database.DB().WithContext(ctx)
+	q := query.Use(db)
+
+	self, err := s.getMembership(q, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := q.WalletGroup.Where(q.WalletGroup.ID.Eq(self.GroupID)).First()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query wallet group: %w", err)
+	}
+
+	members, users, err := s.listMembers(q, group.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	wallet, err := q.QuotaWallet.
+		Where(q.QuotaWallet.GroupID.Eq(group.ID)).
+		Where(q.QuotaWallet.Channel.Eq(string(model.QuotaChannelSMS))).
+		First()
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to query shared wallet: %w", err)
+	}
+
+	result := &dto.WalletGroupData{
+		ID:      strconv.FormatInt(group.ID, 10),
+		Name:    group.Name,
+		Role:    string(self.Role),
+		Members: make([]dto.WalletGroupMemberItem, 0, len(members)),
+	}
+	if wallet != nil {
+		result.SMSBalance = wallet.AvailableAmount
+	}
+
+	if self.Role == model.WalletGroupRoleOwner {
+		invitations, err := q.WalletGroupInvitation.
+			Where(q.WalletGroupInvitation.GroupID.Eq(group.ID)).
+			Where(q.WalletGroupInvitation.ExpiresAt.Gt(time.Now())).
+			Order(q.WalletGroupInvitation.CreatedAt).
+			Find()
+		if err != nil {
+			return nil, fmt.Errorf("failed to query wallet group invitations: %w", err)
+		}
+		for _, invitation := range invitations {
+			item := dto.WalletGroupInvitationItem{
+				ID:         strconv.FormatInt(invitation.ID, 10),
+				MonthlyCap: invitation.MonthlyCap,
+				ExpiresAt:  invitation.ExpiresAt,
+			}
+			if invitee, err := q.User.GetByID(invitation.UserID); err == nil {
+				item.InviteeNickname = invitee.Nickname
+			}
+			result.Invitations = append(result.Invitations, item)
+		}
+	}
+
+	for _, m := range members {
+		u, ok := users[m.UserID]
+		if !ok {
+			continue
+		}
+		result.Members = append(result.Members, dto.WalletGroupMemberItem{
+			UserID:       strconv.FormatInt(u.PublicID, 10),
+			Nickname:     u.Nickname,
+			Role:         string(m.Role),
+			FundingOrder: string(m.FundingOrder),
+			MonthlyCap:   m.MonthlyCap,
+		})
+	}
+
+	return result, nil
+}
+
+// InviteMember 创建者按手机号邀请成员（对方需已注册且未加入其他共享钱包），对方接受后才加入
+// 重复邀请同一用户时刷新有效期与月度上限
+func (s *WalletGroupService) InviteMember(
+	ctx context.Context,
+	userID string,
+	req dto.AddWalletGroupMemberRequest,
+) (*dto.WalletGroupData, error) {
+	if req.MonthlyCap < 0 {
+		return nil, pkgerrors.WalletGroupMemberInvalid
+	}
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	db := database.DB().WithContext(ctx)
+	q := query.Use(db)
+
+	self, err := s.getMembership(q, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if self.Role != model.WalletGroupRoleOwner {
+		return nil, pkgerrors.WalletGroupPermissionDenied
+	}
+
+	target, err := q.User.GetByPhoneHashes(utils.PhoneHashCandidates(req.Phone))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgerrors.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to query user: %w", err)
+	}
+
+	joined, err := q.WalletGroupMember.Where(q.WalletGroupMember.UserID.Eq(target.ID)).Count()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query wallet group member: %w", err)
+	}
+	if joined > 0 {
+		return nil, pkgerrors.WalletGroupAlreadyJoined
+	}
+
+	now := time.Now()
+	invitation := &model.WalletGroupInvitation{
+		GroupID:    self.GroupID,
+		UserID:     target.ID,
+		InviterID:  user.ID,
+		MonthlyCap: req.MonthlyCap,
+		ExpiresAt:  now.Add(walletGroupInvitationTTL),
+	}
+	if err := q.WalletGroupInvitation.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "group_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"inviter_id", "monthly_cap", "expires_at", "updated_at"}),
+	}).Create(invitation); err != nil {
+		return nil, fmt.Errorf("failed to create wallet group invitation: %w", err)
+	}
+
+	logger.Logger.Info("Wallet group member invited",
+		zap.Int64("group_id", self.GroupID),
+		zap.Int64("invitee_id", target.ID),
+		zap.Int("monthly_cap", req.MonthlyCap),
+	)
+
+	return s.GetMyGroup(ctx, userID)
+}
+
+// ListInvitations 当前用户收到的未过期邀请
+func (s *WalletGroupService) ListInvitations(ctx context.Context, userID string) ([]dto.WalletGroupInvitationItem, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	q := query.Use(database.DB().WithContext(ctx))
+	invitations, err := q.WalletGroupInvitation.
+		Where(q.WalletGroupInvitation.UserID.Eq(user.ID)).
+		Where(q.WalletGroupInvitation.ExpiresAt.Gt(time.Now())).
+		Order(q.WalletGroupInvitation.CreatedAt.Desc()).
+		Find()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query wallet group invitations: %w", err)
+	}
+
+	result := make([]dto.WalletGroupInvitationItem, 0, len(invitations))
+	for _, invitation := range invitations {
+		// 组已解散的邀请不再展示
+		group, err := q.WalletGroup.Where(q.WalletGroup.ID.Eq(invitation.GroupID)).First()
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to query wallet group: %w", err)
+		}
+		item := dto.WalletGroupInvitationItem{
+			ID:         strconv.FormatInt(invitation.ID, 10),
+			GroupName:  group.Name,
+			MonthlyCap: invitation.MonthlyCap,
+			ExpiresAt:  invitation.ExpiresAt,
+		}
+		if inviter, err := q.User.GetByID(invitation.InviterID); err == nil {
+			item.InviterNickname = inviter.Nickname
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// AcceptInvitation 接受邀请加入共享钱包，按邀请中的月度上限加入
+func (s *WalletGroupService) AcceptInvitation(
+	ctx context.Context,
+	userID string,
+	invitationID string,
+) (*dto.WalletGroupData, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	id, err := strconv.ParseInt(invitationID, 10, 64)
+	if err != nil {
+		return nil, pkgerrors.WalletGroupInvitationNotFound
+	}
+
+	db := database.DB().WithContext(ctx)
+	err = db.Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+
+		invitation, err := txQ.WalletGroupInvitation.
+			Where(txQ.WalletGroupInvitation.ID.Eq(id)).
+			Where(txQ.WalletGroupInvitation.UserID.Eq(user.ID)).
+			Where(txQ.WalletGroupInvitation.ExpiresAt.Gt(time.Now())).
+			First()
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return pkgerrors.WalletGroupInvitationNotFound
+			}
+			return fmt.Errorf("failed to query wallet group invitation: %w", err)
+		}
+
+		if _, err := txQ.WalletGroup.Where(txQ.WalletGroup.ID.Eq(invitation.GroupID)).First(); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return pkgerrors.WalletGroupInvitationNotFound
+			}
+			return fmt.Errorf("failed to query wallet group: %w", err)
+		}
+
+		joined, err := txQ.WalletGroupMember.Where(txQ.WalletGroupMember.UserID.Eq(user.ID)).Count()
+		if err != nil {
+			return fmt.Errorf("failed to query wallet group member: %w", err)
+		}
+		if joined > 0 {
+			return pkgerrors.WalletGroupAlreadyJoined
+		}
+
+		member := &model.WalletGroupMember{
+			GroupID:      invitation.GroupID,
+			UserID:       user.ID,
+			Role:         model.WalletGroupRoleMember,
+			FundingOrder: model.FundingOrderPersonalFirst,
+			MonthlyCap:   invitation.MonthlyCap,
+		}
+		// 唯一索引兜底并发接受
+		if err := txQ.WalletGroupMember.Create(member); err != nil {
+			return fmt.Errorf("failed to create wallet group member: %w", err)
+		}
+
+		// 加入后其他组的邀请一并作废
+		if _, err := txQ.WalletGroupInvitation.Unscoped().
+			Where(txQ.WalletGroupInvitation.UserID.Eq(user.ID)).
+			Delete(); err != nil {
+			return fmt.Errorf("failed to delete wallet group invitations: %w", err)
+		}
+
+		logger.Logger.Info("Wallet group member joined",
+			zap.Int64("group_id", invitation.GroupID),
+			zap.Int64("member_id", user.ID),
+			zap.Int("monthly_cap", invitation.MonthlyCap),
+		)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetMyGroup(ctx, userID)
+}
+
+// DeclineInvitation 被邀请人拒绝邀请
+func (s *WalletGroupService) DeclineInvitation(ctx context.Context, userID string, invitationID string) error {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	id, err := strconv.ParseInt(invitationID, 10, 64)
+	if err != nil {
+		return pkgerrors.WalletGroupInvitationNotFound
+	}
+
+	q := query.Use(database.DB().WithContext(ctx))
+	info, err := q.WalletGroupInvitation.Unscoped().
+		Where(q.WalletGroupInvitation.ID.Eq(id)).
+		Where(q.WalletGroupInvitation.UserID.Eq(user.ID)).
+		Delete()
+	if err != nil {
+		return fmt.Errorf("failed to delete wallet group invitation: %w", err)
+	}
+	if info.RowsAffected == 0 {
+		return pkgerrors.WalletGroupInvitationNotFound
+	}
+	return nil
+}
+
+// CancelInvitation 创建者撤销尚未接受的邀请
+func (s *WalletGroupService) CancelInvitation(ctx context.Context, userID string, invitationID string) error {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	id, err := strconv.ParseInt(invitationID, 10, 64)
+	if err != nil {
+		return pkgerrors.WalletGroupInvitationNotFound
+	}
+
+	q := query.Use(database.DB().WithContext(ctx))
+	self, err := s.getMembership(q, user.ID)
+	if err != nil {
+		return err
+	}
+	if self.Role != model.WalletGroupRoleOwner {
+		return pkgerrors.WalletGroupPermissionDenied
+	}
+
+	info, err := q.WalletGroupInvitation.Unscoped().
+		Where(q.WalletGroupInvitation.ID.Eq(id)).
+		Where(q.WalletGroupInvitation.GroupID.Eq(self.GroupID)).
+		Delete()
+	if err != nil {
+		return fmt.Errorf("failed to delete wallet group invitation: %w", err)
+	}
+	if info.RowsAffected == 0 {
+		return pkgerrors.WalletGroupInvitationNotFound
+	}
+	return nil
+}
+
+// UpdateMember 更新成员设置
+// 月度上限只能由创建者修改，扣费顺序只能由成员本人修改
+func (s *WalletGroupService) UpdateMember(
+	ctx context.Context,
+	userID string,
+	memberPublicID string,
+	req dto.UpdateWalletGroupMemberRequest,
+) (*dto.WalletGroupData, error) {
+	if req.MonthlyCap != nil && *req.MonthlyCap < 0 {
+		return nil, pkgerrors.WalletGroupMemberInvalid
+	}
+	if req.FundingOrder != nil {
+		switch model.FundingOrder(*req.FundingOrder) {
+		case model.FundingOrderPersonalFirst, model.FundingOrderSharedFirst:
+		default:
+			return nil, pkgerrors.WalletGroupMemberInvalid
+		}
+	}
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	db := database.DB().WithContext(ctx)
+	q := query.Use(db)
+
+	self, err := s.getMembership(q, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := s.getGroupMember(q, self.GroupID, memberPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if req.MonthlyCap != nil {
+		if self.Role != model.WalletGroupRoleOwner {
+			return nil, pkgerrors.WalletGroupPermissionDenied
+		}
+		updates["monthly_cap"] = *req.MonthlyCap
+	}
+	if req.FundingOrder != nil {
+		if target.UserID != user.ID {
+			return nil, pkgerrors.WalletGroupPermissionDenied
+		}
+		updates["funding_order"] = *req.FundingOrder
+	}
+
+	if len(updates) > 0 {
+		updates["updated_at"] = time.Now()
+		if _, err := q.WalletGroupMember.
+			Where(q.WalletGroupMember.ID.Eq(target.ID)).
+			Updates(updates); err != nil {
+			return nil, fmt.Errorf("failed to update wallet group member: %w", err)
+		}
+	}
+
+	return s.GetMyGroup(ctx, userID)
+}
+
+// RemoveMember 创建者移除成员，或成员自行退出（创建者不能退出）
+func (s *WalletGroupService) RemoveMember(ctx context.Context, userID string, memberPublicID string) error {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	db := database.DB().WithContext(ctx)
+	q := query.Use(db)
+
+	self, err := s.getMembership(q, user.ID)
+	if err != nil {
+		return err
+	}
+
+	target, err := s.getGroupMember(q, self.GroupID, memberPublicID)
+	if err != nil {
+		return err
+	}
+
+	if target.Role == model.WalletGroupRoleOwner {
+		return pkgerrors.WalletGroupOwnerCannotLeave
+	}
+	if self.Role != model.WalletGroupRoleOwner && target.UserID != user.ID {
+		return pkgerrors.WalletGroupPermissionDenied
+	}
+
+	// 物理删除，便于之后重新加入（user_id 唯一）
+	if _, err := q.WalletGroupMember.Unscoped().
+		Where(q.WalletGroupMember.ID.Eq(target.ID)).
+		Delete(); err != nil {
+		return fmt.Errorf("failed to remove wallet group member: %w", err)
+	}
+
+	logger.Logger.Info("Wallet group member removed",
+		zap.Int64("group_id", self.GroupID),
+		zap.Int64("member_id", target.UserID),
+		zap.Int64("operator_id", user.ID),
+	)
+	return nil
+}
+
+// Transfer 从个人钱包转入共享钱包，组内任意成员都可以为共享钱包充值
+func (s *WalletGroupService) Transfer(
+	ctx context.Context,
+	userID string,
+	req dto.TransferToWalletGroupRequest,
+) (*dto.WalletGroupData, error) {
+	// binding 标签不会被校验，金额在这里检查；负数会把共享钱包的额度转回个人钱包
+	if req.Amount <= 0 {
+		return nil, pkgerrors.WalletGroupAmountInvalid
+	}
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	db := database.DB().WithContext(ctx)
+	err = db.Transaction(func(tx *gorm.DB) error {
+		txQ := query.Use(tx)
+
+		self, err := s.getMembership(txQ, user.ID)
+		if err != nil {
+			return err
+		}
+
+		var personal model.QuotaWallet
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where(personalWalletCond, user.ID, model.QuotaChannelSMS).
+			First(&personal).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return pkgerrors.QuotaInsufficient
+			}
+			return fmt.Errorf("failed to query wallet: %w", err)
+		}
+		if personal.AvailableAmount < req.Amount {
+			return pkgerrors.QuotaInsufficient
+		}
+
+		var shared model.QuotaWallet
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("group_id = ? AND channel = ?", self.GroupID, model.QuotaChannelSMS).
+			First(&shared).Error; err != nil {
+			return fmt.Errorf("failed to query shared wallet: %w", err)
+		}
+
+		now := time.Now()
+		if err := tx.Model(&personal).Updates(map[string]interface{}{
+			"available_amount": gorm.Expr("available_amount - ?", req.Amount),
+			"updated_at":       now,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to deduct personal wallet: %w", err)
+		}
+		if err := tx.Model(&shared).Updates(map[string]interface{}{
+			"available_amount": gorm.Expr("available_amount + ?", req.Amount),
+			"total_granted":    gorm.Expr("total_granted + ?", req.Amount),
+			"updated_at":       now,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to credit shared wallet: %w", err)
+		}
+
+		transactions := []*model.QuotaTransaction{
+			{
+				UserID:          user.ID,
+				WalletID:        personal.ID,
+				Channel:         model.QuotaChannelSMS,
+				TransactionType: model.TransactionTypeDeduct,
+				Reason:          model.QuotaReasonTransferOut,
+				Amount:          req.Amount,
+				BalanceAfter:    personal.AvailableAmount - req.Amount,
+			},
+			{
+				UserID:          user.ID,
+				WalletID:        shared.ID,
+				Channel:         model.QuotaChannelSMS,
+				TransactionType: model.TransactionTypeGrant,
+				Reason:          model.QuotaReasonGrantTransfer,
+				Amount:          req.Amount,
+				BalanceAfter:    shared.AvailableAmount + req.Amount,
+			},
+		}
+		if err := txQ.QuotaTransaction.Create(transactions...); err != nil {
+			return fmt.Errorf("failed to create transfer transactions: %w", err)
+		}
+
+		logger.Logger.Info("Quota transferred to shared wallet",
+			zap.Int64("user_id", user.ID),
+			zap.Int64("group_id", self.GroupID),
+			zap.Int("amount", req.Amount),
+		)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetMyGroup(ctx, userID)
+}
+
+// GetUsage 查询成员在指定月份从共享钱包消费的额度（仅创建者）
+func (s *WalletGroupService) GetUsage(
+	ctx context.Context,
+	userID string,
+	req dto.WalletGroupUsageQuery,
+) (*dto.WalletGroupUsageData, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	since := monthStart(time.Now())
+	if req.Month != "" {
+		month, err := time.ParseInLocation("2006-01", req.Month, time.Local)
+		if err != nil {
+			return nil, pkgerrors.WalletGroupMonthInvalid
+		}
+		since = month
+	}
+	until := since.AddDate(0, 1, 0)
+
+	db := database.DB().WithContext(ctx)
+	q := query.Use(db)
+
+	self, err := s.getMembership(q, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if self.Role != model.WalletGroupRoleOwner {
+		return nil, pkgerrors.WalletGroupPermissionDenied
+	}
+
+	members, users, err := s.listMembers(q, self.GroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.WalletGroupUsageData{
+		Month:   since.Format("2006-01"),
+		Members: make([]dto.WalletGroupMemberUsage, 0, len(members)),
+	}
+
+	wallet, err := q.QuotaWallet.
+		Where(q.QuotaWallet.GroupID.Eq(self.GroupID)).
+		Where(q.QuotaWallet.Channel.Eq(string(model.QuotaChannelSMS))).
+		First()
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to query shared wallet: %w", err)
+	}
+
+	usage := map[int64]int{}
+	if wallet != nil {
+		// 按 user_id 汇总，总量包含已离开成员的消费
+		usage, err = Quota().walletUsageByUser(db, wallet.ID, nil, since, until)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, m := range members {
+		u, ok := users[m.UserID]
+		if !ok {
+			continue
+		}
+		result.Members = append(result.Members, dto.WalletGroupMemberUsage{
+			UserID:     strconv.FormatInt(u.PublicID, 10),
+			Nickname:   u.Nickname,
+			Spent:      usage[m.UserID],
+			MonthlyCap: m.MonthlyCap,
+		})
+	}
+	for _, spent := range usage {
+		result.TotalSpent += spent
+	}
+
+	return result, nil
+}
+
+// settleSharedWalletsTx 解散共享钱包时结清余额：按各用户累计转入的额度比例退回个人钱包
+// 注销的创建者（excludeUserID）的份额不再退回，与取整余数一并随钱包作废；返回退回的总额
+func (s *WalletGroupService) settleSharedWalletsTx(tx *gorm.DB, groupID int64, excludeUserID int64) (int, error) {
+	var wallets []model.QuotaWallet
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("group_id = ?", groupID).
+		Find(&wallets).Error; err != nil {
+		return 0, fmt.Errorf("failed to query shared wallets: %w", err)
+	}
+
+	type contributionRow struct {
+		UserID int64
+		Amount int
+	}
+
+	refunded := 0
+	for i := range wallets {
+		wallet := &wallets[i]
+		if wallet.AvailableAmount <= 0 {
+			continue
+		}
+
+		var rows []contributionRow
+		if err := tx.Model(&model.QuotaTransaction{}).
+			Select("user_id, COALESCE(SUM(amount), 0) AS amount").
+			Where("wallet_id = ? AND reason = ?", wallet.ID, model.QuotaReasonGrantTransfer).
+			Group("user_id").
+			Scan(&rows).Error; err != nil {
+			return 0, fmt.Errorf("failed to query shared wallet contributions: %w", err)
+		}
+
+		total := 0
+		for _, row := range rows {
+			total += row.Amount
+		}
+		if total <= 0 {
+			continue
+		}
+
+		for _, row := range rows {
+			if row.UserID == excludeUserID {
+				continue
+			}
+			share := int(int64(wallet.AvailableAmount) * int64(row.Amount) / int64(total))
+			if share <= 0 {
+				continue
+			}
+			if err := Quota().grantQuotaTx(tx, row.UserID, wallet.Channel, share, model.QuotaReasonGrantGroupSettle); err != nil {
+				return 0, err
+			}
+			refunded += share
+		}
+
+		if err := tx.Model(wallet).Updates(map[string]interface{}{
+			"available_amount": 0,
+			"updated_at":       time.Now(),
+		}).Error; err != nil {
+			return 0, fmt.Errorf("failed to settle shared wallet: %w", err)
+		}
+		if err := tx.Create(&model.QuotaTransaction{
+			UserID:          wallet.UserID,
+			WalletID:        wallet.ID,
+			Channel:         wallet.Channel,
+			TransactionType: model.TransactionTypeDeduct,
+			Reason:          model.QuotaReasonGroupSettleOut,
+			Amount:          wallet.AvailableAmount,
+			BalanceAfter:    0,
+		}).Error; err != nil {
+			return 0, fmt.Errorf("failed to create settle transaction: %w", err)
+		}
+
+		logger.Logger.Info("Shared wallet settled",
+			zap.Int64("group_id", groupID),
+			zap.Int64("wallet_id", wallet.ID),
+			zap.Int("balance", wallet.AvailableAmount),
+			zap.Int("refunded", refunded),
+		)
+	}
+	return refunded, nil
+}
+
+func (s *WalletGroupService) getUser(ctx context.Context, userID string) (*model.User, error) {
+	var userIDInt int64
+	if _, err := fmt.Sscanf(userID, "%d", &userIDInt); err != nil {
+		return nil, pkgerrors.InvalidUserID
+	}
+
+	q := query.Use(database.DB().WithContext(ctx))
+	user, err := q.User.GetByPublicID(userIDInt)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgerrors.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to query user: %w", err)
+	}
+	return user, nil
+}
+
+// getMembership 查询用户所在组的成员记录，未加入时返回 WalletGroupNotFound
+func (s *WalletGroupService) getMembership(q *query.Query, userID int64) (*model.WalletGroupMember, error) {
+	member, err := q.WalletGroupMember.Where(q.WalletGroupMember.UserID.Eq(userID)).First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgerrors.WalletGroupNotFound
+		}
+		return nil, fmt.Errorf("failed to query wallet group member: %w", err)
+	}
+	return member, nil
+}
+
+// getGroupMember 根据 public_id 查询同组成员
+func (s *WalletGroupService) getGroupMember(q *query.Query, groupID int64, memberPublicID string) (*model.WalletGroupMember, error) {
+	var publicID int64
+	if _, err := fmt.Sscanf(memberPublicID, "%d", &publicID); err != nil {
+		return nil, pkgerrors.InvalidUserID
+	}
+
+	target, err := q.User.GetByPublicID(publicID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgerrors.WalletGroupMemberNotFound
+		}
+		return nil, fmt.Errorf("failed to query user: %w", err)
+	}
+
+	member, err := q.WalletGroupMember.
+		Where(q.WalletGroupMember.GroupID.Eq(groupID)).
+		Where(q.WalletGroupMember.UserID.Eq(target.ID)).
+		First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgerrors.WalletGroupMemberNotFound
+		}
+		return nil, fmt.Errorf("failed to query wallet group member: %w", err)
+	}
+	return member, nil
+}
+
+// listMembers 查询组内成员及对应用户
+func (s *WalletGroupService) listMembers(q *query.Query, groupID int64) ([]*model.WalletGroupMember, map[int64]*model.User, error) {
+	members, err := q.WalletGroupMember.
+		Where(q.WalletGroupMember.GroupID.Eq(groupID)).
+		Order(q.WalletGroupMember.CreatedAt).
+		Find()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query wallet group members: %w", err)
+	}
+
+	userIDs := make([]int64, 0, len(members))
+	for _, m := range members {
+		userIDs = append(userIDs, m.UserID)
+	}
+
+	users := make(map[int64]*model.User, len(members))
+	if len(userIDs) == 0 {
+		return members, users, nil
+	}
+
+	rows, err := q.User.Where(q.User.ID.In(userIDs...)).Find()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query wallet group users: %w", err)
+	}
+	for _, u := range rows {
+		users[u.ID] = u
+	}
+	return members, users, nil
+}
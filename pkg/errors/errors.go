@@ -27,6 +27,8 @@ var (
 	Unauthorized               = Definition{Code: "UNAUTHORIZED", Message: "Unauthorized"}
 	InvalidUserID              = Definition{Code: "INVALID_USER_ID", Message: "Invalid user ID format"}
 	InvalidPhone               = Definition{Code: "INVALID_PHONE", Message: "Invalid phone number format"}
+	WechatLoginFailed          = Definition{Code: "WECHAT_LOGIN_FAILED", Message: "WeChat login failed, please call wx.login again"}
+	WechatPhoneInvalid         = Definition{Code: "WECHAT_PHONE_INVALID", Message: "Failed to decrypt WeChat phone number"}
 )
 
 // 打卡相关错误
@@ -36,6 +38,18 @@ var (
 	ContactLimitReached     = Definition{Code: "CONTACT_LIMIT_REACHED", Message: "Contact limit reached"}
 	ContactPriorityConflict = Definition{Code: "CONTACT_PRIORITY_CONFLICT", Message: "Contact priority conflict"}
 	ContactMinRequired      = Definition{Code: "CONTACT_MIN_REQUIRED", Message: "At least one contact is required"}
+	ContactVersionConflict  = Definition{Code: "CONTACT_VERSION_CONFLICT", Message: "Contact was modified by another request, please reload"}
+
+	ContactConsentNotFound     = Definition{Code: "CONTACT_CONSENT_NOT_FOUND", Message: "Consent link not found"}
+	ContactConsentExpired      = Definition{Code: "CONTACT_CONSENT_EXPIRED", Message: "Consent link has expired"}
+	ContactConsentNotPending   = Definition{Code: "CONTACT_CONSENT_NOT_PENDING", Message: "Contact has already confirmed or declined"}
+	ContactConsentCooldown     = Definition{Code: "CONTACT_CONSENT_COOLDOWN", Message: "Consent request was sent recently, please try again later"}
+	ContactConsentLimitReached = Definition{Code: "CONTACT_CONSENT_LIMIT_REACHED", Message: "Consent request limit reached for this contact"}
+
+	ContactOptedOut          = Definition{Code: "CONTACT_OPTED_OUT", Message: "This number has opted out of notifications and cannot be added as a contact"}
+	ContactOptOutLinkInvalid = Definition{Code: "CONTACT_OPT_OUT_LINK_INVALID", Message: "Opt-out link is invalid"}
+	ContactLinkNotFound      = Definition{Code: "CONTACT_LINK_NOT_FOUND", Message: "This phone is not listed as the user's emergency contact"}
+	ContactImportInvalid     = Definition{Code: "CONTACT_IMPORT_INVALID", Message: "vCard payload is invalid or too large"}
 )
 
 // 平安打卡模块错误。
@@ -49,6 +63,25 @@ var (
 var (
 	JourneyOverlap       = Definition{Code: "JOURNEY_OVERLAP", Message: "Journey overlap"}
 	JourneyNotModifiable = Definition{Code: "JOURNEY_NOT_MODIFIABLE", Message: "Journey not modifiable"}
+	JourneyExtendLimit   = Definition{Code: "JOURNEY_EXTEND_LIMIT", Message: "Journey has reached the maximum number of extensions or total extended time"}
+
+	JourneyCheckpointInvalid  = Definition{Code: "JOURNEY_CHECKPOINT_INVALID", Message: "Checkpoints must be in the future, in chronological order and before the expected return time"}
+	JourneyCheckpointNotFound = Definition{Code: "JOURNEY_CHECKPOINT_NOT_FOUND", Message: "Journey checkpoint not found"}
+	JourneyLocationInvalid    = Definition{Code: "JOURNEY_LOCATION_INVALID", Message: "Location report must contain 1 to 50 points"}
+	JourneyCheckpointClosed   = Definition{Code: "JOURNEY_CHECKPOINT_CLOSED", Message: "Journey checkpoint has already been checked in"}
+	JourneyGeofenceInvalid    = Definition{Code: "JOURNEY_GEOFENCE_INVALID", Message: "Geofence center must be a valid coordinate and radius between 50 and 2000 meters"}
+	HomeLocationNotSet        = Definition{Code: "HOME_LOCATION_NOT_SET", Message: "Home location is not set in user settings"}
+	JourneyShareInvalid       = Definition{Code: "JOURNEY_SHARE_INVALID", Message: "Share link duration is out of range"}
+	JourneyShareNotFound      = Definition{Code: "JOURNEY_SHARE_NOT_FOUND", Message: "Shared journey not found or link has expired"}
+
+	JourneyTemplateInvalid     = Definition{Code: "JOURNEY_TEMPLATE_INVALID", Message: "Journey template is invalid"}
+	JourneyTemplateLimit       = Definition{Code: "JOURNEY_TEMPLATE_LIMIT", Message: "Journey template limit reached"}
+	JourneyTemplateNotFound    = Definition{Code: "JOURNEY_TEMPLATE_NOT_FOUND", Message: "Journey template not found"}
+	JourneyRecurrenceInvalid   = Definition{Code: "JOURNEY_RECURRENCE_INVALID", Message: "Recurrence rule must be FREQ=WEEKLY with BYDAY, BYHOUR and BYMINUTE"}
+	JourneyOccurrenceNotFound  = Definition{Code: "JOURNEY_OCCURRENCE_NOT_FOUND", Message: "Journey occurrence not found"}
+	JourneyOccurrenceClosed    = Definition{Code: "JOURNEY_OCCURRENCE_CLOSED", Message: "Journey occurrence has already started, been skipped or passed its confirmation window"}
+	JourneyContactsInvalid     = Definition{Code: "JOURNEY_CONTACTS_INVALID", Message: "Contact priorities must be distinct, within the plan's contact limit and refer to existing contacts"}
+	JourneyAlertMessageTooLong = Definition{Code: "JOURNEY_ALERT_MESSAGE_TOO_LONG", Message: "Alert message must be at most 60 characters"}
 )
 
 // 通知模块错误。
@@ -58,8 +91,89 @@ var (
 
 // 额度模块错误。
 var (
-	QuotaInsufficient   = Definition{Code: "QUOTA_INSUFFICIENT", Message: "Quota insufficient"}
-	QuotaChannelInvalid = Definition{Code: "QUOTA_CHANNEL_INVALID", Message: "Quota channel invalid"}
+	QuotaInsufficient     = Definition{Code: "QUOTA_INSUFFICIENT", Message: "Quota insufficient"}
+	QuotaChannelInvalid   = Definition{Code: "QUOTA_CHANNEL_INVALID", Message: "Quota channel invalid"}
+	QuotaChannelNotInPlan = Definition{Code: "QUOTA_CHANNEL_NOT_IN_PLAN", Message: "Channel is not included in the current plan"}
+)
+
+// 兑换码错误。
+var (
+	RedeemCodeInvalid     = Definition{Code: "REDEEM_CODE_INVALID", Message: "Redeem code invalid"}
+	RedeemCodeExpired     = Definition{Code: "REDEEM_CODE_EXPIRED", Message: "Redeem code expired"}
+	RedeemCodeExhausted   = Definition{Code: "REDEEM_CODE_EXHAUSTED", Message: "Redeem code has been fully redeemed"}
+	RedeemCodeAlreadyUsed = Definition{Code: "REDEEM_CODE_ALREADY_USED", Message: "Redeem code already used by this user"}
+)
+
+// 共享钱包错误。
+var (
+	WalletGroupNotFound           = Definition{Code: "WALLET_GROUP_NOT_FOUND", Message: "Wallet group not found"}
+	WalletGroupMemberNotFound     = Definition{Code: "WALLET_GROUP_MEMBER_NOT_FOUND", Message: "Wallet group member not found"}
+	WalletGroupAlreadyJoined      = Definition{Code: "WALLET_GROUP_ALREADY_JOINED", Message: "User already belongs to a wallet group"}
+	WalletGroupPermissionDenied   = Definition{Code: "WALLET_GROUP_PERMISSION_DENIED", Message: "Wallet group permission denied"}
+	WalletGroupOwnerCannotLeave   = Definition{Code: "WALLET_GROUP_OWNER_CANNOT_LEAVE", Message: "Wallet group owner cannot leave the group"}
+	WalletGroupMonthInvalid       = Definition{Code: "WALLET_GROUP_MONTH_INVALID", Message: "Month must be formatted as YYYY-MM"}
+	WalletGroupInvitationNotFound = Definition{Code: "WALLET_GROUP_INVITATION_NOT_FOUND", Message: "Wallet group invitation not found or has expired"}
+	WalletGroupMemberInvalid      = Definition{Code: "WALLET_GROUP_MEMBER_INVALID", Message: "Monthly cap must not be negative and funding order must be personal_first or shared_first"}
+	WalletGroupAmountInvalid      = Definition{Code: "WALLET_GROUP_AMOUNT_INVALID", Message: "Transfer amount must be positive"}
+)
+
+// 登录会话错误。
+var (
+	SessionNotFound    = Definition{Code: "SESSION_NOT_FOUND", Message: "Session not found"}
+	SessionRevoked     = Definition{Code: "SESSION_REVOKED", Message: "Session revoked or expired, please sign in again"}
+	RefreshTokenReused = Definition{Code: "REFRESH_TOKEN_REUSED", Message: "Refresh token reuse detected, session revoked"}
+)
+
+// 账号注销错误。
+var (
+	AccountNotPendingErasure = Definition{Code: "ACCOUNT_NOT_PENDING_ERASURE", Message: "Account is not pending erasure"}
+	AccountRestoreExpired    = Definition{Code: "ACCOUNT_RESTORE_EXPIRED", Message: "Account restore window has expired"}
+)
+
+// 更换手机号错误。
+var (
+	PhoneChangeCooldown = Definition{Code: "PHONE_CHANGE_COOLDOWN", Message: "Phone number was changed recently, please try again later"}
+	PhoneUnchanged      = Definition{Code: "PHONE_UNCHANGED", Message: "New phone number is the same as the current one"}
+)
+
+// 登录身份与账号合并错误。
+var (
+	IdentityProviderInvalid  = Definition{Code: "IDENTITY_PROVIDER_INVALID", Message: "Identity provider must be alipay or wechat"}
+	IdentityProofInvalid     = Definition{Code: "IDENTITY_PROOF_INVALID", Message: "Failed to verify identity, please authorize again"}
+	IdentityNotFound         = Definition{Code: "IDENTITY_NOT_FOUND", Message: "Identity not found"}
+	IdentityAlreadyLinked    = Definition{Code: "IDENTITY_ALREADY_LINKED", Message: "An identity of this provider is already linked to the account"}
+	IdentityLinkedElsewhere  = Definition{Code: "IDENTITY_LINKED_ELSEWHERE", Message: "Identity belongs to another account, merge the accounts instead"}
+	IdentityLastRemaining    = Definition{Code: "IDENTITY_LAST_REMAINING", Message: "Cannot unlink the only login identity"}
+	AccountMergeSelf         = Definition{Code: "ACCOUNT_MERGE_SELF", Message: "Identity already belongs to the current account"}
+	AccountMergeConflict     = Definition{Code: "ACCOUNT_MERGE_CONFLICT", Message: "Both accounts have identities of the same provider or a paid subscription"}
+	AccountMergeBlocked      = Definition{Code: "ACCOUNT_MERGE_BLOCKED", Message: "Account has ongoing journeys, pending notifications or a shared wallet"}
+	AccountMergePhoneInvalid = Definition{Code: "ACCOUNT_MERGE_PHONE_INVALID", Message: "Phone number does not belong to either account"}
+)
+
+// 审计日志错误。
+var (
+	AuditQueryInvalid = Definition{Code: "AUDIT_QUERY_INVALID", Message: "Time range must be RFC3339 timestamps"}
+)
+
+// 个人数据导出错误。
+var (
+	DataExportInProgress   = Definition{Code: "DATA_EXPORT_IN_PROGRESS", Message: "A data export is already in progress"}
+	DataExportNotFound     = Definition{Code: "DATA_EXPORT_NOT_FOUND", Message: "Data export not found"}
+	DataExportNotReady     = Definition{Code: "DATA_EXPORT_NOT_READY", Message: "Data export is not ready"}
+	DataExportLinkInvalid  = Definition{Code: "DATA_EXPORT_LINK_INVALID", Message: "Download link is invalid or has expired"}
+	DataExportUnavailable  = Definition{Code: "DATA_EXPORT_UNAVAILABLE", Message: "Data export has already been downloaded or has expired"}
+	DataExportVerifyNeeded = Definition{Code: "DATA_EXPORT_VERIFY_REQUIRED", Message: "Step-up verification required to export full contact numbers"}
+)
+
+// 二次验证错误。
+var (
+	StepUpUnavailable = Definition{Code: "STEP_UP_UNAVAILABLE", Message: "Bind a phone number before verifying"}
+	StepUpRequired    = Definition{Code: "STEP_UP_REQUIRED", Message: "Verify the current phone number before changing it"}
+)
+
+// 订阅模块错误。
+var (
+	SubscriptionPlanInvalid = Definition{Code: "SUBSCRIPTION_PLAN_INVALID", Message: "Subscription plan invalid"}
 )
 
 // 内测排队错误。
@@ -100,6 +214,8 @@ var (
 	ErrPhonesCodesMismatch          = Definition{Code: "PHONES_CODES_MISMATCH", Message: "phones and codes count mismatch"}
 	ErrTencentSMSNotImplemented     = Definition{Code: "TENCENT_SMS_NOT_IMPLEMENTED", Message: "tencent SMS provider not implemented yet"}
 	ErrUnsupportedSMSProvider       = Definition{Code: "UNSUPPORTED_SMS_PROVIDER", Message: "Unsupported SMS provider"}
+	ErrUnsupportedWechatProvider    = Definition{Code: "UNSUPPORTED_WECHAT_PROVIDER", Message: "Unsupported WeChat provider"}
+	ErrWechatNotInitialized         = Definition{Code: "WECHAT_NOT_INITIALIZED", Message: "WeChat client not initialized, call wechat.Init() first"}
 )
 
 // Lookup 提供错误码查询能力。
@@ -114,13 +230,81 @@ var Lookup = map[string]Definition{
 	ContactLimitReached.Code:             ContactLimitReached,
 	ContactMinRequired.Code:              ContactMinRequired,
 	ContactPriorityConflict.Code:         ContactPriorityConflict,
+	ContactVersionConflict.Code:          ContactVersionConflict,
+	ContactConsentNotFound.Code:          ContactConsentNotFound,
+	ContactConsentExpired.Code:           ContactConsentExpired,
+	ContactConsentNotPending.Code:        ContactConsentNotPending,
+	ContactConsentCooldown.Code:          ContactConsentCooldown,
+	ContactConsentLimitReached.Code:      ContactConsentLimitReached,
+	ContactOptedOut.Code:                 ContactOptedOut,
+	ContactOptOutLinkInvalid.Code:        ContactOptOutLinkInvalid,
+	ContactLinkNotFound.Code:             ContactLinkNotFound,
+	ContactImportInvalid.Code:            ContactImportInvalid,
 	CheckInDisabled.Code:                 CheckInDisabled,
 	CheckInAlreadyDone.Code:              CheckInAlreadyDone,
 	JourneyOverlap.Code:                  JourneyOverlap,
 	JourneyNotModifiable.Code:            JourneyNotModifiable,
+	JourneyExtendLimit.Code:              JourneyExtendLimit,
+	JourneyCheckpointInvalid.Code:        JourneyCheckpointInvalid,
+	JourneyCheckpointNotFound.Code:       JourneyCheckpointNotFound,
+	JourneyCheckpointClosed.Code:         JourneyCheckpointClosed,
+	JourneyLocationInvalid.Code:          JourneyLocationInvalid,
+	JourneyGeofenceInvalid.Code:          JourneyGeofenceInvalid,
+	HomeLocationNotSet.Code:              HomeLocationNotSet,
+	JourneyShareInvalid.Code:             JourneyShareInvalid,
+	JourneyShareNotFound.Code:            JourneyShareNotFound,
+	JourneyTemplateInvalid.Code:          JourneyTemplateInvalid,
+	JourneyTemplateLimit.Code:            JourneyTemplateLimit,
+	JourneyTemplateNotFound.Code:         JourneyTemplateNotFound,
+	JourneyRecurrenceInvalid.Code:        JourneyRecurrenceInvalid,
+	JourneyOccurrenceNotFound.Code:       JourneyOccurrenceNotFound,
+	JourneyOccurrenceClosed.Code:         JourneyOccurrenceClosed,
+	JourneyContactsInvalid.Code:          JourneyContactsInvalid,
+	JourneyAlertMessageTooLong.Code:      JourneyAlertMessageTooLong,
 	NotifyAckInvalid.Code:                NotifyAckInvalid,
 	QuotaInsufficient.Code:               QuotaInsufficient,
 	QuotaChannelInvalid.Code:             QuotaChannelInvalid,
+	QuotaChannelNotInPlan.Code:           QuotaChannelNotInPlan,
+	RedeemCodeInvalid.Code:               RedeemCodeInvalid,
+	RedeemCodeExpired.Code:               RedeemCodeExpired,
+	RedeemCodeExhausted.Code:             RedeemCodeExhausted,
+	RedeemCodeAlreadyUsed.Code:           RedeemCodeAlreadyUsed,
+	WalletGroupNotFound.Code:             WalletGroupNotFound,
+	WalletGroupMemberNotFound.Code:       WalletGroupMemberNotFound,
+	WalletGroupAlreadyJoined.Code:        WalletGroupAlreadyJoined,
+	WalletGroupPermissionDenied.Code:     WalletGroupPermissionDenied,
+	WalletGroupOwnerCannotLeave.Code:     WalletGroupOwnerCannotLeave,
+	WalletGroupMonthInvalid.Code:         WalletGroupMonthInvalid,
+	WalletGroupInvitationNotFound.Code:   WalletGroupInvitationNotFound,
+	WalletGroupMemberInvalid.Code:        WalletGroupMemberInvalid,
+	WalletGroupAmountInvalid.Code:        WalletGroupAmountInvalid,
+	SessionNotFound.Code:                 SessionNotFound,
+	SessionRevoked.Code:                  SessionRevoked,
+	RefreshTokenReused.Code:              RefreshTokenReused,
+	AccountNotPendingErasure.Code:        AccountNotPendingErasure,
+	AccountRestoreExpired.Code:           AccountRestoreExpired,
+	PhoneChangeCooldown.Code:             PhoneChangeCooldown,
+	PhoneUnchanged.Code:                  PhoneUnchanged,
+	IdentityProviderInvalid.Code:         IdentityProviderInvalid,
+	IdentityProofInvalid.Code:            IdentityProofInvalid,
+	IdentityNotFound.Code:                IdentityNotFound,
+	IdentityAlreadyLinked.Code:           IdentityAlreadyLinked,
+	IdentityLinkedElsewhere.Code:         IdentityLinkedElsewhere,
+	IdentityLastRemaining.Code:           IdentityLastRemaining,
+	AccountMergeSelf.Code:                AccountMergeSelf,
+	AccountMergeConflict.Code:            AccountMergeConflict,
+	AccountMergeBlocked.Code:             AccountMergeBlocked,
+	AccountMergePhoneInvalid.Code:        AccountMergePhoneInvalid,
+	AuditQueryInvalid.Code:               AuditQueryInvalid,
+	DataExportInProgress.Code:            DataExportInProgress,
+	DataExportNotFound.Code:              DataExportNotFound,
+	DataExportNotReady.Code:              DataExportNotReady,
+	DataExportLinkInvalid.Code:           DataExportLinkInvalid,
+	DataExportUnavailable.Code:           DataExportUnavailable,
+	DataExportVerifyNeeded.Code:          DataExportVerifyNeeded,
+	StepUpUnavailable.Code:               StepUpUnavailable,
+	StepUpRequired.Code:                  StepUpRequired,
+	SubscriptionPlanInvalid.Code:         SubscriptionPlanInvalid,
 	WaitlistFull.Code:                    WaitlistFull,
 	WaitlistNotInvited.Code:              WaitlistNotInvited,
 	OnboardingStepInvalid.Code:           OnboardingStepInvalid,
@@ -132,6 +316,8 @@ var Lookup = map[string]Definition{
 	ErrUnexpectedSigningMethod.Code:      ErrUnexpectedSigningMethod,
 	ErrUserNotFound.Code:                 ErrUserNotFound,
 	InvalidPhone.Code:                    InvalidPhone,
+	WechatLoginFailed.Code:               WechatLoginFailed,
+	WechatPhoneInvalid.Code:              WechatPhoneInvalid,
 	ErrDatabaseConnectionNil.Code:        ErrDatabaseConnectionNil,
 	ErrFailedToUnmarshalJSONB.Code:       ErrFailedToUnmarshalJSONB,
 	ErrCaptchaTokenRequired.Code:         ErrCaptchaTokenRequired,
@@ -145,6 +331,8 @@ var Lookup = map[string]Definition{
 	ErrPhonesCodesMismatch.Code:          ErrPhonesCodesMismatch,
 	ErrTencentSMSNotImplemented.Code:     ErrTencentSMSNotImplemented,
 	ErrUnsupportedSMSProvider.Code:       ErrUnsupportedSMSProvider,
+	ErrUnsupportedWechatProvider.Code:    ErrUnsupportedWechatProvider,
+	ErrWechatNotInitialized.Code:         ErrWechatNotInitialized,
 	TooManyRequests.Code:                 TooManyRequests,
 }
 
@@ -0,0 +1,39 @@
+// Package reqmeta 在 context 中传递请求来源（IP、User-Agent、请求 ID），供审计日志等需要请求信息的服务层使用
+package reqmeta
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ctxKey struct{}
+
+// Meta 请求来源信息
+type Meta struct {
+	IP        string
+	UserAgent string
+	RequestID string
+}
+
+// With 返回携带请求来源信息的 context
+func With(ctx context.Context, meta Meta) context.Context {
+	return context.WithValue(ctx, ctxKey{}, meta)
+}
+
+// From 读取请求来源信息，非 HTTP 请求（定时任务、消费者）返回空值
+func From(ctx context.Context) Meta {
+	if meta, ok := ctx.Value(ctxKey{}).(Meta); ok {
+		return meta
+	}
+	return Meta{}
+}
+
+// TraceID 当前 span 的 trace ID，未开启追踪时为空
+func TraceID(ctx context.Context) string {
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if !spanCtx.HasTraceID() {
+		return ""
+	}
+	return spanCtx.TraceID().String()
+}
@@ -38,16 +38,54 @@ func errorToHTTPStatus(err error) int {
 
 
 	switch def.Code {
-	case "CAPTCHA_RATE_LIMITED", "VERIFICATION_SLIDER_REQUIRED":
+	case "CAPTCHA_RATE_LIMITED", "VERIFICATION_SLIDER_REQUIRED",
+		"PHONE_CHANGE_COOLDOWN", "CONTACT_CONSENT_COOLDOWN",
+		"CONTACT_CONSENT_LIMIT_REACHED":
 		return http.StatusTooManyRequests // 429
 	case "AUTH_CODE_INVALID", "VERIFICATION_CODE_EXPIRED",
 		"VERIFICATION_CODE_INVALID", "VERIFICATION_SLIDER_FAILED",
 		"INVALID_REQUEST", "INVALID_PHONE",
-		"CONTACT_LIMIT_REACHED", "CONTACT_PRIORITY_CONFLICT",
-		"JOURNEY_OVERLAP", "JOURNEY_NOT_MODIFIABLE",
-		"NOTIFY_ACK_INVALID", "QUOTA_CHANNEL_INVALID":
+		"WECHAT_LOGIN_FAILED", "WECHAT_PHONE_INVALID",
+		"CONTACT_LIMIT_REACHED", "CONTACT_PRIORITY_CONFLICT", "CONTACT_VERSION_CONFLICT",
+		"CONTACT_CONSENT_NOT_PENDING", "CONTACT_OPTED_OUT", "CONTACT_IMPORT_INVALID",
+		"JOURNEY_OVERLAP", "JOURNEY_NOT_MODIFIABLE", "JOURNEY_EXTEND_LIMIT",
+		"JOURNEY_CHECKPOINT_INVALID", "JOURNEY_CHECKPOINT_CLOSED", "JOURNEY_LOCATION_INVALID",
+		"JOURNEY_GEOFENCE_INVALID", "HOME_LOCATION_NOT_SET", "JOURNEY_SHARE_INVALID",
+		"JOURNEY_TEMPLATE_INVALID", "JOURNEY_TEMPLATE_LIMIT", "JOURNEY_RECURRENCE_INVALID",
+		"JOURNEY_OCCURRENCE_CLOSED", "JOURNEY_CONTACTS_INVALID", "JOURNEY_ALERT_MESSAGE_TOO_LONG",
+		"NOTIFY_ACK_INVALID", "QUOTA_CHANNEL_INVALID",
+		"SUBSCRIPTION_PLAN_INVALID",
+		"REDEEM_CODE_INVALID", "REDEEM_CODE_EXPIRED",
+		"REDEEM_CODE_EXHAUSTED", "REDEEM_CODE_ALREADY_USED",
+		"WALLET_GROUP_ALREADY_JOINED", "WALLET_GROUP_OWNER_CANNOT_LEAVE",
+		"WALLET_GROUP_MONTH_INVALID", "WALLET_GROUP_MEMBER_INVALID", "WALLET_GROUP_AMOUNT_INVALID",
+		"QUOTA_INSUFFICIENT",
+		"QUOTA_CHANNEL_NOT_IN_PLAN",
+		"ACCOUNT_NOT_PENDING_ERASURE", "ACCOUNT_RESTORE_EXPIRED",
+		"DATA_EXPORT_IN_PROGRESS", "DATA_EXPORT_NOT_READY",
+		"DATA_EXPORT_VERIFY_REQUIRED", "STEP_UP_UNAVAILABLE",
+		"PHONE_ALREADY_REGISTERED", "PHONE_UNCHANGED",
+		"IDENTITY_PROVIDER_INVALID", "IDENTITY_PROOF_INVALID",
+		"IDENTITY_ALREADY_LINKED", "IDENTITY_LINKED_ELSEWHERE",
+		"IDENTITY_LAST_REMAINING", "ACCOUNT_MERGE_SELF",
+		"ACCOUNT_MERGE_CONFLICT", "ACCOUNT_MERGE_BLOCKED",
+		"ACCOUNT_MERGE_PHONE_INVALID", "AUDIT_QUERY_INVALID":
 		return http.StatusBadRequest // 400
-	case "USER_STATUS_INVALID":
+	case "UNAUTHORIZED", "SESSION_REVOKED", "REFRESH_TOKEN_REUSED":
+		return http.StatusUnauthorized // 401
+	case "WALLET_GROUP_NOT_FOUND", "WALLET_GROUP_MEMBER_NOT_FOUND",
+		"WALLET_GROUP_INVITATION_NOT_FOUND",
+		"SESSION_NOT_FOUND", "DATA_EXPORT_NOT_FOUND",
+		"IDENTITY_NOT_FOUND", "CONTACT_CONSENT_NOT_FOUND",
+		"CONTACT_LINK_NOT_FOUND", "JOURNEY_CHECKPOINT_NOT_FOUND",
+		"JOURNEY_SHARE_NOT_FOUND", "JOURNEY_TEMPLATE_NOT_FOUND",
+		"JOURNEY_OCCURRENCE_NOT_FOUND":
+		return http.StatusNotFound // 404
+	case "DATA_EXPORT_LINK_INVALID", "CONTACT_OPT_OUT_LINK_INVALID":
+		return http.StatusForbidden // 403
+	case "DATA_EXPORT_UNAVAILABLE", "CONTACT_CONSENT_EXPIRED":
+		return http.StatusGone // 410
+	case "USER_STATUS_INVALID", "WALLET_GROUP_PERMISSION_DENIED", "STEP_UP_REQUIRED":
 		return http.StatusForbidden // 403
 	default:
 		return http.StatusInternalServerError // 500
@@ -0,0 +1,86 @@
+package sms
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"AreYouOK/config"
+	"AreYouOK/pkg/errors"
+)
+
+// InboundMessage 短信上行消息（用户回复的短信）
+type InboundMessage struct {
+	ReceivedAt time.Time
+	Phone      string
+	Content    string
+}
+
+// aliyunInboundItem 阿里云短信上行 HTTP 批量推送的单条消息
+type aliyunInboundItem struct {
+	PhoneNumber string `json:"phone_number"`
+	SendTime    string `json:"send_time"` // 格式：2006-01-02 15:04:05（北京时间）
+	Content     string `json:"content"`
+	SignName    string `json:"sign_name"`
+	DestCode    string `json:"dest_code"`
+	SequenceID  int64  `json:"sequence_id"`
+}
+
+// mockInboundItem 本地开发时模拟上行消息的请求体
+type mockInboundItem struct {
+	Phone   string `json:"phone"`
+	Content string `json:"content"`
+}
+
+// ParseInbound 按当前短信服务商（SMS_PROVIDER）的推送格式解析上行消息
+// aliyun: 消息数组 [{"phone_number","send_time","content",...}]
+// mock:   单条消息 {"phone","content"}，用于本地开发调试
+func ParseInbound(body []byte) ([]InboundMessage, error) {
+	switch config.Cfg.SMSProvider {
+	case "aliyun":
+		return parseAliyunInbound(body)
+	case "mock":
+		return parseMockInbound(body)
+	default:
+		return nil, fmt.Errorf("%s: %s", errors.ErrUnsupportedSMSProvider.Message, config.Cfg.SMSProvider)
+	}
+}
+
+func parseAliyunInbound(body []byte) ([]InboundMessage, error) {
+	var items []aliyunInboundItem
+	if err := json.Unmarshal(body, &items); err != nil {
+		return nil, fmt.Errorf("failed to parse aliyun inbound message: %w", err)
+	}
+
+	loc, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		loc = time.FixedZone("CST", 8*3600)
+	}
+
+	messages := make([]InboundMessage, 0, len(items))
+	for _, item := range items {
+		receivedAt, err := time.ParseInLocation("2006-01-02 15:04:05", item.SendTime, loc)
+		if err != nil {
+			receivedAt = time.Now()
+		}
+		messages = append(messages, InboundMessage{
+			ReceivedAt: receivedAt,
+			Phone:      item.PhoneNumber,
+			Content:    item.Content,
+		})
+	}
+	return messages, nil
+}
+
+func parseMockInbound(body []byte) ([]InboundMessage, error) {
+	var item mockInboundItem
+	if err := json.Unmarshal(body, &item); err != nil {
+		return nil, fmt.Errorf("failed to parse mock inbound message: %w", err)
+	}
+
+	return []InboundMessage{{
+		ReceivedAt: time.Now(),
+		Phone:      item.Phone,
+		Content:    item.Content,
+	}}, nil
+}
@@ -0,0 +1,32 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"AreYouOK/config"
+)
+
+// SendContactConsentSMS 邀请联系人确认成为紧急联系人
+// phone: 联系人手机号
+// name: 发起邀请的用户昵称
+// link: 确认链接
+
+func SendContactConsentSMS(ctx context.Context, phone, name, link string) (*SendResponse, error) {
+	signName, templateCode, err := config.Cfg.GetSMSTemplateConfig("contact_consent")
+	if err != nil {
+		return nil, err
+	}
+
+	templateParam := map[string]string{
+		"name": name,
+		"link": link,
+	}
+	paramJSON, err := json.Marshal(templateParam)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal template param: %w", err)
+	}
+
+	return SendSingle(ctx, phone, signName, templateCode, string(paramJSON))
+}
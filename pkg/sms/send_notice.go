@@ -0,0 +1,51 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"AreYouOK/config"
+)
+
+// SendPhoneChangedSMS 通知旧号码：账号绑定的手机号已更换
+// phone: 旧手机号
+// newPhoneMasked: 脱敏后的新手机号
+
+func SendPhoneChangedSMS(ctx context.Context, phone, newPhoneMasked string) (*SendResponse, error) {
+	signName, templateCode, err := config.Cfg.GetSMSTemplateConfig("phone_changed")
+	if err != nil {
+		return nil, err
+	}
+
+	templateParam := map[string]string{
+		"phone": newPhoneMasked,
+	}
+	paramJSON, err := json.Marshal(templateParam)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal template param: %w", err)
+	}
+
+	return SendSingle(ctx, phone, signName, templateCode, string(paramJSON))
+}
+
+// SendContactOptedOutSMS 通知用户：紧急联系人已退订短信，需要更换联系人
+// phone: 用户本人手机号
+// name: 联系人称呼
+
+func SendContactOptedOutSMS(ctx context.Context, phone, name string) (*SendResponse, error) {
+	signName, templateCode, err := config.Cfg.GetSMSTemplateConfig("contact_opted_out")
+	if err != nil {
+		return nil, err
+	}
+
+	templateParam := map[string]string{
+		"name": name,
+	}
+	paramJSON, err := json.Marshal(templateParam)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal template param: %w", err)
+	}
+
+	return SendSingle(ctx, phone, signName, templateCode, string(paramJSON))
+}
@@ -18,6 +18,7 @@ const (
 	GeneratorTypeMessage GeneratorType = "message" // 消息队列 ID
 	GeneratorTypeCheckIn GeneratorType = "checkin" // 打卡记录 ID
 	GeneratorTypeContact GeneratorType = "contact" // 联系人 ID
+	GeneratorTypeExport  GeneratorType = "export"  // 数据导出任务 ID
 )
 
 var (
@@ -64,6 +65,7 @@ func Init(machineID, dataCenterID int64) error {
 		GeneratorTypeMessage: baseNodeID + 3, // 偏移 3
 		GeneratorTypeCheckIn: baseNodeID + 4, // 偏移 4
 		GeneratorTypeContact: baseNodeID + 5, // 偏移 5
+		GeneratorTypeExport:  baseNodeID + 6, // 偏移 6
 	}
 
 	for genType, nodeID := range nodeIDs {
@@ -1,6 +1,8 @@
 package token
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"time"
 
@@ -13,6 +15,9 @@ import (
 
 const (
 	IdentityKey = "uid"
+
+	// ContactAudience 紧急联系人会话 token 的 aud，与用户 token 区分，用户鉴权中间件会拒绝带此 aud 的 token
+	ContactAudience = "contact"
 )
 
 var (
@@ -43,51 +48,86 @@ func GetGenerator() *jwt.HertzJWTMiddleware {
 	return sharedGenerator
 }
 
+// TokenPair 一次签发的 access token 与 refresh token
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	RefreshJTI   string // refresh token 的 jti，会话用它判断 refresh token 是否被重复使用
+	ExpiresIn    int
+}
+
+// RefreshClaims refresh token 中的声明
+type RefreshClaims struct {
+	UserID    string
+	SessionID string // 旧版 token 没有 sid，为空
+	JTI       string
+}
+
 // GenerateTokenPair 生成 access token 和 refresh token
-func GenerateTokenPair(userID string) (accessToken, refreshToken string, expiresIn int, err error) {
+// 两个 token 都携带会话 ID（sid）和唯一的 jti，用于登出拉黑与刷新轮换
+func GenerateTokenPair(userID string, sessionID string) (*TokenPair, error) {
 	if sharedGenerator == nil {
-		return "", "", 0, errors.ErrTokenGeneratorNotInitialized
+		return nil, errors.ErrTokenGeneratorNotInitialized
 	}
 
 	now := time.Now()
 	expiresAt := now.Add(time.Duration(config.Cfg.JWTExpireMinutes) * time.Minute)
 
+	accessJTI, err := newJTI()
+	if err != nil {
+		return nil, err
+	}
+
 	accessClaims := jwtv5.MapClaims{
 		IdentityKey: userID,
+		"sid":       sessionID,
+		"jti":       accessJTI,
 		"iat":       now.Unix(),
 		"exp":       expiresAt.Unix(),
 	}
 
 	accessTokenObj := jwtv5.NewWithClaims(jwtv5.SigningMethodHS256, accessClaims)
-	accessToken, err = accessTokenObj.SignedString([]byte(config.Cfg.JWTSecret))
+	accessToken, err := accessTokenObj.SignedString([]byte(config.Cfg.JWTSecret))
 	if err != nil {
-		return "", "", 0, fmt.Errorf("failed to generate access token: %w", err)
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
 
-	expiresIn = int(time.Until(expiresAt).Seconds())
+	expiresIn := int(time.Until(expiresAt).Seconds())
 	if expiresIn < 0 {
 		expiresIn = 0
 	}
 
+	refreshJTI, err := newJTI()
+	if err != nil {
+		return nil, err
+	}
+
 	// 生成 refresh token
 	refreshClaims := jwtv5.MapClaims{
 		IdentityKey: userID,
+		"sid":       sessionID,
+		"jti":       refreshJTI,
 		"iat":       now.Unix(),
 		"type":      "refresh",
 		"exp":       now.Add(time.Duration(config.Cfg.JWTRefreshDays) * 24 * time.Hour).Unix(),
 	}
 
 	refreshTokenObj := jwtv5.NewWithClaims(jwtv5.SigningMethodHS256, refreshClaims)
-	refreshToken, err = refreshTokenObj.SignedString([]byte(config.Cfg.JWTSecret))
+	refreshToken, err := refreshTokenObj.SignedString([]byte(config.Cfg.JWTSecret))
 	if err != nil {
-		return "", "", 0, fmt.Errorf("failed to generate refresh token: %w", err)
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
 	}
 
-	return accessToken, refreshToken, expiresIn, nil
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		RefreshJTI:   refreshJTI,
+		ExpiresIn:    expiresIn,
+	}, nil
 }
 
-// ValidateRefreshToken 验证 refresh token 并返回用户 ID
-func ValidateRefreshToken(tokenString string) (userID string, err error) {
+// ValidateRefreshToken 验证 refresh token 并返回其中的声明
+func ValidateRefreshToken(tokenString string) (*RefreshClaims, error) {
 	token, err := jwtv5.ParseWithClaims(tokenString, jwtv5.MapClaims{}, func(token *jwtv5.Token) (interface{}, error) {
 		if token.Method != jwtv5.SigningMethodHS256 {
 			return nil, fmt.Errorf("%w: %v, expected HS256", errors.ErrUnexpectedSigningMethod, token.Header["alg"])
@@ -96,21 +136,21 @@ func ValidateRefreshToken(tokenString string) (userID string, err error) {
 	})
 
 	if err != nil {
-		return "", fmt.Errorf("failed to parse token: %w", err)
+		return nil, fmt.Errorf("failed to parse token: %w", err)
 	}
 
 	if !token.Valid {
-		return "", errors.ErrInvalidToken
+		return nil, errors.ErrInvalidToken
 	}
 
 	claims, ok := token.Claims.(jwtv5.MapClaims)
 	if !ok {
-		return "", errors.ErrInvalidTokenClaims
+		return nil, errors.ErrInvalidTokenClaims
 	}
 
 	tokenType, ok := claims["type"].(string)
 	if !ok || tokenType != "refresh" {
-		return "", errors.ErrInvalidTokenType
+		return nil, errors.ErrInvalidTokenType
 	}
 
 	uid, ok := claims[IdentityKey].(string)
@@ -118,9 +158,119 @@ func ValidateRefreshToken(tokenString string) (userID string, err error) {
 		if uidFloat, ok := claims[IdentityKey].(float64); ok {
 			uid = fmt.Sprintf("%.0f", uidFloat)
 		} else {
-			return "", errors.ErrUserIDNotFound
+			return nil, errors.ErrUserIDNotFound
 		}
 	}
 
-	return uid, nil
+	sid, _ := claims["sid"].(string)
+	jti, _ := claims["jti"].(string)
+
+	return &RefreshClaims{
+		UserID:    uid,
+		SessionID: sid,
+		JTI:       jti,
+	}, nil
+}
+
+// ContactClaims 紧急联系人会话 token 中的声明
+type ContactClaims struct {
+	ExpiresAt   time.Time
+	PhoneCipher string // 联系人手机号密文（base64），不在 token 中暴露明文或哈希
+	JTI         string
+}
+
+// GenerateContactToken 为通过验证码验证的联系人签发会话 token
+// 联系人会话只有 access token，不支持刷新，过期后重新验证手机号
+func GenerateContactToken(phoneCipher string) (string, int, error) {
+	now := time.Now()
+	expiresAt := now.Add(time.Duration(config.Cfg.ContactSessionMinutes) * time.Minute)
+
+	jti, err := newJTI()
+	if err != nil {
+		return "", 0, err
+	}
+
+	claims := jwtv5.MapClaims{
+		"aud": ContactAudience,
+		"pc":  phoneCipher,
+		"jti": jti,
+		"iat": now.Unix(),
+		"exp": expiresAt.Unix(),
+	}
+
+	tokenObj := jwtv5.NewWithClaims(jwtv5.SigningMethodHS256, claims)
+	signed, err := tokenObj.SignedString([]byte(config.Cfg.JWTSecret))
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to generate contact token: %w", err)
+	}
+
+	return signed, int(time.Until(expiresAt).Seconds()), nil
+}
+
+// ValidateContactToken 验证紧急联系人会话 token，用户 token 的 aud 不匹配会被拒绝
+func ValidateContactToken(tokenString string) (*ContactClaims, error) {
+	token, err := jwtv5.ParseWithClaims(tokenString, jwtv5.MapClaims{}, func(token *jwtv5.Token) (interface{}, error) {
+		if token.Method != jwtv5.SigningMethodHS256 {
+			return nil, fmt.Errorf("%w: %v, expected HS256", errors.ErrUnexpectedSigningMethod, token.Header["alg"])
+		}
+		return []byte(config.Cfg.JWTSecret), nil
+	}, jwtv5.WithAudience(ContactAudience), jwtv5.WithExpirationRequired())
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse token: %w", err)
+	}
+
+	if !token.Valid {
+		return nil, errors.ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(jwtv5.MapClaims)
+	if !ok {
+		return nil, errors.ErrInvalidTokenClaims
+	}
+
+	phoneCipher, _ := claims["pc"].(string)
+	if phoneCipher == "" {
+		return nil, errors.ErrInvalidTokenClaims
+	}
+	jti, _ := claims["jti"].(string)
+
+	result := &ContactClaims{
+		PhoneCipher: phoneCipher,
+		JTI:         jti,
+	}
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+		result.ExpiresAt = exp.Time
+	}
+	return result, nil
+}
+
+// IsContactToken 声明中的 aud 是否为紧急联系人会话
+func IsContactToken(claims map[string]interface{}) bool {
+	switch aud := claims["aud"].(type) {
+	case string:
+		return aud == ContactAudience
+	case []interface{}:
+		for _, v := range aud {
+			if s, ok := v.(string); ok && s == ContactAudience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsRefreshToken 声明中的 type 是否为 refresh token，refresh token 只能用于换取新的 token
+func IsRefreshToken(claims map[string]interface{}) bool {
+	tokenType, _ := claims["type"].(string)
+	return tokenType == "refresh"
+}
+
+// newJTI 生成随机的 token ID
+func newJTI() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
 }
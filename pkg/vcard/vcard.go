@@ -0,0 +1,348 @@
+package vcard
+
+import (
+	"bufio"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Card 解析后的单张名片，只保留紧急联系人需要的字段
+type Card struct {
+	FormattedName string            // FN
+	Name          string            // N 中的姓与名拼接，FN 为空时使用
+	Role          string            // ROLE
+	Phones        []Phone           // TEL，保持文件中的顺序
+	Extensions    map[string]string // X- 开头的扩展属性（属性名大写）
+}
+
+// Phone 名片中的电话号码
+type Phone struct {
+	Number    string   // 原始号码（tel: URI 已去掉前缀），未做规范化
+	Types     []string // TYPE 参数（小写），如 cell、home、voice
+	Preferred bool     // 3.0 的 TYPE=pref 或 4.0 的 PREF=1
+}
+
+// IsCell 号码是否标记为手机
+func (p Phone) IsCell() bool {
+	for _, t := range p.Types {
+		if t == "cell" {
+			return true
+		}
+	}
+	return false
+}
+
+// Preferred 名片中是否有标记为首选的号码
+func (c Card) Preferred() bool {
+	for _, phone := range c.Phones {
+		if phone.Preferred {
+			return true
+		}
+	}
+	return false
+}
+
+// DisplayName 名片显示名称，优先使用 FN
+func (c Card) DisplayName() string {
+	if c.FormattedName != "" {
+		return c.FormattedName
+	}
+	return c.Name
+}
+
+// Parse 解析 vCard 3.0 / 4.0 文本，可以包含多张名片
+// 支持折行、属性分组前缀（item1.TEL）与转义字符；不支持 2.1 的 quoted-printable 编码
+func Parse(data string) ([]Card, error) {
+	lines := unfold(data)
+
+	var (
+		cards   []Card
+		current *Card
+	)
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		name, params, value, ok := splitProperty(line)
+		if !ok {
+			return nil, fmt.Errorf("invalid vcard line %d", i+1)
+		}
+
+		switch name {
+		case "BEGIN":
+			if !strings.EqualFold(value, "VCARD") {
+				continue
+			}
+			if current != nil {
+				return nil, fmt.Errorf("nested BEGIN:VCARD at line %d", i+1)
+			}
+			current = &Card{Extensions: map[string]string{}}
+			continue
+		case "END":
+			if !strings.EqualFold(value, "VCARD") {
+				continue
+			}
+			if current == nil {
+				return nil, fmt.Errorf("unexpected END:VCARD at line %d", i+1)
+			}
+			cards = append(cards, *current)
+			current = nil
+			continue
+		}
+
+		if current == nil {
+			return nil, fmt.Errorf("property outside BEGIN:VCARD at line %d", i+1)
+		}
+
+		switch name {
+		case "FN":
+			current.FormattedName = unescape(value)
+		case "N":
+			current.Name = parseName(value)
+		case "ROLE":
+			current.Role = unescape(value)
+		case "TEL":
+			current.Phones = append(current.Phones, parsePhone(params, value))
+		default:
+			if strings.HasPrefix(name, "X-") {
+				current.Extensions[name] = unescape(value)
+			}
+		}
+	}
+
+	if current != nil {
+		return nil, fmt.Errorf("missing END:VCARD")
+	}
+	if len(cards) == 0 {
+		return nil, fmt.Errorf("no vcard found")
+	}
+	return cards, nil
+}
+
+// Encode 按 vCard 3.0 输出名片，TEL 标记为 cell，首选号码带 TYPE=pref
+func Encode(cards []Card) string {
+	var b strings.Builder
+	for _, card := range cards {
+		b.WriteString("BEGIN:VCARD\r\n")
+		b.WriteString("VERSION:3.0\r\n")
+		writeLine(&b, "FN:"+escape(card.DisplayName()))
+		writeLine(&b, "N:"+escape(card.DisplayName())+";;;;")
+		if card.Role != "" {
+			writeLine(&b, "ROLE:"+escape(card.Role))
+		}
+		for _, phone := range card.Phones {
+			types := "CELL"
+			if phone.Preferred {
+				types += ",PREF"
+			}
+			writeLine(&b, "TEL;TYPE="+types+":"+phone.Number)
+		}
+		for _, key := range sortedKeys(card.Extensions) {
+			writeLine(&b, key+":"+escape(card.Extensions[key]))
+		}
+		b.WriteString("END:VCARD\r\n")
+	}
+	return b.String()
+}
+
+// unfold 拆分为逻辑行：以空格或制表符开头的行是上一行的续行
+func unfold(data string) []string {
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// splitProperty 拆分 "group.NAME;PARAM=a,b;PARAM2=c:value"，属性名与参数名转为大写
+func splitProperty(line string) (string, map[string][]string, string, bool) {
+	colon := indexUnquoted(line, ':')
+	if colon <= 0 {
+		return "", nil, "", false
+	}
+
+	head, value := line[:colon], line[colon+1:]
+	parts := strings.Split(head, ";")
+
+	name := strings.ToUpper(parts[0])
+	if dot := strings.LastIndex(name, "."); dot >= 0 {
+		name = name[dot+1:]
+	}
+	if name == "" {
+		return "", nil, "", false
+	}
+
+	params := make(map[string][]string)
+	for _, part := range parts[1:] {
+		key, val, found := strings.Cut(part, "=")
+		if !found {
+			// 3.0 允许省略 TYPE=，如 TEL;CELL:...
+			key, val = "TYPE", part
+		}
+		key = strings.ToUpper(key)
+		for _, v := range strings.Split(strings.Trim(val, `"`), ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				params[key] = append(params[key], v)
+			}
+		}
+	}
+	return name, params, value, true
+}
+
+// indexUnquoted 查找不在双引号内的字符，参数值可能用引号包含冒号
+func indexUnquoted(s string, target byte) int {
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			quoted = !quoted
+		case target:
+			if !quoted {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func parsePhone(params map[string][]string, value string) Phone {
+	phone := Phone{Number: strings.TrimSpace(value)}
+	if len(phone.Number) > 4 && strings.EqualFold(phone.Number[:4], "tel:") {
+		phone.Number = phone.Number[4:]
+	}
+	// tel URI 可能带 ;ext= 等参数
+	if semi := strings.Index(phone.Number, ";"); semi >= 0 {
+		phone.Number = phone.Number[:semi]
+	}
+
+	for _, t := range params["TYPE"] {
+		t = strings.ToLower(t)
+		if t == "pref" {
+			phone.Preferred = true
+			continue
+		}
+		phone.Types = append(phone.Types, t)
+	}
+	for _, p := range params["PREF"] {
+		if p == "1" {
+			phone.Preferred = true
+		}
+	}
+	return phone
+}
+
+// parseName N 属性为 姓;名;中间名;前缀;后缀，中文姓名按 姓+名 拼接，其他按空格分隔
+func parseName(value string) string {
+	fields := splitUnescaped(value, ';')
+	var family, given string
+	if len(fields) > 0 {
+		family = unescape(fields[0])
+	}
+	if len(fields) > 1 {
+		given = unescape(fields[1])
+	}
+	if family == "" || given == "" {
+		return family + given
+	}
+	if isASCII(family) && isASCII(given) {
+		return given + " " + family
+	}
+	return family + given
+}
+
+func splitUnescaped(s string, sep byte) []string {
+	var (
+		fields []string
+		start  int
+	)
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if s[i] == sep {
+			fields = append(fields, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(fields, s[start:])
+}
+
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return strings.TrimSpace(s)
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func escape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\n", `\n`, "\r", "")
+	return r.Replace(s)
+}
+
+// writeLine 按 RFC 6350 折行，每行不超过 75 字节且不截断 UTF-8 字符
+func writeLine(b *strings.Builder, line string) {
+	const limit = 75
+	first := true
+	for len(line) > 0 {
+		width := limit
+		if !first {
+			width = limit - 1
+			b.WriteByte(' ')
+		}
+		if len(line) <= width {
+			b.WriteString(line)
+			break
+		}
+		cut := width
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n")
+		line = line[cut:]
+		first = false
+	}
+	b.WriteString("\r\n")
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,77 @@
+package wechat
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"AreYouOK/config"
+	"AreYouOK/pkg/errors"
+	"AreYouOK/pkg/logger"
+)
+
+// Session code2session 换取到的登录态
+// session_key 只用于本次请求解密手机号，不落库
+type Session struct {
+	OpenID     string
+	UnionID    string // 小程序绑定了微信开放平台时才返回
+	SessionKey string // base64 编码
+}
+
+// Client 微信小程序登录客户端接口
+type Client interface {
+	// Code2Session 使用 wx.login 返回的 code 换取 openid 与 session_key
+	Code2Session(ctx context.Context, code string) (*Session, error)
+}
+
+var (
+	wechatClient Client
+	wechatOnce   sync.Once
+	wechatErr    error
+)
+
+func Init() error {
+	wechatOnce.Do(func() {
+		cfg := config.Cfg
+
+		switch cfg.WechatProvider {
+		case "wechat":
+			wechatClient, wechatErr = NewMiniappClient()
+		case "mock":
+			wechatClient = NewMockClient()
+		default:
+			wechatErr = fmt.Errorf("%s: %s", errors.ErrUnsupportedWechatProvider.Message, cfg.WechatProvider)
+		}
+
+		if wechatErr != nil {
+			logger.Logger.Error("Failed to initialize WeChat client", zap.Error(wechatErr))
+			return
+		}
+
+		logger.Logger.Info("WeChat client initialized successfully",
+			zap.String("provider", cfg.WechatProvider),
+		)
+	})
+
+	return wechatErr
+}
+
+func GetClient() (Client, error) {
+	if wechatClient == nil {
+		if wechatErr != nil {
+			return nil, wechatErr
+		}
+		return nil, errors.ErrWechatNotInitialized
+	}
+	return wechatClient, nil
+}
+
+func Code2Session(ctx context.Context, code string) (*Session, error) {
+	client, err := GetClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.Code2Session(ctx, code)
+}
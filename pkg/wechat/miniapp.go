@@ -0,0 +1,92 @@
+package wechat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"AreYouOK/config"
+)
+
+const code2SessionURL = "https://api.weixin.qq.com/sns/jscode2session"
+
+// MiniappClient 调用微信 auth.code2Session 接口
+// 参考：https://developers.weixin.qq.com/miniprogram/dev/OpenApiDoc/user-login/code2Session.html
+type MiniappClient struct {
+	httpClient *http.Client
+	appID      string
+	appSecret  string
+}
+
+func NewMiniappClient() (*MiniappClient, error) {
+	if config.Cfg.WechatAppID == "" {
+		return nil, errors.New("WECHAT_APP_ID is not configured")
+	}
+	if config.Cfg.WechatAppSecret == "" {
+		return nil, errors.New("WECHAT_APP_SECRET is not configured")
+	}
+
+	return &MiniappClient{
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		appID:      config.Cfg.WechatAppID,
+		appSecret:  config.Cfg.WechatAppSecret,
+	}, nil
+}
+
+func (c *MiniappClient) Code2Session(ctx context.Context, code string) (*Session, error) {
+	if code == "" {
+		return nil, errors.New("code is required")
+	}
+
+	params := url.Values{}
+	params.Set("appid", c.appID)
+	params.Set("secret", c.appSecret)
+	params.Set("js_code", code)
+	params.Set("grant_type", "authorization_code")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, code2SessionURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build code2session request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call code2session: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read code2session response: %w", err)
+	}
+
+	var parsed struct {
+		OpenID     string `json:"openid"`
+		UnionID    string `json:"unionid"`
+		SessionKey string `json:"session_key"`
+		ErrCode    int    `json:"errcode"`
+		ErrMsg     string `json:"errmsg"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse code2session response: %w", err)
+	}
+
+	// 40029 code 无效，40163 code 已被使用，45011 频率限制
+	if parsed.ErrCode != 0 {
+		return nil, fmt.Errorf("code2session error: errcode=%d, errmsg=%s", parsed.ErrCode, parsed.ErrMsg)
+	}
+	if parsed.OpenID == "" || parsed.SessionKey == "" {
+		return nil, errors.New("code2session returned empty openid or session_key")
+	}
+
+	return &Session{
+		OpenID:     parsed.OpenID,
+		UnionID:    parsed.UnionID,
+		SessionKey: parsed.SessionKey,
+	}, nil
+}
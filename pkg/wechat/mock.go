@@ -0,0 +1,93 @@
+package wechat
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"sync"
+)
+
+// MockSessionKey mock 客户端固定返回的 session_key，配合 EncryptMockPhone 在本地构造 getPhoneNumber 数据
+var MockSessionKey = base64.StdEncoding.EncodeToString([]byte("areyouok-mock-sk"))
+
+// MockClient 本地开发用的 code2session mock，实现 Client 接口
+// 未预置的 code 返回 openid = "mock_" + code，同一个 code 始终对应同一个用户
+type MockClient struct {
+	mu    sync.Mutex
+	Calls []string
+
+	// Sessions 预置 code 对应的登录态
+	Sessions map[string]*Session
+
+	// FailNext 置为 true 时，下一次调用返回 mock 错误并自动复位
+	FailNext bool
+}
+
+func NewMockClient() *MockClient {
+	return &MockClient{
+		Calls:    make([]string, 0),
+		Sessions: make(map[string]*Session),
+	}
+}
+
+func (m *MockClient) Code2Session(ctx context.Context, code string) (*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, code)
+
+	if m.FailNext {
+		m.FailNext = false
+		return nil, errors.New("mock code2session failure")
+	}
+	if code == "" {
+		return nil, errors.New("code is required")
+	}
+
+	if session, ok := m.Sessions[code]; ok {
+		return session, nil
+	}
+
+	return &Session{
+		OpenID:     "mock_" + code,
+		SessionKey: MockSessionKey,
+	}, nil
+}
+
+// EncryptMockPhone 按微信的格式加密手机号，返回 encryptedData 与 iv（均为 base64），用于本地联调 mock 登录
+func EncryptMockPhone(phone string, appID string) (string, string, error) {
+	var info PhoneInfo
+	info.PhoneNumber = phone
+	info.PurePhoneNumber = phone
+	info.CountryCode = "86"
+	info.Watermark.AppID = appID
+
+	plaintext, err := json.Marshal(info)
+	if err != nil {
+		return "", "", err
+	}
+
+	key, _ := base64.StdEncoding.DecodeString(MockSessionKey)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", "", err
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return "", "", err
+	}
+
+	pad := aes.BlockSize - len(plaintext)%aes.BlockSize
+	plaintext = append(plaintext, bytes.Repeat([]byte{byte(pad)}, pad)...)
+
+	ciphertext := make([]byte, len(plaintext))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, plaintext)
+
+	return base64.StdEncoding.EncodeToString(ciphertext), base64.StdEncoding.EncodeToString(iv), nil
+}
@@ -0,0 +1,86 @@
+package wechat
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// PhoneInfo getPhoneNumber 加密数据解密后的手机号信息
+// 参考：https://developers.weixin.qq.com/miniprogram/dev/framework/open-ability/signature.html
+type PhoneInfo struct {
+	PhoneNumber     string `json:"phoneNumber"`     // 带区号的手机号（境外手机号会有区号）
+	PurePhoneNumber string `json:"purePhoneNumber"` // 不带区号的手机号
+	CountryCode     string `json:"countryCode"`
+	Watermark       struct {
+		AppID     string `json:"appid"`
+		Timestamp int64  `json:"timestamp"`
+	} `json:"watermark"`
+}
+
+// DecryptPhone 使用 session_key 解密 getPhoneNumber 返回的 encryptedData（AES-128-CBC，PKCS7 填充）
+// appID 不为空时校验数据水印，防止使用其他小程序的加密数据
+func DecryptPhone(sessionKey, encryptedData, iv, appID string) (*PhoneInfo, error) {
+	key, err := base64.StdEncoding.DecodeString(sessionKey)
+	if err != nil || len(key) != 16 {
+		return nil, errors.New("invalid session_key")
+	}
+	ivBytes, err := base64.StdEncoding.DecodeString(iv)
+	if err != nil || len(ivBytes) != aes.BlockSize {
+		return nil, errors.New("invalid iv")
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(encryptedData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode encrypted data: %w", err)
+	}
+	if len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, errors.New("invalid encrypted data length")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AES cipher: %w", err)
+	}
+
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, ivBytes).CryptBlocks(plaintext, ciphertext)
+
+	plaintext, err = unpadPKCS7(plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	var info PhoneInfo
+	if err := json.Unmarshal(plaintext, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse decrypted phone data: %w", err)
+	}
+	if appID != "" && info.Watermark.AppID != appID {
+		return nil, errors.New("phone data watermark appid mismatch")
+	}
+	if info.PurePhoneNumber == "" {
+		return nil, errors.New("phone number not found in decrypted data")
+	}
+
+	return &info, nil
+}
+
+func unpadPKCS7(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, errors.New("invalid padding")
+	}
+
+	n := int(data[len(data)-1])
+	if n == 0 || n > aes.BlockSize || n > len(data) {
+		return nil, errors.New("invalid padding")
+	}
+	for _, b := range data[len(data)-n:] {
+		if int(b) != n {
+			return nil, errors.New("invalid padding")
+		}
+	}
+
+	return data[:len(data)-n], nil
+}
@@ -13,12 +13,13 @@ CREATE TABLE users (
   id BIGSERIAL PRIMARY KEY, -- 关联的主键 id
   avatar_url VARCHAR(255), -- 头像 url
   public_id BIGINT NOT NULL,
-  alipay_open_id VARCHAR(64) NOT NULL, -- aliyun 的 openid, 做主键性能也很差
+  alipay_open_id VARCHAR(64) NOT NULL DEFAULT '', -- aliyun 的 openid, 做主键性能也很差；支付宝身份的冗余字段，登录身份见 user_identities
   nickname VARCHAR(64) NOT NULL DEFAULT '', -- 默认支付宝的 nickname
   phone_cipher BYTEA, -- 手机号密文
   phone_hash CHAR(64), 
+  phone_hash_version SMALLINT NOT NULL DEFAULT 0, -- phone_hash 的哈希版本：0 为旧的加盐 sha256，其余为 HMAC-SHA256
   status VARCHAR(16) NOT NULL DEFAULT 'waitlisted',
-  emergency_contacts JSONB DEFAULT '[]'::jsonb, -- 紧急联系人数组，最多 3 位，按 priority 排序
+  emergency_contacts JSONB DEFAULT '[]'::jsonb, -- 已废弃：紧急联系人已迁移到 emergency_contacts 表，迁移时回填后清空
   
   -- 用户自定义设置部分
   timezone VARCHAR(64) NOT NULL DEFAULT 'Asia/Shanghai',
@@ -27,30 +28,241 @@ CREATE TABLE users (
   daily_check_in_grace_until TIME NOT NULL DEFAULT TIME '21:00',
   daily_check_in_remind_at TIME NOT NULL DEFAULT TIME '20:00',
   journey_auto_notify BOOLEAN NOT NULL DEFAULT TRUE,
+  home_latitude DOUBLE PRECISION,                    -- 家的位置（地理围栏中心），行程可以以家为目的地
+  home_longitude DOUBLE PRECISION,
+  home_radius INTEGER NOT NULL DEFAULT 0,            -- 围栏半径（米），0 表示未设置
   
   created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
   updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
   deleted_at TIMESTAMPTZ
 );
 CREATE UNIQUE INDEX users_public_id_key ON users(public_id) WHERE deleted_at IS NULL;
-CREATE UNIQUE INDEX users_alipay_open_id_key ON users(alipay_open_id) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX users_alipay_open_id_key ON users(alipay_open_id) WHERE deleted_at IS NULL AND alipay_open_id <> ''; -- 仅通过微信登录的用户为空
 CREATE UNIQUE INDEX users_phone_hash_key ON users(phone_hash) WHERE deleted_at IS NULL;
 CREATE INDEX idx_users_status ON users(status);
 CREATE INDEX idx_users_emergency_contacts ON users USING GIN (emergency_contacts);
 
--- JSONB 格式示例（emergency_contacts）：
+-- 旧版 JSONB 格式示例（emergency_contacts），仅用于迁移回填：
 -- [
 --   {
 --     "display_name": "妈妈",
 --     "relationship": "Mother",
 --     "phone_cipher_base64": "base64-encoded-cipher", -- phone_cipher 的 base64 编码（BYTEA 转 base64）
 --     "phone_hash": "abc123...",
+--     "phone_hash_version": 1, -- phone_hash 的哈希版本
 --     "priority": 1,
 --     "created_at": "2025-03-01T10:00:00+08:00"
 --   }
 -- ]
 -- 约束：最多 3 位，priority 唯一（1-3），phone_hash 唯一， 这里只需要在创建时做限制即可
 
+-- 紧急联系人：取代 users.emergency_contacts，数量上限由套餐决定（免费版 3 位），按 priority 排序。
+-- 接口仍按 priority 定位联系人；contact_id 为 snowflake，对外暴露。
+-- 删除与批量替换均为软删除，已删除的记录保留作为历史；同一用户未删除的联系人 priority 唯一。
+-- version 每次修改加一，更新时作为乐观锁，并发修改只有一个请求成功；密钥轮换的重加密、重新哈希不改变版本号。
+-- 同意流程：新增联系人或更换号码后发送带 token 的确认短信，联系人确认后才会收到告警。
+-- status 枚举值：pending（等待确认）、confirmed（已确认）、declined（已拒绝）、expired（链接过期未回应，由 scheduler 标记）
+-- 链接有效期内联系人可以改变主意；同一号码的发送次数（consent_sends）与间隔受限。用户的第一位联系人确认后用户状态变为 active。
+-- 号码退订后（见 contact_opt_outs）所有用户下该号码的联系人变为 opted_out，不再收到任何短信。
+CREATE TABLE emergency_contacts (
+  id BIGSERIAL PRIMARY KEY,
+  contact_id BIGINT NOT NULL, -- snowflake
+  user_id BIGINT NOT NULL REFERENCES users(id),
+  priority INTEGER NOT NULL,
+  display_name VARCHAR(64) NOT NULL,
+  relationship VARCHAR(32) NOT NULL DEFAULT '',
+  phone_cipher BYTEA NOT NULL, -- 手机号密文
+  phone_hash CHAR(64) NOT NULL,
+  phone_hash_version SMALLINT NOT NULL DEFAULT 0, -- phone_hash 的哈希版本
+  version INTEGER NOT NULL DEFAULT 1,
+  status VARCHAR(16) NOT NULL DEFAULT 'pending',
+  consent_token_hash CHAR(64), -- 确认链接 token 的哈希，明文只出现在短信中
+  consent_sent_at TIMESTAMPTZ, -- 最近一次发送确认短信的时间
+  consent_expires_at TIMESTAMPTZ, -- 确认链接过期时间
+  consent_sends INTEGER NOT NULL DEFAULT 0, -- 当前号码已发送确认短信的次数，更换号码后重新计数
+  responded_at TIMESTAMPTZ, -- 联系人确认或拒绝的时间
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  deleted_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX emergency_contacts_contact_id_key ON emergency_contacts(contact_id);
+CREATE UNIQUE INDEX emergency_contacts_consent_token_key ON emergency_contacts(consent_token_hash) WHERE deleted_at IS NULL;
+CREATE INDEX idx_emergency_contacts_consent_expires ON emergency_contacts(consent_expires_at);
+CREATE UNIQUE INDEX idx_emergency_contacts_user_priority ON emergency_contacts(user_id, priority) WHERE deleted_at IS NULL;
+CREATE INDEX idx_emergency_contacts_phone_hash ON emergency_contacts(phone_hash);
+
+-- 全局免打扰名单：联系人回复 TD / 退订 等关键词（短信上行回调）或点击告警短信中的签名退订链接后写入。
+-- 按手机号哈希匹配，名单中的号码不会再收到告警与确认短信，也不能再被添加为紧急联系人；受影响的用户会收到短信提醒更换联系人。
+-- source 枚举值：sms_reply（短信回复）、link（退订链接）。保存密文以便哈希版本轮换时重算 phone_hash。
+CREATE TABLE contact_opt_outs (
+  id BIGSERIAL PRIMARY KEY,
+  phone_cipher BYTEA NOT NULL, -- 手机号密文
+  phone_hash CHAR(64) NOT NULL,
+  phone_hash_version SMALLINT NOT NULL DEFAULT 0, -- phone_hash 的哈希版本
+  source VARCHAR(16) NOT NULL,
+  keyword VARCHAR(32) NOT NULL DEFAULT '', -- 短信退订时回复的内容
+  opted_out_at TIMESTAMPTZ NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  deleted_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX contact_opt_outs_phone_hash_key ON contact_opt_outs(phone_hash) WHERE deleted_at IS NULL;
+
+-- 登录身份：一个账号可以绑定多个平台的身份，(provider, subject) 全局唯一。
+-- provider 枚举值：alipay（subject 为支付宝 user_id / open_id）、wechat（subject 为小程序 openid）
+-- 小程序登录时平台返回的手机号与已有账号匹配、且该账号未绑定同平台身份时，自动关联到该账号
+CREATE TABLE user_identities (
+  id BIGSERIAL PRIMARY KEY,
+  user_id BIGINT NOT NULL REFERENCES users(id),
+  provider VARCHAR(16) NOT NULL,
+  subject VARCHAR(64) NOT NULL,
+  union_id VARCHAR(64), -- 微信开放平台 unionid
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  deleted_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX user_identities_provider_subject_key ON user_identities(provider, subject);
+CREATE INDEX idx_user_identities_user ON user_identities(user_id);
+CREATE INDEX idx_user_identities_union ON user_identities(union_id);
+
+-- 账号合并记录：同一个人的两个账号（例如分别通过支付宝、微信注册）合并为一个。
+-- 当前登录账号为保留账号（target），被合并账号（source）需提供其登录身份的授权码，并验证任一账号的手机号。
+-- source 的紧急联系人、行程、打卡记录、个人钱包余额、订阅与登录身份转移到 target，随后清空手机号并软删除。
+-- 本表作为审计记录保留，summary 记录各项数据转移的数量。
+CREATE TABLE account_merges (
+  id BIGSERIAL PRIMARY KEY,
+  source_user_id BIGINT NOT NULL REFERENCES users(id),
+  source_public_id BIGINT NOT NULL,
+  target_user_id BIGINT NOT NULL REFERENCES users(id),
+  target_public_id BIGINT NOT NULL,
+  provider VARCHAR(16) NOT NULL, -- 证明 source 归属所用的登录身份
+  session_id BIGINT NOT NULL DEFAULT 0, -- 发起合并的会话
+  ip VARCHAR(64) NOT NULL DEFAULT '',
+  summary JSONB NOT NULL DEFAULT '{}'::jsonb,
+  merged_at TIMESTAMPTZ NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  deleted_at TIMESTAMPTZ
+);
+CREATE INDEX idx_account_merges_source ON account_merges(source_user_id);
+CREATE INDEX idx_account_merges_target ON account_merges(target_user_id);
+
+-- 审计事件：登录、刷新 token、联系人与设置变更、注销、更换手机号、登录身份与账号合并等敏感操作。
+-- 与所描述的变更在同一个事务中写入，只追加不修改（触发器拒绝 UPDATE / DELETE），清除个人数据时作为安全记录保留。
+-- diff 为脱敏后的变更内容：不记录手机号、open_id 与联系人姓名，联系人只记录优先级与关系。
+-- 用户通过 GET /v1/users/me/activity 查看本人记录，运维通过 GET /v1/admin/audit-events 查询。
+-- actor_type 枚举值：user（用户本人）、system（定时任务等后台流程）
+CREATE TABLE audit_events (
+  id BIGSERIAL PRIMARY KEY,
+  user_id BIGINT NOT NULL REFERENCES users(id), -- 事件所属账号
+  actor_type VARCHAR(16) NOT NULL DEFAULT 'user',
+  actor_id BIGINT NOT NULL DEFAULT 0, -- 用户本人操作时等于 user_id，系统操作为 0
+  action VARCHAR(48) NOT NULL, -- 例如 auth.login、contact.update、account.erase_request
+  target_type VARCHAR(32) NOT NULL DEFAULT '', -- 被操作对象类型，例如 session、contact、identity
+  target_id VARCHAR(64) NOT NULL DEFAULT '',
+  session_id BIGINT NOT NULL DEFAULT 0,
+  ip VARCHAR(64) NOT NULL DEFAULT '',
+  device VARCHAR(255) NOT NULL DEFAULT '', -- 登录时为客户端上报的设备信息，其余为 User-Agent
+  trace_id VARCHAR(32) NOT NULL DEFAULT '',
+  diff JSONB NOT NULL DEFAULT '{}'::jsonb,
+  occurred_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_audit_events_user ON audit_events(user_id);
+CREATE INDEX idx_audit_events_action ON audit_events(action);
+
+CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER audit_events_append_only BEFORE UPDATE OR DELETE ON audit_events
+  FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+
+-- 登录会话：每台设备一条，refresh token 每次使用都会轮换。
+-- current_refresh_jti 只记录当前有效的 refresh token，旧 jti 再次出现视为泄露，整个会话随之撤销。
+-- revoked_reason 枚举值：logout（登出）、user_revoked（用户移除设备）、refresh_reuse（refresh token 重放）、account_erase（注销账号）、phone_changed（更换手机号）、merged（账号被合并）
+CREATE TABLE user_sessions (
+  id BIGSERIAL PRIMARY KEY,
+  user_id BIGINT NOT NULL REFERENCES users(id),
+  platform VARCHAR(32) NOT NULL DEFAULT '',
+  device_model VARCHAR(64) NOT NULL DEFAULT '',
+  app_version VARCHAR(32) NOT NULL DEFAULT '',
+  ip VARCHAR(64) NOT NULL DEFAULT '', -- 最近一次登录/刷新的 IP
+  current_refresh_jti VARCHAR(64) NOT NULL,
+  refresh_count INT NOT NULL DEFAULT 0, -- 已轮换次数
+  last_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  expires_at TIMESTAMPTZ NOT NULL, -- 随每次刷新顺延
+  revoked_at TIMESTAMPTZ,
+  revoked_reason VARCHAR(32) NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  deleted_at TIMESTAMPTZ
+);
+CREATE INDEX idx_user_sessions_user ON user_sessions(user_id);
+
+-- 账号注销记录：注销时 users.deleted_at 置为当前时间，进入恢复窗口（默认 14 天）。
+-- 窗口内重新登录可调用 POST /v1/users/me/restore 恢复；窗口结束后由 scheduler 清除个人数据：
+--   删除 journeys、daily_check_ins、notification_tasks、contact_attempts、user_sessions、data_exports、phone_change_logs、user_identities、emergency_contacts（含历史记录；audit_events 保留），
+--   匿名化 users 行（open_id 改写、清空手机号），解散/退出共享钱包，取消订阅。
+--   额度钱包、流水与兑换记录不含个人信息，作为账务记录保留。
+-- 本表不含个人信息，恢复或清除后保留作为审计记录。
+-- status 枚举值：pending（恢复窗口内）、restored（已恢复）、purged（已清除）
+CREATE TABLE account_erasures (
+  id BIGSERIAL PRIMARY KEY,
+  user_id BIGINT NOT NULL REFERENCES users(id),
+  public_id BIGINT NOT NULL,
+  status VARCHAR(16) NOT NULL DEFAULT 'pending',
+  requested_at TIMESTAMPTZ NOT NULL,
+  purge_after TIMESTAMPTZ NOT NULL, -- 恢复窗口截止时间
+  restored_at TIMESTAMPTZ,
+  purged_at TIMESTAMPTZ,
+  summary JSONB NOT NULL DEFAULT '{}'::jsonb, -- 清除时各表处理的行数
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  deleted_at TIMESTAMPTZ
+);
+CREATE INDEX idx_account_erasures_user ON account_erasures(user_id);
+CREATE INDEX idx_account_erasures_status_purge ON account_erasures(status, purge_after);
+
+-- 个人数据导出任务：POST /v1/users/me/export 创建，worker 异步打包为 zip（data.json + 各类记录 CSV）。
+-- 归档只存放在 Redis（保留 DATA_EXPORT_RETAIN_HOURS），通过短时签名链接下载一次后立即删除。
+-- full_contacts 为 true 时包含完整联系人手机号，需本人手机号验证码二次验证，否则仅导出脱敏号码。
+-- status 枚举值：pending、processing、ready、downloaded、expired、failed
+CREATE TABLE data_exports (
+  id BIGSERIAL PRIMARY KEY,
+  export_code BIGINT NOT NULL UNIQUE, -- 对外暴露的导出 ID
+  user_id BIGINT NOT NULL REFERENCES users(id),
+  status VARCHAR(16) NOT NULL DEFAULT 'pending',
+  full_contacts BOOLEAN NOT NULL DEFAULT FALSE,
+  requested_at TIMESTAMPTZ NOT NULL,
+  ready_at TIMESTAMPTZ,
+  expires_at TIMESTAMPTZ, -- 归档过期时间
+  downloaded_at TIMESTAMPTZ,
+  size_bytes INT NOT NULL DEFAULT 0,
+  error_message VARCHAR(255) NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  deleted_at TIMESTAMPTZ
+);
+CREATE INDEX idx_data_exports_user_status ON data_exports(user_id, status);
+
+-- 更换手机号记录：PUT /v1/users/me/phone 成功后写入，作为审计记录并用于冷却期判断（PHONE_CHANGE_COOLDOWN_DAYS）。
+-- 只保存新旧号码哈希；更换后除 session_id 外的会话全部撤销，旧号码收到短信通知（old_notified）。
+CREATE TABLE phone_change_logs (
+  id BIGSERIAL PRIMARY KEY,
+  user_id BIGINT NOT NULL REFERENCES users(id),
+  old_phone_hash CHAR(64) NOT NULL DEFAULT '',
+  new_phone_hash CHAR(64) NOT NULL,
+  session_id BIGINT NOT NULL DEFAULT 0, -- 发起更换的会话
+  ip VARCHAR(64) NOT NULL DEFAULT '',
+  old_notified BOOLEAN NOT NULL DEFAULT FALSE,
+  changed_at TIMESTAMPTZ NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  deleted_at TIMESTAMPTZ
+);
+CREATE INDEX idx_phone_change_logs_user_changed ON phone_change_logs(user_id, changed_at);
+
 -- 平安打卡记录。
 CREATE TABLE daily_check_ins (
   id BIGSERIAL PRIMARY KEY,
@@ -88,6 +300,26 @@ CREATE TABLE journeys (
   
   -- P0.7: 延迟消息追踪（用于取消未触发的超时检查）
   timeout_message_id VARCHAR(128), -- 延迟消息的 message_id，用于在 consumer 中检查行程状态
+
+  -- 延长行程（晚点了）
+  extension_count INTEGER NOT NULL DEFAULT 0, -- 延长次数
+  extended_minutes INTEGER NOT NULL DEFAULT 0, -- 累计延长的分钟数
+
+  -- 目的地围栏：离开围栏后再次进入时自动结束行程
+  dest_latitude DOUBLE PRECISION,
+  dest_longitude DOUBLE PRECISION,
+  dest_radius INTEGER NOT NULL DEFAULT 0,  -- 围栏半径（米），0 表示没有目的地围栏
+  geofence_left_at TIMESTAMPTZ,            -- 行程开始后第一次位于围栏外的定位时间
+  completed_by VARCHAR(16) NOT NULL DEFAULT '', -- 结束方式：manual / geofence
+
+  -- 公开分享链接：只保存 token 哈希，重新生成或撤销后旧链接失效
+  share_token_hash CHAR(64),
+  share_expires_at TIMESTAMPTZ,
+  share_location BOOLEAN NOT NULL DEFAULT FALSE, -- 分享页是否展示最后位置
+
+  -- 超时通知
+  contact_priorities JSONB NOT NULL DEFAULT '[]', -- 通知的联系人优先级，为空表示全部已确认的联系人
+  alert_message VARCHAR(128) NOT NULL DEFAULT '', -- 发给联系人的留言，代替备注
   
   created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
   updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
@@ -96,12 +328,101 @@ CREATE TABLE journeys (
 CREATE INDEX idx_journeys_user_status ON journeys(user_id, status);
 CREATE INDEX idx_journeys_expected ON journeys(expected_return_time);
 CREATE INDEX idx_journeys_timeout_message_id ON journeys(timeout_message_id);
+CREATE UNIQUE INDEX idx_journeys_share_token ON journeys(share_token_hash) WHERE share_token_hash IS NOT NULL;
+
+-- 行程时间线：创建、改期、延长、归来、超时、取消，只追加不修改
+CREATE TABLE journey_events (
+  id BIGSERIAL PRIMARY KEY,
+  journey_id BIGINT NOT NULL REFERENCES journeys(id),
+  type VARCHAR(32) NOT NULL,
+  data JSONB NOT NULL DEFAULT '{}', -- 事件相关的取值，如延长前后的预计返回时间
+  occurred_at TIMESTAMPTZ NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  deleted_at TIMESTAMPTZ
+);
+CREATE INDEX idx_journey_events_journey ON journey_events(journey_id);
+
+-- 行程检查点：每个检查点有独立的提醒与超时，超过宽限期未打卡通知紧急联系人
+CREATE TABLE journey_checkpoints (
+  id BIGSERIAL PRIMARY KEY,
+  journey_id BIGINT NOT NULL REFERENCES journeys(id),
+  seq INTEGER NOT NULL,                               -- 行程内的顺序，从 1 开始
+  label VARCHAR(64) NOT NULL,
+  location VARCHAR(128) NOT NULL DEFAULT '',          -- 地点描述，可为空
+  latitude DOUBLE PRECISION,
+  longitude DOUBLE PRECISION,
+  expected_at TIMESTAMPTZ NOT NULL,                   -- 预计到达时间
+  status VARCHAR(16) NOT NULL DEFAULT 'pending',      -- pending / checked_in / missed
+  checked_in_at TIMESTAMPTZ,
+  reminder_sent_at TIMESTAMPTZ,
+  alert_triggered_at TIMESTAMPTZ,
+  timeout_message_id VARCHAR(128),                    -- consumer 只处理与当前值一致的超时消息
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  deleted_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX idx_journey_checkpoints_seq ON journey_checkpoints(journey_id, seq);
+CREATE INDEX idx_journey_checkpoints_expected ON journey_checkpoints(expected_at);
+
+-- 行程位置点：行程进行中定期上报，每个行程保留的点数有上限，行程结束 N 天后删除
+CREATE TABLE journey_locations (
+  id BIGSERIAL PRIMARY KEY,
+  journey_id BIGINT NOT NULL REFERENCES journeys(id),
+  latitude DOUBLE PRECISION NOT NULL,
+  longitude DOUBLE PRECISION NOT NULL,
+  accuracy DOUBLE PRECISION NOT NULL DEFAULT 0,      -- 定位精度（米）
+  recorded_at TIMESTAMPTZ NOT NULL,                  -- 设备定位时间
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  deleted_at TIMESTAMPTZ
+);
+CREATE INDEX idx_journey_locations_journey ON journey_locations(journey_id, recorded_at);
+
+-- 行程模板：经常重复的行程，可设置每周重复规则（RRULE 子集），由定时任务提前生成每一次的安排
+CREATE TABLE journey_templates (
+  id BIGSERIAL PRIMARY KEY,
+  user_id BIGINT NOT NULL REFERENCES users(id),
+  title VARCHAR(64) NOT NULL,
+  note TEXT NOT NULL DEFAULT '',
+  duration_minutes INTEGER NOT NULL,                 -- 出发到预计返回的时长
+  checkpoints JSONB NOT NULL DEFAULT '[]',           -- 检查点，时间为相对出发时间的分钟数
+  contact_priorities JSONB NOT NULL DEFAULT '[]',    -- 超时通知的联系人，为空表示全部
+  alert_message VARCHAR(128) NOT NULL DEFAULT '',    -- 发给联系人的留言
+  rrule VARCHAR(128) NOT NULL DEFAULT '',            -- 例如 FREQ=WEEKLY;BYDAY=MO,WE;BYHOUR=22;BYMINUTE=0，按用户时区解释
+  auto_start BOOLEAN NOT NULL DEFAULT FALSE,         -- 到点自动开始，否则需要用户确认出发
+  next_occurrence_at TIMESTAMPTZ,                    -- 下一次尚未生成安排的出发时间
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  deleted_at TIMESTAMPTZ
+);
+CREATE INDEX idx_journey_templates_user ON journey_templates(user_id);
+CREATE INDEX idx_journey_templates_next ON journey_templates(next_occurrence_at);
+
+-- 重复行程的单次安排：出发前提醒确认，开始后关联生成的行程，跳过的安排保留记录
+CREATE TABLE journey_occurrences (
+  id BIGSERIAL PRIMARY KEY,
+  template_id BIGINT NOT NULL REFERENCES journey_templates(id),
+  user_id BIGINT NOT NULL REFERENCES users(id),
+  start_at TIMESTAMPTZ NOT NULL,                     -- 计划出发时间
+  status VARCHAR(16) NOT NULL DEFAULT 'scheduled',   -- scheduled / started / skipped
+  skip_reason VARCHAR(16) NOT NULL DEFAULT '',       -- user / unconfirmed / failed
+  reminder_sent_at TIMESTAMPTZ,                      -- 出发前确认提醒的发送时间
+  journey_id BIGINT REFERENCES journeys(id),         -- 开始后生成的行程
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  deleted_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX idx_journey_occurrences_start ON journey_occurrences(template_id, start_at);
+CREATE INDEX idx_journey_occurrences_status_start ON journey_occurrences(status, start_at);
+CREATE INDEX idx_journey_occurrences_user ON journey_occurrences(user_id);
 
 -- 额度钱包：跟踪用户每个渠道的额度状态
 -- 提供高效的状态查询和冻结额度管理
 CREATE TABLE quota_wallets (
   id BIGSERIAL PRIMARY KEY,
-  user_id BIGINT NOT NULL REFERENCES users(id),
+  user_id BIGINT NOT NULL REFERENCES users(id),      -- 个人钱包为所属用户，共享钱包为组创建者
+  group_id BIGINT,                                   -- 非空表示共享钱包（wallet_groups.id）
   channel VARCHAR(16) NOT NULL,
   available_amount INTEGER NOT NULL DEFAULT 0,       -- 可用额度
   frozen_amount INTEGER NOT NULL DEFAULT 0,          -- 冻结额度
@@ -109,10 +430,12 @@ CREATE TABLE quota_wallets (
   total_granted INTEGER NOT NULL DEFAULT 0,          -- 总充值额度
 
   created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
-  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
-
-  UNIQUE (user_id, channel)
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
 );
+-- 个人钱包每个渠道一个，共享钱包每个组每个渠道一个
+CREATE UNIQUE INDEX quota_wallets_user_channel_key ON quota_wallets(user_id, channel) WHERE group_id IS NULL;
+CREATE UNIQUE INDEX quota_wallets_group_channel_key ON quota_wallets(group_id, channel) WHERE group_id IS NOT NULL;
+CREATE INDEX idx_quota_wallets_group ON quota_wallets(group_id);
 CREATE INDEX idx_quota_user_channel_frozen ON quota_wallets(user_id, channel, frozen_amount);
 CREATE INDEX idx_quota_wallets_available ON quota_wallets(available_amount);
 
@@ -121,13 +444,14 @@ CREATE INDEX idx_quota_wallets_available ON quota_wallets(available_amount);
 -- 预扣减机制：通过 reason 字段区分（pre_deduct, confirm_deduct, refund）
 CREATE TABLE quota_transactions (
   id BIGSERIAL PRIMARY KEY,
-  user_id BIGINT NOT NULL REFERENCES users(id),
+  user_id BIGINT NOT NULL REFERENCES users(id), -- 消费（或受益）的用户
+  wallet_id BIGINT NOT NULL DEFAULT 0,          -- 实际付款的钱包（共享钱包消费时与 user_id 的个人钱包不同）
   channel VARCHAR(16) NOT NULL, -- 区分 sms、voice
   transaction_type VARCHAR(16) NOT NULL, -- grant(充值), deduct(扣减)
   reason VARCHAR(32) NOT NULL, -- 交易原因：
-                                --   充值: "grant_default", "grant_recharge", "grant_refund"
+                                --   充值: "grant_default", "grant_recharge", "grant_refund", "grant_plan", "grant_redeem", "grant_transfer", "grant_merge"
                                 --   扣减: "sms_notification", "voice_notification",
-                                --        "pre_deduct" (预扣减), "confirm_deduct" (确认扣减)
+                                --        "pre_deduct" (预扣减), "confirm_deduct" (确认扣减), "transfer_out" (转入共享钱包), "merge_out" (账号合并转出)
   amount INTEGER NOT NULL,              -- 本次的金额变动
   balance_after INTEGER NOT NULL,       -- 操作后余额，对账部分
   created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
@@ -137,6 +461,118 @@ CREATE TABLE quota_transactions (
 CREATE INDEX idx_quota_transactions_deleted_at ON quota_transactions(deleted_at);
 CREATE INDEX idx_quota_transactions_user ON quota_transactions(user_id, created_at);
 CREATE INDEX idx_quota_transactions_user_channel_created ON quota_transactions(user_id, channel, created_at DESC);
+CREATE INDEX idx_quota_transactions_wallet ON quota_transactions(wallet_id);
+
+-- 共享钱包组：例如子女为父母共同支付提醒费用
+CREATE TABLE wallet_groups (
+  id BIGSERIAL PRIMARY KEY,
+  name VARCHAR(64) NOT NULL,
+  owner_id BIGINT NOT NULL REFERENCES users(id), -- 创建者，负责管理成员与月度上限
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  deleted_at TIMESTAMPTZ
+);
+CREATE INDEX idx_wallet_groups_owner ON wallet_groups(owner_id);
+CREATE INDEX idx_wallet_groups_deleted_at ON wallet_groups(deleted_at);
+
+-- 共享钱包成员：一个用户同一时间只能加入一个组
+CREATE TABLE wallet_group_members (
+  id BIGSERIAL PRIMARY KEY,
+  group_id BIGINT NOT NULL REFERENCES wallet_groups(id),
+  user_id BIGINT NOT NULL REFERENCES users(id),
+  role VARCHAR(16) NOT NULL DEFAULT 'member',                  -- owner, member
+  funding_order VARCHAR(16) NOT NULL DEFAULT 'personal_first', -- personal_first, shared_first
+  monthly_cap INTEGER NOT NULL DEFAULT 0,                      -- 每月最多从共享钱包消费的额度（cents），0 表示不限
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  deleted_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX wallet_group_members_user_id_key ON wallet_group_members(user_id);
+CREATE INDEX idx_wallet_group_members_group ON wallet_group_members(group_id);
+CREATE INDEX idx_wallet_group_members_deleted_at ON wallet_group_members(deleted_at);
+
+-- 共享钱包邀请：被邀请人接受后才成为成员，接受、拒绝或撤销后删除
+CREATE TABLE wallet_group_invitations (
+  id BIGSERIAL PRIMARY KEY,
+  group_id BIGINT NOT NULL REFERENCES wallet_groups(id),
+  user_id BIGINT NOT NULL REFERENCES users(id),    -- 被邀请人
+  inviter_id BIGINT NOT NULL REFERENCES users(id),
+  monthly_cap INTEGER NOT NULL DEFAULT 0,          -- 接受后生效的月度上限（cents），0 表示不限
+  expires_at TIMESTAMPTZ NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  deleted_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX wallet_group_invitations_group_user_key ON wallet_group_invitations(group_id, user_id);
+CREATE INDEX idx_wallet_group_invitations_user ON wallet_group_invitations(user_id);
+CREATE INDEX idx_wallet_group_invitations_deleted_at ON wallet_group_invitations(deleted_at);
+
+-- 用户订阅：套餐定义在代码中（model.Plans），这里只记录用户当前套餐与计费周期
+-- 没有订阅记录或已取消的用户按免费版处理
+CREATE TABLE subscriptions (
+  id BIGSERIAL PRIMARY KEY,
+  user_id BIGINT NOT NULL REFERENCES users(id),
+  plan VARCHAR(16) NOT NULL DEFAULT 'free',     -- free, premium, family
+  status VARCHAR(16) NOT NULL DEFAULT 'active', -- active, cancelled
+  billing_anchor TIMESTAMPTZ NOT NULL,          -- 计费锚点，每月周年日发放额度
+  next_grant_at TIMESTAMPTZ NOT NULL,           -- 下一次发放额度的时间
+  granted_cycles INTEGER NOT NULL DEFAULT 0,    -- 已发放周期数，调度器用作乐观锁
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  deleted_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX subscriptions_user_id_key ON subscriptions(user_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_subscriptions_next_grant ON subscriptions(status, next_grant_at);
+CREATE INDEX idx_subscriptions_deleted_at ON subscriptions(deleted_at);
+
+-- 兑换码批次：后台生成，一批码共享面额、渠道和有效期
+CREATE TABLE redeem_code_batches (
+  id BIGSERIAL PRIMARY KEY,
+  name VARCHAR(64) NOT NULL,
+  note TEXT NOT NULL DEFAULT '',
+  created_by VARCHAR(64) NOT NULL DEFAULT '', -- 操作人
+  channel VARCHAR(16) NOT NULL,               -- 额度渠道：sms
+  amount INTEGER NOT NULL,                    -- 每次兑换发放的额度（cents）
+  max_redemptions INTEGER NOT NULL DEFAULT 1, -- 每个码可兑换次数，1 表示一次性
+  code_count INTEGER NOT NULL,
+  expires_at TIMESTAMPTZ NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  deleted_at TIMESTAMPTZ
+);
+CREATE INDEX idx_redeem_code_batches_deleted_at ON redeem_code_batches(deleted_at);
+
+-- 兑换码：只保存哈希，明文只在生成时输出一次
+CREATE TABLE redeem_codes (
+  id BIGSERIAL PRIMARY KEY,
+  batch_id BIGINT NOT NULL REFERENCES redeem_code_batches(id),
+  code_hash CHAR(64) NOT NULL,                -- 规范化后兑换码的哈希
+  channel VARCHAR(16) NOT NULL,
+  amount INTEGER NOT NULL,
+  max_redemptions INTEGER NOT NULL DEFAULT 1,
+  redeemed_count INTEGER NOT NULL DEFAULT 0,  -- 已兑换次数，条件更新保证不超过 max_redemptions
+  status VARCHAR(16) NOT NULL DEFAULT 'active', -- active, disabled
+  expires_at TIMESTAMPTZ NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  deleted_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX redeem_codes_code_hash_key ON redeem_codes(code_hash);
+CREATE INDEX idx_redeem_codes_batch ON redeem_codes(batch_id);
+CREATE INDEX idx_redeem_codes_deleted_at ON redeem_codes(deleted_at);
+
+-- 兑换记录：同一用户对同一兑换码只能兑换一次
+CREATE TABLE redeem_records (
+  id BIGSERIAL PRIMARY KEY,
+  code_id BIGINT NOT NULL REFERENCES redeem_codes(id),
+  batch_id BIGINT NOT NULL REFERENCES redeem_code_batches(id),
+  user_id BIGINT NOT NULL REFERENCES users(id),
+  channel VARCHAR(16) NOT NULL,
+  amount INTEGER NOT NULL,
+  redeemed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE UNIQUE INDEX redeem_records_code_user_key ON redeem_records(code_id, user_id);
+CREATE INDEX idx_redeem_records_user ON redeem_records(user_id);
 
 -- 通知任务：统一调度短信与外呼。
 -- 注意：必须先创建 notification_tasks，因为 contact_attempts 依赖它
@@ -144,12 +580,12 @@ CREATE TABLE notification_tasks (
   id BIGSERIAL PRIMARY KEY,
   task_code BIGINT NOT NULL, -- 与 task_id 做区分，taskID 生成出来是为了在消息队列中做区别
   user_id BIGINT NOT NULL REFERENCES users(id),
-  contact_priority SMALLINT, -- 紧急联系人优先级（1-3），对应 users.emergency_contacts 数组中的 priority
+  contact_priority SMALLINT, -- 紧急联系人优先级（1-3），对应 emergency_contacts.priority
   contact_phone_hash CHAR(64), -- 紧急联系人手机号哈希（用于快速查找）
   category VARCHAR(32) NOT NULL, -- 通知类别：check_in_reminder, check_in_timeout, journey_timeout, journey_reminder
   channel VARCHAR(16) NOT NULL, -- 通知渠道：sms, voice
   payload JSONB NOT NULL, -- 模板变量和通知内容
-  status VARCHAR(16) NOT NULL DEFAULT 'pending', -- pending, processing, success, failed
+  status VARCHAR(16) NOT NULL DEFAULT 'pending', -- pending, processing, success, failed, cancelled（用户注销）
   retry_count SMALLINT NOT NULL DEFAULT 0,
   scheduled_at TIMESTAMPTZ NOT NULL,
   processed_at TIMESTAMPTZ,
@@ -198,20 +634,29 @@ CREATE INDEX idx_contact_attempts_contact ON contact_attempts(contact_phone_hash
 --     - "grant_default": 默认赠送
 --     - "grant_recharge": 用户充值
 --     - "grant_refund": 退款（预扣减失败后的退款）
+--     - "grant_plan": 订阅套餐按月发放
+--     - "grant_redeem": 兑换码兑换
+--     - "grant_transfer": 从个人钱包转入共享钱包
+--     - "grant_merge": 账号合并时从被合并账号转入
 --   
 --   扣减类型（transaction_type='deduct'）:
 --     - "sms_notification": 短信通知扣减（已废弃，改为预扣减机制）
 --     - "voice_notification": 语音通知扣减
 --     - "pre_deduct": 预扣减（冻结额度）
 --     - "confirm_deduct": 确认扣减（解冻并正式扣除）
+--     - "transfer_out": 转出到共享钱包
+--     - "merge_out": 账号合并时转出到保留账号
 
 -- notification_tasks.category 字段说明：
 --   - "check_in_reminder": 打卡提醒
 --   - "check_in_timeout": 打卡超时通知
 --   - "journey_timeout": 行程超时通知
 --   - "journey_reminder": 行程提醒（预留）
+--   - "quota_depleted": 额度耗尽提醒
+--   - "quota_low": 额度不足预警（跌破阈值后提醒一次，充值后重置）
 
 -- journeys.timeout_message_id 字段说明：
 --   记录投放的延迟消息 ID，用于在 consumer 中检查行程状态
 --   如果行程已完成，consumer 可以跳过超时处理
---   RabbitMQ 延迟消息无法直接取消，只能通过检查状态跳过
\ No newline at end of file
+--   RabbitMQ 延迟消息无法直接取消，只能通过检查状态跳过
+--   每次改期或延长都会写入新的 message_id，consumer 收到的消息 ID 与当前值不一致时视为过期消息跳过
\ No newline at end of file
@@ -1,12 +1,19 @@
 package database
 
 import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 
+	"AreYouOK/config"
 	"AreYouOK/internal/model"
 	"AreYouOK/pkg/logger"
+	"AreYouOK/pkg/snowflake"
 )
 
 func Migrate() error {
@@ -17,6 +24,10 @@ func Migrate() error {
 
 	logger.Logger.Info("Starting database migration...")
 
+	// 同意状态列上线前已存在的联系人视为已确认，需在 AutoMigrate 加列之前判断
+	contactConsentMissing := db.Migrator().HasTable(&model.Contact{}) &&
+		!db.Migrator().HasColumn(&model.Contact{}, "status")
+
 	// 迁移所有模型
 	err := db.AutoMigrate(
 		&model.User{},
@@ -26,14 +37,230 @@ func Migrate() error {
 		&model.ContactAttempt{},
 		&model.QuotaTransaction{},
 		&model.QuotaWallet{},
+		&model.Subscription{},
+		&model.RedeemCodeBatch{},
+		&model.RedeemCode{},
+		&model.RedeemRecord{},
+		&model.WalletGroup{},
+		&model.WalletGroupMember{},
+		&model.WalletGroupInvitation{},
+		&model.UserSession{},
+		&model.AccountErasure{},
+		&model.DataExport{},
+		&model.PhoneChangeLog{},
+		&model.UserIdentity{},
+		&model.AccountMerge{},
+		&model.AuditEvent{},
+		&model.Contact{},
+		&model.ContactOptOut{},
+		&model.JourneyEvent{},
+		&model.JourneyCheckpoint{},
+		&model.JourneyLocation{},
+		&model.JourneyTemplate{},
+		&model.JourneyOccurrence{},
 	)
 
 	if err != nil {
 		logger.Logger.Error("Database migration failed", zap.Error(err))
 	}
 
+	if err := migrateQuotaWalletUniqueness(db); err != nil {
+		logger.Logger.Error("Quota wallet index migration failed", zap.Error(err))
+	}
+
+	if err := migrateUserIdentities(db); err != nil {
+		logger.Logger.Error("User identity migration failed", zap.Error(err))
+	}
+
+	if err := migrateAuditEvents(db); err != nil {
+		logger.Logger.Error("Audit event trigger migration failed", zap.Error(err))
+	}
+
+	if err := migrateEmergencyContacts(db); err != nil {
+		logger.Logger.Error("Emergency contact migration failed", zap.Error(err))
+	}
+
+	if contactConsentMissing {
+		if err := migrateContactConsent(db); err != nil {
+			logger.Logger.Error("Contact consent migration failed", zap.Error(err))
+		}
+	}
+
 	
 
 	logger.Logger.Info("Database migration completed successfully")
 	return nil
 }
+
+// migrateQuotaWalletUniqueness 共享钱包与个人钱包共用 quota_wallets 表，
+// 原 (user_id, channel) 唯一约束改为只约束个人钱包，共享钱包按 (group_id, channel) 唯一
+func migrateQuotaWalletUniqueness(db *gorm.DB) error {
+	stmts := []string{
+		"ALTER TABLE quota_wallets DROP CONSTRAINT IF EXISTS quota_wallets_user_id_channel_key",
+		"CREATE UNIQUE INDEX IF NOT EXISTS quota_wallets_user_channel_key ON quota_wallets(user_id, channel) WHERE group_id IS NULL",
+		"CREATE UNIQUE INDEX IF NOT EXISTS quota_wallets_group_channel_key ON quota_wallets(group_id, channel) WHERE group_id IS NOT NULL",
+	}
+	for _, stmt := range stmts {
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateUserIdentities 登录身份迁移到 user_identities：
+// 仅通过微信登录的用户没有支付宝 open_id，users.alipay_open_id 唯一索引改为忽略空值；
+// 已有支付宝用户（含恢复窗口内的注销用户）回填 provider = alipay 的身份记录，重复执行不会重复写入
+func migrateUserIdentities(db *gorm.DB) error {
+	var indexDef string
+	if err := db.Raw("SELECT indexdef FROM pg_indexes WHERE indexname = 'users_alipay_open_id_key'").Scan(&indexDef).Error; err != nil {
+		return err
+	}
+	if !strings.Contains(indexDef, "<>") {
+		stmts := []string{
+			"ALTER TABLE users DROP CONSTRAINT IF EXISTS users_alipay_open_id_key",
+			"DROP INDEX IF EXISTS users_alipay_open_id_key",
+			"CREATE UNIQUE INDEX IF NOT EXISTS users_alipay_open_id_key ON users(alipay_open_id) WHERE deleted_at IS NULL AND alipay_open_id <> ''",
+		}
+		for _, stmt := range stmts {
+			if err := db.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	return db.Exec(`INSERT INTO user_identities (user_id, provider, subject, created_at, updated_at)
+SELECT u.id, 'alipay', u.alipay_open_id, u.created_at, NOW()
+FROM users u
+WHERE u.alipay_open_id <> '' AND u.alipay_open_id NOT LIKE 'purged_%'
+ORDER BY u.deleted_at DESC NULLS FIRST
+ON CONFLICT (provider, subject) DO NOTHING`).Error
+}
+
+// migrateAuditEvents audit_events 只允许追加：UPDATE / DELETE 由触发器直接拒绝
+func migrateAuditEvents(db *gorm.DB) error {
+	stmts := []string{
+		`CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql`,
+		"DROP TRIGGER IF EXISTS audit_events_append_only ON audit_events",
+		"CREATE TRIGGER audit_events_append_only BEFORE UPDATE OR DELETE ON audit_events FOR EACH ROW EXECUTE FUNCTION audit_events_append_only()",
+	}
+	for _, stmt := range stmts {
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateEmergencyContacts 紧急联系人从 users.emergency_contacts JSONB 回填到 emergency_contacts 表：
+// 每个用户的回填与清空 JSONB 在同一事务中完成，重复执行只处理尚未迁移的用户（含恢复窗口内的注销用户）
+func migrateEmergencyContacts(db *gorm.DB) error {
+	if err := snowflake.Init(config.Cfg.SnowflakeMachineID, config.Cfg.SnowflakeDataCenter); err != nil {
+		return fmt.Errorf("failed to initialize snowflake: %w", err)
+	}
+
+	for {
+		var users []*model.User
+		if err := db.Unscoped().
+			Select("id", "emergency_contacts").
+			Where("emergency_contacts IS NOT NULL AND emergency_contacts <> '[]'::jsonb").
+			Order("id").
+			Limit(200).
+			Find(&users).Error; err != nil {
+			return err
+		}
+		if len(users) == 0 {
+			return nil
+		}
+
+		for _, user := range users {
+			if err := db.Transaction(func(tx *gorm.DB) error {
+				return backfillEmergencyContacts(tx, user)
+			}); err != nil {
+				return fmt.Errorf("failed to backfill contacts for user %d: %w", user.ID, err)
+			}
+		}
+	}
+}
+
+func backfillEmergencyContacts(tx *gorm.DB, user *model.User) error {
+	var taken []int
+	if err := tx.Model(&model.Contact{}).
+		Where("user_id = ?", user.ID).
+		Pluck("priority", &taken).Error; err != nil {
+		return err
+	}
+	used := make(map[int]bool, len(taken))
+	maxPriority := 0
+	for _, priority := range taken {
+		used[priority] = true
+		if priority > maxPriority {
+			maxPriority = priority
+		}
+	}
+
+	legacy := user.LegacyContacts
+	sort.SliceStable(legacy, func(i, j int) bool {
+		return legacy[i].Priority < legacy[j].Priority
+	})
+
+	for _, contact := range legacy {
+		cipher, err := base64.StdEncoding.DecodeString(contact.PhoneCipherBase64)
+		if err != nil || len(cipher) == 0 {
+			logger.Logger.Warn("Skipping legacy contact with invalid phone cipher",
+				zap.Int64("user_id", user.ID),
+				zap.Int("priority", contact.Priority),
+			)
+			continue
+		}
+
+		// JSONB 无唯一约束，历史数据中重复的优先级顺延到末尾
+		priority := contact.Priority
+		if priority < 1 || used[priority] {
+			priority = maxPriority + 1
+		}
+		used[priority] = true
+		if priority > maxPriority {
+			maxPriority = priority
+		}
+
+		contactID, err := snowflake.NextID(snowflake.GeneratorTypeContact)
+		if err != nil {
+			return err
+		}
+
+		record := &model.Contact{
+			ContactID:        contactID,
+			UserID:           user.ID,
+			DisplayName:      contact.DisplayName,
+			Relationship:     contact.Relationship,
+			PhoneHash:        contact.PhoneHash,
+			PhoneHashVersion: contact.PhoneHashVersion,
+			PhoneCipher:      cipher,
+			Priority:         priority,
+			Version:          1,
+			Status:           model.ContactStatusConfirmed, // 旧数据没有同意流程，按已确认迁移
+		}
+		if createdAt, err := time.Parse(time.RFC3339, contact.CreatedAt); err == nil {
+			record.CreatedAt = createdAt
+		}
+		if err := tx.Create(record).Error; err != nil {
+			return err
+		}
+	}
+
+	return tx.Unscoped().
+		Model(&model.User{}).
+		Where("id = ?", user.ID).
+		UpdateColumn("emergency_contacts", model.EmergencyContacts{}).Error
+}
+
+// migrateContactConsent 引入联系人同意流程前已存在的联系人（含历史记录）按已确认处理，不要求重新确认
+func migrateContactConsent(db *gorm.DB) error {
+	return db.Exec("UPDATE emergency_contacts SET status = ?, responded_at = created_at WHERE status = ?",
+		model.ContactStatusConfirmed, model.ContactStatusPending).Error
+}
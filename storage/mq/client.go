@@ -101,6 +101,13 @@ func declareExchanges(ch *amqp.Channel) error {
 		// 	durable: true,
 		// },
 
+		// 异步任务交换机，用于用户发起的耗时任务（如个人数据导出）
+		{
+			name:    "jobs.direct",
+			kind:    "direct",
+			durable: true,
+		},
+
 		{
 			name:    "notification.dlx", //考虑什么时候丢弃对应的通知
 			kind:    "direct",
@@ -170,6 +177,9 @@ func declareQueues(ch *amqp.Channel) error {
 		{"scheduler.journey.reminder", true, false, false, nil},
 		{"scheduler.journey.timeout", true, false, false, nil},
 
+		// 异步任务队列
+		{"jobs.user_export", true, false, false, nil},
+
 		// // 事件队列
 		// {"events.check_in.timeout", true, false, false, nil},
 		// {"events.journey.timeout", true, false, false, nil},
@@ -214,6 +224,9 @@ func bindQueues(ch *amqp.Channel) error {
 		{"scheduler.journey.reminder", "scheduler.journey.reminder", "scheduler.delayed"},
 		{"scheduler.journey.timeout", "scheduler.journey.timeout", "scheduler.delayed"},
 
+		// 异步任务队列绑定
+		{"jobs.user_export", "jobs.user_export", "jobs.direct"},
+
 		// // 事件队列绑定
 		// {"events.check_in.timeout", "check_in.timeout", "events.topic"},
 		// {"events.journey.timeout", "journey.timeout", "events.topic"},
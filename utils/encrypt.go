@@ -6,71 +6,170 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"errors"
+	"fmt"
 	"io"
+	"sync"
 
 	"AreYouOK/config"
 )
 
-// 需要之后研究下 aes 加密是如何实现的
-// 返回对应的 encryptPhone 值
+// 密文格式（base64 前的原始字节）：
+//   版本化信封：envelopeVersion(1) | len(keyID)(1) | keyID | nonce | ciphertext
+//   旧格式（无密钥标识）：nonce | ciphertext，只能逐个尝试密钥环中的密钥
+// 加密始终使用当前密钥（ENCRYPTION_KEY / ENCRYPTION_KEY_ID），解密优先使用密文头中的 key ID
 
-var errInvalidCipherText = errors.New("invalid ciphertext payload")
+const envelopeVersion byte = 0x01
 
-func EncryptPhone(plain string) (encoded string, err error) {
-	key := []byte(config.Cfg.EncryptionKey)
+var (
+	errInvalidCipherText = errors.New("invalid ciphertext payload")
+	errUnknownKeyID      = errors.New("no key in keyring can decrypt ciphertext")
+)
 
-	block, err := aes.NewCipher(key)
-	// 加密的块
+var (
+	keyringOnce sync.Once
+	keyring     map[string]cipher.AEAD
+	keyringErr  error
+)
+
+// loadKeyring 根据配置构建密钥环，只构建一次
+func loadKeyring() (map[string]cipher.AEAD, error) {
+	keyringOnce.Do(func() {
+		keys, err := config.Cfg.EncryptionKeys()
+		if err != nil {
+			keyringErr = err
+			return
+		}
+
+		keyring = make(map[string]cipher.AEAD, len(keys))
+		for id, key := range keys {
+			block, err := aes.NewCipher([]byte(key))
+			if err != nil {
+				keyringErr = fmt.Errorf("invalid encryption key %s: %w", id, err)
+				return
+			}
+			gcm, err := cipher.NewGCM(block)
+			if err != nil {
+				keyringErr = err
+				return
+			}
+			keyring[id] = gcm
+		}
+	})
+	return keyring, keyringErr
+}
+
+// EncryptPhone 使用当前密钥加密，返回 base64 编码的版本化密文
+func EncryptPhone(plain string) (encoded string, err error) {
+	raw, err := encryptRaw(plain)
 	if err != nil {
 		return "", err
 	}
+	return base64.StdEncoding.EncodeToString(raw), nil
+}
 
-	gcm, err := cipher.NewGCM(block)
-
+func encryptRaw(plain string) ([]byte, error) {
+	ring, err := loadKeyring()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
+	keyID := config.Cfg.EncryptionKeyID
+	gcm := ring[keyID]
+
 	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
 
-	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
-		return "", err
+	raw := make([]byte, 0, 2+len(keyID)+len(nonce)+len(plain)+gcm.Overhead())
+	raw = append(raw, envelopeVersion, byte(len(keyID)))
+	raw = append(raw, keyID...)
+	raw = append(raw, nonce...)
+	raw = gcm.Seal(raw, nonce, []byte(plain), nil)
+
+	return raw, nil
+}
+
+// DecryptPhone 解密手机号密文，兼容无密钥标识的旧格式
+func DecryptPhone(raw []byte) (string, error) {
+	plain, _, err := decryptRaw(raw)
+	return plain, err
+}
+
+// decryptRaw 解密并返回实际使用的 key ID，旧格式密文返回空 key ID
+func decryptRaw(raw []byte) (string, string, error) {
+	ring, err := loadKeyring()
+	if err != nil {
+		return "", "", err
 	}
 
-	ciphertext := gcm.Seal(nil, nonce, []byte(plain), nil)
+	if keyID, payload, ok := parseEnvelope(raw); ok {
+		if gcm, exists := ring[keyID]; exists {
+			if plain, err := openGCM(gcm, payload); err == nil {
+				return plain, keyID, nil
+			}
+		}
+	}
 
-	raw := append(nonce, ciphertext...)
-	encoded = base64.StdEncoding.EncodeToString(raw)
+	// 旧格式：当前密钥优先，再尝试旧密钥
+	if plain, err := openGCM(ring[config.Cfg.EncryptionKeyID], raw); err == nil {
+		return plain, "", nil
+	}
+	for id, gcm := range ring {
+		if id == config.Cfg.EncryptionKeyID {
+			continue
+		}
+		if plain, err := openGCM(gcm, raw); err == nil {
+			return plain, "", nil
+		}
+	}
 
-	return encoded, nil
+	return "", "", errUnknownKeyID
 }
 
-func DecryptPhone(raw []byte) (string, error) {
-	key := []byte(config.Cfg.EncryptionKey)
+// ReencryptPhone 密文不是由当前密钥生成的版本化信封时，用当前密钥重新加密
+// 返回新密文以及是否发生了变化
+func ReencryptPhone(raw []byte) ([]byte, bool, error) {
+	if keyID, _, ok := parseEnvelope(raw); ok && keyID == config.Cfg.EncryptionKeyID {
+		return raw, false, nil
+	}
 
-	block, err := aes.NewCipher(key)
+	plain, keyID, err := decryptRaw(raw)
 	if err != nil {
-		return "", err
+		return nil, false, err
+	}
+	if keyID == config.Cfg.EncryptionKeyID {
+		return raw, false, nil
 	}
 
-	gcm, err := cipher.NewGCM(block)
+	reencrypted, err := encryptRaw(plain)
 	if err != nil {
-		return "", err
+		return nil, false, err
+	}
+	return reencrypted, true, nil
+}
+
+func parseEnvelope(raw []byte) (keyID string, payload []byte, ok bool) {
+	if len(raw) < 2 || raw[0] != envelopeVersion {
+		return "", nil, false
 	}
+	idLen := int(raw[1])
+	if idLen == 0 || len(raw) < 2+idLen {
+		return "", nil, false
+	}
+	return string(raw[2 : 2+idLen]), raw[2+idLen:], true
+}
 
+func openGCM(gcm cipher.AEAD, raw []byte) (string, error) {
 	nonceSize := gcm.NonceSize()
 	if len(raw) < nonceSize {
 		return "", errInvalidCipherText
 	}
 
-	nonce := raw[:nonceSize]
-	ciphertext := raw[nonceSize:]
-
-	plain, err := gcm.Open(nil, nonce, ciphertext, nil)
+	plain, err := gcm.Open(nil, raw[:nonceSize], raw[nonceSize:], nil)
 	if err != nil {
 		return "", err
 	}
-
 	return string(plain), nil
 }
 
@@ -1,18 +1,158 @@
 package utils
 
 import (
+	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
+	"fmt"
+	"sync"
 
 	"AreYouOK/config"
 )
 
-// hash 化 phone 电话号码存储，密文核对，增加盐值，避免彩虹表攻击，盐 + “：” + phone
+// 手机号哈希：版本 0 为旧的 sha256(盐 + ":" + phone)，之后的版本为 HMAC-SHA256(密钥, phone)
+// 哈希与版本号一起存储，密钥泄露后切换新版本并执行 go run ./cmd/rehash 重算
 
+var (
+	phoneHashKeys     map[int]string
+	phoneHashKeysOnce sync.Once
+)
+
+func loadPhoneHashKeys() map[int]string {
+	phoneHashKeysOnce.Do(func() {
+		keys, err := config.Cfg.PhoneHashKeys()
+		if err != nil {
+			// 启动时已校验，这里只保留可用的旧版本
+			keys = map[int]string{0: config.Cfg.PhoneHashSalt}
+		}
+		phoneHashKeys = keys
+	})
+	return phoneHashKeys
+}
+
+// HashPhone 使用当前版本计算手机号哈希
 func HashPhone(phone string) string {
-	key := config.Cfg.PhoneHashSalt
+	hash, err := HashPhoneVersion(phone, config.Cfg.PhoneHashVersion)
+	if err != nil {
+		hash, _ = HashPhoneVersion(phone, 0)
+	}
+	return hash
+}
+
+// PhoneHashVersion 当前手机号哈希版本
+func PhoneHashVersion() int {
+	if _, ok := loadPhoneHashKeys()[config.Cfg.PhoneHashVersion]; !ok {
+		return 0
+	}
+	return config.Cfg.PhoneHashVersion
+}
+
+// HashPhoneVersion 按指定版本计算手机号哈希
+func HashPhoneVersion(phone string, version int) (string, error) {
+	key, ok := loadPhoneHashKeys()[version]
+	if !ok {
+		return "", fmt.Errorf("unknown phone hash version %d", version)
+	}
+
+	if version == 0 {
+		sum := sha256.Sum256([]byte(key + ":" + phone))
+		return hex.EncodeToString(sum[:]), nil
+	}
+
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte("phone:" + phone))
+
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// PhoneHashCandidates 查询时使用的候选哈希，当前版本在前，迁移窗口内附带上一版本
+func PhoneHashCandidates(phone string) []string {
+	candidates := []string{HashPhone(phone)}
+
+	if previous := config.Cfg.PhoneHashPrevious; previous >= 0 && previous != PhoneHashVersion() {
+		if hash, err := HashPhoneVersion(phone, previous); err == nil {
+			candidates = append(candidates, hash)
+		}
+	}
+
+	return candidates
+}
+
+// MatchPhoneHash 判断哈希是否属于该手机号（当前或上一版本）
+func MatchPhoneHash(phone string, hash string) bool {
+	for _, candidate := range PhoneHashCandidates(phone) {
+		if hmac.Equal([]byte(candidate), []byte(hash)) {
+			return true
+		}
+	}
+	return false
+}
+
+// 不透明 token（兑换码等）的哈希：HMAC-SHA256(TOKEN_HASH_SECRET, 用途:token)
+// 与手机号哈希的密钥分开，PHONEHASH_SALT 泄露后也无法离线校验这些 token
 
-	sum := sha256.Sum256([]byte(key + ":" + phone))
+func hashOpaqueToken(purpose string, token string) string {
+	key := config.Cfg.TokenHashSecret
+	if key == "" {
+		key = config.Cfg.JWTSecret
+	}
+
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(purpose + ":" + token))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// HashRedeemCode 兑换码哈希，数据库中只保存哈希，泄库后无法直接拿到可用的兑换码
+func HashRedeemCode(code string) string {
+	return hashOpaqueToken("redeem", code)
+}
+
+// HashConsentToken 联系人确认链接 token 的哈希，数据库中只保存哈希
+func HashConsentToken(token string) string {
+	return hashOpaqueToken("consent", token)
+}
+
+// HashShareToken 行程分享链接 token 的哈希，数据库中只保存哈希
+func HashShareToken(token string) string {
+	return hashOpaqueToken("share", token)
+}
+
+// SignExportDownload 数据导出下载链接签名，签名覆盖导出 ID 与过期时间
+func SignExportDownload(exportCode int64, expiresAt int64) string {
+	key := config.Cfg.ExportSigningKey
+	if key == "" {
+		key = config.Cfg.JWTSecret
+	}
+
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(fmt.Sprintf("export:%d:%d", exportCode, expiresAt)))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyExportDownload 校验数据导出下载链接签名（常量时间比较）
+func VerifyExportDownload(exportCode int64, expiresAt int64, signature string) bool {
+	expected := SignExportDownload(exportCode, expiresAt)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// SignContactOptOut 告警短信中退订链接的签名，签名覆盖联系人 ID
+// 链接长期有效（联系人可能在很久以后才点开），短信长度有限，只保留前 16 字节
+func SignContactOptOut(contactID int64) string {
+	key := config.Cfg.ExportSigningKey
+	if key == "" {
+		key = config.Cfg.JWTSecret
+	}
+
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(fmt.Sprintf("optout:%d", contactID)))
+
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
 
-	return hex.EncodeToString(sum[:])
+// VerifyContactOptOut 校验退订链接签名（常量时间比较）
+func VerifyContactOptOut(contactID int64, signature string) bool {
+	expected := SignContactOptOut(contactID)
+	return hmac.Equal([]byte(expected), []byte(signature))
 }